@@ -0,0 +1,234 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package archive packages the quads of the graph, and the files referenced by them, into a single
+// gzip compressed tar file that can be shared between machines and kept as evidence.
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cayleygraph/quad"
+	"github.com/cayleygraph/quad/nquads"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+const (
+	// Version is the version of the archive format written by this package.
+	Version = 1
+	// ManifestFile is the name of the archive entry describing the contents.
+	ManifestFile = "manifest.json"
+	// GraphFile is the name of the archive entry holding the quads in N-Quads format.
+	GraphFile = "graph.nq"
+	// FilesDir is the directory of the archive entries holding the files referenced by the graph.
+	FilesDir = "files"
+)
+
+// Manifest describes the contents of an archive.
+type Manifest struct {
+	Version      int       `json:"version"`
+	Created      time.Time `json:"created"`
+	AmassVersion string    `json:"amass_version"`
+	Enumerations []string  `json:"enumerations"`
+	Quads        int       `json:"quads"`
+	Files        []string  `json:"files,omitempty"`
+}
+
+// Write packages the quads into the archive, along with the files relative to the directory.
+func Write(w io.Writer, m *Manifest, quads []quad.Quad, dir string, files []string) error {
+	graph := new(bytes.Buffer)
+	qw := nquads.NewWriter(graph)
+	if _, err := quad.Copy(qw, quad.NewReader(quads)); err != nil {
+		return fmt.Errorf("failed to encode the quads: %v", err)
+	}
+	if err := qw.Close(); err != nil {
+		return fmt.Errorf("failed to encode the quads: %v", err)
+	}
+
+	m.Version = Version
+	m.Quads = len(quads)
+	m.Files = files
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the manifest: %v", err)
+	}
+
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+	if err := writeEntry(tw, ManifestFile, manifest, m.Created); err != nil {
+		return err
+	}
+	if err := writeEntry(tw, GraphFile, graph.Bytes(), m.Created); err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", file, err)
+		}
+		if err := writeEntry(tw, path.Join(FilesDir, file), data, m.Created); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write the archive: %v", err)
+	}
+	return zw.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modified time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modified,
+	}); err != nil {
+		return fmt.Errorf("failed to write the %s header: %v", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	return nil
+}
+
+// Read unpacks the archive, returning the manifest and the quads. The files packaged
+// with the graph are extracted into the directory, unless the directory is empty.
+func Read(r io.Reader, dir string) (*Manifest, []quad.Quad, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decompress the archive: %v", err)
+	}
+	defer zr.Close()
+
+	var m *Manifest
+	var quads []quad.Quad
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("failed to read the archive: %v", err)
+		}
+
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, nil, fmt.Errorf("the archive entry %s is outside of the archive", hdr.Name)
+		}
+
+		switch {
+		case name == ManifestFile:
+			m = new(Manifest)
+			if err := json.NewDecoder(tr).Decode(m); err != nil {
+				return nil, nil, fmt.Errorf("failed to decode the manifest: %v", err)
+			}
+			if m.Version < 1 || m.Version > Version {
+				return nil, nil, fmt.Errorf("the archive version %d is not supported", m.Version)
+			}
+		case name == GraphFile:
+			if quads, err = readQuads(tr); err != nil {
+				return nil, nil, err
+			}
+		case strings.HasPrefix(name, FilesDir+"/") && hdr.Typeflag == tar.TypeReg:
+			if dir == "" {
+				continue
+			}
+			if err := extract(tr, dir, strings.TrimPrefix(name, FilesDir+"/")); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if m == nil {
+		return nil, nil, errors.New("the archive does not contain a manifest")
+	}
+	if len(quads) == 0 {
+		return nil, nil, errors.New("the archive does not contain a graph")
+	}
+	return m, quads, nil
+}
+
+func readQuads(r io.Reader) ([]quad.Quad, error) {
+	var quads []quad.Quad
+
+	qr := nquads.NewReader(r, false)
+	for {
+		q, err := qr.ReadQuad()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode the quads: %v", err)
+		}
+		// Restore the native values, such as the times of the enumerations
+		if ts, ok := q.Object.(quad.TypedString); ok {
+			if v, err := ts.ParseValue(); err == nil {
+				q.Object = v
+			}
+		}
+		quads = append(quads, q)
+	}
+	return quads, nil
+}
+
+func extract(r io.Reader, dir, file string) error {
+	rel := filepath.Clean(filepath.FromSlash(file))
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("the archive file %s is outside of the directory", file)
+	}
+
+	p := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("failed to create the directory for %s: %v", file, err)
+	}
+
+	f, err := os.Create(p)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", file, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf("failed to extract %s: %v", file, err)
+	}
+	return nil
+}
+
+// Screenshots returns the paths of the screenshots, relative to the output directory,
+// referenced by the HTTP probe results in the quads.
+func Screenshots(quads []quad.Quad) []string {
+	seen := make(map[string]struct{})
+
+	for _, q := range quads {
+		if p, ok := q.Predicate.(quad.IRI); !ok || strings.Trim(string(p), "<>") != requests.HTTPPredicate {
+			continue
+		}
+		s, ok := q.Object.(quad.String)
+		if !ok {
+			continue
+		}
+		if h, ok := requests.ParseHTTPInfo(string(s)); ok && h.Screenshot != "" {
+			seen[filepath.ToSlash(h.Screenshot)] = struct{}{}
+		}
+	}
+
+	var files []string
+	for f := range seen {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,154 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/cayleygraph/quad"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+const testUUID = "ef9f9475-34eb-465e-81ec-9a1f7f8d2f5b"
+
+func testQuads(t *testing.T) []quad.Quad {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	if err := g.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", testUUID); err != nil {
+		t.Fatalf("failed to insert the A record: %v", err)
+	}
+	info := &requests.HTTPInfo{
+		URL:        "https://www.owasp.org",
+		StatusCode: 200,
+		Screenshot: filepath.Join("screenshots", "www.owasp.org.png"),
+	}
+	if err := g.UpsertProperty(ctx, netmap.Node("www.owasp.org"), requests.HTTPPredicate, info.String()); err != nil {
+		t.Fatalf("failed to insert the HTTP probe results: %v", err)
+	}
+
+	quads, err := g.ReadEventQuads(ctx, testUUID)
+	if err != nil {
+		t.Fatalf("failed to read the event quads: %v", err)
+	}
+	return quads
+}
+
+func TestRoundTrip(t *testing.T) {
+	quads := testQuads(t)
+	files := Screenshots(quads)
+	if !reflect.DeepEqual(files, []string{"screenshots/www.owasp.org.png"}) {
+		t.Fatalf("unexpected screenshots: %v", files)
+	}
+
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "screenshots"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "screenshots", "www.owasp.org.png"), []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	m := &Manifest{
+		Created:      time.Now().UTC().Truncate(time.Second),
+		AmassVersion: "v3.23.2",
+		Enumerations: []string{testUUID},
+	}
+	if err := Write(buf, m, quads, src, files); err != nil {
+		t.Fatalf("failed to write the archive: %v", err)
+	}
+
+	dst := t.TempDir()
+	got, read, err := Read(buf, dst)
+	if err != nil {
+		t.Fatalf("failed to read the archive: %v", err)
+	}
+	if got.Version != Version || got.Quads != len(quads) || !got.Created.Equal(m.Created) ||
+		!reflect.DeepEqual(got.Enumerations, m.Enumerations) || !reflect.DeepEqual(got.Files, files) {
+		t.Errorf("unexpected manifest: %+v", got)
+	}
+	if len(read) != len(quads) {
+		t.Errorf("expected %d quads, got %d", len(quads), len(read))
+	}
+
+	var found bool
+	for _, q := range read {
+		if p, ok := q.Predicate.(quad.IRI); ok && string(p) == "start" {
+			_, found = q.Object.(quad.Time)
+		}
+	}
+	if !found {
+		t.Error("the start time of the enumeration was not restored")
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dst, "screenshots", "www.owasp.org.png")); err != nil || string(data) != "image" {
+		t.Errorf("the screenshot was not extracted: %v", err)
+	}
+}
+
+func testArchive(t *testing.T, entries map[string]string) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	zw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(zw)
+
+	for name, data := range entries {
+		if err := writeEntry(tw, name, []byte(data), time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = tw.Close()
+	_ = zw.Close()
+	return buf
+}
+
+func TestReadErrors(t *testing.T) {
+	graph := "<www.owasp.org> <type> \"fqdn\" <fqdn> .\n"
+	cases := []struct {
+		label   string
+		entries map[string]string
+	}{
+		{
+			label:   "missing manifest",
+			entries: map[string]string{GraphFile: graph},
+		},
+		{
+			label:   "missing graph",
+			entries: map[string]string{ManifestFile: `{"version": 1}`},
+		},
+		{
+			label:   "unsupported version",
+			entries: map[string]string{ManifestFile: `{"version": 2}`, GraphFile: graph},
+		},
+		{
+			label: "file outside of the directory",
+			entries: map[string]string{
+				ManifestFile:             `{"version": 1}`,
+				GraphFile:                graph,
+				FilesDir + "/../../evil": "data",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if _, _, err := Read(testArchive(t, c.entries), t.TempDir()); err == nil {
+			t.Errorf("%s: expected an error", c.label)
+		}
+	}
+
+	if _, _, err := Read(bytes.NewBufferString("not an archive"), ""); err == nil {
+		t.Error("expected an error for data that is not compressed")
+	}
+}
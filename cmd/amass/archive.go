@@ -0,0 +1,236 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/archive"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/format"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+const (
+	exportUsageMsg = "db export -archive out.amass [options]"
+	importUsageMsg = "db import -archive in.amass [options]"
+)
+
+type archiveArgs struct {
+	Domains   *stringset.Set
+	Workspace string
+	Options   struct {
+		NoColor     bool
+		Screenshots bool
+		Silent      bool
+	}
+	Filepaths struct {
+		Archive    string
+		ConfigFile string
+		Directory  string
+		Domains    string
+	}
+}
+
+func runDBExportCommand(clArgs []string) {
+	var args archiveArgs
+	var help1, help2 bool
+	exportCommand := flag.NewFlagSet("export", flag.ContinueOnError)
+
+	exportBuf := new(bytes.Buffer)
+	exportCommand.SetOutput(exportBuf)
+	args.Domains = stringset.New()
+	defer args.Domains.Close()
+
+	exportCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	exportCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	exportCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	exportCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	exportCommand.BoolVar(&args.Options.Screenshots, "screenshots", false, "Include the screenshots captured by the HTTP probes")
+	exportCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	exportCommand.StringVar(&args.Filepaths.Archive, "archive", "", "Path to the archive file being written")
+	exportCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	exportCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	exportCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	exportCommand.StringVar(&args.Workspace, "workspace", "", "Name of the workspace isolating the enumerations in the graph database")
+
+	if err := exportCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(exportUsageMsg, exportCommand, exportBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Error = io.Discard
+	}
+	if args.Filepaths.Archive == "" {
+		commandUsage(exportUsageMsg, exportCommand, exportBuf)
+		os.Exit(1)
+	}
+	if args.Filepaths.Domains != "" {
+		list, err := config.GetListFromFile(args.Filepaths.Domains)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the domain names file: %v\n", err)
+			os.Exit(1)
+		}
+		args.Domains.InsertMany(list...)
+	}
+
+	cfg := archiveConfig(&args)
+	db := openGraphDatabase(args.Filepaths.Directory, cfg)
+	if db == nil {
+		r.Fprintln(color.Error, "Failed to connect with the database")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	// Create the in-memory graph database for events that have information in scope
+	memDB, err := memGraphForScope(ctx, args.Domains.Slice(), args.Workspace, db)
+	if err != nil {
+		r.Fprintln(color.Error, err.Error())
+		os.Exit(1)
+	}
+	defer memDB.Close()
+
+	uuids, _, _ := orderedEvents(ctx, memDB.EventList(ctx), memDB)
+	if len(uuids) == 0 {
+		r.Fprintln(color.Error, "No enumerations were found in the database")
+		os.Exit(1)
+	}
+	quads, err := memDB.ReadEventQuads(ctx, uuids...)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to read the enumerations: %v\n", err)
+		os.Exit(1)
+	}
+
+	dir := config.OutputDirectory(args.Filepaths.Directory)
+	var files []string
+	if args.Options.Screenshots {
+		for _, file := range archive.Screenshots(quads) {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file))); err != nil {
+				r.Fprintf(color.Error, "The screenshot %s was not found in the output directory\n", file)
+				continue
+			}
+			files = append(files, file)
+		}
+	}
+
+	f, err := os.Create(args.Filepaths.Archive)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to create the archive file: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	m := &archive.Manifest{
+		Created:      time.Now().UTC(),
+		AmassVersion: format.Version,
+		Enumerations: uuids,
+	}
+	if err := archive.Write(f, m, quads, dir, files); err != nil {
+		r.Fprintf(color.Error, "Failed to write the archive: %v\n", err)
+		os.Exit(1)
+	}
+	g.Fprintf(color.Error, "%d enumerations and %d files were written to %s\n", len(uuids), len(files), args.Filepaths.Archive)
+}
+
+func runDBImportCommand(clArgs []string) {
+	var args archiveArgs
+	var help1, help2 bool
+	importCommand := flag.NewFlagSet("import", flag.ContinueOnError)
+
+	importBuf := new(bytes.Buffer)
+	importCommand.SetOutput(importBuf)
+
+	importCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	importCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	importCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	importCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	importCommand.StringVar(&args.Filepaths.Archive, "archive", "", "Path to the archive file being read")
+	importCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	importCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+
+	if err := importCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(importUsageMsg, importCommand, importBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Error = io.Discard
+	}
+	if args.Filepaths.Archive == "" {
+		commandUsage(importUsageMsg, importCommand, importBuf)
+		os.Exit(1)
+	}
+
+	f, err := os.Open(args.Filepaths.Archive)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to open the archive file: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	cfg := archiveConfig(&args)
+	dir := config.OutputDirectory(args.Filepaths.Directory)
+	// The screenshots are extracted into the output directory
+	m, quads, err := archive.Read(f, dir)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to read the archive: %v\n", err)
+		os.Exit(1)
+	}
+
+	db := openGraphDatabase(args.Filepaths.Directory, cfg)
+	if db == nil {
+		r.Fprintln(color.Error, "Failed to connect with the database")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := systems.WriteQuads(context.Background(), db, quads); err != nil {
+		r.Fprintf(color.Error, "Failed to import the archive: %v\n", err)
+		os.Exit(1)
+	}
+	g.Fprintf(color.Error, "%d enumerations and %d files were imported from %s\n", len(m.Enumerations), len(m.Files), args.Filepaths.Archive)
+}
+
+func archiveConfig(args *archiveArgs) *config.Config {
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+		if args.Workspace == "" {
+			args.Workspace = cfg.Workspace
+		}
+		if args.Domains != nil && args.Domains.Len() == 0 {
+			args.Domains.InsertMany(cfg.Domains()...)
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	return cfg
+}
@@ -0,0 +1,67 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/config"
+	amassnet "github.com/owasp-amass/amass/v3/net"
+)
+
+// endpointAudit is the summary of the external endpoints contacted in the strict passive mode.
+type endpointAudit struct {
+	Contacted []*amassnet.AuditEntry `json:"contacted"`
+	Refused   []*amassnet.AuditEntry `json:"refused"`
+}
+
+// startEndpointAudit refuses all connections toward the target from this point on, when the strict passive mode was selected.
+func startEndpointAudit(cfg *config.Config) {
+	if cfg.StrictPassive {
+		amassnet.Audit = amassnet.NewEndpointAudit(cfg.IsTargetHost)
+	}
+}
+
+// printEndpointAudit writes the summary of the endpoints contacted to the terminal and to the JSON
+// file next to the other output files.
+func printEndpointAudit(cfg *config.Config, args *enumArgs) {
+	if amassnet.Audit == nil {
+		return
+	}
+
+	audit := &endpointAudit{
+		Contacted: amassnet.Audit.Contacted(),
+		Refused:   amassnet.Audit.Refused(),
+	}
+
+	fmt.Fprintf(color.Error, "\n%s\n", blue("Strict passive audit: the external endpoints contacted"))
+	fmt.Fprintf(color.Error, "%-10s%-50s%s\n", blue("Protocol"), blue("Endpoint"), blue("Count"))
+	for _, e := range audit.Contacted {
+		fmt.Fprintf(color.Error, "%-10s%-50s%s\n", yellow(e.Protocol), green(e.Endpoint), yellow(e.Count))
+	}
+	if len(audit.Refused) > 0 {
+		fmt.Fprintf(color.Error, "\n%s\n", blue("The endpoints refused, since they belong to the target"))
+		for _, e := range audit.Refused {
+			fmt.Fprintf(color.Error, "%-10s%-50s%s\n", yellow(e.Protocol), red(e.Endpoint), yellow(e.Count))
+		}
+	}
+
+	path := filepath.Join(config.OutputDirectory(cfg.Dir), "amass_audit.json")
+	if args.Filepaths.AllFilePrefix != "" {
+		path = args.Filepaths.AllFilePrefix + "_audit.json"
+	}
+
+	data, err := json.MarshalIndent(audit, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to save the endpoint audit: %v\n", err)
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/caffix/netmap"
+	"github.com/fatih/color"
+	"github.com/go-ini/ini"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/datasrcs"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+const (
+	configUsageMsg = "config init [options]"
+)
+
+type configArgs struct {
+	Options struct {
+		NoColor bool
+	}
+	Filepaths struct {
+		Directory string
+		Output    string
+	}
+}
+
+func runConfigCommand(clArgs []string) {
+	var args configArgs
+	var help1, help2 bool
+	configCommand := flag.NewFlagSet("config", flag.ContinueOnError)
+
+	configBuf := new(bytes.Buffer)
+	configCommand.SetOutput(configBuf)
+
+	configCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	configCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	configCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	configCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+	configCommand.StringVar(&args.Filepaths.Output, "o", "", "Path to the configuration file written (default: config.ini in the output directory)")
+
+	if len(clArgs) < 1 || clArgs[0] != "init" {
+		commandUsage(configUsageMsg, configCommand, configBuf)
+		return
+	}
+	if err := configCommand.Parse(clArgs[1:]); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(configUsageMsg, configCommand, configBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+
+	path := args.Filepaths.Output
+	if path == "" {
+		dir := config.OutputDirectory(args.Filepaths.Directory)
+		if dir == "" {
+			r.Fprintln(color.Error, "Failed to obtain the output directory")
+			os.Exit(1)
+		}
+		path = filepath.Join(dir, "config.ini")
+	}
+
+	w := &wizard{in: bufio.NewReader(os.Stdin), out: color.Output}
+	if _, err := os.Stat(path); err == nil && !w.askBool(fmt.Sprintf("The file %s already exists. Overwrite it?", path), false) {
+		return
+	}
+
+	if err := writeConfigFile(w.run(path), path); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	g.Fprintf(color.Output, "\nThe configuration was written to %s\n", path)
+}
+
+// writeConfigFile saves the configuration next to the path, checks that Amass loads the settings
+// without errors and moves it into place. The file holds credentials, so only the user can read it.
+func writeConfigFile(file *ini.File, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create the directory of the configuration file: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.ini")
+	if err != nil {
+		return fmt.Errorf("failed to create the configuration file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = file.WriteTo(tmp)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write the configuration file: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("failed to restrict the permissions of the configuration file: %v", err)
+	}
+
+	if err := config.NewConfig().LoadSettings(tmp.Name()); err != nil {
+		return fmt.Errorf("the configuration did not pass validation: %v", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// wizard prompts for the settings of the configuration file.
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (w *wizard) run(path string) *ini.File {
+	file := ini.Empty(ini.LoadOptions{AllowShadows: true})
+	def := file.Section(ini.DefaultSection)
+
+	fmt.Fprintf(w.out, "%s\n\n", green("This wizard writes the configuration file to "+path))
+
+	fmt.Fprintf(w.out, "%s\n", blue("Scope"))
+	if domains := w.askList("Root domain names of the enumerations (comma-separated)", isDomainName); len(domains) > 0 {
+		file.Section("scope")
+		setValues(file.Section("scope.domains"), "domain", domains)
+	}
+	if subs := w.askList("Subdomain names to leave out of the enumerations (comma-separated)", isDomainName); len(subs) > 0 {
+		file.Section("scope")
+		setValues(file.Section("scope.blacklisted"), "subdomain", subs)
+	}
+	switch mode := w.askChoice("Mode of the enumerations", []string{"normal", "passive", "active"}, "normal"); mode {
+	case "passive", "active":
+		_, _ = def.NewKey("mode", mode)
+	}
+
+	fmt.Fprintf(w.out, "\n%s\n", blue("DNS Resolvers"))
+	if !w.askBool("Use the built-in public DNS resolvers?", true) {
+		resolvers := w.askList("IP addresses of the DNS resolvers (comma-separated)", isIPAddress)
+		if len(resolvers) > 0 {
+			setValues(file.Section("resolvers"), "resolver", resolvers)
+		}
+	}
+
+	fmt.Fprintf(w.out, "\n%s\n", blue("Brute Forcing"))
+	if w.askBool("Brute force the subdomain names?", false) {
+		sec := file.Section("bruteforce")
+		_, _ = sec.NewKey("enabled", "true")
+		if lists := w.askList("Wordlist files replacing the built-in wordlist (comma-separated)", isFile); len(lists) > 0 {
+			setValues(sec, "wordlist_file", lists)
+		}
+	}
+
+	fmt.Fprintf(w.out, "\n%s\n", blue("Output"))
+	if dir := w.ask("Output directory (blank for the default)", ""); dir != "" {
+		_, _ = def.NewKey("output_directory", dir)
+	}
+	if ws := w.ask("Workspace isolating the enumerations in the graph database (blank for none)", ""); ws != "" {
+		_, _ = def.NewKey("workspace", ws)
+	}
+
+	fmt.Fprintf(w.out, "\n%s\n", blue("Data Sources"))
+	ds := file.Section("data_sources")
+	_, _ = ds.NewKey("minimum_ttl", "1440")
+	w.askCredentials(file)
+	return file
+}
+
+// askCredentials prompts for the credentials of the data sources selected by the user.
+func (w *wizard) askCredentials(file *ini.File) {
+	names, apis := credentialSourceNames()
+	if len(names) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w.out, "The API data sources, which usually require credentials: %s\n", strings.Join(apis, ", "))
+	valid := make(map[string]string)
+	for _, name := range names {
+		valid[strings.ToLower(name)] = name
+	}
+
+	selected := w.askList("Data sources you have credentials for (comma-separated)", func(s string) bool {
+		_, found := valid[strings.ToLower(s)]
+		return found
+	})
+	for _, s := range selected {
+		name := valid[strings.ToLower(s)]
+
+		var added bool
+		src := file.Section("data_sources." + name)
+		cred := file.Section(src.Name() + ".Credentials")
+		for _, field := range []struct{ key, prompt string }{
+			{"apikey", "API key"},
+			{"secret", "Secret (blank when not required)"},
+			{"username", "Username (blank when not required)"},
+			{"password", "Password (blank when not required)"},
+		} {
+			if v := w.ask(name+" "+field.prompt, ""); v != "" {
+				_, _ = cred.NewKey(field.key, v)
+				added = true
+			}
+		}
+
+		if !added {
+			file.DeleteSection(cred.Name())
+			file.DeleteSection(src.Name())
+		}
+	}
+}
+
+// credentialSourceNames returns the names of the data sources that query a service, along with those of the api type.
+func credentialSourceNames() ([]string, []string) {
+	cfg := config.NewConfig()
+	// The scripts are only acquired along with an existing output directory
+	if dir, err := os.MkdirTemp("", "amass-config-"); err == nil {
+		defer os.RemoveAll(dir)
+		cfg.Dir = dir
+	}
+
+	sys := &systems.SimpleSystem{
+		Cfg:   cfg,
+		Graph: netmap.NewGraph(netmap.NewCayleyGraphMemory()),
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	var names, apis []string
+	for _, src := range datasrcs.GetAllSources(sys) {
+		switch src.Description() {
+		case requests.BRUTE, requests.ALT, requests.GUESS:
+			continue
+		case requests.API, requests.LEAK:
+			apis = append(apis, src.String())
+		}
+		names = append(names, src.String())
+	}
+
+	for _, list := range [][]string{names, apis} {
+		sort.Slice(list, func(i, j int) bool {
+			return strings.ToLower(list[i]) < strings.ToLower(list[j])
+		})
+	}
+	return names, apis
+}
+
+func (w *wizard) ask(prompt, def string) string {
+	if def != "" {
+		prompt += " [" + def + "]"
+	}
+	fmt.Fprintf(w.out, "%s: ", yellow(prompt))
+
+	line, err := w.in.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(w.out)
+		return def
+	}
+	if line = strings.TrimSpace(line); line != "" {
+		return line
+	}
+	return def
+}
+
+func (w *wizard) askBool(prompt string, def bool) bool {
+	choices := "y/N"
+	if def {
+		choices = "Y/n"
+	}
+
+	for {
+		switch strings.ToLower(w.ask(prompt+" ("+choices+")", "")) {
+		case "":
+			return def
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+		if w.eof() {
+			return def
+		}
+		fmt.Fprintln(w.out, red("Please answer yes or no"))
+	}
+}
+
+func (w *wizard) askChoice(prompt string, choices []string, def string) string {
+	for {
+		answer := strings.ToLower(w.ask(prompt+" ("+strings.Join(choices, "/")+")", def))
+		for _, c := range choices {
+			if answer == c {
+				return c
+			}
+		}
+		if w.eof() {
+			return def
+		}
+		fmt.Fprintf(w.out, "%s\n", red("Please answer one of "+strings.Join(choices, ", ")))
+	}
+}
+
+// askList prompts until all the comma-separated values pass the check, and an empty answer is accepted.
+func (w *wizard) askList(prompt string, valid func(string) bool) []string {
+loop:
+	for {
+		var values []string
+
+		for _, v := range strings.Split(w.ask(prompt, ""), ",") {
+			if v = strings.TrimSpace(v); v == "" {
+				continue
+			}
+			if !valid(v) {
+				fmt.Fprintf(w.out, "%s\n", red(v+" is not valid"))
+				if w.eof() {
+					return nil
+				}
+				continue loop
+			}
+			values = append(values, v)
+		}
+		return values
+	}
+}
+
+// eof returns true when no more answers can be read, so the prompts do not repeat forever.
+func (w *wizard) eof() bool {
+	_, err := w.in.Peek(1)
+	return err != nil
+}
+
+func setValues(sec *ini.Section, key string, values []string) {
+	for _, v := range values {
+		_, _ = sec.NewKey(key, v)
+	}
+}
+
+func isDomainName(s string) bool {
+	return strings.Contains(s, ".") && !strings.ContainsAny(s, " /:@")
+}
+
+func isIPAddress(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+func isFile(s string) bool {
+	finfo, err := os.Stat(s)
+	return err == nil && !finfo.IsDir()
+}
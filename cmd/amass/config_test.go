@@ -0,0 +1,119 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/owasp-amass/amass/v3/config"
+)
+
+func TestConfigWizard(t *testing.T) {
+	dir := t.TempDir()
+	wordlist := filepath.Join(dir, "words.txt")
+	if err := os.WriteFile(wordlist, []byte("www\napi\n"), 0600); err != nil {
+		t.Fatalf("failed to write the wordlist: %v", err)
+	}
+
+	answers := []string{
+		"owasp.org, example.com",
+		"bad name",
+		"dev.owasp.org",
+		"loud",
+		"active",
+		"n",
+		"8.8.8.8, 1.1.1.1",
+		"y",
+		wordlist,
+		dir,
+		"monitoring",
+		"shodan, NotASource",
+		"Shodan",
+		"abc123",
+		"",
+		"",
+		"",
+	}
+
+	var out bytes.Buffer
+	w := &wizard{in: bufio.NewReader(strings.NewReader(strings.Join(answers, "\n") + "\n")), out: &out}
+	path := filepath.Join(dir, "config.ini")
+	if err := writeConfigFile(w.run(path), path); err != nil {
+		t.Fatalf("writeConfigFile() error = %v", err)
+	}
+	for _, msg := range []string{"bad name is not valid", "Please answer one of", "NotASource is not valid"} {
+		if !strings.Contains(out.String(), msg) {
+			t.Errorf("the wizard did not print %q", msg)
+		}
+	}
+
+	cfg := config.NewConfig()
+	if err := cfg.LoadSettings(path); err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+
+	// The lists are not loaded in the order of the answers
+	sorted := func(list []string) string {
+		list = append([]string(nil), list...)
+		sort.Strings(list)
+		return strings.Join(list, ",")
+	}
+
+	tests := []struct {
+		setting  string
+		got      interface{}
+		expected interface{}
+	}{
+		{setting: "domains", got: sorted(cfg.Domains()), expected: "example.com,owasp.org"},
+		{setting: "blacklisted", got: cfg.Blacklisted("dev.owasp.org"), expected: true},
+		{setting: "active", got: cfg.Active, expected: true},
+		{setting: "resolvers", got: sorted(cfg.Resolvers), expected: "1.1.1.1,8.8.8.8"},
+		{setting: "bruteforce", got: cfg.BruteForcing, expected: true},
+		{setting: "wordlist", got: sorted(cfg.Wordlist), expected: "api,www"},
+		{setting: "output_directory", got: cfg.Dir, expected: dir},
+		{setting: "workspace", got: cfg.Workspace, expected: "monitoring"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("the %s setting was loaded as %v, expected %v", tt.setting, tt.got, tt.expected)
+		}
+	}
+
+	creds := cfg.GetDataSourceConfig("Shodan").GetCredentials()
+	if creds == nil || creds.Key != "abc123" || creds.Secret != "" {
+		t.Errorf("the Shodan credentials were loaded as %v", creds)
+	}
+	if finfo, err := os.Stat(path); err != nil || finfo.Mode().Perm() != 0600 {
+		t.Errorf("the configuration file has the permissions %v: %v", finfo.Mode().Perm(), err)
+	}
+}
+
+func TestConfigWizardDefaults(t *testing.T) {
+	var out bytes.Buffer
+	// The defaults are selected when the input ends before the questions
+	w := &wizard{in: bufio.NewReader(strings.NewReader("")), out: &out}
+
+	path := filepath.Join(t.TempDir(), "config.ini")
+	if err := writeConfigFile(w.run(path), path); err != nil {
+		t.Fatalf("writeConfigFile() error = %v", err)
+	}
+
+	cfg := config.NewConfig()
+	if err := cfg.LoadSettings(path); err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if len(cfg.Domains()) != 0 || cfg.Passive || cfg.Active || cfg.BruteForcing || cfg.Workspace != "" {
+		t.Errorf("the default answers provided the domains %v", cfg.Domains())
+	}
+	if cfg.MinimumTTL != 1440 {
+		t.Errorf("the minimum_ttl setting was loaded as %d", cfg.MinimumTTL)
+	}
+}
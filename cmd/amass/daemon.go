@@ -0,0 +1,255 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/datasrcs"
+	"github.com/owasp-amass/amass/v3/output"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+// The default number of minutes between the enumerations of the daemon mode
+const defaultDaemonInterval = 1440
+
+// runEnumDaemon repeats the enumerations of the scheduled scopes until the user quits. Each
+// enumeration is stored as a new event, and only the changes since the previous events are
+// printed and posted to the configured chat services. The schedule and the status of the last
+// runs are kept in the output directory, so the daemon resumes where it stopped. The data source
+// scripts added, changed or deleted while the daemon waits are reloaded before each enumeration.
+func runEnumDaemon(cfg *config.Config, args *enumArgs, sys systems.System, logOut *logOutputs) {
+	if len(sys.GraphDatabases()) == 0 {
+		r.Fprintln(color.Error, "The daemon mode requires a graph database to store the enumerations")
+		os.Exit(1)
+	}
+
+	notifiers, err := output.NewNotifiers(cfg)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	path := filepath.Join(config.OutputDirectory(cfg.Dir), scheduleStateFile)
+	prev, err := loadScheduleState(path)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	scopes := daemonScopes(cfg, args)
+	for _, s := range scopes {
+		s.resume(prev[s.state.Name], now)
+		fmt.Fprintf(color.Error, "%s%s%s%s\n", yellow("The "), green(s.state.Name),
+			yellow(" scope is enumerated "), yellow(s.state.Schedule))
+	}
+	save := func() {
+		if err := saveScheduleState(path, scopes); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+		}
+	}
+	save()
+
+	w := datasrcs.NewScriptWatcher(sys)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Monitor for the user stopping the daemon
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(quit)
+
+		<-quit
+		cancel()
+	}()
+
+	for {
+		s := nextScope(scopes)
+
+		if wait := time.Until(s.state.NextRun); wait > 0 {
+			fmt.Fprintf(color.Error, "%s%s%s%s\n", yellow("The next enumeration of "), green(s.state.Name),
+				yellow(" begins at "), yellow(s.state.NextRun.Format(timeFormat)))
+
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+		}
+
+		reloadDaemonScripts(w, sys, logOut)
+		runScheduledScope(ctx, cfg, args, sys, notifiers, s, save)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// reloadDaemonScripts updates the data sources of the system with the changes to the scripts.
+func reloadDaemonScripts(w *datasrcs.ScriptWatcher, sys systems.System, logOut *logOutputs) {
+	if w.Reload() > 0 {
+		srcs := sys.DataSources()
+
+		initializeSourceTags(srcs)
+		logOut.setSources(srcs)
+	}
+}
+
+// nextScope returns the scope with the earliest enumeration.
+func nextScope(scopes []*daemonScope) *daemonScope {
+	next := scopes[0]
+
+	for _, s := range scopes[1:] {
+		if s.state.NextRun.Before(next.state.NextRun) {
+			next = s
+		}
+	}
+	return next
+}
+
+// runScheduledScope enumerates the scope and reports the changes, recording the status of the run
+// and the time of the next one.
+func runScheduledScope(ctx context.Context, cfg *config.Config, args *enumArgs,
+	sys systems.System, notifiers []*output.Notifier, s *daemonScope, save func()) {
+	// Every enumeration is stored as a separate event in the graph database
+	cfg.UUID = uuid.New()
+	cfg.ClearDomains()
+	cfg.AddDomains(s.state.Domains...)
+	cfg.Workspace = s.state.Workspace
+
+	started := time.Now()
+	s.state.Runs++
+	s.state.LastStart = &started
+	s.state.LastFinish = nil
+	s.state.LastStatus = runRunning
+	s.state.LastError = ""
+	s.state.Enumeration = cfg.UUID.String()
+	save()
+
+	fmt.Fprintf(color.Error, "%s%s%s\n", yellow("The enumeration of the "), green(s.state.Name), yellow(" scope has started"))
+	err := runEnumeration(ctx, cfg, args, sys)
+
+	finished := time.Now()
+	s.state.LastFinish = &finished
+	switch {
+	case ctx.Err() != nil:
+		s.state.LastStatus = runInterrupted
+	case err != nil:
+		s.state.LastStatus = runFailed
+		s.state.LastError = err.Error()
+		r.Fprintf(color.Error, "The enumeration of the %s scope failed: %v\n", s.state.Name, err)
+	default:
+		s.state.LastStatus = runFinished
+		reportEnumChanges(ctx, cfg, notifiers, sys)
+	}
+
+	s.state.NextRun = s.schedule.Next(started)
+	// The times selected by a cron expression are skipped when they pass during the run
+	if !isInterval(s.schedule) && s.state.NextRun.Before(finished) {
+		s.state.NextRun = s.schedule.Next(finished)
+	}
+	save()
+}
+
+// reportEnumChanges prints and posts the differences between the enumeration that just
+// finished and those that came before it in the same workspace.
+func reportEnumChanges(ctx context.Context, cfg *config.Config, notifiers []*output.Notifier, sys systems.System) {
+	domains := cfg.Domains()
+
+	memDB, err := memGraphForScope(ctx, domains, cfg.Workspace, sys.GraphDatabases()[0])
+	if err != nil {
+		r.Fprintln(color.Error, err.Error())
+		return
+	}
+	defer memDB.Close()
+
+	var older []string
+	var found bool
+	current := cfg.UUID.String()
+	uuids, _, _ := orderedEvents(ctx, memDB.EventsInScope(ctx, domains...), memDB)
+	for _, event := range uuids {
+		if event == current {
+			found = true
+			break
+		}
+		older = append(older, event)
+	}
+	if !found {
+		r.Fprintln(color.Error, "Failed to find the enumeration in the graph database")
+		return
+	}
+
+	cache := sys.Cache()
+	newer := getScopedOutput([]string{current}, domains, false, memDB, cache)
+	if len(older) == 0 {
+		fmt.Fprintf(color.Output, "%s%s%s\n", blue("The first enumeration stored "),
+			green(fmt.Sprint(len(newer))), blue(" names as the baseline for the changes"))
+		return
+	}
+
+	cum := getScopedOutput(older, domains, false, memDB, cache)
+	if cfg.NewOnly {
+		newer = withSkippedNames(newer, cum)
+	}
+	diff := diffEnumOutput(cum, newer)
+	if len(diff) == 0 {
+		g.Fprintln(color.Output, "No differences discovered")
+		return
+	}
+
+	blueLine()
+	fmt.Fprintf(color.Output, "%s\t%s\n", blue("Changes"), yellow(time.Now().Format(timeFormat)))
+	blueLine()
+	for _, d := range diff {
+		fmt.Fprintln(color.Output, d)
+	}
+
+	if len(notifiers) == 0 {
+		return
+	}
+	// The ASN information provides the netblocks of the addresses
+	previous := getScopedOutput(older, domains, true, memDB, cache)
+	latest := getScopedOutput([]string{current}, domains, true, memDB, cache)
+	if cfg.NewOnly {
+		latest = withSkippedNames(latest, previous)
+	}
+	summary := output.NewSummary(domains, previous, latest)
+	for _, n := range notifiers {
+		nctx, cancel := context.WithTimeout(ctx, time.Minute)
+		if err := n.Notify(nctx, summary); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+		}
+		cancel()
+	}
+}
+
+// withSkippedNames adds the older names missing from the newer output, since the enumerations
+// of the new-only mode skip the names already discovered instead of finding them removed.
+func withSkippedNames(newer, older []*requests.Output) []*requests.Output {
+	names := make(map[string]struct{}, len(newer))
+	for _, o := range newer {
+		names[o.Name] = struct{}{}
+	}
+
+	for _, o := range older {
+		if _, found := names[o.Name]; !found {
+			newer = append(newer, o)
+		}
+	}
+	return newer
+}
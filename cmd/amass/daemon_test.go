@@ -0,0 +1,179 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/caffix/netmap"
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/output"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+func TestReportEnumChanges(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	records := []struct {
+		event, name, addr string
+	}{
+		{event: "first", name: "www.owasp.org", addr: "192.0.2.1"},
+		{event: "first", name: "mail.owasp.org", addr: "192.0.2.2"},
+		{event: "second", name: "www.owasp.org", addr: "192.0.2.10"},
+		{event: "second", name: "api.owasp.org", addr: "192.0.2.3"},
+	}
+	events := map[string]uuid.UUID{"first": uuid.New(), "second": uuid.New()}
+	for _, rec := range records {
+		if err := g.UpsertA(ctx, rec.name, rec.addr, "DNS", events[rec.event].String()); err != nil {
+			t.Fatalf("failed to insert %s: %v", rec.name, err)
+		}
+	}
+
+	var mu sync.Mutex
+	var posted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		mu.Lock()
+		posted = append(posted, string(body))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	n, err := output.NewNotifier(&config.OutputSink{Name: "slack", System: "slack", URL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewNotifier() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	defer func(out io.Writer, nocolor bool) {
+		color.Output = out
+		color.NoColor = nocolor
+	}(color.Output, color.NoColor)
+	color.Output = &buf
+	color.NoColor = true
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	sys := &systems.SimpleSystem{Cfg: cfg, Graph: g, ASNCache: requests.NewASNCache()}
+	// The first enumeration only provides the baseline, which includes the root domain name
+	cfg.UUID = events["first"]
+	reportEnumChanges(ctx, cfg, []*output.Notifier{n}, sys)
+	if !strings.Contains(buf.String(), "The first enumeration stored 3 names") {
+		t.Errorf("the first enumeration reported %q", buf.String())
+	}
+	if len(posted) != 0 {
+		t.Errorf("the baseline enumeration was posted: %v", posted)
+	}
+
+	buf.Reset()
+	cfg.UUID = events["second"]
+	reportEnumChanges(ctx, cfg, []*output.Notifier{n}, sys)
+	for _, change := range []string{"Found: api.owasp.org", "Moved: www.owasp.org", "Removed: mail.owasp.org"} {
+		if !strings.Contains(buf.String(), change) {
+			t.Errorf("the changes did not include %q: %s", change, buf.String())
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(posted) != 1 || !strings.Contains(posted[0], "owasp.org") {
+		t.Errorf("the changes were posted as %v", posted)
+	}
+}
+
+func TestWithSkippedNames(t *testing.T) {
+	out := func(names ...string) []*requests.Output {
+		var outputs []*requests.Output
+		for _, name := range names {
+			outputs = append(outputs, &requests.Output{Name: name, Domain: "owasp.org"})
+		}
+		return outputs
+	}
+	names := func(outputs []*requests.Output) []string {
+		var names []string
+		for _, o := range outputs {
+			names = append(names, o.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		newer    []*requests.Output
+		older    []*requests.Output
+		expected []string
+	}{
+		{name: "skipped names added", newer: out("dev.owasp.org"), older: out("www.owasp.org", "api.owasp.org"),
+			expected: []string{"dev.owasp.org", "www.owasp.org", "api.owasp.org"}},
+		{name: "newer names kept", newer: out("www.owasp.org"), older: out("www.owasp.org"),
+			expected: []string{"www.owasp.org"}},
+		{name: "no older names", newer: out("dev.owasp.org"), expected: []string{"dev.owasp.org"}},
+		{name: "no newer names", older: out("www.owasp.org"), expected: []string{"www.owasp.org"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(withSkippedNames(tt.newer, tt.older)); strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("withSkippedNames() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReportEnumChangesNewOnly(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	records := []struct {
+		event, name, addr string
+	}{
+		{event: "first", name: "www.owasp.org", addr: "192.0.2.1"},
+		{event: "first", name: "mail.owasp.org", addr: "192.0.2.2"},
+		// The new-only enumeration skips the names already discovered
+		{event: "second", name: "api.owasp.org", addr: "192.0.2.3"},
+	}
+	events := map[string]uuid.UUID{"first": uuid.New(), "second": uuid.New()}
+	for _, rec := range records {
+		if err := g.UpsertA(ctx, rec.name, rec.addr, "DNS", events[rec.event].String()); err != nil {
+			t.Fatalf("failed to insert %s: %v", rec.name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	defer func(out io.Writer, nocolor bool) {
+		color.Output = out
+		color.NoColor = nocolor
+	}(color.Output, color.NoColor)
+	color.Output = &buf
+	color.NoColor = true
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	cfg.NewOnly = true
+	sys := &systems.SimpleSystem{Cfg: cfg, Graph: g, ASNCache: requests.NewASNCache()}
+
+	cfg.UUID = events["second"]
+	reportEnumChanges(ctx, cfg, nil, sys)
+	if !strings.Contains(buf.String(), "Found: api.owasp.org") {
+		t.Errorf("the changes did not include the new name: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), "Removed:") {
+		t.Errorf("the skipped names were reported as removed: %s", buf.String())
+	}
+}
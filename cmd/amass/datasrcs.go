@@ -0,0 +1,316 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/caffix/service"
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/datasrcs"
+	"github.com/owasp-amass/amass/v3/datasrcs/scripting/scripttest"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/selftest"
+	"github.com/owasp-amass/amass/v3/systems"
+	"github.com/owasp-amass/resolve"
+)
+
+const (
+	datasrcsUsageMsg     = "datasrcs check [options]"
+	datasrcsTestUsageMsg = "datasrcs test [options] SCRIPT"
+)
+
+type datasrcsArgs struct {
+	Domain  string
+	Sources *stringset.Set
+	Timeout int
+	Options struct {
+		NoColor bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+	}
+}
+
+type datasrcsTestArgs struct {
+	Domain  string
+	Address string
+	Timeout int
+	Options struct {
+		NoColor bool
+		Record  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+		Fixture    string
+	}
+}
+
+func runDatasrcsCommand(clArgs []string) {
+	if len(clArgs) > 0 && clArgs[0] == "test" {
+		runDatasrcsTestCommand(clArgs[1:])
+		return
+	}
+
+	args := datasrcsArgs{Sources: stringset.New()}
+	defer args.Sources.Close()
+	var help1, help2 bool
+	datasrcsCommand := flag.NewFlagSet("datasrcs", flag.ContinueOnError)
+
+	datasrcsBuf := new(bytes.Buffer)
+	datasrcsCommand.SetOutput(datasrcsBuf)
+
+	datasrcsCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	datasrcsCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	datasrcsCommand.StringVar(&args.Domain, "d", selftest.DefaultCheckDomain, "Known-good domain name the data sources are checked with")
+	datasrcsCommand.Var(args.Sources, "src", "Data source names or categories to check (can be used multiple times)")
+	datasrcsCommand.IntVar(&args.Timeout, "timeout", int(selftest.DefaultCheckTimeout.Seconds()), "Number of seconds waited for each data source")
+	datasrcsCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	datasrcsCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	datasrcsCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+
+	if len(clArgs) < 1 || clArgs[0] != "check" {
+		commandUsage(datasrcsUsageMsg, datasrcsCommand, datasrcsBuf)
+		return
+	}
+	if err := datasrcsCommand.Parse(clArgs[1:]); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(datasrcsUsageMsg, datasrcsCommand, datasrcsBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Timeout < 1 {
+		r.Fprintln(color.Error, "The timeout flag must provide a positive value")
+		os.Exit(1)
+	}
+
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err != nil && args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	if args.Filepaths.Directory != "" {
+		cfg.Dir = args.Filepaths.Directory
+	}
+	createOutputDirectory(cfg)
+
+	domain := strings.ToLower(strings.TrimSpace(args.Domain))
+	cfg.AddDomain(domain)
+	// The cached responses would hide the data sources that are broken
+	cfg.ResponseCache = false
+
+	sys := &systems.SimpleSystem{
+		Cfg:      cfg,
+		Pool:     checkResolvers(cfg),
+		Trusted:  checkResolvers(cfg),
+		Graph:    netmap.NewGraph(netmap.NewCayleyGraphMemory()),
+		ASNCache: requests.NewASNCache(),
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	srcs := checkedSources(cfg, datasrcs.GetAllSources(sys), args.Sources.Slice())
+	g.Fprintf(color.Error, "Checking %d data sources with %s\n", len(srcs), domain)
+
+	results := selftest.CheckSources(context.Background(), srcs, domain, time.Duration(args.Timeout)*time.Second)
+	if printCheckResults(results) > 0 {
+		os.Exit(1)
+	}
+}
+
+// checkResolvers returns the trusted resolvers used by the data sources that send DNS queries.
+func checkResolvers(cfg *config.Config) *resolve.Resolvers {
+	pool := resolve.NewResolvers()
+	trusted := config.DefaultBaselineResolvers
+	if len(cfg.TrustedResolvers) > 0 {
+		trusted = cfg.TrustedResolvers
+	}
+
+	_ = pool.AddResolvers(cfg.TrustedQPS, trusted...)
+	pool.SetLogger(cfg.Log)
+	pool.SetTimeout(2 * time.Second)
+	return pool
+}
+
+// checkedSources returns the data sources named on the command line, or those selected by the
+// configuration. The name generators are left out, since they do not query a service.
+func checkedSources(cfg *config.Config, all []service.Service, names []string) []service.Service {
+	categories := generateCategoryMap(cfg, all)
+
+	if len(names) > 0 {
+		cfg.SourceFilter.Include = true
+		cfg.SourceFilter.Sources = names
+	}
+	cfg.SourceFilter.Sources = expandCategoryNames(cfg.SourceFilter.Sources, categories)
+
+	var srcs []service.Service
+	for _, src := range datasrcs.SelectedDataSources(cfg, all) {
+		switch src.Description() {
+		case requests.BRUTE, requests.ALT, requests.GUESS:
+			continue
+		}
+		srcs = append(srcs, src)
+	}
+	return srcs
+}
+
+// printCheckResults prints the outcome of each data source check and returns the number of failures.
+func printCheckResults(results []*selftest.CheckResult) int {
+	var passed, failed int
+
+	fmt.Fprintf(color.Output, "\n%-28s%-12s%-12s%-10s%s\n", blue("Data Source"), blue("Type"),
+		blue("Latency"), blue("Names"), blue("Status"))
+	for _, res := range results {
+		latency := "-"
+		if res.Passed() {
+			passed++
+			latency = res.Latency.Round(time.Millisecond).String()
+		}
+
+		status := yellow(res.Status)
+		if res.Failed() {
+			failed++
+			status = red(res.Status)
+			if res.Err != nil {
+				status = red(fmt.Sprintf("%s: %v", res.Status, res.Err))
+			} else if res.Errors > 0 {
+				status = red(fmt.Sprintf("%s: %d of %d", res.Status, res.Errors, res.Requests))
+			}
+		}
+
+		fmt.Fprintf(color.Output, "%-28s%-12s%-12s%-10s%s\n", green(res.Name), yellow(res.Type),
+			yellow(latency), yellow(res.Names), status)
+	}
+
+	fmt.Fprintf(color.Output, "\n%s %s, %s %s\n", green("Passed:"), yellow(passed), green("Failed:"), yellow(failed))
+	return failed
+}
+
+func runDatasrcsTestCommand(clArgs []string) {
+	var args datasrcsTestArgs
+	var help1, help2 bool
+	testCommand := flag.NewFlagSet("datasrcs test", flag.ContinueOnError)
+
+	testBuf := new(bytes.Buffer)
+	testCommand.SetOutput(testBuf)
+
+	testCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	testCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	testCommand.StringVar(&args.Filepaths.Fixture, "fixture", "", "Path to the fixture of the script (default: the script path with the .json extension)")
+	testCommand.BoolVar(&args.Options.Record, "record", false, "Query the service of the script and write the responses to the fixture")
+	testCommand.StringVar(&args.Domain, "d", selftest.DefaultCheckDomain, "Domain name queried while recording the fixture")
+	testCommand.StringVar(&args.Address, "addr", "", "IP address provided to the address callback while recording the fixture")
+	testCommand.IntVar(&args.Timeout, "timeout", int(scripttest.DefaultTimeout.Seconds()), "Number of seconds waited for the script")
+	testCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	testCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file providing the credentials while recording")
+	testCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+
+	if err := testCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 || testCommand.NArg() != 1 {
+		commandUsage(datasrcsTestUsageMsg, testCommand, testBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Timeout < 1 {
+		r.Fprintln(color.Error, "The timeout flag must provide a positive value")
+		os.Exit(1)
+	}
+
+	path := testCommand.Arg(0)
+	script, err := os.ReadFile(path)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to read the script: %v\n", err)
+		os.Exit(1)
+	}
+
+	fixture := args.Filepaths.Fixture
+	if fixture == "" {
+		fixture = strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+	}
+
+	ctx := context.Background()
+	timeout := time.Duration(args.Timeout) * time.Second
+	if args.Options.Record {
+		cfg := config.NewConfig()
+		// The credentials of the data source are read from the configuration file
+		if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err != nil && args.Filepaths.ConfigFile != "" {
+			r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+			os.Exit(1)
+		}
+
+		f, err := scripttest.Record(ctx, string(script), args.Domain, args.Address, cfg, timeout)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to record the fixture: %v\n", err)
+			os.Exit(1)
+		}
+		if err := f.Save(fixture); err != nil {
+			r.Fprintf(color.Error, "Failed to write the fixture: %v\n", err)
+			os.Exit(1)
+		}
+
+		g.Fprintf(color.Error, "Recorded %d responses and %d names in %s\n", len(f.Interactions), len(f.Names), fixture)
+		return
+	}
+
+	f, err := scripttest.LoadFixture(fixture)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	res, err := scripttest.Run(ctx, string(script), f, timeout)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if !printTestResult(res) {
+		os.Exit(1)
+	}
+}
+
+// printTestResult prints the differences between the names provided by the script and those of
+// the fixture, and returns true when the script passed.
+func printTestResult(res *scripttest.Result) bool {
+	for _, req := range res.Unmatched {
+		fmt.Fprintf(color.Error, "%s %s\n", yellow("No response in the fixture for"), req)
+	}
+	for _, name := range res.Missing {
+		fmt.Fprintf(color.Output, "%s %s\n", red("-"), name)
+	}
+	for _, name := range res.Unexpected {
+		fmt.Fprintf(color.Output, "%s %s\n", green("+"), name)
+	}
+
+	if !res.Passed() {
+		fmt.Fprintf(color.Output, "\n%s %s: %s %s, %s %s\n", red("Failed:"), green(res.Source),
+			yellow(len(res.Missing)), "names missing", yellow(len(res.Unexpected)), "unexpected names")
+		return false
+	}
+
+	fmt.Fprintf(color.Output, "%s %s provided the %s names of the fixture\n", green("Passed:"), green(res.Source), yellow(len(res.Names)))
+	return true
+}
@@ -13,15 +13,21 @@ import (
 	"io"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/caffix/netmap"
 	"github.com/caffix/stringset"
 	"github.com/fatih/color"
 	"github.com/owasp-amass/amass/v3/config"
 	"github.com/owasp-amass/amass/v3/datasrcs"
+	"github.com/owasp-amass/amass/v3/filter"
 	"github.com/owasp-amass/amass/v3/format"
+	"github.com/owasp-amass/amass/v3/neo4j"
 	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/stix"
 	"github.com/owasp-amass/amass/v3/systems"
 )
 
@@ -30,33 +36,57 @@ const (
 )
 
 type dbArgs struct {
-	Domains *stringset.Set
-	Enum    int
-	Options struct {
+	CSVColumns format.ParseCSVColumns
+	Domains    *stringset.Set
+	Enum       int
+	Filter     *filter.Filter
+	Selection  *filter.Selection
+	Workspace  string
+	Options    struct {
 		DemoMode         bool
 		IPs              bool
 		IPv4             bool
 		IPv6             bool
 		ListEnumerations bool
+		Neo4jFormat      string
 		ASNTableSummary  bool
 		DiscoveredNames  bool
+		Findings         bool
 		NoColor          bool
 		ShowAll          bool
 		Silent           bool
 		Sources          bool
+		Technologies     bool
+		Unprotected      bool
+		UsesTechnology   string
+		WAFs             bool
 	}
 	Filepaths struct {
-		ConfigFile string
-		Directory  string
-		Domains    string
-		JSONOutput string
-		TermOut    string
+		ConfigFile  string
+		CSVOutput   string
+		Directory   string
+		Domains     string
+		JSONOutput  string
+		Neo4jExport string
+		Neo4jImport string
+		STIXExport  string
+		TermOut     string
 	}
 }
 
 func runDBCommand(clArgs []string) {
 	var args dbArgs
 	var help1, help2 bool
+	var expr string
+	var minConfidence int
+	tags, xtags := stringset.New(), stringset.New()
+	outSrcs, xsrcs := stringset.New(), stringset.New()
+	defer func() {
+		tags.Close()
+		xtags.Close()
+		outSrcs.Close()
+		xsrcs.Close()
+	}()
 	dbCommand := flag.NewFlagSet("db", flag.ContinueOnError)
 
 	dbBuf := new(bytes.Buffer)
@@ -69,26 +99,60 @@ func runDBCommand(clArgs []string) {
 	dbCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
 	dbCommand.IntVar(&args.Enum, "enum", 0, "Identify an enumeration via an index from the listing")
 	dbCommand.BoolVar(&args.Options.DemoMode, "demo", false, "Censor output to make it suitable for demonstrations")
+	dbCommand.Var(xsrcs, "exclude-source", "Leave out the names discovered only by the data sources separated by commas")
+	dbCommand.Var(xtags, "exclude-tag", "Leave out the names discovered with the tags separated by commas (e.g. brute,alt,guess)")
+	dbCommand.StringVar(&expr, "filter", "", "Print just the discovered names matching the filter expression")
+	dbCommand.Var(outSrcs, "include-source", "Print just the names discovered by the data sources separated by commas")
+	dbCommand.Var(tags, "include-tag", "Print just the names discovered with the tags separated by commas (e.g. cert,api)")
 	dbCommand.BoolVar(&args.Options.IPs, "ip", false, "Show the IP addresses for discovered names")
 	dbCommand.BoolVar(&args.Options.IPv4, "ipv4", false, "Show the IPv4 addresses for discovered names")
 	dbCommand.BoolVar(&args.Options.IPv6, "ipv6", false, "Show the IPv6 addresses for discovered names")
 	dbCommand.BoolVar(&args.Options.ListEnumerations, "list", false, "Numbered list of enums filtered on provided domains")
+	dbCommand.IntVar(&minConfidence, "min-confidence", 0, "Print just the names with a confidence from 0 to 100 reaching the threshold")
 	dbCommand.BoolVar(&args.Options.Sources, "src", false, "Print data sources for the discovered names")
 	dbCommand.BoolVar(&args.Options.ASNTableSummary, "summary", false, "Print Just ASN Table Summary")
 	dbCommand.BoolVar(&args.Options.DiscoveredNames, "names", false, "Print Just Discovered Names")
+	dbCommand.BoolVar(&args.Options.Findings, "findings", false, "Print just the discovered names with findings")
+	dbCommand.BoolVar(&args.Options.Technologies, "tech", false, "Print the web technologies identified for the discovered names")
+	dbCommand.StringVar(&args.Options.UsesTechnology, "uses", "", "Print just the discovered names using the web technology")
+	dbCommand.BoolVar(&args.Options.WAFs, "waf", false, "Print the web application firewalls protecting the discovered names")
+	dbCommand.BoolVar(&args.Options.Unprotected, "unprotected", false, "Print just the probed names not protected by a web application firewall")
 	dbCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
 	dbCommand.BoolVar(&args.Options.ShowAll, "show", false, "Print the results for the enumeration index + domains provided")
 	dbCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
 	dbCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	dbCommand.StringVar(&args.Filepaths.CSVOutput, "csv", "", "Path to the CSV output file or '-'")
+	dbCommand.Var(&args.CSVColumns, "csv-columns", "CSV columns in order separated by commas (default: name,domain,addresses,tag,sources)")
 	dbCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
 	dbCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
 	dbCommand.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file")
+	dbCommand.StringVar(&args.Filepaths.Neo4jExport, "export-neo4j", "", "Path to the Neo4j Cypher file, or the directory for the CSV files")
+	dbCommand.StringVar(&args.Options.Neo4jFormat, "neo4j-format", "cypher", "Format of the Neo4j export: cypher or csv")
+	dbCommand.StringVar(&args.Filepaths.Neo4jImport, "import-neo4j", "", "Path to the directory containing the Neo4j CSV files to import")
+	dbCommand.StringVar(&args.Filepaths.STIXExport, "stix", "", "Path to the STIX 2.1 bundle output file")
 	dbCommand.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
+	dbCommand.StringVar(&args.Workspace, "workspace", "", "Name of the workspace isolating the enumerations in the graph database")
 
 	if len(clArgs) < 1 {
 		commandUsage(dbUsageMsg, dbCommand, dbBuf)
 		return
 	}
+	if clArgs[0] == "diff" {
+		runDBDiffCommand(clArgs[1:])
+		return
+	}
+	if clArgs[0] == "prune" {
+		runDBPruneCommand(clArgs[1:])
+		return
+	}
+	if clArgs[0] == "export" {
+		runDBExportCommand(clArgs[1:])
+		return
+	}
+	if clArgs[0] == "import" {
+		runDBImportCommand(clArgs[1:])
+		return
+	}
 	if err := dbCommand.Parse(clArgs); err != nil {
 		r.Fprintf(color.Error, "%v\n", err)
 		os.Exit(1)
@@ -104,6 +168,10 @@ func runDBCommand(clArgs []string) {
 		color.Output = io.Discard
 		color.Error = io.Discard
 	}
+	if f := args.Options.Neo4jFormat; f != "cypher" && f != "csv" {
+		r.Fprintf(color.Error, "The Neo4j format must be cypher or csv, not %s\n", f)
+		os.Exit(1)
+	}
 	if args.Filepaths.Domains != "" {
 		list, err := config.GetListFromFile(args.Filepaths.Domains)
 		if err != nil {
@@ -112,6 +180,22 @@ func runDBCommand(clArgs []string) {
 		}
 		args.Domains.InsertMany(list...)
 	}
+	if expr != "" {
+		f, err := filter.Parse(expr)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the filter: %v\n", err)
+			os.Exit(1)
+		}
+		args.Filter = f
+	}
+	sel, err := outputSelection(tags, xtags, outSrcs, xsrcs, minConfidence)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if !sel.Empty() {
+		args.Selection = sel
+	}
 
 	cfg := config.NewConfig()
 	// Check if a configuration file was provided, and if so, load the settings
@@ -119,6 +203,9 @@ func runDBCommand(clArgs []string) {
 		if args.Filepaths.Directory == "" {
 			args.Filepaths.Directory = cfg.Dir
 		}
+		if args.Workspace == "" {
+			args.Workspace = cfg.Workspace
+		}
 		if args.Domains.Len() == 0 {
 			args.Domains.InsertMany(cfg.Domains()...)
 		}
@@ -139,8 +226,15 @@ func runDBCommand(clArgs []string) {
 		os.Exit(1)
 	}
 	defer db.Close()
+	if args.Filepaths.Neo4jImport != "" {
+		if err := importNeo4j(args.Filepaths.Neo4jImport, db); err != nil {
+			r.Fprintf(color.Error, "Failed to import the Neo4j CSV files: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 	// Create the in-memory graph database for events that have information in scope
-	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), db)
+	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), args.Workspace, db)
 	if err != nil {
 		r.Fprintln(color.Error, err.Error())
 		os.Exit(1)
@@ -160,7 +254,12 @@ func runDBCommand(clArgs []string) {
 		args.Options.DiscoveredNames = true
 		args.Options.ASNTableSummary = true
 	}
-	if !args.Options.DiscoveredNames && !args.Options.ASNTableSummary {
+	if args.Options.Findings || args.Options.Technologies || args.Options.UsesTechnology != "" ||
+		args.Options.WAFs || args.Options.Unprotected || args.Filter != nil || args.Selection != nil || args.Filepaths.CSVOutput != "" {
+		args.Options.DiscoveredNames = true
+	}
+	if !args.Options.DiscoveredNames && !args.Options.ASNTableSummary &&
+		args.Filepaths.Neo4jExport == "" && args.Filepaths.STIXExport == "" {
 		commandUsage(dbUsageMsg, dbCommand, dbBuf)
 		return
 	}
@@ -176,15 +275,58 @@ func runDBCommand(clArgs []string) {
 
 		uuids = []string{uuids[idx]}
 	}
+	if args.Filepaths.Neo4jExport != "" {
+		if err := exportNeo4j(&args, uuids, memDB); err != nil {
+			r.Fprintf(color.Error, "Failed to export the graph to Neo4j: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if args.Filepaths.STIXExport != "" {
+		if err := exportSTIX(args.Filepaths.STIXExport, uuids, memDB); err != nil {
+			r.Fprintf(color.Error, "Failed to export the STIX bundle: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	var asninfo bool
-	if args.Options.ASNTableSummary {
+	// The filter expressions can compare the netblocks and autonomous systems of the addresses
+	if args.Options.ASNTableSummary || (args.Filter != nil && args.Filter.Uses("asn", "cidr", "desc")) {
+		asninfo = true
+	}
+	if args.Filepaths.CSVOutput != "" && (args.CSVColumns.Has("asn") || args.CSVColumns.Has("cidr")) {
 		asninfo = true
 	}
 
 	showEventData(&args, uuids, asninfo, memDB)
 }
 
+// usesTechnology returns true when a web technology with the name was identified for the output.
+func usesTechnology(out *requests.Output, name string) bool {
+	for _, t := range out.Technologies() {
+		if strings.EqualFold(strings.SplitN(t, "/", 2)[0], name) {
+			return true
+		}
+	}
+	return false
+}
+
+// outputSelection returns the selection of the discovered names requested by the output flags.
+func outputSelection(tags, xtags, srcs, xsrcs *stringset.Set, min int) (*filter.Selection, error) {
+	sel := &filter.Selection{
+		IncludeTags:    tags.Slice(),
+		ExcludeTags:    xtags.Slice(),
+		IncludeSources: srcs.Slice(),
+		ExcludeSources: xsrcs.Slice(),
+		MinConfidence:  min,
+	}
+	if err := sel.Check(); err != nil {
+		return nil, err
+	}
+	return sel, nil
+}
+
 func listEvents(uuids []string, db *netmap.Graph) {
 	events, earliest, latest := orderedEvents(context.Background(), uuids, db)
 	// Check if the user has requested the list of enumerations
@@ -236,12 +378,64 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph)
 		}
 	}
 
+	var csvw *format.CSVWriter
+	if args.Filepaths.CSVOutput != "" {
+		csvptr := os.Stdout
+		// Write to STDOUT and not a file if named "-"
+		if args.Filepaths.CSVOutput != "-" {
+			csvptr, err = os.Create(args.Filepaths.CSVOutput)
+			if err != nil {
+				r.Fprintf(color.Error, "Failed to open the CSV output file: %v\n", err)
+				os.Exit(1)
+			}
+			defer csvptr.Close()
+		}
+
+		csvw = format.NewCSVWriter(csvptr, args.CSVColumns)
+		defer func() { _ = csvw.Flush() }()
+	}
+
+	var seen *sightings
+	// The names are seen across all the enumerations in scope, also when a single one was selected
+	if args.Filepaths.JSONOutput != "" || (args.Filter != nil && args.Filter.Uses("first", "seen")) ||
+		(csvw != nil && (args.CSVColumns.Has("first_seen") || args.CSVColumns.Has("last_seen"))) {
+		seen = newSightings()
+		seen.addEvents(context.Background(), db, db.EventList(context.Background()))
+	}
+
 	tags := make(map[string]int)
 	asns := make(map[int]*format.ASNSummaryData)
 	for _, out := range getEventOutput(context.Background(), uuids, asninfo, db, cache) {
 		if len(domains) > 0 && !domainNameInScope(out.Name, domains) {
 			continue
 		}
+		a := &filter.Asset{Output: out}
+		if seen != nil {
+			seen.stamp(out, time.Time{})
+			a.FirstSeen, a.LastSeen = seen.nameSeen(out.Name)
+		}
+		if args.Filter != nil && !args.Filter.Match(a) {
+			continue
+		}
+		if !args.Selection.Match(out) {
+			continue
+		}
+		if args.Options.Findings && len(out.Findings) == 0 {
+			continue
+		}
+		if args.Options.Technologies && len(out.Technologies()) == 0 {
+			continue
+		}
+		if t := args.Options.UsesTechnology; t != "" && !usesTechnology(out, t) {
+			continue
+		}
+		if args.Options.WAFs && len(out.WAFs()) == 0 {
+			continue
+		}
+		// Only the names with web pages probed can be known to lack a firewall
+		if args.Options.Unprotected && (len(out.HTTP) == 0 || len(out.WAFs()) > 0) {
+			continue
+		}
 
 		out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
 		if l := len(out.Addresses); (args.Options.IPs || args.Options.IPv4 || args.Options.IPv6) && l == 0 {
@@ -256,19 +450,39 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph)
 		if ips != "" {
 			ips = " " + ips
 		}
+		var techs string
+		if args.Options.Technologies {
+			techs = " [" + strings.Join(out.Technologies(), ", ") + "]"
+		}
+		if args.Options.WAFs {
+			techs += " [WAF: " + strings.Join(out.WAFs(), ", ") + "]"
+		}
 
 		if args.Options.DiscoveredNames {
 			var written bool
 			if outfile != nil {
-				fmt.Fprintf(outfile, "%s%s%s\n", source, name, ips)
+				fmt.Fprintf(outfile, "%s%s%s%s\n", source, name, ips, techs)
+				for _, line := range format.FindingLines(out) {
+					fmt.Fprintln(outfile, line)
+				}
 				written = true
 			}
 			if args.Filepaths.JSONOutput != "" {
 				discovered = append(discovered, out)
 				written = true
 			}
+			if csvw != nil {
+				if err := csvw.Write(out, a.FirstSeen); err != nil {
+					r.Fprintf(color.Error, "Failed to write the CSV output: %v\n", err)
+					os.Exit(1)
+				}
+				written = true
+			}
 			if !written {
-				fmt.Fprintf(color.Output, "%s%s%s\n", blue(source), green(name), yellow(ips))
+				fmt.Fprintf(color.Output, "%s%s%s%s\n", blue(source), green(name), yellow(ips), magenta(techs))
+				for _, line := range format.FindingLines(out) {
+					fmt.Fprintln(color.Output, red(line))
+				}
 			}
 		}
 	}
@@ -286,7 +500,7 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph)
 		if outfile != nil {
 			out = outfile
 			color.NoColor = true
-		} else if args.Options.ShowAll {
+		} else if args.Options.ShowAll || args.Filepaths.CSVOutput == "-" {
 			out = color.Error
 		} else {
 			out = color.Output
@@ -297,6 +511,41 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph)
 	}
 }
 
+// eventTimes returns the start and finish times of the events.
+func eventTimes(uuids []string, db *netmap.Graph) map[string][2]time.Time {
+	times := make(map[string][2]time.Time, len(uuids))
+
+	events, earliest, latest := orderedEvents(context.Background(), append([]string(nil), uuids...), db)
+	for i, uuid := range events {
+		times[uuid] = [2]time.Time{earliest[i], latest[i]}
+	}
+	return times
+}
+
+// nameSeen returns the start of the first and the finish of the last event that discovered the name.
+func nameSeen(name string, times map[string][2]time.Time, db *netmap.Graph) (time.Time, time.Time) {
+	var first, last time.Time
+
+	edges, err := db.ReadInEdges(context.Background(), netmap.Node(name))
+	if err != nil {
+		return first, last
+	}
+
+	for _, edge := range edges {
+		t, found := times[db.NodeToID(edge.From)]
+		if !found {
+			continue
+		}
+		if first.IsZero() || t[0].Before(first) {
+			first = t[0]
+		}
+		if t[1].After(last) {
+			last = t[1]
+		}
+	}
+	return first, last
+}
+
 type jsonEvent struct {
 	UUID   string `json:"uuid"`
 	Start  string `json:"start"`
@@ -370,6 +619,78 @@ func writeJSON(args *dbArgs, uuids []string, assets []*requests.Output, db *netm
 	_ = jsonptr.Close()
 }
 
+// exportNeo4j writes the nodes and relationships of the events as Cypher statements, or the CSV files of the neo4j-admin import tool.
+func exportNeo4j(args *dbArgs, uuids []string, db *netmap.Graph) error {
+	quads, err := db.ReadEventQuads(context.Background(), uuids...)
+	if err != nil {
+		return err
+	}
+	g := neo4j.NewGraph(quads)
+
+	if args.Options.Neo4jFormat == "cypher" {
+		f, err := os.Create(args.Filepaths.Neo4jExport)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		return neo4j.WriteCypher(f, g)
+	}
+
+	dir := args.Filepaths.Neo4jExport
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	nodes, err := os.Create(filepath.Join(dir, neo4j.NodesFile))
+	if err != nil {
+		return err
+	}
+	defer nodes.Close()
+	rels, err := os.Create(filepath.Join(dir, neo4j.RelationshipsFile))
+	if err != nil {
+		return err
+	}
+	defer rels.Close()
+
+	return neo4j.WriteCSV(nodes, rels, g)
+}
+
+// exportSTIX writes the names, addresses, netblocks and autonomous systems of the events as a STIX 2.1 bundle.
+func exportSTIX(path string, uuids []string, db *netmap.Graph) error {
+	quads, err := db.ReadEventQuads(context.Background(), uuids...)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return stix.WriteBundle(f, stix.NewBundle(quads, time.Now()))
+}
+
+// importNeo4j writes the nodes and relationships in the Neo4j CSV files of the directory into the graph database.
+func importNeo4j(dir string, db *netmap.Graph) error {
+	nodes, err := os.Open(filepath.Join(dir, neo4j.NodesFile))
+	if err != nil {
+		return err
+	}
+	defer nodes.Close()
+	rels, err := os.Open(filepath.Join(dir, neo4j.RelationshipsFile))
+	if err != nil {
+		return err
+	}
+	defer rels.Close()
+
+	g, err := neo4j.ReadCSV(nodes, rels)
+	if err != nil {
+		return err
+	}
+	return systems.WriteQuads(context.Background(), db, g.Quads())
+}
+
 func fillCache(cache *requests.ASNCache, db *netmap.Graph) error {
 	aslist, err := db.AllNodesOfType(context.Background(), netmap.TypeAS)
 	if err != nil {
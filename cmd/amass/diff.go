@@ -0,0 +1,153 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/diff"
+)
+
+const (
+	diffUsageMsg = "db diff [options]"
+)
+
+type diffArgs struct {
+	Domains   *stringset.Set
+	From      int
+	To        int
+	Workspace string
+	Options   struct {
+		NoColor bool
+		Silent  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+		Domains    string
+		Output     string
+	}
+}
+
+func runDBDiffCommand(clArgs []string) {
+	var args diffArgs
+	var help1, help2 bool
+	diffCommand := flag.NewFlagSet("diff", flag.ContinueOnError)
+
+	diffBuf := new(bytes.Buffer)
+	diffCommand.SetOutput(diffBuf)
+	args.Domains = stringset.New()
+	defer args.Domains.Close()
+
+	diffCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	diffCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	diffCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	diffCommand.IntVar(&args.From, "from", 2, "Index from the listing of the older enumeration")
+	diffCommand.IntVar(&args.To, "to", 1, "Index from the listing of the newer enumeration")
+	diffCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	diffCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	diffCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	diffCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	diffCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	diffCommand.StringVar(&args.Filepaths.Output, "o", "-", "Path to the JSON output file or '-'")
+	diffCommand.StringVar(&args.Workspace, "workspace", "", "Name of the workspace isolating the enumerations in the graph database")
+
+	if err := diffCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(diffUsageMsg, diffCommand, diffBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Error = io.Discard
+	}
+	if args.From < 1 || args.To < 1 || args.From == args.To {
+		r.Fprintln(color.Error, "The from and to flags must provide different indices from the listing")
+		os.Exit(1)
+	}
+	if args.Filepaths.Domains != "" {
+		list, err := config.GetListFromFile(args.Filepaths.Domains)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the domain names file: %v\n", err)
+			os.Exit(1)
+		}
+		args.Domains.InsertMany(list...)
+	}
+
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+		if args.Workspace == "" {
+			args.Workspace = cfg.Workspace
+		}
+		if args.Domains.Len() == 0 {
+			args.Domains.InsertMany(cfg.Domains()...)
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+
+	db := openGraphDatabase(args.Filepaths.Directory, cfg)
+	if db == nil {
+		r.Fprintln(color.Error, "Failed to connect with the database")
+		os.Exit(1)
+	}
+	defer db.Close()
+	// Create the in-memory graph database for events that have information in scope
+	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), args.Workspace, db)
+	if err != nil {
+		r.Fprintln(color.Error, err.Error())
+		os.Exit(1)
+	}
+	defer memDB.Close()
+	// Put the events in chronological order, as shown by the listing
+	uuids, _, _ := orderedEvents(context.Background(), memDB.EventList(context.Background()), memDB)
+	if args.From > len(uuids) || args.To > len(uuids) {
+		r.Fprintf(color.Error, "%d enumerations are available in the listing\n", len(uuids))
+		os.Exit(1)
+	}
+
+	from := uuids[len(uuids)-args.From]
+	to := uuids[len(uuids)-args.To]
+	d, err := diff.Events(context.Background(), memDB, from, to, args.Domains.Slice())
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to compare the enumerations: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if args.Filepaths.Output != "-" {
+		out, err = os.Create(args.Filepaths.Output)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the JSON output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		r.Fprintf(color.Error, "Failed to write the differences: %v\n", err)
+		os.Exit(1)
+	}
+}
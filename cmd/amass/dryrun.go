@@ -0,0 +1,203 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caffix/service"
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/datasrcs"
+	"github.com/owasp-amass/amass/v3/enum"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+	"github.com/owasp-amass/resolve"
+)
+
+// The states of the data sources in the plan
+const (
+	planReady       = "ready"
+	planNoKey       = "missing API credentials"
+	planUnavailable = "failed the check of its configuration"
+	planExcluded    = "excluded"
+)
+
+// printEnumPlan prints the data sources, active techniques and DNS query volume of the enumeration
+// described by the configuration. The data sources are started without resolvers or graph databases,
+// so no traffic is sent while the plan is made.
+func printEnumPlan(cfg *config.Config) {
+	// Load the wordlists and check the settings, as the enumeration does before starting
+	if err := cfg.CheckSettings(); err != nil {
+		r.Fprintf(color.Error, "Configuration error: %v\n", err)
+		os.Exit(1)
+	}
+
+	sys := &systems.SimpleSystem{
+		Cfg:      cfg,
+		Pool:     resolve.NewResolvers(),
+		Trusted:  resolve.NewResolvers(),
+		ASNCache: requests.NewASNCache(),
+	}
+
+	all := datasrcs.GetAllSources(sys)
+	cfg.SourceFilter.Sources = expandCategoryNames(cfg.SourceFilter.Sources, generateCategoryMap(cfg, all))
+
+	selected := make(map[string]struct{})
+	for _, src := range datasrcs.SelectedDataSources(cfg, all) {
+		selected[src.String()] = struct{}{}
+	}
+
+	var ready int
+	states := make(map[string]string, len(all))
+	for _, src := range all {
+		if _, found := selected[src.String()]; !found {
+			states[src.String()] = planExcluded
+			continue
+		}
+		states[src.String()] = sourcePlanState(src)
+		if states[src.String()] == planReady {
+			ready++
+		}
+	}
+
+	mode := "normal"
+	if cfg.StrictPassive {
+		mode = "strict passive"
+	} else if cfg.Passive {
+		mode = "passive"
+	} else if cfg.Active {
+		mode = "active"
+	}
+	fmt.Fprintf(color.Output, "%s %s\n", blue("Mode:"), yellow(mode))
+	fmt.Fprintf(color.Output, "%s %s\n", blue("Root domains:"), yellow(strings.Join(cfg.Domains(), ", ")))
+
+	fmt.Fprintf(color.Output, "\n%s %s\n", blue("Data sources that will run:"), yellow(fmt.Sprintf("%d of %d", ready, len(all))))
+	fmt.Fprintf(color.Output, "%-35s%-35s%s\n", blue("Data Source"), blue("Type"), blue("Status"))
+	for _, src := range all {
+		state := states[src.String()]
+
+		status := yellow(state)
+		if state == planNoKey || state == planUnavailable {
+			status = red(state)
+		}
+		fmt.Fprintf(color.Output, "%-35s%-35s%s\n", green(src.String()), yellow(src.Description()), status)
+	}
+
+	fmt.Fprintf(color.Output, "\n%s\n", blue("Active techniques:"))
+	techniques := planTechniques(cfg)
+	if len(techniques) == 0 {
+		fmt.Fprintln(color.Output, yellow("None"))
+	}
+	for _, t := range techniques {
+		fmt.Fprintln(color.Output, yellow(t))
+	}
+
+	fmt.Fprintf(color.Output, "\n%s\n", blue("DNS resolution:"))
+	for _, line := range planDNS(cfg) {
+		fmt.Fprintln(color.Output, yellow(line))
+	}
+}
+
+// sourcePlanState starts and stops the data source, which runs the check of its configuration.
+func sourcePlanState(src service.Service) string {
+	if err := src.Start(); err != nil {
+		if src.Description() == requests.API {
+			return planNoKey
+		}
+		return planUnavailable
+	}
+
+	_ = src.Stop()
+	return planReady
+}
+
+func planTechniques(cfg *config.Config) []string {
+	var techniques []string
+
+	if cfg.Passive {
+		return techniques
+	}
+	if cfg.BruteForcing {
+		t := fmt.Sprintf("Brute forcing with %d words", len(cfg.Wordlist))
+		if cfg.Recursive {
+			t += ", recursively after " + strconv.Itoa(cfg.MinForRecursive) + " subdomain labels"
+		}
+		techniques = append(techniques, t)
+	}
+	if cfg.Alterations {
+		techniques = append(techniques, fmt.Sprintf("Name alterations with %d words", len(cfg.AltWordlist)))
+	}
+	if cfg.Active {
+		techniques = append(techniques, "Zone transfers and certificate name grabs")
+	}
+	if cfg.BatchDNS {
+		techniques = append(techniques, "Batch DNS sender for the brute forcing queries")
+	}
+	if cfg.Takeovers {
+		techniques = append(techniques, "Subdomain takeover checks")
+	}
+	if cfg.DanglingRecords {
+		techniques = append(techniques, "Dangling DNS record checks")
+	}
+	if cfg.PortScans {
+		techniques = append(techniques, "TCP port scans")
+	}
+	if cfg.HTTPProbes {
+		techniques = append(techniques, "HTTP and HTTPS probes")
+	}
+	if cfg.Screenshots {
+		techniques = append(techniques, "Screenshots of the probed web pages")
+	}
+	if cfg.Buckets {
+		techniques = append(techniques, "Cloud storage bucket guesses")
+	}
+	return techniques
+}
+
+// planDNS estimates the DNS queries sent for the names known before the enumeration starts. The names
+// discovered by the data sources, the recursive brute forcing and the alterations add to the estimate.
+func planDNS(cfg *config.Config) []string {
+	if cfg.Passive {
+		return []string{"None, the names are not resolved in the passive mode"}
+	}
+
+	domains := len(cfg.Domains())
+	names := domains + len(cfg.ProvidedNames) + len(cfg.ImportedNames)
+	if cfg.BruteForcing {
+		names += domains * len(cfg.Wordlist)
+	}
+	queries := names * len(enum.FwdQueryTypes)
+
+	trusted := len(config.DefaultBaselineResolvers)
+	if len(cfg.TrustedResolvers) > 0 {
+		trusted = len(cfg.TrustedResolvers)
+	}
+	lines := []string{fmt.Sprintf("Trusted resolvers: %d at %d queries per second each", trusted, cfg.TrustedQPS)}
+
+	var qps int
+	if len(cfg.Resolvers) > 0 {
+		qps = len(cfg.Resolvers) * cfg.ResolversQPS
+		lines = append(lines, fmt.Sprintf("Untrusted resolvers: %d at %d queries per second each",
+			len(cfg.Resolvers), cfg.ResolversQPS))
+	} else {
+		lines = append(lines, fmt.Sprintf("Untrusted resolvers: the public resolvers list at %d queries per second each",
+			cfg.ResolversQPS))
+	}
+	if cfg.MaxDNSQueries > 0 && (qps == 0 || cfg.MaxDNSQueries < qps) {
+		qps = cfg.MaxDNSQueries
+	}
+
+	lines = append(lines, fmt.Sprintf("Estimated queries: at least %d for %d names", queries, names))
+	if qps > 0 {
+		d := time.Duration(float64(queries) / float64(qps) * float64(time.Second)).Round(time.Second)
+		lines = append(lines, fmt.Sprintf("Estimated resolution time: at least %s at %d queries per second", d, qps))
+	}
+	return lines
+}
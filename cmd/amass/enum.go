@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -24,12 +25,16 @@ import (
 	"time"
 
 	"github.com/caffix/netmap"
+	"github.com/caffix/service"
 	"github.com/caffix/stringset"
 	"github.com/fatih/color"
 	"github.com/owasp-amass/amass/v3/config"
 	"github.com/owasp-amass/amass/v3/datasrcs"
 	"github.com/owasp-amass/amass/v3/enum"
+	"github.com/owasp-amass/amass/v3/filter"
 	"github.com/owasp-amass/amass/v3/format"
+	"github.com/owasp-amass/amass/v3/logging"
+	"github.com/owasp-amass/amass/v3/output"
 	"github.com/owasp-amass/amass/v3/requests"
 	"github.com/owasp-amass/amass/v3/systems"
 )
@@ -40,41 +45,73 @@ type enumArgs struct {
 	Addresses         format.ParseIPs
 	ASNs              format.ParseInts
 	CIDRs             format.ParseCIDRs
+	CSVColumns        format.ParseCSVColumns
 	AltWordList       *stringset.Set
 	AltWordListMask   *stringset.Set
 	BruteWordList     *stringset.Set
 	BruteWordListMask *stringset.Set
+	BatchQPS          int
 	Blacklist         *stringset.Set
+	Cron              string
+	CronSchedule      *config.CronExpr
 	Domains           *stringset.Set
 	Excluded          *stringset.Set
+	ExcludedTags      *stringset.Set
+	ExcludedOutSrcs   *stringset.Set
+	HeapThreshold     int
+	Imported          *stringset.Set
 	Included          *stringset.Set
+	IncludedTags      *stringset.Set
+	IncludedOutSrcs   *stringset.Set
 	Interface         string
+	Interval          int
 	MaxDNSQueries     int
 	ResolverQPS       int
 	TrustedQPS        int
 	MaxDepth          int
 	MinForRecursive   int
+	MinConfidence     int
+	MaxScreenshots    int
+	TopPorts          int
 	Names             *stringset.Set
 	Ports             format.ParseInts
+	Selection         *filter.Selection
+	Profiling         string
 	Resolvers         *stringset.Set
 	Trusted           *stringset.Set
 	Timeout           int
+	Workspace         string
 	Options           struct {
 		Active          bool
 		Alterations     bool
+		BatchDNS        bool
 		BruteForcing    bool
+		Buckets         bool
+		Daemon          bool
+		Dangling        bool
 		DemoMode        bool
+		DryRun          bool
 		IPs             bool
 		IPv4            bool
 		IPv6            bool
 		ListSources     bool
+		NewOnly         bool
 		NoAlts          bool
+		NoCloudRanges   bool
 		NoColor         bool
 		NoLocalDatabase bool
 		NoRecursive     bool
 		Passive         bool
+		PassiveStrict   bool
+		PortScans       bool
+		Probe           bool
+		Progress        bool
+		ScanCDNs        bool
+		Screenshots     bool
 		Silent          bool
 		Sources         bool
+		Takeovers       bool
+		Tor             bool
 		Verbose         bool
 	}
 	Filepaths struct {
@@ -83,13 +120,17 @@ type enumArgs struct {
 		Blacklist        string
 		BruteWordlist    format.ParseStrings
 		ConfigFile       string
+		CSVOutput        string
 		Directory        string
 		Domains          format.ParseStrings
 		ExcludedSrcs     string
+		Imports          format.ParseStrings
 		IncludedSrcs     string
 		JSONOutput       string
 		LogFile          string
 		Names            format.ParseStrings
+		NDJSONOutput     string
+		PluginsDirectory string
 		Resolvers        format.ParseStrings
 		Trusted          format.ParseStrings
 		ScriptsDirectory string
@@ -102,42 +143,71 @@ func defineEnumArgumentFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.Var(args.AltWordListMask, "awm", "\"hashcat-style\" wordlist masks for name alterations")
 	enumFlags.Var(&args.ASNs, "asn", "ASNs separated by commas (can be used multiple times)")
 	enumFlags.Var(&args.CIDRs, "cidr", "CIDRs separated by commas (can be used multiple times)")
+	enumFlags.IntVar(&args.BatchQPS, "batch-qps", 0, "Maximum number of DNS queries per second sent by the batch DNS sender")
 	enumFlags.Var(args.Blacklist, "bl", "Blacklist of subdomain names that will not be investigated")
+	enumFlags.StringVar(&args.Cron, "cron", "", "Cron expression scheduling the enumerations of the daemon mode in place of the interval")
+	enumFlags.Var(&args.CSVColumns, "csv-columns", "CSV columns in order separated by commas (default: name,domain,addresses,tag,sources)")
 	enumFlags.Var(args.BruteWordListMask, "wm", "\"hashcat-style\" wordlist masks for DNS brute forcing")
 	enumFlags.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
 	enumFlags.Var(args.Excluded, "exclude", "Data source names separated by commas to be excluded")
+	enumFlags.Var(args.ExcludedOutSrcs, "exclude-source", "Leave out the names discovered only by the data sources separated by commas")
+	enumFlags.Var(args.ExcludedTags, "exclude-tag", "Leave out the names discovered with the tags separated by commas (e.g. brute,alt,guess)")
+	enumFlags.IntVar(&args.HeapThreshold, "heap-threshold", 0, "Megabytes of heap in use that trigger writing a heap profile to the output directory")
 	enumFlags.Var(args.Included, "include", "Data source names separated by commas to be included")
+	enumFlags.Var(args.IncludedOutSrcs, "include-source", "Output just the names discovered by the data sources separated by commas")
+	enumFlags.Var(args.IncludedTags, "include-tag", "Output just the names discovered with the tags separated by commas (e.g. cert,api)")
 	enumFlags.StringVar(&args.Interface, "iface", "", "Provide the network interface to send traffic through")
+	enumFlags.IntVar(&args.Interval, "interval", defaultDaemonInterval, "Number of minutes between the enumerations of the daemon mode")
 	enumFlags.IntVar(&args.MaxDNSQueries, "max-dns-queries", 0, "Deprecated flag to be replaced by dns-qps in version 4.0")
 	enumFlags.IntVar(&args.MaxDNSQueries, "dns-qps", 0, "Maximum number of DNS queries per second across all resolvers")
 	enumFlags.IntVar(&args.ResolverQPS, "rqps", 0, "Maximum number of DNS queries per second for each untrusted resolver")
 	enumFlags.IntVar(&args.TrustedQPS, "trqps", 0, "Maximum number of DNS queries per second for each trusted resolver")
 	enumFlags.IntVar(&args.MaxDepth, "max-depth", 0, "Maximum number of subdomain labels for brute forcing")
 	enumFlags.IntVar(&args.MinForRecursive, "min-for-recursive", 1, "Subdomain labels seen before recursive brute forcing (Default: 1)")
+	enumFlags.IntVar(&args.MinConfidence, "min-confidence", 0, "Output just the names with a confidence from 0 to 100 reaching the threshold")
+	enumFlags.IntVar(&args.MaxScreenshots, "max-screenshots", 0, "Maximum number of screenshots captured at the same time")
 	enumFlags.Var(&args.Ports, "p", "Ports separated by commas (default: 80, 443)")
+	enumFlags.StringVar(&args.Profiling, "pprof", "", "Address serving the net/http/pprof profiling endpoints, such as localhost:6060")
+	enumFlags.IntVar(&args.TopPorts, "top-ports", 0, "Number of the most commonly open ports included in port scans (default: 100)")
 	enumFlags.Var(args.Resolvers, "r", "IP addresses of untrusted DNS resolvers (can be used multiple times)")
-	enumFlags.Var(args.Resolvers, "tr", "IP addresses of trusted DNS resolvers (can be used multiple times)")
+	enumFlags.Var(args.Trusted, "tr", "IP addresses of trusted DNS resolvers (can be used multiple times)")
 	enumFlags.IntVar(&args.Timeout, "timeout", 0, "Number of minutes to let enumeration run before quitting")
+	enumFlags.StringVar(&args.Workspace, "workspace", "", "Name of the workspace isolating the enumerations in the graph database")
 }
 
 func defineEnumOptionFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	var placeholder bool
 	enumFlags.BoolVar(&args.Options.Active, "active", false, "Attempt zone transfers and certificate name grabs")
+	enumFlags.BoolVar(&args.Options.BatchDNS, "batch-dns", false, "Send the brute forcing queries to the trusted resolvers in batches at a high rate")
 	enumFlags.BoolVar(&args.Options.BruteForcing, "brute", false, "Execute brute forcing after searches")
+	enumFlags.BoolVar(&args.Options.Buckets, "buckets", false, "Guess and probe cloud storage bucket names")
+	enumFlags.BoolVar(&args.Options.Daemon, "daemon", false, "Repeat the enumeration on a schedule and only report the changes")
+	enumFlags.BoolVar(&args.Options.Dangling, "dangling", false, "Report DNS records referencing nonexistent resources")
 	enumFlags.BoolVar(&args.Options.DemoMode, "demo", false, "Censor output to make it suitable for demonstrations")
+	enumFlags.BoolVar(&args.Options.DryRun, "dry-run", false, "Print the plan of the enumeration and exit without sending traffic")
 	enumFlags.BoolVar(&args.Options.IPs, "ip", false, "Show the IP addresses for discovered names")
 	enumFlags.BoolVar(&args.Options.IPv4, "ipv4", false, "Show the IPv4 addresses for discovered names")
 	enumFlags.BoolVar(&args.Options.IPv6, "ipv6", false, "Show the IPv6 addresses for discovered names")
 	enumFlags.BoolVar(&args.Options.ListSources, "list", false, "Print the names of all available data sources")
 	enumFlags.BoolVar(&args.Options.Alterations, "alts", false, "Enable generation of altered names")
+	enumFlags.BoolVar(&args.Options.NewOnly, "new-only", false, "Skip the names discovered by previous enumerations in the workspace")
 	enumFlags.BoolVar(&args.Options.NoAlts, "noalts", true, "Deprecated flag to be removed in version 4.0")
+	enumFlags.BoolVar(&args.Options.NoCloudRanges, "no-cloud-ranges", false, "Do not download the cloud provider ranges used to tag the addresses")
 	enumFlags.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
 	enumFlags.BoolVar(&placeholder, "nolocaldb", false, "Deprecated feature to be removed in version 4.0")
 	enumFlags.BoolVar(&args.Options.NoRecursive, "norecursive", false, "Turn off recursive brute forcing")
 	enumFlags.BoolVar(&args.Options.Passive, "passive", false, "Disable DNS resolution of names and dependent features")
+	enumFlags.BoolVar(&args.Options.PassiveStrict, "passive-strict", false, "Passive mode refusing all traffic toward the target and auditing the endpoints contacted")
 	enumFlags.BoolVar(&placeholder, "share", false, "Deprecated feature to be removed in version 4.0")
+	enumFlags.BoolVar(&args.Options.PortScans, "portscan", false, "Scan the resolved in-scope addresses for open TCP ports using connect scans")
+	enumFlags.BoolVar(&args.Options.Probe, "probe", false, "Probe the resolved names over HTTP and HTTPS")
+	enumFlags.BoolVar(&args.Options.Progress, "progress", false, "Periodically print the progress and estimated time remaining")
+	enumFlags.BoolVar(&args.Options.ScanCDNs, "scan-cdn", false, "Include addresses belonging to CDNs in port scans")
+	enumFlags.BoolVar(&args.Options.Screenshots, "screenshots", false, "Capture screenshots of the probed web pages using headless Chrome")
 	enumFlags.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
 	enumFlags.BoolVar(&args.Options.Sources, "src", false, "Print data sources for the discovered names")
+	enumFlags.BoolVar(&args.Options.Takeovers, "takeover", false, "Check CNAME targets for possible subdomain takeovers")
+	enumFlags.BoolVar(&args.Options.Tor, "tor", false, "Route the data source requests through the local Tor client")
 	enumFlags.BoolVar(&args.Options.Verbose, "v", false, "Output status / debug / troubleshooting info")
 }
 
@@ -147,16 +217,20 @@ func defineEnumFilepathFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.StringVar(&args.Filepaths.Blacklist, "blf", "", "Path to a file providing blacklisted subdomains")
 	enumFlags.Var(&args.Filepaths.BruteWordlist, "w", "Path to a different wordlist file for brute forcing")
 	enumFlags.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	enumFlags.StringVar(&args.Filepaths.CSVOutput, "csv", "", "Path to the CSV output file or '-'")
 	enumFlags.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
 	enumFlags.Var(&args.Filepaths.Domains, "df", "Path to a file providing root domain names")
 	enumFlags.StringVar(&args.Filepaths.ExcludedSrcs, "ef", "", "Path to a file providing data sources to exclude")
+	enumFlags.Var(&args.Filepaths.Imports, "import", "Path to the output file of another tool (subfinder, assetfinder or a plain list) seeding the enumeration")
 	enumFlags.StringVar(&args.Filepaths.IncludedSrcs, "if", "", "Path to a file providing data sources to include")
 	enumFlags.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file")
 	enumFlags.StringVar(&args.Filepaths.LogFile, "log", "", "Path to the log file where errors will be written")
+	enumFlags.StringVar(&args.Filepaths.NDJSONOutput, "ndjson", "", "Path to the NDJSON file appended as names are discovered, or '-'")
 	enumFlags.Var(&args.Filepaths.Names, "nf", "Path to a file providing already known subdomain names (from other tools/sources)")
 	enumFlags.Var(&args.Filepaths.Resolvers, "rf", "Path to a file providing untrusted DNS resolvers")
 	enumFlags.Var(&args.Filepaths.Trusted, "trf", "Path to a file providing trusted DNS resolvers")
 	enumFlags.StringVar(&args.Filepaths.ScriptsDirectory, "scripts", "", "Path to a directory containing ADS scripts")
+	enumFlags.StringVar(&args.Filepaths.PluginsDirectory, "plugins", "", "Path to a directory containing data source and output plugins")
 	enumFlags.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
 }
 
@@ -167,6 +241,10 @@ func runEnumCommand(clArgs []string) {
 		return
 	}
 	createOutputDirectory(cfg)
+	if args.Options.DryRun {
+		printEnumPlan(cfg)
+		return
+	}
 
 	rLog, wLog := io.Pipe()
 	dir := config.OutputDirectory(cfg.Dir)
@@ -177,7 +255,17 @@ func runEnumCommand(clArgs []string) {
 		logfile = args.Filepaths.LogFile
 	}
 	// Start handling the log messages
-	go writeLogsAndMessages(rLog, logfile, args.Options.Verbose)
+	logOut := newLogOutputs(cfg)
+	go writeLogsAndMessages(rLog, logfile, args.Options.Verbose, logOut)
+	// Serve the profiling endpoints for the troubleshooting of resource usage
+	pctx, pcancel := context.WithCancel(context.Background())
+	defer pcancel()
+	if err := startProfiling(pctx, args.Profiling, args.HeapThreshold, dir); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	// The strict passive mode must already refuse the connections made while the system starts
+	startEndpointAudit(cfg)
 	// Create the System that will provide architecture to this enumeration
 	sys, err := systems.NewLocalSystem(cfg)
 	if err != nil {
@@ -186,29 +274,81 @@ func runEnumCommand(clArgs []string) {
 	}
 	defer func() { _ = sys.Shutdown() }()
 
-	if err := sys.SetDataSources(datasrcs.GetAllSources(sys)); err != nil {
+	srcs := datasrcs.GetAllSources(sys)
+	logOut.setSources(srcs)
+	if err := sys.SetDataSources(srcs); err != nil {
 		r.Fprintf(color.Error, "%v\n", err)
 		os.Exit(1)
 	}
 	// Expand data source category names into the associated source names
 	initializeSourceTags(sys.DataSources())
-	cfg.SourceFilter.Sources = expandCategoryNames(cfg.SourceFilter.Sources, generateCategoryMap(sys))
+	cfg.SourceFilter.Sources = expandCategoryNames(cfg.SourceFilter.Sources, generateCategoryMap(cfg, sys.DataSources()))
+	if args.Options.Daemon {
+		runEnumDaemon(cfg, args, sys, logOut)
+		return
+	}
+	if err := runEnumeration(context.Background(), cfg, args, sys); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// newEnumSinks returns the external systems and the NDJSON file receiving the findings.
+func newEnumSinks(ctx context.Context, cfg *config.Config, args *enumArgs) ([]output.Sink, error) {
+	sinks, err := output.NewSinks(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if args.Filepaths.NDJSONOutput == "" {
+		return sinks, nil
+	}
+
+	nd, err := output.NewNDJSON(args.Filepaths.NDJSONOutput)
+	if err != nil {
+		cctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		for _, sink := range sinks {
+			_ = sink.Close(cctx)
+		}
+		return nil, err
+	}
+	return append(sinks, nd), nil
+}
+
+// runEnumeration performs a single enumeration and migrates the findings into the system graph databases.
+// The error returned is the reason the enumeration could not be performed.
+func runEnumeration(parent context.Context, cfg *config.Config, args *enumArgs, sys systems.System) error {
 	// Create the in-memory graph database used to store enumeration findings
 	graph := netmap.NewGraph(netmap.NewCayleyGraphMemory())
 	defer graph.Close()
 	// Setup the new enumeration
 	e := enum.NewEnumeration(cfg, sys, graph)
 	if e == nil {
-		r.Fprintf(color.Error, "%s\n", "Failed to setup the enumeration")
-		os.Exit(1)
+		return errors.New("failed to setup the enumeration")
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if args.Timeout == 0 {
+		ctx, cancel = context.WithCancel(parent)
+	} else {
+		ctx, cancel = context.WithTimeout(parent, time.Duration(args.Timeout)*time.Minute)
+	}
+	defer cancel()
+	// The outputs are setup before any goroutine is waiting on the findings
+	sinks, err := newEnumSinks(ctx, cfg, args)
+	if err != nil {
+		return err
 	}
 
 	var wg sync.WaitGroup
 	var outChans []chan *requests.Output
 	// This channel sends the signal for goroutines to terminate
 	done := make(chan struct{})
-	// Print output only if JSONOutput is not meant for STDOUT
-	if args.Filepaths.JSONOutput != "-" {
+	// Print output only if the JSON, CSV or NDJSON output is not meant for STDOUT, and the daemon only reports the changes
+	if !args.Options.Daemon && args.Filepaths.JSONOutput != "-" &&
+		args.Filepaths.CSVOutput != "-" && args.Filepaths.NDJSONOutput != "-" {
 		wg.Add(1)
 		// This goroutine will handle printing the output
 		printOutChan := make(chan *requests.Output, 10)
@@ -228,17 +368,32 @@ func runEnumCommand(clArgs []string) {
 	go saveJSONOutput(e, args, jsonOutChan, &wg)
 	outChans = append(outChans, jsonOutChan)
 
-	var ctx context.Context
-	var cancel context.CancelFunc
-	if args.Timeout == 0 {
-		ctx, cancel = context.WithCancel(context.Background())
-	} else {
-		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(args.Timeout)*time.Minute)
+	if args.Filepaths.CSVOutput != "" {
+		wg.Add(1)
+		// This goroutine will handle saving the output to the CSV file
+		csvOutChan := make(chan *requests.Output, 10)
+		go saveCSVOutput(e, args, csvOutChan, &wg)
+		outChans = append(outChans, csvOutChan)
 	}
-	defer cancel()
+
+	for _, sink := range sinks {
+		wg.Add(1)
+		// This goroutine will handle delivering the output to the external system
+		sinkOutChan := make(chan *requests.Output, 10)
+		go sendSinkOutput(e, sink, sinkOutChan, &wg)
+		outChans = append(outChans, sinkOutChan)
+	}
+
+	wg.Add(1)
+	// This goroutine will handle counting the names discovered by each data source, including those left out of the output
+	srcNames := newSourceNames()
+	srcOutChan := make(chan *requests.Output, 10)
+	go collectSourceNames(e, srcNames, srcOutChan, &wg)
 
 	wg.Add(1)
-	go processOutput(ctx, graph, e, outChans, done, &wg)
+	// The names are stamped with the times they were seen, including by the previous enumerations
+	seen := priorSightings(ctx, sys, cfg.Domains(), cfg.Workspace, cfg.UUID.String())
+	go processOutput(ctx, graph, e, seen, args.Selection, outChans, []chan *requests.Output{srcOutChan}, done, &wg)
 	// Monitor for cancellation by the user
 	go func(d chan struct{}, c context.Context, f context.CancelFunc) {
 		quit := make(chan os.Signal, 1)
@@ -252,15 +407,23 @@ func runEnumCommand(clArgs []string) {
 		case <-c.Done():
 		}
 	}(done, ctx, cancel)
-	// Start the enumeration process
-	if err := e.Start(ctx); err != nil {
-		r.Println(err)
-		os.Exit(1)
+	go handlePauseSignals(e, done)
+	if args.Options.Progress {
+		go printProgress(e, done)
 	}
+	// Start the enumeration process
+	err = e.Start(ctx)
 	// Let all the output goroutines know that the enumeration has finished
 	close(done)
 	wg.Wait()
+	if err != nil {
+		return err
+	}
 	fmt.Fprintf(color.Error, "\n%s\n", green("The enumeration has finished"))
+	contribs := sourceContributions(e.Config, e.SourceStats(), srcNames)
+	printSourceContributions(contribs)
+	saveSourceContributions(e, args, contribs)
+	printEndpointAudit(cfg, args)
 	// If necessary, handle graph database migration
 	if len(e.Sys.GraphDatabases()) > 0 {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
@@ -285,6 +448,7 @@ func runEnumCommand(clArgs []string) {
 			}
 		}
 	}
+	return nil
 }
 
 func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
@@ -296,7 +460,12 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		Blacklist:         stringset.New(),
 		Domains:           stringset.New(),
 		Excluded:          stringset.New(),
+		ExcludedTags:      stringset.New(),
+		ExcludedOutSrcs:   stringset.New(),
+		Imported:          stringset.New(),
 		Included:          stringset.New(),
+		IncludedTags:      stringset.New(),
+		IncludedOutSrcs:   stringset.New(),
 		Names:             stringset.New(),
 		Resolvers:         stringset.New(),
 		Trusted:           stringset.New(),
@@ -340,6 +509,16 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 	if args.Options.NoColor {
 		color.NoColor = true
 	}
+	sel, err := outputSelection(args.IncludedTags, args.ExcludedTags, args.IncludedOutSrcs, args.ExcludedOutSrcs, args.MinConfidence)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	args.Selection = sel
+	// The strict passive mode includes all the restrictions of the passive mode
+	if args.Options.PassiveStrict {
+		args.Options.Passive = true
+	}
 	if args.Options.Silent {
 		color.Output = io.Discard
 		color.Error = io.Discard
@@ -360,6 +539,18 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		fmt.Fprintf(color.Error, "%v\n", err)
 		os.Exit(1)
 	}
+	// Targets are piped into the command when none were provided by the arguments
+	targets := args.Domains.Len() + len(args.Addresses) + len(args.CIDRs) + len(args.ASNs)
+	if !args.Options.ListSources && stdinWanted(targets) {
+		targets, err := readStdinTargets(os.Stdin)
+		if err == nil {
+			err = targets.apply(args.Domains, &args.Addresses, &args.CIDRs, &args.ASNs)
+		}
+		if err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	cfg := config.NewConfig()
 	// Check if a configuration file was provided, and if so, load the settings
@@ -393,10 +584,40 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		r.Fprintln(color.Error, "Ports can only be scanned in the active mode")
 		os.Exit(1)
 	}
-	if len(cfg.Domains()) == 0 {
+	// The daemon mode can enumerate just the scopes scheduled in the configuration file
+	if len(cfg.Domains()) == 0 && !(args.Options.Daemon && len(cfg.Schedules) > 0) {
 		r.Fprintln(color.Error, "Configuration error: No root domain names were provided")
 		os.Exit(1)
 	}
+	if args.HeapThreshold < 0 {
+		r.Fprintln(color.Error, "The heap profile threshold cannot be negative")
+		os.Exit(1)
+	}
+	if args.BatchQPS < 0 {
+		r.Fprintln(color.Error, "The rate of the batch DNS sender cannot be negative")
+		os.Exit(1)
+	}
+	if cfg.BatchDNS && len(cfg.TrustedResolvers) == 0 {
+		r.Fprintln(color.Error, "The batch DNS sender requires the trusted resolvers to be provided")
+		os.Exit(1)
+	}
+	if args.Options.Daemon && args.Interval < 1 {
+		r.Fprintln(color.Error, "The interval of the daemon mode must be at least one minute")
+		os.Exit(1)
+	}
+	if args.Cron != "" {
+		if !args.Options.Daemon {
+			r.Fprintln(color.Error, "The cron expression can only be provided in the daemon mode")
+			os.Exit(1)
+		}
+
+		cron, err := config.ParseCron(args.Cron)
+		if err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+		args.CronSchedule = cron
+	}
 	return cfg, &args
 }
 
@@ -409,7 +630,7 @@ func printOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Outp
 	// Print all the output returned by the enumeration
 	for out := range output {
 		out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
-		if !e.Config.Passive && len(out.Addresses) <= 0 {
+		if !e.Config.Passive && len(out.Addresses) <= 0 && len(out.Findings) == 0 {
 			continue
 		}
 
@@ -425,6 +646,9 @@ func printOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Outp
 		}
 
 		fmt.Fprintf(color.Output, "%s%s%s\n", blue(source), green(name), yellow(ips))
+		for _, line := range format.FindingLines(out) {
+			fmt.Fprintln(color.Output, red(line))
+		}
 	}
 
 	if total == 0 {
@@ -464,7 +688,7 @@ func saveTextOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.O
 	// Save all the output returned by the enumeration
 	for out := range output {
 		out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
-		if !e.Config.Passive && len(out.Addresses) <= 0 {
+		if !e.Config.Passive && len(out.Addresses) <= 0 && len(out.Findings) == 0 {
 			continue
 		}
 
@@ -475,6 +699,9 @@ func saveTextOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.O
 		}
 		// Write the line to the output file
 		fmt.Fprintf(outptr, "%s%s%s\n", source, name, ips)
+		for _, line := range format.FindingLines(out) {
+			fmt.Fprintln(outptr, line)
+		}
 	}
 }
 
@@ -523,11 +750,74 @@ func saveJSONOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.O
 	}
 }
 
-func processOutput(ctx context.Context, g *netmap.Graph, e *enum.Enumeration, outputs []chan *requests.Output, done chan struct{}, wg *sync.WaitGroup) {
+func saveCSVOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Output, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	csvptr := os.Stdout
+	// Write to STDOUT and not a file if named "-"
+	if args.Filepaths.CSVOutput != "-" {
+		var err error
+
+		csvptr, err = os.Create(args.Filepaths.CSVOutput)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the CSV output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer csvptr.Close()
+	}
+
+	w := format.NewCSVWriter(csvptr, args.CSVColumns)
+	// Save all the output returned by the enumeration
+	for out := range output {
+		o := *out
+		o.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
+		if !e.Config.Passive && len(o.Addresses) <= 0 && len(o.Findings) == 0 {
+			continue
+		}
+		// The names never seen before are first seen by this enumeration when they are extracted
+		_ = w.Write(&o, time.Now())
+		if len(output) == 0 {
+			_ = w.Flush()
+		}
+	}
+	_ = w.Flush()
+}
+
+func sendSinkOutput(e *enum.Enumeration, sink output.Sink, out chan *requests.Output, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ctx := context.Background()
+	defer func() {
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		if err := sink.Close(ctx); err != nil {
+			e.Config.Log.Printf("The %s output: %v", sink, err)
+		}
+	}()
+
+	uuid := e.Config.UUID.String()
+	for o := range out {
+		if err := sink.Write(ctx, output.NewEvent(uuid, o)); err != nil {
+			e.Config.Log.Printf("The %s output: %v", sink, err)
+		}
+		// Deliver the buffered events while waiting on the next batch of output
+		if len(out) == 0 {
+			if err := sink.Flush(ctx); err != nil {
+				e.Config.Log.Printf("The %s output: %v", sink, err)
+			}
+		}
+	}
+}
+
+// processOutput delivers the discoveries to the outputs, which only receive the names kept by the selection,
+// while the unfiltered outputs receive all of them. The discoveries are stamped with the times they were seen.
+func processOutput(ctx context.Context, g *netmap.Graph, e *enum.Enumeration, seen *sightings, sel *filter.Selection,
+	outputs, unfiltered []chan *requests.Output, done chan struct{}, wg *sync.WaitGroup) {
 	defer wg.Done()
 	defer func() {
 		// Signal all the other output goroutines to terminate
-		for _, ch := range outputs {
+		for _, ch := range append(outputs, unfiltered...) {
 			close(ch)
 		}
 	}()
@@ -538,7 +828,22 @@ func processOutput(ctx context.Context, g *netmap.Graph, e *enum.Enumeration, ou
 	// The function that obtains output from the enum and puts it on the channel
 	extract := func(limit int) {
 		for _, o := range ExtractOutput(ctx, g, e, known, true, limit) {
-			if !o.Complete(e.Config.Passive) || !e.Config.IsDomainInScope(o.Name) {
+			// Names still being examined are extracted again once the results are available
+			if limit > 0 && e.InFlight(o.Name) {
+				known.Remove(o.Name)
+				continue
+			}
+			if !o.Complete(e.Config.Passive) || !e.Config.IsDomainInScope(o.Name) ||
+				e.Config.Blacklisted(o.Name) || excludedOutput(e.Config, o) {
+				continue
+			}
+			if seen != nil {
+				seen.stamp(o, time.Now())
+			}
+			for _, ch := range unfiltered {
+				ch <- o
+			}
+			if !sel.Match(o) {
 				continue
 			}
 			for _, ch := range outputs {
@@ -564,11 +869,63 @@ func processOutput(ctx context.Context, g *netmap.Graph, e *enum.Enumeration, ou
 	}
 }
 
-func writeLogsAndMessages(logs *io.PipeReader, logfile string, verbose bool) {
+// excludedOutput returns true when all the addresses of the output fall into the excluded address ranges.
+func excludedOutput(cfg *config.Config, o *requests.Output) bool {
+	if len(cfg.ExcludedCIDRs) == 0 || len(o.Addresses) == 0 {
+		return false
+	}
+
+	for _, a := range o.Addresses {
+		if !cfg.IsAddressExcluded(a.Address.String()) {
+			return false
+		}
+	}
+	return true
+}
+
+// logOutputs provides the destinations of the structured log entries.
+type logOutputs struct {
+	parser  *logging.Parser
+	json    bool
+	syslogs []*logging.Syslog
+}
+
+// newLogOutputs returns the logOutputs for the JSON log format and the syslog outputs in the
+// configuration, or nil when the log messages are only written as plain text.
+func newLogOutputs(cfg *config.Config) *logOutputs {
+	syslogs, err := output.NewSyslogLoggers(cfg)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+	}
+	if cfg.LogFormat != logging.FormatJSON && len(syslogs) == 0 {
+		return nil
+	}
+
+	return &logOutputs{
+		parser:  logging.NewParser(nil, cfg.Domains()),
+		json:    cfg.LogFormat == logging.FormatJSON,
+		syslogs: syslogs,
+	}
+}
+
+// setSources lets the parser recognize the messages of the data sources once they are known.
+func (lo *logOutputs) setSources(srcs []service.Service) {
+	if lo == nil {
+		return
+	}
+
+	for _, src := range srcs {
+		lo.parser.SetSources(src.String())
+	}
+}
+
+// writeLogsAndMessages writes the log messages to the file, and the structured entries to the log outputs.
+func writeLogsAndMessages(logs *io.PipeReader, logfile string, verbose bool, lo *logOutputs) {
 	wildcard := regexp.MustCompile("DNS wildcard")
 	queries := regexp.MustCompile("Querying")
 
 	var filePtr *os.File
+	var jsonw *logging.JSONWriter
 	if logfile != "" {
 		var err error
 
@@ -582,8 +939,18 @@ func writeLogsAndMessages(logs *io.PipeReader, logfile string, verbose bool) {
 			}()
 			_ = filePtr.Truncate(0)
 			_, _ = filePtr.Seek(0, 0)
+			if lo != nil && lo.json {
+				jsonw = logging.NewJSONWriter(filePtr, lo.parser)
+			}
 		}
 	}
+	if lo != nil {
+		defer func() {
+			for _, s := range lo.syslogs {
+				_ = s.Close()
+			}
+		}()
+	}
 
 	scanner := bufio.NewScanner(logs)
 	for scanner.Scan() {
@@ -593,12 +960,22 @@ func writeLogsAndMessages(logs *io.PipeReader, logfile string, verbose bool) {
 			break
 		}
 
-		if filePtr != nil {
+		if jsonw == nil && filePtr != nil {
 			fmt.Fprintln(filePtr, line)
 		}
 		// Remove the timestamp
 		parts := strings.Split(line, " ")
 		line = strings.Join(parts[1:], " ")
+		// The structured entries provide their own timestamps
+		if jsonw != nil {
+			_, _ = jsonw.Write([]byte(line))
+		}
+		if lo != nil && len(lo.syslogs) > 0 {
+			e := lo.parser.Parse(line, time.Now())
+			for _, s := range lo.syslogs {
+				_ = s.SendEntry(e)
+			}
+		}
 		// Check for Amass DNS wildcard messages
 		if verbose && wildcard.FindString(line) != "" {
 			fgR.Fprintln(color.Error, line)
@@ -669,6 +1046,15 @@ func processEnumInputFiles(args *enumArgs) error {
 			args.Domains.InsertMany(list...)
 		}
 	}
+	if len(args.Filepaths.Imports) > 0 {
+		for _, f := range args.Filepaths.Imports {
+			list, err := config.GetImportedNamesFromFile(f)
+			if err != nil {
+				return fmt.Errorf("failed to parse the imported names file: %v", err)
+			}
+			args.Imported.InsertMany(list...)
+		}
+	}
 	if len(args.Filepaths.Resolvers) > 0 {
 		for _, f := range args.Filepaths.Resolvers {
 			list, err := config.GetListFromFile(f)
@@ -678,6 +1064,15 @@ func processEnumInputFiles(args *enumArgs) error {
 			args.Resolvers.InsertMany(list...)
 		}
 	}
+	if len(args.Filepaths.Trusted) > 0 {
+		for _, f := range args.Filepaths.Trusted {
+			list, err := config.GetListFromFile(f)
+			if err != nil {
+				return fmt.Errorf("failed to parse the trusted resolver file: %v", err)
+			}
+			args.Trusted.InsertMany(list...)
+		}
+	}
 	return nil
 }
 
@@ -701,9 +1096,18 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.Filepaths.ScriptsDirectory != "" {
 		conf.ScriptsDirectory = e.Filepaths.ScriptsDirectory
 	}
+	if e.Filepaths.PluginsDirectory != "" {
+		conf.PluginsDirectory = e.Filepaths.PluginsDirectory
+	}
+	if e.Workspace != "" {
+		conf.Workspace = e.Workspace
+	}
 	if e.Names.Len() > 0 {
 		conf.ProvidedNames = e.Names.Slice()
 	}
+	if e.Imported.Len() > 0 {
+		conf.ImportedNames = e.Imported.Slice()
+	}
 	if e.BruteWordList.Len() > 0 {
 		conf.Wordlist = e.BruteWordList.Slice()
 	}
@@ -725,15 +1129,67 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.MaxDepth != 0 {
 		conf.MaxDepth = e.MaxDepth
 	}
+	if e.Options.Takeovers {
+		conf.Takeovers = true
+	}
+	if e.Options.Dangling {
+		conf.DanglingRecords = true
+	}
+	if e.Options.NewOnly {
+		conf.NewOnly = true
+	}
+	if e.Options.NoCloudRanges {
+		conf.CloudRanges = false
+	}
+	if e.Options.BatchDNS {
+		conf.BatchDNS = true
+	}
+	if e.BatchQPS > 0 {
+		conf.BatchQPS = e.BatchQPS
+	}
+	if e.Options.Buckets {
+		conf.Buckets = true
+	}
+	if e.Options.Tor {
+		conf.Tor = true
+	}
+	if e.Options.Probe {
+		conf.HTTPProbes = true
+	}
+	if e.Options.Screenshots {
+		conf.HTTPProbes = true
+		conf.Screenshots = true
+	}
+	if e.MaxScreenshots > 0 {
+		conf.ScreenshotConcurrency = e.MaxScreenshots
+	}
+	if e.Options.PortScans {
+		conf.PortScans = true
+	}
+	if e.Options.ScanCDNs {
+		conf.ScanCDNs = true
+	}
+	if e.TopPorts > 0 {
+		conf.ScanTopPorts = e.TopPorts
+	}
 	if e.Options.Active {
 		conf.Active = true
 		conf.Passive = false
 	}
+	if e.Options.PassiveStrict {
+		conf.StrictPassive = true
+	}
 	if e.Options.Passive {
 		conf.Passive = true
 		conf.Active = false
 		conf.BruteForcing = false
 		conf.Alterations = false
+		conf.Takeovers = false
+		conf.DanglingRecords = false
+		conf.Buckets = false
+		conf.HTTPProbes = false
+		conf.Screenshots = false
+		conf.PortScans = false
 	}
 	if e.Blacklist.Len() > 0 {
 		conf.Blacklist = e.Blacklist.Slice()
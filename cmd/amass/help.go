@@ -25,10 +25,20 @@ func runHelpCommand(clArgs []string) {
 		runEnumCommand(help)
 	case "intel":
 		runIntelCommand(help)
+	case "resolve":
+		runResolveCommand(help)
 	case "track":
 		runTrackCommand(help)
 	case "viz":
 		runVizCommand(help)
+	case "report":
+		runReportCommand(help)
+	case "serve":
+		runServeCommand(help)
+	case "datasrcs":
+		runDatasrcsCommand([]string{"check", "-help"})
+	case "config":
+		runConfigCommand([]string{"init", "-help"})
 	default:
 		commandUsage(mainUsageMsg, helpCommand, helpBuf)
 		return
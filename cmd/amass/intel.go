@@ -22,13 +22,14 @@ import (
 	"github.com/owasp-amass/amass/v3/datasrcs"
 	"github.com/owasp-amass/amass/v3/format"
 	"github.com/owasp-amass/amass/v3/intel"
+	amassnet "github.com/owasp-amass/amass/v3/net"
 	"github.com/owasp-amass/amass/v3/systems"
 	"github.com/caffix/stringset"
 	"github.com/fatih/color"
 )
 
 const (
-	intelUsageMsg = "intel [options] [-whois -d DOMAIN] [-addr ADDR -asn ASN -cidr CIDR]"
+	intelUsageMsg = "intel [options] [-whois -d DOMAIN] [-tld-expand|-typosquat -d DOMAIN] [-addr ADDR -asn ASN -cidr CIDR]"
 )
 
 type intelArgs struct {
@@ -42,7 +43,9 @@ type intelArgs struct {
 	MaxDNSQueries    int
 	Ports            format.ParseInts
 	Resolvers        *stringset.Set
+	ScanHosts        []*amassnet.ScanHost
 	Timeout          int
+	TLDs             format.ParseStrings
 	Options          struct {
 		Active       bool
 		DemoMode     bool
@@ -52,6 +55,8 @@ type intelArgs struct {
 		ListSources  bool
 		ReverseWhois bool
 		Sources      bool
+		TLDExpansion bool
+		Typosquats   bool
 		Verbose      bool
 	}
 	Filepaths struct {
@@ -61,6 +66,8 @@ type intelArgs struct {
 		ExcludedSrcs string
 		IncludedSrcs string
 		LogFile      string
+		Masscan      format.ParseStrings
+		Nmap         format.ParseStrings
 		Resolvers    format.ParseStrings
 		TermOut      string
 	}
@@ -78,6 +85,7 @@ func defineIntelArgumentFlags(intelFlags *flag.FlagSet, args *intelArgs) {
 	intelFlags.Var(&args.Ports, "p", "Ports separated by commas (default: 80, 443)")
 	intelFlags.Var(args.Resolvers, "r", "IP addresses of preferred DNS resolvers (can be used multiple times)")
 	intelFlags.IntVar(&args.Timeout, "timeout", 0, "Number of minutes to let enumeration run before quitting")
+	intelFlags.Var(&args.TLDs, "tlds", "Top-level domains separated by commas to check during TLD expansion")
 }
 
 func defineIntelOptionFlags(intelFlags *flag.FlagSet, args *intelArgs) {
@@ -89,6 +97,8 @@ func defineIntelOptionFlags(intelFlags *flag.FlagSet, args *intelArgs) {
 	intelFlags.BoolVar(&args.Options.ListSources, "list", false, "Print additional information")
 	intelFlags.BoolVar(&args.Options.ReverseWhois, "whois", false, "All provided domains are run through reverse whois")
 	intelFlags.BoolVar(&args.Options.Sources, "src", false, "Print data sources for the discovered names")
+	intelFlags.BoolVar(&args.Options.TLDExpansion, "tld-expand", false, "Check the provided domains across other TLDs and print the evidence")
+	intelFlags.BoolVar(&args.Options.Typosquats, "typosquat", false, "Print registered lookalike permutations of the provided domains")
 	intelFlags.BoolVar(&args.Options.Verbose, "v", false, "Output status / debug / troubleshooting info")
 }
 
@@ -99,6 +109,8 @@ func defineIntelFilepathFlags(intelFlags *flag.FlagSet, args *intelArgs) {
 	intelFlags.StringVar(&args.Filepaths.ExcludedSrcs, "ef", "", "Path to a file providing data sources to exclude")
 	intelFlags.StringVar(&args.Filepaths.IncludedSrcs, "if", "", "Path to a file providing data sources to include")
 	intelFlags.StringVar(&args.Filepaths.LogFile, "log", "", "Path to the log file where errors will be written")
+	intelFlags.Var(&args.Filepaths.Masscan, "masscan", "Path to a masscan JSON or list file providing live hosts and open ports")
+	intelFlags.Var(&args.Filepaths.Nmap, "nmap", "Path to an nmap XML file providing live hosts, names and open ports")
 	intelFlags.Var(&args.Filepaths.Resolvers, "rf", "Path to a file providing preferred DNS resolvers")
 	intelFlags.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
 }
@@ -143,6 +155,21 @@ func runIntelCommand(clArgs []string) {
 		fmt.Fprintf(color.Error, "%v\n", err)
 		os.Exit(1)
 	}
+	// Targets are piped into the command when none were provided by the arguments
+	targets := args.Domains.Len() + len(args.Addresses) + len(args.CIDRs) + len(args.ASNs)
+	if args.OrganizationName != "" {
+		targets++
+	}
+	if !args.Options.ListSources && stdinWanted(targets) {
+		targets, err := readStdinTargets(os.Stdin)
+		if err == nil {
+			err = targets.apply(args.Domains, &args.Addresses, &args.CIDRs, &args.ASNs)
+		}
+		if err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	cfg := config.NewConfig()
 	// Check if a configuration file was provided, and if so, load the settings
@@ -163,8 +190,9 @@ func runIntelCommand(clArgs []string) {
 	}
 
 	// Some input validation
-	if !args.Options.ReverseWhois && args.OrganizationName == "" && !args.Options.ListSources &&
-		len(args.Addresses) == 0 && len(args.CIDRs) == 0 && len(args.ASNs) == 0 {
+	if !args.Options.ReverseWhois && !args.Options.TLDExpansion && !args.Options.Typosquats &&
+		args.OrganizationName == "" && !args.Options.ListSources && len(args.Addresses) == 0 && len(args.CIDRs) == 0 &&
+		len(args.ASNs) == 0 && len(args.ScanHosts) == 0 {
 		commandUsage(intelUsageMsg, intelCommand, intelBuf)
 		os.Exit(1)
 	}
@@ -189,14 +217,17 @@ func runIntelCommand(clArgs []string) {
 	}
 
 	createOutputDirectory(cfg)
-	go writeLogsAndMessages(rLog, logfile, args.Options.Verbose)
+	logOut := newLogOutputs(cfg)
+	go writeLogsAndMessages(rLog, logfile, args.Options.Verbose, logOut)
 
 	sys, err := systems.NewLocalSystem(cfg)
 	if err != nil {
 		return
 	}
 
-	if err := sys.SetDataSources(datasrcs.GetAllSources(sys)); err != nil {
+	srcs := datasrcs.GetAllSources(sys)
+	logOut.setSources(srcs)
+	if err := sys.SetDataSources(srcs); err != nil {
 		return
 	}
 
@@ -232,6 +263,19 @@ func runIntelCommand(clArgs []string) {
 		args.Options.IPv4 = false
 		args.Options.IPv6 = false
 		go func() { _ = ic.ReverseWhois() }()
+	} else if args.Options.TLDExpansion || args.Options.Typosquats {
+		if len(ic.Config.Domains()) == 0 {
+			r.Fprintln(color.Error, "No root domain names were provided")
+			os.Exit(1)
+		}
+
+		// The evidence for each candidate is provided as the sources
+		args.Options.Sources = true
+		if args.Options.TLDExpansion {
+			go func() { _ = ic.TLDExpansion(context.Background()) }()
+		} else {
+			go func() { _ = ic.Typosquats(context.Background()) }()
+		}
 	} else {
 		var ctx context.Context
 		var cancel context.CancelFunc
@@ -253,6 +297,7 @@ func runIntelCommand(clArgs []string) {
 			}
 		}()
 
+		ic.ScanHosts = args.ScanHosts
 		go func() { _ = ic.HostedDomains(ctx) }()
 	}
 
@@ -357,9 +402,37 @@ func processIntelInputFiles(args *intelArgs) error {
 			args.Resolvers.InsertMany(list...)
 		}
 	}
+	for _, f := range args.Filepaths.Nmap {
+		hosts, err := readScanFile(f, amassnet.ParseNmapXML)
+		if err != nil {
+			return fmt.Errorf("failed to parse the nmap file: %v", err)
+		}
+
+		args.ScanHosts = append(args.ScanHosts, hosts...)
+	}
+	for _, f := range args.Filepaths.Masscan {
+		hosts, err := readScanFile(f, amassnet.ParseMasscan)
+		if err != nil {
+			return fmt.Errorf("failed to parse the masscan file: %v", err)
+		}
+
+		args.ScanHosts = append(args.ScanHosts, hosts...)
+	}
+	args.ScanHosts = amassnet.MergeScanHosts(args.ScanHosts)
 	return nil
 }
 
+// readScanFile returns the live hosts in the port scanner output file parsed by the function.
+func readScanFile(path string, parse func(io.Reader) ([]*amassnet.ScanHost, error)) ([]*amassnet.ScanHost, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parse(f)
+}
+
 // Setup the amass intelligence collection settings
 func (i intelArgs) OverrideConfig(conf *config.Config) error {
 	if i.Options.Active {
@@ -389,6 +462,10 @@ func (i intelArgs) OverrideConfig(conf *config.Config) error {
 	if i.MaxDNSQueries > 0 {
 		conf.MaxDNSQueries = i.MaxDNSQueries
 	}
+	if len(i.TLDs) > 0 {
+		conf.ExpansionTLDs = nil
+		conf.AddExpansionTLDs(i.TLDs...)
+	}
 
 	if i.Included.Len() > 0 {
 		conf.SourceFilter.Include = true
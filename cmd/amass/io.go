@@ -8,13 +8,17 @@ import (
 	"context"
 	"math/rand"
 	"net"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/owasp-amass/amass/v3/enum"
+	"github.com/owasp-amass/amass/v3/net/cloud"
 	"github.com/owasp-amass/amass/v3/requests"
 	"github.com/caffix/netmap"
 	"github.com/caffix/service"
 	"github.com/caffix/stringset"
+	"github.com/cayleygraph/quad"
 	"golang.org/x/net/publicsuffix"
 )
 
@@ -50,12 +54,28 @@ func EventOutput(ctx context.Context, g *netmap.Graph, uuid string, f *stringset
 	}
 	// Build the lookup map used to create the final result set
 	if pairs, err := g.NamesToAddrs(ctx, uuid, names...); err == nil {
+		details := make(map[string]*addrDetails)
+
 		for _, p := range pairs {
 			if p.Name == "" || p.Addr == "" {
 				continue
 			}
 			if o, found := lookup[p.Name]; found {
-				o.Addresses = append(o.Addresses, requests.AddressInfo{Address: net.ParseIP(p.Addr)})
+				d, found := details[p.Addr]
+				if !found {
+					d = readAddrDetails(ctx, g, p.Addr)
+					details[p.Addr] = d
+				}
+
+				o.Addresses = append(o.Addresses, requests.AddressInfo{
+					Address:  net.ParseIP(p.Addr),
+					Provider: d.provider.Provider,
+					Service:  d.provider.Service,
+					Region:   d.provider.Region,
+					Ports:    d.ports,
+					CPEs:     d.cpes,
+				})
+				o.Findings = append(o.Findings, d.findings...)
 			}
 		}
 	}
@@ -114,11 +134,15 @@ func addInfrastructureInfo(lookup outLookup, filter *stringset.Set, cache *reque
 				CIDRStr:     i.Prefix,
 				Netblock:    netblock,
 				Description: i.Description,
+				Provider:    a.Provider,
+				Service:     a.Service,
+				Region:      a.Region,
+				Ports:       a.Ports,
 			})
 		}
 
 		o.Addresses = newaddrs
-		if len(o.Addresses) > 0 && !filter.Has(o.Name) {
+		if (len(o.Addresses) > 0 || len(o.Findings) > 0) && !filter.Has(o.Name) {
 			output = append(output, o)
 			filter.Insert(o.Name)
 		}
@@ -178,11 +202,104 @@ func buildNameInfo(ctx context.Context, g *netmap.Graph, uuid string, names []st
 		o.Domain = d
 
 		o.Tag = selectTag(o.Sources)
+		o.Findings = readFindings(ctx, g, o.Name)
+		o.HTTP = readHTTPInfo(ctx, g, o.Name)
 		final = append(final, o)
 	}
 	return final
 }
 
+type addrDetails struct {
+	provider cloud.Range
+	findings []*requests.Finding
+	ports    []requests.PortInfo
+	cpes     []string
+}
+
+func readAddrDetails(ctx context.Context, g *netmap.Graph, addr string) *addrDetails {
+	d := &addrDetails{
+		findings: readFindings(ctx, g, addr),
+		ports:    readPorts(ctx, g, addr),
+		cpes:     propertyValues(ctx, g, addr, requests.CPEPredicate),
+	}
+	sort.Strings(d.cpes)
+
+	if values := propertyValues(ctx, g, addr, cloud.Predicate); len(values) > 0 {
+		if rng, ok := cloud.ParseRange(values[0]); ok {
+			d.provider = *rng
+		}
+	}
+	return d
+}
+
+func readPorts(ctx context.Context, g *netmap.Graph, addr string) []requests.PortInfo {
+	edges, err := g.ReadOutEdges(ctx, netmap.Node(addr), requests.PortPredicate)
+	if err != nil {
+		return nil
+	}
+
+	var ports []requests.PortInfo
+	for _, e := range edges {
+		_, p, err := net.SplitHostPort(g.NodeToID(e.To))
+		if err != nil {
+			continue
+		}
+		port, err := strconv.Atoi(p)
+		if err != nil {
+			continue
+		}
+
+		info := requests.PortInfo{Port: port, Protocol: "tcp"}
+		if banners := propertyValues(ctx, g, g.NodeToID(e.To), requests.BannerPredicate); len(banners) > 0 {
+			info.Banner = banners[0]
+		}
+		ports = append(ports, info)
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Port < ports[j].Port
+	})
+	return ports
+}
+
+func readFindings(ctx context.Context, g *netmap.Graph, name string) []*requests.Finding {
+	var findings []*requests.Finding
+
+	for _, v := range propertyValues(ctx, g, name, requests.FindingPredicate) {
+		if f, ok := requests.ParseFinding(v); ok {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+func readHTTPInfo(ctx context.Context, g *netmap.Graph, name string) []*requests.HTTPInfo {
+	var results []*requests.HTTPInfo
+
+	for _, v := range propertyValues(ctx, g, name, requests.HTTPPredicate) {
+		if h, ok := requests.ParseHTTPInfo(v); ok {
+			results = append(results, h)
+		}
+	}
+	return results
+}
+
+// propertyValues returns the string values of the node properties matching the predicate.
+func propertyValues(ctx context.Context, g *netmap.Graph, node, predicate string) []string {
+	props, err := g.ReadProperties(ctx, netmap.Node(node), predicate)
+	if err != nil {
+		return nil
+	}
+
+	var values []string
+	for _, p := range props {
+		if s, ok := quad.NativeOf(p.Value).(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
 func initializeSourceTags(srcs []service.Service) {
 	sourceTags["DNS"] = requests.DNS
 	sourceTags["Reverse DNS"] = requests.DNS
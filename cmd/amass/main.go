@@ -49,7 +49,7 @@ import (
 )
 
 const (
-	mainUsageMsg         = "intel|enum|viz|track|db [options]"
+	mainUsageMsg         = "intel|enum|resolve|viz|track|db|report|serve|selftest|datasrcs|config [options]"
 	exampleConfigFileURL = "https://github.com/owasp-amass/amass/blob/master/examples/config.ini"
 	userGuideURL         = "https://github.com/owasp-amass/amass/blob/master/doc/user_guide.md"
 	tutorialURL          = "https://github.com/owasp-amass/amass/blob/master/doc/tutorial.md"
@@ -57,15 +57,16 @@ const (
 
 var (
 	// Colors used to ease the reading of program output
-	g      = color.New(color.FgHiGreen)
-	r      = color.New(color.FgHiRed)
-	b      = color.New(color.FgHiBlue)
-	fgR    = color.New(color.FgRed)
-	fgY    = color.New(color.FgYellow)
-	red    = color.New(color.FgHiRed).SprintFunc()
-	yellow = color.New(color.FgHiYellow).SprintFunc()
-	green  = color.New(color.FgHiGreen).SprintFunc()
-	blue   = color.New(color.FgHiBlue).SprintFunc()
+	g       = color.New(color.FgHiGreen)
+	r       = color.New(color.FgHiRed)
+	b       = color.New(color.FgHiBlue)
+	fgR     = color.New(color.FgRed)
+	fgY     = color.New(color.FgYellow)
+	red     = color.New(color.FgHiRed).SprintFunc()
+	yellow  = color.New(color.FgHiYellow).SprintFunc()
+	green   = color.New(color.FgHiGreen).SprintFunc()
+	blue    = color.New(color.FgHiBlue).SprintFunc()
+	magenta = color.New(color.FgHiMagenta).SprintFunc()
 )
 
 func commandUsage(msg string, cmdFlagSet *flag.FlagSet, errBuf *bytes.Buffer) {
@@ -78,9 +79,15 @@ func commandUsage(msg string, cmdFlagSet *flag.FlagSet, errBuf *bytes.Buffer) {
 		g.Fprintf(color.Error, "\nSubcommands: \n\n")
 		g.Fprintf(color.Error, "\t%-11s - Discover targets for enumerations\n", "amass intel")
 		g.Fprintf(color.Error, "\t%-11s - Perform enumerations and network mapping\n", "amass enum")
+		g.Fprintf(color.Error, "\t%-11s - Resolve and validate the names provided on stdin\n", "amass resolve")
 		g.Fprintf(color.Error, "\t%-11s - Visualize enumeration results\n", "amass viz")
 		g.Fprintf(color.Error, "\t%-11s - Track differences between enumerations\n", "amass track")
 		g.Fprintf(color.Error, "\t%-11s - Manipulate the Amass graph database\n", "amass db")
+		g.Fprintf(color.Error, "\t%-11s - Generate an HTML report from the graph database\n", "amass report")
+		g.Fprintf(color.Error, "\t%-11s - Serve enumeration jobs and the graph database over HTTP\n", "amass serve")
+		g.Fprintf(color.Error, "\t%-11s - Measure the resolvers, data sources and graph database\n", "amass selftest")
+		g.Fprintf(color.Error, "\t%-11s - Check the data sources and test their scripts with fixtures\n", "amass datasrcs")
+		g.Fprintf(color.Error, "\t%-11s - Write a configuration file by answering prompts\n", "amass config")
 	}
 
 	g.Fprintln(color.Error)
@@ -124,10 +131,22 @@ func main() {
 		runEnumCommand(os.Args[2:])
 	case "intel":
 		runIntelCommand(os.Args[2:])
+	case "resolve":
+		runResolveCommand(os.Args[2:])
 	case "track":
 		runTrackCommand(os.Args[2:])
 	case "viz":
 		runVizCommand(os.Args[2:])
+	case "report":
+		runReportCommand(os.Args[2:])
+	case "serve":
+		runServeCommand(os.Args[2:])
+	case "selftest":
+		runSelftestCommand(os.Args[2:])
+	case "datasrcs":
+		runDatasrcsCommand(os.Args[2:])
+	case "config":
+		runConfigCommand(os.Args[2:])
 	case "help":
 		runHelpCommand(os.Args[2:])
 	default:
@@ -198,13 +217,18 @@ func createOutputDirectory(cfg *config.Config) {
 	}
 }
 
-func generateCategoryMap(sys systems.System) map[string][]string {
+// generateCategoryMap returns the data source names for each category and trust tier, which can be
+// used in place of the data source names to include or exclude.
+func generateCategoryMap(cfg *config.Config, srcs []service.Service) map[string][]string {
 	catToSources := make(map[string][]string)
 
-	for _, src := range sys.DataSources() {
+	for _, src := range srcs {
 		t := src.Description()
 
 		catToSources[t] = append(catToSources[t], src.String())
+		for _, tier := range datasrcs.SourceTiers(cfg, src) {
+			catToSources[tier] = append(catToSources[tier], src.String())
+		}
 	}
 
 	return catToSources
@@ -231,8 +255,9 @@ func openGraphDatabase(dir string, cfg *config.Config) *netmap.Graph {
 			continue
 		}
 
-		cayley := netmap.NewCayleyGraph(db.System, db.URL, db.Options)
-		if cayley == nil {
+		cayley, err := systems.NewCayleyGraph(db)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the %s graph database: %v\n", db.System, err)
 			return nil
 		}
 
@@ -260,20 +285,25 @@ func openGraphDatabase(dir string, cfg *config.Config) *netmap.Graph {
 	return nil
 }
 
-func memGraphForScope(ctx context.Context, domains []string, from *netmap.Graph) (*netmap.Graph, error) {
+func memGraphForScope(ctx context.Context, domains []string, workspace string, from *netmap.Graph) (*netmap.Graph, error) {
 	db := netmap.NewGraph(netmap.NewCayleyGraphMemory())
 	if db == nil {
 		return nil, errors.New("failed to create the in-memory graph database")
 	}
 
-	var err error
-	// Migrate the event data into the in-memory graph database
+	var events []string
 	if len(domains) == 0 {
-		err = from.MigrateEvents(ctx, db)
+		events = from.EventList(ctx)
 	} else {
-		err = from.MigrateEventsInScope(ctx, db, domains)
+		events = from.EventsInScope(ctx, domains...)
 	}
-	if err != nil {
+	// Only the events of the selected workspace are brought into the in-memory graph database
+	events = systems.WorkspaceEvents(ctx, from, workspace, events)
+	if len(events) == 0 {
+		return db, nil
+	}
+	// Migrate the event data into the in-memory graph database
+	if err := from.MigrateEvents(ctx, db, events...); err != nil {
 		return nil, fmt.Errorf("failed to move the data into the in-memory graph database: %v", err)
 	}
 	return db, nil
@@ -0,0 +1,41 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/enum"
+)
+
+// handlePauseSignals pauses the enumeration after receiving the pause signal and resumes it
+// after receiving the resume signal, until the done channel is closed.
+func handlePauseSignals(e *enum.Enumeration, done chan struct{}) {
+	if pauseSignal == nil || resumeSignal == nil {
+		return
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, pauseSignal, resumeSignal)
+	defer signal.Stop(sigs)
+
+	for {
+		select {
+		case <-done:
+			return
+		case sig := <-sigs:
+			if sig == pauseSignal && !e.Paused() {
+				e.Pause()
+				fmt.Fprintf(color.Error, "%s\n", yellow("The enumeration was paused, send SIGUSR2 to resume it"))
+			} else if sig == resumeSignal && e.Paused() {
+				e.Resume()
+				fmt.Fprintf(color.Error, "%s\n", yellow("The enumeration was resumed"))
+			}
+		}
+	}
+}
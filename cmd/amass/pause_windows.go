@@ -0,0 +1,13 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import "os"
+
+// Windows does not provide the signals pausing and resuming the enumeration
+var (
+	pauseSignal  os.Signal
+	resumeSignal os.Signal
+)
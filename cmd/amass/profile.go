@@ -0,0 +1,102 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// The frequency of the heap checks performed for the heap profile threshold
+const heapCheckInterval = 10 * time.Second
+
+// startProfiling serves the net/http/pprof endpoints at the address, when provided, and writes a heap
+// profile to the directory each time the heap in use grows past another multiple of the threshold.
+func startProfiling(ctx context.Context, addr string, thresholdMB int, dir string) error {
+	if addr != "" {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed to serve the profiling endpoints: %v", err)
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+		srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() { _ = srv.Serve(ln) }()
+		go func() {
+			<-ctx.Done()
+			_ = srv.Close()
+		}()
+		fmt.Fprintf(color.Error, "%s%s\n", yellow("The profiling endpoints are served at http://"),
+			yellow(ln.Addr().String()+"/debug/pprof/"))
+	}
+
+	if thresholdMB > 0 {
+		go watchHeap(ctx, uint64(thresholdMB)<<20, dir)
+	}
+	return nil
+}
+
+func watchHeap(ctx context.Context, threshold uint64, dir string) {
+	t := time.NewTicker(heapCheckInterval)
+	defer t.Stop()
+
+	next := threshold
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if stats.HeapInuse < next {
+			continue
+		}
+		// Another profile is only written once the heap grows by the threshold again
+		for next <= stats.HeapInuse {
+			next += threshold
+		}
+
+		path, err := writeHeapProfile(dir)
+		if err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			continue
+		}
+		fmt.Fprintf(color.Error, "%s%s%s%s\n", yellow("The heap in use reached "),
+			yellow(fmt.Sprintf("%d MB", stats.HeapInuse>>20)), yellow(", writing the profile to "), yellow(path))
+	}
+}
+
+func writeHeapProfile(dir string) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("heap-%s.pprof", time.Now().Format("20060102-150405")))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the heap profile: %v", err)
+	}
+	defer f.Close()
+
+	if err := rpprof.WriteHeapProfile(f); err != nil {
+		return "", fmt.Errorf("failed to write the heap profile: %v", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,50 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/enum"
+)
+
+const progressInterval = 30 * time.Second
+
+// printProgress writes the progress of the enumeration to standard error at regular intervals,
+// until the done channel is closed.
+func printProgress(e *enum.Enumeration, done chan struct{}) {
+	t := time.NewTicker(progressInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			// The estimates are meaningless while the work is held
+			if !e.Paused() {
+				fmt.Fprintln(color.Error, progressLine(e.Progress()))
+			}
+		}
+	}
+}
+
+func progressLine(p *enum.Progress) string {
+	eta := "unknown"
+	if d, ok := p.ETA(); ok {
+		eta = d.Round(time.Second).String()
+	}
+
+	line := fmt.Sprintf("%s %s %s %s %s %s", green("Progress:"), yellow(fmt.Sprintf("%.1f%%", p.Percent())),
+		green("Elapsed:"), yellow(p.Elapsed.Round(time.Second).String()), green("ETA:"), yellow(eta))
+	line += fmt.Sprintf(" %s %s %s %s", green("Names:"), yellow(fmt.Sprintf("%d/%d", p.NamesDone, p.Names)),
+		green("Data source requests:"), yellow(fmt.Sprintf("%d/%d", p.SourceRequestsDone, p.SourceRequests)))
+	if p.GuessesRemaining > 0 {
+		line += fmt.Sprintf(" %s %s", green("Guesses planned:"), yellow(p.GuessesRemaining))
+	}
+	return line
+}
@@ -0,0 +1,130 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/prune"
+)
+
+const (
+	pruneUsageMsg = "db prune -days N [options]"
+)
+
+type pruneArgs struct {
+	Days    int
+	Options struct {
+		Delete  bool
+		NoColor bool
+		Silent  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+		Output     string
+	}
+}
+
+func runDBPruneCommand(clArgs []string) {
+	var args pruneArgs
+	var help1, help2 bool
+	pruneCommand := flag.NewFlagSet("prune", flag.ContinueOnError)
+
+	pruneBuf := new(bytes.Buffer)
+	pruneCommand.SetOutput(pruneBuf)
+
+	pruneCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	pruneCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	pruneCommand.IntVar(&args.Days, "days", 0, "Assets not seen by an enumeration within the number of days are stale")
+	pruneCommand.BoolVar(&args.Options.Delete, "delete", false, "Remove the stale assets and their edges from the graph database")
+	pruneCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	pruneCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	pruneCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	pruneCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	pruneCommand.StringVar(&args.Filepaths.Output, "o", "-", "Path to the JSON output file or '-'")
+
+	if err := pruneCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(pruneUsageMsg, pruneCommand, pruneBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Error = io.Discard
+	}
+	if args.Days < 1 {
+		r.Fprintln(color.Error, "The days flag must provide a positive number of days")
+		os.Exit(1)
+	}
+
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+
+	db := openGraphDatabase(args.Filepaths.Directory, cfg)
+	if db == nil {
+		r.Fprintln(color.Error, "Failed to connect with the database")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	before := time.Now().AddDate(0, 0, -args.Days)
+	stale, err := prune.Stale(context.Background(), db, before)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to obtain the stale assets: %v\n", err)
+		os.Exit(1)
+	}
+	if stale == nil {
+		stale = []*prune.Asset{}
+	}
+
+	out := os.Stdout
+	if args.Filepaths.Output != "-" {
+		out, err = os.Create(args.Filepaths.Output)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the JSON output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(stale); err != nil {
+		r.Fprintf(color.Error, "Failed to write the stale assets: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !args.Options.Delete {
+		g.Fprintf(color.Error, "%d assets were not seen within %d days\n", len(stale), args.Days)
+		return
+	}
+	if err := prune.Remove(context.Background(), db, stale); err != nil {
+		r.Fprintf(color.Error, "Failed to remove the stale assets: %v\n", err)
+		os.Exit(1)
+	}
+	g.Fprintf(color.Error, "%d assets not seen within %d days were removed\n", len(stale), args.Days)
+}
@@ -0,0 +1,165 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/report"
+)
+
+const (
+	reportUsageMsg      = "report [options] -d DOMAIN"
+	defaultReportOutput = "amass_report.html"
+)
+
+type reportArgs struct {
+	Domains   *stringset.Set
+	Enum      int
+	Workspace string
+	Options   struct {
+		NoColor bool
+		Silent  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+		Domains    string
+		Output     string
+	}
+}
+
+func runReportCommand(clArgs []string) {
+	var args reportArgs
+	var help1, help2 bool
+	reportCommand := flag.NewFlagSet("report", flag.ContinueOnError)
+
+	args.Domains = stringset.New()
+	defer args.Domains.Close()
+
+	reportBuf := new(bytes.Buffer)
+	reportCommand.SetOutput(reportBuf)
+
+	reportCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	reportCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	reportCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	reportCommand.IntVar(&args.Enum, "enum", 1, "Identify an enumeration via an index from the db listing")
+	reportCommand.StringVar(&args.Workspace, "workspace", "", "Name of the workspace isolating the enumerations in the graph database")
+	reportCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	reportCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	reportCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	reportCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	reportCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	reportCommand.StringVar(&args.Filepaths.Output, "o", "", "Path to the HTML report file (default: amass_report.html in the output directory)")
+
+	if err := reportCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(reportUsageMsg, reportCommand, reportBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = io.Discard
+		color.Error = io.Discard
+	}
+	if args.Enum < 1 {
+		r.Fprintln(color.Error, "The enum flag must provide an index from the listing")
+		os.Exit(1)
+	}
+	if args.Filepaths.Domains != "" {
+		list, err := config.GetListFromFile(args.Filepaths.Domains)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the domain names file: %v\n", err)
+			os.Exit(1)
+		}
+		args.Domains.InsertMany(list...)
+	}
+
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+		if args.Workspace == "" {
+			args.Workspace = cfg.Workspace
+		}
+		if args.Domains.Len() == 0 {
+			args.Domains.InsertMany(cfg.Domains()...)
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+
+	db := openGraphDatabase(args.Filepaths.Directory, cfg)
+	if db == nil {
+		r.Fprintln(color.Error, "Failed to connect with the database")
+		os.Exit(1)
+	}
+	defer db.Close()
+	// Create the in-memory graph database for events that have information in scope
+	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), args.Workspace, db)
+	if err != nil {
+		r.Fprintln(color.Error, err.Error())
+		os.Exit(1)
+	}
+	defer memDB.Close()
+	// Put the events in chronological order, as shown by the listing
+	uuids := memDB.EventsInScope(context.Background(), args.Domains.Slice()...)
+	if len(uuids) == 0 {
+		r.Fprintln(color.Error, "Failed to find the domains of interest in the database")
+		os.Exit(1)
+	}
+	uuids, _, _ = orderedEvents(context.Background(), uuids, memDB)
+	if args.Enum > len(uuids) {
+		r.Fprintf(color.Error, "%d enumerations are available in the listing\n", len(uuids))
+		os.Exit(1)
+	}
+
+	idx := len(uuids) - args.Enum
+	var prev string
+	if idx > 0 {
+		prev = uuids[idx-1]
+	}
+
+	assets := getScopedOutput([]string{uuids[idx]}, args.Domains.Slice(), false, memDB, nil)
+	rep, err := report.New(context.Background(), memDB, uuids[idx], prev, args.Domains.Slice(), assets)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to build the report: %v\n", err)
+		os.Exit(1)
+	}
+
+	path := args.Filepaths.Output
+	if path == "" {
+		path = filepath.Join(config.OutputDirectory(args.Filepaths.Directory), defaultReportOutput)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to open the report file: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	if err := rep.WriteHTML(f); err != nil {
+		r.Fprintf(color.Error, "Failed to write the report: %v\n", err)
+		os.Exit(1)
+	}
+	g.Fprintf(color.Error, "The report was written to %s\n", path)
+}
@@ -0,0 +1,376 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/enum"
+	"github.com/owasp-amass/amass/v3/format"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+	"github.com/owasp-amass/resolve"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	resolveUsageMsg = "resolve [options] < names.txt"
+	// The maximum number of names resolved at the same time
+	maxResolveWorkers = 1000
+	// The number of times a query is sent before the name is considered unresolved
+	resolveAttempts = 3
+)
+
+type resolveArgs struct {
+	Domains       *stringset.Set
+	MaxDNSQueries int
+	ResolverQPS   int
+	Resolvers     *stringset.Set
+	Trusted       *stringset.Set
+	TrustedQPS    int
+	Options       struct {
+		JSON        bool
+		NoColor     bool
+		TrustedOnly bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+		Output     string
+		Resolvers  format.ParseStrings
+		Trusted    format.ParseStrings
+	}
+}
+
+// resolvedName is the result written for each name that passed the validation.
+type resolvedName struct {
+	Name    string               `json:"name"`
+	Domain  string               `json:"domain"`
+	Records []requests.DNSAnswer `json:"records"`
+}
+
+func runResolveCommand(clArgs []string) {
+	args := resolveArgs{
+		Domains:   stringset.New(),
+		Resolvers: stringset.New(),
+		Trusted:   stringset.New(),
+	}
+	var help1, help2 bool
+	resolveCommand := flag.NewFlagSet("resolve", flag.ContinueOnError)
+
+	resolveBuf := new(bytes.Buffer)
+	resolveCommand.SetOutput(resolveBuf)
+
+	resolveCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	resolveCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	resolveCommand.Var(args.Domains, "d", "Root domain names the names must belong to (can be used multiple times)")
+	resolveCommand.IntVar(&args.MaxDNSQueries, "max-dns-queries", 0, "Maximum number of concurrent DNS queries")
+	resolveCommand.Var(args.Resolvers, "r", "IP addresses of untrusted DNS resolvers (can be used multiple times)")
+	resolveCommand.IntVar(&args.ResolverQPS, "rqps", 0, "Maximum number of DNS queries per second for each untrusted resolver")
+	resolveCommand.Var(args.Trusted, "tr", "IP addresses of trusted DNS resolvers (can be used multiple times)")
+	resolveCommand.IntVar(&args.TrustedQPS, "trqps", 0, "Maximum number of DNS queries per second for each trusted resolver")
+	resolveCommand.BoolVar(&args.Options.JSON, "json", false, "Write the results as JSON lines, including the DNS records")
+	resolveCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	resolveCommand.BoolVar(&args.Options.TrustedOnly, "trusted-only", false, "Send the queries to the trusted resolvers only")
+	resolveCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	resolveCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+	resolveCommand.StringVar(&args.Filepaths.Output, "o", "", "Path to the file the results are written to (default: stdout)")
+	resolveCommand.Var(&args.Filepaths.Resolvers, "rf", "Path to a file providing untrusted DNS resolvers")
+	resolveCommand.Var(&args.Filepaths.Trusted, "trf", "Path to a file providing trusted DNS resolvers")
+
+	if err := resolveCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(resolveUsageMsg, resolveCommand, resolveBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if !stdinProvided() {
+		r.Fprintln(color.Error, "The names to resolve must be provided on the standard input")
+		commandUsage(resolveUsageMsg, resolveCommand, resolveBuf)
+		os.Exit(1)
+	}
+
+	cfg, err := resolveConfig(&args)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	pool, trusted, err := systems.NewResolverPools(cfg, args.Options.TrustedOnly)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if pool != trusted {
+			pool.Stop()
+		}
+		trusted.Stop()
+	}()
+
+	out := io.Writer(os.Stdout)
+	if args.Filepaths.Output != "" {
+		f, err := os.OpenFile(args.Filepaths.Output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer func() {
+			_ = f.Sync()
+			_ = f.Close()
+		}()
+		out = f
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Monitor for cancellation by the user
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(quit)
+
+		select {
+		case <-quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	rv := &resolver{cfg: cfg, pool: pool, trusted: trusted}
+	if err := rv.run(ctx, os.Stdin, out, args.Options.JSON); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func resolveConfig(args *resolveArgs) (*config.Config, error) {
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err != nil && args.Filepaths.ConfigFile != "" {
+		return nil, fmt.Errorf("failed to load the configuration file: %v", err)
+	}
+
+	for _, set := range []struct {
+		files format.ParseStrings
+		addrs *stringset.Set
+	}{
+		{args.Filepaths.Resolvers, args.Resolvers},
+		{args.Filepaths.Trusted, args.Trusted},
+	} {
+		for _, f := range set.files {
+			list, err := config.GetListFromFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the resolver file: %v", err)
+			}
+			set.addrs.InsertMany(list...)
+		}
+	}
+
+	if args.Resolvers.Len() > 0 {
+		cfg.SetResolvers(args.Resolvers.Slice()...)
+	}
+	if args.Trusted.Len() > 0 {
+		cfg.SetTrustedResolvers(args.Trusted.Slice()...)
+	}
+	if args.MaxDNSQueries > 0 {
+		cfg.MaxDNSQueries = args.MaxDNSQueries
+	}
+	if args.ResolverQPS > 0 {
+		cfg.ResolversQPS = args.ResolverQPS
+	}
+	if args.TrustedQPS > 0 {
+		cfg.TrustedQPS = args.TrustedQPS
+	}
+	for _, d := range args.Domains.Slice() {
+		cfg.AddDomain(d)
+	}
+	return cfg, nil
+}
+
+// resolver validates the names with the untrusted resolvers, before the answers are confirmed by the
+// trusted resolvers and checked for DNS wildcards, as performed during the enumerations.
+type resolver struct {
+	cfg     *config.Config
+	pool    *resolve.Resolvers
+	trusted *resolve.Resolvers
+}
+
+func (rv *resolver) run(ctx context.Context, in io.Reader, out io.Writer, jsonOut bool) error {
+	workers := rv.cfg.MaxDNSQueries
+	if workers <= 0 || workers > maxResolveWorkers {
+		workers = maxResolveWorkers
+	}
+
+	names := make(chan string, workers)
+	results := make(chan *resolvedName, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for name := range names {
+				if res := rv.resolveName(ctx, name); res != nil {
+					results <- res
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		w := bufio.NewWriter(out)
+		defer w.Flush()
+		enc := json.NewEncoder(w)
+		for res := range results {
+			if jsonOut {
+				_ = enc.Encode(res)
+				continue
+			}
+
+			var addrs []string
+			for _, rec := range res.Records {
+				if t := uint16(rec.Type); t == dns.TypeA || t == dns.TypeAAAA {
+					addrs = append(addrs, rec.Data)
+				}
+			}
+			if len(addrs) > 0 {
+				fmt.Fprintf(w, "%s %s\n", res.Name, strings.Join(addrs, ","))
+			} else {
+				fmt.Fprintln(w, res.Name)
+			}
+		}
+	}()
+
+	filter := stringset.New()
+	defer filter.Close()
+
+	scanner := bufio.NewScanner(in)
+	var err error
+loop:
+	for scanner.Scan() {
+		name := strings.Trim(strings.ToLower(strings.TrimSpace(scanner.Text())), ".")
+		if name == "" || strings.HasPrefix(name, "#") || filter.Has(name) {
+			continue
+		}
+		filter.Insert(name)
+
+		select {
+		case <-ctx.Done():
+			break loop
+		case names <- name:
+		}
+	}
+	if serr := scanner.Err(); serr != nil {
+		err = fmt.Errorf("failed to read the names: %v", serr)
+	}
+
+	close(names)
+	wg.Wait()
+	close(results)
+	<-done
+	return err
+}
+
+// resolveName returns the records of the name, or nil when it does not resolve, is out of scope,
+// or the answers were produced by a DNS wildcard.
+func (rv *resolver) resolveName(ctx context.Context, name string) *resolvedName {
+	domain := rv.domain(name)
+	if domain == "" || rv.cfg.Blacklisted(name) {
+		return nil
+	}
+
+	res := &resolvedName{Name: name, Domain: domain}
+	for _, qtype := range enum.FwdQueryTypes {
+		resp := resolveQuery(ctx, rv.pool, name, qtype)
+		if resp == nil {
+			continue
+		}
+		// The answers of the untrusted resolvers are confirmed by the trusted resolvers
+		if rv.pool != rv.trusted {
+			if resp = resolveQuery(ctx, rv.trusted, name, qtype); resp == nil {
+				continue
+			}
+		}
+		if rv.trusted.WildcardDetected(ctx, resp, domain) {
+			return nil
+		}
+
+		for _, a := range resolve.AnswersByType(resolve.ExtractAnswers(resp), qtype) {
+			res.Records = append(res.Records, requests.DNSAnswer{
+				Name: a.Name,
+				Type: int(a.Type),
+				Data: a.Data,
+			})
+		}
+	}
+
+	if len(res.Records) == 0 {
+		return nil
+	}
+	return res
+}
+
+func (rv *resolver) domain(name string) string {
+	if len(rv.cfg.Domains()) > 0 {
+		return rv.cfg.WhichDomain(name)
+	}
+
+	domain, err := publicsuffix.EffectiveTLDPlusOne(name)
+	if err != nil {
+		return ""
+	}
+	return domain
+}
+
+// resolveQuery returns the successful response with answers, or nil when the name does not exist,
+// has no records of the type or the resolvers failed to answer.
+func resolveQuery(ctx context.Context, pool *resolve.Resolvers, name string, qtype uint16) *dns.Msg {
+	msg := resolve.QueryMsg(name, qtype)
+
+	for i := 0; i < resolveAttempts; i++ {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		resp, err := pool.QueryBlocking(ctx, msg)
+		if err != nil {
+			continue
+		}
+		if resp.Rcode == dns.RcodeNameError || (resp.Rcode == dns.RcodeSuccess && len(resp.Answer) == 0) {
+			return nil
+		}
+		if resp.Rcode == dns.RcodeSuccess {
+			return resp
+		}
+	}
+	return nil
+}
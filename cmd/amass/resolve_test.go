@@ -0,0 +1,270 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/resolve"
+)
+
+// testZone holds the addresses served by the test DNS server. Any name under wild.owasp.org
+// resolves to the same address, as done by a DNS wildcard.
+var testZone = map[string]string{
+	"www.owasp.org.":     "192.0.2.1",
+	"api.owasp.org.":     "192.0.2.2",
+	"blocked.owasp.org.": "192.0.2.3",
+	"www.example.com.":   "192.0.2.4",
+}
+
+type testDNSServer struct {
+	addr    string
+	lock    sync.Mutex
+	queries map[string]int
+}
+
+func (s *testDNSServer) count(name string, qtype uint16) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.queries[dns.Fqdn(name)+dns.TypeToString[qtype]]
+}
+
+func startTestDNSServer(t *testing.T) *testDNSServer {
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the DNS server: %v", err)
+	}
+
+	s := &testDNSServer{
+		addr:    pc.LocalAddr().String(),
+		queries: make(map[string]int),
+	}
+	srv := &dns.Server{
+		PacketConn: pc,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			q := req.Question[0]
+			name := strings.ToLower(q.Name)
+
+			s.lock.Lock()
+			s.queries[name+dns.TypeToString[q.Qtype]]++
+			s.lock.Unlock()
+
+			m := new(dns.Msg)
+			m.SetReply(req)
+			m.RecursionAvailable = true
+
+			addr, found := testZone[name]
+			if !found && strings.HasSuffix(name, ".wild.owasp.org.") {
+				addr, found = "192.0.2.99", true
+			}
+			if !found {
+				m.Rcode = dns.RcodeNameError
+			} else if q.Qtype == dns.TypeA {
+				rr, _ := dns.NewRR(q.Name + " 300 IN A " + addr)
+				m.Answer = append(m.Answer, rr)
+			}
+			_ = w.WriteMsg(m)
+		}),
+	}
+	go func() { _ = srv.ActivateAndServe() }()
+	t.Cleanup(func() { _ = srv.Shutdown() })
+	return s
+}
+
+func newTestResolvers(t *testing.T, addr string) *resolve.Resolvers {
+	pool := resolve.NewResolvers()
+	if err := pool.AddResolvers(100, addr); err != nil {
+		t.Fatalf("failed to add the resolver: %v", err)
+	}
+	pool.SetDetectionResolver(100, addr)
+	pool.SetTimeout(time.Second)
+	t.Cleanup(pool.Stop)
+	return pool
+}
+
+func TestResolveRun(t *testing.T) {
+	srv := startTestDNSServer(t)
+
+	input := strings.Join([]string{
+		"# names collected by the last enumeration",
+		"www.owasp.org",
+		"WWW.owasp.org.",
+		"  www.owasp.org  ",
+		"",
+		"api.owasp.org",
+		"blocked.owasp.org",
+		"foo.wild.owasp.org",
+		"missing.owasp.org",
+		"www.example.com",
+	}, "\n")
+
+	tests := []struct {
+		name     string
+		domains  []string
+		trusted  bool
+		expected []string
+	}{
+		{
+			name:     "names in scope",
+			domains:  []string{"owasp.org"},
+			expected: []string{"api.owasp.org 192.0.2.2", "www.owasp.org 192.0.2.1"},
+		},
+		{
+			name:     "confirmed by the trusted resolvers",
+			domains:  []string{"owasp.org"},
+			trusted:  true,
+			expected: []string{"api.owasp.org 192.0.2.2", "www.owasp.org 192.0.2.1"},
+		},
+		{
+			name:     "no domains provided",
+			expected: []string{"api.owasp.org 192.0.2.2", "www.example.com 192.0.2.4", "www.owasp.org 192.0.2.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := srv.count("www.owasp.org", dns.TypeA)
+
+			cfg := config.NewConfig()
+			cfg.MaxDNSQueries = 10
+			cfg.Blacklist = []string{"blocked.owasp.org"}
+			for _, d := range tt.domains {
+				cfg.AddDomain(d)
+			}
+
+			pool := newTestResolvers(t, srv.addr)
+			trusted := pool
+			if tt.trusted {
+				trusted = newTestResolvers(t, srv.addr)
+			}
+
+			var buf bytes.Buffer
+			rv := &resolver{cfg: cfg, pool: pool, trusted: trusted}
+			if err := rv.run(context.Background(), strings.NewReader(input), &buf, false); err != nil {
+				t.Fatalf("run() returned an error: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			sort.Strings(lines)
+			if strings.Join(lines, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("run() = %v, expected %v", lines, tt.expected)
+			}
+
+			queries := 1
+			if tt.trusted {
+				queries = 2
+			}
+			if got := srv.count("www.owasp.org", dns.TypeA) - before; got != queries {
+				t.Errorf("www.owasp.org was queried %d times, expected %d", got, queries)
+			}
+			if got := srv.count("blocked.owasp.org", dns.TypeA); got != 0 {
+				t.Errorf("the blacklisted name was queried %d times", got)
+			}
+		})
+	}
+}
+
+func TestResolveRunJSON(t *testing.T) {
+	srv := startTestDNSServer(t)
+
+	cfg := config.NewConfig()
+	cfg.MaxDNSQueries = 10
+	cfg.AddDomain("owasp.org")
+	pool := newTestResolvers(t, srv.addr)
+
+	var buf bytes.Buffer
+	rv := &resolver{cfg: cfg, pool: pool, trusted: pool}
+	input := "www.owasp.org\nfoo.wild.owasp.org\nwww.owasp.org\n"
+	if err := rv.run(context.Background(), strings.NewReader(input), &buf, true); err != nil {
+		t.Fatalf("run() returned an error: %v", err)
+	}
+
+	var results []resolvedName
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var res resolvedName
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			t.Fatalf("failed to decode %q: %v", scanner.Text(), err)
+		}
+		results = append(results, res)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("run() wrote %d results, expected 1", len(results))
+	}
+	res := results[0]
+	if res.Name != "www.owasp.org" || res.Domain != "owasp.org" {
+		t.Errorf("run() wrote the name %s of domain %s", res.Name, res.Domain)
+	}
+	if len(res.Records) != 1 || res.Records[0].Type != int(dns.TypeA) || res.Records[0].Data != "192.0.2.1" {
+		t.Errorf("run() wrote the records %v, expected the A record 192.0.2.1", res.Records)
+	}
+}
+
+func TestResolveDomain(t *testing.T) {
+	tests := []struct {
+		name     string
+		domains  []string
+		expected string
+	}{
+		{name: "www.owasp.org", domains: []string{"owasp.org"}, expected: "owasp.org"},
+		{name: "www.example.com", domains: []string{"owasp.org"}, expected: ""},
+		{name: "www.example.co.uk", expected: "example.co.uk"},
+		{name: "co.uk", expected: ""},
+	}
+
+	for _, tt := range tests {
+		cfg := config.NewConfig()
+		for _, d := range tt.domains {
+			cfg.AddDomain(d)
+		}
+
+		rv := &resolver{cfg: cfg}
+		if got := rv.domain(tt.name); got != tt.expected {
+			t.Errorf("domain(%q) = %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestResolveQuery(t *testing.T) {
+	srv := startTestDNSServer(t)
+	pool := newTestResolvers(t, srv.addr)
+
+	tests := []struct {
+		name     string
+		qtype    uint16
+		resolved bool
+	}{
+		{name: "www.owasp.org", qtype: dns.TypeA, resolved: true},
+		{name: "www.owasp.org", qtype: dns.TypeAAAA, resolved: false},
+		{name: "missing.owasp.org", qtype: dns.TypeA, resolved: false},
+	}
+
+	for _, tt := range tests {
+		resp := resolveQuery(context.Background(), pool, tt.name, tt.qtype)
+		if (resp != nil) != tt.resolved {
+			t.Errorf("resolveQuery(%s, %s) = %v, expected a response: %t",
+				tt.name, dns.TypeToString[tt.qtype], resp, tt.resolved)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if resp := resolveQuery(ctx, pool, "www.owasp.org", dns.TypeA); resp != nil {
+		t.Errorf("resolveQuery() returned %v after the context was cancelled", resp)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+)
+
+// The file in the output directory keeping the schedule and the last runs of the daemon mode
+const scheduleStateFile = "amass_schedule.json"
+
+// The status of the last run of a scheduled scope
+const (
+	runRunning     = "running"
+	runFinished    = "finished"
+	runFailed      = "failed"
+	runInterrupted = "interrupted"
+)
+
+// daemonSchedule selects the times a scope is enumerated by the daemon mode.
+type daemonSchedule interface {
+	// Next returns the time of the enumeration following the time provided
+	Next(t time.Time) time.Time
+	String() string
+}
+
+// intervalSchedule repeats the enumerations a fixed duration after the start of the previous one.
+type intervalSchedule time.Duration
+
+// Next implements the daemonSchedule interface.
+func (i intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// String implements the daemonSchedule interface.
+func (i intervalSchedule) String() string {
+	return fmt.Sprintf("every %d minutes", int(time.Duration(i).Minutes()))
+}
+
+// scopeState is the schedule of a scope enumerated by the daemon mode, and the status of its last run.
+type scopeState struct {
+	Name        string     `json:"name"`
+	Schedule    string     `json:"schedule"`
+	Domains     []string   `json:"domains"`
+	Workspace   string     `json:"workspace,omitempty"`
+	NextRun     time.Time  `json:"next_run"`
+	Runs        int        `json:"runs"`
+	LastStart   *time.Time `json:"last_start,omitempty"`
+	LastFinish  *time.Time `json:"last_finish,omitempty"`
+	LastStatus  string     `json:"last_status,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	Enumeration string     `json:"last_enumeration,omitempty"`
+}
+
+// daemonScope is a scope enumerated again by the daemon mode.
+type daemonScope struct {
+	schedule daemonSchedule
+	state    *scopeState
+}
+
+// daemonScopes returns the scope of the command-line and configuration domains, scheduled by the cron
+// expression or the interval, followed by the scopes of the schedules section in the configuration.
+func daemonScopes(cfg *config.Config, args *enumArgs) []*daemonScope {
+	var scopes []*daemonScope
+
+	if domains := cfg.Domains(); len(domains) > 0 {
+		var sched daemonSchedule = intervalSchedule(time.Duration(args.Interval) * time.Minute)
+		if args.CronSchedule != nil {
+			sched = args.CronSchedule
+		}
+
+		scopes = append(scopes, &daemonScope{
+			schedule: sched,
+			state: &scopeState{
+				Name:      "default",
+				Domains:   append([]string(nil), domains...),
+				Workspace: cfg.Workspace,
+			},
+		})
+	}
+
+	for _, s := range cfg.Schedules {
+		workspace := s.Workspace
+		if workspace == "" {
+			workspace = cfg.Workspace
+		}
+
+		scopes = append(scopes, &daemonScope{
+			schedule: s.Cron,
+			state: &scopeState{
+				Name:      s.Name,
+				Domains:   s.Domains,
+				Workspace: workspace,
+			},
+		})
+	}
+
+	for _, s := range scopes {
+		s.state.Schedule = s.schedule.String()
+	}
+	return scopes
+}
+
+// resume restores the last run of the scope from the previous state, when the scope was not
+// changed, and selects the time of the next run. The run missed while the daemon was stopped
+// is performed right away.
+func (s *daemonScope) resume(prev *scopeState, now time.Time) {
+	if prev != nil && prev.Schedule == s.state.Schedule &&
+		prev.Workspace == s.state.Workspace && sameDomains(prev.Domains, s.state.Domains) {
+		s.state.Runs = prev.Runs
+		s.state.LastStart = prev.LastStart
+		s.state.LastFinish = prev.LastFinish
+		s.state.LastStatus = prev.LastStatus
+		s.state.LastError = prev.LastError
+		s.state.Enumeration = prev.Enumeration
+		// The daemon was stopped during the run
+		if s.state.LastStatus == runRunning {
+			s.state.LastStatus = runInterrupted
+		}
+	}
+
+	switch {
+	case s.state.LastStart != nil:
+		s.state.NextRun = s.schedule.Next(*s.state.LastStart)
+		if s.state.NextRun.Before(now) {
+			s.state.NextRun = now
+		}
+	case isInterval(s.schedule):
+		// The enumerations on an interval start with the daemon
+		s.state.NextRun = now
+	default:
+		s.state.NextRun = s.schedule.Next(now)
+	}
+}
+
+func isInterval(sched daemonSchedule) bool {
+	_, ok := sched.(intervalSchedule)
+	return ok
+}
+
+func sameDomains(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// loadScheduleState returns the scope states written by the previous run of the daemon mode.
+func loadScheduleState(path string) (map[string]*scopeState, error) {
+	states := make(map[string]*scopeState)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the schedule state: %v", err)
+	}
+
+	var list []*scopeState
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse the schedule state in %s: %v", path, err)
+	}
+	for _, s := range list {
+		states[s.Name] = s
+	}
+	return states, nil
+}
+
+// saveScheduleState writes the scope states to a temporary file and moves it into place, so the
+// state is never left partially written.
+func saveScheduleState(path string, scopes []*daemonScope) error {
+	list := make([]*scopeState, 0, len(scopes))
+	for _, s := range scopes {
+		list = append(list, s.state)
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".schedule-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create the schedule state: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write the schedule state: %v", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,165 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+// seenRange is the start of the first and the finish of the last enumeration that saw a name or an address.
+type seenRange struct {
+	first time.Time
+	last  time.Time
+}
+
+func (r *seenRange) extend(first, last time.Time) {
+	if r.first.IsZero() || (!first.IsZero() && first.Before(r.first)) {
+		r.first = first
+	}
+	if last.After(r.last) {
+		r.last = last
+	}
+}
+
+// sightings keeps the times the names, and the addresses the names resolved to, were seen across the enumerations.
+type sightings struct {
+	sync.Mutex
+	names map[string]*seenRange
+	addrs map[string]map[string]*seenRange
+}
+
+func newSightings() *sightings {
+	return &sightings{
+		names: make(map[string]*seenRange),
+		addrs: make(map[string]map[string]*seenRange),
+	}
+}
+
+// see records the name, and the address when not empty, as seen between the first and last times.
+func (s *sightings) see(name, addr string, first, last time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	r, found := s.names[name]
+	if !found {
+		r = new(seenRange)
+		s.names[name] = r
+	}
+	r.extend(first, last)
+
+	if addr == "" {
+		return
+	}
+	if _, found := s.addrs[name]; !found {
+		s.addrs[name] = make(map[string]*seenRange)
+	}
+	r, found = s.addrs[name][addr]
+	if !found {
+		r = new(seenRange)
+		s.addrs[name][addr] = r
+	}
+	r.extend(first, last)
+}
+
+// addEvents records the names and the addresses discovered by the events in the graph.
+func (s *sightings) addEvents(ctx context.Context, g *netmap.Graph, uuids []string) {
+	for _, uuid := range uuids {
+		first, last := g.EventDateRange(ctx, uuid)
+		names := g.EventFQDNs(ctx, uuid)
+
+		for _, name := range names {
+			s.see(name, "", first, last)
+		}
+		if len(names) == 0 {
+			continue
+		}
+		if pairs, err := g.NamesToAddrs(ctx, uuid, names...); err == nil {
+			for _, p := range pairs {
+				if p.Name != "" && p.Addr != "" {
+					s.see(p.Name, p.Addr, first, last)
+				}
+			}
+		}
+	}
+}
+
+// nameSeen returns the first and last times the name was seen, which are zero when it was never seen.
+func (s *sightings) nameSeen(name string) (time.Time, time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	if r, found := s.names[name]; found {
+		return r.first, r.last
+	}
+	return time.Time{}, time.Time{}
+}
+
+// stamp sets the times the output and its addresses were seen. The output is also recorded as seen
+// at the time provided, unless the time is zero.
+func (s *sightings) stamp(out *requests.Output, now time.Time) {
+	if !now.IsZero() {
+		s.see(out.Name, "", now, now)
+		for _, a := range out.Addresses {
+			if a.Address != nil {
+				s.see(out.Name, a.Address.String(), now, now)
+			}
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if r, found := s.names[out.Name]; found {
+		out.FirstSeen, out.LastSeen = seenTimes(r)
+	}
+	// The addresses are copied, since the outputs can share them
+	addrs := make([]requests.AddressInfo, len(out.Addresses))
+	copy(addrs, out.Addresses)
+	for i, a := range addrs {
+		if a.Address == nil {
+			continue
+		}
+		if r, found := s.addrs[out.Name][a.Address.String()]; found {
+			addrs[i].FirstSeen, addrs[i].LastSeen = seenTimes(r)
+		}
+	}
+	out.Addresses = addrs
+}
+
+func seenTimes(r *seenRange) (string, string) {
+	var first, last string
+
+	if !r.first.IsZero() {
+		first = r.first.UTC().Format(time.RFC3339)
+	}
+	if !r.last.IsZero() {
+		last = r.last.UTC().Format(time.RFC3339)
+	}
+	return first, last
+}
+
+// priorSightings returns the times the names in scope were seen by the previous enumerations of the
+// workspace, as stored in the graph databases of the system.
+func priorSightings(ctx context.Context, sys systems.System, domains []string, workspace, current string) *sightings {
+	s := newSightings()
+
+	for _, g := range sys.GraphDatabases() {
+		var events []string
+
+		for _, uuid := range systems.WorkspaceEvents(ctx, g, workspace, g.EventsInScope(ctx, domains...)) {
+			if uuid != current {
+				events = append(events, uuid)
+			}
+		}
+		s.addEvents(ctx, g, events)
+	}
+	return s
+}
@@ -0,0 +1,241 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/selftest"
+)
+
+const (
+	selftestUsageMsg = "selftest [options]"
+)
+
+type selftestArgs struct {
+	Queries   int
+	Writes    int
+	Timeout   int
+	Resolvers *stringset.Set
+	Trusted   *stringset.Set
+	Options   struct {
+		NoColor   bool
+		NoGraph   bool
+		NoSources bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+	}
+}
+
+func runSelftestCommand(clArgs []string) {
+	args := selftestArgs{
+		Resolvers: stringset.New(),
+		Trusted:   stringset.New(),
+	}
+	defer args.Resolvers.Close()
+	defer args.Trusted.Close()
+	var help1, help2 bool
+	selftestCommand := flag.NewFlagSet("selftest", flag.ContinueOnError)
+
+	selftestBuf := new(bytes.Buffer)
+	selftestCommand.SetOutput(selftestBuf)
+
+	selftestCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	selftestCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	selftestCommand.IntVar(&args.Queries, "queries", selftest.DefaultQueries, "Number of DNS queries sent to each resolver")
+	selftestCommand.IntVar(&args.Writes, "writes", selftest.DefaultWrites, "Number of names written to the temporary graph database")
+	selftestCommand.IntVar(&args.Timeout, "timeout", int(selftest.DefaultSourceTimeout.Seconds()), "Number of seconds waited for each data source")
+	selftestCommand.Var(args.Resolvers, "r", "IP addresses of untrusted DNS resolvers (can be used multiple times)")
+	selftestCommand.Var(args.Trusted, "tr", "IP addresses of trusted DNS resolvers (can be used multiple times)")
+	selftestCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	selftestCommand.BoolVar(&args.Options.NoGraph, "nograph", false, "Do not measure the graph database")
+	selftestCommand.BoolVar(&args.Options.NoSources, "nosources", false, "Do not measure the data sources")
+	selftestCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	selftestCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
+
+	if err := selftestCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(selftestUsageMsg, selftestCommand, selftestBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Queries < 1 || args.Writes < 1 || args.Timeout < 1 {
+		r.Fprintln(color.Error, "The queries, writes and timeout flags must provide positive values")
+		os.Exit(1)
+	}
+
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err != nil && args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	if args.Filepaths.Directory != "" {
+		cfg.Dir = args.Filepaths.Directory
+	}
+	if args.Resolvers.Len() > 0 {
+		cfg.SetResolvers(args.Resolvers.Slice()...)
+	}
+	if args.Trusted.Len() > 0 {
+		cfg.SetTrustedResolvers(args.Trusted.Slice()...)
+	}
+
+	ctx := context.Background()
+	trusted := config.DefaultBaselineResolvers
+	if len(cfg.TrustedResolvers) > 0 {
+		trusted = cfg.TrustedResolvers
+	}
+	g.Fprintf(color.Error, "Measuring %d trusted resolvers\n", len(trusted))
+	resolvers := selftest.Resolvers(ctx, trusted, true, args.Queries)
+	// The untrusted resolvers are only measured when provided, since the public list is large
+	if len(cfg.Resolvers) > 0 {
+		g.Fprintf(color.Error, "Measuring %d untrusted resolvers\n", len(cfg.Resolvers))
+		resolvers = append(resolvers, selftest.Resolvers(ctx, cfg.Resolvers, false, args.Queries)...)
+	}
+	printResolverResults(resolvers)
+
+	var sources []*selftest.SourceResult
+	if !args.Options.NoSources {
+		scripts, err := cfg.AcquireScripts()
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to acquire the data source scripts: %v\n", err)
+			os.Exit(1)
+		}
+
+		var targets []*selftest.SourceTarget
+		for _, t := range selftest.ScriptTargets(scripts) {
+			if !sourceDisabled(cfg, t.Name) {
+				targets = append(targets, t)
+			}
+		}
+		g.Fprintf(color.Error, "Measuring %d data sources\n", len(targets))
+		sources = selftest.Sources(ctx, targets, time.Duration(args.Timeout)*time.Second)
+		printSourceResults(sources)
+	}
+
+	var graph *selftest.GraphResult
+	if !args.Options.NoGraph {
+		createOutputDirectory(cfg)
+		g.Fprintf(color.Error, "Measuring the local graph database with %d writes\n", args.Writes)
+
+		var err error
+		graph, err = selftest.Graph(ctx, config.OutputDirectory(cfg.Dir), args.Writes)
+		if err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+		printGraphResult(graph)
+	}
+
+	printSuggestions(selftest.Suggest(resolvers, sources, graph))
+}
+
+// sourceDisabled returns true when the configuration excludes the data source from the enumerations.
+func sourceDisabled(cfg *config.Config, name string) bool {
+	if len(cfg.SourceFilter.Sources) == 0 {
+		return false
+	}
+
+	var found bool
+	for _, src := range cfg.SourceFilter.Sources {
+		if strings.EqualFold(src, name) {
+			found = true
+			break
+		}
+	}
+	return found != cfg.SourceFilter.Include
+}
+
+func printResolverResults(results []*selftest.ResolverResult) {
+	fmt.Fprintf(color.Output, "\n%-24s%-10s%-14s%-12s%-10s%s\n", blue("Resolver"), blue("Type"),
+		blue("Reliability"), blue("Latency"), blue("QPS"), blue("Hijacks"))
+	for _, res := range results {
+		t := "untrusted"
+		if res.Trusted {
+			t = "trusted"
+		}
+
+		hijack := "no"
+		if res.Hijacked {
+			hijack = red("yes")
+		}
+		fmt.Fprintf(color.Output, "%-24s%-10s%-14s%-12s%-10s%s\n", green(res.Address), yellow(t),
+			yellow(fmt.Sprintf("%.0f%%", res.Reliability()*100)), yellow(res.Latency.Round(time.Millisecond).String()),
+			yellow(fmt.Sprintf("%.1f", res.QPS)), yellow(hijack))
+	}
+}
+
+func printSourceResults(results []*selftest.SourceResult) {
+	fmt.Fprintf(color.Output, "\n%-24s%-12s%s\n", blue("Data Source"), blue("Latency"), blue("Status"))
+	for _, res := range results {
+		if !res.Reachable() {
+			fmt.Fprintf(color.Output, "%-24s%-12s%s\n", green(res.Name), red("-"), red(res.Err.Error()))
+			continue
+		}
+		fmt.Fprintf(color.Output, "%-24s%-12s%s\n", green(res.Name),
+			yellow(res.Latency.Round(time.Millisecond).String()), yellow(res.StatusCode))
+	}
+}
+
+func printGraphResult(res *selftest.GraphResult) {
+	fmt.Fprintf(color.Output, "\n%s\n", blue("Local Graph Database"))
+	fmt.Fprintf(color.Output, "%s %s\n", green("Writes per second with 1 worker:"), yellow(fmt.Sprintf("%.0f", res.Sequential)))
+	fmt.Fprintf(color.Output, "%s %s\n", green(fmt.Sprintf("Writes per second with %d workers:", res.Workers)),
+		yellow(fmt.Sprintf("%.0f", res.Concurrent)))
+}
+
+func printSuggestions(s *selftest.Suggestions) {
+	fmt.Fprintf(color.Output, "\n%s\n", blue("Suggested Settings"))
+
+	var flags []string
+	if s.ResolversQPS > 0 {
+		flags = append(flags, fmt.Sprintf("-rqps %d", s.ResolversQPS))
+	}
+	if s.TrustedQPS > 0 {
+		flags = append(flags, fmt.Sprintf("-trqps %d", s.TrustedQPS))
+	}
+	if len(flags) > 0 {
+		fmt.Fprintf(color.Output, "%s %s\n\n", green("amass enum"), yellow(strings.Join(flags, " ")))
+	}
+
+	if s.ResolutionWorkers > 0 || s.GraphWriteWorkers > 0 {
+		fmt.Fprintln(color.Output, yellow("[concurrency]"))
+		if s.ResolutionWorkers > 0 {
+			fmt.Fprintln(color.Output, yellow(fmt.Sprintf("resolution = %d", s.ResolutionWorkers)))
+		}
+		if s.GraphWriteWorkers > 0 {
+			fmt.Fprintln(color.Output, yellow(fmt.Sprintf("graph_writes = %d", s.GraphWriteWorkers)))
+		}
+		fmt.Fprintln(color.Output)
+	}
+
+	if len(s.DisableSources) > 0 {
+		fmt.Fprintln(color.Output, yellow("[data_sources.disabled]"))
+		for _, name := range s.DisableSources {
+			fmt.Fprintln(color.Output, yellow("data_source = "+name))
+		}
+		fmt.Fprintln(color.Output)
+	}
+
+	if len(s.DropResolvers) > 0 {
+		fmt.Fprintf(color.Output, "%s %s\n", green("Remove the unreliable resolvers:"), red(strings.Join(s.DropResolvers, ", ")))
+	}
+}
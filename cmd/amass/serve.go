@@ -0,0 +1,429 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/datasrcs"
+	"github.com/owasp-amass/amass/v3/enum"
+	"github.com/owasp-amass/amass/v3/format"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/server"
+	"github.com/owasp-amass/amass/v3/systems"
+	"google.golang.org/grpc"
+)
+
+const (
+	serveUsageMsg      = "serve [options]"
+	defaultServeAddr   = "127.0.0.1:8080"
+	serveShutdownDelay = 10 * time.Second
+	serveReloadDelay   = 15 * time.Second
+)
+
+type serveArgs struct {
+	Address     string
+	GRPCAddress string
+	Tokens      format.ParseStrings
+	Options     struct {
+		NoColor bool
+		Silent  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+	}
+}
+
+func runServeCommand(clArgs []string) {
+	var args serveArgs
+	var help1, help2 bool
+	serveCommand := flag.NewFlagSet("serve", flag.ContinueOnError)
+
+	serveBuf := new(bytes.Buffer)
+	serveCommand.SetOutput(serveBuf)
+
+	serveCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	serveCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	serveCommand.StringVar(&args.Address, "addr", defaultServeAddr, "Address the server listens on")
+	serveCommand.StringVar(&args.GRPCAddress, "grpc-addr", "", "Address the gRPC job management API listens on (default: disabled)")
+	serveCommand.Var(&args.Tokens, "token", "API tokens accepted by the server separated by commas (default: a generated token)")
+	serveCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	serveCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	serveCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	serveCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+
+	if err := serveCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(serveUsageMsg, serveCommand, serveBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = io.Discard
+		color.Error = io.Discard
+	}
+
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+
+	if args.Filepaths.Directory != "" {
+		cfg.Dir = args.Filepaths.Directory
+	}
+	createOutputDirectory(cfg)
+
+	rLog, wLog := io.Pipe()
+	// Setup logging so that messages from the enumeration jobs are written to the file
+	cfg.Log = log.New(wLog, "", log.Lmicroseconds)
+	logOut := newLogOutputs(cfg)
+	go writeLogsAndMessages(rLog, filepath.Join(config.OutputDirectory(cfg.Dir), "amass.log"), false, logOut)
+
+	var db *netmap.Graph
+	var runner *serveRunner
+	// The System performing the enumeration jobs also provides the graph database being served
+	if sys, err := newServeSystem(cfg); err == nil {
+		defer func() { _ = sys.Shutdown() }()
+		logOut.setSources(sys.DataSources())
+
+		runner = &serveRunner{args: &args, sys: sys}
+		db = systemGraphDatabase(cfg, sys)
+	} else {
+		fgY.Fprintf(color.Error, "The enumeration jobs are not available: %v\n", err)
+
+		db = openGraphDatabase(args.Filepaths.Directory, cfg)
+		if db == nil {
+			r.Fprintln(color.Error, "Failed to connect with the database")
+			os.Exit(1)
+		}
+		defer db.Close()
+	}
+
+	tokens := []string(args.Tokens)
+	if len(tokens) == 0 {
+		token, err := generateToken()
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to generate the API token: %v\n", err)
+			os.Exit(1)
+		}
+		tokens = append(tokens, token)
+		fmt.Fprintf(color.Error, "%s%s\n", yellow("The generated API token is "), green(token))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings := &server.Settings{Tokens: tokens}
+	if runner != nil {
+		settings.Runner = runner
+	}
+
+	if runner != nil {
+		go runner.reloadScripts(ctx, logOut)
+	}
+
+	handler, err := server.NewHandler(ctx, db, settings)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to create the server: %v\n", err)
+		os.Exit(1)
+	}
+
+	srv := &http.Server{
+		Addr:              args.Address,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	var gsrv *grpc.Server
+	if args.GRPCAddress != "" {
+		gsrv = startGRPCServer(ctx, args.GRPCAddress, settings)
+	}
+	// Monitor for cancellation by the user
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(quit)
+
+		<-quit
+		ctx, cancel := context.WithTimeout(context.Background(), serveShutdownDelay)
+		defer cancel()
+		if gsrv != nil {
+			// The result streams following the jobs would keep a graceful stop waiting
+			gsrv.Stop()
+		}
+		_ = srv.Shutdown(ctx)
+	}()
+
+	g.Fprintf(color.Error, "The GraphQL endpoint is available at http://%s%s\n", args.Address, server.GraphQLPath)
+	g.Fprintf(color.Error, "The REST API is available at http://%s%s\n", args.Address, server.APIPath)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		r.Fprintf(color.Error, "The server failed: %v\n", err)
+		os.Exit(1)
+	}
+	// Stop the enumeration job in progress and wait for the findings to be stored
+	cancel()
+	if runner != nil {
+		runner.Lock()
+	}
+}
+
+// startGRPCServer serves the job management API on the address, or returns nil when the
+// enumeration jobs are not available.
+func startGRPCServer(ctx context.Context, addr string, settings *server.Settings) *grpc.Server {
+	gsrv, err := server.NewGRPCServer(ctx, settings)
+	if err != nil {
+		fgY.Fprintf(color.Error, "The gRPC job management API is not available: %v\n", err)
+		return nil
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to listen for the gRPC job management API: %v\n", err)
+		os.Exit(1)
+	}
+	go func() {
+		if err := gsrv.Serve(lis); err != nil {
+			r.Fprintf(color.Error, "The gRPC server failed: %v\n", err)
+		}
+	}()
+
+	g.Fprintf(color.Error, "The gRPC job management API is available at %s\n", addr)
+	return gsrv
+}
+
+func newServeSystem(cfg *config.Config) (*systems.LocalSystem, error) {
+	sys, err := systems.NewLocalSystem(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sys.SetDataSources(datasrcs.GetAllSources(sys)); err != nil {
+		_ = sys.Shutdown()
+		return nil, err
+	}
+	initializeSourceTags(sys.DataSources())
+	return sys, nil
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// systemGraphDatabase returns the system graph database selected by the configuration as the
+// primary, since the local database cannot be opened a second time while the system is running.
+func systemGraphDatabase(cfg *config.Config, sys systems.System) *netmap.Graph {
+	dbs := sys.GraphDatabases()
+
+	for i, db := range cfg.GraphDBs {
+		// The local database is the first in the list of the system
+		if db.Primary && i+1 < len(dbs) {
+			return dbs[i+1]
+		}
+	}
+	return dbs[0]
+}
+
+// serveRunner performs the enumeration jobs submitted to the REST API, one at a time.
+type serveRunner struct {
+	sync.Mutex
+	args *serveArgs
+	sys  systems.System
+	// The enumeration of the job in progress, which can be paused and resumed
+	elock   sync.Mutex
+	current *enum.Enumeration
+	job     string
+}
+
+// Run implements the server.Runner interface.
+func (s *serveRunner) Run(ctx context.Context, id string, req *server.JobRequest, out func(*requests.Output)) error {
+	s.Lock()
+	defer s.Unlock()
+
+	cfg := config.NewConfig()
+	// Each job starts with the settings of the configuration file
+	if err := config.AcquireConfig(s.args.Filepaths.Directory, s.args.Filepaths.ConfigFile, cfg); err != nil && s.args.Filepaths.ConfigFile != "" {
+		return fmt.Errorf("failed to load the configuration file: %v", err)
+	}
+
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	cfg.UUID = u
+	cfg.Dir = s.sys.Config().Dir
+	cfg.Log = s.sys.Config().Log
+	cfg.AddDomains(req.Domains...)
+	if req.Workspace != "" {
+		cfg.Workspace = req.Workspace
+	}
+	cfg.Passive = req.Passive
+	cfg.Active = req.Active
+	cfg.BruteForcing = req.Brute
+	cfg.Alterations = req.Alterations
+	cfg.SourceFilter.Sources = expandCategoryNames(cfg.SourceFilter.Sources, generateCategoryMap(cfg, s.sys.DataSources()))
+
+	graph := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer graph.Close()
+
+	e := enum.NewEnumeration(cfg, s.sys, graph)
+	if e == nil {
+		return errors.New("failed to setup the enumeration")
+	}
+	s.setCurrent(id, e)
+	defer s.setCurrent("", nil)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	outChan := make(chan *requests.Output, 10)
+
+	wg.Add(2)
+	go processOutput(ctx, graph, e, nil, nil, []chan *requests.Output{outChan}, nil, done, &wg)
+	go func() {
+		defer wg.Done()
+
+		for o := range outChan {
+			out(o)
+		}
+	}()
+
+	err = e.Start(ctx)
+	close(done)
+	wg.Wait()
+	// Reaching the timeout or being canceled does not fail the job
+	if ctx.Err() != nil {
+		err = nil
+	}
+	// The findings are stored even when the job was stopped early
+	mctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	for _, g := range s.sys.GraphDatabases() {
+		if merr := graph.Migrate(mctx, g); merr != nil && err == nil {
+			err = fmt.Errorf("the database migration to %s failed: %v", g, merr)
+		}
+	}
+	return err
+}
+
+// reloadScripts periodically loads the data source scripts added or changed in the scripts
+// directories, and removes the data sources of the deleted scripts. The data sources are replaced
+// between the jobs, so a job in progress keeps the data sources it started with.
+func (s *serveRunner) reloadScripts(ctx context.Context, logOut *logOutputs) {
+	w := datasrcs.NewScriptWatcher(s.sys)
+	t := time.NewTicker(serveReloadDelay)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		s.Lock()
+		if w.Reload() > 0 {
+			srcs := s.sys.DataSources()
+
+			initializeSourceTags(srcs)
+			logOut.setSources(srcs)
+		}
+		s.Unlock()
+	}
+}
+
+func (s *serveRunner) setCurrent(id string, e *enum.Enumeration) {
+	s.elock.Lock()
+	defer s.elock.Unlock()
+
+	s.job = id
+	s.current = e
+}
+
+func (s *serveRunner) enumeration(id string) (*enum.Enumeration, error) {
+	s.elock.Lock()
+	defer s.elock.Unlock()
+
+	if s.current == nil || s.job != id {
+		return nil, errors.New("the job is not in progress")
+	}
+	return s.current, nil
+}
+
+// Pause implements the server.Pauser interface.
+func (s *serveRunner) Pause(id string) error {
+	e, err := s.enumeration(id)
+	if err != nil {
+		return err
+	}
+
+	e.Pause()
+	return nil
+}
+
+// Resume implements the server.Pauser interface.
+func (s *serveRunner) Resume(id string) error {
+	e, err := s.enumeration(id)
+	if err != nil {
+		return err
+	}
+
+	e.Resume()
+	return nil
+}
+
+// Progress implements the server.ProgressReporter interface.
+func (s *serveRunner) Progress(id string) (*server.JobProgress, error) {
+	e, err := s.enumeration(id)
+	if err != nil {
+		return nil, err
+	}
+
+	p := e.Progress()
+	jp := &server.JobProgress{
+		Percent:   p.Percent(),
+		Planned:   p.Total(),
+		Completed: p.Completed(),
+	}
+	if eta, ok := p.ETA(); ok {
+		jp.ETA = int(eta.Seconds())
+	}
+	return jp, nil
+}
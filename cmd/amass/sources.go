@@ -0,0 +1,130 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/enum"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+// sourceContribution describes the requests and the discovered names of a data source.
+type sourceContribution struct {
+	Name        string `json:"name"`
+	Requests    int    `json:"requests"`
+	Errors      int    `json:"errors"`
+	Names       int    `json:"names"`
+	UniqueNames int    `json:"unique_names"`
+	// The usage of each API key, when the data source rotates among several of them
+	Keys []config.KeyUsage `json:"keys,omitempty"`
+}
+
+// sourceNames counts the names discovered by each data source, and those discovered by no other data source.
+type sourceNames struct {
+	names  map[string]int
+	unique map[string]int
+}
+
+func newSourceNames() *sourceNames {
+	return &sourceNames{
+		names:  make(map[string]int),
+		unique: make(map[string]int),
+	}
+}
+
+func collectSourceNames(e *enum.Enumeration, counts *sourceNames, output chan *requests.Output, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for out := range output {
+		if !e.Config.Passive && len(out.Addresses) <= 0 && len(out.Findings) == 0 {
+			continue
+		}
+
+		srcs := make(map[string]struct{})
+		for _, src := range out.Sources {
+			srcs[src] = struct{}{}
+		}
+		for src := range srcs {
+			counts.names[src]++
+			if len(srcs) == 1 {
+				counts.unique[src]++
+			}
+		}
+	}
+}
+
+// sourceContributions combines the requests of the data sources with the names they discovered,
+// ordered by the unique names, so the most valuable data sources come first.
+func sourceContributions(cfg *config.Config, stats []*enum.SourceStats, counts *sourceNames) []*sourceContribution {
+	contribs := make([]*sourceContribution, 0, len(stats))
+	for _, s := range stats {
+		c := &sourceContribution{
+			Name:        s.Name,
+			Requests:    s.Requests,
+			Errors:      s.Errors,
+			Names:       counts.names[s.Name],
+			UniqueNames: counts.unique[s.Name],
+		}
+		if dsc := cfg.GetDataSourceConfig(s.Name); dsc != nil {
+			if keys := dsc.KeyUsage(); len(keys) > 1 {
+				c.Keys = keys
+			}
+		}
+		contribs = append(contribs, c)
+	}
+
+	sort.Slice(contribs, func(i, j int) bool {
+		if contribs[i].UniqueNames != contribs[j].UniqueNames {
+			return contribs[i].UniqueNames > contribs[j].UniqueNames
+		}
+		if contribs[i].Names != contribs[j].Names {
+			return contribs[i].Names > contribs[j].Names
+		}
+		return contribs[i].Name < contribs[j].Name
+	})
+	return contribs
+}
+
+func printSourceContributions(contribs []*sourceContribution) {
+	fmt.Fprintf(color.Error, "\n%-28s%-12s%-10s%-10s%s\n", blue("Data Source"), blue("Requests"),
+		blue("Errors"), blue("Names"), blue("Unique"))
+	for _, c := range contribs {
+		errs := yellow(c.Errors)
+		if c.Errors > 0 {
+			errs = red(c.Errors)
+		}
+
+		fmt.Fprintf(color.Error, "%-28s%-12s%-10s%-10s%s\n", green(c.Name), yellow(c.Requests),
+			errs, yellow(c.Names), yellow(c.UniqueNames))
+		for _, k := range c.Keys {
+			fmt.Fprintf(color.Error, "  %-26s%s\n", yellow(k.Name),
+				yellow(fmt.Sprintf("%d uses, %d rate limited", k.Uses, k.RateLimited)))
+		}
+	}
+}
+
+// saveSourceContributions writes the statistics of the data sources to the JSON file next to the other output files.
+func saveSourceContributions(e *enum.Enumeration, args *enumArgs, contribs []*sourceContribution) {
+	path := filepath.Join(config.OutputDirectory(e.Config.Dir), "amass_sources.json")
+	if args.Filepaths.AllFilePrefix != "" {
+		path = args.Filepaths.AllFilePrefix + "_sources.json"
+	}
+
+	data, err := json.MarshalIndent(contribs, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to save the data source statistics: %v\n", err)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/caffix/stringset"
+	"github.com/owasp-amass/amass/v3/format"
+)
+
+var (
+	asnRegex   = regexp.MustCompile(`^(?i:AS)?([0-9]+)$`)
+	rangeRegex = regexp.MustCompile(`^[0-9.]+-[0-9]+$`)
+)
+
+// stdinTargets holds the targets read from the standard input, grouped by their type.
+type stdinTargets struct {
+	Domains   []string
+	Addresses []string
+	CIDRs     []string
+	ASNs      []string
+}
+
+// stdinProvided returns true when the standard input is a pipe or a file, and not a terminal.
+func stdinProvided() bool {
+	finfo, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return finfo.Mode()&os.ModeCharDevice == 0
+}
+
+// stdinWanted returns true when the command was provided no targets by the arguments and the standard
+// input is not a terminal. The commands provided targets do not block on a pipe that never closes.
+func stdinWanted(targets int) bool {
+	return targets == 0 && stdinProvided()
+}
+
+// readStdinTargets reads one target per line and detects whether it's an ASN, a CIDR, an IP address
+// or range, or a domain name. Blank lines and lines starting with '#' are skipped.
+func readStdinTargets(reader io.Reader) (*stdinTargets, error) {
+	var num int
+	targets := new(stdinTargets)
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		num++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if m := asnRegex.FindStringSubmatch(line); m != nil {
+			targets.ASNs = append(targets.ASNs, m[1])
+		} else if _, _, err := net.ParseCIDR(line); err == nil {
+			targets.CIDRs = append(targets.CIDRs, line)
+		} else if net.ParseIP(line) != nil || rangeRegex.MatchString(line) {
+			var ips format.ParseIPs
+			if err := ips.Set(line); err != nil {
+				return nil, fmt.Errorf("line %d of the standard input: %v", num, err)
+			}
+			targets.Addresses = append(targets.Addresses, line)
+		} else if isDomainName(line) {
+			targets.Domains = append(targets.Domains, strings.ToLower(strings.Trim(line, ".")))
+		} else {
+			return nil, fmt.Errorf("line %d of the standard input is not a domain name, address, CIDR or ASN: %s", num, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the standard input: %v", err)
+	}
+	return targets, nil
+}
+
+// apply adds the targets to the arguments of the command.
+func (t *stdinTargets) apply(domains *stringset.Set, addrs, cidrs, asns flag.Value) error {
+	domains.InsertMany(t.Domains...)
+
+	for _, set := range []struct {
+		values []string
+		arg    flag.Value
+	}{
+		{t.Addresses, addrs},
+		{t.CIDRs, cidrs},
+		{t.ASNs, asns},
+	} {
+		for _, v := range set.values {
+			if err := set.arg.Set(v); err != nil {
+				return fmt.Errorf("failed to parse the standard input: %v", err)
+			}
+		}
+	}
+	return nil
+}
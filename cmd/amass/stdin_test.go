@@ -0,0 +1,108 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/caffix/stringset"
+	"github.com/owasp-amass/amass/v3/format"
+)
+
+func TestReadStdinTargets(t *testing.T) {
+	input := `# The targets of the engagement
+AS13335
+as15169
+8075
+
+192.0.2.0/24
+2001:db8::/32
+198.51.100.7
+2001:db8::1
+203.0.113.1-20
+OWASP.org.
+   example.com
+`
+
+	targets, err := readStdinTargets(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readStdinTargets() error = %v", err)
+	}
+
+	expected := &stdinTargets{
+		Domains:   []string{"owasp.org", "example.com"},
+		Addresses: []string{"198.51.100.7", "2001:db8::1", "203.0.113.1-20"},
+		CIDRs:     []string{"192.0.2.0/24", "2001:db8::/32"},
+		ASNs:      []string{"13335", "15169", "8075"},
+	}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Errorf("readStdinTargets() = %+v, expected %+v", targets, expected)
+	}
+}
+
+func TestReadStdinTargetsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  string
+	}{
+		{name: "not a target", input: "owasp.org\nlocalhost\n", line: "line 2"},
+		{name: "invalid range", input: "# comment\n203.0.113.1-300\n", line: "line 2"},
+		{name: "URL", input: "https://owasp.org/\n", line: "line 1"},
+		{name: "name with spaces", input: "\n\nowasp .org\n", line: "line 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readStdinTargets(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.line) {
+				t.Errorf("readStdinTargets() error = %v, expected it to name %s", err, tt.line)
+			}
+		})
+	}
+}
+
+func TestStdinTargetsApply(t *testing.T) {
+	targets, err := readStdinTargets(strings.NewReader("AS13335\n192.0.2.0/24\n198.51.100.1-3\nowasp.org\n"))
+	if err != nil {
+		t.Fatalf("readStdinTargets() error = %v", err)
+	}
+
+	var addrs format.ParseIPs
+	var cidrs format.ParseCIDRs
+	var asns format.ParseInts
+	domains := stringset.New("example.com")
+	defer domains.Close()
+
+	if err := targets.apply(domains, &addrs, &cidrs, &asns); err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+	if domains.Len() != 2 || !domains.Has("owasp.org") {
+		t.Errorf("apply() provided the domains %v", domains.Slice())
+	}
+	if len(addrs) != 3 || addrs[0].String() != "198.51.100.1" || addrs[2].String() != "198.51.100.3" {
+		t.Errorf("apply() provided the addresses %v", addrs)
+	}
+	if len(cidrs) != 1 || cidrs[0].String() != "192.0.2.0/24" {
+		t.Errorf("apply() provided the CIDRs %v", cidrs)
+	}
+	if len(asns) != 1 || asns[0] != 13335 {
+		t.Errorf("apply() provided the ASNs %v", asns)
+	}
+
+	bad := &stdinTargets{ASNs: []string{"not a number"}}
+	if err := bad.apply(domains, &addrs, &cidrs, &asns); err == nil {
+		t.Error("apply() accepted an ASN that is not a number")
+	}
+}
+
+func TestStdinWantedWithTargets(t *testing.T) {
+	// The standard input is not consulted when the arguments provide targets
+	if stdinWanted(1) {
+		t.Error("stdinWanted() returned true for a command provided targets")
+	}
+}
@@ -7,13 +7,17 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/output"
 	"github.com/owasp-amass/amass/v3/requests"
 	"github.com/caffix/netmap"
 	"github.com/caffix/stringset"
@@ -26,18 +30,21 @@ const (
 )
 
 type trackArgs struct {
-	Domains *stringset.Set
-	Last    int
-	Since   string
-	Options struct {
+	Domains   *stringset.Set
+	Last      int
+	Since     string
+	Workspace string
+	Options   struct {
 		History bool
 		NoColor bool
+		Notify  bool
 		Silent  bool
 	}
 	Filepaths struct {
 		ConfigFile string
 		Directory  string
 		Domains    string
+		JSONOutput string
 	}
 }
 
@@ -59,10 +66,13 @@ func runTrackCommand(clArgs []string) {
 	trackCommand.StringVar(&args.Since, "since", "", "Exclude all enumerations before (format: "+timeFormat+")")
 	trackCommand.BoolVar(&args.Options.History, "history", false, "Show the difference between all enumeration pairs")
 	trackCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	trackCommand.BoolVar(&args.Options.Notify, "notify", false, "Post a summary of the changes to the configured chat services")
 	trackCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
 	trackCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
 	trackCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
 	trackCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	trackCommand.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file describing the changes")
+	trackCommand.StringVar(&args.Workspace, "workspace", "", "Name of the workspace isolating the enumerations in the graph database")
 
 	if len(clArgs) < 1 {
 		commandUsage(trackUsageMsg, trackCommand, trackBuf)
@@ -83,6 +93,10 @@ func runTrackCommand(clArgs []string) {
 		color.Output = io.Discard
 		color.Error = io.Discard
 	}
+	// The JSON written to STDOUT replaces the text output
+	if args.Filepaths.JSONOutput == "-" {
+		color.Output = io.Discard
+	}
 	// Some input validation
 	if args.Since != "" && args.Last != 0 {
 		r.Fprintln(color.Error, "The since flag cannot be used with the last or all flags")
@@ -121,6 +135,9 @@ func runTrackCommand(clArgs []string) {
 		if args.Filepaths.Directory == "" {
 			args.Filepaths.Directory = cfg.Dir
 		}
+		if args.Workspace == "" {
+			args.Workspace = cfg.Workspace
+		}
 		if args.Domains.Len() == 0 {
 			args.Domains.InsertMany(cfg.Domains()...)
 		}
@@ -136,7 +153,7 @@ func runTrackCommand(clArgs []string) {
 	}
 	defer db.Close()
 	// Create the in-memory graph database
-	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), db)
+	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), args.Workspace, db)
 	if err != nil {
 		r.Fprintln(color.Error, err.Error())
 		os.Exit(1)
@@ -184,6 +201,15 @@ func runTrackCommand(clArgs []string) {
 	latest = latest[begin:]
 
 	cache := cacheWithData()
+	if args.Options.Notify {
+		notifyChanges(cfg, uuids, args.Domains.Slice(), memDB, cache)
+	}
+	if args.Filepaths.JSONOutput != "" {
+		writeTrackJSON(args.Filepaths.JSONOutput, uuids, args.Domains.Slice(), args.Options.History, memDB, cache)
+		if args.Filepaths.JSONOutput == "-" {
+			return
+		}
+	}
 	if len(uuids) == 1 {
 		printOneEvent(uuids, args.Domains.Slice(), earliest[0], latest[0], memDB, cache)
 		return
@@ -195,7 +221,7 @@ func runTrackCommand(clArgs []string) {
 }
 
 func printOneEvent(uuid, domains []string, earliest, latest time.Time, db *netmap.Graph, cache *requests.ASNCache) {
-	one := getScopedOutput(uuid, domains, db, cache)
+	one := getScopedOutput(uuid, domains, false, db, cache)
 
 	blueLine()
 	fmt.Fprintf(color.Output, "%s\t%s%s%s\n%s\t%s%s%s\n", blue("Between"),
@@ -210,7 +236,7 @@ func printOneEvent(uuid, domains []string, earliest, latest time.Time, db *netma
 
 func cumulativeOutput(uuids, domains []string, ea, la []time.Time, db *netmap.Graph, cache *requests.ASNCache) {
 	idx := len(uuids) - 1
-	cum := getScopedOutput(uuids[:idx], domains, db, cache)
+	cum := getScopedOutput(uuids[:idx], domains, false, db, cache)
 
 	blueLine()
 	fmt.Fprintf(color.Output, "%s\t%s%s%s\n%s\t%s%s%s\n", blue("Between"),
@@ -219,7 +245,7 @@ func cumulativeOutput(uuids, domains []string, ea, la []time.Time, db *netmap.Gr
 	blueLine()
 
 	var updates bool
-	out := getScopedOutput([]string{uuids[idx]}, domains, db, cache)
+	out := getScopedOutput([]string{uuids[idx]}, domains, false, db, cache)
 	for _, d := range diffEnumOutput(cum, out) {
 		updates = true
 		fmt.Fprintln(color.Output, d)
@@ -229,10 +255,10 @@ func cumulativeOutput(uuids, domains []string, ea, la []time.Time, db *netmap.Gr
 	}
 }
 
-func getScopedOutput(uuids, domains []string, db *netmap.Graph, cache *requests.ASNCache) []*requests.Output {
+func getScopedOutput(uuids, domains []string, asninfo bool, db *netmap.Graph, cache *requests.ASNCache) []*requests.Output {
 	var output []*requests.Output
 
-	for _, out := range getEventOutput(context.TODO(), uuids, false, db, cache) {
+	for _, out := range getEventOutput(context.TODO(), uuids, asninfo, db, cache) {
 		if len(domains) > 0 && !domainNameInScope(out.Name, domains) {
 			continue
 		}
@@ -260,8 +286,8 @@ func completeHistoryOutput(uuids, domains []string, ea, la []time.Time, db *netm
 		blueLine()
 
 		var updates bool
-		out1 := getScopedOutput([]string{prev}, domains, db, cache)
-		out2 := getScopedOutput([]string{uuid}, domains, db, cache)
+		out1 := getScopedOutput([]string{prev}, domains, false, db, cache)
+		out2 := getScopedOutput([]string{uuid}, domains, false, db, cache)
 		for _, d := range diffEnumOutput(out1, out2) {
 			updates = true
 			fmt.Fprintln(color.Output, d)
@@ -273,6 +299,35 @@ func completeHistoryOutput(uuids, domains []string, ea, la []time.Time, db *netm
 	}
 }
 
+// notifyChanges posts the summary of changes between the latest enumeration and those before it.
+func notifyChanges(cfg *config.Config, uuids, domains []string, db *netmap.Graph, cache *requests.ASNCache) {
+	notifiers, err := output.NewNotifiers(cfg)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if len(notifiers) == 0 {
+		r.Fprintln(color.Error, "No chat services were configured in the outputs section")
+		os.Exit(1)
+	}
+
+	var older []*requests.Output
+	idx := len(uuids) - 1
+	if idx > 0 {
+		older = getScopedOutput(uuids[:idx], domains, true, db, cache)
+	}
+	// The ASN information provides the netblocks of the addresses
+	summary := output.NewSummary(domains, older, getScopedOutput([]string{uuids[idx]}, domains, true, db, cache))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, summary); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+		}
+	}
+}
+
 func blueLine() {
 	for i := 0; i < 8; i++ {
 		b.Fprint(color.Output, "----------")
@@ -281,6 +336,27 @@ func blueLine() {
 }
 
 func diffEnumOutput(older, newer []*requests.Output) []string {
+	var diff []string
+
+	for _, rec := range compareEnumOutput(older, newer) {
+		prev := strings.Join(rec.PreviousAddresses, ",")
+		cur := strings.Join(rec.CurrentAddresses, ",")
+
+		switch rec.Type {
+		case trackAdded:
+			diff = append(diff, fmt.Sprintf("%s%s %s", blue("Found: "), green(rec.Name), yellow(cur)))
+		case trackMoved:
+			diff = append(diff, fmt.Sprintf("%s%s\n\t%s\t%s\n\t%s\t%s", blue("Moved: "),
+				green(rec.Name), blue(" from "), yellow(prev), blue(" to "), yellow(cur)))
+		case trackRemoved:
+			diff = append(diff, fmt.Sprintf("%s%s %s", blue("Removed: "), green(rec.Name), yellow(prev)))
+		}
+	}
+	return diff
+}
+
+// compareEnumOutput returns the names found, moved and removed by the newer output, sorted by name within each type.
+func compareEnumOutput(older, newer []*requests.Output) []*trackRecord {
 	oldmap := make(map[string]*requests.Output)
 	newmap := make(map[string]*requests.Output)
 
@@ -291,41 +367,56 @@ func diffEnumOutput(older, newer []*requests.Output) []string {
 		newmap[o.Name] = o
 	}
 
-	var diff []string
+	var added, moved, removed []*trackRecord
 	for name, o := range newmap {
 		o2, found := oldmap[name]
 		if !found {
-			diff = append(diff, fmt.Sprintf("%s%s %s", blue("Found: "),
-				green(name), yellow(lineOfAddresses(o.Addresses))))
+			added = append(added, &trackRecord{
+				Type:             trackAdded,
+				Name:             name,
+				Domain:           o.Domain,
+				CurrentAddresses: addressStrings(o.Addresses),
+			})
 			continue
 		}
 
 		if !compareAddresses(o.Addresses, o2.Addresses) {
-			diff = append(diff, fmt.Sprintf("%s%s\n\t%s\t%s\n\t%s\t%s", blue("Moved: "),
-				green(name), blue(" from "), yellow(lineOfAddresses(o2.Addresses)),
-				blue(" to "), yellow(lineOfAddresses(o.Addresses))))
+			moved = append(moved, &trackRecord{
+				Type:              trackMoved,
+				Name:              name,
+				Domain:            o.Domain,
+				PreviousAddresses: addressStrings(o2.Addresses),
+				CurrentAddresses:  addressStrings(o.Addresses),
+			})
 		}
 	}
 
 	for name, o := range oldmap {
 		if _, found := newmap[name]; !found {
-			diff = append(diff, fmt.Sprintf("%s%s %s", blue("Removed: "),
-				green(name), yellow(lineOfAddresses(o.Addresses))))
+			removed = append(removed, &trackRecord{
+				Type:              trackRemoved,
+				Name:              name,
+				Domain:            o.Domain,
+				PreviousAddresses: addressStrings(o.Addresses),
+			})
 		}
 	}
-	return diff
+
+	var records []*trackRecord
+	for _, recs := range [][]*trackRecord{added, moved, removed} {
+		sort.Slice(recs, func(i, j int) bool { return recs[i].Name < recs[j].Name })
+		records = append(records, recs...)
+	}
+	return records
 }
 
-func lineOfAddresses(addrs []requests.AddressInfo) string {
-	var line string
+func addressStrings(addrs []requests.AddressInfo) []string {
+	var values []string
 
-	for i, addr := range addrs {
-		if i != 0 {
-			line = line + ","
-		}
-		line = line + addr.Address.String()
+	for _, addr := range addrs {
+		values = append(values, addr.Address.String())
 	}
-	return line
+	return values
 }
 
 func compareAddresses(addr1, addr2 []requests.AddressInfo) bool {
@@ -344,3 +435,112 @@ func compareAddresses(addr1, addr2 []requests.AddressInfo) bool {
 	}
 	return true
 }
+
+// The types of changes described by the track JSON output
+const (
+	trackAdded   = "added"
+	trackMoved   = "moved"
+	trackRemoved = "removed"
+)
+
+type trackRecord struct {
+	Type              string   `json:"type"`
+	Name              string   `json:"name"`
+	Domain            string   `json:"domain"`
+	PreviousAddresses []string `json:"previous_addresses,omitempty"`
+	CurrentAddresses  []string `json:"current_addresses,omitempty"`
+	PreviousSeen      string   `json:"previous_seen,omitempty"`
+	CurrentSeen       string   `json:"current_seen,omitempty"`
+}
+
+type trackComparison struct {
+	Previous []*jsonEvent   `json:"previous"`
+	Current  *jsonEvent     `json:"current"`
+	Records  []*trackRecord `json:"records"`
+}
+
+type trackOutput struct {
+	Domains     []string           `json:"domains"`
+	Comparisons []*trackComparison `json:"comparisons"`
+}
+
+// writeTrackJSON writes the same comparisons as the text output, with the addresses and seen times of each change.
+func writeTrackJSON(path string, uuids, domains []string, history bool, db *netmap.Graph, cache *requests.ASNCache) {
+	output := trackOutput{Domains: domains}
+	times := eventTimes(uuids, db)
+
+	idx := len(uuids) - 1
+	switch {
+	case len(uuids) == 1:
+		output.Comparisons = append(output.Comparisons, trackCompare(nil, uuids[0], domains, times, db, cache))
+	case history:
+		for i := 1; i < len(uuids); i++ {
+			output.Comparisons = append(output.Comparisons,
+				trackCompare(uuids[i-1:i], uuids[i], domains, times, db, cache))
+		}
+	default:
+		output.Comparisons = append(output.Comparisons, trackCompare(uuids[:idx], uuids[idx], domains, times, db, cache))
+	}
+
+	var err error
+	jsonptr := os.Stdout
+	// Write to STDOUT and not a file if named "-"
+	if path != "-" {
+		jsonptr, err = os.Create(path)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the JSON output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer jsonptr.Close()
+	}
+
+	if err := json.NewEncoder(jsonptr).Encode(output); err != nil {
+		r.Fprintf(color.Error, "Failed to write the JSON output: %v\n", err)
+		os.Exit(1)
+	}
+	_ = jsonptr.Sync()
+}
+
+func trackCompare(previous []string, current string, domains []string,
+	times map[string][2]time.Time, db *netmap.Graph, cache *requests.ASNCache) *trackComparison {
+	comp := &trackComparison{Previous: []*jsonEvent{}, Records: []*trackRecord{}}
+
+	prevTimes := make(map[string][2]time.Time, len(previous))
+	for _, uuid := range previous {
+		prevTimes[uuid] = times[uuid]
+		comp.Previous = append(comp.Previous, trackEvent(uuid, times))
+	}
+	curTimes := map[string][2]time.Time{current: times[current]}
+	comp.Current = trackEvent(current, times)
+
+	var older []*requests.Output
+	if len(previous) > 0 {
+		older = getScopedOutput(previous, domains, false, db, cache)
+	}
+	newer := getScopedOutput([]string{current}, domains, false, db, cache)
+
+	for _, rec := range compareEnumOutput(older, newer) {
+		if rec.Type != trackAdded {
+			if _, last := nameSeen(rec.Name, prevTimes, db); !last.IsZero() {
+				rec.PreviousSeen = last.Format(timeFormat)
+			}
+		}
+		if rec.Type != trackRemoved {
+			if first, _ := nameSeen(rec.Name, curTimes, db); !first.IsZero() {
+				rec.CurrentSeen = first.Format(timeFormat)
+			}
+		}
+		comp.Records = append(comp.Records, rec)
+	}
+	return comp
+}
+
+func trackEvent(uuid string, times map[string][2]time.Time) *jsonEvent {
+	t := times[uuid]
+
+	return &jsonEvent{
+		UUID:   uuid,
+		Start:  t[0].Format(timeFormat),
+		Finish: t[1].Format(timeFormat),
+	}
+}
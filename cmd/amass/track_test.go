@@ -0,0 +1,118 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caffix/netmap"
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+// testTrackGraph returns the graph of two enumerations, where the second finds api.owasp.org,
+// moves www.owasp.org and no longer finds mail.owasp.org.
+func testTrackGraph(t *testing.T) (*netmap.Graph, []string) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	t.Cleanup(func() { g.Close() })
+
+	records := []struct {
+		event      int
+		name, addr string
+	}{
+		{event: 0, name: "www.owasp.org", addr: "192.0.2.1"},
+		{event: 0, name: "mail.owasp.org", addr: "192.0.2.2"},
+		{event: 0, name: "ftp.owasp.org", addr: "192.0.2.4"},
+		{event: 1, name: "www.owasp.org", addr: "192.0.2.10"},
+		{event: 1, name: "api.owasp.org", addr: "192.0.2.3"},
+		{event: 1, name: "ftp.owasp.org", addr: "192.0.2.4"},
+	}
+	uuids := []string{uuid.New().String(), uuid.New().String()}
+	for _, rec := range records {
+		if err := g.UpsertA(ctx, rec.name, rec.addr, "DNS", uuids[rec.event]); err != nil {
+			t.Fatalf("failed to insert %s: %v", rec.name, err)
+		}
+	}
+	return g, uuids
+}
+
+func TestDiffEnumOutput(t *testing.T) {
+	g, uuids := testTrackGraph(t)
+	domains := []string{"owasp.org"}
+
+	defer func(nocolor bool) { color.NoColor = nocolor }(color.NoColor)
+	color.NoColor = true
+
+	older := getScopedOutput(uuids[:1], domains, false, g, requests.NewASNCache())
+	newer := getScopedOutput(uuids[1:], domains, false, g, requests.NewASNCache())
+	expected := []string{
+		"Found: api.owasp.org 192.0.2.3",
+		"Moved: www.owasp.org\n\t from \t192.0.2.1\n\t to \t192.0.2.10",
+		"Removed: mail.owasp.org 192.0.2.2",
+	}
+
+	diff := diffEnumOutput(older, newer)
+	if strings.Join(diff, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("diffEnumOutput() = %q, expected %q", diff, expected)
+	}
+	if diff := diffEnumOutput(newer, newer); len(diff) != 0 {
+		t.Errorf("diffEnumOutput() of the same output = %q", diff)
+	}
+}
+
+func TestWriteTrackJSON(t *testing.T) {
+	g, uuids := testTrackGraph(t)
+	domains := []string{"owasp.org"}
+
+	path := filepath.Join(t.TempDir(), "track.json")
+	writeTrackJSON(path, uuids, domains, false, g, requests.NewASNCache())
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the JSON output: %v", err)
+	}
+
+	var out trackOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("failed to decode the JSON output: %v", err)
+	}
+	if len(out.Comparisons) != 1 {
+		t.Fatalf("writeTrackJSON() wrote %d comparisons", len(out.Comparisons))
+	}
+
+	comp := out.Comparisons[0]
+	if comp.Current == nil || comp.Current.UUID != uuids[1] || len(comp.Previous) != 1 || comp.Previous[0].UUID != uuids[0] {
+		t.Errorf("the comparison has the wrong enumerations: %v, %v", comp.Previous, comp.Current)
+	}
+
+	expected := []trackRecord{
+		{Type: trackAdded, Name: "api.owasp.org", Domain: "owasp.org", CurrentAddresses: []string{"192.0.2.3"}},
+		{Type: trackMoved, Name: "www.owasp.org", Domain: "owasp.org",
+			PreviousAddresses: []string{"192.0.2.1"}, CurrentAddresses: []string{"192.0.2.10"}},
+		{Type: trackRemoved, Name: "mail.owasp.org", Domain: "owasp.org", PreviousAddresses: []string{"192.0.2.2"}},
+	}
+	if len(comp.Records) != len(expected) {
+		t.Fatalf("writeTrackJSON() wrote the records %s", data)
+	}
+	for i, rec := range comp.Records {
+		exp := expected[i]
+
+		if rec.Type != exp.Type || rec.Name != exp.Name || rec.Domain != exp.Domain ||
+			strings.Join(rec.PreviousAddresses, ",") != strings.Join(exp.PreviousAddresses, ",") ||
+			strings.Join(rec.CurrentAddresses, ",") != strings.Join(exp.CurrentAddresses, ",") {
+			t.Errorf("record %d = %+v, expected %+v", i, rec, exp)
+		}
+		if (rec.PreviousSeen == "") != (rec.Type == trackAdded) || (rec.CurrentSeen == "") != (rec.Type == trackRemoved) {
+			t.Errorf("record %d has the seen times %q and %q", i, rec.PreviousSeen, rec.CurrentSeen)
+		}
+	}
+}
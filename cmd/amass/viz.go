@@ -19,18 +19,22 @@ import (
 )
 
 const (
-	vizUsageMsg = "viz -d3|-dot||-gexf|-graphistry|-maltego [options]"
+	vizUsageMsg = "viz -cytoscape|-d3|-dot|-gexf|-graphistry|-maltego|-mermaid [options]"
 )
 
 type vizArgs struct {
-	Domains *stringset.Set
-	Enum    int
-	Options struct {
+	Domains   *stringset.Set
+	Enum      int
+	Subtree   string
+	Workspace string
+	Options   struct {
+		Cytoscape  bool
 		D3         bool
 		DOT        bool
 		GEXF       bool
 		Graphistry bool
 		Maltego    bool
+		Mermaid    bool
 		NoColor    bool
 		Silent     bool
 	}
@@ -65,11 +69,15 @@ func runVizCommand(clArgs []string) {
 	vizCommand.StringVar(&args.Filepaths.Input, "i", "", "The Amass data operations JSON file")
 	vizCommand.StringVar(&args.Filepaths.Output, "o", "", "Path to the directory for output files being generated")
 	vizCommand.StringVar(&args.Filepaths.AllFilePrefix, "oA", "", "Path prefix used for naming all output files")
+	vizCommand.StringVar(&args.Workspace, "workspace", "", "Name of the workspace isolating the enumerations in the graph database")
+	vizCommand.BoolVar(&args.Options.Cytoscape, "cytoscape", false, "Generate the Cytoscape.js JSON file")
 	vizCommand.BoolVar(&args.Options.D3, "d3", false, "Generate the D3 v4 force simulation HTML file")
 	vizCommand.BoolVar(&args.Options.DOT, "dot", false, "Generate the DOT output file")
 	vizCommand.BoolVar(&args.Options.GEXF, "gexf", false, "Generate the Gephi Graph Exchange XML Format (GEXF) file")
 	vizCommand.BoolVar(&args.Options.Graphistry, "graphistry", false, "Generate the Graphistry JSON file")
 	vizCommand.BoolVar(&args.Options.Maltego, "maltego", false, "Generate the Maltego csv file")
+	vizCommand.BoolVar(&args.Options.Mermaid, "mermaid", false, "Generate the Mermaid diagram file")
+	vizCommand.StringVar(&args.Subtree, "subtree", "", "Only visualize the subtree of names at or below the provided name")
 	vizCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
 	vizCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
 
@@ -93,8 +101,8 @@ func runVizCommand(clArgs []string) {
 		color.Error = io.Discard
 	}
 	// Make sure at least one graph file format has been identified on the command-line
-	if !args.Options.D3 && !args.Options.DOT &&
-		!args.Options.GEXF && !args.Options.Graphistry && !args.Options.Maltego {
+	if !args.Options.Cytoscape && !args.Options.D3 && !args.Options.DOT &&
+		!args.Options.GEXF && !args.Options.Graphistry && !args.Options.Maltego && !args.Options.Mermaid {
 		r.Fprintln(color.Error, "At least one file format must be selected")
 		os.Exit(1)
 	}
@@ -113,6 +121,9 @@ func runVizCommand(clArgs []string) {
 		if args.Filepaths.Directory == "" {
 			args.Filepaths.Directory = config.OutputDirectory(cfg.Dir)
 		}
+		if args.Workspace == "" {
+			args.Workspace = cfg.Workspace
+		}
 		if args.Domains.Len() == 0 {
 			args.Domains.InsertMany(cfg.Domains()...)
 		}
@@ -128,7 +139,7 @@ func runVizCommand(clArgs []string) {
 	}
 	defer db.Close()
 	// Create the in-memory graph database
-	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), db)
+	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), args.Workspace, db)
 	if err != nil {
 		r.Fprintln(color.Error, err.Error())
 		os.Exit(1)
@@ -152,6 +163,13 @@ func runVizCommand(clArgs []string) {
 	}
 	// Obtain the visualization nodes & edges from the graph
 	nodes, edges := viz.VizData(context.Background(), memDB, uuids)
+	if args.Subtree != "" {
+		nodes, edges = viz.Subtree(nodes, edges, args.Subtree)
+		if len(nodes) == 0 {
+			r.Fprintf(color.Error, "Failed to find %s in the enumeration data\n", args.Subtree)
+			os.Exit(1)
+		}
+	}
 	// Get the directory to save the files into
 	dir := args.Filepaths.Directory
 
@@ -168,6 +186,19 @@ func runVizCommand(clArgs []string) {
 		}
 		dir = args.Filepaths.Output
 	}
+	// The screenshot paths are relative to the directory containing the graph database
+	for i, n := range nodes {
+		if n.Screenshot == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, filepath.Join(args.Filepaths.Directory, n.Screenshot)); err == nil {
+			nodes[i].Screenshot = filepath.ToSlash(rel)
+		}
+	}
+	if args.Options.Cytoscape {
+		path := filepath.Join(dir, prefix+"_cytoscape.json")
+		err = writeGraphOutputFile("cytoscape", path, nodes, edges)
+	}
 	if args.Options.D3 {
 		path := filepath.Join(dir, prefix+".html")
 		err = writeGraphOutputFile("d3", path, nodes, edges)
@@ -188,6 +219,13 @@ func runVizCommand(clArgs []string) {
 		path := filepath.Join(dir, prefix+"_maltego.csv")
 		err = writeGraphOutputFile("maltego", path, nodes, edges)
 	}
+	if args.Options.Mermaid {
+		if len(edges) > viz.MermaidMaxEdges {
+			fgY.Fprintf(color.Error, "The Mermaid diagram has %d edges, more than most renderers display by default; use -subtree to reduce it\n", len(edges))
+		}
+		path := filepath.Join(dir, prefix+".mmd")
+		err = writeGraphOutputFile("mermaid", path, nodes, edges)
+	}
 	if err != nil {
 		r.Fprintf(color.Error, "Failed to write the output file: %v\n", err)
 		os.Exit(1)
@@ -208,6 +246,8 @@ func writeGraphOutputFile(t string, path string, nodes []viz.Node, edges []viz.E
 	_, _ = f.Seek(0, 0)
 
 	switch t {
+	case "cytoscape":
+		err = viz.WriteCytoscapeData(f, nodes, edges)
 	case "d3":
 		err = viz.WriteD3Data(f, nodes, edges)
 	case "dot":
@@ -218,6 +258,8 @@ func writeGraphOutputFile(t string, path string, nodes []viz.Node, edges []viz.E
 		err = viz.WriteGraphistryData(f, nodes, edges)
 	case "maltego":
 		viz.WriteMaltegoData(f, nodes, edges)
+	case "mermaid":
+		err = viz.WriteMermaidData(f, nodes, edges)
 	}
 	return err
 }
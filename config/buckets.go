@@ -0,0 +1,35 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+
+	"github.com/caffix/stringset"
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadBucketSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("buckets")
+	if err != nil {
+		return nil
+	}
+
+	c.Buckets = sec.Key("enabled").MustBool(true)
+	if !c.Buckets {
+		return nil
+	}
+
+	if sec.HasKey("keyword") {
+		for _, k := range sec.Key("keyword").ValueWithShadows() {
+			if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+				c.BucketKeywords = append(c.BucketKeywords, k)
+			}
+		}
+	}
+
+	c.BucketKeywords = stringset.Deduplicate(c.BucketKeywords)
+	return nil
+}
@@ -0,0 +1,35 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestConfigloadBucketSettings(t *testing.T) {
+	c := NewConfig()
+	cfg, err := ini.LoadSources(ini.LoadOptions{
+		Insensitive:  true,
+		AllowShadows: true,
+	}, []byte(`
+	[buckets]
+	enabled = true
+	keyword = Acme
+	keyword = acme
+	keyword = acmecorp
+	`))
+	if err != nil {
+		t.Fatalf("Failed to load the test configuration: %v", err)
+	}
+
+	if err := c.loadBucketSettings(cfg); err != nil {
+		t.Fatalf("Config.loadBucketSettings() error = %v", err)
+	}
+	if !c.Buckets || len(c.BucketKeywords) != 2 {
+		t.Errorf("Config.loadBucketSettings() enabled = %t, keywords = %v", c.Buckets, c.BucketKeywords)
+	}
+}
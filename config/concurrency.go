@@ -0,0 +1,39 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadConcurrencySettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("concurrency")
+	if err != nil {
+		return nil
+	}
+
+	for _, setting := range []struct {
+		key   string
+		value *int
+	}{
+		{key: "resolution", value: &c.ResolutionWorkers},
+		{key: "data_sources", value: &c.DataSourceWorkers},
+		{key: "brute_forcing", value: &c.BruteForceWorkers},
+		{key: "graph_writes", value: &c.GraphWriteWorkers},
+	} {
+		if !sec.HasKey(setting.key) {
+			continue
+		}
+
+		n, err := sec.Key(setting.key).Int()
+		if err != nil || n < 0 {
+			return fmt.Errorf("the concurrency %s setting must be a non-negative number", setting.key)
+		}
+		*setting.value = n
+	}
+	return nil
+}
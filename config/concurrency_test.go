@@ -0,0 +1,43 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestConfigloadConcurrencySettings(t *testing.T) {
+	load := func(data string) (*Config, error) {
+		c := NewConfig()
+		cfg, err := ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(data))
+		if err != nil {
+			t.Fatalf("Failed to load the test configuration: %v", err)
+		}
+		return c, c.loadConcurrencySettings(cfg)
+	}
+
+	c, err := load(`
+	[concurrency]
+	resolution = 2000
+	data_sources = 8
+	brute_forcing = 1
+	graph_writes = 4
+	`)
+	if err != nil {
+		t.Fatalf("Config.loadConcurrencySettings() error = %v", err)
+	}
+	if c.ResolutionWorkers != 2000 || c.DataSourceWorkers != 8 || c.BruteForceWorkers != 1 || c.GraphWriteWorkers != 4 {
+		t.Errorf("Config.loadConcurrencySettings() set %d, %d, %d and %d workers",
+			c.ResolutionWorkers, c.DataSourceWorkers, c.BruteForceWorkers, c.GraphWriteWorkers)
+	}
+
+	for _, data := range []string{"[concurrency]\nresolution = -1", "[concurrency]\ngraph_writes = many"} {
+		if _, err := load(data); err == nil {
+			t.Errorf("Config.loadConcurrencySettings() accepted %q", data)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package config
 import (
 	"bufio"
 	"compress/gzip"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -14,6 +15,7 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -23,6 +25,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/owasp-amass/amass/v3/net/tor"
 	"github.com/owasp-amass/amass/v3/resources"
 	"github.com/caffix/stringset"
 	"github.com/go-ini/ini"
@@ -36,6 +39,12 @@ const (
 	systemCfgDir   = "/etc"
 )
 
+// The defaults of the Bloom filters tracking the names and addresses already seen
+const (
+	defaultFilterCapacity          = 1000000
+	defaultFilterFalsePositiveRate = 0.01
+)
+
 // Updater allows an object to implement a method that updates a configuration.
 type Updater interface {
 	OverrideConfig(*Config) error
@@ -60,21 +69,76 @@ type Config struct {
 	// Alternative directory for scripts provided by the user
 	ScriptsDirectory string `ini:"scripts_directory"`
 
+	// Alternative directory for the data source and output plugins provided by the user
+	PluginsDirectory string `ini:"plugins_directory"`
+
+	// The workspace that isolates the enumerations within the graph databases
+	Workspace string `ini:"workspace"`
+
+	// The format of the log file: text or json
+	LogFormat string `ini:"log_format"`
+
 	// The graph databases used by the system / enumerations
 	GraphDBs []*Database
 
+	// The external systems receiving the enumeration findings
+	Outputs []*OutputSink
+
+	// The scopes enumerated by the daemon mode at the times selected by cron expressions
+	Schedules []*Schedule
+
 	// The maximum number of concurrent DNS queries
 	MaxDNSQueries int `ini:"maximum_dns_queries"`
 
+	// The number of requests each data source queue keeps in memory before spilling to disk
+	QueueMemoryLimit int `ini:"queue_memory_limit"`
+
+	// The number of items waiting in the pipeline and the megabytes of heap in use that pause
+	// the release of new names to the enumeration
+	QueueBudget  int `ini:"queue_budget"`
+	MemoryBudget int `ini:"memory_budget"`
+
+	// The minutes spent guessing the names of each root domain before its brute forcing and
+	// alteration names are dropped
+	DomainTimeBudget int `ini:"domain_time_budget"`
+
+	// Will the brute forcing queries be sent to the trusted resolvers in batches, and at what rate?
+	BatchDNS bool
+	BatchQPS int
+
+	// The worker pool sizes of the enumeration subsystems, where 0 selects the default
+	ResolutionWorkers int
+	DataSourceWorkers int
+	BruteForceWorkers int
+	GraphWriteWorkers int
+
+	// The number of elements and false-positive rate of the Bloom filters tracking what was already seen
+	FilterCapacity          int     `ini:"filter_capacity"`
+	FilterFalsePositiveRate float64 `ini:"filter_false_positive_rate"`
+
+	// The SOCKS5 proxy URL, with an optional username and password, used by the data sources without
+	// a proxy of their own, and by the DNS queries of the enumeration when they are sent over TCP
+	SOCKS5Proxy string `ini:"socks5_proxy"`
+	SOCKS5DNS   bool   `ini:"socks5_dns"`
+
+	// Will the published address ranges of the cloud providers be downloaded and used to tag the addresses?
+	CloudRanges bool `ini:"cloud_ranges"`
+
 	// Names provided to seed the enumeration
 	ProvidedNames []string
 
+	// Names imported from the output of other tools to seed the enumeration
+	ImportedNames []string
+
 	// The IP addresses specified as in scope
 	Addresses []net.IP
 
 	// CIDR that is in scope
 	CIDRs []*net.IPNet
 
+	// The address ranges excluded from the scope
+	ExcludedCIDRs []*net.IPNet
+
 	// ASNs specified as in scope
 	ASNs []int
 
@@ -106,15 +170,73 @@ type Config struct {
 	EditDistance   int
 	AltWordlist    []string
 
+	// The top-level domains checked for in-scope labels during TLD expansion
+	ExpansionTLDs []string
+
+	// Will CNAME targets be checked for subdomain takeovers?
+	Takeovers bool
+
+	// Paths to files containing additional subdomain takeover fingerprints
+	TakeoverFingerprints []string
+
+	// Will DNS records referencing nonexistent or unallocated resources be reported?
+	DanglingRecords bool
+
+	// Will the resolved names be probed over HTTP and HTTPS?
+	HTTPProbes bool
+
+	// Will web technologies be identified in the HTTP probe responses?
+	TechDetection bool
+
+	// Paths to files containing additional web technology fingerprints
+	TechFingerprints []string
+
+	// Will the web application firewalls protecting the probed pages be identified?
+	WAFDetection bool
+
+	// Will screenshots of the web pages be captured using a headless browser?
+	Screenshots bool
+
+	// The maximum number of screenshots captured at the same time
+	ScreenshotConcurrency int
+
+	// Will the resolved addresses be scanned for open TCP ports?
+	PortScans bool
+
+	// The number of most commonly open ports included in the scans
+	ScanTopPorts int
+
+	// Additional ports included in the scans
+	ScanPorts []int
+
+	// Will addresses belonging to content delivery networks be scanned?
+	ScanCDNs bool
+
+	// Will the banners of the services listening on the open ports be collected?
+	ScanBanners bool
+
+	// Will cloud storage bucket names be guessed and probed?
+	Buckets bool
+
+	// Organization keywords used to derive the candidate bucket names
+	BucketKeywords []string
+
 	// Only access the data sources for names and return results?
 	Passive bool
 
+	// Will the passive mode refuse all traffic toward the target and audit the endpoints contacted?
+	StrictPassive bool
+
 	// Determines if zone transfers will be attempted
 	Active bool
 
+	// Will the names discovered by previous enumerations in the workspace be skipped?
+	NewOnly bool
+
 	// A blacklist of subdomain names that will not be investigated
-	Blacklist     []string
-	blacklistLock sync.Mutex
+	Blacklist      []string
+	blacklistLock  sync.Mutex
+	blacklistGlobs map[string]*regexp.Regexp
 
 	// A list of data sources that should not be utilized
 	SourceFilter struct {
@@ -125,6 +247,27 @@ type Config struct {
 	// The minimum number of minutes that data source responses will be reused
 	MinimumTTL int
 
+	// Will the data source responses be cached in the output directory?
+	ResponseCache bool
+
+	// The HTTP(S) proxy used by the data sources without a proxy of their own
+	DataSourceProxy string
+
+	// The TLS settings of the data source requests: the CA bundle trusted along with the system roots,
+	// the client certificate and key, and whether the server certificates are left unverified
+	DataSourceCAFile     string
+	DataSourceClientCert string
+	DataSourceClientKey  string
+	DataSourceInsecure   bool
+	dataSourceTLS        *tls.Config
+	dataSourceTLSLock    sync.Mutex
+
+	// Will the data source requests be routed through Tor, and how is the Tor client reached?
+	Tor                bool
+	TorSOCKSAddr       string
+	TorControlAddr     string
+	TorControlPassword string
+
 	// Type of DNS records to query for
 	RecordTypes []string
 
@@ -143,8 +286,15 @@ type Config struct {
 	// The regular expressions for the root domains added to the enumeration
 	regexps map[string]*regexp.Regexp
 
+	// The regular expressions selecting the subdomain names in scope, and those left out of scope
+	scopeIncludes []*regexp.Regexp
+	scopeExcludes []*regexp.Regexp
+
 	// The data source configurations
 	datasrcConfigs map[string]*DataSourceConfig
+
+	// The settings provided to the external plugins, keyed by the plugin name
+	pluginSettings map[string]map[string]string
 }
 
 // NewConfig returns a default configuration object.
@@ -163,12 +313,37 @@ func NewConfig() *Config {
 		MinForWordFlip: 2,
 		EditDistance:   1,
 		Recursive:      true,
+		TechDetection:  true,
+		WAFDetection:   true,
+		ScanBanners:    true,
 		MinimumTTL:     1440,
+		ResponseCache:  true,
+		CloudRanges:    true,
 		ResolversQPS:   DefaultQueriesPerPublicResolver,
 		TrustedQPS:     DefaultQueriesPerBaselineResolver,
+		// Each page captured loads in its own headless browser tab
+		ScreenshotConcurrency: 5,
+		ScanTopPorts:          100,
+		TorSOCKSAddr:          tor.DefaultSOCKSAddr,
+		TorControlAddr:        tor.DefaultControlAddr,
 	}
 }
 
+// FilterSettings returns the capacity and false-positive rate of the Bloom filters tracking
+// the names and addresses already seen, applying the defaults to the settings not provided.
+func (c *Config) FilterSettings() (uint, float64) {
+	capacity := uint(defaultFilterCapacity)
+	if c.FilterCapacity > 0 {
+		capacity = uint(c.FilterCapacity)
+	}
+
+	rate := defaultFilterFalsePositiveRate
+	if c.FilterFalsePositiveRate > 0 {
+		rate = c.FilterFalsePositiveRate
+	}
+	return capacity, rate
+}
+
 // UpdateConfig allows the provided Updater to update the current configuration.
 func (c *Config) UpdateConfig(update Updater) error {
 	return update.OverrideConfig(c)
@@ -193,6 +368,9 @@ func (c *Config) CheckSettings() error {
 			}
 		}
 	}
+	if c.StrictPassive && !c.Passive {
+		return errors.New("the strict passive mode requires the passive mode")
+	}
 	if c.Passive && c.Active {
 		return errors.New("active enumeration cannot be performed without DNS resolution")
 	}
@@ -222,19 +400,58 @@ func (c *Config) CheckSettings() error {
 	return err
 }
 
-// LoadSettings parses settings from an .ini file and assigns them to the Config.
+// LoadSettings parses settings from an .ini file and assigns them to the Config. The AMASS_
+// environment variables override the settings of the file, and can be used without one.
 func (c *Config) LoadSettings(path string) error {
+	environ := environOverrides()
+
+	var source interface{} = path
+	// The environment variables can provide the settings without a configuration file
+	if path == "" && len(environ) > 0 {
+		source = []byte("[data_sources]")
+	}
+
 	cfg, err := ini.LoadSources(ini.LoadOptions{
 		Insensitive:  true,
 		AllowShadows: true,
-	}, path)
+	}, source)
 	if err != nil {
 		return fmt.Errorf("failed to load the configuration file: %v", err)
 	}
+	if err := applyEnvironOverrides(cfg, environ); err != nil {
+		return err
+	}
 	// Get the easy ones out of the way using mapping
 	if err = cfg.MapTo(c); err != nil {
 		return fmt.Errorf("error mapping configuration settings to internal values: %v", err)
 	}
+	switch c.LogFormat = strings.ToLower(c.LogFormat); c.LogFormat {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("the log format %q is not supported, use text or json", c.LogFormat)
+	}
+	if c.QueueMemoryLimit < 0 {
+		return errors.New("the queue_memory_limit setting cannot be negative")
+	}
+	if c.QueueBudget < 0 || c.MemoryBudget < 0 {
+		return errors.New("the queue_budget and memory_budget settings cannot be negative")
+	}
+	if c.DomainTimeBudget < 0 {
+		return errors.New("the domain_time_budget setting cannot be negative")
+	}
+	if c.FilterCapacity < 0 {
+		return errors.New("the filter_capacity setting cannot be negative")
+	}
+	if r := c.FilterFalsePositiveRate; r < 0 || r >= 1 {
+		return errors.New("the filter_false_positive_rate setting must be between 0 and 1")
+	}
+	if c.SOCKS5Proxy != "" {
+		if u, err := url.Parse(c.SOCKS5Proxy); err != nil || u.Scheme != "socks5" || u.Host == "" {
+			return fmt.Errorf("the socks5_proxy setting %s must be a socks5 URL", c.SOCKS5Proxy)
+		}
+	} else if c.SOCKS5DNS {
+		return errors.New("the socks5_dns setting requires the socks5_proxy setting")
+	}
 	// Attempt to load a special mode of operation specified by the user
 	if cfg.Section(ini.DefaultSection).HasKey("mode") {
 		mode := cfg.Section(ini.DefaultSection).Key("mode").String()
@@ -249,10 +466,22 @@ func (c *Config) LoadSettings(path string) error {
 	loads := []func(cfg *ini.File) error{
 		c.loadResolverSettings,
 		c.loadScopeSettings,
+		c.loadConcurrencySettings,
 		c.loadAlterationSettings,
 		c.loadBruteForceSettings,
+		c.loadTLDExpansionSettings,
+		c.loadTakeoverSettings,
+		c.loadDanglingSettings,
+		c.loadHTTPProbeSettings,
+		c.loadScreenshotSettings,
+		c.loadPortScanSettings,
+		c.loadBucketSettings,
 		c.loadDatabaseSettings,
+		c.loadOutputSettings,
+		c.loadScheduleSettings,
+		c.loadPluginSettings,
 		c.loadDataSourceSettings,
+		c.loadTorSettings,
 	}
 	for _, load := range loads {
 		if err := load(cfg); err != nil {
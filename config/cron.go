@@ -0,0 +1,207 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The cron expressions provided by the common shorthand names
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// The names accepted in place of the month and day of week numbers
+var (
+	cronMonths = []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronDays   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// The period searched for the next time matched by a cron expression, which covers the leap days
+const cronSearchYears = 5
+
+// CronExpr is a parsed cron expression, with the minute, hour, day of month, month and day of week fields.
+type CronExpr struct {
+	expr    string
+	minutes uint64
+	hours   uint64
+	days    uint64
+	months  uint64
+	weekday uint64
+	// Was the day of month or the day of week field left unrestricted with an asterisk?
+	anyDay     bool
+	anyWeekday bool
+}
+
+// ParseCron returns the CronExpr for the five fields of the expression provided, or one of the
+// shorthand names, such as @daily and @hourly.
+func ParseCron(expr string) (*CronExpr, error) {
+	spec := strings.ToLower(strings.TrimSpace(expr))
+	if m, found := cronMacros[spec]; found {
+		spec = m
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("the cron expression %q must have five fields", expr)
+	}
+
+	c := &CronExpr{
+		expr:       strings.TrimSpace(expr),
+		anyDay:     strings.HasPrefix(fields[2], "*"),
+		anyWeekday: strings.HasPrefix(fields[4], "*"),
+	}
+	for i, f := range []struct {
+		name     string
+		bits     *uint64
+		min, max int
+		names    []string
+	}{
+		{"minute", &c.minutes, 0, 59, nil},
+		{"hour", &c.hours, 0, 23, nil},
+		{"day of month", &c.days, 1, 31, nil},
+		{"month", &c.months, 1, 12, cronMonths},
+		// Sunday can also be selected with the number seven
+		{"day of week", &c.weekday, 0, 7, cronDays},
+	} {
+		bits, err := parseCronField(fields[i], f.min, f.max, f.names)
+		if err != nil {
+			return nil, fmt.Errorf("the %s field of the cron expression %q is invalid: %v", f.name, expr, err)
+		}
+		*f.bits = bits
+	}
+	if c.weekday&(1<<7) != 0 {
+		c.weekday |= 1
+	}
+	// Expressions such as those selecting the 30th of February would never run
+	if c.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("the cron expression %q never selects a time", expr)
+	}
+	return c, nil
+}
+
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i != -1 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s < 1 {
+				return 0, fmt.Errorf("the step %q must be a positive integer", part[i+1:])
+			}
+			rng, step = part[:i], s
+		}
+
+		var first, last int
+		switch {
+		case rng == "*":
+			first, last = min, max
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+
+			var err error
+			if first, err = cronValue(bounds[0], min, max, names); err != nil {
+				return 0, err
+			}
+			if last, err = cronValue(bounds[1], min, max, names); err != nil {
+				return 0, err
+			}
+			if first > last {
+				return 0, fmt.Errorf("the range %q must be in ascending order", rng)
+			}
+		default:
+			v, err := cronValue(rng, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			// A single value with a step runs through the end of the range
+			first, last = v, v
+			if step > 1 {
+				last = max
+			}
+		}
+
+		for v := first; v <= last; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, min, max int, names []string) (int, error) {
+	if s == "" {
+		return 0, errors.New("a value is missing")
+	}
+	for i, name := range names {
+		if name != "" && s == name {
+			return i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("the value %q is not a number", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("the value %d must be between %d and %d", v, min, max)
+	}
+	return v, nil
+}
+
+// String returns the cron expression as it was provided.
+func (c *CronExpr) String() string {
+	return c.expr
+}
+
+// Next returns the first time after the time provided that matches the cron expression, in the
+// location of the time provided. The zero time is returned when no time matches within five years.
+func (c *CronExpr) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+
+	for limit := t.AddDate(cronSearchYears, 0, 0); t.Before(limit); {
+		if c.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches follows the convention of cron, where a day matching either the day of month or the
+// day of week field is selected when both fields are restricted.
+func (c *CronExpr) dayMatches(t time.Time) bool {
+	day := c.days&(1<<uint(t.Day())) != 0
+	weekday := c.weekday&(1<<uint(t.Weekday())) != 0
+
+	if c.anyDay || c.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
@@ -0,0 +1,107 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "success - every minute", expr: "* * * * *"},
+		{name: "success - lists, ranges and steps", expr: "0,30 8-18/2 1-15 */3 1-5"},
+		{name: "success - names", expr: "0 3 * jan-jun mon,fri"},
+		{name: "success - sunday as seven", expr: "0 0 * * 7"},
+		{name: "success - macro", expr: "@daily"},
+		{name: "failure - missing field", expr: "0 3 * *", wantErr: true},
+		{name: "failure - out of range", expr: "60 * * * *", wantErr: true},
+		{name: "failure - descending range", expr: "0 18-8 * * *", wantErr: true},
+		{name: "failure - zero step", expr: "*/0 * * * *", wantErr: true},
+		{name: "failure - unknown name", expr: "0 0 * * someday", wantErr: true},
+		{name: "failure - unknown macro", expr: "@sometimes", wantErr: true},
+		{name: "failure - never", expr: "0 0 31 feb *", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCron() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && c.String() != tt.expr {
+				t.Errorf("CronExpr.String() = %s, want %s", c.String(), tt.expr)
+			}
+		})
+	}
+}
+
+func TestCronExprNext(t *testing.T) {
+	// A Wednesday
+	start := time.Date(2023, time.March, 15, 10, 30, 20, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{
+			name: "next minute",
+			expr: "* * * * *",
+			want: time.Date(2023, time.March, 15, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name: "later today",
+			expr: "0 12 * * *",
+			want: time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "tomorrow",
+			expr: "@daily",
+			want: time.Date(2023, time.March, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "step",
+			expr: "*/20 * * * *",
+			want: time.Date(2023, time.March, 15, 10, 40, 0, 0, time.UTC),
+		},
+		{
+			name: "day of week",
+			expr: "0 3 * * mon",
+			want: time.Date(2023, time.March, 20, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			expr: "0 0 1 * fri",
+			want: time.Date(2023, time.March, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "next year",
+			expr: "0 0 1 jan *",
+			want: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 feb *",
+			want: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+			if got := c.Next(start); !got.Equal(tt.want) {
+				t.Errorf("CronExpr.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import "github.com/go-ini/ini"
+
+func (c *Config) loadDanglingSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("dangling")
+	if err != nil {
+		return nil
+	}
+
+	c.DanglingRecords = sec.Key("enabled").MustBool(true)
+	return nil
+}
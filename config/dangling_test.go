@@ -0,0 +1,26 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestConfigloadDanglingSettings(t *testing.T) {
+	c := NewConfig()
+	cfg, err := ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+	[dangling]
+	enabled = true
+	`))
+	if err != nil {
+		t.Fatalf("Failed to load the test configuration: %v", err)
+	}
+
+	if err := c.loadDanglingSettings(cfg); err != nil || !c.DanglingRecords {
+		t.Errorf("Config.loadDanglingSettings() error = %v, enabled = %t", err, c.DanglingRecords)
+	}
+}
@@ -5,18 +5,57 @@
 package config
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/caffix/stringset"
 	"github.com/go-ini/ini"
+	"github.com/owasp-amass/amass/v3/net/http"
+	"github.com/owasp-amass/amass/v3/net/tor"
 )
 
 // DataSourceConfig contains the configurations specific to a data source.
 type DataSourceConfig struct {
-	Name  string
-	TTL   int `ini:"ttl"`
-	creds map[string]*Credentials
+	Name string
+	TTL  int `ini:"ttl"`
+	// The requests per minute replacing the built-in rate limit of the data source
+	RateLimit int `ini:"rate_limit"`
+	// The requests that can be sent at once after the data source was idle
+	Burst int `ini:"burst"`
+	// How the credential sets of the data source are selected: "round_robin" or "on_rate_limit"
+	KeyRotation string `ini:"key_rotation"`
+	// The HTTP(S) proxy replacing the default proxy of the data sources, or "direct" to bypass it
+	Proxy string `ini:"proxy"`
+	// The other settings of the section, which are provided to the script of the data source
+	Options map[string]string `ini:"-"`
+	lock    sync.Mutex
+	creds   map[string]*Credentials
+	// The names of the credential sets, in the order they were added
+	order []string
+	next  int
+	usage map[string]*KeyUsage
+}
+
+// The rotation strategies for the credential sets of a data source
+const (
+	// Each selection of the credentials moves on to the next set
+	RoundRobinRotation = "round_robin"
+	// The same set is selected until the service of the data source rate limits it
+	RateLimitRotation = "on_rate_limit"
+)
+
+// DirectProxy is the proxy setting that sends the requests of a data source without a proxy.
+const DirectProxy = "direct"
+
+// KeyUsage describes how often a set of credentials was selected and rate limited.
+type KeyUsage struct {
+	Name        string `json:"name"`
+	Uses        int    `json:"uses"`
+	RateLimited int    `json:"rate_limited"`
 }
 
 // Credentials contains values required for authenticating with web APIs.
@@ -52,26 +91,194 @@ func (dsc *DataSourceConfig) AddCredentials(cred *Credentials) error {
 		return fmt.Errorf("AddCredentials: The Credentials argument is invalid")
 	}
 
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
 	if dsc.creds == nil {
 		dsc.creds = make(map[string]*Credentials)
+		dsc.usage = make(map[string]*KeyUsage)
+	}
+	if _, found := dsc.creds[cred.Name]; !found {
+		dsc.order = append(dsc.order, cred.Name)
+		dsc.usage[cred.Name] = &KeyUsage{Name: cred.Name}
 	}
 
 	dsc.creds[cred.Name] = cred
 	return nil
 }
 
-// GetCredentials returns randomly selected Credentials associated with the receiver configuration.
+// HasCredentials returns true when the receiver configuration has at least one set of Credentials.
+func (dsc *DataSourceConfig) HasCredentials() bool {
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
+	return len(dsc.creds) > 0
+}
+
+// GetCredentials returns the Credentials selected by the key rotation of the receiver configuration.
 func (dsc *DataSourceConfig) GetCredentials() *Credentials {
-	if num := len(dsc.creds); num > 0 {
-		var creds []*Credentials
-		for _, c := range dsc.creds {
-			creds = append(creds, c)
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
+	num := len(dsc.order)
+	if num == 0 {
+		return nil
+	}
+
+	name := dsc.order[dsc.next%num]
+	if dsc.KeyRotation != RateLimitRotation {
+		dsc.next = (dsc.next + 1) % num
+	}
+
+	dsc.usage[name].Uses++
+	return dsc.creds[name]
+}
+
+// MatchCredentials returns the Credentials with a secret value found in the text, such as the URL, headers
+// and body of a request, or nil when none of the credential sets were used.
+func (dsc *DataSourceConfig) MatchCredentials(text string) *Credentials {
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
+	for _, name := range dsc.order {
+		c := dsc.creds[name]
+
+		for _, v := range []string{c.Key, c.Secret, c.Password} {
+			if v != "" && strings.Contains(text, v) {
+				return c
+			}
 		}
-		return creds[0]
 	}
 	return nil
 }
 
+// RateLimited records that the service of the data source rate limited the Credentials provided.
+// With the on_rate_limit rotation, the following selections move on to the next set of credentials.
+func (dsc *DataSourceConfig) RateLimited(cred *Credentials) {
+	if cred == nil {
+		return
+	}
+
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
+	u, found := dsc.usage[cred.Name]
+	if !found {
+		return
+	}
+	u.RateLimited++
+
+	num := len(dsc.order)
+	if dsc.KeyRotation == RateLimitRotation && dsc.order[dsc.next%num] == cred.Name {
+		dsc.next = (dsc.next + 1) % num
+	}
+}
+
+// KeyUsage returns the selections and rate limits of each set of Credentials, in the order they were added.
+func (dsc *DataSourceConfig) KeyUsage() []KeyUsage {
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
+	usage := make([]KeyUsage, 0, len(dsc.order))
+	for _, name := range dsc.order {
+		usage = append(usage, *dsc.usage[name])
+	}
+	return usage
+}
+
+// SourceProxy returns the proxy the requests of the data source are sent through, an empty string
+// when the environment settings apply, or DirectProxy when the requests bypass any proxy. The proxy
+// of the data source comes first, followed by the proxy of the data sources and the SOCKS5 proxy.
+// When Tor is enabled, every data source is sent through its own Tor circuit instead.
+func (c *Config) SourceProxy(source string) string {
+	if c.Tor {
+		return tor.ProxyURL(c.TorSOCKSAddr, source)
+	}
+	if dsc := c.GetDataSourceConfig(source); dsc != nil && dsc.Proxy != "" {
+		return dsc.Proxy
+	}
+	if c.DataSourceProxy != "" {
+		return c.DataSourceProxy
+	}
+	return c.SOCKS5Proxy
+}
+
+// SourceTLSConfig returns the TLS settings of the data source requests, which verify the server
+// certificates unless the insecure_skip_verify setting was provided.
+func (c *Config) SourceTLSConfig() *tls.Config {
+	c.dataSourceTLSLock.Lock()
+	defer c.dataSourceTLSLock.Unlock()
+
+	if c.dataSourceTLS == nil {
+		tlsc, err := http.TLSConfig(c.DataSourceCAFile, c.DataSourceClientCert, c.DataSourceClientKey, c.DataSourceInsecure)
+		if err != nil {
+			// The files were checked when the settings were loaded
+			tlsc = &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: c.DataSourceInsecure}
+		}
+		c.dataSourceTLS = tlsc
+	}
+	return c.dataSourceTLS
+}
+
+func (c *Config) loadDataSourceTLSSettings(sec *ini.Section) error {
+	c.DataSourceCAFile = strings.TrimSpace(sec.Key("ca_file").String())
+	c.DataSourceClientCert = strings.TrimSpace(sec.Key("client_cert").String())
+	c.DataSourceClientKey = strings.TrimSpace(sec.Key("client_key").String())
+	c.DataSourceInsecure = sec.Key("insecure_skip_verify").MustBool(false)
+
+	tlsc, err := http.TLSConfig(c.DataSourceCAFile, c.DataSourceClientCert, c.DataSourceClientKey, c.DataSourceInsecure)
+	if err != nil {
+		return fmt.Errorf("the TLS settings of the data_sources section are invalid: %v", err)
+	}
+
+	c.dataSourceTLSLock.Lock()
+	c.dataSourceTLS = tlsc
+	c.dataSourceTLSLock.Unlock()
+	return nil
+}
+
+func checkProxy(proxy string) error {
+	if proxy == "" || proxy == DirectProxy {
+		return nil
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+		return fmt.Errorf("the proxy %s must be an http, https or socks5 URL", proxy)
+	}
+	return nil
+}
+
+// The settings of the data source sections that are not provided to the scripts as options
+var dataSourceSettings = map[string]struct{}{
+	"ttl":          {},
+	"rate_limit":   {},
+	"burst":        {},
+	"key_rotation": {},
+	"proxy":        {},
+}
+
+// dataSourceOptions returns the settings of the data source section unknown to Amass, with the
+// names in lowercase.
+func dataSourceOptions(sec *ini.Section) map[string]string {
+	var opts map[string]string
+
+	for _, key := range sec.Keys() {
+		name := strings.ToLower(key.Name())
+		if _, found := dataSourceSettings[name]; found {
+			continue
+		}
+		if opts == nil {
+			opts = make(map[string]string)
+		}
+		opts[name] = strings.TrimSpace(key.String())
+	}
+	return opts
+}
+
 func (c *Config) loadDataSourceSettings(cfg *ini.File) error {
 	sec, err := cfg.GetSection("data_sources")
 	if err != nil {
@@ -83,14 +290,33 @@ func (c *Config) loadDataSourceSettings(cfg *ini.File) error {
 			c.MinimumTTL = ttl
 		}
 	}
+	if sec.HasKey("proxy") {
+		c.DataSourceProxy = strings.TrimSpace(sec.Key("proxy").String())
+		if err := checkProxy(c.DataSourceProxy); err != nil {
+			return fmt.Errorf("the proxy setting of the data_sources section is invalid: %v", err)
+		}
+	}
+	if err := c.loadDataSourceTLSSettings(sec); err != nil {
+		return err
+	}
+	if sec.HasKey("response_cache") {
+		if cache, err := sec.Key("response_cache").Bool(); err == nil {
+			c.ResponseCache = cache
+		}
+	}
 
+	var filtered bool
 	for _, child := range sec.ChildSections() {
 		name := strings.Split(child.Name(), ".")[1]
 
-		if name == "disabled" {
-			// Load up all the disabled data source names
+		if name == "disabled" || name == "enabled" {
+			if filtered {
+				return errors.New("the data_sources section cannot provide both enabled and disabled data sources")
+			}
+			filtered = true
+			// Load up all the enabled or disabled data source names, which can also be categories or trust tiers
 			c.SourceFilter.Sources = stringset.Deduplicate(child.Key("data_source").ValueWithShadows())
-			c.SourceFilter.Include = false
+			c.SourceFilter.Include = name == "enabled"
 			continue
 		}
 
@@ -103,6 +329,27 @@ func (c *Config) loadDataSourceSettings(cfg *ini.File) error {
 		if c.MinimumTTL > dsc.TTL {
 			dsc.TTL = c.MinimumTTL
 		}
+		if dsc.RateLimit < 0 || dsc.Burst < 0 {
+			return fmt.Errorf("the rate_limit and burst settings of %s cannot be negative", name)
+		}
+		if dsc.Burst > 0 && dsc.RateLimit == 0 {
+			return fmt.Errorf("the burst setting of %s requires the rate_limit setting", name)
+		}
+		// The sections of the credentials are also found among the children
+		if len(strings.Split(child.Name(), ".")) == 2 {
+			dsc.Options = dataSourceOptions(child)
+		}
+		dsc.Proxy = strings.TrimSpace(dsc.Proxy)
+		if err := checkProxy(dsc.Proxy); err != nil {
+			return fmt.Errorf("the proxy setting of %s is invalid: %v", name, err)
+		}
+		switch dsc.KeyRotation {
+		case "":
+			dsc.KeyRotation = RoundRobinRotation
+		case RoundRobinRotation, RateLimitRotation:
+		default:
+			return fmt.Errorf("the key_rotation setting of %s must be %s or %s", name, RoundRobinRotation, RateLimitRotation)
+		}
 		// Check for data source credentials
 		for _, cr := range child.ChildSections() {
 			setName := strings.Split(cr.Name(), ".")[2]
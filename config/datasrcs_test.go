@@ -5,6 +5,8 @@
 package config
 
 import (
+	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/go-ini/ini"
@@ -55,6 +57,42 @@ func TestGetCredentials(t *testing.T) {
 	}
 }
 
+func TestKeyRotation(t *testing.T) {
+	c := NewConfig()
+	dsc := c.GetDataSourceConfig("test")
+	for _, name := range []string{"account1", "account2", "account3"} {
+		if err := dsc.AddCredentials(&Credentials{Name: name, Key: name + "key"}); err != nil {
+			t.Fatalf("AddCredentials returned an error: %v", err)
+		}
+	}
+
+	var names []string
+	for i := 0; i < 4; i++ {
+		names = append(names, dsc.GetCredentials().Name)
+	}
+	if expected := []string{"account1", "account2", "account3", "account1"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("The round robin rotation selected %v, expected %v", names, expected)
+	}
+
+	dsc.KeyRotation = RateLimitRotation
+	current := dsc.GetCredentials()
+	if next := dsc.GetCredentials(); next != current {
+		t.Errorf("The rotation moved from %s to %s before the credentials were rate limited", current.Name, next.Name)
+	}
+	if creds := dsc.MatchCredentials("https://api.example.com/?key=" + current.Key); creds != current {
+		t.Fatalf("MatchCredentials failed to find the %s credentials in the request", current.Name)
+	}
+	dsc.RateLimited(current)
+	if next := dsc.GetCredentials(); next == current {
+		t.Errorf("The rotation kept selecting the %s credentials after they were rate limited", current.Name)
+	}
+
+	usage := dsc.KeyUsage()
+	if len(usage) != 3 || usage[1].Uses != 3 || usage[1].RateLimited != 1 {
+		t.Errorf("KeyUsage returned %v", usage)
+	}
+}
+
 func TestLoadDataSourceSettings(t *testing.T) {
 	c := NewConfig()
 
@@ -81,12 +119,14 @@ func TestLoadDataSourceSettings(t *testing.T) {
 		[]byte(`
 		[data_sources]
 		minimum_ttl = 1440
+		response_cache = false
 
 		[data_sources.disabled]
 		data_source = CommonCrawl
 
 		[data_sources.AlienVault]
 		ttl = 4320
+		Time_Last_After = -86400
 		[data_sources.AlienVault.Credentials]
 		apikey = fake
 
@@ -99,7 +139,7 @@ func TestLoadDataSourceSettings(t *testing.T) {
 	if err := c.loadDataSourceSettings(cfg); err != nil {
 		t.Errorf("Failed to parse the data source settings: %v", err)
 	}
-	if c.MinimumTTL != 1440 {
+	if c.MinimumTTL != 1440 || c.ResponseCache {
 		t.Errorf("Failed to load global data source settings")
 	}
 
@@ -110,4 +150,234 @@ func TestLoadDataSourceSettings(t *testing.T) {
 	if creds := dsc.GetCredentials(); creds == nil || creds.Key != "fake" {
 		t.Errorf("Failed to load data source credentials")
 	}
+	if len(dsc.Options) != 1 || dsc.Options["time_last_after"] != "-86400" {
+		t.Errorf("Failed to load the data source options: %v", dsc.Options)
+	}
+	if dsc := c.GetDataSourceConfig("BinaryEdge"); len(dsc.Options) != 0 {
+		t.Errorf("Loaded the data source options %v without the settings", dsc.Options)
+	}
+}
+
+func TestLoadDataSourceFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     string
+		include bool
+		sources []string
+		err     bool
+	}{
+		{
+			name: "enabled",
+			cfg: `
+			[data_sources]
+			[data_sources.enabled]
+			data_source = authenticated
+			data_source = cert
+			`,
+			include: true,
+			sources: []string{"authenticated", "cert"},
+		},
+		{
+			name: "disabled",
+			cfg: `
+			[data_sources]
+			[data_sources.disabled]
+			data_source = scrape
+			`,
+			sources: []string{"scrape"},
+		},
+		{
+			name: "both",
+			cfg: `
+			[data_sources]
+			[data_sources.enabled]
+			data_source = api
+			[data_sources.disabled]
+			data_source = scrape
+			`,
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ini.LoadSources(ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			}, []byte(tt.cfg))
+			if err != nil {
+				t.Fatalf("Failed to load the configuration: %v", err)
+			}
+
+			c := NewConfig()
+			err = c.loadDataSourceSettings(cfg)
+			if tt.err {
+				if err == nil {
+					t.Error("Failed to report both the enabled and disabled data sources")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to parse the data source settings: %v", err)
+			}
+			if c.SourceFilter.Include != tt.include {
+				t.Errorf("The filter includes the data sources: %t, expected %t", c.SourceFilter.Include, tt.include)
+			}
+			// The order of the names is not preserved by the deduplication
+			sort.Strings(c.SourceFilter.Sources)
+			if !reflect.DeepEqual(c.SourceFilter.Sources, tt.sources) {
+				t.Errorf("The filter provides %v, expected %v", c.SourceFilter.Sources, tt.sources)
+			}
+		})
+	}
+}
+
+func TestLoadDataSourceRateLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   string
+		rate  int
+		burst int
+		err   bool
+	}{
+		{
+			name: "rate and burst",
+			cfg: `
+			[data_sources]
+			[data_sources.Shodan]
+			rate_limit = 120
+			burst = 5
+			`,
+			rate:  120,
+			burst: 5,
+		},
+		{
+			name: "key rotation",
+			cfg: `
+			[data_sources]
+			[data_sources.Shodan]
+			key_rotation = sometimes
+			`,
+			err: true,
+		},
+		{
+			name: "proxy",
+			cfg: `
+			[data_sources]
+			[data_sources.Shodan]
+			proxy = ftp://proxy.example.com
+			`,
+			err: true,
+		},
+		{
+			name: "negative",
+			cfg: `
+			[data_sources]
+			[data_sources.Shodan]
+			rate_limit = -1
+			`,
+			err: true,
+		},
+		{
+			name: "burst without rate",
+			cfg: `
+			[data_sources]
+			[data_sources.Shodan]
+			burst = 5
+			`,
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ini.LoadSources(ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			}, []byte(tt.cfg))
+			if err != nil {
+				t.Fatalf("Failed to load the configuration: %v", err)
+			}
+
+			c := NewConfig()
+			err = c.loadDataSourceSettings(cfg)
+			if tt.err {
+				if err == nil {
+					t.Error("Failed to report the invalid data source settings")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to parse the data source settings: %v", err)
+			}
+			if dsc := c.GetDataSourceConfig("Shodan"); dsc.RateLimit != tt.rate || dsc.Burst != tt.burst {
+				t.Errorf("The data source has a rate limit of %d and burst of %d, expected %d and %d",
+					dsc.RateLimit, dsc.Burst, tt.rate, tt.burst)
+			}
+		})
+	}
+}
+
+func TestSourceProxy(t *testing.T) {
+	cfg, _ := ini.LoadSources(ini.LoadOptions{
+		Insensitive:  true,
+		AllowShadows: true,
+	}, []byte(`
+	[data_sources]
+	proxy = http://egress.example.com:3128
+
+	[data_sources.Shodan]
+	proxy = direct
+
+	[data_sources.Censys]
+	proxy = https://other.example.com:8443
+	`))
+
+	c := NewConfig()
+	if err := c.loadDataSourceSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the data source settings: %v", err)
+	}
+
+	for src, expected := range map[string]string{
+		"Shodan":      DirectProxy,
+		"Censys":      "https://other.example.com:8443",
+		"CommonCrawl": "http://egress.example.com:3128",
+	} {
+		if proxy := c.SourceProxy(src); proxy != expected {
+			t.Errorf("The %s data source uses the proxy %q, expected %q", src, proxy, expected)
+		}
+	}
+}
+
+func TestSourceTLSConfig(t *testing.T) {
+	if tlsc := NewConfig().SourceTLSConfig(); tlsc == nil || tlsc.InsecureSkipVerify {
+		t.Error("The data source requests do not verify the server certificates by default")
+	}
+
+	for _, tt := range []struct {
+		cfg      string
+		insecure bool
+		err      bool
+	}{
+		{cfg: "insecure_skip_verify = true", insecure: true},
+		{cfg: "ca_file = /path/does/not/exist.pem", err: true},
+		{cfg: "client_cert = /path/does/not/exist.pem", err: true},
+	} {
+		cfg, err := ini.LoadSources(ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		}, []byte("[data_sources]\n"+tt.cfg))
+		if err != nil {
+			t.Fatalf("Failed to load the configuration: %v", err)
+		}
+
+		c := NewConfig()
+		if err := c.loadDataSourceSettings(cfg); (err != nil) != tt.err {
+			t.Errorf("Config.loadDataSourceSettings() with %q error = %v, wantErr %v", tt.cfg, err, tt.err)
+			continue
+		}
+		if !tt.err && c.SourceTLSConfig().InsecureSkipVerify != tt.insecure {
+			t.Errorf("The %q setting was not applied to the data source requests", tt.cfg)
+		}
+	}
 }
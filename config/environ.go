@@ -0,0 +1,159 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+const (
+	envPrefix = "AMASS_"
+	// The separator of the section names and the option name in the environment variable names
+	envSectionSep = "__"
+	// The credential set holding the data source credentials provided by the environment
+	envCredentialSet = "environment"
+)
+
+// The options that can be repeated, which take comma-separated values from the environment
+var repeatableKeys = map[string]struct{}{
+	"address":           {},
+	"asn":               {},
+	"cidr":              {},
+	"data_source":       {},
+	"domain":            {},
+	"exclude_cidr":      {},
+	"fingerprints_file": {},
+	"keyword":           {},
+	"port":              {},
+	"resolver":          {},
+	"subdomain":         {},
+	"technologies_file": {},
+	"tld":               {},
+	"tld_file":          {},
+	"wordlist_file":     {},
+}
+
+// The suffixes of the environment variables providing the credentials of a data source
+var credentialSuffixes = map[string]string{
+	"_API_KEY":  "apikey",
+	"_SECRET":   "secret",
+	"_USERNAME": "username",
+	"_PASSWORD": "password",
+}
+
+// environOverrides returns the environment variables that override the configuration settings.
+func environOverrides() []string {
+	var vars []string
+
+	for _, v := range os.Environ() {
+		if strings.HasPrefix(v, envPrefix) && !strings.HasPrefix(v, cfgEnvironVar+"=") {
+			vars = append(vars, v)
+		}
+	}
+
+	sort.Strings(vars)
+	return vars
+}
+
+// applyEnvironOverrides sets the options named by the environment variables in the configuration.
+// AMASS_<OPTION> sets an option of the default section, AMASS_<SECTION>__<OPTION> sets an option of
+// a section, where the names of nested sections are also separated by two underscores, and
+// AMASS_<SOURCE>_API_KEY, _SECRET, _USERNAME and _PASSWORD replace the credentials of a data source.
+func applyEnvironOverrides(cfg *ini.File, environ []string) error {
+	replaced := make(map[string]bool)
+
+	for _, v := range environ {
+		name, value, found := strings.Cut(v, "=")
+		if !found || !strings.HasPrefix(name, envPrefix) || name == cfgEnvironVar {
+			continue
+		}
+		name = strings.TrimPrefix(name, envPrefix)
+
+		if strings.Contains(name, envSectionSep) {
+			parts := strings.Split(strings.ToLower(name), envSectionSep)
+			for _, p := range parts {
+				if p == "" {
+					return fmt.Errorf("the environment variable %s%s does not name a configuration option", envPrefix, name)
+				}
+			}
+
+			sec := section(cfg, strings.Join(parts[:len(parts)-1], "."))
+			if err := setEnvironKey(sec, parts[len(parts)-1], value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if source, key := credentialKey(name); key != "" {
+			if source == "" {
+				return fmt.Errorf("the environment variable %s%s does not name a data source", envPrefix, name)
+			}
+
+			srcName := "data_sources." + source
+			// The credentials of the environment replace those of the configuration file
+			if !replaced[source] {
+				for _, cr := range section(cfg, srcName).ChildSections() {
+					cfg.DeleteSection(cr.Name())
+				}
+				replaced[source] = true
+			}
+
+			sec := section(cfg, srcName+"."+envCredentialSet)
+			if err := setEnvironKey(sec, key, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := setEnvironKey(cfg.Section(ini.DefaultSection), strings.ToLower(name), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// section returns the named section, creating it along with the parent sections when missing.
+func section(cfg *ini.File, name string) *ini.Section {
+	labels := strings.Split(name, ".")
+
+	for i := 1; i < len(labels); i++ {
+		_ = cfg.Section(strings.Join(labels[:i], "."))
+	}
+	return cfg.Section(name)
+}
+
+func credentialKey(name string) (string, string) {
+	for suffix, key := range credentialSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return strings.ToLower(strings.TrimSuffix(name, suffix)), key
+		}
+	}
+	return "", ""
+}
+
+func setEnvironKey(sec *ini.Section, key, value string) error {
+	values := []string{value}
+	if _, found := repeatableKeys[key]; found {
+		values = nil
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+
+	sec.DeleteKey(key)
+	for _, v := range values {
+		if _, err := sec.NewKey(key, v); err != nil {
+			return fmt.Errorf("failed to set the %s option from the environment: %v", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestApplyEnvironOverrides(t *testing.T) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{
+		Insensitive:  true,
+		AllowShadows: true,
+	}, []byte(`
+	mode = active
+
+	[scope.domains]
+	domain = example.com
+
+	[data_sources]
+	minimum_ttl = 60
+
+	[data_sources.Shodan]
+	ttl = 120
+
+	[data_sources.Shodan.Credentials]
+	apikey = fromfile
+	`))
+	if err != nil {
+		t.Fatalf("Failed to load the test configuration: %v", err)
+	}
+
+	if err := applyEnvironOverrides(cfg, []string{
+		"AMASS_CONFIG=/etc/amass/config.ini",
+		"AMASS_MODE=passive",
+		"AMASS_SCOPE__DOMAINS__DOMAIN=owasp.org, example.org",
+		"AMASS_DATA_SOURCES__MINIMUM_TTL=1440",
+		"AMASS_TOR__ENABLED=true",
+		"AMASS_SHODAN_API_KEY=fromenv",
+		"AMASS_CENSYS_API_KEY=id",
+		"AMASS_CENSYS_SECRET=a,b",
+	}); err != nil {
+		t.Fatalf("applyEnvironOverrides() error = %v", err)
+	}
+
+	for name, expected := range map[string][]string{
+		"default/mode":                           {"passive"},
+		"scope.domains/domain":                   {"owasp.org", "example.org"},
+		"data_sources/minimum_ttl":               {"1440"},
+		"tor/enabled":                            {"true"},
+		"data_sources.shodan/ttl":                {"120"},
+		"data_sources.shodan.environment/apikey": {"fromenv"},
+		"data_sources.censys.environment/secret": {"a,b"},
+		"data_sources.censys.environment/apikey": {"id"},
+	} {
+		sec, key := filepath.Split(name)
+		if got := cfg.Section(filepath.Clean(sec)).Key(key).ValueWithShadows(); !reflect.DeepEqual(got, expected) {
+			t.Errorf("The %s option was set to %v, expected %v", name, got, expected)
+		}
+	}
+	if _, err := cfg.GetSection("data_sources.shodan.credentials"); err == nil {
+		t.Error("The credentials of the environment did not replace those of the configuration file")
+	}
+
+	if err := applyEnvironOverrides(cfg, []string{"AMASS_SCOPE____DOMAIN=owasp.org"}); err == nil {
+		t.Error("Failed to report the environment variable that does not name an option")
+	}
+}
+
+func TestLoadSettingsEnviron(t *testing.T) {
+	t.Setenv("AMASS_SOCKS5_PROXY", "socks5://127.0.0.1:1080")
+	t.Setenv("AMASS_SHODAN_API_KEY", "fromenv")
+	t.Setenv("AMASS_DATA_SOURCES__SHODAN__TTL", "4320")
+
+	// The settings are loaded from the environment without a configuration file
+	c := NewConfig()
+	if err := c.LoadSettings(""); err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if c.SOCKS5Proxy != "socks5://127.0.0.1:1080" {
+		t.Errorf("The socks5_proxy setting was not loaded from the environment: %q", c.SOCKS5Proxy)
+	}
+
+	dsc := c.GetDataSourceConfig("Shodan")
+	if creds := dsc.GetCredentials(); creds == nil || creds.Key != "fromenv" || creds.Name != envCredentialSet {
+		t.Errorf("The Shodan credentials were not loaded from the environment: %+v", creds)
+	}
+	if dsc.TTL != 4320 {
+		t.Errorf("The Shodan ttl setting was not loaded from the environment: %d", dsc.TTL)
+	}
+
+	// The environment overrides the configuration file
+	path := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(path, []byte("socks5_proxy = socks5://10.0.0.1:1080\n[data_sources]\n"), 0600); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	c = NewConfig()
+	if err := c.LoadSettings(path); err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if c.SOCKS5Proxy != "socks5://127.0.0.1:1080" {
+		t.Errorf("The environment did not override the configuration file: %q", c.SOCKS5Proxy)
+	}
+}
@@ -83,3 +83,29 @@ func TestLocalDatabaseSettings(t *testing.T) {
 		t.Errorf("LocalDatabaseSettings failed")
 	}
 }
+
+func TestLoadSQLiteDatabaseSettings(t *testing.T) {
+	c := NewConfig()
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{Insensitive: true},
+		[]byte(`
+		[graphdbs]
+		[graphdbs.sqlite]
+		primary = true
+		url = /tmp/amass.sqlite
+		`),
+	)
+	if err := c.loadDatabaseSettings(cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(c.GraphDBs) != 1 {
+		t.Fatalf("expected one graph database, got %d", len(c.GraphDBs))
+	}
+	if db := c.GraphDBs[0]; db.System != "sqlite" || !db.Primary || db.URL != "/tmp/amass.sqlite" {
+		t.Errorf("unexpected SQLite database settings: %+v", db)
+	}
+	if c.LocalDatabaseSettings(c.GraphDBs).Primary {
+		t.Error("the local database should not be primary when the SQLite database is")
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/caffix/stringset"
+)
+
+// The fields holding the subdomain names in the JSON lines written by the other tools
+var importNameFields = []string{"host", "name", "subdomain"}
+
+// GetImportedNamesFromFile returns the subdomain names in the output file of another tool, such as
+// the plain lists written by subfinder and assetfinder, the JSON lines of subfinder and Amass, the
+// comma-separated lines starting with the name, and the URLs written by the probing tools.
+func GetImportedNamesFromFile(path string) ([]string, error) {
+	lines, err := GetListFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := stringset.New()
+	defer names.Close()
+
+	for _, line := range lines {
+		if name := importedName(line); name != "" {
+			names.Insert(name)
+		}
+	}
+	return names.Slice(), nil
+}
+
+func importedName(line string) string {
+	var name string
+
+	if strings.HasPrefix(line, "{") {
+		var fields map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			return ""
+		}
+
+		for _, key := range importNameFields {
+			if v, ok := fields[key].(string); ok && v != "" {
+				name = v
+				break
+			}
+		}
+	} else if !strings.HasPrefix(line, "#") {
+		if fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}); len(fields) > 0 {
+			name = fields[0]
+		}
+	}
+
+	if strings.Contains(name, "://") {
+		if u, err := url.Parse(name); err == nil {
+			name = u.Hostname()
+		}
+	}
+
+	name = strings.Trim(strings.ToLower(strings.TrimSpace(name)), ".")
+	name = strings.TrimPrefix(name, "*.")
+	if !strings.Contains(name, ".") || strings.ContainsAny(name, "/:@ ") {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,50 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestGetImportedNamesFromFile(t *testing.T) {
+	lines := []string{
+		"# assetfinder --subs-only example.com",
+		"www.example.com",
+		"WWW.EXAMPLE.COM.",
+		"*.dev.example.com",
+		`{"host":"api.example.com","input":"example.com","source":"crtsh"}`,
+		`{"name":"mail.example.com","domain":"example.com"}`,
+		`{"input":"example.com"}`,
+		"vpn.example.com,192.168.1.1,alienvault",
+		"https://portal.example.com:8443/login",
+		"localhost",
+		`{"host":`,
+	}
+
+	path := filepath.Join(t.TempDir(), "subfinder.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		t.Fatalf("Failed to write the test file: %v", err)
+	}
+
+	names, err := GetImportedNamesFromFile(path)
+	if err != nil {
+		t.Fatalf("GetImportedNamesFromFile() error = %v", err)
+	}
+	sort.Strings(names)
+
+	expected := []string{"api.example.com", "dev.example.com", "mail.example.com",
+		"portal.example.com", "vpn.example.com", "www.example.com"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("GetImportedNamesFromFile() = %v, expected %v", names, expected)
+	}
+
+	if _, err := GetImportedNamesFromFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("GetImportedNamesFromFile() did not report the missing file")
+	}
+}
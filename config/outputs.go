@@ -0,0 +1,106 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-ini/ini"
+	"github.com/owasp-amass/amass/v3/net/http"
+)
+
+// OutputSink contains values required for delivering the enumeration findings to external systems.
+// The Name is the section name without the 'outputs.' prefix, such as 'webhook.chatops'.
+type OutputSink struct {
+	Name         string
+	System       string
+	URL          string `ini:"url"`
+	Username     string `ini:"username"`
+	Password     string `ini:"password"`
+	Key          string `ini:"apikey"`
+	Token        string `ini:"token"`
+	Index        string `ini:"index"`
+	SourceType   string `ini:"sourcetype"`
+	Template     string `ini:"template"`
+	BatchSize    int    `ini:"batch_size"`
+	Brokers      string `ini:"brokers"`
+	Topic        string `ini:"topic"`
+	Mechanism    string `ini:"sasl_mechanism"`
+	TLS          bool   `ini:"tls"`
+	CAFile       string `ini:"ca_file"`
+	Insecure     bool   `ini:"insecure_skip_verify"`
+	Secret       string `ini:"secret"`
+	Retries      int    `ini:"retries"`
+	Severity     string `ini:"severity"`
+	Event        string `ini:"event"`
+	Tags         string `ini:"tags"`
+	Distribution int    `ini:"distribution"`
+	Facility     string `ini:"facility"`
+	Discoveries  bool   `ini:"discoveries"`
+	tlsOnce      sync.Once
+	tlsc         *tls.Config
+}
+
+// TLSConfig returns the TLS settings of the connections to the system, which verify the server
+// certificates unless the insecure_skip_verify setting was provided.
+func (s *OutputSink) TLSConfig() *tls.Config {
+	s.tlsOnce.Do(func() {
+		tlsc, err := http.TLSConfig(s.CAFile, "", "", s.Insecure)
+		if err != nil {
+			// The file was checked when the settings were loaded
+			tlsc = &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: s.Insecure}
+		}
+		s.tlsc = tlsc
+	})
+	return s.tlsc
+}
+
+func (c *Config) loadOutputSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("outputs")
+	if err != nil {
+		return nil
+	}
+
+	for _, child := range sec.ChildSections() {
+		sink := new(OutputSink)
+		name := strings.Split(child.Name(), ".")[1]
+
+		if err := child.MapTo(sink); err != nil {
+			return fmt.Errorf("failed to parse the %s output settings: %v", name, err)
+		}
+		// Kafka clusters are reached using the brokers instead of a URL
+		if sink.URL == "" && sink.Brokers == "" {
+			return fmt.Errorf("the %s output requires the url setting", name)
+		}
+		if sink.BatchSize < 0 {
+			return fmt.Errorf("the %s output batch_size setting must be a positive integer", name)
+		}
+		if sink.Retries < 0 {
+			return fmt.Errorf("the %s output retries setting must be a positive integer", name)
+		}
+		if sink.Distribution < 0 || sink.Distribution > 3 {
+			return fmt.Errorf("the %s output distribution setting must be between 0 and 3", name)
+		}
+		if sink.Template != "" {
+			if _, err := os.Stat(sink.Template); err != nil {
+				return fmt.Errorf("unable to load the file in the %s output template setting: %s: %v", name, sink.Template, err)
+			}
+		}
+		if sink.CAFile != "" {
+			if _, err := http.TLSConfig(sink.CAFile, "", "", sink.Insecure); err != nil {
+				return fmt.Errorf("the %s output ca_file setting is invalid: %v", name, err)
+			}
+		}
+
+		sink.Name = strings.TrimPrefix(child.Name(), "outputs.")
+		sink.System = name
+		c.Outputs = append(c.Outputs, sink)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestConfigloadOutputSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     []byte
+		wantErr bool
+		outputs int
+	}{
+		{
+			name: "success - elasticsearch",
+			cfg: []byte(`
+			[outputs]
+			[outputs.elasticsearch]
+			url = https://localhost:9200
+			username = elastic
+			password = changeme
+			index = amass
+			batch_size = 100
+			`),
+			outputs: 1,
+		},
+		{
+			name: "success - multiple webhooks",
+			cfg: []byte(`
+			[outputs]
+			[outputs.webhook.chatops]
+			url = https://chat.example.com/hooks/1
+			[outputs.webhook.tickets]
+			url = https://tickets.example.com/hooks/2
+			retries = 5
+			`),
+			outputs: 2,
+		},
+		{
+			name: "success - misp",
+			cfg: []byte(`
+			[outputs]
+			[outputs.misp]
+			url = https://misp.example.com
+			apikey = 0123456789abcdef
+			tags = tlp:amber,amass
+			distribution = 1
+			`),
+			outputs: 1,
+		},
+		{
+			name: "success - syslog",
+			cfg: []byte(`
+			[outputs]
+			[outputs.syslog]
+			url = tls://logs.example.com:6514
+			facility = local3
+			discoveries = true
+			`),
+			outputs: 1,
+		},
+		{
+			name: "success - insecure splunk",
+			cfg: []byte(`
+			[outputs]
+			[outputs.splunk]
+			url = https://localhost:8088
+			token = 12345678
+			insecure_skip_verify = true
+			`),
+			outputs: 1,
+		},
+		{
+			name: "failure - misp distribution",
+			cfg: []byte(`
+			[outputs]
+			[outputs.misp]
+			url = https://misp.example.com
+			distribution = 4
+			`),
+			wantErr: true,
+		},
+		{
+			name: "failure - missing url",
+			cfg: []byte(`
+			[outputs]
+			[outputs.elasticsearch]
+			index = amass
+			`),
+			wantErr: true,
+		},
+		{
+			name: "failure - missing template",
+			cfg: []byte(`
+			[outputs]
+			[outputs.elasticsearch]
+			url = https://localhost:9200
+			template = /path/does/not/exist.json
+			`),
+			wantErr: true,
+		},
+		{
+			name: "failure - missing ca_file",
+			cfg: []byte(`
+			[outputs]
+			[outputs.elasticsearch]
+			url = https://localhost:9200
+			ca_file = /path/does/not/exist.pem
+			`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			cfg, err := ini.LoadSources(ini.LoadOptions{Insensitive: true}, tt.cfg)
+			if err != nil {
+				t.Fatalf("Failed to load the test configuration: %v", err)
+			}
+
+			if err := c.loadOutputSettings(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("Config.loadOutputSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(c.Outputs) != tt.outputs {
+				t.Errorf("Config.loadOutputSettings() returned %d outputs, expected %d", len(c.Outputs), tt.outputs)
+			}
+			for _, o := range c.Outputs {
+				if o.System == "webhook" && o.Name != "webhook.chatops" && o.Name != "webhook.tickets" {
+					t.Errorf("Config.loadOutputSettings() returned the webhook output named %s", o.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestOutputSinkTLSConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		sink     *OutputSink
+		insecure bool
+	}{
+		{name: "default", sink: &OutputSink{URL: "https://localhost:9200"}},
+		{name: "insecure", sink: &OutputSink{URL: "https://localhost:9200", Insecure: true}, insecure: true},
+	}
+
+	for _, tt := range tests {
+		tlsc := tt.sink.TLSConfig()
+		if tlsc.InsecureSkipVerify != tt.insecure || tlsc.MinVersion != tls.VersionTLS12 {
+			t.Errorf("OutputSink.TLSConfig() for the %s settings = %v, expected verification: %t", tt.name, tlsc, !tt.insecure)
+		}
+		// The settings are reused, so the HTTP clients are not created for each request
+		if tt.sink.TLSConfig() != tlsc {
+			t.Errorf("OutputSink.TLSConfig() for the %s settings returned a new configuration", tt.name)
+		}
+	}
+}
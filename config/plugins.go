@@ -0,0 +1,85 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+// The directory within the output directory holding the plugins provided by the user
+const pluginsDirName = "plugins"
+
+func (c *Config) loadPluginSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("plugins")
+	if err != nil {
+		return nil
+	}
+
+	for _, child := range sec.ChildSections() {
+		name := strings.ToLower(strings.TrimPrefix(child.Name(), "plugins."))
+
+		if c.pluginSettings == nil {
+			c.pluginSettings = make(map[string]map[string]string)
+		}
+		c.pluginSettings[name] = child.KeysHash()
+	}
+	return nil
+}
+
+// GetPluginSettings returns the settings of the plugins section in the configuration for the plugin name.
+func (c *Config) GetPluginSettings(name string) map[string]string {
+	c.Lock()
+	defer c.Unlock()
+
+	settings := make(map[string]string)
+	for k, v := range c.pluginSettings[strings.ToLower(strings.TrimSpace(name))] {
+		settings[k] = v
+	}
+	return settings
+}
+
+// AcquirePlugins returns the paths of the plugin executables in the plugins directories.
+func (c *Config) AcquirePlugins() ([]string, error) {
+	dir := OutputDirectory(c.Dir)
+	if dir == "" {
+		return nil, nil
+	}
+
+	finfo, err := os.Stat(dir)
+	if os.IsNotExist(err) || !finfo.IsDir() {
+		return nil, errors.New("the output directory does not exist or is not a directory")
+	}
+
+	paths := []string{filepath.Join(dir, pluginsDirName)}
+	if c.PluginsDirectory != "" {
+		paths = append(paths, c.PluginsDirectory)
+	}
+
+	var plugins []string
+	for _, path := range paths {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			info, err := entry.Info()
+			// Is this file not an executable?
+			if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+			plugins = append(plugins, filepath.Join(path, entry.Name()))
+		}
+	}
+
+	sort.Strings(plugins)
+	return plugins, nil
+}
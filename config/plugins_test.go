@@ -0,0 +1,81 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestConfigloadPluginSettings(t *testing.T) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{
+		Insensitive:  true,
+		AllowShadows: true,
+	}, []byte(`
+	[plugins]
+	[plugins.example]
+	endpoint = https://api.example.com/v2
+	region = eu
+	`))
+	if err != nil {
+		t.Fatalf("Failed to load the settings: %v", err)
+	}
+
+	c := NewConfig()
+	if err := c.loadPluginSettings(cfg); err != nil {
+		t.Fatalf("loadPluginSettings() error = %v", err)
+	}
+
+	want := map[string]string{
+		"endpoint": "https://api.example.com/v2",
+		"region":   "eu",
+	}
+	if got := c.GetPluginSettings("Example"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPluginSettings() = %v, want %v", got, want)
+	}
+	if got := c.GetPluginSettings("missing"); len(got) != 0 {
+		t.Errorf("GetPluginSettings() returned %v for a plugin without settings", got)
+	}
+}
+
+func TestConfigAcquirePlugins(t *testing.T) {
+	c := NewConfig()
+	c.Dir = t.TempDir()
+	c.PluginsDirectory = t.TempDir()
+
+	files := []struct {
+		path string
+		mode os.FileMode
+	}{
+		{path: filepath.Join(c.Dir, pluginsDirName, "amass-shodan"), mode: 0755},
+		{path: filepath.Join(c.Dir, pluginsDirName, "README.md"), mode: 0644},
+		{path: filepath.Join(c.PluginsDirectory, "amass-kafka"), mode: 0700},
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+			t.Fatalf("Failed to create the plugins directory: %v", err)
+		}
+		if err := os.WriteFile(f.path, []byte("#!/bin/sh\n"), f.mode); err != nil {
+			t.Fatalf("Failed to write the %s file: %v", f.path, err)
+		}
+	}
+
+	got, err := c.AcquirePlugins()
+	if err != nil {
+		t.Fatalf("AcquirePlugins() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("AcquirePlugins() returned %d plugins, want 2: %v", len(got), got)
+	}
+	for _, path := range got {
+		if filepath.Base(path) == "README.md" {
+			t.Errorf("AcquirePlugins() returned the file %s that is not executable", path)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadPortScanSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("port_scan")
+	if err != nil {
+		return nil
+	}
+
+	c.PortScans = sec.Key("enabled").MustBool(true)
+	if !c.PortScans {
+		return nil
+	}
+
+	// The scans are performed with TCP connections, which do not require raw sockets or privileges
+	if sec.HasKey("method") {
+		switch m := strings.ToLower(sec.Key("method").String()); m {
+		case "connect":
+		case "syn":
+			return errors.New("the port_scan method 'syn' is not supported, use 'connect' or import the results of masscan or nmap with the intel subcommand")
+		default:
+			return fmt.Errorf("the port_scan method '%s' is not supported", m)
+		}
+	}
+
+	if sec.HasKey("top_ports") {
+		n, err := sec.Key("top_ports").Int()
+		if err != nil || n <= 0 {
+			return errors.New("the port_scan top_ports setting must be a positive integer")
+		}
+		c.ScanTopPorts = n
+	}
+
+	c.ScanCDNs = sec.Key("scan_cdn").MustBool(false)
+	c.ScanBanners = sec.Key("banners").MustBool(true)
+	if sec.HasKey("port") {
+		for _, port := range sec.Key("port").ValueWithShadows() {
+			c.ScanPorts = uniqueIntAppend(c.ScanPorts, port)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestConfigloadPortScanSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     []byte
+		wantErr bool
+		top     int
+		ports   []int
+		cdn     bool
+		banners bool
+	}{
+		{
+			name: "success - defaults",
+			cfg: []byte(`
+			[port_scan]
+			enabled = true
+			`),
+			top:     100,
+			banners: true,
+		},
+		{
+			name: "success - top ports and additional ports",
+			cfg: []byte(`
+			[port_scan]
+			method = connect
+			top_ports = 20
+			port = 8443
+			port = 9443
+			scan_cdn = true
+			banners = false
+			`),
+			top:   20,
+			ports: []int{8443, 9443},
+			cdn:   true,
+		},
+		{
+			name: "failure - syn scanning",
+			cfg: []byte(`
+			[port_scan]
+			method = syn
+			`),
+			wantErr: true,
+		},
+		{
+			name: "failure - invalid top ports",
+			cfg: []byte(`
+			[port_scan]
+			top_ports = 0
+			`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			cfg, err := ini.LoadSources(ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			}, tt.cfg)
+			if err != nil {
+				t.Fatalf("Failed to load the test configuration: %v", err)
+			}
+
+			if err := c.loadPortScanSettings(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("Config.loadPortScanSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !c.PortScans || c.ScanTopPorts != tt.top || !reflect.DeepEqual(c.ScanPorts, tt.ports) || c.ScanCDNs != tt.cdn || c.ScanBanners != tt.banners {
+				t.Errorf("Config.loadPortScanSettings() enabled = %t, top = %d, ports = %v, scan_cdn = %t, banners = %t",
+					c.PortScans, c.ScanTopPorts, c.ScanPorts, c.ScanCDNs, c.ScanBanners)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/caffix/stringset"
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadHTTPProbeSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("http_probe")
+	if err != nil {
+		return nil
+	}
+
+	c.HTTPProbes = sec.Key("enabled").MustBool(true)
+	c.WAFDetection = sec.Key("waf").MustBool(true)
+	c.TechDetection = sec.Key("technologies").MustBool(true)
+	if !c.TechDetection {
+		return nil
+	}
+
+	if sec.HasKey("technologies_file") {
+		for _, path := range sec.Key("technologies_file").ValueWithShadows() {
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("unable to load the file in the http_probe technologies_file setting: %s: %v", path, err)
+			}
+			c.TechFingerprints = append(c.TechFingerprints, path)
+		}
+	}
+
+	c.TechFingerprints = stringset.Deduplicate(c.TechFingerprints)
+	return nil
+}
+
+func (c *Config) loadScreenshotSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("screenshots")
+	if err != nil {
+		return nil
+	}
+
+	c.Screenshots = sec.Key("enabled").MustBool(true)
+	// The pages captured are those found by the HTTP probes
+	if c.Screenshots {
+		c.HTTPProbes = true
+	}
+	if sec.HasKey("concurrency") {
+		max, err := sec.Key("concurrency").Int()
+		if err != nil || max <= 0 {
+			return errors.New("the screenshots concurrency setting must be a positive integer")
+		}
+		c.ScreenshotConcurrency = max
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestConfigloadHTTPProbeSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     []byte
+		wantErr bool
+		tech    bool
+	}{
+		{
+			name: "success - technologies enabled by default",
+			cfg: []byte(`
+			[http_probe]
+			enabled = true
+			`),
+			tech: true,
+		},
+		{
+			name: "success - technologies disabled",
+			cfg: []byte(`
+			[http_probe]
+			technologies = false
+			technologies_file = /path/does/not/exist.json
+			`),
+			tech: false,
+		},
+		{
+			name: "failure - missing file",
+			cfg: []byte(`
+			[http_probe]
+			technologies_file = /path/does/not/exist.json
+			`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			cfg, err := ini.LoadSources(ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			}, tt.cfg)
+			if err != nil {
+				t.Fatalf("Failed to load the test configuration: %v", err)
+			}
+
+			if err := c.loadHTTPProbeSettings(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("Config.loadHTTPProbeSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (!c.HTTPProbes || c.TechDetection != tt.tech) {
+				t.Errorf("Config.loadHTTPProbeSettings() enabled = %t, technologies = %t, want %t", c.HTTPProbes, c.TechDetection, tt.tech)
+			}
+		})
+	}
+}
+
+func TestConfigloadScreenshotSettings(t *testing.T) {
+	c := NewConfig()
+	cfg, err := ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+	[screenshots]
+	enabled = true
+	concurrency = 2
+	`))
+	if err != nil {
+		t.Fatalf("Failed to load the test configuration: %v", err)
+	}
+
+	if err := c.loadScreenshotSettings(cfg); err != nil || !c.Screenshots || c.ScreenshotConcurrency != 2 {
+		t.Errorf("Config.loadScreenshotSettings() error = %v, enabled = %t, concurrency = %d", err, c.Screenshots, c.ScreenshotConcurrency)
+	}
+
+	cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+	[screenshots]
+	concurrency = 0
+	`))
+	if err := c.loadScreenshotSettings(cfg); err == nil {
+		t.Errorf("Config.loadScreenshotSettings() accepted a concurrency of zero")
+	}
+}
@@ -125,8 +125,8 @@ func (c *Config) AddResolver(resolver string) {
 
 // SetTrustedResolvers assigns the trusted resolver names provided in the parameter to the list in the configuration.
 func (c *Config) SetTrustedResolvers(resolvers ...string) {
-	c.Resolvers = []string{}
-	c.AddResolvers(resolvers...)
+	c.TrustedResolvers = []string{}
+	c.AddTrustedResolvers(resolvers...)
 }
 
 // AddTrustedResolvers appends the trusted resolver names provided in the parameter to the list in the configuration.
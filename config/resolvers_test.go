@@ -43,3 +43,17 @@ func TestConfigSetResolvers(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigSetTrustedResolvers(t *testing.T) {
+	c := &Config{Resolvers: []string{"127.0.0.1"}}
+	resolvers := []string{"127.0.0.2", "127.0.0.3"}
+
+	c.SetTrustedResolvers(resolvers...)
+	sort.Strings(c.TrustedResolvers)
+	if !reflect.DeepEqual(resolvers, c.TrustedResolvers) {
+		t.Errorf("SetTrustedResolvers() = %v, want %v", c.TrustedResolvers, resolvers)
+	}
+	if len(c.Resolvers) != 1 || c.Resolvers[0] != "127.0.0.1" {
+		t.Errorf("SetTrustedResolvers() changed the untrusted resolvers to %v", c.Resolvers)
+	}
+}
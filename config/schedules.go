@@ -0,0 +1,61 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caffix/stringset"
+	"github.com/go-ini/ini"
+)
+
+// Schedule is a scope enumerated again by the daemon mode at the times selected by the cron expression.
+// The Name is the section name without the 'schedules.' prefix, such as 'acme'.
+type Schedule struct {
+	Name      string
+	Cron      *CronExpr
+	Domains   []string
+	Workspace string
+}
+
+func (c *Config) loadScheduleSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("schedules")
+	if err != nil {
+		return nil
+	}
+
+	for _, child := range sec.ChildSections() {
+		name := strings.TrimPrefix(child.Name(), "schedules.")
+
+		if !child.HasKey("cron") {
+			return fmt.Errorf("the %s schedule requires the cron setting", name)
+		}
+		cron, err := ParseCron(child.Key("cron").String())
+		if err != nil {
+			return fmt.Errorf("the %s schedule cron setting is invalid: %v", name, err)
+		}
+
+		var domains []string
+		if child.HasKey("domain") {
+			for _, d := range child.Key("domain").ValueWithShadows() {
+				if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+					domains = append(domains, d)
+				}
+			}
+		}
+		if len(domains) == 0 {
+			return fmt.Errorf("the %s schedule requires the domain setting", name)
+		}
+
+		c.Schedules = append(c.Schedules, &Schedule{
+			Name:      name,
+			Cron:      cron,
+			Domains:   stringset.Deduplicate(domains),
+			Workspace: strings.TrimSpace(child.Key("workspace").String()),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestConfigloadScheduleSettings(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       []byte
+		wantErr   bool
+		schedules int
+	}{
+		{
+			name: "success - multiple scopes",
+			cfg: []byte(`
+			[schedules]
+			[schedules.acme]
+			cron = 0 3 * * *
+			domain = acme.com
+			domain = acme.net
+			workspace = acme
+			[schedules.example]
+			cron = @weekly
+			domain = example.com
+			`),
+			schedules: 2,
+		},
+		{
+			name: "failure - missing cron",
+			cfg: []byte(`
+			[schedules]
+			[schedules.acme]
+			domain = acme.com
+			`),
+			wantErr: true,
+		},
+		{
+			name: "failure - invalid cron",
+			cfg: []byte(`
+			[schedules]
+			[schedules.acme]
+			cron = 0 25 * * *
+			domain = acme.com
+			`),
+			wantErr: true,
+		},
+		{
+			name: "failure - missing domain",
+			cfg: []byte(`
+			[schedules]
+			[schedules.acme]
+			cron = @daily
+			`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			cfg, err := ini.LoadSources(ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			}, tt.cfg)
+			if err != nil {
+				t.Fatalf("Failed to load the test configuration: %v", err)
+			}
+
+			if err := c.loadScheduleSettings(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("Config.loadScheduleSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(c.Schedules) != tt.schedules {
+				t.Errorf("Config.loadScheduleSettings() = %d schedules, want %d", len(c.Schedules), tt.schedules)
+			}
+		})
+	}
+
+	c := NewConfig()
+	cfg, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true, AllowShadows: true}, tests[0].cfg)
+	if err := c.loadScheduleSettings(cfg); err != nil {
+		t.Fatalf("Config.loadScheduleSettings() error = %v", err)
+	}
+	if s := c.Schedules[0]; s.Name != "acme" || len(s.Domains) != 2 || s.Workspace != "acme" || s.Cron.String() != "0 3 * * *" {
+		t.Errorf("Config.loadScheduleSettings() = %s, %v, %s, %s", s.Name, s.Domains, s.Workspace, s.Cron)
+	}
+}
@@ -73,6 +73,15 @@ func (c *Config) AddDomain(domain string) {
 	c.domains = stringset.Deduplicate(c.domains)
 }
 
+// ClearDomains removes the domain names from the configuration, so another scope can be enumerated.
+func (c *Config) ClearDomains() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.domains = nil
+	c.regexps = nil
+}
+
 // Domains returns the list of domain names currently in the configuration.
 func (c *Config) Domains() []string {
 	c.Lock()
@@ -93,17 +102,62 @@ func (c *Config) IsDomainInScope(name string) bool {
 }
 
 // WhichDomain returns the domain in the config list that the DNS name in the parameter ends with.
+// The subdomain names must also pass the include and exclude regular expressions of the scope.
 func (c *Config) WhichDomain(name string) string {
 	n := strings.ToLower(strings.TrimSpace(name))
 
 	for _, d := range c.Domains() {
 		if hasPathSuffix(n, d) {
+			if n != d && !c.scopeRegexAllows(n) {
+				return ""
+			}
 			return d
 		}
 	}
 	return ""
 }
 
+// AddScopeRegex compiles the regular expression, which must match the entire subdomain name, and
+// adds it to the include or exclude rules of the scope.
+func (c *Config) AddScopeRegex(expr string, include bool) error {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if include {
+		c.scopeIncludes = append(c.scopeIncludes, re)
+	} else {
+		c.scopeExcludes = append(c.scopeExcludes, re)
+	}
+	return nil
+}
+
+// scopeRegexAllows returns true when the name matches none of the exclude rules, and at least one
+// of the include rules when they were provided.
+func (c *Config) scopeRegexAllows(name string) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, re := range c.scopeExcludes {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	if len(c.scopeIncludes) == 0 {
+		return true
+	}
+	for _, re := range c.scopeIncludes {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
 func hasPathSuffix(path, suffix string) bool {
 	if strings.HasSuffix(path, suffix) {
 		plen := len(path)
@@ -143,6 +197,49 @@ func (c *Config) IsAddressInScope(addr string) bool {
 	return false
 }
 
+// IsAddressExcluded returns true if the addr parameter falls into one of the excluded address ranges.
+func (c *Config) IsAddressExcluded(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range c.ExcludedCIDRs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTargetHost returns true if the host parameter is a name under one of the root domain names,
+// or one of the addresses provided as the network scope. Unlike the other scope checks, names
+// rejected by the scope regular expressions still belong to the target.
+func (c *Config) IsTargetHost(host string) bool {
+	host = strings.Trim(strings.ToLower(strings.TrimSpace(host)), ".")
+
+	if ip := net.ParseIP(host); ip != nil {
+		for _, a := range c.Addresses {
+			if a.Equal(ip) {
+				return true
+			}
+		}
+		for _, cidr := range c.CIDRs {
+			if cidr.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, d := range c.Domains() {
+		if hasPathSuffix(host, d) {
+			return true
+		}
+	}
+	return false
+}
+
 // BlacklistSubdomain adds a subdomain name to the config blacklist.
 func (c *Config) BlacklistSubdomain(name string) {
 	c.blacklistLock.Lock()
@@ -156,6 +253,8 @@ func (c *Config) BlacklistSubdomain(name string) {
 }
 
 // Blacklisted returns true is the name in the parameter ends with a subdomain name in the config blacklist.
+// The entries can hold wildcards, where '*' matches any characters and '?' matches a single character
+// within a label, such as *-staging.example.com or *.k8s.example.com.
 func (c *Config) Blacklisted(name string) bool {
 	c.blacklistLock.Lock()
 	defer c.blacklistLock.Unlock()
@@ -163,7 +262,22 @@ func (c *Config) Blacklisted(name string) bool {
 	n := strings.ToLower(strings.TrimSpace(name))
 
 	for _, bl := range c.Blacklist {
-		if hasPathSuffix(n, bl) {
+		if !strings.ContainsAny(bl, "*?") {
+			if hasPathSuffix(n, bl) {
+				return true
+			}
+			continue
+		}
+
+		if c.blacklistGlobs == nil {
+			c.blacklistGlobs = make(map[string]*regexp.Regexp)
+		}
+		re, found := c.blacklistGlobs[bl]
+		if !found {
+			re = globRegex(bl)
+			c.blacklistGlobs[bl] = re
+		}
+		if re != nil && re.MatchString(n) {
 			return true
 		}
 	}
@@ -171,6 +285,31 @@ func (c *Config) Blacklisted(name string) bool {
 	return false
 }
 
+// globRegex returns the regular expression matching the names that equal the wildcard pattern,
+// or are subdomains of a name that does.
+func globRegex(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+
+	expr.WriteString(`^(?:.+\.)?`)
+	for _, r := range strings.ToLower(strings.TrimSpace(pattern)) {
+		switch r {
+		case '*':
+			expr.WriteString(`[^.]*`)
+		case '?':
+			expr.WriteString(`[^.]`)
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString(`$`)
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
 func (c *Config) loadScopeSettings(cfg *ini.File) error {
 	scope, err := cfg.GetSection("scope")
 	if err != nil {
@@ -199,6 +338,16 @@ func (c *Config) loadScopeSettings(cfg *ini.File) error {
 		}
 	}
 
+	if scope.HasKey("exclude_cidr") {
+		for _, cidr := range scope.Key("exclude_cidr").ValueWithShadows() {
+			_, ipnet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+			if err != nil {
+				return fmt.Errorf("the scope exclude_cidr setting %s is invalid: %v", cidr, err)
+			}
+			c.ExcludedCIDRs = append(c.ExcludedCIDRs, ipnet)
+		}
+	}
+
 	if scope.HasKey("asn") {
 		for _, asn := range scope.Key("asn").ValueWithShadows() {
 			c.ASNs = uniqueIntAppend(c.ASNs, asn)
@@ -211,6 +360,23 @@ func (c *Config) loadScopeSettings(cfg *ini.File) error {
 		}
 	}
 
+	for _, rule := range []struct {
+		key     string
+		include bool
+	}{
+		{"include_regex", true},
+		{"exclude_regex", false},
+	} {
+		if !scope.HasKey(rule.key) {
+			continue
+		}
+		for _, expr := range scope.Key(rule.key).ValueWithShadows() {
+			if err := c.AddScopeRegex(expr, rule.include); err != nil {
+				return fmt.Errorf("the scope %s setting %s is invalid: %v", rule.key, expr, err)
+			}
+		}
+	}
+
 	// Load up all the DNS domain names
 	if domains, err := cfg.GetSection("scope.domains"); err == nil {
 		for _, domain := range domains.Key("domain").ValueWithShadows() {
@@ -62,6 +62,20 @@ func TestConfigAddDomains(t *testing.T) {
 	}
 }
 
+func TestConfigClearDomains(t *testing.T) {
+	c := new(Config)
+	c.AddDomains("utica.edu", "owasp.org")
+	c.ClearDomains()
+	c.AddDomain("example.com")
+
+	if domains := c.Domains(); len(domains) != 1 || domains[0] != "example.com" {
+		t.Errorf("Config.ClearDomains() left the domains %v", domains)
+	}
+	if c.IsDomainInScope("www.utica.edu") {
+		t.Errorf("Config.ClearDomains() left www.utica.edu in scope")
+	}
+}
+
 func TestConfigParseIPsParseRange(t *testing.T) {
 	type args struct {
 		s string
@@ -382,3 +396,135 @@ func TestLoadScopeSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestScopeRegex(t *testing.T) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{
+		Insensitive:  true,
+		AllowShadows: true,
+	}, []byte(`
+	[scope]
+	include_regex = .*\.example\.com
+	exclude_regex = .*\.cdn\.example\.com
+	exclude_regex = dev[0-9]+\.example\.com
+
+	[scope.domains]
+	domain = example.com
+	domain = owasp.org
+	`))
+	if err != nil {
+		t.Fatalf("Failed to load the test configuration: %v", err)
+	}
+
+	c := NewConfig()
+	if err := c.loadScopeSettings(cfg); err != nil {
+		t.Fatalf("Config.loadScopeSettings() error = %v", err)
+	}
+
+	for name, expected := range map[string]string{
+		"example.com":                 "example.com",
+		"www.example.com":             "example.com",
+		"img.cdn.example.com":         "",
+		"dev12.example.com":           "",
+		"dev12.staging.example.com":   "example.com",
+		"owasp.org":                   "owasp.org",
+		"www.owasp.org":               "",
+		"www.example.com.example.net": "",
+	} {
+		if d := c.WhichDomain(name); d != expected {
+			t.Errorf("Config.WhichDomain(%s) = %q, expected %q", name, d, expected)
+		}
+	}
+	if c.IsDomainInScope("img.cdn.example.com") {
+		t.Error("Config.IsDomainInScope() returned true for the excluded name")
+	}
+
+	if err := c.AddScopeRegex("(unbalanced", false); err == nil {
+		t.Error("Config.AddScopeRegex() did not report the invalid regular expression")
+	}
+}
+
+func TestExcludedCIDRs(t *testing.T) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{
+		Insensitive:  true,
+		AllowShadows: true,
+	}, []byte(`
+	[scope]
+	exclude_cidr = 203.0.113.0/24
+	exclude_cidr = 2001:db8::/32
+	`))
+	if err != nil {
+		t.Fatalf("Failed to load the test configuration: %v", err)
+	}
+
+	c := NewConfig()
+	if err := c.loadScopeSettings(cfg); err != nil {
+		t.Fatalf("Config.loadScopeSettings() error = %v", err)
+	}
+
+	for addr, expected := range map[string]bool{
+		"203.0.113.25":   true,
+		"203.0.114.1":    false,
+		"2001:db8::1":    true,
+		"2001:db9::1":    false,
+		"not an address": false,
+	} {
+		if got := c.IsAddressExcluded(addr); got != expected {
+			t.Errorf("Config.IsAddressExcluded(%s) = %v, expected %v", addr, got, expected)
+		}
+	}
+
+	cfg, _ = ini.LoadSources(ini.LoadOptions{Insensitive: true, AllowShadows: true}, []byte(`
+	[scope]
+	exclude_cidr = 203.0.113.0/33
+	`))
+	if err := NewConfig().loadScopeSettings(cfg); err == nil {
+		t.Error("Config.loadScopeSettings() did not report the invalid exclude_cidr setting")
+	}
+}
+
+func TestIsTargetHost(t *testing.T) {
+	c := NewConfig()
+	c.AddDomains("example.com")
+	_ = c.AddScopeRegex(`.*\.cdn\.example\.com`, false)
+	c.Addresses = []net.IP{net.ParseIP("192.0.2.10")}
+	_, cidr, _ := net.ParseCIDR("198.51.100.0/24")
+	c.CIDRs = []*net.IPNet{cidr}
+
+	for host, expected := range map[string]bool{
+		"example.com":         true,
+		"WWW.Example.com.":    true,
+		"img.cdn.example.com": true,
+		"notexample.com":      false,
+		"api.github.com":      false,
+		"192.0.2.10":          true,
+		"192.0.2.11":          false,
+		"198.51.100.200":      true,
+	} {
+		if got := c.IsTargetHost(host); got != expected {
+			t.Errorf("Config.IsTargetHost(%s) = %v, expected %v", host, got, expected)
+		}
+	}
+}
+
+func TestConfigBlacklistedWildcards(t *testing.T) {
+	c := new(Config)
+	c.Blacklist = []string{"*-staging.example.com", "*.k8s.example.com", "db?.example.com", "internal.example.com"}
+
+	for name, expected := range map[string]bool{
+		"api-staging.example.com":      true,
+		"v2.api-staging.example.com":   true,
+		"staging.example.com":          false,
+		"api-staging.prod.example.com": false,
+		"node1.k8s.example.com":        true,
+		"a.b.k8s.example.com":          true,
+		"k8s.example.com":              false,
+		"db1.example.com":              true,
+		"db12.example.com":             false,
+		"www.internal.example.com":     true,
+		"www.example.com":              false,
+	} {
+		if got := c.Blacklisted(name); got != expected {
+			t.Errorf("Config.Blacklisted(%s) = %v, expected %v", name, got, expected)
+		}
+	}
+}
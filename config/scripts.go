@@ -19,23 +19,41 @@ func (c *Config) AcquireScripts() ([]string, error) {
 		return scripts, err
 	}
 
+	paths, err := c.ScriptPaths()
+	for _, path := range paths {
+		// Get the script content
+		data, rerr := os.ReadFile(path)
+		if rerr != nil {
+			continue
+		}
+
+		scripts = append(scripts, string(data))
+	}
+
+	return scripts, err
+}
+
+// ScriptPaths returns the paths of the user provided scripts, found in the scripts directory of
+// the output directory and in the directory selected by the configuration.
+func (c *Config) ScriptPaths() ([]string, error) {
 	dir := OutputDirectory(c.Dir)
 	if dir == "" {
-		return scripts, nil
+		return nil, nil
 	}
 
 	finfo, err := os.Stat(dir)
 	if os.IsNotExist(err) || !finfo.IsDir() {
-		return scripts, errors.New("the output directory does not exist or is not a directory")
+		return nil, errors.New("the output directory does not exist or is not a directory")
 	}
 
-	paths := []string{filepath.Join(dir, "scripts")}
+	dirs := []string{filepath.Join(dir, "scripts")}
 	if c.ScriptsDirectory != "" {
-		paths = append(paths, c.ScriptsDirectory)
+		dirs = append(dirs, c.ScriptsDirectory)
 	}
 
-	for _, path := range paths {
-		_ = filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+	var paths []string
+	for _, dir := range dirs {
+		_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
@@ -43,16 +61,11 @@ func (c *Config) AcquireScripts() ([]string, error) {
 			if info.IsDir() || filepath.Ext(info.Name()) != ".ads" {
 				return nil
 			}
-			// Get the script content
-			data, err := os.ReadFile(path)
-			if err != nil {
-				return err
-			}
 
-			scripts = append(scripts, string(data))
+			paths = append(paths, path)
 			return nil
 		})
 	}
 
-	return scripts, nil
+	return paths, nil
 }
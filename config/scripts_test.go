@@ -0,0 +1,45 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestConfigScriptPaths(t *testing.T) {
+	c := NewConfig()
+	c.Dir = t.TempDir()
+	c.ScriptsDirectory = t.TempDir()
+
+	files := []string{
+		filepath.Join(c.Dir, "scripts", "api", "example.ads"),
+		filepath.Join(c.Dir, "scripts", "README.md"),
+		filepath.Join(c.ScriptsDirectory, "custom.ads"),
+	}
+	for _, path := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create the scripts directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(`name = "Example"`), 0644); err != nil {
+			t.Fatalf("Failed to write the %s file: %v", path, err)
+		}
+	}
+
+	got, err := c.ScriptPaths()
+	if err != nil {
+		t.Fatalf("ScriptPaths() error = %v", err)
+	}
+
+	want := []string{files[0], files[2]}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScriptPaths() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/caffix/stringset"
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadTakeoverSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("takeover")
+	if err != nil {
+		return nil
+	}
+
+	c.Takeovers = sec.Key("enabled").MustBool(true)
+	if !c.Takeovers {
+		return nil
+	}
+
+	if sec.HasKey("fingerprints_file") {
+		for _, path := range sec.Key("fingerprints_file").ValueWithShadows() {
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("unable to load the file in the takeover fingerprints_file setting: %s: %v", path, err)
+			}
+			c.TakeoverFingerprints = append(c.TakeoverFingerprints, path)
+		}
+	}
+
+	c.TakeoverFingerprints = stringset.Deduplicate(c.TakeoverFingerprints)
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestConfigloadTakeoverSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     []byte
+		wantErr bool
+		enabled bool
+	}{
+		{
+			name: "success - enabled",
+			cfg: []byte(`
+			[takeover]
+			enabled = true
+			`),
+			enabled: true,
+		},
+		{
+			name: "success - disabled",
+			cfg: []byte(`
+			[takeover]
+			enabled = false
+			fingerprints_file = /path/does/not/exist.json
+			`),
+			enabled: false,
+		},
+		{
+			name: "failure - missing file",
+			cfg: []byte(`
+			[takeover]
+			fingerprints_file = /path/does/not/exist.json
+			`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			cfg, err := ini.LoadSources(ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			}, tt.cfg)
+			if err != nil {
+				t.Fatalf("Failed to load the test configuration: %v", err)
+			}
+
+			if err := c.loadTakeoverSettings(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("Config.loadTakeoverSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && c.Takeovers != tt.enabled {
+				t.Errorf("Config.loadTakeoverSettings() enabled = %t, want %t", c.Takeovers, tt.enabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caffix/stringset"
+	"github.com/go-ini/ini"
+)
+
+// DefaultExpansionTLDs is the list of top-level domains checked during TLD expansion when none are configured.
+var DefaultExpansionTLDs = []string{
+	"com", "net", "org", "info", "biz", "io", "co", "dev", "app", "ai", "cloud", "tech",
+	"online", "site", "xyz", "me", "tv", "us", "ca", "uk", "co.uk", "eu", "de", "fr",
+	"nl", "es", "it", "ch", "se", "ru", "cn", "jp", "in", "br", "au", "com.au",
+}
+
+func (c *Config) loadTLDExpansionSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("tld_expansion")
+	if err != nil {
+		return nil
+	}
+
+	var tlds []string
+	if sec.HasKey("tld") {
+		tlds = append(tlds, sec.Key("tld").ValueWithShadows()...)
+	}
+	if sec.HasKey("tld_file") {
+		for _, path := range sec.Key("tld_file").ValueWithShadows() {
+			list, err := GetListFromFile(path)
+			if err != nil {
+				return fmt.Errorf("unable to load the file in the tld_expansion tld_file setting: %s: %v", path, err)
+			}
+			tlds = append(tlds, list...)
+		}
+	}
+
+	c.AddExpansionTLDs(tlds...)
+	return nil
+}
+
+// AddExpansionTLDs appends the provided top-level domains to the list checked during TLD expansion.
+func (c *Config) AddExpansionTLDs(tlds ...string) {
+	for _, tld := range tlds {
+		if t := strings.ToLower(strings.Trim(strings.TrimSpace(tld), ".")); t != "" {
+			c.ExpansionTLDs = append(c.ExpansionTLDs, t)
+		}
+	}
+
+	c.ExpansionTLDs = stringset.Deduplicate(c.ExpansionTLDs)
+}
@@ -0,0 +1,67 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestConfigloadTLDExpansionSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     []byte
+		wantErr bool
+		want    int
+	}{
+		{
+			name: "success - shadowed keys",
+			cfg: []byte(`
+			[tld_expansion]
+			tld = io
+			tld = .DEV
+			tld = io
+			`),
+			want: 2,
+		},
+		{
+			name: "failure - missing file",
+			cfg: []byte(`
+			[tld_expansion]
+			tld_file = /path/does/not/exist.txt
+			`),
+			wantErr: true,
+		},
+		{
+			name: "success - missing section",
+			cfg: []byte(`
+			[scope]
+			port = 443
+			`),
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			cfg, err := ini.LoadSources(ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			}, tt.cfg)
+			if err != nil {
+				t.Fatalf("Failed to load the test configuration: %v", err)
+			}
+
+			if err := c.loadTLDExpansionSettings(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("Config.loadTLDExpansionSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(c.ExpansionTLDs) != tt.want {
+				t.Errorf("Config.loadTLDExpansionSettings() got %v, want %d TLDs", c.ExpansionTLDs, tt.want)
+			}
+		})
+	}
+}
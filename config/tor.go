@@ -0,0 +1,39 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadTorSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("tor")
+	if err != nil {
+		return nil
+	}
+
+	c.Tor = sec.Key("enabled").MustBool(true)
+	if sec.HasKey("socks") {
+		c.TorSOCKSAddr = strings.TrimSpace(sec.Key("socks").String())
+	}
+	if sec.HasKey("control") {
+		c.TorControlAddr = strings.TrimSpace(sec.Key("control").String())
+	}
+	c.TorControlPassword = sec.Key("control_password").String()
+
+	for name, addr := range map[string]string{
+		"socks":   c.TorSOCKSAddr,
+		"control": c.TorControlAddr,
+	} {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("the tor %s setting %s must be a host and port: %v", name, addr, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/go-ini/ini"
+	"github.com/owasp-amass/amass/v3/net/tor"
+)
+
+func TestConfigloadTorSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     []byte
+		wantErr bool
+		socks   string
+		control string
+	}{
+		{
+			name: "success - default addresses",
+			cfg: []byte(`
+			[tor]
+			enabled = true
+			`),
+			socks:   tor.DefaultSOCKSAddr,
+			control: tor.DefaultControlAddr,
+		},
+		{
+			name: "success - custom addresses",
+			cfg: []byte(`
+			[tor]
+			socks = 127.0.0.1:9150
+			control = 127.0.0.1:9151
+			control_password = secret
+			`),
+			socks:   "127.0.0.1:9150",
+			control: "127.0.0.1:9151",
+		},
+		{
+			name: "failure - missing port",
+			cfg: []byte(`
+			[tor]
+			socks = 127.0.0.1
+			`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			cfg, err := ini.LoadSources(ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			}, tt.cfg)
+			if err != nil {
+				t.Fatalf("Failed to load the test configuration: %v", err)
+			}
+
+			if err := c.loadTorSettings(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("Config.loadTorSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !c.Tor || c.TorSOCKSAddr != tt.socks || c.TorControlAddr != tt.control {
+				t.Errorf("Config.loadTorSettings() = %v, %s, %s", c.Tor, c.TorSOCKSAddr, c.TorControlAddr)
+			}
+		})
+	}
+}
+
+func TestTorSourceProxy(t *testing.T) {
+	c := NewConfig()
+	c.DataSourceProxy = "http://egress.example.com:3128"
+	c.Tor = true
+
+	seen := make(map[string]bool)
+	for _, src := range []string{"Shodan", "Censys", "CommonCrawl"} {
+		u, err := url.Parse(c.SourceProxy(src))
+		if err != nil || u.Scheme != "socks5" || u.Host != tor.DefaultSOCKSAddr {
+			t.Errorf("The %s data source is not sent through Tor: %s", src, c.SourceProxy(src))
+			continue
+		}
+		// Each data source must be isolated onto its own circuit
+		if seen[u.User.String()] {
+			t.Errorf("The %s data source shares the Tor credentials of another data source", src)
+		}
+		seen[u.User.String()] = true
+	}
+}
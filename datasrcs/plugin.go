@@ -0,0 +1,263 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/caffix/service"
+	"github.com/owasp-amass/amass/v3/net/http"
+	"github.com/owasp-amass/amass/v3/plugins"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+// The time allowed for a plugin to describe, start and stop its data source
+const pluginCallTimeout = 30 * time.Second
+
+// Plugin is the Service that handles access to a data source provided by an external plugin.
+type Plugin struct {
+	service.BaseService
+
+	SourceType string
+	sys        systems.System
+	path       string
+	info       *plugins.SourceInfo
+	lock       sync.Mutex
+	client     *plugins.Client
+	src        plugins.DataSource
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+// NewPlugin returns the object initialized, but not yet started. The plugin process is only
+// kept running while the service is started.
+func NewPlugin(path string, sys systems.System) (*Plugin, error) {
+	client, err := plugins.NewClient(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Kill()
+
+	src, err := client.DataSource()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginCallTimeout)
+	defer cancel()
+
+	info, err := src.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if info.Name == "" {
+		return nil, fmt.Errorf("the %s plugin did not provide the name of its data source", path)
+	}
+
+	p := &Plugin{
+		SourceType: info.Type,
+		sys:        sys,
+		path:       path,
+		info:       info,
+	}
+	if p.SourceType == "" {
+		p.SourceType = requests.API
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	p.BaseService = *service.NewBaseService(p, info.Name)
+	go p.requests()
+	return p, nil
+}
+
+// Description implements the Service interface.
+func (p *Plugin) Description() string {
+	return p.SourceType
+}
+
+// OnStart implements the Service interface.
+func (p *Plugin) OnStart() error {
+	client, err := plugins.NewClient(p.path, p.sys.Config().Log)
+	if err != nil {
+		return err
+	}
+
+	src, err := client.DataSource()
+	if err != nil {
+		client.Kill()
+		return err
+	}
+
+	cfg := p.sys.Config()
+	scfg := &plugins.SourceConfig{
+		Settings: cfg.GetPluginSettings(p.String()),
+		Domains:  cfg.Domains(),
+	}
+	if dsc := cfg.GetDataSourceConfig(p.String()); dsc != nil {
+		scfg.TTL = dsc.TTL
+		if cr := dsc.GetCredentials(); cr != nil {
+			scfg.Credentials = append(scfg.Credentials, cr)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(p.ctx, pluginCallTimeout)
+	defer cancel()
+
+	if err := src.Start(ctx, scfg); err != nil {
+		client.Kill()
+		return fmt.Errorf("%s: start: %v", p.String(), err)
+	}
+
+	p.lock.Lock()
+	p.client = client
+	p.src = src
+	p.lock.Unlock()
+	return nil
+}
+
+// OnStop implements the Service interface.
+func (p *Plugin) OnStop() error {
+	p.cancel()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.client == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginCallTimeout)
+	defer cancel()
+
+	err := p.src.Stop(ctx)
+	p.client.Kill()
+	p.client = nil
+	p.src = nil
+	if err != nil {
+		return fmt.Errorf("%s: stop: %v", p.String(), err)
+	}
+	return nil
+}
+
+// HandlesReq implements the Service interface.
+func (p *Plugin) HandlesReq(req interface{}) bool {
+	switch t := req.(type) {
+	case *requests.DNSRequest:
+		return p.info.Vertical && t != nil && t.Domain != ""
+	case *requests.WhoisRequest:
+		return p.info.Horizontal && t != nil && t.Domain != ""
+	case *requests.AddrRequest:
+		return p.info.Address && t != nil && t.Address != ""
+	}
+	return false
+}
+
+func (p *Plugin) requests() {
+	for {
+		select {
+		case <-p.Done():
+			return
+		case <-p.ctx.Done():
+			return
+		case in := <-p.Input():
+			src := p.dataSource()
+			if src == nil || !p.HandlesReq(in) {
+				continue
+			}
+
+			var err error
+			switch req := in.(type) {
+			case *requests.DNSRequest:
+				p.sys.Config().Log.Printf("Querying %s for %s subdomains", p.String(), req.Domain)
+				err = src.Vertical(p.ctx, req.Domain, func(name string) {
+					p.newName(name)
+				})
+			case *requests.WhoisRequest:
+				err = src.Horizontal(p.ctx, req.Domain, func(assoc string) {
+					p.associated(req.Domain, assoc)
+				})
+			case *requests.AddrRequest:
+				err = src.Address(p.ctx, req.Address, func(name string) {
+					p.newName(name)
+				})
+			}
+			if err != nil && !errors.Is(p.ctx.Err(), context.Canceled) {
+				p.sys.Config().Log.Printf("%s: %v", p.String(), err)
+			}
+		}
+	}
+}
+
+func (p *Plugin) dataSource() plugins.DataSource {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.src
+}
+
+func (p *Plugin) newName(name string) {
+	n := http.CleanName(name)
+	if n == "" {
+		return
+	}
+
+	if domain := p.sys.Config().WhichDomain(n); domain != "" {
+		req := requests.NewDNSRequest(n, domain, p.SourceType, p.String())
+
+		select {
+		case <-p.ctx.Done():
+			requests.ReleaseDNSRequest(req)
+		case <-p.Done():
+			requests.ReleaseDNSRequest(req)
+		case p.Output() <- req:
+		}
+	}
+}
+
+func (p *Plugin) associated(domain, assoc string) {
+	if assoc = http.CleanName(assoc); assoc == "" || assoc == domain {
+		return
+	}
+
+	select {
+	case <-p.ctx.Done():
+	case <-p.Done():
+	case p.Output() <- &requests.WhoisRequest{
+		Domain:     domain,
+		NewDomains: []string{assoc},
+		Tag:        p.SourceType,
+		Source:     p.String(),
+	}:
+	}
+}
+
+// pluginSources returns the data sources provided by the plugins in the plugins directories. The
+// plugins providing only output sinks are left out.
+func pluginSources(sys systems.System) []service.Service {
+	cfg := sys.Config()
+
+	paths, err := cfg.AcquirePlugins()
+	if err != nil {
+		return nil
+	}
+
+	var srvs []service.Service
+	for _, path := range paths {
+		p, err := NewPlugin(path, sys)
+		if err != nil {
+			if !plugins.Unimplemented(err) {
+				cfg.Log.Printf("Plugin: %v", err)
+			}
+			continue
+		}
+		srvs = append(srvs, p)
+	}
+	return srvs
+}
@@ -57,11 +57,13 @@ func (r *RADb) Description() string {
 // OnStart implements the Service interface.
 func (r *RADb) OnStart() error {
 	msg := resolve.QueryMsg(radbWhoisURL, dns.TypeA)
-	if resp, err := r.sys.TrustedResolvers().QueryBlocking(context.TODO(), msg); err == nil {
-		if ans := resolve.ExtractAnswers(resp); len(ans) > 0 {
-			ip := ans[0].Data
-			if ip != "" {
-				r.addr = ip
+	if err := amassnet.Audit.Allow("dns", radbWhoisURL); err == nil {
+		if resp, err := r.sys.TrustedResolvers().QueryBlocking(context.TODO(), msg); err == nil {
+			if ans := resolve.ExtractAnswers(resp); len(ans) > 0 {
+				ip := ans[0].Data
+				if ip != "" {
+					r.addr = ip
+				}
 			}
 		}
 	}
@@ -321,6 +323,9 @@ func (r *RADb) ipToASN(ctx context.Context, cidr string) int {
 	numRateLimitChecks(r, 2)
 	if r.addr == "" {
 		msg := resolve.QueryMsg(radbWhoisURL, dns.TypeA)
+		if err := amassnet.Audit.Allow("dns", radbWhoisURL); err != nil {
+			return 0
+		}
 		resp, err := r.sys.TrustedResolvers().QueryBlocking(ctx, msg)
 		if err != nil {
 			r.sys.Config().Log.Printf("%s: %s: %v", r.String(), radbWhoisURL, err)
@@ -0,0 +1,214 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/caffix/service"
+	"github.com/caffix/stringset"
+	"github.com/owasp-amass/amass/v3/net/rdap"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+	"golang.org/x/net/publicsuffix"
+)
+
+// RDAP is the Service that handles access to the registration data of the regional internet registries.
+type RDAP struct {
+	service.BaseService
+
+	SourceType string
+	sys        systems.System
+	client     *rdap.Client
+}
+
+// NewRDAP returns the object initialized, but not yet started.
+func NewRDAP(sys systems.System) *RDAP {
+	r := &RDAP{
+		SourceType: requests.RIR,
+		sys:        sys,
+		client:     rdap.NewClient(),
+	}
+
+	r.BaseService = *service.NewBaseService(r, "RDAP")
+	go r.requests()
+	return r
+}
+
+// Description implements the Service interface.
+func (r *RDAP) Description() string {
+	return r.SourceType
+}
+
+// HandlesReq implements the Service interface.
+func (r *RDAP) HandlesReq(req interface{}) bool {
+	switch t := req.(type) {
+	case *requests.ASNRequest:
+		return t != nil && (t.Address != "" || t.ASN != 0)
+	case *requests.WhoisRequest:
+		return t != nil && t.Domain != ""
+	}
+	return false
+}
+
+// OnStart implements the Service interface.
+func (r *RDAP) OnStart() error {
+	if cfg := r.sys.Config().GetDataSourceConfig(r.String()); cfg != nil && cfg.TTL > 0 {
+		r.client.TTL = time.Duration(cfg.TTL) * time.Minute
+	}
+
+	r.SetRateLimit(1)
+	return nil
+}
+
+func (r *RDAP) requests() {
+	for {
+		select {
+		case <-r.Done():
+			return
+		case in := <-r.Input():
+			switch req := in.(type) {
+			case *requests.ASNRequest:
+				r.CheckRateLimit()
+				r.asnRequest(context.TODO(), req)
+			case *requests.WhoisRequest:
+				r.CheckRateLimit()
+				r.whoisRequest(context.TODO(), req)
+			}
+		}
+	}
+}
+
+func (r *RDAP) asnRequest(ctx context.Context, req *requests.ASNRequest) {
+	if req.Address == "" && req.ASN == 0 {
+		return
+	}
+
+	asn := req.ASN
+	var prefix string
+	var netblocks []string
+	if req.Address != "" {
+		n, err := r.client.IPNetwork(ctx, req.Address)
+		if err != nil {
+			r.sys.Config().Log.Printf("%s: %s: %v", r.String(), req.Address, err)
+			return
+		} else if len(n.CIDRs) == 0 {
+			r.sys.Config().Log.Printf("%s: %s: The query returned zero netblocks", r.String(), req.Address)
+			return
+		}
+
+		prefix = n.CIDRs[0]
+		netblocks = n.CIDRs
+		if asn == 0 && len(n.OriginASNs) > 0 {
+			asn = n.OriginASNs[0]
+		}
+	}
+	if asn == 0 {
+		return
+	}
+
+	r.CheckRateLimit()
+	a, err := r.client.Autnum(ctx, asn)
+	if err != nil {
+		r.sys.Config().Log.Printf("%s: AS%d: %v", r.String(), asn, err)
+		return
+	}
+	if prefix == "" {
+		if as := r.sys.Cache().ASNSearch(asn); as == nil || as.Prefix == "" {
+			// The cache requires at least one netblock for the ASN entry
+			return
+		}
+	}
+
+	r.sys.Cache().Update(&requests.ASNRequest{
+		Address:        req.Address,
+		ASN:            asn,
+		Prefix:         prefix,
+		CC:             a.Country,
+		AllocationDate: rdap.FindEvent(a.Events, "registration"),
+		Description:    a.Name,
+		Netblocks:      netblocks,
+		Tag:            r.SourceType,
+		Source:         r.String(),
+	})
+}
+
+// whoisRequest looks up the domain registration in place of scraping the whois servers. The registered
+// domains of the name servers are reported as associated when they share the registrant of the domain.
+func (r *RDAP) whoisRequest(ctx context.Context, req *requests.WhoisRequest) {
+	domain, err := publicsuffix.EffectiveTLDPlusOne(strings.ToLower(req.Domain))
+	if err != nil {
+		return
+	}
+
+	d, err := r.client.Domain(ctx, domain)
+	if err != nil {
+		r.sys.Config().Log.Printf("%s: %s: %v", r.String(), domain, err)
+		return
+	}
+
+	org, email := registrant(d)
+	if org == "" {
+		return
+	}
+
+	assoc := stringset.New()
+	defer assoc.Close()
+
+	checked := stringset.New(domain)
+	defer checked.Close()
+	for _, ns := range d.Nameservers {
+		nsdom, err := publicsuffix.EffectiveTLDPlusOne(ns)
+		if err != nil || checked.Has(nsdom) {
+			continue
+		}
+		checked.Insert(nsdom)
+
+		r.CheckRateLimit()
+		if nd, err := r.client.Domain(ctx, nsdom); err == nil {
+			if o, _ := registrant(nd); strings.EqualFold(o, org) {
+				assoc.Insert(nsdom)
+			}
+		}
+	}
+	if assoc.Len() == 0 {
+		return
+	}
+
+	select {
+	case <-r.Done():
+	case r.Output() <- &requests.WhoisRequest{
+		Domain:     domain,
+		Company:    org,
+		Email:      email,
+		NewDomains: assoc.Slice(),
+		Tag:        r.SourceType,
+		Source:     r.String(),
+	}:
+	}
+}
+
+// registrant returns the organization and email address of the domain registrant, or empty
+// strings when the registration data is redacted.
+func registrant(d *rdap.Domain) (string, string) {
+	e := rdap.FindEntity(d.Entities, "registrant")
+	if e == nil {
+		return "", ""
+	}
+
+	org := e.Org
+	if org == "" {
+		org = e.Name
+	}
+	lower := strings.ToLower(org)
+	for _, s := range []string{"redacted", "privacy", "proxy", "not disclosed", "withheld"} {
+		if strings.Contains(lower, s) {
+			return "", ""
+		}
+	}
+	return org, e.Email
+}
@@ -0,0 +1,98 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+func testDomainObject(name, org string, nameservers ...string) string {
+	var ns string
+	for i, n := range nameservers {
+		if i > 0 {
+			ns += ","
+		}
+		ns += fmt.Sprintf(`{"objectClassName": "nameserver", "ldhName": "%s"}`, n)
+	}
+
+	return fmt.Sprintf(`{
+  "objectClassName": "domain",
+  "ldhName": "%s",
+  "nameservers": [%s],
+  "entities": [{
+    "objectClassName": "entity",
+    "roles": ["registrant"],
+    "vcardArray": ["vcard", [["fn", {}, "text", "Domain Admin"], ["org", {}, "text", "%s"], ["email", {}, "text", "admin@%s"]]]
+  }]
+}`, name, ns, org, name)
+}
+
+func TestRDAPWhoisRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/boot/dns.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"services": [[["org", "net", "com"], ["%s/rdap/"]]]}`, srv.URL)
+	})
+	objects := map[string]string{
+		"owasp.org":     testDomainObject("owasp.org", "OWASP Foundation", "ns1.owasp-dns.net", "ns2.owasp-dns.net", "ns.cloudhost.com"),
+		"owasp-dns.net": testDomainObject("owasp-dns.net", "OWASP Foundation"),
+		"cloudhost.com": testDomainObject("cloudhost.com", "Cloud Hosting Inc"),
+		"private.org":   testDomainObject("private.org", "REDACTED FOR PRIVACY", "ns1.owasp-dns.net"),
+	}
+	for name, obj := range objects {
+		body := obj
+		mux.HandleFunc("/rdap/domain/"+name, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		})
+	}
+
+	cfg := config.NewConfig()
+	r := NewRDAP(&systems.SimpleSystem{Cfg: cfg, ASNCache: requests.NewASNCache()})
+	r.client.BootstrapURL = srv.URL + "/boot/"
+
+	tests := []struct {
+		domain   string
+		expected []string
+	}{
+		{domain: "www.owasp.org", expected: []string{"owasp-dns.net"}},
+		{domain: "private.org", expected: nil},
+		{domain: "missing.org", expected: nil},
+	}
+
+	for _, tt := range tests {
+		go r.whoisRequest(context.Background(), &requests.WhoisRequest{Domain: tt.domain})
+
+		var got []string
+		select {
+		case out := <-r.Output():
+			if w, ok := out.(*requests.WhoisRequest); ok {
+				got = w.NewDomains
+				if w.Company != "OWASP Foundation" || w.Source != "RDAP" {
+					t.Errorf("whoisRequest(%s) provided the registrant %s from %s", tt.domain, w.Company, w.Source)
+				}
+			}
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+			t.Errorf("whoisRequest(%s) = %v, expected %v", tt.domain, got, tt.expected)
+		}
+	}
+
+	if !r.HandlesReq(&requests.WhoisRequest{Domain: "owasp.org"}) || r.HandlesReq(&requests.DNSRequest{Domain: "owasp.org"}) {
+		t.Errorf("HandlesReq() selected the wrong requests")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"os"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/datasrcs/scripting"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+// ScriptWatcher reloads the user provided data source scripts that were added or changed since
+// the data sources of the system were started, and removes the data sources of deleted scripts.
+type ScriptWatcher struct {
+	sys     systems.System
+	modtime map[string]time.Time
+}
+
+// NewScriptWatcher returns a ScriptWatcher for the scripts currently found in the scripts directories.
+func NewScriptWatcher(sys systems.System) *ScriptWatcher {
+	w := &ScriptWatcher{
+		sys:     sys,
+		modtime: make(map[string]time.Time),
+	}
+
+	for path, mod := range w.scripts() {
+		w.modtime[path] = mod
+	}
+	return w
+}
+
+// Reload loads the scripts added or changed since the last check, and replaces the running data
+// sources of the same name. The scripts that fail to load are logged, and the running data source
+// is kept in place. The data sources loaded from scripts that were deleted are stopped and removed.
+// The number of data sources replaced, added to or removed from the system is returned.
+func (w *ScriptWatcher) Reload() int {
+	var count int
+	cfg := w.sys.Config()
+
+	current := w.scripts()
+	for path := range w.modtime {
+		if _, found := current[path]; found {
+			continue
+		}
+
+		delete(w.modtime, path)
+		if s := w.loadedFrom(path); s != nil {
+			if err := w.sys.RemoveSource(s.String()); err == nil {
+				cfg.Log.Printf("Script: Removed the %s data source, since %s was deleted", s.String(), path)
+				count++
+			}
+		}
+	}
+
+	for path, mod := range current {
+		if last, found := w.modtime[path]; found && last.Equal(mod) {
+			continue
+		}
+		// The script is not checked again until it changes
+		w.modtime[path] = mod
+
+		s := loadScriptFile(w.sys, path)
+		if s == nil {
+			continue
+		}
+
+		old := w.loadedFrom(path)
+		s.SetWorkers(w.workers(s))
+		if err := w.sys.ReplaceSource(s); err != nil {
+			cfg.Log.Printf("Script: Failed to start %s: %v", path, err)
+			continue
+		}
+		// The data source previously loaded from the file is removed when the script was renamed
+		if old != nil && old.String() != s.String() {
+			_ = w.sys.RemoveSource(old.String())
+		}
+
+		cfg.Log.Printf("Script: Loaded the %s data source from %s", s.String(), path)
+		count++
+	}
+	return count
+}
+
+// loadedFrom returns the running data source that was loaded from the script at path.
+func (w *ScriptWatcher) loadedFrom(path string) *scripting.Script {
+	for _, src := range w.sys.DataSources() {
+		if s, ok := src.(*scripting.Script); ok && s.Path == path {
+			return s
+		}
+	}
+	return nil
+}
+
+// loadScriptFile returns the script found at path, or logs the error that prevented it from loading.
+func loadScriptFile(sys systems.System, path string) *scripting.Script {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		sys.Config().Log.Printf("Script: Failed to read %s: %v", path, err)
+		return nil
+	}
+
+	s, err := scripting.LoadScript(string(data), sys)
+	if err != nil {
+		sys.Config().Log.Printf("Script: Failed to load %s: %v", path, err)
+		return nil
+	}
+
+	s.Path = path
+	return s
+}
+
+func (w *ScriptWatcher) scripts() map[string]time.Time {
+	paths, _ := w.sys.Config().ScriptPaths()
+
+	scripts := make(map[string]time.Time, len(paths))
+	for _, path := range paths {
+		if finfo, err := os.Stat(path); err == nil {
+			scripts[path] = finfo.ModTime()
+		}
+	}
+	return scripts
+}
+
+// workers returns the worker slots shared by the running scripts of the same class as the
+// provided script, so the reloaded scripts are limited along with the other data sources.
+func (w *ScriptWatcher) workers(s *scripting.Script) chan struct{} {
+	for _, src := range w.sys.DataSources() {
+		if other, ok := src.(*scripting.Script); ok && generator(other) == generator(s) {
+			return other.Workers()
+		}
+	}
+
+	cfg := w.sys.Config()
+	if generator(s) {
+		return workerSlots(cfg.BruteForceWorkers)
+	}
+	return workerSlots(cfg.DataSourceWorkers)
+}
+
+func generator(s *scripting.Script) bool {
+	switch s.SourceType {
+	case requests.BRUTE, requests.ALT, requests.GUESS:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/datasrcs/scripting"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+func writeTestScript(t *testing.T, path, name string, mod time.Time) {
+	script := fmt.Sprintf("name = %q\ntype = \"api\"\n", name)
+
+	if err := os.WriteFile(path, []byte(script), 0600); err != nil {
+		t.Fatalf("failed to write the script: %v", err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatalf("failed to set the modification time: %v", err)
+	}
+}
+
+func TestScriptWatcherReload(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Dir = t.TempDir()
+	dir := filepath.Join(cfg.Dir, "scripts")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatalf("failed to create the scripts directory: %v", err)
+	}
+
+	sys := &systems.SimpleSystem{Cfg: cfg, ASNCache: requests.NewASNCache()}
+	defer func() { _ = sys.Shutdown() }()
+
+	now := time.Now()
+	path := filepath.Join(dir, "test.ads")
+	w := NewScriptWatcher(sys)
+	running := func() string {
+		if s, ok := sys.Service.(*scripting.Script); ok {
+			return s.String()
+		}
+		return ""
+	}
+
+	steps := []struct {
+		name     string
+		change   func()
+		count    int
+		expected string
+	}{
+		{
+			name:     "added",
+			change:   func() { writeTestScript(t, path, "Testing", now) },
+			count:    1,
+			expected: "Testing",
+		},
+		{
+			name:     "unchanged",
+			change:   func() {},
+			count:    0,
+			expected: "Testing",
+		},
+		{
+			name:     "failed to load",
+			change:   func() { _ = os.WriteFile(path, []byte("name = "), 0600) },
+			count:    0,
+			expected: "Testing",
+		},
+		{
+			name:     "renamed",
+			change:   func() { writeTestScript(t, path, "Renamed", now.Add(time.Minute)) },
+			count:    1,
+			expected: "Renamed",
+		},
+		{
+			name:     "deleted",
+			change:   func() { _ = os.Remove(path) },
+			count:    1,
+			expected: "",
+		},
+	}
+
+	for _, step := range steps {
+		step.change()
+
+		if got := w.Reload(); got != step.count {
+			t.Errorf("Reload() after the script was %s = %d, expected %d", step.name, got, step.count)
+		}
+		if got := running(); got != step.expected {
+			t.Errorf("the script was %s and the %q data source is running, expected %q", step.name, got, step.expected)
+		}
+	}
+}
@@ -7,14 +7,43 @@ package scripting
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/gob"
+	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/owasp-amass/amass/v3/config"
 	"github.com/owasp-amass/amass/v3/net/http"
+	lua "github.com/yuin/gopher-lua"
 )
 
+// The directory within the output directory holding the cached responses of the data sources
+const cacheDirName = "cache"
+
+// cacheTTL returns the number of minutes that the responses of the data source are reused,
+// or zero when the responses are not cached.
+func (s *Script) cacheTTL(dsc *config.DataSourceConfig) int {
+	if dsc == nil {
+		return 0
+	}
+
+	ttl := dsc.TTL
+	// The data sources without a section in the configuration still receive the minimum
+	if min := s.sys.Config().MinimumTTL; min > ttl {
+		ttl = min
+	}
+	return ttl
+}
+
 func (s *Script) getCachedResponse(ctx context.Context, url string, ttl int) (*http.Response, error) {
+	if resp, err := s.getDiskCachedResponse(url, ttl); err == nil {
+		return resp, nil
+	}
+
 	for _, db := range s.sys.GraphDatabases() {
 		tCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
@@ -40,6 +69,10 @@ func (s *Script) setCachedResponse(ctx context.Context, url string, resp *http.R
 		return err
 	}
 
+	if err := s.setDiskCachedResponse(url, b.Bytes()); err != nil {
+		return err
+	}
+
 	for _, db := range s.sys.GraphDatabases() {
 		tCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
@@ -50,3 +83,142 @@ func (s *Script) setCachedResponse(ctx context.Context, url string, resp *http.R
 	}
 	return nil
 }
+
+// cachePath returns the file holding the cached response of the data source for the query,
+// or an empty string when the on-disk cache is not available.
+func (s *Script) cachePath(query string) string {
+	cfg := s.sys.Config()
+	if !cfg.ResponseCache {
+		return ""
+	}
+
+	dir := config.OutputDirectory(cfg.Dir)
+	if dir == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(query))
+	return filepath.Join(dir, cacheDirName, strings.ToLower(s.String()), hex.EncodeToString(sum[:])+".gob")
+}
+
+func (s *Script) getDiskCachedResponse(query string, ttl int) (*http.Response, error) {
+	path := s.cachePath(query)
+	if path == "" {
+		return nil, fmt.Errorf("the on-disk cache is not available")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(info.ModTime()) > time.Duration(ttl)*time.Minute {
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("the cached response for %s has expired", query)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &http.Response{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Script) setDiskCachedResponse(query string, data []byte) error {
+	path := s.cachePath(query)
+	if path == "" {
+		return nil
+	}
+	return writeCacheFile(path, data)
+}
+
+func writeCacheFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so concurrent enumerations never read a partial response
+	tmp, err := os.CreateTemp(filepath.Dir(path), "response-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// The directory within the cache of the data source holding the values kept by the script
+const storeDirName = "store"
+
+// storePath returns the file holding the value kept by the script for the key, or an empty
+// string when the output directory is not available.
+func (s *Script) storePath(key string) string {
+	dir := config.OutputDirectory(s.sys.Config().Dir)
+	if dir == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, cacheDirName, strings.ToLower(s.String()), storeDirName, hex.EncodeToString(sum[:]))
+}
+
+// Wrapper so that scripts can keep values across enumerations, such as the cursors and tokens of a service.
+func (s *Script) cacheSet(L *lua.LState) int {
+	if _, err := extractContext(L.CheckUserData(1)); err != nil {
+		L.Push(lua.LString("No user data parameter or context expired"))
+		return 1
+	}
+
+	path := s.storePath(L.CheckString(2))
+	if path == "" {
+		L.Push(lua.LString("The output directory is not available"))
+		return 1
+	}
+
+	if err := writeCacheFile(path, []byte(L.CheckString(3))); err != nil {
+		L.Push(lua.LString(err.Error()))
+		return 1
+	}
+	L.Push(lua.LNil)
+	return 1
+}
+
+// Wrapper so that scripts can obtain the values kept by previous enumerations. The value is nil when
+// it was never kept, or when it is older than the optional number of minutes.
+func (s *Script) cacheGet(L *lua.LState) int {
+	if _, err := extractContext(L.CheckUserData(1)); err != nil {
+		L.Push(lua.LNil)
+		return 1
+	}
+
+	path := s.storePath(L.CheckString(2))
+	ttl := L.OptInt(3, 0)
+	if path == "" {
+		L.Push(lua.LNil)
+		return 1
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || (ttl > 0 && time.Since(info.ModTime()) > time.Duration(ttl)*time.Minute) {
+		L.Push(lua.LNil)
+		return 1
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		L.Push(lua.LNil)
+		return 1
+	}
+	L.Push(lua.LString(data))
+	return 1
+}
@@ -137,6 +137,15 @@ func (s *Script) dataSourceConfig(L *lua.LState) int {
 		tb.RawSetString("credentials", c)
 	}
 
+	if len(cfg.Options) > 0 {
+		opts := L.NewTable()
+
+		for k, v := range cfg.Options {
+			opts.RawSetString(k, lua.LString(v))
+		}
+		tb.RawSetString("options", opts)
+	}
+
 	L.Push(tb)
 	return 1
 }
@@ -162,6 +171,8 @@ func (s *Script) bruteWordlist(L *lua.LState) int {
 		for _, word := range s.sys.Config().Wordlist {
 			tb.Append(lua.LString(word))
 		}
+		// The script generates a name from each word of the list
+		s.planned(tb.Len())
 	}
 
 	if tb.Len() > 0 {
@@ -204,10 +215,19 @@ func numRateLimitChecks(srv service.Service, num int) {
 
 // Wrapper so scripts can block until past the data source rate limit.
 func (s *Script) checkRateLimit(L *lua.LState) int {
-	numRateLimitChecks(s, s.seconds)
+	s.waitRateLimit()
 	return 0
 }
 
+// waitRateLimit blocks until the rate limit of the configuration, or the one set by the script, allows another request.
+func (s *Script) waitRateLimit() {
+	if s.limiter != nil {
+		s.limiter.Take()
+		return
+	}
+	numRateLimitChecks(s, s.seconds)
+}
+
 // Wrapper so that scripts can request the path to the Amass output directory.
 func (s *Script) outputdir(L *lua.LState) int {
 	var dir string
@@ -101,6 +101,12 @@ func (s *Script) fwdQuery(ctx context.Context, name string, qtype uint16) (*dns.
 }
 
 func (s *Script) dnsQuery(ctx context.Context, msg *dns.Msg, r *resolve.Resolvers, attempts int) (*dns.Msg, error) {
+	if len(msg.Question) > 0 {
+		if err := amassnet.Audit.Allow("dns", resolve.RemoveLastDot(msg.Question[0].Name)); err != nil {
+			return nil, err
+		}
+	}
+
 	for num := 0; num < attempts; num++ {
 		select {
 		case <-ctx.Done():
@@ -271,6 +277,10 @@ func (s *Script) zoneWalk(L *lua.LState) int {
 		L.Push(lua.LString("the name " + name + " was not in scope"))
 		return 1
 	}
+	if s.sys.Config().StrictPassive {
+		L.Push(lua.LString("the zone walk is not performed in the strict passive mode"))
+		return 1
+	}
 
 	r := resolve.NewResolvers()
 	r.SetLogger(s.sys.Config().Log)
@@ -328,6 +338,11 @@ func (s *Script) wrapZoneTransfer(L *lua.LState) int {
 		L.Push(lua.LString("the name " + name + " was not in scope"))
 		return 2
 	}
+	if s.sys.Config().StrictPassive {
+		L.Push(lua.LNil)
+		L.Push(lua.LString("the zone transfer is not performed in the strict passive mode"))
+		return 2
+	}
 
 	tb := L.NewTable()
 	if reqs, err := ZoneTransfer(ctx, name, domain, server); err == nil && len(reqs) > 0 {
@@ -0,0 +1,337 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	lua "github.com/yuin/gopher-lua"
+	luajson "layeh.com/gopher-json"
+)
+
+// The uncompressed bytes extracted from an archive, which protects the scripts from decompression bombs
+const maxUnzipSize = 256 << 20
+
+// jsonPathStep is one of the member names, array indices or wildcards of a JSONPath expression.
+type jsonPathStep struct {
+	key       string
+	index     int
+	isIndex   bool
+	wildcard  bool
+	recursive bool
+}
+
+// Wrapper so that scripts can extract the values selected by a JSONPath expression from a JSON document.
+func (s *Script) jsonPath(L *lua.LState) int {
+	content := L.CheckString(1)
+	path := L.CheckString(2)
+
+	var doc interface{}
+	if err := json.Unmarshal([]byte(content), &doc); err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString("failed to decode the JSON content: " + err.Error()))
+		return 2
+	}
+
+	values, err := extractJSONPath(doc, path)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+	if len(values) == 0 {
+		L.Push(lua.LNil)
+		L.Push(lua.LNil)
+		return 2
+	}
+
+	tb := L.NewTable()
+	for _, v := range values {
+		tb.Append(luajson.DecodeValue(L, v))
+	}
+	L.Push(tb)
+	L.Push(lua.LNil)
+	return 2
+}
+
+// extractJSONPath returns the values of the decoded JSON document selected by the JSONPath expression.
+// The expression supports the member names, array indices, wildcards and the recursive descent,
+// such as $.results[*].hostname or $..subdomains[0], but not the filters and slices.
+func extractJSONPath(doc interface{}, path string) ([]interface{}, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := []interface{}{doc}
+	for _, step := range steps {
+		var next []interface{}
+
+		for _, v := range cur {
+			if step.recursive {
+				next = append(next, descendJSON(v, step)...)
+			} else {
+				next = append(next, applyJSONStep(v, step)...)
+			}
+		}
+		cur = next
+	}
+	return cur, nil
+}
+
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	p := strings.TrimSpace(path)
+	if p == "" {
+		return nil, errors.New("the JSONPath expression is empty")
+	}
+	p = strings.TrimPrefix(p, "$")
+
+	var steps []jsonPathStep
+	for i := 0; i < len(p); {
+		var recursive bool
+
+		switch {
+		case strings.HasPrefix(p[i:], ".."):
+			recursive = true
+			i += 2
+		case p[i] == '.':
+			i++
+		case p[i] == '[':
+		case i == 0:
+			// The expressions can leave out the root and start with a member name
+		default:
+			return nil, fmt.Errorf("the JSONPath expression %q is invalid at position %d", path, i)
+		}
+
+		if i < len(p) && p[i] == '[' {
+			end := strings.IndexByte(p[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("the JSONPath expression %q is missing a closing bracket", path)
+			}
+
+			step, err := parseJSONPathBracket(p[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("the JSONPath expression %q is invalid: %v", path, err)
+			}
+			step.recursive = recursive
+			steps = append(steps, step)
+			i += end + 1
+			continue
+		}
+
+		end := i
+		for end < len(p) && p[end] != '.' && p[end] != '[' {
+			end++
+		}
+		name := p[i:end]
+		if name == "" {
+			return nil, fmt.Errorf("the JSONPath expression %q is missing a member name at position %d", path, i)
+		}
+
+		steps = append(steps, jsonPathStep{
+			key:       name,
+			wildcard:  name == "*",
+			recursive: recursive,
+		})
+		i = end
+	}
+	return steps, nil
+}
+
+func parseJSONPathBracket(inner string) (jsonPathStep, error) {
+	inner = strings.TrimSpace(inner)
+
+	switch {
+	case inner == "*":
+		return jsonPathStep{wildcard: true}, nil
+	case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+		return jsonPathStep{key: inner[1 : len(inner)-1]}, nil
+	}
+
+	idx, err := strconv.Atoi(inner)
+	if err != nil {
+		return jsonPathStep{}, fmt.Errorf("the selector [%s] is not supported", inner)
+	}
+	return jsonPathStep{index: idx, isIndex: true}, nil
+}
+
+func applyJSONStep(v interface{}, step jsonPathStep) []interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if step.wildcard {
+			return jsonChildren(t)
+		}
+		if step.isIndex {
+			return nil
+		}
+		if child, found := t[step.key]; found {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if step.wildcard {
+			return jsonChildren(t)
+		}
+		if !step.isIndex {
+			return nil
+		}
+
+		idx := step.index
+		// The negative indices count back from the end of the array
+		if idx < 0 {
+			idx += len(t)
+		}
+		if idx >= 0 && idx < len(t) {
+			return []interface{}{t[idx]}
+		}
+	}
+	return nil
+}
+
+// descendJSON applies the step to the value and all the values nested within it.
+func descendJSON(v interface{}, step jsonPathStep) []interface{} {
+	results := applyJSONStep(v, step)
+
+	for _, child := range jsonChildren(v) {
+		results = append(results, descendJSON(child, step)...)
+	}
+	return results
+}
+
+// jsonChildren returns the elements of an array, or the members of an object in the order of their names.
+func jsonChildren(v interface{}) []interface{} {
+	var children []interface{}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			children = append(children, t[k])
+		}
+	case []interface{}:
+		children = append(children, t...)
+	}
+	return children
+}
+
+// Wrapper so that scripts can extract the text, or an attribute, of the HTML elements matching a CSS selector.
+func (s *Script) htmlSelect(L *lua.LState) int {
+	content := L.CheckString(1)
+	selector := L.CheckString(2)
+	attr := L.OptString(3, "")
+
+	values, err := selectHTML(content, selector, attr)
+	if err != nil || len(values) == 0 {
+		L.Push(lua.LNil)
+		return 1
+	}
+
+	tb := L.NewTable()
+	for _, v := range values {
+		tb.Append(lua.LString(v))
+	}
+	L.Push(tb)
+	return 1
+}
+
+// selectHTML returns the trimmed text of the elements matching the CSS selector, or the values of the
+// attribute when one is provided. The elements missing the attribute are left out.
+func selectHTML(content, selector, attr string) ([]string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	doc.Find(selector).Each(func(i int, sel *goquery.Selection) {
+		if attr == "" {
+			if text := strings.TrimSpace(sel.Text()); text != "" {
+				values = append(values, text)
+			}
+			return
+		}
+		if v, exists := sel.Attr(attr); exists {
+			values = append(values, strings.TrimSpace(v))
+		}
+	})
+	return values, nil
+}
+
+// Wrapper so that scripts can extract the files of a ZIP archive, such as the bulk downloads of a service.
+func (s *Script) unzip(L *lua.LState) int {
+	files, err := extractZip(L.CheckString(1), maxUnzipSize)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	tb := L.NewTable()
+	for _, f := range files {
+		file := L.NewTable()
+
+		file.RawSetString("name", lua.LString(f.name))
+		file.RawSetString("data", lua.LString(f.data))
+		tb.Append(file)
+	}
+	L.Push(tb)
+	L.Push(lua.LNil)
+	return 2
+}
+
+type zipFile struct {
+	name string
+	data string
+}
+
+// extractZip returns the regular files of the ZIP archive in the order of the archive. An error is
+// returned once the uncompressed files exceed the limit provided.
+func extractZip(content string, limit int64) ([]*zipFile, error) {
+	r, err := zip.NewReader(strings.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the ZIP archive: %v", err)
+	}
+
+	var total int64
+	var files []*zipFile
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s in the ZIP archive: %v", f.Name, err)
+		}
+
+		var buf bytes.Buffer
+		// The sizes in the headers of the archive cannot be trusted
+		n, err := io.Copy(&buf, io.LimitReader(rc, limit-total+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s from the ZIP archive: %v", f.Name, err)
+		}
+
+		total += n
+		if total > limit {
+			return nil, fmt.Errorf("the ZIP archive exceeds %d bytes once extracted", limit)
+		}
+		files = append(files, &zipFile{name: f.Name, data: buf.String()})
+	}
+	return files, nil
+}
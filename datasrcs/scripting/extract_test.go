@@ -0,0 +1,229 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func TestExtractJSONPath(t *testing.T) {
+	content := `{
+		"total": 2,
+		"results": [
+			{"hostname": "www.owasp.org", "ips": ["192.0.2.1"]},
+			{"hostname": "api.owasp.org", "meta": {"hostname": "dev.owasp.org"}}
+		]
+	}`
+
+	var doc interface{}
+	if err := json.Unmarshal([]byte(content), &doc); err != nil {
+		t.Fatalf("Failed to decode the test document: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []interface{}
+		wantErr bool
+	}{
+		{
+			name: "member",
+			path: "$.total",
+			want: []interface{}{float64(2)},
+		},
+		{
+			name: "wildcard",
+			path: "$.results[*].hostname",
+			want: []interface{}{"www.owasp.org", "api.owasp.org"},
+		},
+		{
+			name: "index",
+			path: "$.results[0].ips[0]",
+			want: []interface{}{"192.0.2.1"},
+		},
+		{
+			name: "negative index and quoted member",
+			path: "$['results'][-1].hostname",
+			want: []interface{}{"api.owasp.org"},
+		},
+		{
+			name: "recursive descent",
+			path: "$..hostname",
+			want: []interface{}{"www.owasp.org", "api.owasp.org", "dev.owasp.org"},
+		},
+		{
+			name: "without the root",
+			path: "results[1].meta.hostname",
+			want: []interface{}{"dev.owasp.org"},
+		},
+		{
+			name: "no match",
+			path: "$.missing[0]",
+		},
+		{
+			name:    "filter",
+			path:    "$.results[?(@.hostname)]",
+			wantErr: true,
+		},
+		{
+			name:    "missing bracket",
+			path:    "$.results[0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractJSONPath(doc, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractJSONPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractJSONPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectHTML(t *testing.T) {
+	content := `<html><body>
+		<table id="subs">
+			<tr><td><a href="https://www.owasp.org/">www.owasp.org</a></td></tr>
+			<tr><td><a href="https://api.owasp.org/">api.owasp.org</a></td></tr>
+			<tr><td><a>broken</a></td></tr>
+		</table>
+	</body></html>`
+
+	texts, err := selectHTML(content, "#subs td a", "")
+	if err != nil {
+		t.Fatalf("selectHTML() error = %v", err)
+	}
+	if want := []string{"www.owasp.org", "api.owasp.org", "broken"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("selectHTML() = %v, want %v", texts, want)
+	}
+
+	links, err := selectHTML(content, "#subs td a", "href")
+	if err != nil {
+		t.Fatalf("selectHTML() error = %v", err)
+	}
+	if want := []string{"https://www.owasp.org/", "https://api.owasp.org/"}; !reflect.DeepEqual(links, want) {
+		t.Errorf("selectHTML() = %v, want %v", links, want)
+	}
+}
+
+func TestExtractZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	if _, err := zw.Create("owasp/"); err != nil {
+		t.Fatalf("Failed to create the directory: %v", err)
+	}
+	for _, f := range []struct {
+		name string
+		data string
+	}{
+		{name: "owasp/owasp.org.txt", data: "www.owasp.org\napi.owasp.org\n"},
+		{name: "owasp/owasp.com.txt", data: "mail.owasp.com\n"},
+	} {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", f.name, err)
+		}
+		if _, err := w.Write([]byte(f.data)); err != nil {
+			t.Fatalf("Failed to write %s: %v", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close the archive: %v", err)
+	}
+
+	files, err := extractZip(buf.String(), maxUnzipSize)
+	if err != nil {
+		t.Fatalf("extractZip() error = %v", err)
+	}
+	if len(files) != 2 || files[0].name != "owasp/owasp.org.txt" || files[0].data != "www.owasp.org\napi.owasp.org\n" || files[1].name != "owasp/owasp.com.txt" {
+		t.Errorf("extractZip() returned the unexpected files %+v", files)
+	}
+
+	if _, err := extractZip(buf.String(), 20); err == nil {
+		t.Error("extractZip() did not enforce the limit of the extracted bytes")
+	}
+	if _, err := extractZip("not an archive", maxUnzipSize); err == nil {
+		t.Error("extractZip() did not fail with content that is not an archive")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2023, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{value: "", ok: false},
+		{value: "30", want: 30 * time.Second, ok: true},
+		{value: "86400", want: maxRetryAfter, ok: true},
+		{value: "Wed, 15 Mar 2023 10:31:00 GMT", want: time.Minute, ok: true},
+		{value: "Wed, 15 Mar 2023 10:00:00 GMT", want: 0, ok: true},
+		{value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := retryAfter(tt.value, now); got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestScriptExtractionFunctions(t *testing.T) {
+	sys := newMockSystem(config.NewConfig())
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(fmt.Sprintf(`
+		name="extraction"
+		type="testing"
+
+		function vertical(ctx, domain)
+			local names, err = json_path(%q, "$.subdomains[*]")
+			if (err ~= nil or names == nil) then
+				return
+			end
+
+			local links = html_select(%q, "a", "href")
+			if (links == nil) then
+				return
+			end
+			new_name(ctx, names[#names] .. "." .. links[1])
+		end
+	`, `{"subdomains": ["www", "api"]}`, `<a href="owasp.org">OWASP</a>`), sys)
+	if s == nil {
+		t.Fatal("Failed to initialize the script")
+	}
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("Failed to start the script: %v", err)
+	}
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	s.Input() <- &requests.DNSRequest{Domain: domain}
+
+	select {
+	case out := <-s.Output():
+		if req, ok := out.(*requests.DNSRequest); !ok || req.Name != "api.owasp.org" {
+			t.Errorf("The script provided %v", out)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The script did not extract the values")
+	}
+}
@@ -6,14 +6,26 @@ package scripting
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/owasp-amass/amass/v3/config"
 	"github.com/owasp-amass/amass/v3/net/dns"
 	"github.com/owasp-amass/amass/v3/net/http"
+	"github.com/owasp-amass/amass/v3/net/tor"
 	lua "github.com/yuin/gopher-lua"
 )
 
+// The number of pages requested by the paginate function unless the script selects another limit
+const defaultMaxPages = 100
+
+// The longest time the requests of a script wait for the Retry-After requested by a service
+const maxRetryAfter = 5 * time.Minute
+
 // Wrapper that allows scripts to make HTTP client requests.
 func (s *Script) request(L *lua.LState) int {
 	ctx, err := extractContext(L.CheckUserData(1))
@@ -30,13 +42,43 @@ func (s *Script) request(L *lua.LState) int {
 		return 2
 	}
 
-	url, found := getStringField(L, opt, "url")
+	ro, found := requestOptions(L, opt)
 	if !found {
 		L.Push(lua.LNil)
 		L.Push(lua.LString("No URL found in the parameters"))
 		return 2
 	}
 
+	resp, err := s.reqWithRetries(ctx, ro)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+	} else {
+		L.Push(responseToTable(L, resp))
+		L.Push(lua.LNil)
+	}
+	return 2
+}
+
+// scriptRequest holds the options of the HTTP requests made by the scripts.
+type scriptRequest struct {
+	url     string
+	body    string
+	header  http.Header
+	auth    *http.BasicAuth
+	retries int
+	// The responses that change while the service works, such as the results of searches polled
+	// by the script, are not read from or written to the response cache
+	nocache bool
+}
+
+// requestOptions returns the options in the table provided by the script, and false when the URL is missing.
+func requestOptions(L *lua.LState, opt *lua.LTable) (*scriptRequest, bool) {
+	url, found := getStringField(L, opt, "url")
+	if !found {
+		return nil, false
+	}
+
 	var hdr http.Header
 	if lv := L.GetField(opt, "header"); lv != nil {
 		if tbl, ok := lv.(*lua.LTable); ok {
@@ -54,21 +96,29 @@ func (s *Script) request(L *lua.LState) int {
 		}
 	}
 
-	id, _ := getStringField(L, opt, "id")
-	pass, _ := getStringField(L, opt, "pass")
-	resp, err := s.req(ctx, url, body, hdr, &http.BasicAuth{
-		Username: id,
-		Password: pass,
-	})
+	var retries int
+	if n, ok := getNumberField(L, opt, "retries"); ok && n > 0 {
+		retries = int(n)
+	}
 
-	if err != nil {
-		L.Push(lua.LNil)
-		L.Push(lua.LString(err.Error()))
-	} else {
-		L.Push(responseToTable(L, resp))
-		L.Push(lua.LNil)
+	var nocache bool
+	if cache, ok := getBoolField(L, opt, "cache"); ok && !cache {
+		nocache = true
 	}
-	return 2
+
+	id, _ := getStringField(L, opt, "id")
+	pass, _ := getStringField(L, opt, "pass")
+	return &scriptRequest{
+		url:    url,
+		body:   body,
+		header: hdr,
+		auth: &http.BasicAuth{
+			Username: id,
+			Password: pass,
+		},
+		retries: retries,
+		nocache: nocache,
+	}, true
 }
 
 func responseToTable(L *lua.LState, resp *http.Response) *lua.LTable {
@@ -88,6 +138,7 @@ func responseToTable(L *lua.LState, resp *http.Response) *lua.LTable {
 
 	r.RawSetString("body", lua.LString(resp.Body))
 	r.RawSetString("length", lua.LNumber(resp.Length))
+	r.RawSetString("url", lua.LString(resp.URL))
 
 	if resp.TLS != nil {
 		tls := L.NewTable()
@@ -139,37 +190,14 @@ func (s *Script) scrape(L *lua.LState) int {
 		return 1
 	}
 
-	url, found := getStringField(L, opt, "url")
+	ro, found := requestOptions(L, opt)
 	if !found {
 		L.Push(lua.LFalse)
 		return 1
 	}
 
-	var hdr http.Header
-	if lv := L.GetField(opt, "header"); lv != nil {
-		if tbl, ok := lv.(*lua.LTable); ok {
-			hdr = make(http.Header)
-			tbl.ForEach(func(k, v lua.LValue) {
-				hdr[k.String()] = v.String()
-			})
-		}
-	}
-
-	var body string
-	if method, ok := getStringField(L, opt, "method"); ok && strings.ToLower(method) == "post" {
-		if d, ok := getStringField(L, opt, "body"); ok {
-			body = d
-		}
-	}
-
-	id, _ := getStringField(L, opt, "id")
-	pass, _ := getStringField(L, opt, "pass")
-
 	sucess := lua.LFalse
-	if resp, err := s.req(ctx, url, body, hdr, &http.BasicAuth{
-		Username: id,
-		Password: pass,
-	}); err == nil && resp.StatusCode >= 200 && resp.StatusCode < 400 {
+	if resp, err := s.reqWithRetries(ctx, ro); err == nil && resp.StatusCode >= 200 && resp.StatusCode < 400 {
 		if num := s.internalSendNames(ctx, resp.Body); num > 0 {
 			sucess = lua.LTrue
 		}
@@ -181,12 +209,17 @@ func (s *Script) scrape(L *lua.LState) int {
 	return 1
 }
 
-func (s *Script) req(ctx context.Context, url, data string, hdr http.Header, auth *http.BasicAuth) (*http.Response, error) {
+func (s *Script) req(ctx context.Context, ro *scriptRequest) (*http.Response, error) {
+	url, data, hdr, auth := ro.url, ro.body, ro.header, ro.auth
 	cfg := s.sys.Config()
 	// Check for cached responses first
 	dsc := cfg.GetDataSourceConfig(s.String())
-	if dsc != nil && dsc.TTL > 0 {
-		if r, err := s.getCachedResponse(ctx, url+data, dsc.TTL); err == nil {
+	ttl := s.cacheTTL(dsc)
+	if ro.nocache {
+		ttl = 0
+	}
+	if ttl > 0 {
+		if r, err := s.getCachedResponse(ctx, url+data, ttl); err == nil {
 			return r, nil
 		}
 	}
@@ -196,24 +229,275 @@ func (s *Script) req(ctx context.Context, url, data string, hdr http.Header, aut
 		method = "POST"
 	}
 
-	numRateLimitChecks(s, s.seconds)
+	s.waitRateLimit()
+	if err := s.waitRetryAfter(ctx); err != nil {
+		return nil, err
+	}
+
 	resp, err := http.RequestWebPage(ctx, &http.Request{
 		URL:    url,
 		Method: method,
 		Header: hdr,
 		Body:   data,
 		Auth:   auth,
+		Proxy:  cfg.SourceProxy(s.String()),
+		TLS:    cfg.SourceTLSConfig(),
 	})
+	s.countRequest(err != nil || resp.StatusCode >= 400)
+	if err == nil && retryResponse(resp) {
+		if d, ok := retryAfter(resp.Header["Retry-After"], time.Now()); ok {
+			s.delayRequests(d)
+		}
+	}
 	if err != nil {
 		if cfg.Verbose {
 			cfg.Log.Printf("%s: %s: %v", s.String(), url, err)
 		}
-	} else if dsc != nil && dsc.TTL > 0 && resp.StatusCode >= 200 && resp.StatusCode < 400 {
+	} else if resp.StatusCode == 429 {
+		if dsc != nil {
+			s.rateLimitedCredentials(dsc, url, data, hdr, auth)
+		}
+		if cfg.Tor {
+			s.newTorIdentity(ctx)
+		}
+	} else if ttl > 0 && resp.StatusCode >= 200 && resp.StatusCode < 400 {
 		_ = s.setCachedResponse(ctx, url+data, resp)
 	}
 	return resp, err
 }
 
+// reqWithRetries sends the request again, up to the number of retries, while the service responds
+// that it is rate limited or unavailable. The requests without a Retry-After header in the response
+// back off exponentially before the next attempt.
+func (s *Script) reqWithRetries(ctx context.Context, ro *scriptRequest) (*http.Response, error) {
+	for i := 0; ; i++ {
+		resp, err := s.req(ctx, ro)
+		if err != nil || i >= ro.retries || !retryResponse(resp) {
+			return resp, err
+		}
+
+		if _, found := resp.Header["Retry-After"]; !found {
+			s.delayRequests(time.Duration(1<<uint(i)) * time.Second)
+		}
+	}
+}
+
+// retryResponse returns true when the service responds that it is rate limited or unavailable.
+// Some services, such as GitHub for the secondary rate limits, respond with the status 403 and
+// provide the Retry-After header.
+func retryResponse(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case 429, 503:
+		return true
+	case 403:
+		_, found := resp.Header["Retry-After"]
+		return found
+	}
+	return false
+}
+
+// retryAfter returns the duration requested by the Retry-After header, which provides a number of
+// seconds or an HTTP date. The duration is limited, so a long wait does not stall the enumeration.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	var d time.Duration
+	if secs, err := strconv.Atoi(value); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := time.Parse(time.RFC1123, value); err == nil {
+		d = t.Sub(now)
+	} else {
+		return 0, false
+	}
+
+	if d < 0 {
+		d = 0
+	} else if d > maxRetryAfter {
+		d = maxRetryAfter
+	}
+	return d, true
+}
+
+// delayRequests holds the following requests of the script until the duration has passed.
+func (s *Script) delayRequests(d time.Duration) {
+	s.retryLock.Lock()
+	defer s.retryLock.Unlock()
+
+	if until := time.Now().Add(d); until.After(s.retryAt) {
+		s.retryAt = until
+	}
+}
+
+// waitRetryAfter blocks until the time requested by the service in the last Retry-After header has passed.
+func (s *Script) waitRetryAfter(ctx context.Context) error {
+	s.retryLock.Lock()
+	wait := time.Until(s.retryAt)
+	s.retryLock.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return errors.New("the context expired while waiting to retry the request")
+	case <-t.C:
+	}
+	return nil
+}
+
+// Wrapper so that scripts can request the pages of a service. The function provided is called with
+// each response and the page number, and returns the URL of the next page, a table with the url and
+// body of the next request, or false to stop. When the function returns nothing, the next page is
+// selected by the Link header of the response.
+func (s *Script) paginate(L *lua.LState) int {
+	ctx, err := extractContext(L.CheckUserData(1))
+	if err != nil {
+		L.Push(lua.LNumber(0))
+		L.Push(lua.LString("No user data parameter or context expired"))
+		return 2
+	}
+
+	opt := L.CheckTable(2)
+	fn := L.CheckFunction(3)
+	ro, found := requestOptions(L, opt)
+	if !found {
+		L.Push(lua.LNumber(0))
+		L.Push(lua.LString("No URL found in the parameters"))
+		return 2
+	}
+
+	max := defaultMaxPages
+	if n, ok := getNumberField(L, opt, "max_pages"); ok && n > 0 {
+		max = int(n)
+	}
+
+	var pages int
+	for pages < max {
+		resp, err := s.reqWithRetries(ctx, ro)
+		if err != nil {
+			L.Push(lua.LNumber(pages))
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+			L.Push(lua.LNumber(pages))
+			L.Push(lua.LString(fmt.Sprintf("the request for page %d returned with status: %s", pages+1, resp.Status)))
+			return 2
+		}
+
+		pages++
+		if err := L.CallByParam(lua.P{
+			Fn:      fn,
+			NRet:    1,
+			Protect: true,
+		}, responseToTable(L, resp), lua.LNumber(pages)); err != nil {
+			L.Push(lua.LNumber(pages))
+			L.Push(lua.LString(err.Error()))
+			return 2
+		}
+
+		ret := L.Get(-1)
+		L.Pop(1)
+
+		switch v := ret.(type) {
+		case lua.LString:
+			ro.url = string(v)
+		case *lua.LTable:
+			if u, ok := getStringField(L, v, "url"); ok {
+				ro.url = u
+			}
+			if b, ok := getStringField(L, v, "body"); ok {
+				ro.body = b
+			}
+		case lua.LBool:
+			if !bool(v) {
+				ro.url = ""
+			} else {
+				ro.url = nextLink(ro.url, resp.Header["Link"])
+			}
+		default:
+			ro.url = nextLink(ro.url, resp.Header["Link"])
+		}
+		if ro.url == "" {
+			break
+		}
+	}
+
+	L.Push(lua.LNumber(pages))
+	L.Push(lua.LNil)
+	return 2
+}
+
+// nextLink returns the URL of the next page in the Link header, resolved against the URL of the
+// current page, or an empty string when the header does not provide the next page.
+func nextLink(current, header string) string {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			param = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(param), " ", ""))
+			if param != `rel="next"` && param != "rel=next" {
+				continue
+			}
+
+			base, err := url.Parse(current)
+			if err != nil {
+				return ""
+			}
+			next, err := base.Parse(strings.Trim(target, "<>"))
+			if err != nil {
+				return ""
+			}
+			return next.String()
+		}
+	}
+	return ""
+}
+
+// rateLimitedCredentials records the rate limit against the credentials found in the request, so the key
+// rotation of the data source can move on to the next set of credentials.
+func (s *Script) rateLimitedCredentials(dsc *config.DataSourceConfig, url, data string, hdr http.Header, auth *http.BasicAuth) {
+	text := []string{url, data}
+	for _, v := range hdr {
+		text = append(text, v)
+	}
+	if auth != nil {
+		text = append(text, auth.Username, auth.Password)
+	}
+
+	if creds := dsc.MatchCredentials(strings.Join(text, "\n")); creds != nil {
+		dsc.RateLimited(creds)
+		if cfg := s.sys.Config(); cfg.Verbose {
+			cfg.Log.Printf("%s: the %s credentials were rate limited", s.String(), creds.Name)
+		}
+	}
+}
+
+// newTorIdentity asks the Tor client for clean circuits, so the following requests leave through
+// different exit relays than the one that was rate limited.
+func (s *Script) newTorIdentity(ctx context.Context) {
+	cfg := s.sys.Config()
+
+	sent, err := tor.SharedController(cfg.TorControlAddr, cfg.TorControlPassword).NewIdentity(ctx)
+	if err != nil {
+		cfg.Log.Printf("%s: failed to request new Tor circuits: %v", s.String(), err)
+	} else if sent && cfg.Verbose {
+		cfg.Log.Printf("%s: requested new Tor circuits after being rate limited", s.String())
+	}
+}
+
 // Wrapper so that scripts can crawl for subdomain names in scope.
 func (s *Script) crawl(L *lua.LState) int {
 	cfg := s.sys.Config()
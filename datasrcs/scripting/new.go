@@ -27,15 +27,14 @@ func (s *Script) genNewName(ctx context.Context, name string) {
 
 func (s *Script) newNameWithSrc(ctx context.Context, name, tag, src string) {
 	if domain := s.sys.Config().WhichDomain(name); domain != "" {
+		req := requests.NewDNSRequest(name, domain, tag, src)
+
 		select {
 		case <-ctx.Done():
+			requests.ReleaseDNSRequest(req)
 		case <-s.Done():
-		case s.Output() <- &requests.DNSRequest{
-			Name:   name,
-			Domain: domain,
-			Tag:    tag,
-			Source: src,
-		}:
+			requests.ReleaseDNSRequest(req)
+		case s.Output() <- req:
 		}
 	}
 }
@@ -142,6 +141,117 @@ func (s *Script) internalSendDNSRecords(ctx context.Context, name string, record
 	}
 }
 
+func (s *Script) sendDNSHistory(L *lua.LState) int {
+	ctx, err := extractContext(L.CheckUserData(1))
+	if err != nil || contextExpired(ctx) {
+		return 0
+	}
+
+	name := L.CheckString(2)
+	if name == "" {
+		return 0
+	}
+
+	array := L.CheckTable(3)
+	if array == nil {
+		return 0
+	}
+
+	var records []requests.HistoricalRecord
+	array.ForEach(func(k, v lua.LValue) {
+		if tbl, ok := v.(*lua.LTable); ok {
+			var qtype int
+			if lv := L.GetField(tbl, "rrtype"); lv != nil {
+				if n, ok := lv.(lua.LNumber); ok {
+					qtype = int(n)
+				}
+			}
+
+			data, _ := getStringField(L, tbl, "rrdata")
+			first, _ := getStringField(L, tbl, "first_seen")
+			last, _ := getStringField(L, tbl, "last_seen")
+			if qtype != 0 && data != "" {
+				records = append(records, requests.HistoricalRecord{
+					Type:      qtype,
+					Data:      data,
+					FirstSeen: first,
+					LastSeen:  last,
+				})
+			}
+		}
+	})
+	if len(records) == 0 {
+		return 0
+	}
+
+	name = strings.ToLower(resolve.RemoveLastDot(name))
+	if domain := s.sys.Config().WhichDomain(name); domain != "" {
+		select {
+		case <-ctx.Done():
+		case <-s.Done():
+		case s.Output() <- &requests.DNSHistoryRequest{
+			Name:    name,
+			Domain:  domain,
+			Records: records,
+			Tag:     s.Description(),
+			Source:  s.String(),
+		}:
+		}
+	}
+	return 0
+}
+
+// Wrapper so that scripts can send the open ports and the CPE names of the software found on an address.
+func (s *Script) sendServices(L *lua.LState) int {
+	ctx, err := extractContext(L.CheckUserData(1))
+	if err != nil || contextExpired(ctx) {
+		return 0
+	}
+
+	ip := net.ParseIP(L.CheckString(2))
+	if ip == nil {
+		return 0
+	}
+	addr := ip.String()
+	if reserved, _ := amassnet.IsReservedAddress(addr); reserved {
+		return 0
+	}
+
+	var ports []int
+	if tbl := L.OptTable(3, nil); tbl != nil {
+		tbl.ForEach(func(k, v lua.LValue) {
+			if n, ok := v.(lua.LNumber); ok && n > 0 && n <= 65535 {
+				ports = append(ports, int(n))
+			}
+		})
+	}
+
+	var cpes []string
+	if tbl := L.OptTable(4, nil); tbl != nil {
+		tbl.ForEach(func(k, v lua.LValue) {
+			if str, ok := v.(lua.LString); ok && strings.TrimSpace(string(str)) != "" {
+				cpes = append(cpes, strings.TrimSpace(string(str)))
+			}
+		})
+	}
+	if len(ports) == 0 && len(cpes) == 0 {
+		return 0
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-s.Done():
+	case s.Output() <- &requests.ServicesRequest{
+		Address: addr,
+		Ports:   ports,
+		CPEs:    cpes,
+		Tag:     s.Description(),
+		Source:  s.String(),
+	}:
+	}
+	return 0
+}
+
 func (s *Script) newPTR(ctx context.Context, record *resolve.ExtractedAnswer) {
 	answer := strings.ToLower(resolve.RemoveLastDot(record.Data))
 	if amassdns.RemoveAsteriskLabel(answer) != answer {
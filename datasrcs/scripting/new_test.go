@@ -5,6 +5,7 @@
 package scripting
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -106,6 +107,91 @@ func TestSendDNSRecords(t *testing.T) {
 	}
 }
 
+func TestSendDNSHistory(t *testing.T) {
+	script, sys := setupMockScriptEnv(`
+		name="dns_history"
+		type="testing"
+
+		function vertical(ctx, domain)
+			send_dns_history(ctx, "www." .. domain, { {
+				['rrtype']=1,
+				['rrdata']="192.0.2.1",
+				['first_seen']="2019-04-01",
+				['last_seen']="2021-11-30",
+			}, {
+				['rrtype']=1,
+			}})
+		end
+	`)
+	if script == nil || sys == nil {
+		t.Fatal("failed to initialize the scripting environment")
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	sys.Config().AddDomain("owasp.org")
+	script.Input() <- &requests.DNSRequest{Domain: "owasp.org"}
+
+	timer := time.NewTimer(15 * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		t.Error("test timed out")
+	case req := <-script.Output():
+		h, ok := req.(*requests.DNSHistoryRequest)
+		if !ok || h.Name != "www.owasp.org" || h.Domain != "owasp.org" || h.Source != "dns_history" {
+			t.Fatalf("send DNS history provided %v", req)
+		}
+		expected := requests.HistoricalRecord{
+			Type:      1,
+			Data:      "192.0.2.1",
+			FirstSeen: "2019-04-01",
+			LastSeen:  "2021-11-30",
+		}
+		if len(h.Records) != 1 || h.Records[0] != expected {
+			t.Errorf("send DNS history provided the records %v, expected %v", h.Records, expected)
+		}
+	}
+}
+
+func TestSendServices(t *testing.T) {
+	script, sys := setupMockScriptEnv(`
+		name="services"
+		type="testing"
+
+		function vertical(ctx, domain)
+			send_services(ctx, "10.0.0.1", {80}, {})
+			send_services(ctx, "8.8.8.8", {53, 443, 0, 70000}, {"cpe:/a:isc:bind", " "})
+		end
+	`)
+	if script == nil || sys == nil {
+		t.Fatal("failed to initialize the scripting environment")
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	sys.Config().AddDomain("owasp.org")
+	script.Input() <- &requests.DNSRequest{Domain: "owasp.org"}
+
+	timer := time.NewTimer(15 * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		t.Error("test timed out")
+	case req := <-script.Output():
+		svc, ok := req.(*requests.ServicesRequest)
+		if !ok || svc.Address != "8.8.8.8" || svc.Source != "services" {
+			t.Fatalf("send services provided %v", req)
+		}
+		if !reflect.DeepEqual(svc.Ports, []int{53, 443}) {
+			t.Errorf("send services provided the ports %v, expected [53 443]", svc.Ports)
+		}
+		if !reflect.DeepEqual(svc.CPEs, []string{"cpe:/a:isc:bind"}) {
+			t.Errorf("send services provided the CPEs %v, expected [cpe:/a:isc:bind]", svc.CPEs)
+		}
+	}
+}
+
 func TestNewAddrs(t *testing.T) {
 	expected := stringset.New("72.237.4.113", "72.237.4.114", "72.237.4.35", "72.237.4.38", "72.237.4.79",
 		"72.237.4.90", "72.237.4.103", "72.237.4.243", "4.26.24.234", "44.193.34.238", "52.206.190.41", "18.211.32.87")
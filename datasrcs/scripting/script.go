@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"regexp"
 	"sync"
+	"time"
 
 	"github.com/caffix/service"
 	luaurl "github.com/cjoudrey/gluaurl"
@@ -18,6 +19,7 @@ import (
 	"github.com/owasp-amass/amass/v3/requests"
 	"github.com/owasp-amass/amass/v3/systems"
 	lua "github.com/yuin/gopher-lua"
+	"go.uber.org/ratelimit"
 	luajson "layeh.com/gopher-json"
 )
 
@@ -41,22 +43,44 @@ type Script struct {
 	startRet   chan error
 	stop       chan struct{}
 	SourceType string
+	Path       string
 	sys        systems.System
 	luaState   *lua.LState
 	cbs        *callbacks
 	cbsLock    sync.Mutex
 	subre      *regexp.Regexp
 	seconds    int
+	limiter    ratelimit.Limiter
+	workers    chan struct{}
+	planLock   sync.Mutex
+	planner    func(num int)
+	statsLock  sync.Mutex
+	sent       int
+	failed     int
 	ctx        context.Context
 	cancel     context.CancelFunc
+	// The time the service asked for in the last Retry-After header of its responses
+	retryLock sync.Mutex
+	retryAt   time.Time
 }
 
 // NewScript returns the object initialized, but not yet started.
 func NewScript(script string, sys systems.System) *Script {
-	re, err := regexp.Compile(dns.AnySubdomainRegexString())
+	s, err := LoadScript(script, sys)
 	if err != nil {
+		sys.Config().Log.Printf("Script: Failed to load the %s script: %v", script, err)
 		return nil
 	}
+	return s
+}
+
+// LoadScript returns the object initialized, but not yet started, or the error that prevented
+// the script from being loaded.
+func LoadScript(script string, sys systems.System) (*Script, error) {
+	re, err := regexp.Compile(dns.AnySubdomainRegexString())
+	if err != nil {
+		return nil, err
+	}
 
 	s := &Script{
 		start:    make(chan struct{}, 1),
@@ -70,26 +94,29 @@ func NewScript(script string, sys systems.System) *Script {
 
 	// Load the script
 	if err := L.DoString(script); err != nil {
-		sys.Config().Log.Printf("Script: Failed to load the %s script: %v", script, err)
-		return nil
+		s.cancel()
+		L.Close()
+		return nil, err
 	}
 	// Pull the script name from the script
 	name, err := s.scriptName()
 	if err != nil {
-		sys.Config().Log.Printf("Script: Failed to obtain the %s script name: %v", script, err)
-		return nil
+		s.cancel()
+		L.Close()
+		return nil, fmt.Errorf("failed to obtain the script name: %v", err)
 	}
 	// Pull the script type from the script
 	s.SourceType, err = s.scriptType()
 	if err != nil {
-		sys.Config().Log.Printf("Script: Failed to obtain the %s script type: %v", script, err)
-		return nil
+		s.cancel()
+		L.Close()
+		return nil, fmt.Errorf("failed to obtain the script type: %v", err)
 	}
 
 	s.BaseService = *service.NewBaseService(s, name)
 	s.assignCallbacks()
 	go s.requests()
-	return s
+	return s, nil
 }
 
 // Setup the Lua state with desired constraints and access to necessary functionality.
@@ -117,6 +144,8 @@ func (s *Script) newLuaState(cfg *config.Config) *lua.LState {
 	L.SetGlobal("new_name", L.NewFunction(s.newName))
 	L.SetGlobal("send_names", L.NewFunction(s.sendNames))
 	L.SetGlobal("send_dns_records", L.NewFunction(s.sendDNSRecords))
+	L.SetGlobal("send_dns_history", L.NewFunction(s.sendDNSHistory))
+	L.SetGlobal("send_services", L.NewFunction(s.sendServices))
 	L.SetGlobal("new_addr", L.NewFunction(s.newAddr))
 	L.SetGlobal("new_asn", L.NewFunction(s.newASN))
 	L.SetGlobal("associated", L.NewFunction(s.associated))
@@ -131,6 +160,12 @@ func (s *Script) newLuaState(cfg *config.Config) *lua.LState {
 	L.SetGlobal("output_dir", L.NewFunction(s.outputdir))
 	L.SetGlobal("set_rate_limit", L.NewFunction(s.setRateLimit))
 	L.SetGlobal("check_rate_limit", L.NewFunction(s.checkRateLimit))
+	L.SetGlobal("paginate", L.NewFunction(s.paginate))
+	L.SetGlobal("cache_get", L.NewFunction(s.cacheGet))
+	L.SetGlobal("cache_set", L.NewFunction(s.cacheSet))
+	L.SetGlobal("json_path", L.NewFunction(s.jsonPath))
+	L.SetGlobal("html_select", L.NewFunction(s.htmlSelect))
+	L.SetGlobal("unzip", L.NewFunction(s.unzip))
 	L.SetGlobal("subdomain_regex", lua.LString(dns.AnySubdomainRegexString()))
 	return L
 }
@@ -244,11 +279,86 @@ func (s *Script) requests() {
 		case <-s.stop:
 			s.stopScript()
 		case in := <-s.Input():
-			s.dispatch(in)
+			if s.acquireWorker() {
+				s.dispatch(in)
+				s.releaseWorker()
+			}
 		}
 	}
 }
 
+// SetWorkers makes the script share the worker slots with other data sources, so the number of
+// requests processed at the same time is limited to the capacity of the channel. It must be
+// called before the script receives requests.
+func (s *Script) SetWorkers(workers chan struct{}) {
+	s.workers = workers
+}
+
+// Workers returns the worker slots shared by the script with the other data sources, or nil
+// when the number of requests processed by the script is not limited.
+func (s *Script) Workers() chan struct{} {
+	return s.workers
+}
+
+// SetPlanner registers the function told the number of names each brute forcing pass of the
+// script will generate, so the enumeration can account for the work before the names arrive.
+func (s *Script) SetPlanner(fn func(num int)) {
+	s.planLock.Lock()
+	defer s.planLock.Unlock()
+
+	s.planner = fn
+}
+
+func (s *Script) planned(num int) {
+	s.planLock.Lock()
+	fn := s.planner
+	s.planLock.Unlock()
+
+	if fn != nil && num > 0 {
+		fn(num)
+	}
+}
+
+// RequestStats returns the number of requests the script has sent to its service, and how many
+// of them failed or were refused. The responses provided by the cache are not counted.
+func (s *Script) RequestStats() (int, int) {
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+
+	return s.sent, s.failed
+}
+
+func (s *Script) countRequest(failed bool) {
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+
+	s.sent++
+	if failed {
+		s.failed++
+	}
+}
+
+func (s *Script) acquireWorker() bool {
+	if s.workers == nil {
+		return true
+	}
+
+	select {
+	case <-s.Done():
+		return false
+	case <-s.ctx.Done():
+		return false
+	case s.workers <- struct{}{}:
+	}
+	return true
+}
+
+func (s *Script) releaseWorker() {
+	if s.workers != nil {
+		<-s.workers
+	}
+}
+
 func (s *Script) startScript() {
 	if L := s.luaState; s.cbs.Start.Type() != lua.LTNil {
 		err := L.CallByParam(lua.P{
@@ -263,7 +373,11 @@ func (s *Script) startScript() {
 		}
 	}
 
-	if s.seconds > 0 {
+	if dsc := s.sys.Config().GetDataSourceConfig(s.String()); dsc != nil && dsc.RateLimit > 0 {
+		// The rate limit of the configuration replaces the one set by the script
+		s.limiter = ratelimit.New(dsc.RateLimit, ratelimit.Per(time.Minute), ratelimit.WithSlack(dsc.Burst))
+		s.seconds = 0
+	} else if s.seconds > 0 {
 		s.SetRateLimit(1)
 	}
 
@@ -5,6 +5,14 @@
 package scripting
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
 	"github.com/owasp-amass/amass/v3/config"
 	"github.com/owasp-amass/amass/v3/requests"
 	"github.com/owasp-amass/amass/v3/systems"
@@ -39,3 +47,587 @@ func newMockSystem(cfg *config.Config) systems.System {
 	_ = ss.Trusted.AddResolvers(20, "8.8.8.8")
 	return ss
 }
+
+func TestScriptWorkers(t *testing.T) {
+	sys := newMockSystem(config.NewConfig())
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(`
+		name="workers"
+		type="testing"
+
+		function vertical(ctx, domain)
+			new_name(ctx, "www." .. domain)
+		end
+	`, sys)
+	if s == nil {
+		t.Fatal("Failed to initialize the script")
+	}
+	// The only worker slot is taken by another data source
+	workers := make(chan struct{}, 1)
+	workers <- struct{}{}
+	s.SetWorkers(workers)
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("Failed to start the script: %v", err)
+	}
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	s.Input() <- &requests.DNSRequest{Domain: domain}
+
+	select {
+	case <-s.Output():
+		t.Fatal("The script processed the request without a worker slot")
+	case <-time.After(250 * time.Millisecond):
+	}
+
+	<-workers
+	select {
+	case out := <-s.Output():
+		if req, ok := out.(*requests.DNSRequest); !ok || req.Name != "www.owasp.org" {
+			t.Errorf("The script provided %v", out)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The script did not process the request after the worker slot was released")
+	}
+}
+
+func TestScriptPlanner(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Wordlist = []string{"www", "mail", "dev"}
+	sys := newMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(`
+		name="planner"
+		type="testing"
+
+		function vertical(ctx, domain)
+			for _, word in pairs(brute_wordlist(ctx)) do
+				new_name(ctx, word .. "." .. domain)
+			end
+		end
+	`, sys)
+	if s == nil {
+		t.Fatal("Failed to initialize the script")
+	}
+
+	planned := make(chan int, 1)
+	s.SetPlanner(func(num int) { planned <- num })
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("Failed to start the script: %v", err)
+	}
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	s.Input() <- &requests.DNSRequest{Domain: domain}
+
+	select {
+	case num := <-planned:
+		if num != len(cfg.Wordlist) {
+			t.Errorf("The script planned %d names, expected %d", num, len(cfg.Wordlist))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The script did not report the planned names")
+	}
+	for range cfg.Wordlist {
+		select {
+		case <-s.Output():
+		case <-time.After(5 * time.Second):
+			t.Fatal("The script did not provide the planned names")
+		}
+	}
+}
+
+func TestScriptRequestStats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := config.NewConfig()
+	cfg.Dir = t.TempDir()
+	sys := newMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(fmt.Sprintf(`
+		name="stats"
+		type="testing"
+
+		function vertical(ctx, domain)
+			request(ctx, {url="%s/ok"})
+			request(ctx, {url="%s/fail"})
+			new_name(ctx, "www." .. domain)
+		end
+	`, ts.URL, ts.URL), sys)
+	if s == nil {
+		t.Fatal("Failed to initialize the script")
+	}
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("Failed to start the script: %v", err)
+	}
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	s.Input() <- &requests.DNSRequest{Domain: domain}
+
+	select {
+	case <-s.Output():
+	case <-time.After(5 * time.Second):
+		t.Fatal("The script did not process the request")
+	}
+	if reqs, errs := s.RequestStats(); reqs != 2 || errs != 1 {
+		t.Errorf("The script counted %d requests and %d errors, expected 2 and 1", reqs, errs)
+	}
+}
+
+func TestScriptConfigRateLimit(t *testing.T) {
+	cfg := config.NewConfig()
+	dsc := cfg.GetDataSourceConfig("ratelimit")
+	dsc.RateLimit = 600
+
+	sys := newMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(`
+		name="ratelimit"
+		type="testing"
+
+		function start()
+			set_rate_limit(10)
+		end
+
+		function vertical(ctx, domain)
+			for i=1,3 do
+				check_rate_limit()
+			end
+			new_name(ctx, "www." .. domain)
+		end
+	`, sys)
+	if s == nil {
+		t.Fatal("Failed to initialize the script")
+	}
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("Failed to start the script: %v", err)
+	}
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	start := time.Now()
+	s.Input() <- &requests.DNSRequest{Domain: domain}
+
+	// The configured 600 requests per minute replace the ten seconds between requests set by the script
+	select {
+	case <-s.Output():
+	case <-time.After(5 * time.Second):
+		t.Fatal("The script did not use the rate limit of the configuration")
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("The script sent three requests in %s, faster than the configured rate limit", elapsed)
+	}
+}
+
+func TestScriptKeyRotation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") == "limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := config.NewConfig()
+	cfg.Dir = t.TempDir()
+	dsc := cfg.GetDataSourceConfig("rotation")
+	dsc.KeyRotation = config.RateLimitRotation
+	_ = dsc.AddCredentials(&config.Credentials{Name: "first", Key: "limited"})
+	_ = dsc.AddCredentials(&config.Credentials{Name: "second", Key: "available"})
+
+	sys := newMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(fmt.Sprintf(`
+		name="rotation"
+		type="testing"
+
+		function vertical(ctx, domain)
+			for i=1,2 do
+				local c = datasrc_config().credentials
+				local resp, err = request(ctx, {url="%s/?key=" .. c.key})
+				if (resp ~= nil and resp.status_code == 200) then
+					new_name(ctx, c.key .. "." .. domain)
+				end
+			end
+		end
+	`, ts.URL), sys)
+	if s == nil {
+		t.Fatal("Failed to initialize the script")
+	}
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("Failed to start the script: %v", err)
+	}
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	s.Input() <- &requests.DNSRequest{Domain: domain}
+
+	select {
+	case req := <-s.Output():
+		if r, ok := req.(*requests.DNSRequest); !ok || r.Name != "available."+domain {
+			t.Errorf("The script did not move on to the next credentials after the rate limit")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The script did not move on to the next credentials after the rate limit")
+	}
+	if usage := dsc.KeyUsage(); usage[0].RateLimited != 1 || usage[1].Uses != 1 {
+		t.Errorf("The key usage of the data source was %v", usage)
+	}
+}
+
+func TestScriptResponseCache(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		fmt.Fprint(w, "www.owasp.org")
+	}))
+	defer ts.Close()
+
+	cfg := config.NewConfig()
+	cfg.Dir = t.TempDir()
+	script := fmt.Sprintf(`
+		name="cache"
+		type="testing"
+
+		function vertical(ctx, domain)
+			request(ctx, {url="%s/?q=" .. domain})
+			new_name(ctx, "www." .. domain)
+		end
+	`, ts.URL)
+
+	domain := "owasp.org"
+	cfg.AddDomain(domain)
+	// Each run uses a new system, so only the on-disk cache is shared by the runs
+	for i := 0; i < 2; i++ {
+		sys := newMockSystem(cfg)
+
+		s := NewScript(script, sys)
+		if s == nil {
+			t.Fatal("Failed to initialize the script")
+		}
+		if err := sys.AddAndStart(s); err != nil {
+			t.Fatalf("Failed to start the script: %v", err)
+		}
+
+		s.Input() <- &requests.DNSRequest{Domain: domain}
+		select {
+		case <-s.Output():
+		case <-time.After(5 * time.Second):
+			t.Fatal("The script did not process the request")
+		}
+		_ = sys.Shutdown()
+	}
+
+	if hits := atomic.LoadInt32(&hits); hits != 1 {
+		t.Errorf("The service received %d requests, expected the second run to use the cached response", hits)
+	}
+	if files, err := filepath.Glob(filepath.Join(cfg.Dir, cacheDirName, "cache", "*.gob")); err != nil || len(files) != 1 {
+		t.Errorf("The cache directory holds %d responses, expected 1", len(files))
+	}
+}
+
+func TestScriptRequestNoCache(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"status":%d}`, atomic.AddInt32(&hits, 1))
+	}))
+	defer ts.Close()
+
+	cfg := config.NewConfig()
+	cfg.Dir = t.TempDir()
+	sys := newMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(fmt.Sprintf(`
+		local json = require("json")
+		name="nocache"
+		type="testing"
+
+		function vertical(ctx, domain)
+			local d
+			for i=1,2 do
+				local resp, err = request(ctx, {url="%s/?q=" .. domain, cache=false})
+				if (err ~= nil and err ~= "") then
+					return
+				end
+				d = json.decode(resp.body)
+			end
+			if (d ~= nil and d.status == 2) then
+				new_name(ctx, "www." .. domain)
+			end
+		end
+	`, ts.URL), sys)
+	if s == nil {
+		t.Fatal("Failed to initialize the script")
+	}
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("Failed to start the script: %v", err)
+	}
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	s.Input() <- &requests.DNSRequest{Domain: domain}
+
+	select {
+	case <-s.Output():
+	case <-time.After(5 * time.Second):
+		t.Fatal("The script received a cached response for the polled request")
+	}
+	if files, err := filepath.Glob(filepath.Join(cfg.Dir, cacheDirName, "nocache", "*.gob")); err != nil || len(files) != 0 {
+		t.Errorf("The cache directory holds %d responses, expected none", len(files))
+	}
+}
+
+func TestScriptPaginate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		// Only the first page links to the next, and the script selects the last page
+		if page == "1" {
+			w.Header().Set("Link", `</?page=2>; rel="next", </?page=3>; rel="last"`)
+		}
+		fmt.Fprintf(w, "page%s", page)
+	}))
+	defer ts.Close()
+
+	cfg := config.NewConfig()
+	cfg.Dir = t.TempDir()
+	sys := newMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(fmt.Sprintf(`
+		name="paginate"
+		type="testing"
+
+		function vertical(ctx, domain)
+			local bodies = {}
+			local pages, err = paginate(ctx, {url="%s/?page=1"}, function(resp, page)
+				table.insert(bodies, resp.body)
+				if (page == 2) then
+					return "%s/?page=3"
+				end
+			end)
+			if (err ~= nil or pages ~= 3) then
+				return
+			end
+			new_name(ctx, table.concat(bodies, "-") .. "." .. domain)
+		end
+	`, ts.URL, ts.URL), sys)
+	if s == nil {
+		t.Fatal("Failed to initialize the script")
+	}
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("Failed to start the script: %v", err)
+	}
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	s.Input() <- &requests.DNSRequest{Domain: domain}
+
+	select {
+	case out := <-s.Output():
+		if req, ok := out.(*requests.DNSRequest); !ok || req.Name != "page1-page2-page3."+domain {
+			t.Errorf("The script provided %v", out)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The script did not request all the pages")
+	}
+}
+
+func TestScriptRetryAfter(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusForbidden} {
+		testScriptRetryAfter(t, status)
+	}
+}
+
+func testScriptRetryAfter(t *testing.T, status int) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	cfg := config.NewConfig()
+	cfg.Dir = t.TempDir()
+	sys := newMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(fmt.Sprintf(`
+		name="retry"
+		type="testing"
+
+		function vertical(ctx, domain)
+			local resp, err = request(ctx, {url="%s/", retries=2})
+			if (err == nil and resp.status_code == 200) then
+				new_name(ctx, "www." .. domain)
+			end
+		end
+	`, ts.URL), sys)
+	if s == nil {
+		t.Fatal("Failed to initialize the script")
+	}
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("Failed to start the script: %v", err)
+	}
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	start := time.Now()
+	s.Input() <- &requests.DNSRequest{Domain: domain}
+
+	select {
+	case <-s.Output():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("The script did not retry the request rate limited with the status %d", status)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("The script retried the request after %s, before the Retry-After passed", elapsed)
+	}
+	if hits := atomic.LoadInt32(&hits); hits != 2 {
+		t.Errorf("The service received %d requests, expected 2", hits)
+	}
+}
+
+func TestScriptCacheStore(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Dir = t.TempDir()
+	script := `
+		name="store"
+		type="testing"
+
+		function vertical(ctx, domain)
+			local cursor = cache_get(ctx, "cursor")
+			if (cursor == nil) then
+				cursor = "first"
+			end
+
+			local err = cache_set(ctx, "cursor", "second")
+			if (err ~= nil) then
+				return
+			end
+			new_name(ctx, cursor .. "." .. domain)
+		end
+	`
+
+	domain := "owasp.org"
+	cfg.AddDomain(domain)
+	// The values kept by the first run are available to the second
+	for _, want := range []string{"first", "second"} {
+		sys := newMockSystem(cfg)
+
+		s := NewScript(script, sys)
+		if s == nil {
+			t.Fatal("Failed to initialize the script")
+		}
+		if err := sys.AddAndStart(s); err != nil {
+			t.Fatalf("Failed to start the script: %v", err)
+		}
+
+		s.Input() <- &requests.DNSRequest{Domain: domain}
+		select {
+		case out := <-s.Output():
+			if req, ok := out.(*requests.DNSRequest); !ok || req.Name != want+"."+domain {
+				t.Errorf("The script provided %v, expected the %s value", out, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("The script did not process the request")
+		}
+		_ = sys.Shutdown()
+	}
+}
+
+func TestScriptConfigOptions(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.GetDataSourceConfig("options").Options = map[string]string{"label": "fenced"}
+
+	sys := newMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(`
+		name="options"
+		type="testing"
+
+		function vertical(ctx, domain)
+			local cfg = datasrc_config()
+			if (cfg.options ~= nil and cfg.options.label ~= nil) then
+				new_name(ctx, cfg.options.label .. "." .. domain)
+			end
+		end
+	`, sys)
+	if s == nil {
+		t.Fatal("Failed to initialize the script")
+	}
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("Failed to start the script: %v", err)
+	}
+
+	domain := "owasp.org"
+	sys.Config().AddDomain(domain)
+	s.Input() <- &requests.DNSRequest{Domain: domain}
+
+	select {
+	case out := <-s.Output():
+		if req, ok := out.(*requests.DNSRequest); !ok || req.Name != "fenced."+domain {
+			t.Errorf("The script provided %v, expected the name from the options", out)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The script did not receive the options of the data source")
+	}
+}
+
+func TestLoadScript(t *testing.T) {
+	sys := newMockSystem(config.NewConfig())
+	defer func() { _ = sys.Shutdown() }()
+
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{
+			name:   "Syntax error",
+			script: `name = "Broken"` + "\n" + `function vertical(ctx, domain`,
+		},
+		{
+			name:   "Missing name",
+			script: `type = "api"`,
+		},
+		{
+			name:   "Missing type",
+			script: `name = "Untyped"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s, err := LoadScript(tt.script, sys); err == nil || s != nil {
+				t.Errorf("LoadScript() = %v, %v, want an error", s, err)
+			}
+		})
+	}
+
+	s, err := LoadScript(`
+	name = "Loaded"
+	type = "api"
+	`, sys)
+	if err != nil {
+		t.Fatalf("LoadScript() error = %v", err)
+	}
+	if s.String() != "Loaded" || s.SourceType != "api" {
+		t.Errorf("LoadScript() = %s of type %s", s.String(), s.SourceType)
+	}
+}
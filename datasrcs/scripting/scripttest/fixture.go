@@ -0,0 +1,109 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package scripttest runs the data source scripts against recorded HTTP fixtures, so the scripts
+// can be developed and tested without reaching their services or providing live credentials.
+package scripttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Fixture holds the HTTP interactions of a script with its service, and the names the script is
+// expected to provide for the domain. When the fixture provides the address, the address callback
+// of the script is executed for the address in place of the vertical callback.
+type Fixture struct {
+	Domain       string         `json:"domain"`
+	Address      string         `json:"address,omitempty"`
+	Credentials  *Credentials   `json:"credentials,omitempty"`
+	Interactions []*Interaction `json:"interactions"`
+	Names        []string       `json:"names"`
+}
+
+// Credentials are provided to the script in place of those found in the configuration.
+type Credentials struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Key      string `json:"apikey,omitempty"`
+	Secret   string `json:"secret,omitempty"`
+}
+
+// Interaction is a request sent by the script and the response of the service.
+type Interaction struct {
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response"`
+}
+
+// Request identifies the requests answered by the response of the interaction. The body is only
+// compared when the fixture provides it.
+type Request struct {
+	Method string `json:"method,omitempty"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the recorded response of the service. The body can be kept in a separate file,
+// named relative to the fixture.
+type Response struct {
+	Status   int               `json:"status,omitempty"`
+	Header   map[string]string `json:"header,omitempty"`
+	Body     string            `json:"body,omitempty"`
+	BodyFile string            `json:"body_file,omitempty"`
+}
+
+// LoadFixture reads the fixture at path, along with the response bodies kept in separate files.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse the fixture %s: %v", path, err)
+	}
+
+	f.Domain = strings.ToLower(strings.TrimSpace(f.Domain))
+	if f.Domain == "" {
+		return nil, fmt.Errorf("the fixture %s does not provide the domain", path)
+	}
+	if f.Address = strings.TrimSpace(f.Address); f.Address != "" && net.ParseIP(f.Address) == nil {
+		return nil, fmt.Errorf("the fixture %s provides the invalid address %s", path, f.Address)
+	}
+
+	for i, in := range f.Interactions {
+		if in.Request == nil || in.Request.URL == "" || in.Response == nil {
+			return nil, fmt.Errorf("interaction %d of the fixture %s is missing the request URL or the response", i+1, path)
+		}
+		if in.Response.BodyFile == "" {
+			continue
+		}
+
+		file := in.Response.BodyFile
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(path), file)
+		}
+
+		body, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the response body of interaction %d: %v", i+1, err)
+		}
+		in.Response.Body = string(body)
+	}
+	return &f, nil
+}
+
+// Save writes the fixture to path.
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
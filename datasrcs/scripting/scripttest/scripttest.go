@@ -0,0 +1,306 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/datasrcs/scripting"
+	amasshttp "github.com/owasp-amass/amass/v3/net/http"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+	"github.com/owasp-amass/resolve"
+)
+
+// DefaultTimeout is the time waited for the script to finish the request of the domain.
+const DefaultTimeout = time.Minute
+
+// The requests per minute allowed while the responses are replayed, so the rate limits of the
+// scripts do not slow down the tests
+const replayRateLimit = 60000
+
+// The scripts share the HTTP client of the net/http package, so one script is run at a time
+var transportLock sync.Mutex
+
+// Result is the outcome of running a script against a fixture.
+type Result struct {
+	// The name of the data source provided by the script
+	Source string
+	// The names provided by the script, in sorted order
+	Names []string
+	// The names of the fixture that were not provided by the script
+	Missing []string
+	// The names provided by the script that are not found in the fixture
+	Unexpected []string
+	// The requests sent by the script without a response in the fixture
+	Unmatched []string
+}
+
+// Passed returns true when the script provided exactly the names of the fixture.
+func (r *Result) Passed() bool {
+	return len(r.Missing) == 0 && len(r.Unexpected) == 0
+}
+
+// Run executes the vertical callback of the script for the domain of the fixture, or the address
+// callback for the address of the fixture, answering the HTTP requests with the recorded responses,
+// and compares the names provided with the fixture.
+func Run(ctx context.Context, script string, f *Fixture, timeout time.Duration) (*Result, error) {
+	rp := newReplayer(f)
+
+	source, names, err := execute(ctx, script, f.Domain, f.Address, func(name string) *config.Credentials {
+		c := f.Credentials
+		if c == nil {
+			return nil
+		}
+		return &config.Credentials{
+			Username: c.Username,
+			Password: c.Password,
+			Key:      c.Key,
+			Secret:   c.Secret,
+		}
+	}, rp, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := stringset.New()
+	defer expected.Close()
+	for _, name := range f.Names {
+		expected.Insert(strings.ToLower(strings.TrimSpace(name)))
+	}
+
+	provided := stringset.New(names...)
+	defer provided.Close()
+
+	res := &Result{
+		Source:    source,
+		Names:     names,
+		Unmatched: rp.Unmatched(),
+	}
+	for _, name := range expected.Slice() {
+		if !provided.Has(name) {
+			res.Missing = append(res.Missing, name)
+		}
+	}
+	for _, name := range names {
+		if !expected.Has(name) {
+			res.Unexpected = append(res.Unexpected, name)
+		}
+	}
+	sort.Strings(res.Missing)
+	return res, nil
+}
+
+// Record executes the vertical callback of the script for the domain, or the address callback when
+// the address is provided, sending the HTTP requests to the service, and returns the fixture
+// holding the interactions and the names provided. The script receives the credentials of its
+// data source from the configuration, and the values of the credentials are replaced with
+// placeholders in the fixture.
+func Record(ctx context.Context, script, domain, addr string, cfg *config.Config, timeout time.Duration) (*Fixture, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if domain == "" {
+		return nil, errors.New("the domain name was not provided")
+	}
+	if addr = strings.TrimSpace(addr); addr != "" && net.ParseIP(addr) == nil {
+		return nil, fmt.Errorf("the address %s is not valid", addr)
+	}
+
+	base := amasshttp.DefaultClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var fc *Credentials
+	rec := newRecorder(base)
+	_, names, err := execute(ctx, script, domain, addr, func(name string) *config.Credentials {
+		var creds *config.Credentials
+
+		if cfg != nil {
+			if dsc := cfg.GetDataSourceConfig(name); dsc != nil {
+				creds = dsc.GetCredentials()
+			}
+		}
+		fc = rec.redact(creds)
+		return creds
+	}, rec, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fixture{
+		Domain:       domain,
+		Address:      addr,
+		Credentials:  fc,
+		Interactions: rec.Interactions(),
+		Names:        names,
+	}, nil
+}
+
+// Verify runs the script file against the fixture file, and reports the differences with the
+// names of the fixture as errors of the test.
+func Verify(t testing.TB, scriptPath, fixturePath string) {
+	t.Helper()
+
+	script, err := os.ReadFile(scriptPath)
+	if err != nil {
+		t.Fatalf("Failed to read the script: %v", err)
+	}
+
+	f, err := LoadFixture(fixturePath)
+	if err != nil {
+		t.Fatalf("Failed to load the fixture: %v", err)
+	}
+
+	res, err := Run(context.Background(), string(script), f, DefaultTimeout)
+	if err != nil {
+		t.Fatalf("Failed to run the script: %v", err)
+	}
+	for _, req := range res.Unmatched {
+		t.Logf("%s: the fixture has no response for %s", res.Source, req)
+	}
+	if len(res.Missing) > 0 {
+		t.Errorf("%s did not provide the names %v", res.Source, res.Missing)
+	}
+	if len(res.Unexpected) > 0 {
+		t.Errorf("%s provided the unexpected names %v", res.Source, res.Unexpected)
+	}
+}
+
+// execute runs the vertical callback of the script, or the address callback when the address is
+// provided, with the credentials selected by the name of the data source and the HTTP requests
+// sent through the transport. The name of the data source and the names it provided are returned
+// in sorted order.
+func execute(ctx context.Context, script, domain, addr string, credentials func(name string) *config.Credentials, rt http.RoundTripper, timeout time.Duration) (string, []string, error) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	dir, err := os.MkdirTemp("", "amass-scripttest")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := config.NewConfig()
+	// The responses are neither cached nor read from the output directory of the user
+	cfg.Dir = dir
+	cfg.ResponseCache = false
+	cfg.AddDomain(domain)
+
+	sys := &systems.SimpleSystem{
+		Cfg:      cfg,
+		Pool:     resolve.NewResolvers(),
+		Trusted:  resolve.NewResolvers(),
+		Graph:    netmap.NewGraph(netmap.NewCayleyGraphMemory()),
+		ASNCache: requests.NewASNCache(),
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	s, err := scripting.LoadScript(script, sys)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load the script: %v", err)
+	}
+
+	dsc := cfg.GetDataSourceConfig(s.String())
+	if creds := credentials(s.String()); creds != nil {
+		c := *creds
+		c.Name = "scripttest"
+		if err := dsc.AddCredentials(&c); err != nil {
+			return "", nil, err
+		}
+	}
+	if _, replay := rt.(*replayer); replay {
+		dsc.RateLimit = replayRateLimit
+	}
+
+	transportLock.Lock()
+	defer transportLock.Unlock()
+
+	prev := amasshttp.DefaultClient.Transport
+	amasshttp.DefaultClient.Transport = rt
+	defer func() { amasshttp.DefaultClient.Transport = prev }()
+
+	if err := sys.AddAndStart(s); err != nil {
+		return s.String(), nil, fmt.Errorf("%s failed to start: %v", s.String(), err)
+	}
+
+	var req interface{} = &requests.DNSRequest{Domain: domain}
+	callback := "vertical"
+	if addr != "" {
+		req = &requests.AddrRequest{Address: addr, Domain: domain, InScope: true}
+		callback = "address"
+	}
+	if !s.HandlesReq(req) {
+		return s.String(), nil, fmt.Errorf("%s does not provide the %s callback", s.String(), callback)
+	}
+
+	names, err := collectNames(ctx, s, req, domain, timeout)
+	return s.String(), names, err
+}
+
+// collectNames sends the request to the script and returns the names provided for the domain.
+func collectNames(ctx context.Context, s *scripting.Script, req interface{}, domain string, timeout time.Duration) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	found := stringset.New()
+	defer found.Close()
+
+	collect := func(out interface{}) {
+		if d, ok := out.(*requests.DNSRequest); ok && d.Name != "" && d.Domain == domain {
+			found.Insert(strings.ToLower(d.Name))
+		}
+	}
+
+	accepted := make(chan bool, 1)
+	go func() {
+		// The script processes the requests one at a time, so the script has finished with the
+		// domain once it accepts the request that follows
+		for _, in := range []interface{}{req, struct{}{}} {
+			select {
+			case <-ctx.Done():
+				accepted <- false
+				return
+			case s.Input() <- in:
+			}
+		}
+		accepted <- true
+	}()
+
+	var finished bool
+loop:
+	for {
+		select {
+		case out := <-s.Output():
+			collect(out)
+		case finished = <-accepted:
+			break loop
+		}
+	}
+	if !finished {
+		return nil, fmt.Errorf("%s did not finish the request of %s: %v", s.String(), domain, ctx.Err())
+	}
+	// The names sent before the script accepted the following request are still buffered
+	for len(s.Output()) > 0 {
+		collect(<-s.Output())
+	}
+
+	names := found.Slice()
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,157 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripttest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+)
+
+func TestVerify(t *testing.T) {
+	for _, script := range []string{
+		"api/binaryedge",
+		"api/censys",
+		"api/chaos",
+		"api/dnsdb",
+		"api/fofa",
+		"api/fullhunt",
+		"api/github",
+		"api/gitlab",
+		"api/internetdb",
+		"api/netlas",
+		"api/securitytrails",
+		"api/virustotal",
+		"api/zoomeye",
+		"archive/wayback",
+		"leak/intelx",
+		"leak/leakix",
+	} {
+		name := path.Base(script)
+
+		t.Run(name, func(t *testing.T) {
+			Verify(t, filepath.Join("..", "..", "..", "resources", "scripts", filepath.FromSlash(script)+".ads"), filepath.Join("testdata", name+".json"))
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	script := `
+	name = "Testing"
+	type = "api"
+
+	function vertical(ctx, domain)
+		local resp, err = request(ctx, {['url']="https://api.example.com/v1/" .. domain .. "?page=1"})
+		if (err == nil or err == "") and resp.status_code == 200 then
+			send_names(ctx, resp.body)
+		end
+		request(ctx, {['url']="https://api.example.com/v1/" .. domain .. "?page=2"})
+	end
+	`
+	f := &Fixture{
+		Domain: "owasp.org",
+		Interactions: []*Interaction{
+			{
+				Request:  &Request{URL: "https://api.example.com/v1/owasp.org?page=1"},
+				Response: &Response{Body: "www.owasp.org api.owasp.org"},
+			},
+		},
+		Names: []string{"www.owasp.org", "mail.owasp.org"},
+	}
+
+	res, err := Run(context.Background(), script, f, 10*time.Second)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if res.Source != "Testing" {
+		t.Errorf("Run() returned the source %s", res.Source)
+	}
+	if want := []string{"api.owasp.org", "www.owasp.org"}; !reflect.DeepEqual(res.Names, want) {
+		t.Errorf("Run() returned the names %v, want %v", res.Names, want)
+	}
+	if want := []string{"mail.owasp.org"}; !reflect.DeepEqual(res.Missing, want) {
+		t.Errorf("Run() returned the missing names %v, want %v", res.Missing, want)
+	}
+	if want := []string{"api.owasp.org"}; !reflect.DeepEqual(res.Unexpected, want) {
+		t.Errorf("Run() returned the unexpected names %v, want %v", res.Unexpected, want)
+	}
+	if want := []string{"GET https://api.example.com/v1/owasp.org?page=2"}; !reflect.DeepEqual(res.Unmatched, want) {
+		t.Errorf("Run() returned the unmatched requests %v, want %v", res.Unmatched, want)
+	}
+	if res.Passed() {
+		t.Error("Run() passed with names missing from the results")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "secretkey" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "www.owasp.org mail.owasp.org")
+	}))
+	defer ts.Close()
+
+	script := fmt.Sprintf(`
+	name = "Testing"
+	type = "api"
+
+	function vertical(ctx, domain)
+		local c = datasrc_config().credentials
+		local resp, err = request(ctx, {['url']="%s/?domain=" .. domain .. "&key=" .. c.key})
+		if (err == nil or err == "") and resp.status_code == 200 then
+			send_names(ctx, resp.body)
+		end
+	end
+	`, ts.URL)
+
+	cfg := config.NewConfig()
+	if err := cfg.GetDataSourceConfig("Testing").AddCredentials(&config.Credentials{Name: "account", Key: "secretkey"}); err != nil {
+		t.Fatalf("Failed to add the credentials: %v", err)
+	}
+
+	f, err := Record(context.Background(), script, "owasp.org", "", cfg, 10*time.Second)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if f.Credentials == nil || f.Credentials.Key != "AMASS_APIKEY" {
+		t.Errorf("Record() returned the credentials %+v", f.Credentials)
+	}
+	if len(f.Interactions) != 1 {
+		t.Fatalf("Record() returned %d interactions, want 1", len(f.Interactions))
+	}
+	if got, want := f.Interactions[0].Request.URL, ts.URL+"/?domain=owasp.org&key=AMASS_APIKEY"; got != want {
+		t.Errorf("Record() recorded the URL %s, want %s", got, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := f.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+	// The recorded fixture is replayed without the service and the credentials
+	ts.Close()
+
+	res, err := Run(context.Background(), script, loaded, 10*time.Second)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !res.Passed() || len(res.Unmatched) > 0 {
+		t.Errorf("Run() of the recorded fixture = %+v", res)
+	}
+}
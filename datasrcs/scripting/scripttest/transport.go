@@ -0,0 +1,223 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripttest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/owasp-amass/amass/v3/config"
+)
+
+// replayer answers the requests of the script with the responses of the fixture.
+type replayer struct {
+	sync.Mutex
+	interactions []*Interaction
+	used         []bool
+	unmatched    []string
+}
+
+func newReplayer(f *Fixture) *replayer {
+	return &replayer{
+		interactions: f.Interactions,
+		used:         make([]bool, len(f.Interactions)),
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface. The interactions are used in the order of
+// the fixture, and the last matching interaction answers the requests sent again. The requests
+// without a matching interaction receive a 404 response.
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	match := -1
+	for i, in := range r.interactions {
+		if !in.Request.matches(req, body) {
+			continue
+		}
+
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match == -1 {
+		r.unmatched = append(r.unmatched, req.Method+" "+req.URL.String())
+		return newResponse(req, &Response{Status: http.StatusNotFound}), nil
+	}
+
+	r.used[match] = true
+	return newResponse(req, r.interactions[match].Response), nil
+}
+
+// Unmatched returns the requests sent by the script that were not found in the fixture.
+func (r *replayer) Unmatched() []string {
+	r.Lock()
+	defer r.Unlock()
+
+	return append([]string(nil), r.unmatched...)
+}
+
+func (fr *Request) matches(req *http.Request, body string) bool {
+	method := fr.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	if !strings.EqualFold(method, req.Method) || fr.URL != req.URL.String() {
+		return false
+	}
+	return fr.Body == "" || fr.Body == body
+}
+
+// recorder sends the requests of the script to the service and keeps the interactions. The
+// credentials found in the requests are replaced with placeholders within the fixture.
+type recorder struct {
+	sync.Mutex
+	base         http.RoundTripper
+	replacer     *strings.Replacer
+	interactions []*Interaction
+}
+
+func newRecorder(base http.RoundTripper) *recorder {
+	return &recorder{
+		base:     base,
+		replacer: strings.NewReplacer(),
+	}
+}
+
+// redact replaces the values of the credentials in the following requests, and returns the
+// credentials holding the placeholders.
+func (r *recorder) redact(creds *config.Credentials) *Credentials {
+	if creds == nil {
+		return nil
+	}
+
+	var pairs []string
+	fc := new(Credentials)
+	for _, c := range []struct {
+		value       string
+		placeholder string
+		field       *string
+	}{
+		{value: creds.Username, placeholder: "AMASS_USERNAME", field: &fc.Username},
+		{value: creds.Password, placeholder: "AMASS_PASSWORD", field: &fc.Password},
+		{value: creds.Key, placeholder: "AMASS_APIKEY", field: &fc.Key},
+		{value: creds.Secret, placeholder: "AMASS_SECRET", field: &fc.Secret},
+	} {
+		if c.value != "" {
+			pairs = append(pairs, c.value, c.placeholder)
+			*c.field = c.placeholder
+		}
+	}
+
+	r.Lock()
+	r.replacer = strings.NewReplacer(pairs...)
+	r.Unlock()
+	return fc
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of %s: %v", req.URL.String(), err)
+	}
+
+	hdr := make(map[string]string)
+	for k := range resp.Header {
+		// The headers of the response are provided by the fixture after it was recorded
+		if k != "Content-Length" && k != "Content-Encoding" && k != "Set-Cookie" {
+			hdr[k] = resp.Header.Get(k)
+		}
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.interactions = append(r.interactions, &Interaction{
+		Request: &Request{
+			Method: req.Method,
+			URL:    r.replacer.Replace(req.URL.String()),
+			Body:   r.replacer.Replace(body),
+		},
+		Response: &Response{
+			Status: resp.StatusCode,
+			Header: hdr,
+			Body:   string(data),
+		},
+	})
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	return resp, nil
+}
+
+// Interactions returns the interactions recorded in the order the requests were sent.
+func (r *recorder) Interactions() []*Interaction {
+	r.Lock()
+	defer r.Unlock()
+
+	return append([]*Interaction(nil), r.interactions...)
+}
+
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	defer req.Body.Close()
+
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
+
+func newResponse(req *http.Request, fr *Response) *http.Response {
+	status := fr.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	hdr := make(http.Header)
+	for k, v := range fr.Header {
+		hdr.Set(k, v)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        hdr,
+		Body:          io.NopCloser(strings.NewReader(fr.Body)),
+		ContentLength: int64(len(fr.Body)),
+		Request:       req,
+	}
+}
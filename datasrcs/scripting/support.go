@@ -142,6 +142,15 @@ func getStringField(L *lua.LState, t lua.LValue, key string) (string, bool) {
 	return "", false
 }
 
+func getBoolField(L *lua.LState, t lua.LValue, key string) (bool, bool) {
+	if lv := L.GetField(t, key); lv != nil {
+		if b, ok := lv.(lua.LBool); ok {
+			return bool(b), true
+		}
+	}
+	return false, false
+}
+
 func getNumberField(L *lua.LState, t lua.LValue, key string) (float64, bool) {
 	if lv := L.GetField(t, key); lv != nil {
 		if n, ok := lv.(lua.LNumber); ok {
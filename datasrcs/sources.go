@@ -9,22 +9,53 @@ import (
 
 	"github.com/owasp-amass/amass/v3/config"
 	"github.com/owasp-amass/amass/v3/datasrcs/scripting"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/resources"
 	"github.com/owasp-amass/amass/v3/systems"
 	"github.com/caffix/service"
 	"github.com/caffix/stringset"
 )
 
+// The trust tiers that select the data sources in place of their names and categories.
+const (
+	// The data sources providing names from DNS, certificates, web archives and crawling
+	TrustedTier = "trusted"
+	// The data sources providing names from scraping, APIs and other indirect evidence
+	UntrustedTier = "untrusted"
+	// The data sources with credentials provided by the configuration
+	AuthenticatedTier = "authenticated"
+)
+
+// SourceTiers returns the trust tiers the data source belongs to.
+func SourceTiers(cfg *config.Config, src service.Service) []string {
+	tiers := []string{UntrustedTier}
+	if requests.TrustedTag(src.Description()) {
+		tiers[0] = TrustedTier
+	}
+
+	if dsc := cfg.GetDataSourceConfig(src.String()); dsc != nil && dsc.HasCredentials() {
+		tiers = append(tiers, AuthenticatedTier)
+	}
+	return tiers
+}
+
 // GetAllSources returns a slice of all data source services initialized.
 func GetAllSources(sys systems.System) []service.Service {
-	srvs := []service.Service{NewRADb(sys)}
+	srvs := []service.Service{NewRADb(sys), NewRDAP(sys)}
 
-	if scripts, err := sys.Config().AcquireScripts(); err == nil {
-		for _, script := range scripts {
-			if s := scripting.NewScript(script, sys); s != nil {
-				srvs = append(srvs, s)
-			}
+	cfg := sys.Config()
+	// The name generators and the other data sources have separate worker slots
+	workers := workerSlots(cfg.DataSourceWorkers)
+	generators := workerSlots(cfg.BruteForceWorkers)
+	for _, s := range loadScripts(sys) {
+		if generator(s) {
+			s.SetWorkers(generators)
+		} else {
+			s.SetWorkers(workers)
 		}
+		srvs = append(srvs, s)
 	}
+	srvs = append(srvs, pluginSources(sys)...)
 
 	sort.Slice(srvs, func(i, j int) bool {
 		return srvs[i].String() < srvs[j].String()
@@ -32,6 +63,53 @@ func GetAllSources(sys systems.System) []service.Service {
 	return srvs
 }
 
+// loadScripts returns the default scripts and those provided by the user. The user provided
+// scripts that fail to load are logged along with the path of the script file.
+func loadScripts(sys systems.System) []*scripting.Script {
+	defaults, err := resources.GetDefaultScripts()
+	if err != nil {
+		return nil
+	}
+
+	paths, err := sys.Config().ScriptPaths()
+	if err != nil {
+		return nil
+	}
+
+	names := stringset.New()
+	defer names.Close()
+
+	var scripts []*scripting.Script
+	// The data sources are identified by name, so only the first script using the name is kept
+	add := func(s *scripting.Script, from string) {
+		if names.Has(s.String()) {
+			sys.Config().Log.Printf("Script: Skipped %s, since the %s data source was already loaded", from, s.String())
+			return
+		}
+		names.Insert(s.String())
+		scripts = append(scripts, s)
+	}
+
+	for _, script := range defaults {
+		if s := scripting.NewScript(script, sys); s != nil {
+			add(s, "a default script")
+		}
+	}
+	for _, path := range paths {
+		if s := loadScriptFile(sys, path); s != nil {
+			add(s, path)
+		}
+	}
+	return scripts
+}
+
+func workerSlots(n int) chan struct{} {
+	if n <= 0 {
+		return nil
+	}
+	return make(chan struct{}, n)
+}
+
 // SelectedDataSources uses the config and available data sources to return the selected data sources.
 func SelectedDataSources(cfg *config.Config, avail []service.Service) []service.Service {
 	specified := stringset.New()
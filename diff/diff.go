@@ -0,0 +1,285 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package diff compares the names, addresses and DNS records discovered by two enumerations.
+package diff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/cayleygraph/quad"
+)
+
+// The DNS record types of the edges leaving the names in the graph
+var recordTypes = map[string]string{
+	"a_record":     "A",
+	"aaaa_record":  "AAAA",
+	"cname_record": "CNAME",
+	"ns_record":    "NS",
+	"mx_record":    "MX",
+	"ptr_record":   "PTR",
+	"srv_record":   "SRV",
+}
+
+// Event identifies an enumeration and the time it was performed.
+type Event struct {
+	UUID   string    `json:"uuid"`
+	Start  time.Time `json:"start"`
+	Finish time.Time `json:"finish"`
+}
+
+// Record is a DNS resource record of a name, other than the address records.
+type Record struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Asset is a name discovered by an enumeration, with the addresses and records it resolved to.
+type Asset struct {
+	Name      string    `json:"name"`
+	Domain    string    `json:"domain"`
+	Addresses []string  `json:"addresses,omitempty"`
+	Records   []*Record `json:"records,omitempty"`
+}
+
+// Change describes how the addresses and records of a name discovered by both enumerations differ.
+type Change struct {
+	Name             string    `json:"name"`
+	Domain           string    `json:"domain"`
+	AddedAddresses   []string  `json:"added_addresses,omitempty"`
+	RemovedAddresses []string  `json:"removed_addresses,omitempty"`
+	AddedRecords     []*Record `json:"added_records,omitempty"`
+	RemovedRecords   []*Record `json:"removed_records,omitempty"`
+}
+
+// Diff contains the differences between the assets of the From and To enumerations.
+type Diff struct {
+	From    *Event    `json:"from,omitempty"`
+	To      *Event    `json:"to,omitempty"`
+	Added   []*Asset  `json:"added"`
+	Removed []*Asset  `json:"removed"`
+	Changed []*Change `json:"changed"`
+}
+
+// Events returns the differences between the assets discovered by the enumerations identified by the
+// uuids, limited to the domains provided, or the domains of both enumerations when none are provided.
+func Events(ctx context.Context, g *netmap.Graph, from, to string, domains []string) (*Diff, error) {
+	if len(domains) == 0 {
+		domains = append(g.EventDomains(ctx, from), g.EventDomains(ctx, to)...)
+	}
+
+	older, err := EventAssets(ctx, g, from, domains)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := EventAssets(ctx, g, to, domains)
+	if err != nil {
+		return nil, err
+	}
+
+	d := Compare(older, newer)
+	d.From = event(ctx, g, from)
+	d.To = event(ctx, g, to)
+	return d, nil
+}
+
+func event(ctx context.Context, g *netmap.Graph, uuid string) *Event {
+	start, finish := g.EventDateRange(ctx, uuid)
+
+	return &Event{
+		UUID:   uuid,
+		Start:  start,
+		Finish: finish,
+	}
+}
+
+// EventAssets returns the names in scope of the domains that were discovered by the enumeration identified by
+// the uuid. The records are limited to those with targets that were also discovered by the enumeration.
+func EventAssets(ctx context.Context, g *netmap.Graph, uuid string, domains []string) ([]*Asset, error) {
+	quads, err := g.ReadEventQuads(ctx, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the quads of event %s: %v", uuid, err)
+	}
+
+	// The nodes associated with the event
+	inEvent := make(map[string]struct{})
+	for _, q := range quads {
+		if valToStr(q.Subject) == uuid && isIRI(q.Object) {
+			inEvent[valToStr(q.Object)] = struct{}{}
+		}
+	}
+
+	assets := make(map[string]*Asset)
+	for _, q := range quads {
+		subject := valToStr(q.Subject)
+		if _, found := inEvent[subject]; !found {
+			continue
+		}
+
+		domain := scopeDomain(subject, domains)
+		if domain == "" {
+			continue
+		}
+
+		if valToStr(q.Predicate) == "type" {
+			if valToStr(q.Object) == netmap.TypeFQDN {
+				asset(assets, subject, domain)
+			}
+			continue
+		}
+
+		rrtype, found := recordTypes[valToStr(q.Predicate)]
+		if !found || !isIRI(q.Object) {
+			continue
+		}
+		target := valToStr(q.Object)
+		if _, found := inEvent[target]; !found {
+			continue
+		}
+
+		a := asset(assets, subject, domain)
+		if rrtype == "A" || rrtype == "AAAA" {
+			a.Addresses = append(a.Addresses, target)
+		} else {
+			a.Records = append(a.Records, &Record{Type: rrtype, Value: target})
+		}
+	}
+
+	var results []*Asset
+	for _, a := range assets {
+		sort.Strings(a.Addresses)
+		sortRecords(a.Records)
+		results = append(results, a)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results, nil
+}
+
+func asset(assets map[string]*Asset, name, domain string) *Asset {
+	a, found := assets[name]
+	if !found {
+		a = &Asset{Name: name, Domain: domain}
+		assets[name] = a
+	}
+	return a
+}
+
+// Compare returns the assets added, removed and changed in the newer assets.
+func Compare(older, newer []*Asset) *Diff {
+	d := &Diff{
+		Added:   []*Asset{},
+		Removed: []*Asset{},
+		Changed: []*Change{},
+	}
+
+	oldmap := make(map[string]*Asset, len(older))
+	for _, a := range older {
+		oldmap[a.Name] = a
+	}
+	newmap := make(map[string]*Asset, len(newer))
+	for _, a := range newer {
+		newmap[a.Name] = a
+	}
+
+	for _, a := range newer {
+		o, found := oldmap[a.Name]
+		if !found {
+			d.Added = append(d.Added, a)
+			continue
+		}
+
+		c := &Change{
+			Name:             a.Name,
+			Domain:           a.Domain,
+			AddedAddresses:   stringsMissing(a.Addresses, o.Addresses),
+			RemovedAddresses: stringsMissing(o.Addresses, a.Addresses),
+			AddedRecords:     recordsMissing(a.Records, o.Records),
+			RemovedRecords:   recordsMissing(o.Records, a.Records),
+		}
+		if len(c.AddedAddresses) > 0 || len(c.RemovedAddresses) > 0 ||
+			len(c.AddedRecords) > 0 || len(c.RemovedRecords) > 0 {
+			d.Changed = append(d.Changed, c)
+		}
+	}
+
+	for _, o := range older {
+		if _, found := newmap[o.Name]; !found {
+			d.Removed = append(d.Removed, o)
+		}
+	}
+	return d
+}
+
+// stringsMissing returns the values of a that are not found in b.
+func stringsMissing(a, b []string) []string {
+	set := make(map[string]struct{}, len(b))
+	for _, s := range b {
+		set[s] = struct{}{}
+	}
+
+	var results []string
+	for _, s := range a {
+		if _, found := set[s]; !found {
+			results = append(results, s)
+		}
+	}
+	return results
+}
+
+// recordsMissing returns the records of a that are not found in b.
+func recordsMissing(a, b []*Record) []*Record {
+	set := make(map[Record]struct{}, len(b))
+	for _, r := range b {
+		set[*r] = struct{}{}
+	}
+
+	var results []*Record
+	for _, r := range a {
+		if _, found := set[*r]; !found {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+func sortRecords(records []*Record) {
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Type != records[j].Type {
+			return records[i].Type < records[j].Type
+		}
+		return records[i].Value < records[j].Value
+	})
+}
+
+// scopeDomain returns the domain that the name belongs to, or an empty string when the name is out of scope.
+func scopeDomain(name string, domains []string) string {
+	for _, d := range domains {
+		if name == d || strings.HasSuffix(name, "."+d) {
+			return d
+		}
+	}
+	return ""
+}
+
+func isIRI(v quad.Value) bool {
+	_, ok := v.(quad.IRI)
+	return ok
+}
+
+func valToStr(v quad.Value) string {
+	switch val := v.(type) {
+	case quad.IRI:
+		return strings.TrimRight(strings.TrimLeft(string(val), "<"), ">")
+	case quad.String:
+		return string(val)
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/caffix/netmap"
+)
+
+const (
+	olderUUID = "ef9f9475-34eb-465e-81ec-9a1f7f8d2f5b"
+	newerUUID = "0a3c4c48-59b4-4f7d-9d6c-2a4bba2f5f8e"
+)
+
+func testGraph(t *testing.T) *netmap.Graph {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+
+	for _, uuid := range []string{olderUUID, newerUUID} {
+		if _, err := g.UpsertEvent(ctx, uuid); err != nil {
+			t.Fatalf("failed to insert the event: %v", err)
+		}
+	}
+
+	// The name discovered by both enumerations moved to another address
+	_ = g.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", olderUUID)
+	_ = g.UpsertA(ctx, "www.owasp.org", "192.168.1.2", "DNS", newerUUID)
+	// The name is only found by the older enumeration
+	_ = g.UpsertA(ctx, "old.owasp.org", "192.168.1.3", "DNS", olderUUID)
+	// The names are only found by the newer enumeration
+	_ = g.UpsertCNAME(ctx, "new.owasp.org", "www.owasp.org", "DNS", newerUUID)
+	_ = g.UpsertNS(ctx, "owasp.org", "ns1.owasp.org", "DNS", newerUUID)
+	return g
+}
+
+func TestEventAssets(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	assets, err := EventAssets(context.Background(), g, newerUUID, []string{"owasp.org"})
+	if err != nil {
+		t.Fatalf("failed to obtain the assets: %v", err)
+	}
+
+	expected := []*Asset{
+		{Name: "new.owasp.org", Domain: "owasp.org", Records: []*Record{{Type: "CNAME", Value: "www.owasp.org"}}},
+		{Name: "ns1.owasp.org", Domain: "owasp.org"},
+		{Name: "owasp.org", Domain: "owasp.org", Records: []*Record{{Type: "NS", Value: "ns1.owasp.org"}}},
+		{Name: "www.owasp.org", Domain: "owasp.org", Addresses: []string{"192.168.1.2"}},
+	}
+	if !reflect.DeepEqual(assets, expected) {
+		t.Errorf("unexpected assets:")
+		for _, a := range assets {
+			t.Errorf("%+v", a)
+		}
+	}
+
+	if assets, _ := EventAssets(context.Background(), g, newerUUID, []string{"example.com"}); len(assets) != 0 {
+		t.Errorf("expected no assets for domains out of scope, got %d", len(assets))
+	}
+}
+
+func TestEvents(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	d, err := Events(context.Background(), g, olderUUID, newerUUID, nil)
+	if err != nil {
+		t.Fatalf("failed to compare the events: %v", err)
+	}
+	if d.From.UUID != olderUUID || d.To.UUID != newerUUID || d.From.Start.IsZero() {
+		t.Errorf("unexpected events: %+v and %+v", d.From, d.To)
+	}
+
+	var added []string
+	for _, a := range d.Added {
+		added = append(added, a.Name)
+	}
+	if !reflect.DeepEqual(added, []string{"new.owasp.org", "ns1.owasp.org"}) {
+		t.Errorf("unexpected added names: %v", added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Name != "old.owasp.org" {
+		t.Errorf("unexpected removed names: %+v", d.Removed)
+	}
+
+	expected := []*Change{
+		{
+			Name:         "owasp.org",
+			Domain:       "owasp.org",
+			AddedRecords: []*Record{{Type: "NS", Value: "ns1.owasp.org"}},
+		},
+		{
+			Name:             "www.owasp.org",
+			Domain:           "owasp.org",
+			AddedAddresses:   []string{"192.168.1.2"},
+			RemovedAddresses: []string{"192.168.1.1"},
+		},
+	}
+	if !reflect.DeepEqual(d.Changed, expected) {
+		t.Errorf("unexpected changes:")
+		for _, c := range d.Changed {
+			t.Errorf("%+v", c)
+		}
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	assets := []*Asset{
+		{Name: "www.owasp.org", Domain: "owasp.org", Addresses: []string{"192.168.1.1"}},
+	}
+
+	d := Compare(assets, assets)
+	if len(d.Added) != 0 || len(d.Removed) != 0 || len(d.Changed) != 0 {
+		t.Errorf("expected no differences, got %+v", d)
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package diskqueue provides a FIFO queue that keeps a bounded number of elements in memory and
+// spills the rest to disk, so the queues of enumerations with huge scopes do not exhaust the memory.
+package diskqueue
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Queue is a FIFO queue holding up to the limit of elements in memory. The elements appended beyond
+// the limit are gob encoded into segment files, so their concrete types must be registered using
+// gob.Register. A limit of zero keeps all the elements in memory.
+type Queue struct {
+	sync.Mutex
+	parent   string
+	dir      string
+	limit    int
+	mem      []interface{}
+	segments []*segment
+	writer   *segment
+	spilled  int
+	seq      int
+	err      error
+	closed   bool
+}
+
+type segment struct {
+	path  string
+	file  *os.File
+	buf   *bufio.Writer
+	enc   *gob.Encoder
+	count int
+}
+
+type record struct {
+	Data interface{}
+}
+
+// New returns a Queue keeping up to limit elements in memory and spilling the rest to a
+// directory created within dir when needed.
+func New(dir string, limit int) (*Queue, error) {
+	if limit < 0 {
+		return nil, errors.New("the queue memory limit cannot be negative")
+	}
+	if limit > 0 && dir == "" {
+		return nil, errors.New("the queue requires a directory for spilling the elements to disk")
+	}
+	return &Queue{parent: dir, limit: limit}, nil
+}
+
+// Append adds the data to the back of the Queue.
+func (q *Queue) Append(data interface{}) error {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return errors.New("the queue has been closed")
+	}
+	// The elements in memory must precede all the elements on disk
+	if q.limit == 0 || (q.spilled == 0 && len(q.mem) < q.limit) {
+		q.mem = append(q.mem, data)
+		return nil
+	}
+	return q.spill(data)
+}
+
+func (q *Queue) spill(data interface{}) error {
+	if q.writer == nil {
+		if err := q.newSegment(); err != nil {
+			return err
+		}
+	}
+
+	if err := q.writer.enc.Encode(&record{Data: data}); err != nil {
+		return fmt.Errorf("failed to write the element to the queue segment: %v", err)
+	}
+	q.writer.count++
+	q.spilled++
+	// The segments hold the number of elements loaded into memory at once
+	if q.writer.count >= q.limit {
+		return q.closeSegment()
+	}
+	return nil
+}
+
+func (q *Queue) newSegment() error {
+	if q.dir == "" {
+		if err := os.MkdirAll(q.parent, 0755); err != nil {
+			return fmt.Errorf("failed to create the queue directory: %v", err)
+		}
+
+		dir, err := os.MkdirTemp(q.parent, "queue-")
+		if err != nil {
+			return fmt.Errorf("failed to create the queue directory: %v", err)
+		}
+		q.dir = dir
+	}
+
+	q.seq++
+	path := filepath.Join(q.dir, fmt.Sprintf("%08d.gob", q.seq))
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create the queue segment: %v", err)
+	}
+
+	buf := bufio.NewWriter(f)
+	q.writer = &segment{
+		path: path,
+		file: f,
+		buf:  buf,
+		enc:  gob.NewEncoder(buf),
+	}
+	return nil
+}
+
+func (q *Queue) closeSegment() error {
+	w := q.writer
+	file, buf := w.file, w.buf
+	q.writer = nil
+	w.file, w.buf, w.enc = nil, nil, nil
+	q.segments = append(q.segments, w)
+
+	if err := buf.Flush(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write the queue segment: %v", err)
+	}
+	return file.Close()
+}
+
+// load reads the oldest segment into memory and removes the file. When the segment cannot be read,
+// the elements decoded before the failure are kept and the rest of the segment is dropped.
+func (q *Queue) load() error {
+	if len(q.segments) == 0 && q.writer != nil {
+		if err := q.closeSegment(); err != nil {
+			return err
+		}
+	}
+	if len(q.segments) == 0 {
+		return nil
+	}
+
+	seg := q.segments[0]
+	data, err := readSegment(seg)
+
+	q.segments = q.segments[1:]
+	q.spilled -= seg.count
+	q.mem = append(q.mem, data...)
+	_ = os.Remove(seg.path)
+	return err
+}
+
+func readSegment(seg *segment) ([]interface{}, error) {
+	f, err := os.Open(seg.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the queue segment: %v", err)
+	}
+	defer f.Close()
+
+	data := make([]interface{}, 0, seg.count)
+	dec := gob.NewDecoder(bufio.NewReader(f))
+	for i := 0; i < seg.count; i++ {
+		var r record
+
+		if err := dec.Decode(&r); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return data, fmt.Errorf("failed to read the queue segment: %v", err)
+		}
+		data = append(data, r.Data)
+	}
+	return data, nil
+}
+
+// Next removes and returns the data at the front of the Queue.
+func (q *Queue) Next() (interface{}, bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	// Segments that cannot be read are dropped in favor of the following segments
+	for len(q.mem) == 0 && q.spilled > 0 {
+		if err := q.load(); err != nil {
+			q.err = err
+		}
+	}
+	if len(q.mem) == 0 {
+		return nil, false
+	}
+
+	data := q.mem[0]
+	q.mem[0] = nil
+	q.mem = q.mem[1:]
+	return data, true
+}
+
+// Len returns the number of elements in memory and on disk.
+func (q *Queue) Len() int {
+	q.Lock()
+	defer q.Unlock()
+
+	return len(q.mem) + q.spilled
+}
+
+// Err returns the last error that caused elements on disk to be lost.
+func (q *Queue) Err() error {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.err
+}
+
+// Close discards the elements and removes the files spilled to disk.
+func (q *Queue) Close() error {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+
+	if q.writer != nil {
+		_ = q.writer.file.Close()
+		q.writer = nil
+	}
+	q.mem = nil
+	q.segments = nil
+	q.spilled = 0
+	if q.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(q.dir)
+}
@@ -0,0 +1,165 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package diskqueue
+
+import (
+	"encoding/gob"
+	"os"
+	"testing"
+)
+
+type testElement struct {
+	Name  string
+	Times int
+}
+
+func init() {
+	gob.Register(&testElement{})
+}
+
+func TestQueueOrder(t *testing.T) {
+	dir := t.TempDir()
+
+	q, err := New(dir, 3)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer q.Close()
+
+	next := 0
+	expect := func(count int) {
+		for i := 0; i < count; i++ {
+			data, ok := q.Next()
+			if !ok {
+				t.Fatalf("Next() returned no element, expected %d", next)
+			}
+			if e := data.(*testElement); e.Times != next {
+				t.Fatalf("Next() returned %d, expected %d", e.Times, next)
+			}
+			next++
+		}
+	}
+
+	appended := 0
+	add := func(count int) {
+		for i := 0; i < count; i++ {
+			if err := q.Append(&testElement{Name: "www.owasp.org", Times: appended}); err != nil {
+				t.Fatalf("Append() error = %v", err)
+			}
+			appended++
+		}
+	}
+	// The elements beyond the limit are spilled to disk across multiple segments
+	add(10)
+	if q.Len() != 10 {
+		t.Errorf("Len() = %d, expected 10", q.Len())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("the queue created %d directories", len(entries))
+	}
+
+	expect(4)
+	add(5)
+	expect(11)
+	if _, ok := q.Next(); ok || q.Len() != 0 {
+		t.Errorf("the queue still has %d elements", q.Len())
+	}
+	if err := q.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+
+	add(1)
+	expect(1)
+	if err := q.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Close() did not remove the spilled elements")
+	}
+	if err := q.Append(&testElement{}); err == nil {
+		t.Errorf("Append() on the closed queue did not return an error")
+	}
+}
+
+func TestQueueInMemory(t *testing.T) {
+	q, err := New("", 0)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer q.Close()
+
+	for i := 0; i < 100; i++ {
+		// The elements kept in memory do not need to be registered
+		_ = q.Append(i)
+	}
+	for i := 0; i < 100; i++ {
+		if data, ok := q.Next(); !ok || data.(int) != i {
+			t.Fatalf("Next() = %v, %v, expected %d", data, ok, i)
+		}
+	}
+
+	if _, err := New("", 10); err == nil {
+		t.Errorf("New() accepted a memory limit without a directory")
+	}
+	if _, err := New(t.TempDir(), -1); err == nil {
+		t.Errorf("New() accepted a negative memory limit")
+	}
+}
+
+func TestQueueUnregisteredType(t *testing.T) {
+	q, err := New(t.TempDir(), 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer q.Close()
+
+	_ = q.Append(struct{ Name string }{})
+	if err := q.Append(struct{ Name string }{Name: "spilled"}); err == nil {
+		t.Errorf("Append() spilled an unregistered type")
+	}
+}
+
+func TestQueueCorruptedSegment(t *testing.T) {
+	q, err := New(t.TempDir(), 2)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer q.Close()
+
+	for i := 0; i < 7; i++ {
+		if err := q.Append(&testElement{Name: "www.owasp.org", Times: i}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	// The first segment on disk holds the elements 2 and 3
+	if err := os.WriteFile(q.segments[0].path, []byte("corrupted"), 0644); err != nil {
+		t.Fatalf("failed to corrupt the segment: %v", err)
+	}
+
+	var got []int
+	for {
+		data, ok := q.Next()
+		if !ok {
+			break
+		}
+		got = append(got, data.(*testElement).Times)
+	}
+
+	expected := []int{0, 1, 4, 5, 6}
+	if len(got) != len(expected) {
+		t.Fatalf("Next() returned %v, expected %v", got, expected)
+	}
+	for i, n := range expected {
+		if got[i] != n {
+			t.Fatalf("Next() returned %v, expected %v", got, expected)
+		}
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len() = %d after the queue was drained", q.Len())
+	}
+	if err := q.Err(); err == nil {
+		t.Errorf("Err() did not report the corrupted segment")
+	}
+}
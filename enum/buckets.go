@@ -0,0 +1,166 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/caffix/netmap"
+	"github.com/caffix/pipeline"
+	"github.com/caffix/stringset"
+	"github.com/owasp-amass/amass/v3/net/cloud"
+	"github.com/owasp-amass/amass/v3/requests"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	maxBucketTasks  int = 10
+	maxBucketLabels int = 500
+	bucketSource        = "Bucket Guess"
+)
+
+// bucketTask derives cloud storage bucket names from the discovered labels and probes for their existence.
+type bucketTask struct {
+	sync.Mutex
+	enum     *Enumeration
+	keywords map[string][]string
+	labels   *stringset.Set
+	names    *stringset.Set
+}
+
+func newBucketTask(e *Enumeration) *bucketTask {
+	b := &bucketTask{
+		enum:     e,
+		keywords: make(map[string][]string),
+		labels:   stringset.New(),
+		names:    stringset.New(),
+	}
+
+	for _, domain := range e.Config.Domains() {
+		keywords := append([]string{}, e.Config.BucketKeywords...)
+
+		if suffix, _ := publicsuffix.PublicSuffix(domain); suffix != domain {
+			label := strings.TrimSuffix(domain, "."+suffix)
+			keywords = append(keywords, label[strings.LastIndex(label, ".")+1:])
+		}
+		b.keywords[domain] = stringset.Deduplicate(keywords)
+	}
+	return b
+}
+
+func (b *bucketTask) stop() {
+	b.labels.Close()
+	b.names.Close()
+}
+
+// Process implements the pipeline Task interface.
+func (b *bucketTask) Process(ctx context.Context, data pipeline.Data, tp pipeline.TaskParams) (pipeline.Data, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil
+	default:
+	}
+
+	req, ok := data.(*requests.DNSRequest)
+	if !ok || req == nil || !b.enum.Config.IsDomainInScope(req.Name) {
+		return data, nil
+	}
+
+	domain := strings.ToLower(req.Domain)
+	keywords, found := b.keywords[domain]
+	if !found {
+		return data, nil
+	}
+
+	var candidates []string
+	// The keywords are combined with common words the first time the domain is seen
+	if b.newName(domain) {
+		candidates = cloud.BucketCandidates(keywords)
+	}
+	if name := strings.ToLower(req.Name); name != domain {
+		if labels := b.newLabels(strings.Split(strings.TrimSuffix(name, "."+domain), ".")); len(labels) > 0 {
+			candidates = append(candidates, cloud.BucketCandidates(keywords, labels...)...)
+		}
+	}
+
+	for _, name := range candidates {
+		if !b.newName(name) {
+			continue
+		}
+
+		for _, bkt := range cloud.ProbeBuckets(ctx, name) {
+			if err := b.insertBucket(ctx, domain, bkt); err != nil {
+				b.enum.Config.Log.Print(err.Error())
+			}
+		}
+	}
+	return data, nil
+}
+
+func (b *bucketTask) newLabels(labels []string) []string {
+	b.Lock()
+	defer b.Unlock()
+
+	var results []string
+	for _, l := range labels {
+		if l == "" || b.labels.Has(l) || b.labels.Len() >= maxBucketLabels {
+			continue
+		}
+
+		b.labels.Insert(l)
+		results = append(results, l)
+	}
+	return results
+}
+
+func (b *bucketTask) newName(name string) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.names.Has(name) {
+		return false
+	}
+	b.names.Insert(name)
+	return true
+}
+
+func (b *bucketTask) insertBucket(ctx context.Context, domain string, bkt *cloud.Bucket) error {
+	uuid := b.enum.Config.UUID.String()
+
+	node, err := b.enum.graph.UpsertNode(ctx, bkt.URL, cloud.TypeBucket)
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the bucket %s: %v", b.enum.graph, bkt.URL, err)
+	}
+	if err := b.enum.graph.AddNodeToEvent(ctx, node, bucketSource, uuid); err != nil {
+		return fmt.Errorf("%s failed to add the bucket %s to the event: %v", b.enum.graph, bkt.URL, err)
+	}
+	for pred, val := range map[string]string{"name": bkt.Name, "provider": bkt.Provider, "access": bkt.Access} {
+		if err := b.enum.graph.UpsertProperty(ctx, node, pred, val); err != nil {
+			return fmt.Errorf("%s failed to insert the bucket %s property: %v", b.enum.graph, pred, err)
+		}
+	}
+
+	dnode, err := b.enum.graph.UpsertFQDN(ctx, domain, "DNS", uuid)
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the FQDN %s: %v", b.enum.graph, domain, err)
+	}
+	if err := b.enum.graph.UpsertEdge(ctx, &netmap.Edge{
+		Predicate: cloud.TypeBucket,
+		From:      dnode,
+		To:        node,
+	}); err != nil {
+		return fmt.Errorf("%s failed to link the bucket %s: %v", b.enum.graph, bkt.URL, err)
+	}
+
+	f := &requests.Finding{
+		Type:        requests.FindingBucket,
+		Description: fmt.Sprintf("Discovered the %s storage bucket %s with %s access", bkt.Provider, bkt.Name, bkt.Access),
+		Evidence:    bkt.URL,
+	}
+	return b.enum.graph.UpsertProperty(ctx, dnode, requests.FindingPredicate, f.String())
+}
@@ -0,0 +1,95 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// The frequency of the checks performed against the enumeration budget
+const budgetCheckInterval = time.Second
+
+// budget pauses the release of new names while the items waiting in the pipeline or the heap
+// in use exceed the configured limits. The data sources, including the brute forcing and
+// alteration scripts, block on their output until the DNS resolution catches up.
+type budget struct {
+	sync.Mutex
+	src    *enumSource
+	items  int
+	memory uint64
+	paused bool
+}
+
+// newBudget returns the budget for the input source, or nil when no limits were configured.
+func newBudget(src *enumSource) *budget {
+	cfg := src.enum.Config
+	if cfg.QueueBudget <= 0 && cfg.MemoryBudget <= 0 {
+		return nil
+	}
+
+	b := &budget{
+		src:    src,
+		items:  cfg.QueueBudget,
+		memory: uint64(cfg.MemoryBudget) << 20,
+	}
+	go b.monitor()
+	return b
+}
+
+// exceeded returns true while the release of new names is paused.
+func (b *budget) exceeded() bool {
+	if b == nil {
+		return false
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	return b.paused
+}
+
+func (b *budget) monitor() {
+	t := time.NewTicker(budgetCheckInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-b.src.done:
+			return
+		case <-t.C:
+			b.check(b.src.pipeline.DataItemCount(), heapInUse())
+		}
+	}
+}
+
+func (b *budget) check(items int, heap uint64) {
+	b.Lock()
+	defer b.Unlock()
+
+	memOver := b.memory > 0 && heap >= b.memory
+	if b.paused {
+		// Resume after the backlog falls well below the limit to avoid pausing on every check,
+		// and always once the pipeline is empty, since nothing else can reduce the memory in use
+		if items == 0 || (!memOver && (b.items == 0 || items < b.items*3/4)) {
+			b.paused = false
+			b.src.enum.Config.Log.Printf("The enumeration resumed the release of names with %d items waiting in the pipeline", items)
+		}
+		return
+	}
+
+	if items > 0 && (memOver || (b.items > 0 && items >= b.items)) {
+		b.paused = true
+		b.src.enum.Config.Log.Printf("The enumeration paused the release of names with %d items waiting in the pipeline and %d MB of heap in use", items, heap>>20)
+	}
+}
+
+func heapInUse() uint64 {
+	var stats runtime.MemStats
+
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
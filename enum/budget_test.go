@@ -0,0 +1,53 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"testing"
+
+	"github.com/owasp-amass/amass/v3/config"
+)
+
+func TestBudgetCheck(t *testing.T) {
+	const mb = uint64(1) << 20
+
+	tests := []struct {
+		name   string
+		items  int
+		memory uint64
+		paused bool
+		count  int
+		heap   uint64
+		expect bool
+	}{
+		{name: "below the queue budget", items: 100, count: 99, expect: false},
+		{name: "at the queue budget", items: 100, count: 100, expect: true},
+		{name: "paused above three quarters", items: 100, paused: true, count: 75, expect: true},
+		{name: "resumed below three quarters", items: 100, paused: true, count: 74, expect: false},
+		{name: "resumed on an empty pipeline", items: 100, memory: 512 * mb, paused: true, count: 0, heap: 1024 * mb, expect: false},
+		{name: "not paused on an empty pipeline", memory: 512 * mb, count: 0, heap: 1024 * mb, expect: false},
+		{name: "at the memory budget", memory: 512 * mb, count: 1, heap: 512 * mb, expect: true},
+		{name: "below the memory budget", memory: 512 * mb, count: 1000, heap: 511 * mb, expect: false},
+		{name: "paused over the memory budget", items: 100, memory: 512 * mb, paused: true, count: 10, heap: 600 * mb, expect: true},
+		{name: "resumed below the memory budget", memory: 512 * mb, paused: true, count: 1000, heap: 500 * mb, expect: false},
+		{name: "memory budget with the queue budget reached", items: 100, memory: 512 * mb, count: 100, heap: 100 * mb, expect: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &budget{
+				src:    &enumSource{enum: &Enumeration{Config: config.NewConfig()}},
+				items:  tt.items,
+				memory: tt.memory,
+				paused: tt.paused,
+			}
+
+			b.check(tt.count, tt.heap)
+			if b.exceeded() != tt.expect {
+				t.Errorf("check(%d, %d) paused = %t, expected %t", tt.count, tt.heap, b.exceeded(), tt.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/caffix/netmap"
+	"github.com/caffix/pipeline"
+	"github.com/cayleygraph/quad"
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/amass/v3/net/cloud"
+	"github.com/owasp-amass/amass/v3/net/rdap"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/resolve"
+	"golang.org/x/net/publicsuffix"
+)
+
+const maxDanglingTasks int = 50
+
+// querier performs the DNS queries of the checks, such as the trusted resolvers.
+type querier interface {
+	QueryBlocking(ctx context.Context, msg *dns.Msg) (*dns.Msg, error)
+}
+
+// domainRegistry provides the registration data of domain names.
+type domainRegistry interface {
+	Domain(ctx context.Context, name string) (*rdap.Domain, error)
+}
+
+// danglingTask reports DNS records that reference resources no longer owned or reachable.
+type danglingTask struct {
+	enum     *Enumeration
+	resolver querier
+	client   domainRegistry
+}
+
+func newDanglingTask(e *Enumeration) *danglingTask {
+	return &danglingTask{
+		enum:     e,
+		resolver: e.Sys.TrustedResolvers(),
+		client:   rdap.NewClient(),
+	}
+}
+
+// Process implements the pipeline Task interface.
+func (d *danglingTask) Process(ctx context.Context, data pipeline.Data, tp pipeline.TaskParams) (pipeline.Data, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil
+	default:
+	}
+
+	req, ok := data.(*requests.DNSRequest)
+	if !ok || req == nil || !d.enum.Config.IsDomainInScope(req.Name) {
+		return data, nil
+	}
+
+	for _, rec := range req.Records {
+		var f *requests.Finding
+
+		target := strings.ToLower(resolve.RemoveLastDot(rec.Data))
+		switch uint16(rec.Type) {
+		case dns.TypeCNAME:
+			f = d.checkCNAME(ctx, target)
+		case dns.TypeNS:
+			f = d.checkNS(ctx, target)
+		case dns.TypeMX:
+			f = d.checkMX(ctx, target)
+		case dns.TypeA, dns.TypeAAAA:
+			f = d.checkAddr(ctx, target)
+		}
+
+		if f != nil {
+			if err := d.enum.graph.UpsertProperty(ctx, netmap.Node(req.Name), requests.FindingPredicate, f.String()); err != nil {
+				d.enum.Config.Log.Printf("%s failed to insert the dangling record finding: %v", d.enum.graph, err)
+			}
+		}
+	}
+	return data, nil
+}
+
+func (d *danglingTask) checkCNAME(ctx context.Context, target string) *requests.Finding {
+	if target == "" || !nxdomain(ctx, d.resolver, target, dns.TypeA) {
+		return nil
+	}
+
+	return &requests.Finding{
+		Type:        requests.FindingDangling,
+		Description: "CNAME record points to a nonexistent name",
+		Evidence:    fmt.Sprintf("CNAME %s returns NXDOMAIN", target),
+	}
+}
+
+func (d *danglingTask) checkNS(ctx context.Context, target string) *requests.Finding {
+	domain, err := publicsuffix.EffectiveTLDPlusOne(target)
+	if err != nil || d.enum.Config.IsDomainInScope(domain) {
+		return nil
+	}
+
+	// The registration data is only consulted once the delegated domain fails to resolve
+	if !nxdomain(ctx, d.resolver, domain, dns.TypeNS) {
+		return nil
+	}
+	// Lookup failures, such as a TLD without an RDAP server, do not show the domain is unregistered
+	if _, err := d.client.Domain(ctx, domain); !errors.Is(err, rdap.ErrNotFound) {
+		return nil
+	}
+
+	return &requests.Finding{
+		Type:        requests.FindingDangling,
+		Description: "NS record delegates to an unregistered domain",
+		Evidence:    fmt.Sprintf("NS %s belongs to %s, which is not registered", target, domain),
+	}
+}
+
+func (d *danglingTask) checkMX(ctx context.Context, target string) *requests.Finding {
+	if target == "" {
+		return nil
+	}
+	// A failed query does not show the host is missing
+	if found, err := resolves(ctx, d.resolver, target); err != nil || found {
+		return nil
+	}
+
+	return &requests.Finding{
+		Type:        requests.FindingDangling,
+		Description: "MX record points to a host that does not resolve",
+		Evidence:    fmt.Sprintf("MX %s has no A or AAAA records", target),
+	}
+}
+
+// checkAddr reports the addresses that were tagged as part of a range published by a cloud provider,
+// while the ranges currently published by the provider no longer contain them. The address is likely
+// to have been released by the provider, and can be allocated to other customers.
+func (d *danglingTask) checkAddr(ctx context.Context, addr string) *requests.Finding {
+	ranges := d.enum.cloud
+	if ranges == nil || ranges.Lookup(addr) != nil {
+		return nil
+	}
+
+	props, err := d.enum.graph.ReadProperties(ctx, netmap.Node(addr), cloud.Predicate)
+	if err != nil {
+		return nil
+	}
+
+	for _, p := range props {
+		s, ok := quad.NativeOf(p.Value).(string)
+		if !ok {
+			continue
+		}
+
+		rng, ok := cloud.ParseRange(s)
+		// The providers identified by their ASNs do not publish the ranges
+		if !ok || !ranges.HasProvider(rng.Provider) {
+			continue
+		}
+
+		return &requests.Finding{
+			Type:        requests.FindingDangling,
+			Description: "Address record points into a cloud range no longer published by the provider",
+			Evidence:    fmt.Sprintf("%s was within the %s range %s", addr, rng.Provider, rng.CIDR),
+		}
+	}
+	return nil
+}
+
+// nxdomain returns true when the resolver reports that the name does not exist.
+func nxdomain(ctx context.Context, r querier, name string, qtype uint16) bool {
+	resp, err := r.QueryBlocking(ctx, resolve.QueryMsg(name, qtype))
+
+	return err == nil && resp.Rcode == dns.RcodeNameError
+}
+
+// resolves returns true when the name has at least one A or AAAA record, and an error
+// when the resolver was unable to answer the queries.
+func resolves(ctx context.Context, r querier, name string) (bool, error) {
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		resp, err := r.QueryBlocking(ctx, resolve.QueryMsg(name, qtype))
+		if err != nil {
+			return false, err
+		}
+		if resp.Rcode != dns.RcodeSuccess {
+			continue
+		}
+
+		for _, a := range resolve.ExtractAnswers(resp) {
+			if a.Type == qtype {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,204 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/cloud"
+	"github.com/owasp-amass/amass/v3/net/rdap"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+// stubResolver answers the queries for the names it holds, and returns NXDOMAIN for the rest.
+type stubResolver struct {
+	answers map[string]string
+	errs    map[string]error
+}
+
+func (r *stubResolver) QueryBlocking(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	q := msg.Question[0]
+	if err, found := r.errs[q.Name]; found {
+		return nil, err
+	}
+
+	resp := new(dns.Msg)
+	resp.SetReply(msg)
+	addr, found := r.answers[q.Name]
+	if !found {
+		resp.Rcode = dns.RcodeNameError
+		return resp, nil
+	}
+
+	switch q.Qtype {
+	case dns.TypeA:
+		rr, _ := dns.NewRR(q.Name + " 300 IN A " + addr)
+		resp.Answer = append(resp.Answer, rr)
+	case dns.TypeNS:
+		rr, _ := dns.NewRR(q.Name + " 300 IN NS ns1." + q.Name)
+		resp.Answer = append(resp.Answer, rr)
+	}
+	return resp, nil
+}
+
+type stubRegistry map[string]error
+
+func (s stubRegistry) Domain(ctx context.Context, name string) (*rdap.Domain, error) {
+	if err, found := s[name]; found {
+		return nil, err
+	}
+	return &rdap.Domain{Name: name}, nil
+}
+
+func newTestDanglingTask(t *testing.T) *danglingTask {
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	t.Cleanup(func() { g.Close() })
+
+	e := &Enumeration{
+		Config: cfg,
+		graph:  g,
+		cloud: cloud.NewRanges([]*cloud.Range{
+			{CIDR: "3.0.0.0/8", Provider: cloud.AWS, Service: "AMAZON"},
+		}),
+	}
+	return &danglingTask{
+		enum: e,
+		resolver: &stubResolver{
+			answers: map[string]string{
+				"www.owasp.org.":    "104.16.1.1",
+				"mail.owasp.org.":   "104.16.1.2",
+				"registered.com.":   "192.0.2.1",
+				"owasp.github.io.":  "185.199.108.153",
+				"mail.example.com.": "192.0.2.25",
+			},
+			errs: map[string]error{"timeout.owasp.org.": errors.New("timeout")},
+		},
+		client: stubRegistry{
+			"expired.com":  rdap.ErrNotFound,
+			"no-rdap.zone": errors.New("no RDAP server is available for the TLD"),
+		},
+	}
+}
+
+func TestDanglingCNAME(t *testing.T) {
+	d := newTestDanglingTask(t)
+
+	tests := []struct {
+		target   string
+		dangling bool
+	}{
+		{target: "owasp.github.io", dangling: false},
+		{target: "missing.herokuapp.com", dangling: true},
+		{target: "timeout.owasp.org", dangling: false},
+		{target: "", dangling: false},
+	}
+
+	for _, tt := range tests {
+		if f := d.checkCNAME(context.Background(), tt.target); (f != nil) != tt.dangling {
+			t.Errorf("checkCNAME(%q) returned %v, expected a finding: %t", tt.target, f, tt.dangling)
+		} else if f != nil && f.Type != requests.FindingDangling {
+			t.Errorf("checkCNAME(%q) returned the finding type %s", tt.target, f.Type)
+		}
+	}
+}
+
+func TestDanglingNS(t *testing.T) {
+	d := newTestDanglingTask(t)
+
+	tests := []struct {
+		name     string
+		target   string
+		dangling bool
+	}{
+		{name: "delegated domain resolves", target: "ns1.registered.com", dangling: false},
+		{name: "domain in scope", target: "ns1.owasp.org", dangling: false},
+		{name: "unregistered domain", target: "ns1.expired.com", dangling: true},
+		{name: "registration lookup failed", target: "ns1.no-rdap.zone", dangling: false},
+		{name: "registered domain without records", target: "ns1.parked.com", dangling: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if f := d.checkNS(context.Background(), tt.target); (f != nil) != tt.dangling {
+				t.Errorf("checkNS(%q) returned %v, expected a finding: %t", tt.target, f, tt.dangling)
+			}
+		})
+	}
+}
+
+func TestDanglingMX(t *testing.T) {
+	d := newTestDanglingTask(t)
+
+	tests := []struct {
+		target   string
+		dangling bool
+	}{
+		{target: "mail.owasp.org", dangling: false},
+		{target: "mail.example.com", dangling: false},
+		{target: "mx.retired.com", dangling: true},
+		{target: "timeout.owasp.org", dangling: false},
+		{target: "", dangling: false},
+	}
+
+	for _, tt := range tests {
+		if f := d.checkMX(context.Background(), tt.target); (f != nil) != tt.dangling {
+			t.Errorf("checkMX(%q) returned %v, expected a finding: %t", tt.target, f, tt.dangling)
+		}
+	}
+}
+
+func TestDanglingAddr(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDanglingTask(t)
+
+	tagged := map[string]*cloud.Range{
+		// Still within the ranges published by AWS
+		"3.5.1.1": {CIDR: "3.5.0.0/16", Provider: cloud.AWS, Service: "EC2"},
+		// The range is no longer published by AWS
+		"52.95.1.1": {CIDR: "52.95.0.0/16", Provider: cloud.AWS, Service: "EC2"},
+		// The ranges of Akamai are identified by the ASNs
+		"23.32.1.1": {CIDR: "23.32.0.0/11", Provider: cloud.Akamai},
+	}
+	for addr, rng := range tagged {
+		node, err := d.enum.graph.UpsertAddress(ctx, addr, "DNS", "ef9f9475-34eb-465e-81ec-9a1f7f8d2f5b")
+		if err != nil {
+			t.Fatalf("failed to insert %s: %v", addr, err)
+		}
+		if err := d.enum.graph.UpsertProperty(ctx, node, cloud.Predicate, rng.String()); err != nil {
+			t.Fatalf("failed to tag %s: %v", addr, err)
+		}
+	}
+
+	tests := []struct {
+		addr     string
+		dangling bool
+	}{
+		{addr: "3.5.1.1", dangling: false},
+		{addr: "52.95.1.1", dangling: true},
+		{addr: "23.32.1.1", dangling: false},
+		{addr: "192.0.2.1", dangling: false},
+		{addr: "10.0.0.1", dangling: false},
+	}
+
+	for _, tt := range tests {
+		if f := d.checkAddr(ctx, tt.addr); (f != nil) != tt.dangling {
+			t.Errorf("checkAddr(%q) returned %v, expected a finding: %t", tt.addr, f, tt.dangling)
+		}
+	}
+
+	// No findings are reported without the published ranges
+	d.enum.cloud = nil
+	if f := d.checkAddr(ctx, "52.95.1.1"); f != nil {
+		t.Errorf("checkAddr() returned %v without the published ranges", f)
+	}
+}
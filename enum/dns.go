@@ -15,6 +15,9 @@ import (
 	"github.com/caffix/pipeline"
 	"github.com/caffix/queue"
 	"github.com/miekg/dns"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/dns/batch"
+	"github.com/owasp-amass/amass/v3/net/dns/socks"
 	"github.com/owasp-amass/amass/v3/requests"
 	"github.com/owasp-amass/resolve"
 )
@@ -24,6 +27,8 @@ const (
 	maxRcodeServerFails int           = 3
 	initialBackoffDelay time.Duration = 250 * time.Millisecond
 	maximumBackoffDelay time.Duration = 4 * time.Second
+	// The queries in flight for the batch DNS sender without a rate limit
+	defaultBatchInFlight int = 10000
 )
 
 // FwdQueryTypes include the DNS record types that are queried for a discovered name.
@@ -44,6 +49,7 @@ type req struct {
 	InScope    bool
 	Sent       bool
 	HasRecords bool
+	Batch      bool
 }
 
 // dnsTask is the task that handles all DNS name resolution requests within the pipeline.
@@ -55,6 +61,8 @@ type dnsTask struct {
 	enum      *Enumeration
 	done      chan struct{}
 	pool      *resolve.Resolvers
+	batch     *batch.Sender
+	proxied   *socks.Sender
 	params    pipeline.TaskParams
 	reqs      map[string]*req
 	resps     chan *dns.Msg
@@ -72,7 +80,38 @@ func newDNSTask(e *Enumeration, trusted bool) *dnsTask {
 		pool = e.Sys.TrustedResolvers()
 		qps = e.Config.TrustedQPS
 	}
-	plen := pool.Len() * qps
+	var sender *batch.Sender
+	// The names guessed by brute forcing can be sent to the trusted resolvers at a high rate
+	if !trusted && !e.Config.SOCKS5DNS && e.Config.BatchDNS && len(e.Config.TrustedResolvers) > 0 {
+		s, err := batch.NewSender(e.Config.TrustedResolvers, e.Config.BatchQPS)
+		if err != nil {
+			e.Config.Log.Printf("Failed to start the batch DNS sender: %v", err)
+		} else {
+			sender = s
+		}
+	}
+
+	def := pool.Len() * qps
+	if sender != nil {
+		def = batchInFlight(e.Config.BatchQPS, def)
+	}
+	plen := resolutionWorkers(e, def)
+
+	var proxied *socks.Sender
+	// Most SOCKS5 proxies do not relay UDP, so the queries are sent to the trusted resolvers over TCP
+	if e.Config.SOCKS5DNS {
+		trustedAddrs := config.DefaultBaselineResolvers
+		if len(e.Config.TrustedResolvers) > 0 {
+			trustedAddrs = e.Config.TrustedResolvers
+		}
+
+		s, err := socks.NewSender(e.Config.SOCKS5Proxy, trustedAddrs, plen)
+		if err != nil {
+			e.Config.Log.Printf("Failed to start the SOCKS5 DNS sender: %v", err)
+		} else {
+			proxied = s
+		}
+	}
 
 	dt := &dnsTask{
 		trust:     trust,
@@ -80,6 +119,8 @@ func newDNSTask(e *Enumeration, trusted bool) *dnsTask {
 		enum:      e,
 		done:      make(chan struct{}, 2),
 		pool:      pool,
+		batch:     sender,
+		proxied:   proxied,
 		reqs:      make(map[string]*req),
 		resps:     make(chan *dns.Msg, plen),
 		respQueue: queue.NewQueue(),
@@ -95,6 +136,28 @@ func newDNSTask(e *Enumeration, trusted bool) *dnsTask {
 	return dt
 }
 
+// batchInFlight returns the number of queries in flight that lets the batch DNS sender
+// reach its rate, which is a second of queries when the rate is limited.
+func batchInFlight(qps, def int) int {
+	n := qps
+	if n <= 0 {
+		n = defaultBatchInFlight
+	}
+	if n < def {
+		return def
+	}
+	return n
+}
+
+// resolutionWorkers returns the configured number of concurrent queries, or the default
+// based on the size and rate limit of the resolver pool.
+func resolutionWorkers(e *Enumeration, def int) int {
+	if n := e.Config.ResolutionWorkers; n > 0 {
+		return n
+	}
+	return def
+}
+
 func (dt *dnsTask) stop() {
 	select {
 	case <-dt.done:
@@ -102,6 +165,12 @@ func (dt *dnsTask) stop() {
 	default:
 	}
 	close(dt.done)
+	if dt.batch != nil {
+		dt.batch.Stop()
+	}
+	if dt.proxied != nil {
+		dt.proxied.Stop()
+	}
 	// TODO: empty the channel and queue to delete requests
 }
 
@@ -139,12 +208,7 @@ func (dt *dnsTask) rootTaskFunc() pipeline.TaskFunc {
 				return nil, nil
 			}
 		case *requests.SubdomainRequest:
-			r = &requests.DNSRequest{
-				Name:   v.Name,
-				Domain: v.Domain,
-				Tag:    v.Tag,
-				Source: v.Source,
-			}
+			r = requests.NewDNSRequest(v.Name, v.Domain, v.Tag, v.Source)
 		}
 
 		if r != nil && dt.enum.Config.IsDomainInScope(r.Name) {
@@ -176,14 +240,18 @@ func (dt *dnsTask) Process(ctx context.Context, data pipeline.Data, tp pipeline.
 		msg := resolve.QueryMsg(v.Name, qtype)
 		k := key(msg.Id, msg.Question[0].Name)
 
-		if dt.addReqWithIncrement(k, &req{
+		entry := &req{
 			Ctx:        ctx,
 			Data:       data.Clone(),
 			Qtype:      qtype,
 			Attempts:   1,
 			HasRecords: len(v.Records) > 0,
-		}) {
-			dt.pool.Query(ctx, msg, dt.resps)
+			Batch:      dt.batch != nil && guessedName(v.Tag),
+		}
+		if dt.addReqWithIncrement(k, entry) {
+			// The registry holds the clone and the request does not continue down the pipeline
+			requests.ReleaseDNSRequest(v)
+			dt.query(ctx, msg, entry)
 			return nil, nil
 		} else {
 			dt.enum.Config.Log.Printf("Failed to enter %s into the request registry on the %s DNS task", msg.Question[0].Name, dt.trust)
@@ -192,6 +260,27 @@ func (dt *dnsTask) Process(ctx context.Context, data pipeline.Data, tp pipeline.
 	return data, nil
 }
 
+// query sends the guessed names through the batch DNS sender when it is enabled, and all the names
+// through the SOCKS5 proxy when the queries are sent over TCP. The queries are held while the
+// enumeration is paused.
+func (dt *dnsTask) query(ctx context.Context, msg *dns.Msg, entry *req) {
+	// The senders report no response when the context ends while paused
+	_ = dt.enum.pause.wait(ctx)
+	if dt.proxied != nil {
+		dt.proxied.Query(ctx, msg, dt.resps)
+		return
+	}
+	if entry.Batch {
+		dt.batch.Query(ctx, msg, dt.resps)
+		return
+	}
+	dt.pool.Query(ctx, msg, dt.resps)
+}
+
+func guessedName(tag string) bool {
+	return tag == requests.BRUTE || tag == requests.ALT || tag == requests.GUESS
+}
+
 func (dt *dnsTask) nextStage(ctx context.Context, data pipeline.Data) {
 	dt.Lock()
 	params := dt.params
@@ -255,12 +344,26 @@ func (dt *dnsTask) delReq(key string) *req {
 	return nil
 }
 
+// pendingReqs returns the number of requests waiting for the DNS responses.
+func (dt *dnsTask) pendingReqs() int {
+	dt.Lock()
+	defer dt.Unlock()
+
+	return len(dt.reqs)
+}
+
 func (dt *dnsTask) delReqWithDecrement(key string) {
 	if req := dt.delReq(key); req != nil {
 		dt.release <- struct{}{}
 
-		if !req.Sent && (req.InScope || req.HasRecords) {
+		if req.Sent {
+			return
+		}
+		if req.InScope || req.HasRecords {
 			dt.nextStage(req.Ctx, req.Data)
+		} else if r, ok := req.Data.(*requests.DNSRequest); ok {
+			// Most brute forced names end here, so the requests are recycled
+			requests.ReleaseDNSRequest(r)
 		}
 	}
 }
@@ -336,7 +439,7 @@ func (dt *dnsTask) retry(msg *dns.Msg, id uint16, entry *req) {
 		dt.delReq(k)
 		dt.addReq(key(msg.Id, msg.Question[0].Name), entry)
 		time.Sleep(resolve.TruncatedExponentialBackoff(entry.Attempts-1, initialBackoffDelay, maximumBackoffDelay))
-		dt.pool.Query(entry.Ctx, msg, dt.resps)
+		dt.query(entry.Ctx, msg, entry)
 	} else {
 		dt.enum.Config.Log.Printf("%s was dropped after failing to resolve %d times on the %s DNS task", msg.Question[0].Name, entry.Attempts-1, dt.trust)
 		dt.delReqWithDecrement(k)
@@ -353,7 +456,7 @@ func (dt *dnsTask) nextType(ctx context.Context, name string, id, qtype uint16,
 		msg := resolve.QueryMsg(name, entry.Qtype)
 		dt.delReq(k)
 		dt.addReq(key(msg.Id, msg.Question[0].Name), entry)
-		dt.pool.Query(ctx, msg, dt.resps)
+		dt.query(ctx, msg, entry)
 	} else {
 		dt.delReqWithDecrement(k)
 	}
@@ -0,0 +1,102 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"sync"
+	"time"
+
+	"github.com/caffix/service"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+// domainBudget limits the wall-clock time spent on each root domain. The time of a domain starts
+// with the first name received for it, and once it runs out, the brute forcing and alteration
+// names of the domain are dropped while the names from the other data sources are still resolved.
+type domainBudget struct {
+	sync.Mutex
+	enum   *Enumeration
+	limit  time.Duration
+	now    func() time.Time
+	starts map[string]time.Time
+	logged map[string]struct{}
+}
+
+// newDomainBudget returns the budget for the enumeration, or nil when no limit was configured.
+func newDomainBudget(e *Enumeration) *domainBudget {
+	if e.Config.DomainTimeBudget <= 0 {
+		return nil
+	}
+
+	return &domainBudget{
+		enum:   e,
+		limit:  time.Duration(e.Config.DomainTimeBudget) * time.Minute,
+		now:    time.Now,
+		starts: make(map[string]time.Time),
+		logged: make(map[string]struct{}),
+	}
+}
+
+// drop returns true when the request is a guessed name of a root domain out of time.
+func (b *domainBudget) drop(req *requests.DNSRequest) bool {
+	if b == nil || req.Domain == "" {
+		return false
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if _, found := b.starts[req.Domain]; !found {
+		b.starts[req.Domain] = b.now()
+		return false
+	}
+	if !guessedName(req.Tag) || !b.ranOut(req.Domain) {
+		return false
+	}
+
+	if _, logged := b.logged[req.Domain]; !logged {
+		b.logged[req.Domain] = struct{}{}
+		b.enum.Config.Log.Printf("The time budget of %s ran out, dropping its brute forcing and alteration names", req.Domain)
+	}
+	return true
+}
+
+// expired returns true when the time budget of the root domain has run out.
+func (b *domainBudget) expired(domain string) bool {
+	if b == nil || domain == "" {
+		return false
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	return b.ranOut(domain)
+}
+
+// ranOut must be called while holding the lock.
+func (b *domainBudget) ranOut(domain string) bool {
+	start, found := b.starts[domain]
+
+	return found && b.now().Sub(start) >= b.limit
+}
+
+// skipRequest returns true when the request would have the brute forcing or alteration
+// source generate names for a root domain that ran out of time.
+func (b *domainBudget) skipRequest(src service.Service, element interface{}) bool {
+	if b == nil || !guessedName(src.Description()) {
+		return false
+	}
+
+	var domain string
+	switch req := element.(type) {
+	case *requests.DNSRequest:
+		domain = req.Domain
+	case *requests.ResolvedRequest:
+		domain = req.Domain
+	case *requests.SubdomainRequest:
+		domain = req.Domain
+	}
+	return b.expired(domain)
+}
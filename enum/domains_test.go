@@ -0,0 +1,109 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"testing"
+	"time"
+
+	"github.com/caffix/service"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+type testSource struct {
+	*service.BaseService
+	sourceType string
+}
+
+func (s *testSource) Description() string {
+	return s.sourceType
+}
+
+func TestDomainBudgetDrop(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DomainTimeBudget = 10
+
+	now := time.Now()
+	b := newDomainBudget(&Enumeration{Config: cfg})
+	b.now = func() time.Time { return now }
+
+	name := func(n, domain, tag string) *requests.DNSRequest {
+		return &requests.DNSRequest{Name: n, Domain: domain, Tag: tag}
+	}
+	// The time of the domain starts with the first name received
+	if b.drop(name("owasp.org", "owasp.org", requests.DNS)) {
+		t.Error("drop() dropped the first name of the domain")
+	}
+
+	now = now.Add(9 * time.Minute)
+	if b.drop(name("www.owasp.org", "owasp.org", requests.BRUTE)) || b.expired("owasp.org") {
+		t.Error("drop() dropped a guessed name before the time budget ran out")
+	}
+	if b.drop(name("example.com", "example.com", requests.DNS)) {
+		t.Error("drop() dropped the first name of the second domain")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.expired("owasp.org") || b.expired("example.com") || b.expired("unknown.org") {
+		t.Error("expired() did not report the domain out of time")
+	}
+	for _, tag := range []string{requests.BRUTE, requests.ALT, requests.GUESS} {
+		if !b.drop(name("dev.owasp.org", "owasp.org", tag)) {
+			t.Errorf("drop() kept the %s name of the domain out of time", tag)
+		}
+	}
+	// The names from the other data sources are still resolved
+	for _, tag := range []string{requests.DNS, requests.API, requests.CERT} {
+		if b.drop(name("api.owasp.org", "owasp.org", tag)) {
+			t.Errorf("drop() dropped the %s name of the domain out of time", tag)
+		}
+	}
+	if b.drop(name("dev.example.com", "example.com", requests.BRUTE)) {
+		t.Error("drop() dropped the guessed name of the domain with time remaining")
+	}
+
+	var none *domainBudget
+	if none.drop(name("dev.owasp.org", "owasp.org", requests.BRUTE)) || none.expired("owasp.org") {
+		t.Error("the budget without a limit dropped the name")
+	}
+}
+
+func TestDomainBudgetSkipRequest(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DomainTimeBudget = 1
+
+	now := time.Now()
+	b := newDomainBudget(&Enumeration{Config: cfg})
+	b.now = func() time.Time { return now }
+	b.drop(&requests.DNSRequest{Name: "owasp.org", Domain: "owasp.org", Tag: requests.DNS})
+	now = now.Add(time.Minute)
+
+	brute := &testSource{sourceType: requests.BRUTE}
+	alt := &testSource{sourceType: requests.ALT}
+	api := &testSource{sourceType: requests.API}
+
+	tests := []struct {
+		name    string
+		src     service.Service
+		element interface{}
+		skip    bool
+	}{
+		{name: "brute forcing resolved name", src: brute, element: &requests.ResolvedRequest{Name: "www.owasp.org", Domain: "owasp.org"}, skip: true},
+		{name: "brute forcing subdomain", src: brute, element: &requests.SubdomainRequest{Name: "dev.owasp.org", Domain: "owasp.org"}, skip: true},
+		{name: "alterations resolved name", src: alt, element: &requests.ResolvedRequest{Name: "www.owasp.org", Domain: "owasp.org"}, skip: true},
+		{name: "other data source", src: api, element: &requests.ResolvedRequest{Name: "www.owasp.org", Domain: "owasp.org"}, skip: false},
+		{name: "domain with time remaining", src: brute, element: &requests.ResolvedRequest{Name: "www.example.com", Domain: "example.com"}, skip: false},
+		{name: "address request", src: brute, element: &requests.AddrRequest{Address: "192.0.2.1", Domain: "owasp.org"}, skip: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if skip := b.skipRequest(tt.src, tt.element); skip != tt.skip {
+				t.Errorf("skipRequest() = %t, expected %t", skip, tt.skip)
+			}
+		})
+	}
+}
@@ -6,18 +6,36 @@ package enum
 
 import (
 	"context"
+	"encoding/gob"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/caffix/netmap"
 	"github.com/caffix/pipeline"
 	"github.com/caffix/queue"
 	"github.com/caffix/service"
+	"github.com/caffix/stringset"
 	"github.com/owasp-amass/amass/v3/config"
 	"github.com/owasp-amass/amass/v3/datasrcs"
+	"github.com/owasp-amass/amass/v3/diskqueue"
+	"github.com/owasp-amass/amass/v3/net/cloud"
 	"github.com/owasp-amass/amass/v3/requests"
 	"github.com/owasp-amass/amass/v3/systems"
 )
 
+// The most names of the previous enumerations kept in memory by the new-only mode
+const maxKnownNames = 1000000
+
+func init() {
+	// The requests waiting for the data sources can be spilled to disk
+	gob.Register(&requests.DNSRequest{})
+	gob.Register(&requests.ResolvedRequest{})
+	gob.Register(&requests.SubdomainRequest{})
+	gob.Register(&requests.AddrRequest{})
+	gob.Register(&requests.ASNRequest{})
+}
+
 // Enumeration is the object type used to execute a DNS enumeration.
 type Enumeration struct {
 	Config   *config.Config
@@ -27,13 +45,24 @@ type Enumeration struct {
 	srcs     []service.Service
 	done     chan struct{}
 	nameSrc  *enumSource
+	domains  *domainBudget
 	subTask  *subdomainTask
 	dnsTask  *dnsTask
 	valTask  *dnsTask
 	store    *dataManager
+	takeover *takeoverTask
+	dangling *danglingTask
+	buckets  *bucketTask
+	probe    *probeTask
+	portscan *portScanTask
+	cloud    *cloud.Ranges
+	inflight *stringset.Set
 	requests queue.Queue
 	plock    sync.Mutex
 	pending  bool
+	pause    *pauseGate
+	progress *progress
+	srcStats map[string]*SourceStats
 }
 
 // NewEnumeration returns an initialized Enumeration that has not been started yet.
@@ -43,7 +72,10 @@ func NewEnumeration(cfg *config.Config, sys systems.System, graph *netmap.Graph)
 		Sys:      sys,
 		graph:    graph,
 		srcs:     datasrcs.SelectedDataSources(cfg, sys.DataSources()),
+		inflight: stringset.New(),
 		requests: queue.NewQueue(),
+		pause:    newPauseGate(),
+		progress: new(progress),
 	}
 }
 
@@ -60,8 +92,36 @@ func (e *Enumeration) Start(ctx context.Context) error {
 	var cancel context.CancelFunc
 	e.ctx, cancel = context.WithCancel(ctx)
 	defer cancel()
+	defer e.progress.finish()
+	// The data sources report the brute forcing names planned from the wordlist
+	for _, src := range e.srcs {
+		if ps, ok := src.(plannedSource); ok {
+			ps.SetPlanner(e.progress.plannedGuesses)
+		}
+	}
+	e.markSourceStats()
+	e.domains = newDomainBudget(e)
 	go e.manageDataSrcRequests()
 
+	if err := systems.SetEventWorkspace(e.ctx, e.graph, e.Config.UUID.String(), e.Config.Workspace); err != nil {
+		return err
+	}
+
+	if !e.Config.Passive && e.Config.Takeovers {
+		t, err := newTakeoverTask(e)
+		if err != nil {
+			return err
+		}
+		e.takeover = t
+	}
+	if !e.Config.Passive && e.Config.HTTPProbes {
+		p, err := newProbeTask(e)
+		if err != nil {
+			return err
+		}
+		e.probe = p
+		defer e.probe.stop()
+	}
 	if !e.Config.Passive {
 		e.dnsTask = newDNSTask(e, false)
 		e.valTask = newDNSTask(e, true)
@@ -70,6 +130,19 @@ func (e *Enumeration) Start(ctx context.Context) error {
 		defer e.subTask.Stop()
 		defer e.dnsTask.stop()
 		defer e.valTask.stop()
+
+		if e.Config.DanglingRecords {
+			e.dangling = newDanglingTask(e)
+		}
+		if e.Config.PortScans {
+			e.portscan = newPortScanTask(e)
+			defer e.portscan.stop()
+		}
+		if e.Config.Buckets {
+			e.buckets = newBucketTask(e)
+			defer e.buckets.stop()
+		}
+		e.loadCloudRanges()
 	}
 
 	var stages []pipeline.Stage
@@ -77,14 +150,24 @@ func (e *Enumeration) Start(ctx context.Context) error {
 		stages = append(stages, pipeline.FIFO("root", e.valTask.rootTaskFunc()))
 		stages = append(stages, pipeline.FIFO("dns", e.dnsTask))
 		stages = append(stages, pipeline.FIFO("validate", e.valTask))
-		stages = append(stages, pipeline.FIFO("store", e.store))
+		stages = append(stages, e.storeStage())
+		if post := e.postStoreStages(); len(post) > 0 {
+			stages = append(stages, pipeline.FIFO("inflight", e.markInFlight()))
+			stages = append(stages, post...)
+			stages = append(stages, pipeline.FIFO("landed", e.unmarkInFlight()))
+		}
 		stages = append(stages, pipeline.FIFO("", e.subTask))
 	}
 
 	p := pipeline.NewPipeline(stages...)
+	var known *stringset.Set
+	if e.Config.NewOnly {
+		known = e.knownNames()
+	}
 	// The pipeline input source will receive all the names
-	e.nameSrc = newEnumSource(p, e)
+	e.nameSrc = newEnumSource(p, e, known)
 	defer e.nameSrc.Stop()
+	e.progress.begin(e.activeWork)
 
 	e.submitASNs()
 	e.submitDomainNames()
@@ -107,6 +190,87 @@ func (e *Enumeration) Start(ctx context.Context) error {
 	return err
 }
 
+// The optional stages that examine the names after they have been stored.
+func (e *Enumeration) postStoreStages() []pipeline.Stage {
+	var stages []pipeline.Stage
+
+	if e.takeover != nil {
+		stages = append(stages, pipeline.DynamicPool("takeover", e.pausable(e.takeover), maxTakeoverTasks))
+	}
+	if e.dangling != nil {
+		stages = append(stages, pipeline.DynamicPool("dangling", e.pausable(e.dangling), maxDanglingTasks))
+	}
+	if e.portscan != nil {
+		stages = append(stages, pipeline.DynamicPool("portscan", e.pausable(e.portscan), maxPortScanTasks))
+	}
+	if e.probe != nil {
+		stages = append(stages, pipeline.DynamicPool("probe", e.pausable(e.probe), maxProbeTasks))
+	}
+	if e.buckets != nil {
+		stages = append(stages, pipeline.DynamicPool("buckets", e.pausable(e.buckets), maxBucketTasks))
+	}
+	return stages
+}
+
+// InFlight returns true when the name has been stored, but the stages examining it have not finished.
+func (e *Enumeration) InFlight(name string) bool {
+	return e.inflight.Has(name)
+}
+
+// storeStage returns the stage writing to the graph, which uses a pool of workers when configured.
+func (e *Enumeration) storeStage() pipeline.Stage {
+	if n := e.Config.GraphWriteWorkers; n > 1 {
+		return pipeline.FixedPool("store", e.store, n)
+	}
+	return pipeline.FIFO("store", e.store)
+}
+
+func (e *Enumeration) markInFlight() pipeline.TaskFunc {
+	return pipeline.TaskFunc(func(ctx context.Context, data pipeline.Data, tp pipeline.TaskParams) (pipeline.Data, error) {
+		if req, ok := data.(*requests.DNSRequest); ok && req != nil {
+			e.inflight.Insert(req.Name)
+		}
+		return data, nil
+	})
+}
+
+func (e *Enumeration) unmarkInFlight() pipeline.TaskFunc {
+	return pipeline.TaskFunc(func(ctx context.Context, data pipeline.Data, tp pipeline.TaskParams) (pipeline.Data, error) {
+		if req, ok := data.(*requests.DNSRequest); ok && req != nil {
+			e.inflight.Remove(req.Name)
+		}
+		return data, nil
+	})
+}
+
+// loadCloudRanges provides the cached cloud provider ranges, and downloads the published ranges in
+// the background when the cache is stale, so the enumeration does not wait for the providers.
+func (e *Enumeration) loadCloudRanges() {
+	if !e.Config.CloudRanges {
+		return
+	}
+
+	dir := config.OutputDirectory(e.Config.Dir)
+	ranges, stale := cloud.Cached(dir)
+	e.cloud = ranges
+	if !stale {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(e.ctx, time.Minute)
+		defer cancel()
+
+		latest, err := cloud.Load(ctx, dir)
+		if err != nil {
+			e.Config.Log.Print(err.Error())
+		}
+		if latest.Len() > 0 {
+			ranges.Replace(latest)
+		}
+	}()
+}
+
 // Release the root domain names to the input source and each data source.
 func (e *Enumeration) submitDomainNames() {
 	for _, domain := range e.Config.Domains() {
@@ -117,8 +281,9 @@ func (e *Enumeration) submitDomainNames() {
 			Source: "DNS",
 		}
 
-		e.nameSrc.newName(req)
+		// The input source recycles the requests it rejects
 		e.sendRequests(req.Clone().(*requests.DNSRequest))
+		e.nameSrc.newName(req)
 	}
 }
 
@@ -146,7 +311,25 @@ func (e *Enumeration) manageDataSrcRequests() {
 	}
 
 	finished := make(chan string, len(e.srcs)*2)
-	requestsMap := make(map[string][]interface{})
+	// The requests waiting for each data source are spilled to disk beyond the memory limit
+	dir := filepath.Join(config.OutputDirectory(e.Config.Dir), "queues")
+	requestsMap := make(map[string]*diskqueue.Queue)
+	for name := range nameToSrc {
+		q, err := diskqueue.New(dir, e.Config.QueueMemoryLimit)
+		if err != nil {
+			e.Config.Log.Printf("Failed to create the request queue for %s: %v", name, err)
+			q, _ = diskqueue.New("", 0)
+		}
+		requestsMap[name] = q
+	}
+	defer func() {
+		for name, q := range requestsMap {
+			if err := q.Err(); err != nil {
+				e.Config.Log.Printf("The request queue for %s lost requests: %v", name, err)
+			}
+			_ = q.Close()
+		}
+	}()
 loop:
 	for {
 		select {
@@ -161,24 +344,26 @@ loop:
 			}
 
 			for name := range nameToSrc {
-				if src := nameToSrc[name]; src != nil && src.HandlesReq(element) {
-					if len(requestsMap[name]) == 0 && !pending[name] {
+				// The guessing sources do not generate names for the root domains out of time
+				if src := nameToSrc[name]; src != nil && src.HandlesReq(element) && !e.domains.skipRequest(src, element) {
+					e.progress.sourceRequest()
+					if requestsMap[name].Len() == 0 && !pending[name] {
 						go e.fireRequest(src, element, finished)
 						pending[name] = true
-					} else {
-						requestsMap[name] = append(requestsMap[name], element)
+					} else if err := requestsMap[name].Append(element); err != nil {
+						e.Config.Log.Printf("Failed to queue the request for %s: %v", name, err)
 					}
 				}
 			}
 		case name := <-finished:
-			if len(requestsMap[name]) == 0 {
+			element, ok := requestsMap[name].Next()
+			if !ok {
 				pending[name] = false
 				e.setRequestsPending(pending)
 				continue loop
 			}
 
-			go e.fireRequest(nameToSrc[name], requestsMap[name][0], finished)
-			requestsMap[name] = requestsMap[name][1:]
+			go e.fireRequest(nameToSrc[name], element, finished)
 		}
 	}
 	e.requests.Process(func(e interface{}) {})
@@ -207,12 +392,16 @@ func (e *Enumeration) setRequestsPending(p map[string]bool) {
 }
 
 func (e *Enumeration) fireRequest(srv service.Service, req interface{}, finished chan string) {
+	// The requests wait in the queues of the data sources while the enumeration is paused
+	_ = e.pause.wait(e.ctx)
+
 	select {
 	case <-e.done:
 	case <-e.ctx.Done():
 	case <-srv.Done():
 	case srv.Input() <- req:
 	}
+	e.progress.sourceRequestDone()
 	finished <- srv.String()
 }
 
@@ -233,6 +422,11 @@ func (e *Enumeration) makeOutputSink() pipeline.SinkFunc {
 }
 
 func (e *Enumeration) submitKnownNames() {
+	// The names of previous enumerations are skipped instead of being enumerated again
+	if e.Config.NewOnly {
+		return
+	}
+
 	srcTags := make(map[string]string)
 	for _, src := range e.Sys.DataSources() {
 		srcTags[src.String()] = src.Description()
@@ -246,7 +440,8 @@ func (e *Enumeration) submitKnownNames() {
 func (e *Enumeration) readNamesFromDatabase(db *netmap.Graph, stags map[string]string) {
 	domains := e.Config.Domains()
 
-	for _, event := range db.EventsInScope(e.ctx, domains...) {
+	events := db.EventsInScope(e.ctx, domains...)
+	for _, event := range systems.WorkspaceEvents(e.ctx, db, e.Config.Workspace, events) {
 		for _, name := range db.EventFQDNs(e.ctx, event) {
 			select {
 			case <-e.done:
@@ -272,20 +467,55 @@ func (e *Enumeration) readNamesFromDatabase(db *netmap.Graph, stags map[string]s
 	}
 }
 
-func (e *Enumeration) submitProvidedNames() {
-	for _, name := range e.Config.ProvidedNames {
-		select {
-		case <-e.done:
-			return
-		default:
+// knownNames returns the names discovered by the previous enumerations in the workspace, excluding the root domain names.
+// At most maxKnownNames are kept in memory, and the names beyond the limit are enumerated again.
+func (e *Enumeration) knownNames() *stringset.Set {
+	known := stringset.New()
+	domains := e.Config.Domains()
+
+	for _, db := range e.Sys.GraphDatabases() {
+		events := db.EventsInScope(e.ctx, domains...)
+
+		for _, event := range systems.WorkspaceEvents(e.ctx, db, e.Config.Workspace, events) {
+			for _, name := range db.EventFQDNs(e.ctx, event) {
+				if known.Len() >= maxKnownNames {
+					e.Config.Log.Printf("The new-only mode skips the first %d names of the previous enumerations", maxKnownNames)
+					return known
+				}
+				if domain := e.Config.WhichDomain(name); domain != "" && domain != name {
+					known.Insert(name)
+				}
+			}
 		}
-		if domain := e.Config.WhichDomain(name); domain != "" {
-			e.nameSrc.newName(&requests.DNSRequest{
-				Name:   name,
-				Domain: domain,
-				Tag:    requests.EXTERNAL,
-				Source: "User Input",
-			})
+	}
+	return known
+}
+
+func (e *Enumeration) submitProvidedNames() {
+	for _, set := range []struct {
+		names  []string
+		tag    string
+		source string
+	}{
+		{e.Config.ProvidedNames, requests.EXTERNAL, "User Input"},
+		// The imported names are resolved and validated like those of the data sources, so once
+		// resolved, they seed the recursive brute forcing and the alterations
+		{e.Config.ImportedNames, requests.IMPORT, "Import"},
+	} {
+		for _, name := range set.names {
+			select {
+			case <-e.done:
+				return
+			default:
+			}
+			if domain := e.Config.WhichDomain(name); domain != "" {
+				e.nameSrc.newName(&requests.DNSRequest{
+					Name:   name,
+					Domain: domain,
+					Tag:    set.tag,
+					Source: set.source,
+				})
+			}
 		}
 	}
 }
@@ -0,0 +1,101 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/google/uuid"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/cloud"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+func TestKnownNames(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	records := []struct {
+		event, workspace, name string
+	}{
+		{event: "first", name: "www.owasp.org"},
+		{event: "second", name: "api.owasp.org"},
+		{event: "other", workspace: "other", name: "dev.owasp.org"},
+		{event: "example", name: "www.example.com"},
+	}
+	events := make(map[string]string)
+	for _, rec := range records {
+		if _, found := events[rec.event]; !found {
+			events[rec.event] = uuid.New().String()
+		}
+
+		id := events[rec.event]
+		if _, err := g.UpsertFQDN(ctx, rec.name, "DNS", id); err != nil {
+			t.Fatalf("failed to insert %s: %v", rec.name, err)
+		}
+		if err := systems.SetEventWorkspace(ctx, g, id, rec.workspace); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	cfg := config.NewConfig()
+	cfg.AddDomains("owasp.org", "example.com")
+	e := &Enumeration{
+		Config: cfg,
+		Sys:    &systems.SimpleSystem{Cfg: cfg, ASNCache: requests.NewASNCache(), Graph: g},
+		ctx:    ctx,
+	}
+
+	known := e.knownNames()
+	defer known.Close()
+	// The root domain names of the events are still enumerated
+	for _, name := range []string{"www.owasp.org", "api.owasp.org", "www.example.com"} {
+		if !known.Has(name) {
+			t.Errorf("knownNames() did not return %s", name)
+		}
+	}
+	for _, name := range []string{"owasp.org", "example.com", "dev.owasp.org"} {
+		if known.Has(name) {
+			t.Errorf("knownNames() returned %s", name)
+		}
+	}
+	if known.Len() != 3 {
+		t.Errorf("knownNames() returned %v", known.Slice())
+	}
+}
+
+func TestLoadCloudRanges(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Dir = t.TempDir()
+
+	cache, _ := json.Marshal(map[string]interface{}{
+		"updated":   time.Now(),
+		"providers": map[string][]*cloud.Range{cloud.Cloudflare: {{CIDR: "104.16.0.0/13", Provider: cloud.Cloudflare}}},
+	})
+	if err := os.WriteFile(filepath.Join(cfg.Dir, cloud.CacheFile), cache, 0600); err != nil {
+		t.Fatalf("failed to write the cache: %v", err)
+	}
+
+	e := &Enumeration{Config: cfg, ctx: context.Background()}
+	// The current cache is used without downloading the ranges
+	e.loadCloudRanges()
+	if e.cloud == nil || e.cloud.Lookup("104.16.1.1") == nil {
+		t.Errorf("loadCloudRanges() did not provide the cached ranges")
+	}
+
+	cfg.CloudRanges = false
+	e = &Enumeration{Config: cfg, ctx: context.Background()}
+	if e.loadCloudRanges(); e.cloud != nil {
+		t.Errorf("loadCloudRanges() provided the ranges after they were disabled")
+	}
+}
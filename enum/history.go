@@ -0,0 +1,70 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/resolve"
+)
+
+// insertDNSHistory stores the historical DNS records provided by a data source. The records are
+// linked to the FQDN node by the history edge predicates and kept out of the event, so they are
+// not mistaken for the records currently observed by the enumeration.
+func (e *Enumeration) insertDNSHistory(ctx context.Context, req *requests.DNSHistoryRequest) error {
+	name := strings.ToLower(resolve.RemoveLastDot(req.Name))
+	if name == "" || e.Config.Blacklisted(name) || e.Config.WhichDomain(name) == "" {
+		return nil
+	}
+
+	fnode, err := e.graph.UpsertNode(ctx, name, netmap.TypeFQDN)
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the FQDN %s: %v", e.graph, name, err)
+	}
+
+	for _, r := range req.Records {
+		pred := requests.HistoryEdgePredicate(r.Type)
+		data := strings.ToLower(resolve.RemoveLastDot(strings.TrimSpace(r.Data)))
+		if pred == "" || data == "" {
+			continue
+		}
+
+		ntype := netmap.TypeFQDN
+		switch uint16(r.Type) {
+		case dns.TypeA, dns.TypeAAAA:
+			if net.ParseIP(data) == nil {
+				continue
+			}
+			ntype = netmap.TypeAddr
+		case dns.TypeCNAME, dns.TypeNS, dns.TypeMX, dns.TypePTR:
+		default:
+			continue
+		}
+
+		tnode, err := e.graph.UpsertNode(ctx, data, ntype)
+		if err != nil {
+			return fmt.Errorf("%s failed to insert the historical record data %s: %v", e.graph, data, err)
+		}
+		if err := e.graph.UpsertEdge(ctx, &netmap.Edge{
+			Predicate: pred,
+			From:      fnode,
+			To:        tnode,
+		}); err != nil {
+			return fmt.Errorf("%s failed to link the historical record %s: %v", e.graph, data, err)
+		}
+
+		r.Data = data
+		if err := e.graph.UpsertProperty(ctx, fnode, requests.DNSHistoryPredicate, r.String()); err != nil {
+			return fmt.Errorf("%s failed to insert the historical record of %s: %v", e.graph, name, err)
+		}
+	}
+	return nil
+}
@@ -8,12 +8,14 @@ import (
 	"context"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/caffix/pipeline"
 	"github.com/caffix/queue"
 	"github.com/caffix/service"
+	"github.com/caffix/stringset"
 	amassnet "github.com/owasp-amass/amass/v3/net"
 	"github.com/owasp-amass/amass/v3/net/dns"
 	"github.com/owasp-amass/amass/v3/requests"
@@ -22,6 +24,9 @@ import (
 
 const waitForDuration = 10 * time.Second
 
+// The number of priorities within each level of the queue package, which order the names by depth
+const depthPriorities = 16
+
 // enumSource handles the filtering and release of new Data in the enumeration.
 type enumSource struct {
 	pipeline  *pipeline.Pipeline
@@ -30,6 +35,9 @@ type enumSource struct {
 	dups      queue.Queue
 	sweeps    queue.Queue
 	filter    *bf.StableBloomFilter
+	budget    *budget
+	domains   *domainBudget
+	known     *stringset.Set
 	subre     *regexp.Regexp
 	done      chan struct{}
 	doneOnce  sync.Once
@@ -40,9 +48,10 @@ type enumSource struct {
 	count     uint32
 }
 
-// newEnumSource returns an initialized input source for the enumeration pipeline.
-func newEnumSource(p *pipeline.Pipeline, e *Enumeration) *enumSource {
-	size := e.Sys.TrustedResolvers().Len() * e.Config.TrustedQPS
+// newEnumSource returns an initialized input source for the enumeration pipeline. The known
+// names are skipped, and can be nil when all the names are enumerated.
+func newEnumSource(p *pipeline.Pipeline, e *Enumeration, known *stringset.Set) *enumSource {
+	size := resolutionWorkers(e, e.Sys.TrustedResolvers().Len()*e.Config.TrustedQPS)
 
 	r := &enumSource{
 		pipeline: p,
@@ -50,13 +59,16 @@ func newEnumSource(p *pipeline.Pipeline, e *Enumeration) *enumSource {
 		queue:    queue.NewQueue(),
 		dups:     queue.NewQueue(),
 		sweeps:   queue.NewQueue(),
-		filter:   bf.NewDefaultStableBloomFilter(1000000, 0.01),
+		filter:   bf.NewDefaultStableBloomFilter(e.Config.FilterSettings()),
+		known:    known,
 		subre:    dns.AnySubdomainRegex(),
 		done:     make(chan struct{}),
 		release:  make(chan struct{}, size),
 		inputsig: make(chan uint32, size*2),
 		max:      size,
 	}
+	r.budget = newBudget(r)
+	r.domains = e.domains
 	// Monitor the enumeration for completion or termination
 	go func() {
 		select {
@@ -84,6 +96,9 @@ func (r *enumSource) Stop() {
 	r.dups.Process(func(e interface{}) {})
 	r.sweeps.Process(func(e interface{}) {})
 	r.filter.Reset()
+	if r.known != nil {
+		r.known.Close()
+	}
 }
 
 func (r *enumSource) markDone() {
@@ -100,25 +115,66 @@ func (r *enumSource) newName(req *requests.DNSRequest) {
 	}
 
 	if req.Name == "" || !req.Valid() {
-		r.releaseOutput(1)
+		r.reject(req)
 		return
 	}
 	// Clean up the newly discovered name and domain
 	requests.SanitizeDNSRequest(req)
 	// Check that the name is valid
 	if r.subre.FindString(req.Name) != req.Name {
-		r.releaseOutput(1)
+		r.reject(req)
 		return
 	}
 	if r.enum.Config.Blacklisted(req.Name) {
-		r.releaseOutput(1)
+		r.reject(req)
+		return
+	}
+	// Skip the names discovered by the previous enumerations in the new-only mode
+	if r.known != nil && r.known.Has(req.Name) {
+		r.reject(req)
+		return
+	}
+	// Drop the guesses for the root domains that ran out of time
+	if r.domains.drop(req) {
+		r.reject(req)
 		return
 	}
 	if !r.accept(req.Name, req.Tag, req.Source, true) {
-		r.releaseOutput(1)
+		r.reject(req)
 		return
 	}
-	r.queue.Append(req)
+	r.queue.AppendPriority(req, namePriority(req))
+	r.enum.progress.acceptedName()
+}
+
+// reject releases the output slot of the name and recycles the request, which the
+// data sources hand over to the input source.
+func (r *enumSource) reject(req *requests.DNSRequest) {
+	requests.ReleaseDNSRequest(req)
+	r.releaseOutput(1)
+}
+
+// namePriority returns the queue priority of the name, so the names from trusted sources come before
+// those of the other data sources, which come before the brute forcing and alteration guesses.
+// Within each level, the names closer to the root domain are released before the deeper names.
+func namePriority(req *requests.DNSRequest) int {
+	level := queue.PriorityHigh
+	switch req.Tag {
+	case requests.BRUTE, requests.ALT, requests.GUESS:
+		level = queue.PriorityLow
+	default:
+		if requests.TrustedTag(req.Tag) {
+			level = queue.PriorityCritical
+		}
+	}
+
+	depth := strings.Count(req.Name, ".") - strings.Count(req.Domain, ".")
+	if depth < 0 {
+		depth = 0
+	} else if depth >= depthPriorities {
+		depth = depthPriorities - 1
+	}
+	return level*depthPriorities + depthPriorities - 1 - depth
 }
 
 func (r *enumSource) newAddr(req *requests.AddrRequest) {
@@ -128,11 +184,14 @@ func (r *enumSource) newAddr(req *requests.AddrRequest) {
 	default:
 	}
 
-	if !req.Valid() || !req.InScope || !r.accept(req.Address, req.Tag, req.Source, false) {
+	if !req.Valid() || !req.InScope || r.enum.Config.IsAddressExcluded(req.Address) ||
+		!r.accept(req.Address, req.Tag, req.Source, false) {
 		return
 	}
 
-	r.queue.Append(req)
+	// The addresses share the level of the names provided by the data sources
+	r.queue.AppendPriority(req, queue.PriorityHigh*depthPriorities+depthPriorities-1)
+	r.enum.progress.acceptedName()
 	// Does the address fall into a reserved address range?
 	if reserved, _ := amassnet.IsReservedAddress(req.Address); !reserved {
 		// Queue the request for later use in reverse DNS sweeps
@@ -146,11 +205,7 @@ func (r *enumSource) accept(s, tag, source string, name bool) bool {
 	// from a trusted source
 	if !trusted && r.filter.Test([]byte(s+strconv.FormatBool(true))) {
 		if name {
-			r.dups.Append(&requests.DNSRequest{
-				Name:   s,
-				Tag:    tag,
-				Source: source,
-			})
+			r.dups.Append(requests.NewDNSRequest(s, "", tag, source))
 		}
 		return false
 	}
@@ -158,11 +213,7 @@ func (r *enumSource) accept(s, tag, source string, name bool) bool {
 	// reconsidered when presented from a trusted data source
 	if r.filter.Test([]byte(s + strconv.FormatBool(trusted))) {
 		if name {
-			r.dups.Append(&requests.DNSRequest{
-				Name:   s,
-				Tag:    tag,
-				Source: source,
-			})
+			r.dups.Append(requests.NewDNSRequest(s, "", tag, source))
 		}
 		return false
 	}
@@ -173,6 +224,11 @@ func (r *enumSource) accept(s, tag, source string, name bool) bool {
 
 // Next implements the pipeline InputSource interface.
 func (r *enumSource) Next(ctx context.Context) bool {
+	// No new names are released while the enumeration is paused
+	if !r.enum.pause.wait(ctx) {
+		r.markDone()
+		return false
+	}
 	// Low if below 75%
 	if p := (float32(r.queue.Len()) / float32(r.max)) * 100; p < 75 {
 		r.fillQueue()
@@ -189,6 +245,11 @@ func (r *enumSource) Next(ctx context.Context) bool {
 			r.markDone()
 			return false
 		case <-t.C:
+			// The enumeration is not finished while its work is held by the pause
+			if r.enum.Paused() {
+				t.Reset(waitForDuration)
+				continue
+			}
 			if !r.enum.requestsPending() && r.pipeline.DataItemCount() <= 0 {
 				r.markDone()
 				return false
@@ -203,14 +264,21 @@ func (r *enumSource) Next(ctx context.Context) bool {
 
 // Data implements the pipeline InputSource interface.
 func (r *enumSource) Data() pipeline.Data {
-	var data pipeline.Data
+	for {
+		element, ok := r.queue.Next()
+		if !ok {
+			return nil
+		}
+		// The guesses already queued are also dropped once the root domain runs out of time
+		if req, isName := element.(*requests.DNSRequest); isName && r.domains.drop(req) {
+			requests.ReleaseDNSRequest(req)
+			continue
+		}
 
-	if element, ok := r.queue.Next(); ok {
-		data = element.(pipeline.Data)
 		// Signal that new input was added to the pipeline
 		r.inputsig <- r.incrementCount()
+		return element.(pipeline.Data)
 	}
-	return data
 }
 
 func (r *enumSource) getCount() uint32 {
@@ -239,6 +307,10 @@ func (r *enumSource) Error() error {
 }
 
 func (r *enumSource) fillQueue() {
+	// The data sources remain blocked on their output while the budget is exceeded
+	if r.budget.exceeded() {
+		return
+	}
 	if unfilled := r.max - r.queue.Len(); unfilled > 0 {
 		if fill := unfilled - len(r.release); fill > 0 {
 			r.releaseOutput(fill)
@@ -275,9 +347,22 @@ func (r *enumSource) monitorDataSrcOutput(srv service.Service) {
 
 			switch req := in.(type) {
 			case *requests.DNSRequest:
+				if req.Tag == requests.BRUTE {
+					r.enum.progress.providedGuess()
+				}
 				r.newName(req)
 			case *requests.AddrRequest:
 				r.newAddr(req)
+			case *requests.DNSHistoryRequest:
+				if err := r.enum.insertDNSHistory(r.enum.ctx, req); err != nil {
+					r.enum.Config.Log.Print(err.Error())
+				}
+				r.releaseOutput(1)
+			case *requests.ServicesRequest:
+				if err := r.enum.insertServices(r.enum.ctx, req); err != nil {
+					r.enum.Config.Log.Print(err.Error())
+				}
+				r.releaseOutput(1)
 			}
 		}
 	}
@@ -301,6 +386,7 @@ func (r *enumSource) processDupNames() {
 	var pending []*altsource
 	each := func(element interface{}) {
 		req := element.(*requests.DNSRequest)
+		defer requests.ReleaseDNSRequest(req)
 
 		if r.addSourceToEntry(uuid, req.Name, req.Source) {
 			return
@@ -0,0 +1,103 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/caffix/queue"
+	"github.com/caffix/stringset"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/dns"
+	"github.com/owasp-amass/amass/v3/requests"
+	bf "github.com/tylertreat/BoomFilters"
+)
+
+func TestNamePriority(t *testing.T) {
+	req := func(name, tag string) *requests.DNSRequest {
+		return &requests.DNSRequest{Name: name, Domain: "owasp.org", Tag: tag}
+	}
+
+	tests := []struct {
+		name   string
+		first  *requests.DNSRequest
+		second *requests.DNSRequest
+	}{
+		{name: "trusted before untrusted", first: req("a.b.c.owasp.org", requests.CERT), second: req("www.owasp.org", requests.API)},
+		{name: "untrusted before guessed", first: req("a.b.c.owasp.org", requests.API), second: req("www.owasp.org", requests.BRUTE)},
+		{name: "shallower trusted name first", first: req("www.owasp.org", requests.DNS), second: req("dev.www.owasp.org", requests.DNS)},
+		{name: "shallower untrusted name first", first: req("www.owasp.org", requests.API), second: req("dev.www.owasp.org", requests.SCRAPE)},
+		{name: "shallower guessed name first", first: req("www.owasp.org", requests.ALT), second: req("dev.www.owasp.org", requests.BRUTE)},
+		{name: "root domain first", first: req("owasp.org", requests.API), second: req("www.owasp.org", requests.API)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p1, p2 := namePriority(tt.first), namePriority(tt.second); p1 <= p2 {
+				t.Errorf("%s has the priority %d, expected it above %d for %s", tt.first.Name, p1, p2, tt.second.Name)
+			}
+		})
+	}
+
+	deep := func(depth int) *requests.DNSRequest {
+		return req(strings.Repeat("a.", depth)+"owasp.org", requests.API)
+	}
+	// The names at or beyond the deepest level share the lowest priority of their level
+	if p := namePriority(deep(depthPriorities - 1)); p != namePriority(deep(depthPriorities)) || p != namePriority(deep(40)) {
+		t.Errorf("the depths at or beyond %d were not clamped", depthPriorities-1)
+	}
+	if namePriority(deep(depthPriorities-2)) <= namePriority(deep(depthPriorities-1)) {
+		t.Errorf("the depth %d did not come before the depth %d", depthPriorities-2, depthPriorities-1)
+	}
+	if namePriority(deep(40)) <= namePriority(req("www.owasp.org", requests.BRUTE)) {
+		t.Error("the clamped untrusted name did not come before the guessed names")
+	}
+	if p := namePriority(&requests.DNSRequest{Name: "owasp.org", Domain: "www.owasp.org", Tag: requests.API}); p != namePriority(req("owasp.org", requests.API)) {
+		t.Error("the name shallower than the root domain did not have the root domain priority")
+	}
+}
+
+func TestNewNameSkipsKnown(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	known := stringset.New("www.owasp.org", "api.owasp.org")
+	r := &enumSource{
+		enum:    &Enumeration{Config: cfg, progress: new(progress)},
+		queue:   queue.NewQueue(),
+		dups:    queue.NewQueue(),
+		sweeps:  queue.NewQueue(),
+		filter:  bf.NewDefaultStableBloomFilter(cfg.FilterSettings()),
+		known:   known,
+		subre:   dns.AnySubdomainRegex(),
+		done:    make(chan struct{}),
+		release: make(chan struct{}, 10),
+	}
+	defer r.Stop()
+
+	tests := []struct {
+		name     string
+		accepted bool
+	}{
+		{name: "www.owasp.org", accepted: false},
+		{name: "API.owasp.org", accepted: false},
+		{name: "dev.owasp.org", accepted: true},
+		{name: "owasp.org", accepted: true},
+	}
+
+	for _, tt := range tests {
+		before := r.queue.Len()
+
+		r.newName(&requests.DNSRequest{Name: tt.name, Domain: "owasp.org", Tag: requests.API, Source: "Test"})
+		if got := r.queue.Len() > before; got != tt.accepted {
+			t.Errorf("newName(%s) accepted the name: %t, expected %t", tt.name, got, tt.accepted)
+		}
+	}
+	// Only the new names are resolved and counted by the progress
+	if r.enum.progress.names != 2 {
+		t.Errorf("the progress counted %d accepted names, expected 2", r.enum.progress.names)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"sync"
+
+	"github.com/caffix/pipeline"
+)
+
+// pauseGate holds the active work of the enumeration while it is paused. The names, requests and
+// queries waiting at the gate keep their state, so the enumeration continues where it left off.
+type pauseGate struct {
+	sync.Mutex
+	paused bool
+	resume chan struct{}
+}
+
+func newPauseGate() *pauseGate {
+	return &pauseGate{resume: make(chan struct{})}
+}
+
+// set pauses or resumes the work, and returns false when the gate was already in that state.
+func (g *pauseGate) set(pause bool) bool {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.paused == pause {
+		return false
+	}
+
+	g.paused = pause
+	if !pause {
+		close(g.resume)
+		g.resume = make(chan struct{})
+	}
+	return true
+}
+
+func (g *pauseGate) isPaused() bool {
+	g.Lock()
+	defer g.Unlock()
+
+	return g.paused
+}
+
+// wait blocks while the gate is paused, and returns false when the context ends first.
+func (g *pauseGate) wait(ctx context.Context) bool {
+	for {
+		g.Lock()
+		paused, resume := g.paused, g.resume
+		g.Unlock()
+
+		if !paused {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-resume:
+		}
+	}
+}
+
+// Pause holds the DNS queries, the data source requests, the release of new names and the active
+// techniques of the enumeration until Resume is called. The work already started is allowed to
+// finish, and the enumeration timeout continues while paused.
+func (e *Enumeration) Pause() {
+	if e.pause.set(true) {
+		e.Config.Log.Print("The enumeration was paused")
+	}
+}
+
+// Resume continues the enumeration after a call to Pause.
+func (e *Enumeration) Resume() {
+	if e.pause.set(false) {
+		e.Config.Log.Print("The enumeration was resumed")
+	}
+}
+
+// Paused returns true while the enumeration is paused.
+func (e *Enumeration) Paused() bool {
+	return e.pause.isPaused()
+}
+
+// pausable holds the data at the gate before the task processes it.
+func (e *Enumeration) pausable(task pipeline.Task) pipeline.Task {
+	return pipeline.TaskFunc(func(ctx context.Context, data pipeline.Data, tp pipeline.TaskParams) (pipeline.Data, error) {
+		if !e.pause.wait(ctx) {
+			return nil, nil
+		}
+		return task.Process(ctx, data, tp)
+	})
+}
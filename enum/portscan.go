@@ -0,0 +1,200 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/caffix/pipeline"
+	"github.com/caffix/stringset"
+	"github.com/cayleygraph/quad"
+	"github.com/miekg/dns"
+	amassnet "github.com/owasp-amass/amass/v3/net"
+	"github.com/owasp-amass/amass/v3/net/cloud"
+	"github.com/owasp-amass/amass/v3/net/http"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+const (
+	maxPortScanTasks int = 10
+	maxPortScanDials int = 50
+	portScanTimeout      = 2 * time.Second
+	portScanSource       = "Port Scan"
+	portCertSource       = "Active Cert"
+)
+
+// portScanTask performs TCP connect scans of the addresses that in-scope names resolve to.
+type portScanTask struct {
+	enum    *Enumeration
+	ports   []int
+	scanned *stringset.Set
+}
+
+func newPortScanTask(e *Enumeration) *portScanTask {
+	p := &portScanTask{
+		enum:    e,
+		scanned: stringset.New(),
+	}
+
+	seen := make(map[int]struct{})
+	// The ports used for certificate grabbing and crawling are always scanned
+	for _, list := range [][]int{amassnet.TopPorts(e.Config.ScanTopPorts), e.Config.ScanPorts, e.Config.Ports} {
+		for _, port := range list {
+			if _, found := seen[port]; !found {
+				seen[port] = struct{}{}
+				p.ports = append(p.ports, port)
+			}
+		}
+	}
+	return p
+}
+
+func (p *portScanTask) stop() {
+	p.scanned.Close()
+}
+
+// Process implements the pipeline Task interface.
+func (p *portScanTask) Process(ctx context.Context, data pipeline.Data, tp pipeline.TaskParams) (pipeline.Data, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil
+	default:
+	}
+
+	req, ok := data.(*requests.DNSRequest)
+	if !ok || req == nil || !p.enum.Config.IsDomainInScope(req.Name) {
+		return data, nil
+	}
+
+	// Names served by a content delivery network resolve to edge servers shared with other customers
+	cdn := cnameCDN(req)
+	if cdn != "" && !p.enum.Config.ScanCDNs {
+		p.enum.Config.Log.Printf("Skipping the port scans of %s, which is served by the %s CDN", req.Name, cdn)
+		return data, nil
+	}
+
+	for _, rec := range req.Records {
+		if t := uint16(rec.Type); t != dns.TypeA && t != dns.TypeAAAA {
+			continue
+		}
+
+		addr := strings.TrimSpace(rec.Data)
+		if reserved, _ := amassnet.IsReservedAddress(addr); reserved || p.scanned.Has(addr) {
+			continue
+		}
+		p.scanned.Insert(addr)
+
+		if cdn := p.enum.addrCDN(ctx, addr); cdn != "" && !p.enum.Config.ScanCDNs {
+			p.enum.Config.Log.Printf("Skipping the port scan of %s, which belongs to the %s CDN", addr, cdn)
+			continue
+		}
+
+		for _, port := range amassnet.ScanPorts(ctx, addr, p.ports, portScanTimeout, maxPortScanDials) {
+			var banner string
+			if p.enum.Config.ScanBanners && !p.httpPort(port) {
+				banner, _ = amassnet.GrabBanner(ctx, addr, port, portScanTimeout)
+			}
+			if err := p.enum.insertPort(ctx, addr, port, banner, portScanSource); err != nil {
+				p.enum.Config.Log.Print(err.Error())
+			}
+			// Any open port could be providing a TLS service
+			p.pullCertNames(ctx, addr, port)
+		}
+	}
+	return data, nil
+}
+
+// httpPort returns true when the port is one of the web ports already described by the HTTP probes.
+func (p *portScanTask) httpPort(port int) bool {
+	for _, web := range p.enum.Config.Ports {
+		if port == web {
+			return true
+		}
+	}
+	return false
+}
+
+// insertPort stores the open TCP port of the address, as discovered by the source.
+func (e *Enumeration) insertPort(ctx context.Context, addr string, port int, banner, source string) error {
+	id := net.JoinHostPort(addr, strconv.Itoa(port))
+
+	node, err := e.graph.UpsertNode(ctx, id, requests.TypePort)
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the port %s: %v", e.graph, id, err)
+	}
+	if err := e.graph.AddNodeToEvent(ctx, node, source, e.Config.UUID.String()); err != nil {
+		return fmt.Errorf("%s failed to add the port %s to the event: %v", e.graph, id, err)
+	}
+	props := map[string]string{"number": strconv.Itoa(port), "protocol": "tcp"}
+	if banner != "" {
+		props[requests.BannerPredicate] = banner
+	}
+	for pred, val := range props {
+		if err := e.graph.UpsertProperty(ctx, node, pred, val); err != nil {
+			return fmt.Errorf("%s failed to insert the port %s property: %v", e.graph, pred, err)
+		}
+	}
+
+	return e.graph.UpsertEdge(ctx, &netmap.Edge{
+		Predicate: requests.PortPredicate,
+		From:      netmap.Node(addr),
+		To:        node,
+	})
+}
+
+func (p *portScanTask) pullCertNames(ctx context.Context, addr string, port int) {
+	for _, name := range http.PullCertificateNames(ctx, addr, []int{port}) {
+		if domain := strings.ToLower(p.enum.Config.WhichDomain(name)); domain != "" {
+			p.enum.nameSrc.newName(&requests.DNSRequest{
+				Name:   name,
+				Domain: domain,
+				Tag:    requests.CERT,
+				Source: portCertSource,
+			})
+		}
+	}
+}
+
+// cnameCDN returns the content delivery network identified by the CNAME records of the request.
+func cnameCDN(req *requests.DNSRequest) string {
+	for _, rec := range req.Records {
+		if uint16(rec.Type) != dns.TypeCNAME {
+			continue
+		}
+		if cdn := cloud.CDNByCNAME(rec.Data); cdn != "" {
+			return cdn
+		}
+	}
+	return ""
+}
+
+// addrCDN returns the content delivery network the address belongs to, using the published
+// ranges and the provider previously stored for the address, or an empty string otherwise.
+func (e *Enumeration) addrCDN(ctx context.Context, addr string) string {
+	if e.cloud != nil {
+		if rng := e.cloud.Lookup(addr); cloud.IsCDN(rng) {
+			return rng.Provider
+		}
+	}
+
+	props, err := e.graph.ReadProperties(ctx, netmap.Node(addr), cloud.Predicate)
+	if err != nil {
+		return ""
+	}
+	for _, p := range props {
+		if s, ok := quad.NativeOf(p.Value).(string); ok {
+			if rng, ok := cloud.ParseRange(s); ok && cloud.IsCDN(rng) {
+				return rng.Provider
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,171 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/caffix/netmap"
+	"github.com/caffix/pipeline"
+	"github.com/caffix/stringset"
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/http"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/resources"
+)
+
+const maxProbeTasks int = 50
+
+// probeTask requests the discovered names over HTTP and HTTPS and stores the details of the responses.
+type probeTask struct {
+	enum     *Enumeration
+	tech     *http.TechDetector
+	shots    *http.Screenshotter
+	dir      string
+	captured *stringset.Set
+}
+
+// newProbeTask returns a probeTask with the embedded and user provided technology fingerprints
+// loaded, and the headless browser started when screenshots have been requested.
+func newProbeTask(e *Enumeration) (*probeTask, error) {
+	p := &probeTask{enum: e, captured: stringset.New()}
+
+	if e.Config.Screenshots {
+		p.startScreenshots()
+	}
+	if !e.Config.TechDetection {
+		return p, nil
+	}
+
+	fps, err := resources.GetTechFingerprints()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range e.Config.TechFingerprints {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the technology fingerprints file %s: %v", path, err)
+		}
+
+		list, err := resources.ParseTechFingerprints(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		fps = append(fps, list...)
+	}
+
+	p.tech, err = http.NewTechDetector(fps)
+	if err != nil {
+		p.stop()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *probeTask) startScreenshots() {
+	p.dir = filepath.Join(config.OutputDirectory(p.enum.Config.Dir), "screenshots")
+	if err := os.MkdirAll(p.dir, 0755); err != nil {
+		p.enum.Config.Log.Printf("Failed to create the screenshots directory: %v", err)
+		return
+	}
+
+	shots, err := http.NewScreenshotter(p.enum.ctx, p.enum.Config.ScreenshotConcurrency)
+	if err != nil {
+		p.enum.Config.Log.Printf("Screenshots will not be captured: %v", err)
+		return
+	}
+	p.shots = shots
+}
+
+func (p *probeTask) stop() {
+	if p.shots != nil {
+		p.shots.Close()
+	}
+	p.captured.Close()
+}
+
+// Process implements the pipeline Task interface.
+func (p *probeTask) Process(ctx context.Context, data pipeline.Data, tp pipeline.TaskParams) (pipeline.Data, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil
+	default:
+	}
+
+	req, ok := data.(*requests.DNSRequest)
+	if !ok || req == nil || !p.enum.Config.IsDomainInScope(req.Name) || !hasAddressRecords(req) {
+		return data, nil
+	}
+
+	for _, scheme := range []string{"https", "http"} {
+		r, err := http.Probe(ctx, scheme+"://"+req.Name)
+		if err != nil {
+			continue
+		}
+
+		info := &requests.HTTPInfo{
+			URL:        r.URL,
+			StatusCode: r.Response.StatusCode,
+			Title:      r.Title,
+			Server:     r.Response.Header["Server"],
+			Redirects:  r.Redirects,
+			Length:     r.Response.Length,
+		}
+		if p.tech != nil {
+			for _, t := range p.tech.Detect(r.Response) {
+				info.Technologies = append(info.Technologies, t.String())
+			}
+		}
+		if p.enum.Config.WAFDetection {
+			info.WAF = http.DetectWAF(ctx, r)
+		}
+		if p.shots != nil {
+			info.Screenshot = p.screenshot(ctx, r.URL)
+		}
+		if err := p.enum.graph.UpsertProperty(ctx, netmap.Node(req.Name), requests.HTTPPredicate, info.String()); err != nil {
+			p.enum.Config.Log.Printf("%s failed to insert the HTTP probe results: %v", p.enum.graph, err)
+		}
+	}
+	return data, nil
+}
+
+// screenshot captures the page and returns the path of the image relative to the output directory.
+func (p *probeTask) screenshot(ctx context.Context, u string) string {
+	file := http.ScreenshotFilename(u)
+	rel := filepath.Join("screenshots", file)
+	// The HTTP and HTTPS probes are often redirected to the same page
+	if p.captured.Has(u) {
+		return rel
+	}
+
+	img, err := p.shots.Capture(ctx, u)
+	if err != nil {
+		p.enum.Config.Log.Print(err.Error())
+		return ""
+	}
+	if err := os.WriteFile(filepath.Join(p.dir, file), img, 0644); err != nil {
+		p.enum.Config.Log.Printf("Failed to write the screenshot of %s: %v", u, err)
+		return ""
+	}
+
+	p.captured.Insert(u)
+	return rel
+}
+
+func hasAddressRecords(req *requests.DNSRequest) bool {
+	for _, rec := range req.Records {
+		switch uint16(rec.Type) {
+		case dns.TypeA, dns.TypeAAAA, dns.TypeCNAME:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress is a snapshot of the work planned and completed by the enumeration. The planned work
+// grows as the data sources return names, so the estimates firm up as the enumeration advances.
+type Progress struct {
+	Elapsed time.Duration
+	// The requests for the data sources, and those already handed to them
+	SourceRequests     int
+	SourceRequestsDone int
+	// The names and addresses accepted by the enumeration, and those done being examined
+	Names     int
+	NamesDone int
+	// The brute forcing names planned from the wordlist, but not yet provided by the data source
+	GuessesRemaining int
+	Finished         bool
+}
+
+// Total returns the amount of work known to the enumeration.
+func (p *Progress) Total() int {
+	return p.SourceRequests + p.Names + p.GuessesRemaining
+}
+
+// Completed returns the amount of work finished by the enumeration.
+func (p *Progress) Completed() int {
+	return p.SourceRequestsDone + p.NamesDone
+}
+
+// Percent returns the share of the known work that has been completed.
+func (p *Progress) Percent() float64 {
+	if p.Finished {
+		return 100
+	}
+
+	total := p.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(p.Completed()) * 100 / float64(total)
+}
+
+// ETA returns the estimated time remaining, based on the rate of the work completed so far.
+// The second return value is false when nothing has been completed to base the estimate on.
+func (p *Progress) ETA() (time.Duration, bool) {
+	if p.Finished {
+		return 0, true
+	}
+
+	completed := p.Completed()
+	if completed == 0 {
+		return 0, false
+	}
+
+	remaining := p.Total() - completed
+	if remaining < 0 {
+		remaining = 0
+	}
+	return time.Duration(float64(p.Elapsed) * float64(remaining) / float64(completed)), true
+}
+
+// plannedSource is implemented by the data sources able to tell the enumeration how many
+// brute forcing names they will provide, before the names are generated.
+type plannedSource interface {
+	SetPlanner(fn func(num int))
+}
+
+// progress counts the work of the enumeration as it is planned and completed.
+type progress struct {
+	sync.Mutex
+	start    time.Time
+	end      time.Time
+	srcReqs  int
+	srcDone  int
+	names    int
+	planned  int
+	provided int
+	// Returns the number of names and addresses still being examined
+	active func() int
+}
+
+func (p *progress) begin(active func() int) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.start = time.Now()
+	p.active = active
+}
+
+func (p *progress) finish() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.end = time.Now()
+	p.active = nil
+}
+
+func (p *progress) sourceRequest() {
+	p.Lock()
+	p.srcReqs++
+	p.Unlock()
+}
+
+func (p *progress) sourceRequestDone() {
+	p.Lock()
+	p.srcDone++
+	p.Unlock()
+}
+
+func (p *progress) acceptedName() {
+	p.Lock()
+	p.names++
+	p.Unlock()
+}
+
+func (p *progress) plannedGuesses(num int) {
+	p.Lock()
+	p.planned += num
+	p.Unlock()
+}
+
+func (p *progress) providedGuess() {
+	p.Lock()
+	p.provided++
+	p.Unlock()
+}
+
+// Progress returns a snapshot of the work planned and completed by the enumeration.
+func (e *Enumeration) Progress() *Progress {
+	p := e.progress
+	p.Lock()
+	defer p.Unlock()
+
+	snap := &Progress{
+		SourceRequests:     p.srcReqs,
+		SourceRequestsDone: p.srcDone,
+		Names:              p.names,
+		NamesDone:          p.names,
+		Finished:           !p.end.IsZero(),
+	}
+	if p.start.IsZero() {
+		return snap
+	}
+
+	if snap.Finished {
+		snap.Elapsed = p.end.Sub(p.start)
+		return snap
+	}
+	snap.Elapsed = time.Since(p.start)
+
+	if p.planned > p.provided {
+		snap.GuessesRemaining = p.planned - p.provided
+	}
+	if p.active != nil {
+		if snap.NamesDone -= p.active(); snap.NamesDone < 0 {
+			snap.NamesDone = 0
+		}
+	}
+	return snap
+}
+
+// activeWork returns the number of names and addresses queued, moving through the pipeline
+// or waiting for the DNS responses.
+func (e *Enumeration) activeWork() int {
+	n := e.nameSrc.queue.Len() + e.nameSrc.pipeline.DataItemCount()
+
+	if e.dnsTask != nil {
+		n += e.dnsTask.pendingReqs()
+	}
+	if e.valTask != nil {
+		n += e.valTask.pendingReqs()
+	}
+	return n
+}
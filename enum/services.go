@@ -0,0 +1,55 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/caffix/netmap"
+	amassnet "github.com/owasp-amass/amass/v3/net"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+// insertServices stores the open ports and the software of an address provided by a data source.
+// The ports are linked to the address as the port scans do, and the CPE names are kept as
+// properties of the address node.
+func (e *Enumeration) insertServices(ctx context.Context, req *requests.ServicesRequest) error {
+	ip := net.ParseIP(strings.TrimSpace(req.Address))
+	if ip == nil {
+		return nil
+	}
+
+	addr := ip.String()
+	if reserved, _ := amassnet.IsReservedAddress(addr); reserved || e.Config.IsAddressExcluded(addr) {
+		return nil
+	}
+
+	node, err := e.graph.UpsertNode(ctx, addr, netmap.TypeAddr)
+	if err != nil {
+		return fmt.Errorf("%s failed to insert the address %s: %v", e.graph, addr, err)
+	}
+
+	for _, port := range req.Ports {
+		if port <= 0 || port > 65535 {
+			continue
+		}
+		if err := e.insertPort(ctx, addr, port, "", req.Source); err != nil {
+			return err
+		}
+	}
+
+	for _, cpe := range req.CPEs {
+		if cpe = strings.TrimSpace(cpe); cpe == "" {
+			continue
+		}
+		if err := e.graph.UpsertProperty(ctx, node, requests.CPEPredicate, cpe); err != nil {
+			return fmt.Errorf("%s failed to insert the CPE of %s: %v", e.graph, addr, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+// SourceStats describes the requests sent by a data source during the enumeration.
+type SourceStats struct {
+	Name string
+	// The requests sent to the service of the data source, and those that failed
+	Requests int
+	Errors   int
+}
+
+// requestCounter is implemented by the data sources counting the requests sent to their service.
+type requestCounter interface {
+	RequestStats() (int, int)
+}
+
+// markSourceStats records the counts of the data sources as the enumeration starts, since the
+// data sources are shared by the enumerations of the system.
+func (e *Enumeration) markSourceStats() {
+	e.plock.Lock()
+	defer e.plock.Unlock()
+
+	e.srcStats = make(map[string]*SourceStats, len(e.srcs))
+	for _, src := range e.srcs {
+		stats := &SourceStats{Name: src.String()}
+
+		if rc, ok := src.(requestCounter); ok {
+			stats.Requests, stats.Errors = rc.RequestStats()
+		}
+		e.srcStats[stats.Name] = stats
+	}
+}
+
+// SourceStats returns the requests sent by each selected data source since the enumeration started.
+func (e *Enumeration) SourceStats() []*SourceStats {
+	e.plock.Lock()
+	defer e.plock.Unlock()
+
+	stats := make([]*SourceStats, 0, len(e.srcs))
+	for _, src := range e.srcs {
+		s := &SourceStats{Name: src.String()}
+
+		if rc, ok := src.(requestCounter); ok {
+			s.Requests, s.Errors = rc.RequestStats()
+			if start, found := e.srcStats[s.Name]; found {
+				s.Requests -= start.Requests
+				s.Errors -= start.Errors
+			}
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
@@ -11,13 +11,17 @@ import (
 	"net"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	amassnet "github.com/owasp-amass/amass/v3/net"
+	"github.com/owasp-amass/amass/v3/net/cloud"
 	amassdns "github.com/owasp-amass/amass/v3/net/dns"
 	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/caffix/netmap"
 	"github.com/caffix/pipeline"
 	"github.com/caffix/queue"
+	"github.com/caffix/stringset"
 	"github.com/owasp-amass/resolve"
 	"github.com/miekg/dns"
 	bf "github.com/tylertreat/BoomFilters"
@@ -26,11 +30,14 @@ import (
 
 // dataManager is the stage that stores all data processed by the pipeline.
 type dataManager struct {
+	sync.Mutex
 	enum        *Enumeration
 	queue       queue.Queue
 	signalDone  chan struct{}
 	confirmDone chan struct{}
 	filter      *bf.StableBloomFilter
+	// The ranges of the addresses with ASN lookups that failed to answer
+	unknown *stringset.Set
 }
 
 // newDataManager returns a dataManager specific to the provided Enumeration.
@@ -40,7 +47,8 @@ func newDataManager(e *Enumeration) *dataManager {
 		queue:       queue.NewQueue(),
 		signalDone:  make(chan struct{}, 2),
 		confirmDone: make(chan struct{}, 2),
-		filter:      bf.NewDefaultStableBloomFilter(1000000, 0.01),
+		filter:      bf.NewDefaultStableBloomFilter(e.Config.FilterSettings()),
+		unknown:     stringset.New(),
 	}
 
 	go dm.processASNRequests()
@@ -68,6 +76,11 @@ func (dm *dataManager) Process(ctx context.Context, data pipeline.Data, tp pipel
 			return nil, nil
 		}
 
+		// Names resolving only into the excluded address ranges are not stored or examined further
+		if dm.excludedAddresses(v) {
+			return nil, nil
+		}
+
 		id = v.Name
 		if err := dm.dnsRequest(ctx, v, tp); err != nil {
 			dm.enum.Config.Log.Print(err.Error())
@@ -77,18 +90,49 @@ func (dm *dataManager) Process(ctx context.Context, data pipeline.Data, tp pipel
 			return nil, nil
 		}
 
+		if dm.enum.Config.IsAddressExcluded(v.Address) {
+			return nil, nil
+		}
+
 		id = v.Address
 		if err := dm.addrRequest(ctx, v, tp); err != nil {
 			dm.enum.Config.Log.Print(err.Error())
 		}
 	}
 
-	if id != "" && dm.filter.TestAndAdd([]byte(id)) {
+	if id != "" && dm.seen(id) {
 		return nil, nil
 	}
 	return data, nil
 }
 
+// seen guards the filter, since the graph writes can be performed by multiple workers.
+func (dm *dataManager) seen(id string) bool {
+	dm.Lock()
+	defer dm.Unlock()
+
+	return dm.filter.TestAndAdd([]byte(id))
+}
+
+// excludedAddresses returns true when all the addresses the name resolves to fall into the excluded ranges.
+func (dm *dataManager) excludedAddresses(req *requests.DNSRequest) bool {
+	if len(dm.enum.Config.ExcludedCIDRs) == 0 {
+		return false
+	}
+
+	var found bool
+	for _, r := range req.Records {
+		if t := uint16(r.Type); t != dns.TypeA && t != dns.TypeAAAA {
+			continue
+		}
+		if !dm.enum.Config.IsAddressExcluded(strings.TrimSpace(r.Data)) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
 func (dm *dataManager) dnsRequest(ctx context.Context, req *requests.DNSRequest, tp pipeline.TaskParams) error {
 	if dm.enum.Config.Blacklisted(req.Name) {
 		return nil
@@ -356,12 +400,13 @@ func (dm *dataManager) addrRequest(ctx context.Context, req *requests.AddrReques
 		}
 		return err
 	}
+	// Tag the address before the infrastructure data makes the name available for output
+	if err := dm.tagCloudProvider(ctx, req.Address, "", 0); err != nil {
+		dm.enum.Config.Log.Print(err.Error())
+	}
 	if r := dm.enum.Sys.Cache().AddrSearch(req.Address); r != nil {
-		var err error
-		if e := dm.enum.graph.UpsertInfrastructure(ctx, r.ASN,
-			r.Description, req.Address, r.Prefix, r.Source, uuid); e != nil {
-			err = e
-		}
+		err := dm.insertInfrastructure(ctx, r.ASN, r.Description, req.Address, r.Prefix, r.Source)
+		dm.checkRouted(ctx, r)
 		return err
 	}
 
@@ -393,9 +438,15 @@ func (dm *dataManager) nextInfraInfo() {
 
 	ctx := context.Background()
 	req := e.(*requests.AddrRequest)
-	uuid := dm.enum.Config.UUID.String()
 	if r := dm.enum.Sys.Cache().AddrSearch(req.Address); r != nil {
-		_ = dm.enum.graph.UpsertInfrastructure(ctx, r.ASN, r.Description, req.Address, r.Prefix, r.Source, uuid)
+		_ = dm.insertInfrastructure(ctx, r.ASN, r.Description, req.Address, r.Prefix, r.Source)
+		dm.checkRouted(ctx, r)
+		return
+	}
+
+	prefix := fakePrefix(req.Address)
+	if dm.unknown.Has(prefix) {
+		_ = dm.insertInfrastructure(ctx, 0, "Unknown", req.Address, prefix, "RIR")
 		return
 	}
 
@@ -410,25 +461,59 @@ loop:
 
 		time.Sleep(2 * time.Second)
 		if r := dm.enum.Sys.Cache().AddrSearch(req.Address); r != nil {
-			_ = dm.enum.graph.UpsertInfrastructure(ctx, r.ASN, r.Description, req.Address, r.Prefix, r.Source, uuid)
+			_ = dm.insertInfrastructure(ctx, r.ASN, r.Description, req.Address, r.Prefix, r.Source)
+			dm.checkRouted(ctx, r)
 			return
 		}
 	}
 
-	asn := 0
-	desc := "Unknown"
-	prefix := fakePrefix(req.Address)
-	_ = dm.enum.graph.UpsertInfrastructure(ctx, asn, desc, req.Address, prefix, "RIR", uuid)
-
-	first, cidr, _ := net.ParseCIDR(prefix)
-	dm.enum.Sys.Cache().Update(&requests.ASNRequest{
-		Address:     first.String(),
-		ASN:         asn,
-		Prefix:      cidr.String(),
-		Description: desc,
-		Tag:         requests.RIR,
-		Source:      "RIR",
-	})
+	// The lookup timed out or the enumeration is finished, so the routing of the address is unknown.
+	// The range is not added to the ASN cache, where it would appear as not routed
+	_ = dm.insertInfrastructure(ctx, 0, "Unknown", req.Address, prefix, "RIR")
+	dm.unknown.Insert(prefix)
+}
+
+// checkRouted records the dangling finding for the address when the ASN data shows the range
+// containing it as not routed. The lookups that failed to answer never produce the finding.
+func (dm *dataManager) checkRouted(ctx context.Context, r *requests.ASNRequest) {
+	if !dm.enum.Config.DanglingRecords || r.ASN != 0 || r.Description != requests.NotRoutedDescription {
+		return
+	}
+
+	f := &requests.Finding{
+		Type:        requests.FindingDangling,
+		Description: "Address is not announced by any autonomous system",
+		Evidence:    fmt.Sprintf("The range %s containing %s is not routed", r.Prefix, r.Address),
+	}
+	_ = dm.enum.graph.UpsertProperty(ctx, netmap.Node(r.Address), requests.FindingPredicate, f.String())
+}
+
+// insertInfrastructure stores the infrastructure information and the cloud provider of the address.
+func (dm *dataManager) insertInfrastructure(ctx context.Context, asn int, desc, addr, prefix, source string) error {
+	if err := dm.enum.graph.UpsertInfrastructure(ctx, asn, desc, addr, prefix, source, dm.enum.Config.UUID.String()); err != nil {
+		return fmt.Errorf("%s failed to insert infrastructure data: %v", dm.enum.graph, err)
+	}
+	return dm.tagCloudProvider(ctx, addr, prefix, asn)
+}
+
+// tagCloudProvider stores the provider, service and region of the published range containing the
+// address, or the provider identified by the ASN when the provider does not publish its ranges.
+func (dm *dataManager) tagCloudProvider(ctx context.Context, addr, prefix string, asn int) error {
+	var rng *cloud.Range
+	if dm.enum.cloud != nil {
+		rng = dm.enum.cloud.Lookup(addr)
+	}
+	if p := cloud.ProviderByASN(asn); rng == nil && p != "" {
+		rng = &cloud.Range{CIDR: prefix, Provider: p}
+	}
+	if rng == nil {
+		return nil
+	}
+
+	if err := dm.enum.graph.UpsertProperty(ctx, netmap.Node(addr), cloud.Predicate, rng.String()); err != nil {
+		return fmt.Errorf("%s failed to insert the cloud provider: %v", dm.enum.graph, err)
+	}
+	return nil
 }
 
 func fakePrefix(addr string) string {
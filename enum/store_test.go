@@ -0,0 +1,101 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/caffix/netmap"
+	"github.com/caffix/queue"
+	"github.com/caffix/stringset"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+func newTestDataManager(t *testing.T, ctx context.Context) *dataManager {
+	cfg := config.NewConfig()
+	cfg.DanglingRecords = true
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	t.Cleanup(func() { g.Close() })
+
+	e := &Enumeration{
+		Config:   cfg,
+		Sys:      &systems.SimpleSystem{Cfg: cfg, ASNCache: requests.NewASNCache()},
+		ctx:      ctx,
+		graph:    g,
+		requests: queue.NewQueue(),
+	}
+	return &dataManager{
+		enum:    e,
+		queue:   queue.NewQueue(),
+		unknown: stringset.New(),
+	}
+}
+
+func findings(t *testing.T, dm *dataManager, addr string) int {
+	props, err := dm.enum.graph.ReadProperties(context.Background(), netmap.Node(addr), requests.FindingPredicate)
+	if err != nil {
+		return 0
+	}
+	return len(props)
+}
+
+func TestNextInfraInfoNotRouted(t *testing.T) {
+	dm := newTestDataManager(t, context.Background())
+	dm.enum.Sys.Cache().Update(&requests.ASNRequest{
+		Address:     "1.0.1.0",
+		ASN:         0,
+		Prefix:      "1.0.1.0/24",
+		Description: requests.NotRoutedDescription,
+	})
+	dm.enum.Sys.Cache().Update(&requests.ASNRequest{
+		Address:     "8.8.8.0",
+		ASN:         15169,
+		Prefix:      "8.8.8.0/24",
+		Description: "GOOGLE",
+	})
+
+	tests := []struct {
+		addr     string
+		expected int
+	}{
+		{addr: "1.0.1.5", expected: 1},
+		{addr: "8.8.8.8", expected: 0},
+	}
+
+	for _, tt := range tests {
+		dm.queue.Append(&requests.AddrRequest{Address: tt.addr})
+		dm.nextInfraInfo()
+
+		if got := findings(t, dm, tt.addr); got != tt.expected {
+			t.Errorf("nextInfraInfo() recorded %d findings for %s, expected %d", got, tt.addr, tt.expected)
+		}
+	}
+}
+
+func TestNextInfraInfoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dm := newTestDataManager(t, ctx)
+	for _, addr := range []string{"203.0.113.7", "203.0.113.8"} {
+		dm.queue.Append(&requests.AddrRequest{Address: addr})
+		dm.nextInfraInfo()
+
+		if got := findings(t, dm, addr); got != 0 {
+			t.Errorf("nextInfraInfo() recorded %d findings for %s after the cancellation", got, addr)
+		}
+		// The unknown range must not be cached as the range of an ASN
+		if r := dm.enum.Sys.Cache().AddrSearch(addr); r != nil {
+			t.Errorf("the ASN cache returned %v for %s", r, addr)
+		}
+	}
+	if !dm.unknown.Has("203.0.113.0/24") {
+		t.Errorf("the range of the unanswered lookups was not retained")
+	}
+}
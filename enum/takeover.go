@@ -0,0 +1,149 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/caffix/netmap"
+	"github.com/caffix/pipeline"
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/amass/v3/net/http"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/resources"
+	"github.com/owasp-amass/resolve"
+)
+
+const maxTakeoverTasks int = 50
+
+// takeoverTask checks the CNAME targets of discovered names against the subdomain takeover fingerprints.
+type takeoverTask struct {
+	enum     *Enumeration
+	fps      []*resources.TakeoverFingerprint
+	resolver querier
+	fetch    func(context.Context, *http.Request) (*http.Response, error)
+}
+
+// newTakeoverTask returns a takeoverTask with the embedded and user provided fingerprints loaded.
+func newTakeoverTask(e *Enumeration) (*takeoverTask, error) {
+	fps, err := resources.GetTakeoverFingerprints()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range e.Config.TakeoverFingerprints {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the takeover fingerprints file %s: %v", path, err)
+		}
+
+		list, err := resources.ParseTakeoverFingerprints(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		fps = append(fps, list...)
+	}
+	return &takeoverTask{
+		enum:     e,
+		fps:      fps,
+		resolver: e.Sys.TrustedResolvers(),
+		fetch:    http.RequestWebPage,
+	}, nil
+}
+
+// Process implements the pipeline Task interface.
+func (t *takeoverTask) Process(ctx context.Context, data pipeline.Data, tp pipeline.TaskParams) (pipeline.Data, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil
+	default:
+	}
+
+	req, ok := data.(*requests.DNSRequest)
+	if !ok || req == nil || !t.enum.Config.IsDomainInScope(req.Name) {
+		return data, nil
+	}
+
+	// Each CNAME of the chain is checked, since any of the targets can be the vulnerable service
+	for _, rec := range req.Records {
+		if uint16(rec.Type) != dns.TypeCNAME {
+			continue
+		}
+
+		target := strings.ToLower(resolve.RemoveLastDot(rec.Data))
+		fp := t.matchFingerprint(target)
+		if fp == nil {
+			continue
+		}
+
+		if f := t.check(ctx, req.Name, target, fp); f != nil {
+			if err := t.enum.graph.UpsertProperty(ctx, netmap.Node(req.Name), requests.FindingPredicate, f.String()); err != nil {
+				t.enum.Config.Log.Printf("%s failed to insert the takeover finding: %v", t.enum.graph, err)
+			}
+			break
+		}
+	}
+	return data, nil
+}
+
+func (t *takeoverTask) matchFingerprint(target string) *resources.TakeoverFingerprint {
+	for _, fp := range t.fps {
+		for _, suffix := range fp.CNAME {
+			s := strings.ToLower(strings.Trim(suffix, "."))
+			// Entries without a dot are label fragments, such as 's3-website'
+			if target == s || strings.HasSuffix(target, "."+s) || (!strings.Contains(s, ".") && strings.Contains(target, s)) {
+				return fp
+			}
+		}
+	}
+	return nil
+}
+
+func (t *takeoverTask) check(ctx context.Context, name, target string, fp *resources.TakeoverFingerprint) *requests.Finding {
+	if fp.NXDomain {
+		if !nxdomain(ctx, t.resolver, target, dns.TypeA) {
+			return nil
+		}
+
+		return &requests.Finding{
+			Type:        requests.FindingTakeover,
+			Description: fmt.Sprintf("Possible %s subdomain takeover", fp.Service),
+			Evidence:    fmt.Sprintf("CNAME %s returns NXDOMAIN", target),
+		}
+	}
+
+	if len(fp.Fingerprint) == 0 {
+		return nil
+	}
+
+	for _, scheme := range []string{"https", "http"} {
+		resp, err := t.fetch(ctx, &http.Request{URL: scheme + "://" + name})
+		if err != nil || (fp.HTTPStatus != 0 && resp.StatusCode != fp.HTTPStatus) {
+			continue
+		}
+		// All the strings of the fingerprint must be found in the response
+		if matchesAll(resp.Body, fp.Fingerprint) {
+			return &requests.Finding{
+				Type:        requests.FindingTakeover,
+				Description: fmt.Sprintf("Possible %s subdomain takeover", fp.Service),
+				Evidence:    fmt.Sprintf("CNAME %s and the %s response contained '%s'", target, scheme, strings.Join(fp.Fingerprint, "', '")),
+			}
+		}
+	}
+	return nil
+}
+
+func matchesAll(body string, fingerprint []string) bool {
+	for _, s := range fingerprint {
+		if !strings.Contains(body, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,231 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/http"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/resources"
+)
+
+var testTakeoverFingerprints = []*resources.TakeoverFingerprint{
+	{
+		Service:     "Heroku",
+		CNAME:       []string{"herokuapp.com"},
+		Fingerprint: []string{"No such app", "herokucdn.com/error-pages/no-such-app.html"},
+	},
+	{
+		Service:     "Netlify",
+		CNAME:       []string{"netlify.app"},
+		Fingerprint: []string{"Not Found - Request ID:"},
+		HTTPStatus:  404,
+	},
+	{
+		Service:  "Azure",
+		CNAME:    []string{"cloudapp.net"},
+		NXDomain: true,
+	},
+	{
+		Service:     "AWS/S3",
+		CNAME:       []string{"s3-website"},
+		Fingerprint: []string{"NoSuchBucket"},
+	},
+}
+
+// testPages provides the HTTP responses of the hosts, and the other hosts fail to respond.
+type testPages map[string]*http.Response
+
+func (p testPages) fetch(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if resp, found := p[req.URL]; found {
+		return resp, nil
+	}
+	return nil, errors.New("connection refused")
+}
+
+func newTestTakeoverTask(t *testing.T, pages testPages) *takeoverTask {
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	t.Cleanup(func() { g.Close() })
+
+	return &takeoverTask{
+		enum: &Enumeration{Config: cfg, graph: g},
+		fps:  testTakeoverFingerprints,
+		resolver: &stubResolver{
+			answers: map[string]string{"live.cloudapp.net.": "192.0.2.10"},
+			errs:    map[string]error{"timeout.cloudapp.net.": errors.New("timeout")},
+		},
+		fetch: pages.fetch,
+	}
+}
+
+func TestTakeoverMatchFingerprint(t *testing.T) {
+	tt := newTestTakeoverTask(t, nil)
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{target: "owasp.herokuapp.com", expected: "Heroku"},
+		{target: "herokuapp.com", expected: "Heroku"},
+		{target: "owasp.herokuapp.com.evil.org", expected: ""},
+		{target: "notherokuapp.com", expected: ""},
+		{target: "owasp.s3-website-us-east-1.amazonaws.com", expected: "AWS/S3"},
+		{target: "www.owasp.org", expected: ""},
+	}
+
+	for _, test := range tests {
+		var got string
+		if fp := tt.matchFingerprint(test.target); fp != nil {
+			got = fp.Service
+		}
+		if got != test.expected {
+			t.Errorf("matchFingerprint(%q) = %q, expected %q", test.target, got, test.expected)
+		}
+	}
+}
+
+func TestTakeoverCheck(t *testing.T) {
+	heroku := testTakeoverFingerprints[0]
+	netlify := testTakeoverFingerprints[1]
+	azure := testTakeoverFingerprints[2]
+
+	tests := []struct {
+		name     string
+		host     string
+		target   string
+		fp       *resources.TakeoverFingerprint
+		pages    testPages
+		takeover bool
+	}{
+		{
+			name:   "body match",
+			host:   "app.owasp.org",
+			target: "owasp.herokuapp.com",
+			fp:     heroku,
+			pages: testPages{"https://app.owasp.org": {StatusCode: 404,
+				Body: `<title>No such app</title><iframe src="//www.herokucdn.com/error-pages/no-such-app.html">`}},
+			takeover: true,
+		},
+		{
+			name:     "body match over http",
+			host:     "app.owasp.org",
+			target:   "owasp.herokuapp.com",
+			fp:       heroku,
+			pages:    testPages{"http://app.owasp.org": {Body: `No such app herokucdn.com/error-pages/no-such-app.html`}},
+			takeover: true,
+		},
+		{
+			name:     "only part of the fingerprint",
+			host:     "app.owasp.org",
+			target:   "owasp.herokuapp.com",
+			fp:       heroku,
+			pages:    testPages{"https://app.owasp.org": {Body: "There is no such app in the catalog"}},
+			takeover: false,
+		},
+		{
+			name:     "status code matches",
+			host:     "docs.owasp.org",
+			target:   "owasp.netlify.app",
+			fp:       netlify,
+			pages:    testPages{"https://docs.owasp.org": {StatusCode: 404, Body: "Not Found - Request ID: 01H"}},
+			takeover: true,
+		},
+		{
+			name:     "status code does not match",
+			host:     "docs.owasp.org",
+			target:   "owasp.netlify.app",
+			fp:       netlify,
+			pages:    testPages{"https://docs.owasp.org": {StatusCode: 200, Body: "Not Found - Request ID: 01H"}},
+			takeover: false,
+		},
+		{
+			name:     "nxdomain target",
+			host:     "vm.owasp.org",
+			target:   "gone.cloudapp.net",
+			fp:       azure,
+			takeover: true,
+		},
+		{
+			name:     "nxdomain service with a resolving target",
+			host:     "vm.owasp.org",
+			target:   "live.cloudapp.net",
+			fp:       azure,
+			takeover: false,
+		},
+		{
+			name:     "nxdomain service without an answer",
+			host:     "vm.owasp.org",
+			target:   "timeout.cloudapp.net",
+			fp:       azure,
+			takeover: false,
+		},
+		{
+			name:     "no match",
+			host:     "app.owasp.org",
+			target:   "owasp.herokuapp.com",
+			fp:       heroku,
+			pages:    testPages{"https://app.owasp.org": {StatusCode: 200, Body: "Welcome to the OWASP app"}},
+			takeover: false,
+		},
+		{
+			name:     "no response",
+			host:     "app.owasp.org",
+			target:   "owasp.herokuapp.com",
+			fp:       heroku,
+			takeover: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tt := newTestTakeoverTask(t, test.pages)
+
+			f := tt.check(context.Background(), test.host, test.target, test.fp)
+			if (f != nil) != test.takeover {
+				t.Errorf("check(%q, %q) returned %v, expected a finding: %t", test.host, test.target, f, test.takeover)
+			} else if f != nil && f.Type != requests.FindingTakeover {
+				t.Errorf("check(%q, %q) returned the finding type %s", test.host, test.target, f.Type)
+			}
+		})
+	}
+}
+
+func TestTakeoverCNAMEChain(t *testing.T) {
+	ctx := context.Background()
+	tt := newTestTakeoverTask(t, testPages{
+		"https://www.owasp.org": {Body: "No such app herokucdn.com/error-pages/no-such-app.html"},
+	})
+
+	if _, err := tt.enum.graph.UpsertFQDN(ctx, "www.owasp.org", "DNS", "ef9f9475-34eb-465e-81ec-9a1f7f8d2f5b"); err != nil {
+		t.Fatalf("failed to insert the name: %v", err)
+	}
+	// The vulnerable service is the last target of the chain
+	req := &requests.DNSRequest{
+		Name:   "www.owasp.org",
+		Domain: "owasp.org",
+		Records: []requests.DNSAnswer{
+			{Name: "www.owasp.org", Type: int(dns.TypeCNAME), Data: "cdn.owasp-edge.net."},
+			{Name: "cdn.owasp-edge.net", Type: int(dns.TypeCNAME), Data: "owasp.herokuapp.com."},
+			{Name: "owasp.herokuapp.com", Type: int(dns.TypeA), Data: "192.0.2.20"},
+		},
+	}
+	if _, err := tt.Process(ctx, req, nil); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	props, err := tt.enum.graph.ReadProperties(ctx, netmap.Node("www.owasp.org"), requests.FindingPredicate)
+	if err != nil || len(props) != 1 {
+		t.Errorf("Process() stored %d findings for the CNAME chain: %v", len(props), err)
+	}
+}
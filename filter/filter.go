@@ -0,0 +1,523 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package filter implements the expression language selecting the discovered names, such as
+// `tag==cert && source=="Crtsh" && seen>2024-01-01`, evaluated against the enumeration findings.
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+// Asset is a discovered name, with the times of the first and last enumerations that discovered it.
+type Asset struct {
+	*requests.Output
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Filter is a parsed expression that selects the assets matching it.
+type Filter struct {
+	expr   string
+	root   node
+	fields map[string]struct{}
+}
+
+type fieldKind int
+
+const (
+	stringField fieldKind = iota
+	addrField
+	numberField
+	timeField
+)
+
+// The fields of the assets that can be compared in the expressions
+var fields = map[string]fieldKind{
+	"name":       stringField,
+	"domain":     stringField,
+	"tag":        stringField,
+	"source":     stringField,
+	"cidr":       stringField,
+	"desc":       stringField,
+	"tech":       stringField,
+	"waf":        stringField,
+	"finding":    stringField,
+	"addr":       addrField,
+	"asn":        numberField,
+	"confidence": numberField,
+	"first":      timeField,
+	"seen":       timeField,
+}
+
+// The alternative names accepted for the fields
+var aliases = map[string]string{
+	"conf": "confidence",
+	"ip":   "addr",
+	"last": "seen",
+	"src":  "source",
+}
+
+// The layouts accepted for the values of the time fields
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+type node interface {
+	match(a *Asset) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n *andNode) match(a *Asset) bool { return n.left.match(a) && n.right.match(a) }
+
+type orNode struct{ left, right node }
+
+func (n *orNode) match(a *Asset) bool { return n.left.match(a) || n.right.match(a) }
+
+type notNode struct{ n node }
+
+func (n *notNode) match(a *Asset) bool { return !n.n.match(a) }
+
+type cmpNode struct {
+	field string
+	op    string
+	value string
+	re    *regexp.Regexp
+	ip    net.IP
+	ipnet *net.IPNet
+	num   int
+	t     time.Time
+}
+
+// Parse returns the Filter for the expression, or an error describing why the expression is not valid.
+func Parse(expr string) (*Filter, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("the filter expression is empty")
+	}
+
+	p := &parser{tokens: tokens, fields: make(map[string]struct{})}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t != nil {
+		return nil, fmt.Errorf("unexpected %q at position %d of the filter expression", t.text, t.pos+1)
+	}
+	return &Filter{expr: expr, root: root, fields: p.fields}, nil
+}
+
+// String returns the expression of the filter.
+func (f *Filter) String() string {
+	return f.expr
+}
+
+// Uses returns true when the expression compares any of the fields.
+func (f *Filter) Uses(fields ...string) bool {
+	for _, field := range fields {
+		if alias, found := aliases[field]; found {
+			field = alias
+		}
+		if _, found := f.fields[field]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns true when the asset satisfies the expression.
+func (f *Filter) Match(a *Asset) bool {
+	if a == nil || a.Output == nil {
+		return false
+	}
+	return f.root.match(a)
+}
+
+func (n *cmpNode) match(a *Asset) bool {
+	switch fields[n.field] {
+	case timeField:
+		t := a.LastSeen
+		if n.field == "first" {
+			t = a.FirstSeen
+		}
+		if t.IsZero() {
+			return false
+		}
+		return compare(compareTimes(t, n.t), n.op)
+	case numberField:
+		if n.field == "confidence" {
+			return compare(compareInts(a.Confidence(), n.num), n.op)
+		}
+		for _, addr := range a.Addresses {
+			if compare(compareInts(addr.ASN, n.num), n.op) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var found bool
+	for _, v := range n.values(a) {
+		if n.equal(v) {
+			found = true
+			break
+		}
+	}
+	if n.op == "!=" || n.op == "!~" {
+		return !found
+	}
+	return found
+}
+
+// equal returns true when the asset value satisfies the == or =~ form of the comparison.
+func (n *cmpNode) equal(v string) bool {
+	if n.re != nil {
+		return n.re.MatchString(v)
+	}
+	if fields[n.field] == addrField {
+		ip := net.ParseIP(v)
+		if ip == nil {
+			return false
+		}
+		if n.ipnet != nil {
+			return n.ipnet.Contains(ip)
+		}
+		return n.ip.Equal(ip)
+	}
+	if n.field == "tech" && strings.EqualFold(strings.SplitN(v, "/", 2)[0], n.value) {
+		return true
+	}
+	return strings.EqualFold(v, n.value)
+}
+
+func (n *cmpNode) values(a *Asset) []string {
+	var values []string
+
+	switch n.field {
+	case "name":
+		values = append(values, a.Name)
+	case "domain":
+		values = append(values, a.Domain)
+	case "tag":
+		values = append(values, a.Tag)
+	case "source":
+		values = append(values, a.Sources...)
+	case "tech":
+		values = append(values, a.Technologies()...)
+	case "waf":
+		values = append(values, a.WAFs()...)
+	case "finding":
+		for _, f := range a.Findings {
+			values = append(values, f.Type)
+		}
+	case "addr", "cidr", "desc":
+		for _, addr := range a.Addresses {
+			switch n.field {
+			case "addr":
+				values = append(values, addr.Address.String())
+			case "cidr":
+				values = append(values, addr.CIDRStr)
+			case "desc":
+				values = append(values, addr.Description)
+			}
+		}
+	}
+	return values
+}
+
+func compare(c int, op string) bool {
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+type token struct {
+	text   string
+	quoted bool
+	pos    int
+}
+
+// The operators of the language, with the longer operators first
+var operators = []string{"&&", "||", "==", "!=", "=~", "!~", "<=", ">=", "<", ">", "!", "(", ")"}
+
+func lex(expr string) ([]*token, error) {
+	var tokens []*token
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			i++
+			continue
+		}
+
+		if c == '"' {
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(expr) && expr[j] != '"'; j++ {
+				if expr[j] == '\\' && j+1 < len(expr) {
+					j++
+				}
+				sb.WriteByte(expr[j])
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("the string at position %d of the filter expression is not terminated", i+1)
+			}
+			tokens = append(tokens, &token{text: sb.String(), quoted: true, pos: i})
+			i = j + 1
+			continue
+		}
+
+		var op string
+		for _, o := range operators {
+			if strings.HasPrefix(expr[i:], o) {
+				op = o
+				break
+			}
+		}
+		if op != "" {
+			tokens = append(tokens, &token{text: op, pos: i})
+			i += len(op)
+			continue
+		}
+
+		j := i
+		for ; j < len(expr) && !strings.ContainsRune(" \t\n\r\"()!=<>&|~", rune(expr[j])); j++ {
+		}
+		if j == i {
+			return nil, fmt.Errorf("unexpected %q at position %d of the filter expression", string(c), i+1)
+		}
+		tokens = append(tokens, &token{text: expr[i:j], pos: i})
+		i = j
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []*token
+	pos    int
+	fields map[string]struct{}
+}
+
+func (p *parser) peek() *token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t != nil && !t.quoted && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{n: n}, nil
+	}
+
+	if p.accept("(") {
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.unexpected("a closing parenthesis")
+		}
+		return n, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	ft := p.peek()
+	if ft == nil || ft.quoted || isOperator(ft.text) {
+		return nil, p.unexpected("a field name")
+	}
+	p.pos++
+
+	field := strings.ToLower(ft.text)
+	if alias, found := aliases[field]; found {
+		field = alias
+	}
+	kind, found := fields[field]
+	if !found {
+		return nil, fmt.Errorf("the filter field %q at position %d is not supported", ft.text, ft.pos+1)
+	}
+	p.fields[field] = struct{}{}
+
+	ot := p.peek()
+	if ot == nil || ot.quoted || !isComparison(ot.text) {
+		return nil, p.unexpected("a comparison operator after " + ft.text)
+	}
+	p.pos++
+	op := ot.text
+
+	vt := p.peek()
+	if vt == nil || (!vt.quoted && isOperator(vt.text)) {
+		return nil, p.unexpected("a value after " + ft.text + op)
+	}
+	p.pos++
+
+	n := &cmpNode{field: field, op: op, value: vt.text}
+	if op == "=~" || op == "!~" {
+		if kind == numberField || kind == timeField {
+			return nil, fmt.Errorf("the %s field does not support the %s operator", field, op)
+		}
+
+		re, err := regexp.Compile(vt.text)
+		if err != nil {
+			return nil, fmt.Errorf("the regular expression %q is not valid: %v", vt.text, err)
+		}
+		n.re = re
+		return n, nil
+	}
+
+	switch kind {
+	case stringField, addrField:
+		if op != "==" && op != "!=" {
+			return nil, fmt.Errorf("the %s field does not support the %s operator", field, op)
+		}
+		if kind == addrField {
+			if ip, ipnet, err := net.ParseCIDR(vt.text); err == nil {
+				n.ip, n.ipnet = ip, ipnet
+			} else if n.ip = net.ParseIP(vt.text); n.ip == nil {
+				return nil, fmt.Errorf("the value %q of the %s field is not an IP address or netblock", vt.text, field)
+			}
+		} else if strings.Contains(vt.text, "*") {
+			// The wildcards match any sequence of characters
+			n.re = regexp.MustCompile("(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(vt.text), `\*`, ".*") + "$")
+		}
+	case numberField:
+		num, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(vt.text), "AS"))
+		if err != nil {
+			return nil, fmt.Errorf("the value %q of the %s field is not a number", vt.text, field)
+		}
+		n.num = num
+	case timeField:
+		if op == "==" || op == "!=" {
+			return nil, fmt.Errorf("the %s field does not support the %s operator", field, op)
+		}
+
+		t, err := parseTime(vt.text)
+		if err != nil {
+			return nil, fmt.Errorf("the value %q of the %s field is not a date", vt.text, field)
+		}
+		n.t = t
+	}
+	return n, nil
+}
+
+func (p *parser) unexpected(expected string) error {
+	if t := p.peek(); t != nil {
+		return fmt.Errorf("expected %s at position %d of the filter expression, found %q", expected, t.pos+1, t.text)
+	}
+	return fmt.Errorf("expected %s at the end of the filter expression", expected)
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s is not a valid time", s)
+}
+
+func isOperator(s string) bool {
+	for _, o := range operators {
+		if s == o {
+			return true
+		}
+	}
+	return false
+}
+
+func isComparison(s string) bool {
+	switch s {
+	case "==", "!=", "=~", "!~", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func testAsset() *Asset {
+	return &Asset{
+		Output: &requests.Output{
+			Name:    "www.owasp.org",
+			Domain:  "owasp.org",
+			Tag:     requests.CERT,
+			Sources: []string{"Crtsh", "DNS"},
+			Addresses: []requests.AddressInfo{{
+				Address:     net.ParseIP("192.168.1.10"),
+				CIDRStr:     "192.168.1.0/24",
+				ASN:         64496,
+				Description: "EXAMPLE-AS",
+			}},
+			Findings: []*requests.Finding{{Type: requests.FindingDangling, Description: "dangling"}},
+			HTTP:     []*requests.HTTPInfo{{URL: "https://www.owasp.org/", Technologies: []string{"WordPress/6.1"}}},
+		},
+		FirstSeen: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+		LastSeen:  time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`tag==cert && source=="crtsh" && seen>2024-01-01`, true},
+		{`tag==cert && seen<2024-01-01`, false},
+		{`first>=2023-06-01 && last<="2024-03-15T12:00:00Z"`, true},
+		{`name==*.owasp.org`, true},
+		{`name=="api.owasp.org" || domain==owasp.org`, true},
+		{`!(name==www.owasp.org)`, false},
+		{`name!=www.owasp.org`, false},
+		{`name=~"^w{3}\\."`, true},
+		{`name!~^api`, true},
+		{`src==DNS && source!=Brute`, true},
+		{`addr==192.168.0.0/16`, true},
+		{`ip==192.168.1.11`, false},
+		{`cidr=="192.168.1.0/24" && desc=~EXAMPLE`, true},
+		{`asn==AS64496`, true},
+		{`asn>64496`, false},
+		{`tech==wordpress && finding==dangling`, true},
+		{`waf==Cloudflare`, false},
+		{`waf!=Cloudflare`, true},
+		{`(tag==api || tag==dns) && name==www.owasp.org`, false},
+		{`tag==api || tag==dns && name==www.owasp.org`, false},
+		{`tag==cert || tag==dns && name==api.owasp.org`, true},
+		{`confidence>=100`, true},
+		{`conf<50 || tag==brute`, false},
+	}
+
+	a := testAsset()
+	for _, tt := range tests {
+		f, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if got := f.Match(a); got != tt.want {
+			t.Errorf("Filter(%q).Match() = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	a.LastSeen = time.Time{}
+	if f, _ := Parse("seen<2030-01-01"); f.Match(a) {
+		t.Errorf("the filter matched an asset without the time it was seen")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"name",
+		"name==",
+		"name=www.owasp.org",
+		"color==blue",
+		"name>www.owasp.org",
+		"seen==2024-01-01",
+		"seen>yesterday",
+		"asn==many",
+		"addr==www.owasp.org",
+		`name=~"("`,
+		`name=="www.owasp.org`,
+		"(name==www.owasp.org",
+		"name==www.owasp.org)",
+		"name==www.owasp.org &&",
+		"name==www.owasp.org tag==dns",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) accepted the invalid expression", expr)
+		}
+	}
+}
+
+func TestFilterUses(t *testing.T) {
+	f, _ := Parse("name==www.owasp.org || !(first<2024-01-01 && ip==192.168.1.10)")
+	if f.Uses("seen", "asn") {
+		t.Errorf("Uses() returned true for fields not in the expression")
+	}
+	if !f.Uses("first") || !f.Uses("addr") || !f.Uses("seen", "ip") {
+		t.Errorf("Uses() returned false for fields in the expression")
+	}
+}
+
+func TestSelectionMatch(t *testing.T) {
+	brute := &requests.Output{Name: "dev.owasp.org", Tag: requests.BRUTE, Sources: []string{"Brute Forcing"}}
+	cert := testAsset().Output
+
+	tests := []struct {
+		sel   *Selection
+		brute bool
+		cert  bool
+	}{
+		{nil, true, true},
+		{&Selection{}, true, true},
+		{&Selection{IncludeTags: []string{"CERT", "api"}}, false, true},
+		{&Selection{ExcludeTags: []string{"BRUTE", "ALT", "GUESS"}}, false, true},
+		{&Selection{IncludeSources: []string{"crtsh"}}, false, true},
+		{&Selection{ExcludeSources: []string{"Brute Forcing", "Crtsh"}}, false, true},
+		{&Selection{ExcludeSources: []string{"Crtsh", "DNS"}}, true, false},
+		{&Selection{MinConfidence: 50}, false, true},
+	}
+
+	for i, tt := range tests {
+		if got := tt.sel.Match(brute); got != tt.brute {
+			t.Errorf("test %d: Selection.Match() = %v for the brute forced name, want %v", i, got, tt.brute)
+		}
+		if got := tt.sel.Match(cert); got != tt.cert {
+			t.Errorf("test %d: Selection.Match() = %v for the certificate name, want %v", i, got, tt.cert)
+		}
+	}
+}
+
+func TestSelectionCheck(t *testing.T) {
+	for _, sel := range []*Selection{
+		{MinConfidence: 101},
+		{MinConfidence: -1},
+		{IncludeTags: []string{"cert"}, ExcludeTags: []string{"CERT"}},
+		{IncludeSources: []string{"Crtsh"}, ExcludeSources: []string{"crtsh"}},
+	} {
+		if err := sel.Check(); err == nil {
+			t.Errorf("Selection.Check() accepted the invalid selection %+v", sel)
+		}
+	}
+	if err := (&Selection{IncludeTags: []string{"cert"}, MinConfidence: 80}).Check(); err != nil {
+		t.Errorf("Selection.Check() error = %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+// Selection keeps the discovered names with the tags and the data sources selected, and with the confidence
+// reaching the threshold. It provides the output flags with a simpler form than the expressions.
+type Selection struct {
+	IncludeTags    []string
+	ExcludeTags    []string
+	IncludeSources []string
+	ExcludeSources []string
+	MinConfidence  int
+}
+
+// Check returns an error when the confidence threshold is out of range, or when a tag or a data
+// source is both included and excluded.
+func (s *Selection) Check() error {
+	if s.MinConfidence < 0 || s.MinConfidence > 100 {
+		return fmt.Errorf("the confidence threshold %d must be between 0 and 100", s.MinConfidence)
+	}
+	for _, tag := range s.IncludeTags {
+		if contains(s.ExcludeTags, tag) {
+			return fmt.Errorf("the tag %s cannot be both included and excluded", tag)
+		}
+	}
+	for _, src := range s.IncludeSources {
+		if contains(s.ExcludeSources, src) {
+			return fmt.Errorf("the data source %s cannot be both included and excluded", src)
+		}
+	}
+	return nil
+}
+
+// Empty returns true when the selection keeps all the discovered names.
+func (s *Selection) Empty() bool {
+	return s == nil || (len(s.IncludeTags) == 0 && len(s.ExcludeTags) == 0 &&
+		len(s.IncludeSources) == 0 && len(s.ExcludeSources) == 0 && s.MinConfidence == 0)
+}
+
+// Match returns true when the discovered name is kept by the selection. The names reported by several
+// data sources are only left out when all of them were excluded.
+func (s *Selection) Match(o *requests.Output) bool {
+	if s.Empty() {
+		return true
+	}
+	if o == nil {
+		return false
+	}
+
+	if len(s.IncludeTags) > 0 && !contains(s.IncludeTags, o.Tag) {
+		return false
+	}
+	if contains(s.ExcludeTags, o.Tag) {
+		return false
+	}
+	if len(s.IncludeSources) > 0 && !containsAny(s.IncludeSources, o.Sources) {
+		return false
+	}
+	if len(s.ExcludeSources) > 0 && len(o.Sources) > 0 {
+		var kept bool
+		for _, src := range o.Sources {
+			if !contains(s.ExcludeSources, src) {
+				kept = true
+				break
+			}
+		}
+		if !kept {
+			return false
+		}
+	}
+	return o.Confidence() >= s.MinConfidence
+}
+
+func contains(list []string, v string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(list, values []string) bool {
+	for _, v := range values {
+		if contains(list, v) {
+			return true
+		}
+	}
+	return false
+}
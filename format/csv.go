@@ -0,0 +1,179 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+// CSVColumns are the columns that can be selected for the CSV output.
+var CSVColumns = []string{"name", "domain", "addresses", "tag", "sources", "first_seen", "last_seen", "cidr", "asn"}
+
+// DefaultCSVColumns is the column selection used when none is provided.
+var DefaultCSVColumns = []string{"name", "domain", "addresses", "tag", "sources"}
+
+// The separator used within the columns that hold several values
+const csvValueSep = ";"
+
+// ParseCSVColumns implements the flag.Value interface.
+type ParseCSVColumns []string
+
+func (p *ParseCSVColumns) String() string {
+	if p == nil {
+		return ""
+	}
+	return strings.Join(*p, ",")
+}
+
+// Set implements the flag.Value interface. The columns keep the order provided.
+func (p *ParseCSVColumns) Set(s string) error {
+	var columns []string
+
+	seen := make(map[string]struct{})
+	for _, c := range strings.Split(s, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" {
+			continue
+		}
+		if !validCSVColumn(c) {
+			return fmt.Errorf("unknown CSV column %q, the columns are %s", c, strings.Join(CSVColumns, ", "))
+		}
+		if _, found := seen[c]; found {
+			return fmt.Errorf("the CSV column %q was selected more than once", c)
+		}
+		seen[c] = struct{}{}
+		columns = append(columns, c)
+	}
+
+	if len(columns) == 0 {
+		return fmt.Errorf("no CSV columns were selected")
+	}
+	*p = columns
+	return nil
+}
+
+// Has returns true when the column was selected, or is one of the default columns when none were.
+func (p ParseCSVColumns) Has(column string) bool {
+	columns := []string(p)
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+func validCSVColumn(c string) bool {
+	for _, col := range CSVColumns {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
+
+// CSVWriter writes the discovered names as CSV records with the selected columns.
+type CSVWriter struct {
+	w       *csv.Writer
+	columns []string
+	header  bool
+}
+
+// NewCSVWriter returns a CSVWriter for the columns, or the DefaultCSVColumns when none are provided.
+func NewCSVWriter(w io.Writer, columns ParseCSVColumns) *CSVWriter {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+
+	return &CSVWriter{
+		w:       csv.NewWriter(w),
+		columns: columns,
+	}
+}
+
+// Write adds the record for the output, preceded by the header row on the first call.
+// The firstSeen time is written when the output does not provide its own, and only when not zero.
+func (c *CSVWriter) Write(out *requests.Output, firstSeen time.Time) error {
+	if !c.header {
+		if err := c.w.Write(c.columns); err != nil {
+			return err
+		}
+		c.header = true
+	}
+
+	record := make([]string, 0, len(c.columns))
+	for _, col := range c.columns {
+		record = append(record, csvValue(col, out, firstSeen))
+	}
+	return c.w.Write(record)
+}
+
+// Flush writes any buffered records to the underlying writer.
+func (c *CSVWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func csvValue(col string, out *requests.Output, firstSeen time.Time) string {
+	var values []string
+
+	switch col {
+	case "name":
+		return out.Name
+	case "domain":
+		return out.Domain
+	case "tag":
+		return out.Tag
+	case "sources":
+		return strings.Join(out.Sources, csvValueSep)
+	case "first_seen":
+		if out.FirstSeen != "" {
+			return out.FirstSeen
+		}
+		if firstSeen.IsZero() {
+			return ""
+		}
+		return firstSeen.UTC().Format(time.RFC3339)
+	case "last_seen":
+		return out.LastSeen
+	case "addresses":
+		for _, a := range out.Addresses {
+			values = append(values, a.Address.String())
+		}
+	case "cidr":
+		for _, a := range out.Addresses {
+			if a.CIDRStr != "" {
+				values = appendUnique(values, a.CIDRStr)
+			}
+		}
+	case "asn":
+		for _, a := range out.Addresses {
+			if a.ASN != 0 {
+				values = appendUnique(values, strconv.Itoa(a.ASN))
+			}
+		}
+	}
+	return strings.Join(values, csvValueSep)
+}
+
+func appendUnique(values []string, v string) []string {
+	for _, val := range values {
+		if val == v {
+			return values
+		}
+	}
+	return append(values, v)
+}
@@ -0,0 +1,105 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func TestParseCSVColumns(t *testing.T) {
+	cases := []struct {
+		label    string
+		input    string
+		ok       bool
+		expected string
+	}{
+		{label: "Valid_Input", input: "name,domain,addresses", ok: true, expected: "name,domain,addresses"},
+		{label: "Reordered_Columns", input: " ASN, name,first_seen,", ok: true, expected: "asn,name,first_seen"},
+		{label: "Empty_Input", input: " , "},
+		{label: "Unknown_Column", input: "name,ttl"},
+		{label: "Duplicate_Column", input: "name,domain,name"},
+	}
+
+	for _, c := range cases {
+		f := func(t *testing.T) {
+			var columns ParseCSVColumns
+
+			err := columns.Set(c.input)
+			if c.ok && err != nil {
+				t.Errorf("Error: %v", err)
+			} else if !c.ok && err == nil {
+				t.Errorf("Expected an error for %q", c.input)
+			}
+			if got := columns.String(); got != c.expected {
+				t.Errorf("Got: %q; Expected: %q", got, c.expected)
+			}
+		}
+		t.Run(c.label, f)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	var columns ParseCSVColumns
+	_ = columns.Set("name,addresses,cidr,asn,sources,first_seen,tag")
+	if !columns.Has("first_seen") || columns.Has("domain") {
+		t.Errorf("Has() did not report the selected columns")
+	}
+	w := NewCSVWriter(&buf, columns)
+
+	out := &requests.Output{
+		Name:    "www.owasp.org",
+		Domain:  "owasp.org",
+		Tag:     requests.CERT,
+		Sources: []string{"Crtsh", "DNS"},
+		Addresses: []requests.AddressInfo{
+			{Address: net.ParseIP("192.168.1.10"), CIDRStr: "192.168.1.0/24", ASN: 64496},
+			{Address: net.ParseIP("192.168.1.11"), CIDRStr: "192.168.1.0/24", ASN: 64496},
+		},
+	}
+	if err := w.Write(out, time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if err := w.Write(&requests.Output{Name: "a,b.owasp.org", Tag: requests.DNS}, time.Time{}); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected := `name,addresses,cidr,asn,sources,first_seen,tag
+www.owasp.org,192.168.1.10;192.168.1.11,192.168.1.0/24,64496,Crtsh;DNS,2023-06-01T12:00:00Z,cert
+"a,b.owasp.org",,,,,,dns
+`
+	if got := buf.String(); got != expected {
+		t.Errorf("Got: %q; Expected: %q", got, expected)
+	}
+
+	buf.Reset()
+	_ = columns.Set("name,first_seen,last_seen")
+	w = NewCSVWriter(&buf, columns)
+	out.FirstSeen = "2023-01-15T08:00:00Z"
+	out.LastSeen = "2024-03-15T12:00:00Z"
+	_ = w.Write(out, time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC))
+	_ = w.Flush()
+	if got := buf.String(); got != "name,first_seen,last_seen\nwww.owasp.org,2023-01-15T08:00:00Z,2024-03-15T12:00:00Z\n" {
+		t.Errorf("Got: %q for the output providing the times it was seen", got)
+	}
+
+	buf.Reset()
+	w = NewCSVWriter(&buf, nil)
+	_ = w.Write(out, time.Time{})
+	_ = w.Flush()
+	if got := strings.SplitN(buf.String(), "\n", 2)[0]; got != "name,domain,addresses,tag,sources" {
+		t.Errorf("Got the header %q for the default columns", got)
+	}
+}
@@ -223,6 +223,20 @@ func OutputLineParts(out *requests.Output, src, addrs, demo bool) (source, name,
 	return
 }
 
+// FindingLines returns the findings associated with the output formatted for display beneath the name.
+func FindingLines(out *requests.Output) []string {
+	var lines []string
+
+	for _, f := range out.Findings {
+		line := fmt.Sprintf("%-18s%s", "", "["+f.Type+"] "+f.Description)
+		if f.Evidence != "" {
+			line += " (" + f.Evidence + ")"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 // DesiredAddrTypes removes undesired address types from the AddressInfo slice.
 func DesiredAddrTypes(addrs []requests.AddressInfo, ipv4, ipv6 bool) []requests.AddressInfo {
 	if !ipv4 && !ipv6 {
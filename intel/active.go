@@ -119,7 +119,12 @@ func (a *activeTask) certEnumeration(ctx context.Context, req *requests.AddrRequ
 
 	c := a.c
 	addrinfo := requests.AddressInfo{Address: ip}
-	for _, name := range http.PullCertificateNames(ctx, req.Address, c.Config.Ports) {
+	ports := c.certPorts(req.Address)
+	if len(ports) == 0 {
+		return
+	}
+
+	for _, name := range http.PullCertificateNames(ctx, req.Address, ports) {
 		if n := strings.TrimSpace(name); n != "" {
 			domain, err := publicsuffix.EffectiveTLDPlusOne(n)
 			if err != nil {
@@ -29,7 +29,7 @@ type intelSource struct {
 func newIntelSource(c *Collection) *intelSource {
 	return &intelSource{
 		collection: c,
-		filter:     bf.NewDefaultStableBloomFilter(1000000, 0.01),
+		filter:     bf.NewDefaultStableBloomFilter(c.Config.FilterSettings()),
 		queue:      queue.NewQueue(),
 		done:       make(chan struct{}),
 		timeout:    minWaitForData,
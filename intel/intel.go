@@ -42,6 +42,9 @@ type Collection struct {
 	doneAlreadyClosed bool
 	filter            *bf.StableBloomFilter
 	timeChan          chan time.Time
+	// The live hosts imported from the output of port scanners
+	ScanHosts []*amassnet.ScanHost
+	scanHosts map[string]*amassnet.ScanHost
 }
 
 // NewCollection returns an initialized Collection object that has not been started yet.
@@ -52,7 +55,7 @@ func NewCollection(cfg *config.Config, sys systems.System) *Collection {
 		srcs:     datasrcs.SelectedDataSources(cfg, sys.DataSources()),
 		Output:   make(chan *requests.Output, 100),
 		done:     make(chan struct{}, 2),
-		filter:   bf.NewDefaultStableBloomFilter(1000000, 0.01),
+		filter:   bf.NewDefaultStableBloomFilter(cfg.FilterSettings()),
 		timeChan: make(chan time.Time, 50),
 	}
 }
@@ -94,6 +97,19 @@ func (c *Collection) HostedDomains(ctx context.Context) error {
 	for _, addr := range c.Config.Addresses {
 		source.InputAddress(&requests.AddrRequest{Address: addr.String()})
 	}
+	if len(c.ScanHosts) > 0 {
+		c.Lock()
+		c.scanHosts = make(map[string]*amassnet.ScanHost, len(c.ScanHosts))
+		for _, host := range c.ScanHosts {
+			c.scanHosts[host.Address] = host
+		}
+		c.Unlock()
+
+		c.storeScanHosts(c.ctx)
+		for _, host := range c.ScanHosts {
+			source.InputAddress(&requests.AddrRequest{Address: host.Address})
+		}
+	}
 	for _, cidr := range append(c.Config.CIDRs, c.asnsToCIDRs()...) {
 		// Skip IPv6 netblocks, since they are simply too large
 		if ip := cidr.IP.Mask(cidr.Mask); amassnet.IsIPv6(ip) {
@@ -162,6 +178,20 @@ func (c *Collection) makeDNSTaskFunc() pipeline.TaskFunc {
 				}
 			}
 		}
+		// The names reported by the port scanner for the address
+		if host := c.scanHost(req.Address); host != nil {
+			for _, name := range host.Names {
+				if d, err := publicsuffix.EffectiveTLDPlusOne(name); err == nil && d != "" {
+					go pipeline.SendData(ctx, "filter", &requests.Output{
+						Name:      d,
+						Domain:    d,
+						Addresses: []requests.AddressInfo{addrinfo},
+						Tag:       requests.IMPORT,
+						Sources:   []string{host.Source},
+					}, tp)
+				}
+			}
+		}
 		return data, nil
 	})
 }
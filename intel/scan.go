@@ -0,0 +1,95 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package intel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/caffix/netmap"
+	amassnet "github.com/owasp-amass/amass/v3/net"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+// scanHost returns the imported port scanner results for the address, or nil when it was not scanned.
+func (c *Collection) scanHost(addr string) *amassnet.ScanHost {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.scanHosts[addr]
+}
+
+// certPorts returns the ports where the certificates of the address are pulled from. The TLS ports
+// found open by the port scanner take the place of the ports in the configuration.
+func (c *Collection) certPorts(addr string) []int {
+	if host := c.scanHost(addr); host != nil {
+		return host.TLSPorts
+	}
+	return c.Config.Ports
+}
+
+// storeScanHosts links the names and the open ports of the imported hosts into the graph databases,
+// where they join the findings of the enumerations against the same addresses.
+func (c *Collection) storeScanHosts(ctx context.Context) {
+	for _, g := range c.Sys.GraphDatabases() {
+		uuid := c.Config.UUID.String()
+
+		if err := systems.SetEventWorkspace(ctx, g, uuid, c.Config.Workspace); err != nil {
+			c.Config.Log.Printf("%s failed to set the workspace of the event: %v", g, err)
+			continue
+		}
+
+		for _, host := range c.ScanHosts {
+			if err := insertScanHost(ctx, g, host, uuid); err != nil {
+				c.Config.Log.Print(err.Error())
+			}
+		}
+	}
+}
+
+func insertScanHost(ctx context.Context, g *netmap.Graph, host *amassnet.ScanHost, uuid string) error {
+	addr := host.Address
+
+	if _, err := g.UpsertAddress(ctx, addr, host.Source, uuid); err != nil {
+		return fmt.Errorf("%s failed to insert the address %s: %v", g, addr, err)
+	}
+	for _, name := range host.Names {
+		insert := g.UpsertA
+		if amassnet.IsIPv6(net.ParseIP(addr)) {
+			insert = g.UpsertAAAA
+		}
+		if err := insert(ctx, name, addr, host.Source, uuid); err != nil {
+			return fmt.Errorf("%s failed to link the name %s to the address %s: %v", g, name, addr, err)
+		}
+	}
+
+	for _, port := range host.Ports {
+		id := net.JoinHostPort(addr, strconv.Itoa(port))
+
+		node, err := g.UpsertNode(ctx, id, requests.TypePort)
+		if err != nil {
+			return fmt.Errorf("%s failed to insert the port %s: %v", g, id, err)
+		}
+		if err := g.AddNodeToEvent(ctx, node, host.Source, uuid); err != nil {
+			return fmt.Errorf("%s failed to add the port %s to the event: %v", g, id, err)
+		}
+		for pred, val := range map[string]string{"number": strconv.Itoa(port), "protocol": "tcp"} {
+			if err := g.UpsertProperty(ctx, node, pred, val); err != nil {
+				return fmt.Errorf("%s failed to insert the port %s property: %v", g, pred, err)
+			}
+		}
+		if err := g.UpsertEdge(ctx, &netmap.Edge{
+			Predicate: requests.PortPredicate,
+			From:      netmap.Node(addr),
+			To:        node,
+		}); err != nil {
+			return fmt.Errorf("%s failed to link the port %s to the address: %v", g, id, err)
+		}
+	}
+	return nil
+}
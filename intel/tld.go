@@ -0,0 +1,249 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package intel
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/caffix/stringset"
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/http"
+	"github.com/owasp-amass/amass/v3/net/rdap"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/resolve"
+	"golang.org/x/net/publicsuffix"
+)
+
+const maxCandidateTasks int = 25
+
+// domainProfile holds the registration and DNS evidence collected for a single domain name.
+type domainProfile struct {
+	Registered  bool
+	Resolves    bool
+	Addresses   []net.IP
+	Nameservers *stringset.Set
+	MailServers *stringset.Set
+	Registrant  string
+	CertOrg     string
+}
+
+func (p *domainProfile) close() {
+	p.Nameservers.Close()
+	p.MailServers.Close()
+}
+
+// TLDExpansion checks the second-level labels of the in-scope domains across other top-level
+// domains and returns the registered candidates, along with the evidence linking them to the target.
+func (c *Collection) TLDExpansion(ctx context.Context) error {
+	if c.Output == nil {
+		return errors.New("the intelligence collection did not have an output channel")
+	} else if err := c.Config.CheckSettings(); err != nil {
+		return err
+	}
+	defer close(c.Output)
+
+	tlds := c.Config.ExpansionTLDs
+	if len(tlds) == 0 {
+		tlds = config.DefaultExpansionTLDs
+	}
+
+	client := rdap.NewClient()
+	for _, domain := range c.Config.Domains() {
+		base, err := publicsuffix.EffectiveTLDPlusOne(domain)
+		if err != nil {
+			continue
+		}
+		suffix, _ := publicsuffix.PublicSuffix(base)
+		label := strings.TrimSuffix(base, "."+suffix)
+
+		var candidates []*candidate
+		for _, tld := range tlds {
+			if name := label + "." + tld; name != base {
+				candidates = append(candidates, &candidate{Name: name})
+			}
+		}
+		if !c.checkCandidates(ctx, client, base, candidates) {
+			break
+		}
+	}
+	return nil
+}
+
+// candidate is a domain name that may be related to, or impersonating, an in-scope domain.
+type candidate struct {
+	Name string
+	Kind string
+}
+
+// checkCandidates profiles the candidates concurrently and returns false when the context expires.
+func (c *Collection) checkCandidates(ctx context.Context, client *rdap.Client, base string, candidates []*candidate) bool {
+	target := c.profileDomain(ctx, client, base)
+	defer target.close()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	sem := make(chan struct{}, maxCandidateTasks)
+	for _, cand := range candidates {
+		if c.filter.TestAndAdd([]byte(cand.Name)) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(cand *candidate) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			c.checkCandidate(ctx, client, target, cand)
+		}(cand)
+	}
+	return true
+}
+
+func (c *Collection) checkCandidate(ctx context.Context, client *rdap.Client, target *domainProfile, cand *candidate) {
+	p := c.profileDomain(ctx, client, cand.Name)
+	defer p.close()
+
+	if !p.Registered && !p.Resolves {
+		return
+	}
+
+	var evidence []string
+	if cand.Kind != "" {
+		evidence = append(evidence, cand.Kind)
+	}
+	if p.Registered {
+		evidence = append(evidence, "Registered")
+	}
+	if p.Resolves {
+		evidence = append(evidence, "Resolves")
+	}
+	if sharedNames(target.Nameservers, p.Nameservers) {
+		evidence = append(evidence, "Shared NS")
+	}
+	if sharedNames(target.MailServers, p.MailServers) {
+		evidence = append(evidence, "Shared MX")
+	}
+	if target.Registrant != "" && strings.EqualFold(target.Registrant, p.Registrant) {
+		evidence = append(evidence, "Registrant Match")
+	}
+	if target.CertOrg != "" && strings.EqualFold(target.CertOrg, p.CertOrg) {
+		evidence = append(evidence, "Certificate Org Match")
+	}
+
+	var addrs []requests.AddressInfo
+	for _, ip := range p.Addresses {
+		addrs = append(addrs, requests.AddressInfo{Address: ip})
+	}
+
+	c.Output <- &requests.Output{
+		Name:      cand.Name,
+		Domain:    cand.Name,
+		Addresses: addrs,
+		Tag:       requests.DNS,
+		Sources:   evidence,
+	}
+}
+
+func (c *Collection) profileDomain(ctx context.Context, client *rdap.Client, name string) *domainProfile {
+	p := &domainProfile{
+		Nameservers: stringset.New(),
+		MailServers: stringset.New(),
+	}
+
+	if d, err := client.Domain(ctx, name); err == nil {
+		p.Registered = true
+		p.Nameservers.InsertMany(d.Nameservers...)
+		if e := rdap.FindEntity(d.Entities, "registrant"); e != nil {
+			p.Registrant = e.Org
+			if p.Registrant == "" {
+				p.Registrant = e.Name
+			}
+		}
+	}
+
+	if ns := c.queryNames(ctx, name, dns.TypeNS); len(ns) > 0 {
+		p.Registered = true
+		p.Nameservers.InsertMany(ns...)
+	}
+	p.MailServers.InsertMany(c.queryNames(ctx, name, dns.TypeMX)...)
+
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		for _, addr := range c.queryNames(ctx, name, qtype) {
+			if ip := net.ParseIP(addr); ip != nil {
+				p.Resolves = true
+				p.Addresses = append(p.Addresses, ip)
+			}
+		}
+	}
+
+	if c.Config.Active && p.Resolves {
+		p.CertOrg = certificateOrg(ctx, name, c.Config.Ports)
+	}
+	return p
+}
+
+func (c *Collection) queryNames(ctx context.Context, name string, qtype uint16) []string {
+	resp, err := c.Sys.TrustedResolvers().QueryBlocking(ctx, resolve.QueryMsg(name, qtype))
+	if err != nil {
+		return nil
+	}
+
+	var results []string
+	for _, a := range resolve.ExtractAnswers(resp) {
+		if a.Type != qtype {
+			continue
+		}
+
+		data := strings.ToLower(strings.Trim(strings.TrimSpace(a.Data), "."))
+		if data != "" {
+			results = append(results, data)
+		}
+	}
+	return results
+}
+
+func certificateOrg(ctx context.Context, name string, ports []int) string {
+	for _, port := range ports {
+		conn, err := http.TLSConn(ctx, name, port)
+		if err != nil {
+			continue
+		}
+
+		var org string
+		if certs := conn.ConnectionState().PeerCertificates; len(certs) > 0 && len(certs[0].Subject.Organization) > 0 {
+			org = certs[0].Subject.Organization[0]
+		}
+		conn.Close()
+
+		if org != "" {
+			return org
+		}
+	}
+	return ""
+}
+
+func sharedNames(a, b *stringset.Set) bool {
+	if a.Len() == 0 || b.Len() == 0 {
+		return false
+	}
+
+	set := stringset.New(a.Slice()...)
+	defer set.Close()
+
+	set.Intersect(b)
+	return set.Len() > 0
+}
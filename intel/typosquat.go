@@ -0,0 +1,295 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package intel
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/rdap"
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// The kinds of permutations generated for lookalike domain names
+const (
+	kindAddition      = "Addition"
+	kindBitsquat      = "Bitsquat"
+	kindHomoglyph     = "Homoglyph"
+	kindHyphenation   = "Hyphenation"
+	kindInsertion     = "Insertion"
+	kindOmission      = "Omission"
+	kindPunycode      = "Punycode"
+	kindRepetition    = "Repetition"
+	kindReplacement   = "Replacement"
+	kindSubdomain     = "Subdomain"
+	kindTLDSwap       = "TLD Swap"
+	kindTransposition = "Transposition"
+	kindVowelSwap     = "Vowel Swap"
+)
+
+const ldhChars = "abcdefghijklmnopqrstuvwxyz0123456789-"
+
+var keyboardAdjacent = map[rune]string{
+	'1': "2q", '2': "3wq1", '3': "4ew2", '4': "5re3", '5': "6tr4", '6': "7yt5", '7': "8uy6", '8': "9iu7", '9': "0oi8", '0': "po9",
+	'q': "12wa", 'w': "3esaq2", 'e': "4rdsw3", 'r': "5tfde4", 't': "6ygfr5", 'y': "7uhgt6", 'u': "8ijhy7", 'i': "9okju8", 'o': "0plki9", 'p': "lo0",
+	'a': "qwsz", 's': "edxzaw", 'd': "rfcxse", 'f': "tgvcdr", 'g': "yhbvft", 'h': "ujnbgy", 'j': "ikmnhu", 'k': "olmji", 'l': "kop",
+	'z': "asx", 'x': "zsdc", 'c': "xdfv", 'v': "cfgb", 'b': "vghn", 'n': "bhjm", 'm': "njk",
+}
+
+// ASCII substitutions that are visually similar to the original characters
+var asciiHomoglyphs = map[string][]string{
+	"a": {"4"}, "b": {"d", "lb"}, "d": {"b", "cl"}, "e": {"3"}, "g": {"q", "9"}, "i": {"1", "l"},
+	"l": {"1", "i"}, "m": {"n", "nn", "rn"}, "n": {"m", "r"}, "o": {"0"}, "q": {"g"}, "s": {"5"},
+	"u": {"v"}, "v": {"u"}, "w": {"vv"}, "z": {"2"}, "rn": {"m"}, "cl": {"d"}, "vv": {"w"},
+}
+
+// Unicode confusables that render like the ASCII characters, mostly from the Cyrillic and Greek scripts
+var unicodeHomoglyphs = map[rune][]rune{
+	'a': {'а', 'ɑ', 'à', 'á', 'â', 'ä'}, 'c': {'с', 'ϲ'}, 'd': {'ԁ'}, 'e': {'е', 'è', 'é', 'ê', 'ë'},
+	'h': {'һ'}, 'i': {'і', 'í', 'ï'}, 'j': {'ј'}, 'k': {'κ'}, 'l': {'ӏ'}, 'n': {'ո'}, 'o': {'о', 'ο', 'ò', 'ó', 'ö'},
+	'p': {'р'}, 'q': {'ԛ'}, 's': {'ѕ'}, 'u': {'υ', 'ü'}, 'v': {'ν'}, 'w': {'ԝ'}, 'x': {'х'}, 'y': {'у', 'ý'},
+}
+
+// Typosquats generates lookalike permutations of the in-scope domains, such as homoglyphs,
+// bitsquats and TLD swaps, and returns the candidates that are registered or resolve.
+func (c *Collection) Typosquats(ctx context.Context) error {
+	if c.Output == nil {
+		return errors.New("the intelligence collection did not have an output channel")
+	} else if err := c.Config.CheckSettings(); err != nil {
+		return err
+	}
+	defer close(c.Output)
+
+	tlds := c.Config.ExpansionTLDs
+	if len(tlds) == 0 {
+		tlds = config.DefaultExpansionTLDs
+	}
+
+	client := rdap.NewClient()
+	for _, domain := range c.Config.Domains() {
+		base, err := publicsuffix.EffectiveTLDPlusOne(domain)
+		if err != nil {
+			continue
+		}
+		if !c.checkCandidates(ctx, client, base, typoPermutations(base, tlds)) {
+			break
+		}
+	}
+	return nil
+}
+
+func typoPermutations(domain string, tlds []string) []*candidate {
+	suffix, _ := publicsuffix.PublicSuffix(domain)
+	label := strings.TrimSuffix(domain, "."+suffix)
+	if label == "" || label == domain {
+		return nil
+	}
+
+	seen := map[string]struct{}{domain: {}}
+	var results []*candidate
+	add := func(kind, l, s string) {
+		name, ok := permutationName(l, s)
+		if !ok {
+			return
+		}
+		if _, found := seen[name]; !found {
+			seen[name] = struct{}{}
+			results = append(results, &candidate{Name: name, Kind: kind})
+		}
+	}
+
+	gens := []struct {
+		kind string
+		fn   func(string) []string
+	}{
+		{kindAddition, addition},
+		{kindBitsquat, bitsquat},
+		{kindHomoglyph, asciiHomoglyph},
+		{kindHyphenation, hyphenation},
+		{kindInsertion, insertion},
+		{kindOmission, omission},
+		{kindPunycode, punycodeHomoglyph},
+		{kindRepetition, repetition},
+		{kindReplacement, replacement},
+		{kindSubdomain, subdomain},
+		{kindTransposition, transposition},
+		{kindVowelSwap, vowelSwap},
+	}
+	for _, g := range gens {
+		for _, l := range g.fn(label) {
+			add(g.kind, l, suffix)
+		}
+	}
+	for _, tld := range tlds {
+		add(kindTLDSwap, label, tld)
+	}
+	return results
+}
+
+// permutationName joins the label and suffix, and checks that the result is a valid domain name.
+func permutationName(label, suffix string) (string, bool) {
+	ascii, err := idna.Lookup.ToASCII(label)
+	if err != nil || len(ascii) == 0 || len(ascii) > 63 {
+		return "", false
+	}
+
+	for _, l := range strings.Split(ascii, ".") {
+		if l == "" || strings.HasPrefix(l, "-") || strings.HasSuffix(l, "-") {
+			return "", false
+		}
+	}
+	return ascii + "." + suffix, true
+}
+
+func addition(label string) []string {
+	var results []string
+
+	for _, r := range ldhChars {
+		results = append(results, label+string(r), string(r)+label)
+	}
+	return results
+}
+
+func bitsquat(label string) []string {
+	var results []string
+
+	for i := 0; i < len(label); i++ {
+		for bit := 0; bit < 8; bit++ {
+			b := label[i] ^ (1 << bit)
+
+			if strings.IndexByte(ldhChars, b) != -1 {
+				results = append(results, label[:i]+string(b)+label[i+1:])
+			}
+		}
+	}
+	return results
+}
+
+func asciiHomoglyph(label string) []string {
+	var results []string
+
+	for from, list := range asciiHomoglyphs {
+		for i := strings.Index(label, from); i != -1; {
+			for _, to := range list {
+				results = append(results, label[:i]+to+label[i+len(from):])
+			}
+
+			next := strings.Index(label[i+1:], from)
+			if next == -1 {
+				break
+			}
+			i += next + 1
+		}
+	}
+	return results
+}
+
+func punycodeHomoglyph(label string) []string {
+	var results []string
+
+	runes := []rune(label)
+	for i, r := range runes {
+		for _, glyph := range unicodeHomoglyphs[r] {
+			p := make([]rune, len(runes))
+			copy(p, runes)
+			p[i] = glyph
+
+			results = append(results, string(p))
+		}
+	}
+	return results
+}
+
+func hyphenation(label string) []string {
+	var results []string
+
+	for i := 1; i < len(label); i++ {
+		results = append(results, label[:i]+"-"+label[i:])
+	}
+	return results
+}
+
+func insertion(label string) []string {
+	var results []string
+
+	for i := 0; i < len(label); i++ {
+		for _, r := range keyboardAdjacent[rune(label[i])] {
+			results = append(results, label[:i]+string(r)+label[i:], label[:i+1]+string(r)+label[i+1:])
+		}
+	}
+	return results
+}
+
+func omission(label string) []string {
+	var results []string
+
+	for i := 0; i < len(label); i++ {
+		results = append(results, label[:i]+label[i+1:])
+	}
+	return results
+}
+
+func repetition(label string) []string {
+	var results []string
+
+	for i := 0; i < len(label); i++ {
+		results = append(results, label[:i]+string(label[i])+label[i:])
+	}
+	return results
+}
+
+func replacement(label string) []string {
+	var results []string
+
+	for i := 0; i < len(label); i++ {
+		for _, r := range keyboardAdjacent[rune(label[i])] {
+			results = append(results, label[:i]+string(r)+label[i+1:])
+		}
+	}
+	return results
+}
+
+func subdomain(label string) []string {
+	var results []string
+
+	for i := 1; i < len(label); i++ {
+		if label[i-1] != '-' && label[i] != '-' {
+			results = append(results, label[:i]+"."+label[i:])
+		}
+	}
+	return append(results, "www"+label, "www-"+label)
+}
+
+func transposition(label string) []string {
+	var results []string
+
+	for i := 0; i < len(label)-1; i++ {
+		if label[i] != label[i+1] {
+			results = append(results, label[:i]+string(label[i+1])+string(label[i])+label[i+2:])
+		}
+	}
+	return results
+}
+
+func vowelSwap(label string) []string {
+	var results []string
+	vowels := "aeiou"
+
+	for i := 0; i < len(label); i++ {
+		if strings.IndexByte(vowels, label[i]) == -1 {
+			continue
+		}
+
+		for _, v := range vowels {
+			if byte(v) != label[i] {
+				results = append(results, label[:i]+string(v)+label[i+1:])
+			}
+		}
+	}
+	return results
+}
@@ -0,0 +1,57 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package intel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypoPermutations(t *testing.T) {
+	perms := typoPermutations("owasp.org", []string{"org", "com"})
+	if len(perms) == 0 {
+		t.Fatal("typoPermutations returned no permutations")
+	}
+
+	kinds := make(map[string]string)
+	for _, p := range perms {
+		if p.Name == "owasp.org" {
+			t.Errorf("typoPermutations returned the original domain name")
+		}
+		if strings.HasPrefix(p.Name, "-") || strings.Contains(p.Name, "-.") {
+			t.Errorf("typoPermutations returned the invalid name %s", p.Name)
+		}
+		kinds[p.Name] = p.Kind
+	}
+
+	expected := map[string]string{
+		"owsap.org":        kindTransposition,
+		"0wasp.org":        kindHomoglyph,
+		"owas.org":         kindOmission,
+		"owasp.com":        kindTLDSwap,
+		"wwwowasp.org":     kindSubdomain,
+		"xn--wasp-45d.org": kindPunycode,
+		"gwasp.org":        kindBitsquat,
+	}
+	for name, kind := range expected {
+		if got, found := kinds[name]; !found {
+			t.Errorf("typoPermutations did not generate %s", name)
+		} else if got != kind {
+			t.Errorf("typoPermutations generated %s as %s, expected %s", name, got, kind)
+		}
+	}
+}
+
+func TestTypoPermutationsPublicSuffix(t *testing.T) {
+	for _, p := range typoPermutations("example.co.uk", nil) {
+		if !strings.HasSuffix(p.Name, ".co.uk") {
+			t.Errorf("typoPermutations did not preserve the public suffix: %s", p.Name)
+		}
+	}
+
+	if perms := typoPermutations("co.uk", nil); len(perms) != 0 {
+		t.Errorf("typoPermutations generated names for a public suffix")
+	}
+}
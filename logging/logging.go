@@ -0,0 +1,184 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package logging converts the messages written to the Amass log.Logger into structured entries,
+// so the logs of enumerations run across a fleet can be parsed by log management systems.
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The formats supported for the log files
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// The levels assigned to the log entries
+const (
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// Entry is a structured log message with the fields of the subsystem that produced it.
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Level    string    `json:"level"`
+	Source   string    `json:"source,omitempty"`
+	Resolver string    `json:"resolver,omitempty"`
+	Domain   string    `json:"domain,omitempty"`
+	Message  string    `json:"msg"`
+}
+
+var (
+	queryingRE = regexp.MustCompile(`^Querying (.+) for (\S+) subdomains`)
+	wildcardRE = regexp.MustCompile(`^DNS wildcard detected: Resolver (\S+): \*\.(\S+)`)
+	resolverRE = regexp.MustCompile(`(?i)\bresolver (\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?|\[[0-9a-f:]+\](?::\d+)?)`)
+	failureRE  = regexp.MustCompile(`(?i)\b(fail(ed|s|ure)?|error|unable|cannot|dropped|timeout|refused)\b`)
+	warningRE  = regexp.MustCompile(`(?i)\b(skipping|will not|zero|incorrect|wildcard)\b`)
+)
+
+// Parser recognizes the data sources and root domain names mentioned by the log messages.
+type Parser struct {
+	sync.Mutex
+	sources map[string]string
+	domains []string
+}
+
+// NewParser returns a Parser for the data source names and root domain names of the enumeration.
+func NewParser(sources, domains []string) *Parser {
+	p := &Parser{sources: make(map[string]string, len(sources))}
+
+	p.SetSources(sources...)
+	for _, d := range domains {
+		p.domains = append(p.domains, strings.ToLower(d))
+	}
+	return p
+}
+
+// SetSources adds the data source names, since the sources can start logging before all are known.
+func (p *Parser) SetSources(sources ...string) {
+	p.Lock()
+	defer p.Unlock()
+
+	for _, s := range sources {
+		p.sources[strings.ToLower(s)] = s
+	}
+}
+
+// Parse returns the Entry for the log message, which must not include the timestamp.
+func (p *Parser) Parse(msg string, t time.Time) *Entry {
+	msg = strings.TrimSpace(msg)
+	e := &Entry{Time: t.UTC(), Level: LevelInfo, Message: msg}
+
+	if m := queryingRE.FindStringSubmatch(msg); m != nil {
+		e.Source = p.source(m[1])
+		e.Domain = strings.ToLower(m[2])
+		return e
+	}
+	if m := wildcardRE.FindStringSubmatch(msg); m != nil {
+		e.Level = LevelWarn
+		e.Resolver = m[1]
+		e.Domain = p.domain(m[2])
+		return e
+	}
+	// The data sources begin their messages with the name followed by a colon
+	if i := strings.Index(msg, ": "); i > 0 {
+		e.Source = p.source(msg[:i])
+	}
+	if m := resolverRE.FindStringSubmatch(msg); m != nil {
+		e.Resolver = m[1]
+	}
+	e.Domain = p.messageDomain(msg)
+
+	switch {
+	case failureRE.MatchString(msg):
+		e.Level = LevelError
+	case warningRE.MatchString(msg):
+		e.Level = LevelWarn
+	case e.Source != "" && strings.Count(msg, ": ") >= 2:
+		// The source messages providing a target and the error returned
+		e.Level = LevelError
+	}
+	return e
+}
+
+func (p *Parser) source(name string) string {
+	p.Lock()
+	defer p.Unlock()
+
+	if s, found := p.sources[strings.ToLower(strings.TrimSpace(name))]; found {
+		return s
+	}
+	return ""
+}
+
+// domain returns the root domain name of the provided name, or the name when not in scope.
+func (p *Parser) domain(name string) string {
+	name = strings.ToLower(strings.Trim(name, "."))
+
+	for _, d := range p.domains {
+		if name == d || strings.HasSuffix(name, "."+d) {
+			return d
+		}
+	}
+	return name
+}
+
+// messageDomain returns the first root domain name mentioned by the message.
+func (p *Parser) messageDomain(msg string) string {
+	for _, token := range strings.FieldsFunc(strings.ToLower(msg), func(r rune) bool {
+		return !(r == '.' || r == '-' || r == '_' || r == '*' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'))
+	}) {
+		token = strings.Trim(token, ".*")
+
+		for _, d := range p.domains {
+			if token == d || strings.HasSuffix(token, "."+d) {
+				return d
+			}
+		}
+	}
+	return ""
+}
+
+// JSONWriter is an io.Writer that encodes each log line written to it as an Entry.
+type JSONWriter struct {
+	sync.Mutex
+	w      io.Writer
+	parser *Parser
+}
+
+// NewJSONWriter returns a JSONWriter that writes the entries to w, one line of JSON each.
+// The parser can be nil when the data sources and domains are not known.
+func NewJSONWriter(w io.Writer, parser *Parser) *JSONWriter {
+	if parser == nil {
+		parser = NewParser(nil, nil)
+	}
+	return &JSONWriter{w: w, parser: parser}
+}
+
+// Write implements the io.Writer interface. Several lines written at once become several entries.
+func (j *JSONWriter) Write(b []byte) (int, error) {
+	j.Lock()
+	defer j.Unlock()
+
+	now := time.Now()
+	enc := json.NewEncoder(j.w)
+	for _, line := range strings.Split(string(b), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if err := enc.Encode(j.parser.Parse(line, now)); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
@@ -0,0 +1,82 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	p := NewParser([]string{"Crtsh"}, []string{"OWASP.org"})
+	p.SetSources("HackerTarget")
+
+	tests := []struct {
+		msg      string
+		expected Entry
+	}{
+		{
+			msg:      "Querying Crtsh for owasp.org subdomains",
+			expected: Entry{Level: LevelInfo, Source: "Crtsh", Domain: "owasp.org"},
+		},
+		{
+			msg:      "DNS wildcard detected: Resolver 8.8.8.8: *.dev.owasp.org",
+			expected: Entry{Level: LevelWarn, Resolver: "8.8.8.8", Domain: "owasp.org"},
+		},
+		{
+			msg:      "HackerTarget: https://api.hackertarget.com/hostsearch/?q=owasp.org: 429 Too Many Requests",
+			expected: Entry{Level: LevelError, Source: "HackerTarget", Domain: "owasp.org"},
+		},
+		{
+			msg:      "The resolver 1.1.1.1:53 failed to respond for www.owasp.org",
+			expected: Entry{Level: LevelError, Resolver: "1.1.1.1:53", Domain: "owasp.org"},
+		},
+		{
+			msg:      "Unknown: the message of another subsystem",
+			expected: Entry{Level: LevelInfo},
+		},
+	}
+
+	now := time.Now()
+	for _, test := range tests {
+		e := p.Parse(test.msg, now)
+
+		test.expected.Time = now.UTC()
+		test.expected.Message = test.msg
+		if *e != test.expected {
+			t.Errorf("Parse(%q) = %+v, expected %+v", test.msg, *e, test.expected)
+		}
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := NewJSONWriter(&buf, NewParser([]string{"Crtsh"}, []string{"owasp.org"}))
+	input := "Querying Crtsh for owasp.org subdomains\n\nCrtsh: owasp.org: unable to parse the response\n"
+	if n, err := w.Write([]byte(input)); err != nil || n != len(input) {
+		t.Fatalf("Write() = %d, %v", n, err)
+	}
+
+	var entries []*Entry
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("the line %q is not a valid entry: %v", scanner.Text(), err)
+		}
+		entries = append(entries, &e)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Write() provided %d entries, expected 2", len(entries))
+	}
+	if e := entries[1]; e.Level != LevelError || e.Source != "Crtsh" || e.Time.IsZero() {
+		t.Errorf("the second entry was %+v", e)
+	}
+}
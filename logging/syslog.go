@@ -0,0 +1,225 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The severities of the syslog messages
+const (
+	SeverityError  = 3
+	SeverityWarn   = 4
+	SeverityNotice = 5
+	SeverityInfo   = 6
+)
+
+const (
+	syslogAppName     = "amass"
+	syslogTimeFormat  = "2006-01-02T15:04:05.000000Z07:00"
+	syslogDialTimeout = 10 * time.Second
+	// The private enterprise number reserved for documentation by RFC 5612
+	syslogSDID = "amass@32473"
+)
+
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"authpriv": 10,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// Syslog sends RFC 5424 messages to a syslog endpoint over UDP, TCP or TLS. The messages sent over
+// the stream transports are framed using octet counting, as described by RFC 6587 and RFC 5425.
+type Syslog struct {
+	sync.Mutex
+	network  string
+	address  string
+	facility int
+	hostname string
+	conn     net.Conn
+}
+
+// DialSyslog connects to the endpoint at the URL, such as "udp://localhost:514", "tcp://localhost:601"
+// or "tls://localhost:6514". The facility is a name such as user, daemon or local0 (default user).
+func DialSyslog(rawurl, facility string) (*Syslog, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("the syslog URL %q is not valid", rawurl)
+	}
+
+	network := strings.ToLower(u.Scheme)
+	switch network {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("the syslog URL scheme must be udp, tcp or tls, not %q", u.Scheme)
+	}
+
+	address := u.Host
+	if u.Port() == "" {
+		port := "514"
+		switch network {
+		case "tcp":
+			port = "601"
+		case "tls":
+			port = "6514"
+		}
+		address = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	fac := syslogFacilities["user"]
+	if facility != "" {
+		f, found := syslogFacilities[strings.ToLower(facility)]
+		if !found {
+			return nil, fmt.Errorf("the syslog facility %q is not supported", facility)
+		}
+		fac = f
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	s := &Syslog{
+		network:  network,
+		address:  address,
+		facility: fac,
+		hostname: hostname,
+	}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// connect must be called while holding the lock.
+func (s *Syslog) connect() error {
+	var err error
+	var conn net.Conn
+
+	d := &net.Dialer{Timeout: syslogDialTimeout}
+	if s.network == "tls" {
+		conn, err = tls.DialWithDialer(d, "tcp", s.address, &tls.Config{MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = d.Dial(s.network, s.address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to the syslog endpoint %s: %v", s.address, err)
+	}
+
+	s.conn = conn
+	return nil
+}
+
+// Send delivers the message with the severity, message ID and structured data parameters.
+// The connection is established again once when the message cannot be written.
+func (s *Syslog) Send(t time.Time, severity int, msgid string, params map[string]string, msg string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	b := s.format(t, severity, msgid, params, msg)
+	if s.network != "udp" {
+		b = append([]byte(fmt.Sprintf("%d ", len(b))), b...)
+	}
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if err = s.connect(); err != nil {
+				continue
+			}
+		}
+		if _, err = s.conn.Write(b); err == nil {
+			return nil
+		}
+
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+func (s *Syslog) format(t time.Time, severity int, msgid string, params map[string]string, msg string) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %d %s ", s.facility*8+severity,
+		t.UTC().Format(syslogTimeFormat), s.hostname, syslogAppName, os.Getpid(), msgid)
+
+	var keys []string
+	for k, v := range params {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		b.WriteString("-")
+	} else {
+		sort.Strings(keys)
+
+		b.WriteString("[" + syslogSDID)
+		for _, k := range keys {
+			fmt.Fprintf(&b, ` %s="%s"`, k, sdEscaper.Replace(params[k]))
+		}
+		b.WriteString("]")
+	}
+
+	if msg != "" {
+		b.WriteString(" " + strings.TrimRight(msg, "\n"))
+	}
+	return []byte(b.String())
+}
+
+// The characters escaped within the structured data parameter values
+var sdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// SendEntry delivers the log entry, with the fields as structured data parameters.
+func (s *Syslog) SendEntry(e *Entry) error {
+	severity := SeverityInfo
+	switch e.Level {
+	case LevelWarn:
+		severity = SeverityWarn
+	case LevelError:
+		severity = SeverityError
+	}
+
+	return s.Send(e.Time, severity, "log", map[string]string{
+		"source":   e.Source,
+		"resolver": e.Resolver,
+		"domain":   e.Domain,
+	}, e.Message)
+}
+
+// Close releases the connection to the syslog endpoint.
+func (s *Syslog) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
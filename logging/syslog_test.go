@@ -0,0 +1,137 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDialSyslogSettings(t *testing.T) {
+	for _, test := range []struct {
+		url      string
+		facility string
+	}{
+		{url: "localhost:514"},
+		{url: "http://localhost:514"},
+		{url: "udp://localhost:514", facility: "local9"},
+	} {
+		if _, err := DialSyslog(test.url, test.facility); err == nil {
+			t.Errorf("DialSyslog(%q, %q) did not return an error", test.url, test.facility)
+		}
+	}
+}
+
+func TestSyslogUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the messages: %v", err)
+	}
+	defer pc.Close()
+
+	s, err := DialSyslog("udp://"+pc.LocalAddr().String(), "local3")
+	if err != nil {
+		t.Fatalf("DialSyslog() error = %v", err)
+	}
+	defer s.Close()
+
+	ts := time.Date(2023, time.March, 1, 12, 30, 0, 0, time.UTC)
+	if err := s.SendEntry(&Entry{
+		Time:    ts,
+		Level:   LevelError,
+		Source:  "Crtsh",
+		Domain:  "owasp.org",
+		Message: `Crtsh: "owasp.org" [failed]`,
+	}); err != nil {
+		t.Fatalf("Syslog.SendEntry() error = %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("failed to read the message: %v", err)
+	}
+	// local3 is facility 19 and errors are severity 3
+	prefix := "<155>1 2023-03-01T12:30:00.000000Z "
+	suffix := fmt.Sprintf(` amass %d log [amass@32473 domain="owasp.org" source="Crtsh"] Crtsh: "owasp.org" [failed]`, os.Getpid())
+	if msg := string(buf[:n]); !strings.HasPrefix(msg, prefix) || !strings.HasSuffix(msg, suffix) {
+		t.Errorf("the syslog message was %q", msg)
+	}
+}
+
+func TestSyslogTCPFraming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the connections: %v", err)
+	}
+	defer ln.Close()
+
+	msgs := make(chan string, 4)
+	go func() {
+		// The first connection is closed after a message to exercise the reconnection
+		for i := 0; i < 2; i++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			r := bufio.NewReader(conn)
+			for {
+				size, err := r.ReadString(' ')
+				if err != nil {
+					break
+				}
+				n, _ := strconv.Atoi(strings.TrimSpace(size))
+				b := make([]byte, n)
+				if _, err := io.ReadFull(r, b); err != nil {
+					break
+				}
+				msgs <- string(b)
+				if i == 0 {
+					break
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	s, err := DialSyslog("tcp://"+ln.Addr().String(), "")
+	if err != nil {
+		t.Fatalf("DialSyslog() error = %v", err)
+	}
+	defer s.Close()
+
+	if err := s.Send(time.Now(), SeverityInfo, "log", nil, "first\nmessage"); err != nil {
+		t.Fatalf("Syslog.Send() error = %v", err)
+	}
+	first := <-msgs
+	if !strings.HasPrefix(first, "<14>1 ") || !strings.HasSuffix(first, " log - first\nmessage") {
+		t.Errorf("the first syslog message was %q", first)
+	}
+
+	// Writes to the closed connection can succeed until the peer resets it
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		_ = s.Send(time.Now(), SeverityWarn, "log", nil, "second")
+
+		select {
+		case second := <-msgs:
+			if !strings.HasPrefix(second, "<12>1 ") {
+				t.Errorf("the second syslog message was %q", second)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	t.Errorf("the message was not sent after the connection was closed")
+}
@@ -0,0 +1,240 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package neo4j
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ArrayDelimiter separates the labels and array elements within the CSV fields. The unit separator
+// is not found in the values of the Amass graph and must be provided to neo4j-admin as U+001F.
+const ArrayDelimiter = "\x1f"
+
+// The names of the CSV files written for the neo4j-admin import tool
+const (
+	NodesFile         = "nodes.csv"
+	RelationshipsFile = "relationships.csv"
+)
+
+const (
+	idColumn    = "id:ID"
+	labelColumn = ":LABEL"
+	startColumn = ":START_ID"
+	endColumn   = ":END_ID"
+	typeColumn  = ":TYPE"
+)
+
+var neo4jTypes = map[string]struct{}{
+	"string": {}, "datetime": {}, "long": {}, "double": {}, "boolean": {},
+}
+
+// WriteCSV writes the nodes and relationships of the graph in the CSV format of the neo4j-admin import tool.
+func WriteCSV(nodes, rels io.Writer, g *Graph) error {
+	props := g.properties()
+
+	nw := csv.NewWriter(nodes)
+	header := []string{idColumn, labelColumn}
+	for _, p := range props {
+		col := p.name + ":" + p.typ
+		if p.array {
+			col += "[]"
+		}
+		header = append(header, col)
+	}
+	if err := nw.Write(header); err != nil {
+		return err
+	}
+
+	for _, n := range g.Nodes {
+		labels := Label
+		if n.Type != "" {
+			labels += ArrayDelimiter + n.Type
+		}
+
+		record := []string{n.ID, labels}
+		for _, p := range props {
+			var vals []string
+			for _, v := range n.Properties[p.name] {
+				vals = append(vals, csvValue(v, p.typ))
+			}
+			record = append(record, strings.Join(vals, ArrayDelimiter))
+		}
+		if err := nw.Write(record); err != nil {
+			return err
+		}
+	}
+	nw.Flush()
+	if err := nw.Error(); err != nil {
+		return err
+	}
+
+	rw := csv.NewWriter(rels)
+	if err := rw.Write([]string{startColumn, endColumn, typeColumn}); err != nil {
+		return err
+	}
+	for _, r := range g.Relationships {
+		if err := rw.Write([]string{r.Start, r.End, r.Type}); err != nil {
+			return err
+		}
+	}
+	rw.Flush()
+	return rw.Error()
+}
+
+// ReadCSV returns the graph read from the nodes and relationships in the CSV format written by WriteCSV.
+func ReadCSV(nodes, rels io.Reader) (*Graph, error) {
+	g := new(Graph)
+
+	nr := csv.NewReader(nodes)
+	header, err := nr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header of the nodes: %v", err)
+	}
+
+	idIdx, labelIdx := -1, -1
+	props := make(map[int]*property)
+	for i, col := range header {
+		switch col {
+		case idColumn:
+			idIdx = i
+		case labelColumn:
+			labelIdx = i
+		default:
+			props[i] = parseColumn(col)
+		}
+	}
+	if idIdx == -1 {
+		return nil, fmt.Errorf("the nodes do not provide the %s column", idColumn)
+	}
+
+	for {
+		record, err := nr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read the nodes: %v", err)
+		}
+
+		n := &Node{
+			ID:         record[idIdx],
+			Properties: make(map[string][]interface{}),
+		}
+		if labelIdx != -1 {
+			for _, l := range strings.Split(record[labelIdx], ArrayDelimiter) {
+				if l != "" && l != Label {
+					n.Type = l
+				}
+			}
+		}
+
+		for i, p := range props {
+			if record[i] == "" {
+				continue
+			}
+
+			vals := []string{record[i]}
+			if p.array {
+				vals = strings.Split(record[i], ArrayDelimiter)
+			}
+			for _, s := range vals {
+				v, err := parseValue(s, p.typ)
+				if err != nil {
+					return nil, fmt.Errorf("the %s property of node %s: %v", p.name, n.ID, err)
+				}
+				n.Properties[p.name] = append(n.Properties[p.name], v)
+			}
+		}
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	rr := csv.NewReader(rels)
+	header, err = rr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header of the relationships: %v", err)
+	}
+
+	cols := map[string]int{startColumn: -1, endColumn: -1, typeColumn: -1}
+	for i, col := range header {
+		if _, found := cols[col]; found {
+			cols[col] = i
+		}
+	}
+	for col, idx := range cols {
+		if idx == -1 {
+			return nil, fmt.Errorf("the relationships do not provide the %s column", col)
+		}
+	}
+
+	for {
+		record, err := rr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read the relationships: %v", err)
+		}
+
+		g.Relationships = append(g.Relationships, &Relationship{
+			Start: record[cols[startColumn]],
+			End:   record[cols[endColumn]],
+			Type:  record[cols[typeColumn]],
+		})
+	}
+
+	if len(g.Nodes) == 0 {
+		return nil, errors.New("no nodes were read")
+	}
+	return g, nil
+}
+
+// parseColumn returns the property described by a column header in the form of name:type[].
+func parseColumn(col string) *property {
+	p := &property{name: col, typ: "string"}
+
+	if idx := strings.LastIndex(col, ":"); idx != -1 {
+		typ := strings.ToLower(col[idx+1:])
+		if strings.HasSuffix(typ, "[]") {
+			typ = strings.TrimSuffix(typ, "[]")
+			p.array = true
+		}
+		if _, found := neo4jTypes[typ]; found {
+			p.name = col[:idx]
+			p.typ = typ
+		} else {
+			p.array = false
+		}
+	}
+	return p
+}
+
+func csvValue(v interface{}, typ string) string {
+	if valueType(v) != typ {
+		return fmt.Sprint(v)
+	}
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+func parseValue(s, typ string) (interface{}, error) {
+	switch typ {
+	case "datetime":
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return t.UTC(), err
+	case "long":
+		return strconv.ParseInt(s, 10, 64)
+	case "double":
+		return strconv.ParseFloat(s, 64)
+	case "boolean":
+		return strconv.ParseBool(s)
+	}
+	return s, nil
+}
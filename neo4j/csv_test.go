@@ -0,0 +1,95 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package neo4j
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+	expected := NewGraph(testQuads(t, g))
+
+	nodes, rels := new(bytes.Buffer), new(bytes.Buffer)
+	if err := WriteCSV(nodes, rels, expected); err != nil {
+		t.Fatalf("failed to write the CSV files: %v", err)
+	}
+
+	header := strings.SplitN(nodes.String(), "\n", 2)[0]
+	for _, col := range []string{"id:ID", ":LABEL", "description:string", "start:datetime", "http:string"} {
+		if !strings.Contains(header, col) {
+			t.Errorf("the header %q does not contain the %s column", header, col)
+		}
+	}
+	if !strings.HasPrefix(rels.String(), ":START_ID,:END_ID,:TYPE\n") {
+		t.Errorf("unexpected header of the relationships: %q", rels.String())
+	}
+
+	got, err := ReadCSV(nodes, rels)
+	if err != nil {
+		t.Fatalf("failed to read the CSV files: %v", err)
+	}
+	if !reflect.DeepEqual(got.Nodes, expected.Nodes) {
+		t.Errorf("the nodes read do not match the nodes written")
+	}
+	if !reflect.DeepEqual(got.Relationships, expected.Relationships) {
+		t.Errorf("the relationships read do not match the relationships written")
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	cases := []struct {
+		label string
+		nodes string
+		rels  string
+	}{
+		{
+			label: "missing ID column",
+			nodes: ":LABEL\nAmass\n",
+			rels:  ":START_ID,:END_ID,:TYPE\n",
+		},
+		{
+			label: "missing type column",
+			nodes: "id:ID,:LABEL\nowasp.org,Amass\n",
+			rels:  ":START_ID,:END_ID\n",
+		},
+		{
+			label: "invalid datetime",
+			nodes: "id:ID,:LABEL,start:datetime\nevent,Amass,yesterday\n",
+			rels:  ":START_ID,:END_ID,:TYPE\n",
+		},
+		{
+			label: "no nodes",
+			nodes: "id:ID,:LABEL\n",
+			rels:  ":START_ID,:END_ID,:TYPE\n",
+		},
+	}
+
+	for _, c := range cases {
+		if _, err := ReadCSV(strings.NewReader(c.nodes), strings.NewReader(c.rels)); err == nil {
+			t.Errorf("%s: expected an error", c.label)
+		}
+	}
+}
+
+func TestParseColumn(t *testing.T) {
+	cases := map[string]property{
+		"description":      {name: "description", typ: "string"},
+		"start:datetime":   {name: "start", typ: "datetime"},
+		"http:string[]":    {name: "http", typ: "string", array: true},
+		"port:LONG":        {name: "port", typ: "long"},
+		"urn:uuid:unknown": {name: "urn:uuid:unknown", typ: "string"},
+	}
+
+	for col, expected := range cases {
+		if got := parseColumn(col); *got != expected {
+			t.Errorf("expected %+v for %s, got %+v", expected, col, *got)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package neo4j
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteCypher writes the Cypher statements that merge the nodes and relationships of the
+// graph into a Neo4j database, such as by providing the output to cypher-shell.
+func WriteCypher(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "CREATE CONSTRAINT amass_id IF NOT EXISTS FOR (n:%s) REQUIRE n.id IS UNIQUE;\n", Label)
+
+	props := g.properties()
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "MERGE (n:%s {id: %s})", Label, cypherString(n.ID))
+		if n.Type != "" {
+			fmt.Fprintf(bw, " SET n:%s", cypherName(n.Type))
+		}
+
+		var sets []string
+		for _, p := range props {
+			vals, found := n.Properties[p.name]
+			if !found {
+				continue
+			}
+
+			var v string
+			if p.array {
+				var list []string
+				for _, val := range vals {
+					list = append(list, cypherValue(val, p.typ))
+				}
+				v = "[" + strings.Join(list, ", ") + "]"
+			} else {
+				v = cypherValue(vals[0], p.typ)
+			}
+			sets = append(sets, "n."+cypherName(p.name)+" = "+v)
+		}
+		if len(sets) > 0 {
+			fmt.Fprintf(bw, " SET %s", strings.Join(sets, ", "))
+		}
+		fmt.Fprintln(bw, ";")
+	}
+
+	for _, r := range g.Relationships {
+		fmt.Fprintf(bw, "MATCH (a:%s {id: %s}), (b:%s {id: %s}) MERGE (a)-[:%s]->(b);\n",
+			Label, cypherString(r.Start), Label, cypherString(r.End), cypherName(r.Type))
+	}
+	return bw.Flush()
+}
+
+func cypherValue(v interface{}, typ string) string {
+	if valueType(v) != typ {
+		return cypherString(fmt.Sprint(v))
+	}
+
+	switch val := v.(type) {
+	case time.Time:
+		return "datetime(" + cypherString(val.Format(time.RFC3339Nano)) + ")"
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case string:
+		return cypherString(val)
+	}
+	return cypherString(fmt.Sprint(v))
+}
+
+// cypherString returns the Cypher string literal for the value.
+func cypherString(s string) string {
+	var b strings.Builder
+
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// cypherName returns the quoted Cypher name for the label, relationship type or property key.
+func cypherName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
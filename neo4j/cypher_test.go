@@ -0,0 +1,80 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package neo4j
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCypher(t *testing.T) {
+	g := &Graph{
+		Nodes: []*Node{
+			{
+				ID:   "www.owasp.org",
+				Type: "fqdn",
+				Properties: map[string][]interface{}{
+					"http": {`{"url":"https://www.owasp.org"}`, `{"url":"http://www.owasp.org"}`},
+				},
+			},
+			{
+				ID:   "26808",
+				Type: "as",
+				Properties: map[string][]interface{}{
+					"description": {"UTORONTO-AS, \"CA\""},
+				},
+			},
+			{
+				ID:   "event",
+				Type: "event",
+				Properties: map[string][]interface{}{
+					"start": {time.Date(2023, 4, 1, 12, 30, 0, 0, time.UTC)},
+				},
+			},
+		},
+		Relationships: []*Relationship{
+			{Start: "event", End: "www.owasp.org", Type: "DNS"},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WriteCypher(buf, g); err != nil {
+		t.Fatalf("failed to write the Cypher statements: %v", err)
+	}
+	out := buf.String()
+
+	for _, expected := range []string{
+		"CREATE CONSTRAINT amass_id IF NOT EXISTS FOR (n:Amass) REQUIRE n.id IS UNIQUE;\n",
+		"MERGE (n:Amass {id: \"www.owasp.org\"}) SET n:`fqdn` SET n.`http` = [\"{\\\"url\\\":\\\"https://www.owasp.org\\\"}\", \"{\\\"url\\\":\\\"http://www.owasp.org\\\"}\"];\n",
+		"MERGE (n:Amass {id: \"26808\"}) SET n:`as` SET n.`description` = \"UTORONTO-AS, \\\"CA\\\"\";\n",
+		"MERGE (n:Amass {id: \"event\"}) SET n:`event` SET n.`start` = datetime(\"2023-04-01T12:30:00Z\");\n",
+		"MATCH (a:Amass {id: \"event\"}), (b:Amass {id: \"www.owasp.org\"}) MERGE (a)-[:`DNS`]->(b);\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("the Cypher statements do not contain %q:\n%s", expected, out)
+		}
+	}
+}
+
+func TestCypherString(t *testing.T) {
+	cases := map[string]string{
+		"plain":          `"plain"`,
+		`quote " and \`:  `"quote \" and \\"`,
+		"line\nbreak\t":  `"line\nbreak\t"`,
+		"control\x01chr": `"control\u0001chr"`,
+	}
+
+	for input, expected := range cases {
+		if got := cypherString(input); got != expected {
+			t.Errorf("expected %s for %q, got %s", expected, input, got)
+		}
+	}
+
+	if got := cypherName("back`tick"); got != "`back``tick`" {
+		t.Errorf("unexpected name quoting: %s", got)
+	}
+}
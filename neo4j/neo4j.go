@@ -0,0 +1,236 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package neo4j converts the Amass graph into the property graph model of Neo4j, where the
+// node types become labels, the edge predicates become relationship types and the remaining
+// quads become node properties.
+package neo4j
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cayleygraph/quad"
+)
+
+// Label is assigned to all the nodes exported from the Amass graph, in addition to the node type.
+const Label = "Amass"
+
+// The predicate of the quads providing the types of the nodes
+const typePredicate = "type"
+
+// Node is a node of the property graph, identified by the ID of the Amass graph node.
+type Node struct {
+	ID         string
+	Type       string
+	Properties map[string][]interface{}
+}
+
+// Relationship is the directed relationship between the nodes identified by Start and End.
+type Relationship struct {
+	Start, End string
+	Type       string
+}
+
+// Graph is the property graph representation of the Amass graph.
+type Graph struct {
+	Nodes         []*Node
+	Relationships []*Relationship
+}
+
+// The Neo4j type and cardinality of a property shared by the nodes
+type property struct {
+	name  string
+	typ   string
+	array bool
+}
+
+// NewGraph returns the property graph built from the quads of the Amass graph.
+func NewGraph(quads []quad.Quad) *Graph {
+	g := new(Graph)
+	nodes := make(map[string]*Node)
+	rels := make(map[Relationship]struct{})
+
+	node := func(id string) *Node {
+		n, found := nodes[id]
+		if !found {
+			n = &Node{
+				ID:         id,
+				Properties: make(map[string][]interface{}),
+			}
+			nodes[id] = n
+			g.Nodes = append(g.Nodes, n)
+		}
+		return n
+	}
+
+	for _, q := range quads {
+		subject := valToStr(q.Subject)
+		predicate := valToStr(q.Predicate)
+		if subject == "" || predicate == "" || q.Object == nil {
+			continue
+		}
+
+		n := node(subject)
+		if iri, ok := q.Object.(quad.IRI); ok {
+			r := Relationship{Start: subject, End: valToStr(iri), Type: predicate}
+			if _, found := rels[r]; !found {
+				rels[r] = struct{}{}
+				node(r.End)
+				g.Relationships = append(g.Relationships, &r)
+			}
+			continue
+		}
+
+		val := nativeValue(q.Object)
+		if predicate == typePredicate {
+			if s, ok := val.(string); ok {
+				n.Type = s
+			}
+			continue
+		}
+		n.Properties[predicate] = append(n.Properties[predicate], val)
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.Slice(g.Relationships, func(i, j int) bool {
+		a, b := g.Relationships[i], g.Relationships[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.End < b.End
+	})
+	return g
+}
+
+// Quads returns the quads of the Amass graph represented by the property graph.
+func (g *Graph) Quads() []quad.Quad {
+	var quads []quad.Quad
+
+	for _, n := range g.Nodes {
+		if n.Type != "" {
+			quads = append(quads, quad.Make(quad.IRI(n.ID), quad.IRI(typePredicate), quad.String(n.Type), quad.IRI(n.Type)))
+		}
+
+		names := make([]string, 0, len(n.Properties))
+		for name := range n.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			for _, v := range n.Properties[name] {
+				quads = append(quads, quad.Make(quad.IRI(n.ID), quad.IRI(name), quadValue(v), nil))
+			}
+		}
+	}
+
+	for _, r := range g.Relationships {
+		quads = append(quads, quad.Make(quad.IRI(r.Start), quad.IRI(r.Type), quad.IRI(r.End), nil))
+	}
+	return quads
+}
+
+// properties returns the properties of the nodes ordered by name, where a property has the type of its
+// values, or string when the values have different types, and is an array when a node has many values.
+func (g *Graph) properties() []*property {
+	props := make(map[string]*property)
+
+	for _, n := range g.Nodes {
+		for name, vals := range n.Properties {
+			p, found := props[name]
+			if !found {
+				p = &property{name: name}
+				props[name] = p
+			}
+			if len(vals) > 1 {
+				p.array = true
+			}
+
+			for _, v := range vals {
+				if t := valueType(v); p.typ == "" {
+					p.typ = t
+				} else if p.typ != t {
+					p.typ = "string"
+				}
+			}
+		}
+	}
+
+	var results []*property
+	for _, p := range props {
+		results = append(results, p)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].name < results[j].name
+	})
+	return results
+}
+
+func valToStr(v quad.Value) string {
+	switch val := v.(type) {
+	case quad.IRI:
+		return strings.TrimRight(strings.TrimLeft(string(val), "<"), ">")
+	case quad.String:
+		return string(val)
+	}
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func nativeValue(v quad.Value) interface{} {
+	switch val := v.(type) {
+	case quad.String:
+		return string(val)
+	case quad.Time:
+		return time.Time(val).UTC()
+	case quad.Int:
+		return int64(val)
+	case quad.Float:
+		return float64(val)
+	case quad.Bool:
+		return bool(val)
+	}
+	return v.String()
+}
+
+func quadValue(v interface{}) quad.Value {
+	switch val := v.(type) {
+	case time.Time:
+		return quad.Time(val)
+	case int64:
+		return quad.Int(val)
+	case float64:
+		return quad.Float(val)
+	case bool:
+		return quad.Bool(val)
+	case string:
+		return quad.String(val)
+	}
+	return quad.String(fmt.Sprint(v))
+}
+
+// valueType returns the name of the Neo4j type for the property value.
+func valueType(v interface{}) string {
+	switch v.(type) {
+	case time.Time:
+		return "datetime"
+	case int64:
+		return "long"
+	case float64:
+		return "double"
+	case bool:
+		return "boolean"
+	}
+	return "string"
+}
@@ -0,0 +1,83 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package neo4j
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/cayleygraph/quad"
+)
+
+const testUUID = "ef9f9475-34eb-465e-81ec-9a1f7f8d2f5b"
+
+func testGraph(t *testing.T) *netmap.Graph {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+
+	if err := g.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", testUUID); err != nil {
+		t.Fatalf("failed to insert the A record: %v", err)
+	}
+	if err := g.UpsertInfrastructure(ctx, 26808, "UTORONTO-AS, CA", "192.168.1.1", "192.168.1.0/24", "RIR", testUUID); err != nil {
+		t.Fatalf("failed to insert the infrastructure: %v", err)
+	}
+	if err := g.UpsertProperty(ctx, netmap.Node("www.owasp.org"), "http", `{"url":"https://www.owasp.org"}`); err != nil {
+		t.Fatalf("failed to insert the property: %v", err)
+	}
+	return g
+}
+
+func testQuads(t *testing.T, g *netmap.Graph) []quad.Quad {
+	quads, err := g.ReadEventQuads(context.Background(), testUUID)
+	if err != nil {
+		t.Fatalf("failed to read the event quads: %v", err)
+	}
+	return quads
+}
+
+func TestNewGraph(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	pg := NewGraph(testQuads(t, g))
+	nodes := make(map[string]*Node)
+	for _, n := range pg.Nodes {
+		nodes[n.ID] = n
+	}
+
+	expected := map[string]string{
+		"www.owasp.org":  netmap.TypeFQDN,
+		"192.168.1.1":    netmap.TypeAddr,
+		"192.168.1.0/24": netmap.TypeNetblock,
+		"26808":          netmap.TypeAS,
+		testUUID:         netmap.TypeEvent,
+	}
+	for id, typ := range expected {
+		if n, found := nodes[id]; !found || n.Type != typ {
+			t.Errorf("expected the %s node with the %s label, got %+v", id, typ, n)
+		}
+	}
+
+	if vals := nodes["26808"].Properties["description"]; len(vals) != 1 || vals[0] != "UTORONTO-AS, CA" {
+		t.Errorf("unexpected description of the AS node: %v", vals)
+	}
+	if vals := nodes[testUUID].Properties["start"]; len(vals) != 1 {
+		t.Errorf("expected the start time of the event, got %v", vals)
+	} else if _, ok := vals[0].(time.Time); !ok {
+		t.Errorf("expected the start of the event to be a time, got %T", vals[0])
+	}
+
+	var found bool
+	for _, r := range pg.Relationships {
+		if r.Start == "www.owasp.org" && r.End == "192.168.1.1" && r.Type == "a_record" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("the a_record relationship was not found")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Audit is the global option for recording the external endpoints contacted, and refusing
+// the connections toward the hosts owned by the target. It is nil unless a strict mode was selected.
+var Audit *EndpointAudit
+
+// AuditEntry is an external endpoint, along with the number of times it was contacted or refused.
+type AuditEntry struct {
+	Protocol string `json:"protocol"`
+	Endpoint string `json:"endpoint"`
+	Count    int    `json:"count"`
+}
+
+type auditKey struct {
+	protocol string
+	endpoint string
+}
+
+// EndpointAudit keeps track of the external endpoints contacted over the network.
+type EndpointAudit struct {
+	sync.Mutex
+	blocked   func(host string) bool
+	contacted map[auditKey]int
+	refused   map[auditKey]int
+}
+
+// NewEndpointAudit returns an EndpointAudit refusing the endpoints with the hosts matched by the blocked function.
+func NewEndpointAudit(blocked func(host string) bool) *EndpointAudit {
+	return &EndpointAudit{
+		blocked:   blocked,
+		contacted: make(map[auditKey]int),
+		refused:   make(map[auditKey]int),
+	}
+}
+
+// Allow records the endpoint contacted over the protocol and returns an error when the endpoint must
+// not be contacted. The endpoint can be a host, or a host and port, and all endpoints are allowed by a nil audit.
+func (a *EndpointAudit) Allow(protocol, endpoint string) error {
+	if a == nil {
+		return nil
+	}
+
+	host := endpoint
+	if h, _, err := net.SplitHostPort(endpoint); err == nil {
+		host = h
+	}
+	host = strings.Trim(strings.ToLower(host), ".")
+	key := auditKey{protocol: protocol, endpoint: strings.ToLower(endpoint)}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if a.blocked != nil && a.blocked(host) {
+		a.refused[key]++
+		return fmt.Errorf("the %s connection to %s was refused, since the host belongs to the target", protocol, endpoint)
+	}
+	a.contacted[key]++
+	return nil
+}
+
+// Contacted returns the endpoints contacted, sorted by protocol and endpoint.
+func (a *EndpointAudit) Contacted() []*AuditEntry {
+	a.Lock()
+	defer a.Unlock()
+
+	return auditEntries(a.contacted)
+}
+
+// Refused returns the endpoints that were refused, sorted by protocol and endpoint.
+func (a *EndpointAudit) Refused() []*AuditEntry {
+	a.Lock()
+	defer a.Unlock()
+
+	return auditEntries(a.refused)
+}
+
+func auditEntries(m map[auditKey]int) []*AuditEntry {
+	var entries []*AuditEntry
+
+	for k, count := range m {
+		entries = append(entries, &AuditEntry{
+			Protocol: k.protocol,
+			Endpoint: k.endpoint,
+			Count:    count,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Protocol != entries[j].Protocol {
+			return entries[i].Protocol < entries[j].Protocol
+		}
+		return entries[i].Endpoint < entries[j].Endpoint
+	})
+	return entries
+}
@@ -0,0 +1,51 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEndpointAudit(t *testing.T) {
+	var none *EndpointAudit
+	if err := none.Allow("tcp", "www.example.com:443"); err != nil {
+		t.Errorf("a nil audit refused the endpoint: %v", err)
+	}
+
+	a := NewEndpointAudit(func(host string) bool {
+		return host == "example.com" || strings.HasSuffix(host, ".example.com")
+	})
+
+	if err := a.Allow("https", "crt.sh"); err != nil {
+		t.Errorf("the audit refused crt.sh: %v", err)
+	}
+	if err := a.Allow("tcp", "crt.sh:443"); err != nil {
+		t.Errorf("the audit refused crt.sh:443: %v", err)
+	}
+	if err := a.Allow("https", "CRT.SH"); err != nil {
+		t.Errorf("the audit refused CRT.SH: %v", err)
+	}
+	if err := a.Allow("tcp", "www.example.com:80"); err == nil {
+		t.Error("the audit did not refuse the endpoint owned by the target")
+	}
+	if err := a.Allow("dns", "Example.com."); err == nil {
+		t.Error("the audit did not refuse the name owned by the target")
+	}
+
+	contacted := a.Contacted()
+	if len(contacted) != 2 {
+		t.Fatalf("the audit returned %d contacted endpoints, expected 2", len(contacted))
+	}
+	if e := contacted[0]; e.Protocol != "https" || e.Endpoint != "crt.sh" || e.Count != 2 {
+		t.Errorf("the first contacted endpoint was %+v", e)
+	}
+	if e := contacted[1]; e.Protocol != "tcp" || e.Endpoint != "crt.sh:443" || e.Count != 1 {
+		t.Errorf("the second contacted endpoint was %+v", e)
+	}
+	if refused := a.Refused(); len(refused) != 2 || refused[0].Protocol != "dns" {
+		t.Errorf("the audit returned the refused endpoints %+v", refused)
+	}
+}
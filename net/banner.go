@@ -0,0 +1,120 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxBannerLen int = 256
+
+// The X.224 Connection Request carrying an RDP Negotiation Request for TLS and CredSSP
+var rdpNegRequest = []byte{
+	0x03, 0x00, 0x00, 0x13, // TPKT header
+	0x0e, 0xe0, 0x00, 0x00, 0x00, 0x00, 0x00, // X.224 Connection Request
+	0x01, 0x00, 0x08, 0x00, 0x03, 0x00, 0x00, 0x00, // RDP Negotiation Request
+}
+
+var rdpProtocols = map[uint32]string{
+	0: "Standard RDP Security",
+	1: "TLS",
+	2: "CredSSP",
+	4: "RDSTLS",
+	8: "CredSSP with Early User Authorization",
+}
+
+// GrabBanner connects to the port on the address and returns the banner presented by the service,
+// such as the greetings of SSH, SMTP and FTP servers. Services that wait for the client to speak
+// first are sent an RDP negotiation request. An empty banner is returned for HTTP services and
+// services that do not respond.
+func GrabBanner(ctx context.Context, addr string, port int, timeout time.Duration) (string, error) {
+	conn, err := scanDialer(timeout).DialContext(ctx, "tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s on port %d: %v", addr, port, err)
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	// Stop waiting on the service when the context is cancelled
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	buf := make([]byte, 1024)
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if n, err := conn.Read(buf); n > 0 {
+		return cleanBanner(buf[:n]), nil
+	} else if e, ok := err.(net.Error); !ok || !e.Timeout() {
+		return "", nil
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(rdpNegRequest); err != nil {
+		return "", nil
+	}
+	n, _ := conn.Read(buf)
+	if n == 0 {
+		return "", nil
+	}
+	if banner, err := rdpBanner(buf[:n]); err == nil {
+		return banner, nil
+	}
+	return cleanBanner(buf[:n]), nil
+}
+
+// cleanBanner returns the first line of the banner without non-printable characters.
+func cleanBanner(b []byte) string {
+	line := strings.SplitN(string(b), "\n", 2)[0]
+	line = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return -1
+		}
+		return r
+	}, line))
+
+	// The HTTP services are described by the probes
+	if strings.HasPrefix(line, "HTTP/") {
+		return ""
+	}
+	if len(line) > maxBannerLen {
+		line = line[:maxBannerLen]
+	}
+	return line
+}
+
+// rdpBanner describes the X.224 Connection Confirm received in response to the RDP negotiation request.
+func rdpBanner(b []byte) (string, error) {
+	// The TPKT header is followed by the X.224 Connection Confirm
+	if len(b) < 11 || b[0] != 0x03 || b[1] != 0x00 || b[5]&0xf0 != 0xd0 {
+		return "", errors.New("the response is not an X.224 Connection Confirm")
+	}
+	if len(b) < 19 {
+		return "RDP", nil
+	}
+
+	val := binary.LittleEndian.Uint32(b[15:19])
+	switch b[11] {
+	case 0x02:
+		if proto, found := rdpProtocols[val]; found {
+			return "RDP (" + proto + ")", nil
+		}
+		return fmt.Sprintf("RDP (protocol %d)", val), nil
+	case 0x03:
+		return fmt.Sprintf("RDP (negotiation failure %d)", val), nil
+	}
+	return "RDP", nil
+}
@@ -0,0 +1,76 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func bannerServer(t *testing.T, handle func(net.Conn)) (string, int) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start the listener: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			handle(conn)
+			conn.Close()
+		}
+	}()
+
+	addr := l.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestGrabBanner(t *testing.T) {
+	addr, port := bannerServer(t, func(conn net.Conn) {
+		_, _ = conn.Write([]byte("SSH-2.0-OpenSSH_8.9p1 Ubuntu-3ubuntu0.1\r\n"))
+	})
+
+	banner, err := GrabBanner(context.Background(), addr, port, time.Second)
+	if err != nil || banner != "SSH-2.0-OpenSSH_8.9p1 Ubuntu-3ubuntu0.1" {
+		t.Errorf("GrabBanner() = %q, %v", banner, err)
+	}
+}
+
+func TestGrabBannerRDP(t *testing.T) {
+	addr, port := bannerServer(t, func(conn net.Conn) {
+		buf := make([]byte, len(rdpNegRequest))
+		if _, err := conn.Read(buf); err != nil || !bytes.Equal(buf, rdpNegRequest) {
+			return
+		}
+		_, _ = conn.Write([]byte{0x03, 0x00, 0x00, 0x13, 0x0e, 0xd0, 0x00, 0x00,
+			0x12, 0x34, 0x00, 0x02, 0x1f, 0x08, 0x00, 0x02, 0x00, 0x00, 0x00})
+	})
+
+	banner, err := GrabBanner(context.Background(), addr, port, 250*time.Millisecond)
+	if err != nil || banner != "RDP (CredSSP)" {
+		t.Errorf("GrabBanner() = %q, %v", banner, err)
+	}
+}
+
+func TestCleanBanner(t *testing.T) {
+	tests := map[string]string{
+		"220 mail.owasp.org ESMTP Postfix\r\n250 OK\r\n": "220 mail.owasp.org ESMTP Postfix",
+		"220 \x00(vsFTPd 3.0.3)\x7f\r\n":                 "220 (vsFTPd 3.0.3)",
+		"HTTP/1.1 400 Bad Request\r\n":                   "",
+	}
+
+	for in, want := range tests {
+		if got := cleanBanner([]byte(in)); got != want {
+			t.Errorf("cleanBanner(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
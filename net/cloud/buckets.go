@@ -0,0 +1,156 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloud
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/owasp-amass/amass/v3/net/http"
+)
+
+// TypeBucket is the graph node type used for cloud storage buckets.
+const TypeBucket = "bucket"
+
+// The access levels determined by the unauthenticated bucket probes
+const (
+	AccessPrivate = "private"
+	AccessPublic  = "public"
+)
+
+// Common words combined with the keywords when guessing bucket names
+var bucketSuffixes = []string{
+	"assets", "backup", "backups", "data", "dev", "files", "images", "logs",
+	"media", "prod", "public", "staging", "static", "test", "uploads", "www",
+}
+
+var (
+	bucketNameRE  = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+	storageNameRE = regexp.MustCompile(`^[a-z0-9]{3,24}$`)
+)
+
+// Bucket is a cloud storage bucket identified by the probes.
+type Bucket struct {
+	Provider string
+	Name     string
+	URL      string
+	Access   string
+}
+
+type bucketProbe struct {
+	provider string
+	valid    *regexp.Regexp
+	url      func(name string) string
+	check    func(resp *http.Response) (bool, string)
+}
+
+var bucketProbes = []*bucketProbe{
+	{
+		provider: AWS,
+		valid:    bucketNameRE,
+		url:      func(name string) string { return "https://s3.amazonaws.com/" + name + "/" },
+		check:    checkXMLStorage("NoSuchBucket"),
+	},
+	{
+		provider: GCP,
+		valid:    bucketNameRE,
+		url:      func(name string) string { return "https://storage.googleapis.com/" + name + "/" },
+		check:    checkXMLStorage("NoSuchBucket"),
+	},
+	{
+		provider: Azure,
+		valid:    storageNameRE,
+		url:      func(name string) string { return "https://" + name + ".blob.core.windows.net/?comp=list" },
+		// Any response shows that the storage account name resolves and is in use
+		check: func(resp *http.Response) (bool, string) {
+			if resp.StatusCode == 200 && strings.Contains(resp.Body, "<EnumerationResults") {
+				return true, AccessPublic
+			}
+			return true, AccessPrivate
+		},
+	},
+}
+
+// ProbeBuckets checks each storage provider for a bucket with the provided name,
+// using unauthenticated requests, and returns the buckets found.
+func ProbeBuckets(ctx context.Context, name string) []*Bucket {
+	var results []*Bucket
+
+	for _, p := range bucketProbes {
+		if !p.valid.MatchString(name) {
+			continue
+		}
+
+		u := p.url(name)
+		resp, err := http.RequestWebPage(ctx, &http.Request{URL: u})
+		if err != nil {
+			continue
+		}
+
+		if found, access := p.check(resp); found {
+			results = append(results, &Bucket{
+				Provider: p.provider,
+				Name:     name,
+				URL:      u,
+				Access:   access,
+			})
+		}
+	}
+	return results
+}
+
+func checkXMLStorage(missing string) func(resp *http.Response) (bool, string) {
+	return func(resp *http.Response) (bool, string) {
+		switch {
+		case resp.StatusCode == 404 || strings.Contains(resp.Body, missing):
+			return false, ""
+		case resp.StatusCode == 200 && strings.Contains(resp.Body, "<ListBucketResult"):
+			return true, AccessPublic
+		case resp.StatusCode == 301 || resp.StatusCode == 401 || resp.StatusCode == 403:
+			return true, AccessPrivate
+		}
+		return false, ""
+	}
+}
+
+// BucketCandidates returns the bucket names derived from the keywords, combined with
+// the labels when provided, and with common words used in bucket names otherwise.
+func BucketCandidates(keywords []string, labels ...string) []string {
+	seen := make(map[string]struct{})
+	var results []string
+
+	add := func(name string) {
+		if _, found := seen[name]; !found && bucketNameRE.MatchString(name) {
+			seen[name] = struct{}{}
+			results = append(results, name)
+		}
+	}
+
+	for _, k := range keywords {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" {
+			continue
+		}
+
+		words := labels
+		if len(labels) == 0 {
+			add(k)
+			words = bucketSuffixes
+		}
+
+		for _, w := range words {
+			w = strings.ToLower(strings.TrimSpace(w))
+			if w == "" || w == k || strings.HasPrefix(w, "-") || strings.HasSuffix(w, "-") {
+				continue
+			}
+
+			add(k + "-" + w)
+			add(w + "-" + k)
+			add(k + w)
+		}
+	}
+	return results
+}
@@ -0,0 +1,51 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloud
+
+import (
+	"testing"
+
+	"github.com/owasp-amass/amass/v3/net/http"
+)
+
+func TestBucketCandidates(t *testing.T) {
+	names := BucketCandidates([]string{"owasp"}, "dev", "owasp", "-bad")
+
+	expected := map[string]bool{"owasp-dev": false, "dev-owasp": false, "owaspdev": false}
+	for _, name := range names {
+		if _, found := expected[name]; !found {
+			t.Errorf("BucketCandidates() returned the unexpected name %s", name)
+		}
+		expected[name] = true
+	}
+	for name, found := range expected {
+		if !found {
+			t.Errorf("BucketCandidates() did not return %s", name)
+		}
+	}
+
+	if names := BucketCandidates([]string{"owasp"}); len(names) != 1+3*len(bucketSuffixes) {
+		t.Errorf("BucketCandidates() returned %d names for the keyword alone", len(names))
+	}
+}
+
+func TestCheckXMLStorage(t *testing.T) {
+	check := checkXMLStorage("NoSuchBucket")
+	tests := []struct {
+		resp   *http.Response
+		found  bool
+		access string
+	}{
+		{&http.Response{StatusCode: 404, Body: "<Code>NoSuchBucket</Code>"}, false, ""},
+		{&http.Response{StatusCode: 200, Body: "<ListBucketResult>"}, true, AccessPublic},
+		{&http.Response{StatusCode: 403, Body: "<Code>AccessDenied</Code>"}, true, AccessPrivate},
+	}
+
+	for _, test := range tests {
+		if found, access := check(test.resp); found != test.found || access != test.access {
+			t.Errorf("checkXMLStorage() returned %t and %s for status %d", found, access, test.resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloud
+
+import "strings"
+
+// The CNAME target suffixes that identify names served by content delivery networks
+var cdnSuffixes = map[string]string{
+	"akamai.net":         Akamai,
+	"akamaiedge.net":     Akamai,
+	"akamaihd.net":       Akamai,
+	"akamaized.net":      Akamai,
+	"edgekey.net":        Akamai,
+	"edgesuite.net":      Akamai,
+	"azureedge.net":      Azure,
+	"azurefd.net":        Azure,
+	"b-cdn.net":          "BunnyCDN",
+	"cdn.cloudflare.net": Cloudflare,
+	"cdn77.org":          "CDN77",
+	"cdngc.net":          "CDNetworks",
+	"cloudfront.net":     AWS,
+	"edgecastcdn.net":    "Edgecast",
+	"fastly.net":         Fastly,
+	"fastlylb.net":       Fastly,
+	"impervadns.net":     "Imperva",
+	"incapdns.net":       "Imperva",
+	"kxcdn.com":          "KeyCDN",
+	"llnwd.net":          "Limelight",
+	"stackpathdns.com":   "StackPath",
+}
+
+// The services published by providers for their content delivery network ranges
+var cdnServices = map[string]struct{}{
+	"cdn":                     {},
+	"cloudfront":              {},
+	"azurefrontdoor.frontend": {},
+	"azurecdn":                {},
+}
+
+// IsCDN returns true when the range belongs to a content delivery network.
+func IsCDN(rng *Range) bool {
+	if rng == nil {
+		return false
+	}
+
+	switch rng.Provider {
+	case Akamai, Cloudflare, Fastly:
+		return true
+	}
+
+	_, found := cdnServices[strings.ToLower(rng.Service)]
+	return found
+}
+
+// CDNByCNAME returns the content delivery network identified by the CNAME
+// target, or an empty string when the target does not match a known CDN.
+func CDNByCNAME(target string) string {
+	target = strings.ToLower(strings.TrimSuffix(target, "."))
+
+	for suffix, cdn := range cdnSuffixes {
+		if target == suffix || strings.HasSuffix(target, "."+suffix) {
+			return cdn
+		}
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloud
+
+import "testing"
+
+func TestIsCDN(t *testing.T) {
+	tests := []struct {
+		rng  *Range
+		want bool
+	}{
+		{&Range{Provider: Cloudflare}, true},
+		{&Range{Provider: Akamai}, true},
+		{&Range{Provider: AWS, Service: "CLOUDFRONT"}, true},
+		{&Range{Provider: AWS, Service: "EC2"}, false},
+		{&Range{Provider: Azure, Service: "AzureFrontDoor.Frontend"}, true},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsCDN(tt.rng); got != tt.want {
+			t.Errorf("IsCDN(%v) = %t, want %t", tt.rng, got, tt.want)
+		}
+	}
+}
+
+func TestCDNByCNAME(t *testing.T) {
+	tests := map[string]string{
+		"d111111abcdef8.cloudfront.net.": AWS,
+		"www.example.com.edgekey.net":    Akamai,
+		"example.global.ssl.fastly.net":  Fastly,
+		"www.example.com":                "",
+		"notcloudfront.net":              "",
+	}
+
+	for target, want := range tests {
+		if got := CDNByCNAME(target); got != want {
+			t.Errorf("CDNByCNAME(%s) = %q, want %q", target, got, want)
+		}
+	}
+}
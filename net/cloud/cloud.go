@@ -0,0 +1,291 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yl2chen/cidranger"
+)
+
+// Predicate is the graph property predicate used to store the cloud provider information of an address.
+const Predicate = "cloud"
+
+// CacheFile is the name of the file in the output directory that holds the published ranges.
+const CacheFile = "cloud_ranges.json"
+
+// RefreshInterval is the age at which the cached ranges are downloaded again.
+var RefreshInterval = 24 * time.Hour
+
+// Range is an address range published by a cloud or CDN provider.
+type Range struct {
+	CIDR     string `json:"cidr"`
+	Provider string `json:"provider"`
+	Service  string `json:"service,omitempty"`
+	Region   string `json:"region,omitempty"`
+}
+
+// String returns the Range encoded for storage as a graph property value.
+func (r *Range) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// ParseRange decodes a Range previously encoded by the String method.
+func ParseRange(s string) (*Range, bool) {
+	var r Range
+
+	if err := json.Unmarshal([]byte(strings.TrimSpace(s)), &r); err != nil || r.Provider == "" {
+		return nil, false
+	}
+	return &r, true
+}
+
+type rangerEntry struct {
+	ipnet net.IPNet
+	data  *Range
+}
+
+func (e *rangerEntry) Network() net.IPNet {
+	return e.ipnet
+}
+
+// Ranges provides lookups of addresses against the published cloud provider ranges.
+type Ranges struct {
+	sync.RWMutex
+	ranger    cidranger.Ranger
+	count     int
+	providers map[string]struct{}
+}
+
+type cacheFile struct {
+	Updated   time.Time           `json:"updated"`
+	Providers map[string][]*Range `json:"providers"`
+}
+
+// NewRanges returns a Ranges populated with the provided address ranges.
+func NewRanges(ranges []*Range) *Ranges {
+	r := &Ranges{
+		ranger:    cidranger.NewPCTrieRanger(),
+		providers: make(map[string]struct{}),
+	}
+
+	r.Insert(ranges...)
+	return r
+}
+
+// Insert adds the provided address ranges. Ranges published by providers more than
+// once, such as a general and a service specific entry, keep the more specific entry.
+func (r *Ranges) Insert(ranges ...*Range) {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, rng := range ranges {
+		_, ipnet, err := net.ParseCIDR(rng.CIDR)
+		if err != nil {
+			continue
+		}
+
+		if entries, err := r.ranger.ContainingNetworks(ipnet.IP); err == nil {
+			var dup bool
+
+			for _, e := range entries {
+				if entry, ok := e.(*rangerEntry); ok && entry.ipnet.String() == ipnet.String() {
+					if entry.data.Service == "" || (isGenericService(entry.data.Service) && !isGenericService(rng.Service)) {
+						entry.data = rng
+					}
+					dup = true
+					break
+				}
+			}
+			if dup {
+				continue
+			}
+		}
+
+		if err := r.ranger.Insert(&rangerEntry{ipnet: *ipnet, data: rng}); err == nil {
+			r.count++
+			r.providers[rng.Provider] = struct{}{}
+		}
+	}
+}
+
+// HasProvider returns true when ranges published by the provider are available for lookups.
+func (r *Ranges) HasProvider(provider string) bool {
+	r.RLock()
+	defer r.RUnlock()
+
+	_, found := r.providers[provider]
+	return found
+}
+
+// Len returns the number of address ranges available for lookups.
+func (r *Ranges) Len() int {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.count
+}
+
+// Lookup returns the most specific published range containing the address.
+func (r *Ranges) Lookup(addr string) *Range {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	entries, err := r.ranger.ContainingNetworks(ip)
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+
+	var best *rangerEntry
+	var bestOnes int
+	for _, e := range entries {
+		entry, ok := e.(*rangerEntry)
+		if !ok {
+			continue
+		}
+
+		if ones, _ := entry.ipnet.Mask.Size(); best == nil || ones > bestOnes ||
+			(ones == bestOnes && isGenericService(best.data.Service) && !isGenericService(entry.data.Service)) {
+			best = entry
+			bestOnes = ones
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	rng := *best.data
+	return &rng
+}
+
+// Replace swaps in the address ranges of other for the later lookups, so the Ranges already shared
+// with other goroutines can be refreshed. The other Ranges must not be modified afterwards.
+func (r *Ranges) Replace(other *Ranges) {
+	other.RLock()
+	ranger, count, providers := other.ranger, other.count, other.providers
+	other.RUnlock()
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.ranger = ranger
+	r.count = count
+	r.providers = providers
+}
+
+// Cached returns the ranges cached in the directory without downloading the published ranges,
+// and true when the cache is missing or older than the RefreshInterval.
+func Cached(dir string) (*Ranges, bool) {
+	cache := readCache(filepath.Join(dir, CacheFile))
+	if cache == nil {
+		return NewRanges(nil), true
+	}
+	return cache.ranges(), time.Since(cache.Updated) > RefreshInterval
+}
+
+// Load returns the ranges cached in the directory, and downloads the published ranges
+// when the cache is older than the RefreshInterval. Providers that cannot be reached
+// continue to use the previously cached ranges.
+func Load(ctx context.Context, dir string) (*Ranges, error) {
+	path := filepath.Join(dir, CacheFile)
+
+	cache := readCache(path)
+	if cache == nil {
+		cache = &cacheFile{Providers: make(map[string][]*Range)}
+	}
+
+	var err error
+	if time.Since(cache.Updated) > RefreshInterval {
+		var updated int
+		var failed []string
+
+		for _, src := range sources {
+			ranges, e := src.fetch(ctx)
+			if e != nil || len(ranges) == 0 {
+				failed = append(failed, src.provider)
+				continue
+			}
+			cache.Providers[src.provider] = ranges
+			updated++
+		}
+
+		// The cache remains stale, so the download is attempted again, when no provider was reached
+		if updated > 0 {
+			cache.Updated = time.Now()
+			if e := writeCache(path, cache); e != nil && dir != "" {
+				err = e
+			}
+		}
+		if len(failed) > 0 {
+			e := fmt.Errorf("failed to download the published ranges for %s", strings.Join(failed, ", "))
+			if err != nil {
+				e = fmt.Errorf("%v: %v", err, e)
+			}
+			err = e
+		}
+	}
+
+	return cache.ranges(), err
+}
+
+func (c *cacheFile) ranges() *Ranges {
+	r := NewRanges(nil)
+
+	for _, ranges := range c.Providers {
+		r.Insert(ranges...)
+	}
+	return r
+}
+
+func readCache(path string) *cacheFile {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var cache cacheFile
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Providers == nil {
+		return nil
+	}
+	return &cache
+}
+
+func writeCache(path string, cache *cacheFile) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("failed to encode the cloud provider ranges: %v", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write the cloud provider ranges to %s: %v", path, err)
+	}
+	return nil
+}
+
+// The services published for the entire address space of the provider
+func isGenericService(service string) bool {
+	switch strings.ToLower(service) {
+	case "", "amazon", "azurecloud", "google cloud":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const awsRanges = `{
+  "prefixes": [
+    {"ip_prefix": "3.0.0.0/8", "region": "GLOBAL", "service": "AMAZON"},
+    {"ip_prefix": "3.5.0.0/16", "region": "us-east-1", "service": "AMAZON"},
+    {"ip_prefix": "3.5.0.0/16", "region": "us-east-1", "service": "EC2"}
+  ],
+  "ipv6_prefixes": [
+    {"ipv6_prefix": "2600:1f00::/24", "region": "GLOBAL", "service": "AMAZON"}
+  ]
+}`
+
+func TestLookup(t *testing.T) {
+	ranges, err := parseAWS(awsRanges)
+	if err != nil {
+		t.Fatalf("parseAWS() error = %v", err)
+	}
+
+	r := NewRanges(ranges)
+	if r.Len() != 3 {
+		t.Errorf("NewRanges() stored %d ranges, expected 3", r.Len())
+	}
+
+	if rng := r.Lookup("3.5.1.1"); rng == nil || rng.Service != "EC2" || rng.Region != "us-east-1" {
+		t.Errorf("Lookup() returned %v for an EC2 address", rng)
+	}
+	if rng := r.Lookup("3.6.1.1"); rng == nil || rng.Provider != AWS || rng.Region != "GLOBAL" {
+		t.Errorf("Lookup() returned %v for an Amazon address", rng)
+	}
+	if rng := r.Lookup("2600:1f00::1"); rng == nil || rng.Provider != AWS {
+		t.Errorf("Lookup() returned %v for an IPv6 address", rng)
+	}
+	if rng := r.Lookup("8.8.8.8"); rng != nil {
+		t.Errorf("Lookup() returned %v for an address outside the ranges", rng)
+	}
+	if !r.HasProvider(AWS) || r.HasProvider(GCP) {
+		t.Errorf("HasProvider() did not match the providers of the ranges")
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	rng := &Range{CIDR: "104.16.0.0/13", Provider: Cloudflare, Service: "CDN"}
+
+	if got, ok := ParseRange(rng.String()); !ok || *got != *rng {
+		t.Errorf("ParseRange() returned %v, expected %v", got, rng)
+	}
+	if _, ok := ParseRange("{}"); ok {
+		t.Errorf("ParseRange() accepted a range without a provider")
+	}
+}
+
+func TestLoadFromCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := &cacheFile{
+		Updated: time.Now(),
+		Providers: map[string][]*Range{
+			Cloudflare: newRange("104.16.0.0/13", Cloudflare, "CDN", ""),
+		},
+	}
+	if err := writeCache(filepath.Join(dir, CacheFile), cache); err != nil {
+		t.Fatalf("writeCache() error = %v", err)
+	}
+
+	r, err := Load(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if rng := r.Lookup("104.16.1.1"); rng == nil || rng.Provider != Cloudflare {
+		t.Errorf("Load() did not provide the cached ranges: %v", rng)
+	}
+}
+
+func TestCached(t *testing.T) {
+	dir := t.TempDir()
+	if r, stale := Cached(dir); r.Len() != 0 || !stale {
+		t.Errorf("Cached() without the cache file = %d ranges, stale: %t", r.Len(), stale)
+	}
+
+	for _, tt := range []struct {
+		updated time.Time
+		stale   bool
+	}{
+		{updated: time.Now(), stale: false},
+		{updated: time.Now().Add(-2 * RefreshInterval), stale: true},
+	} {
+		cache := &cacheFile{
+			Updated:   tt.updated,
+			Providers: map[string][]*Range{Cloudflare: newRange("104.16.0.0/13", Cloudflare, "CDN", "")},
+		}
+		if err := writeCache(filepath.Join(dir, CacheFile), cache); err != nil {
+			t.Fatalf("writeCache() error = %v", err)
+		}
+
+		r, stale := Cached(dir)
+		if rng := r.Lookup("104.16.1.1"); rng == nil || rng.Provider != Cloudflare {
+			t.Errorf("Cached() did not provide the cached ranges: %v", rng)
+		}
+		if stale != tt.stale {
+			t.Errorf("Cached() of the cache updated at %v returned stale: %t, expected %t", tt.updated, stale, tt.stale)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	r := NewRanges(newRange("104.16.0.0/13", Cloudflare, "CDN", ""))
+	r.Replace(NewRanges(newRange("3.0.0.0/8", AWS, "AMAZON", "GLOBAL")))
+
+	if rng := r.Lookup("3.5.1.1"); rng == nil || rng.Provider != AWS || !r.HasProvider(AWS) {
+		t.Errorf("Replace() did not provide the new ranges: %v", rng)
+	}
+	if rng := r.Lookup("104.16.1.1"); rng != nil || r.HasProvider(Cloudflare) || r.Len() != 1 {
+		t.Errorf("Replace() kept the previous ranges: %v", rng)
+	}
+}
+
+func TestParsePlain(t *testing.T) {
+	ranges, _ := parsePlain(Cloudflare)("173.245.48.0/20\n103.21.244.0/22\n\nnot a prefix\n")
+
+	if len(ranges) != 2 {
+		t.Errorf("parsePlain() returned %d ranges, expected 2", len(ranges))
+	}
+}
+
+func TestLoadKeepsFailedProviders(t *testing.T) {
+	defer func(saved []*source) { sources = saved }(sources)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, CacheFile)
+	stale := time.Now().Add(-2 * RefreshInterval)
+	cache := &cacheFile{
+		Updated: stale,
+		Providers: map[string][]*Range{
+			AWS:        newRange("3.0.0.0/8", AWS, "AMAZON", "GLOBAL"),
+			Cloudflare: newRange("104.16.0.0/13", Cloudflare, "CDN", ""),
+		},
+	}
+	if err := writeCache(path, cache); err != nil {
+		t.Fatalf("writeCache() error = %v", err)
+	}
+
+	failing := func(ctx context.Context) ([]*Range, error) {
+		return nil, errors.New("unreachable")
+	}
+	// The cache is not refreshed when every provider fails
+	sources = []*source{{AWS, failing}, {Cloudflare, failing}}
+	if _, err := Load(context.Background(), dir); err == nil {
+		t.Error("Load() did not report the failed providers")
+	}
+	if c := readCache(path); c == nil || !c.Updated.Equal(stale) {
+		t.Errorf("Load() updated the cache without downloading any ranges")
+	}
+
+	sources = []*source{
+		{AWS, failing},
+		{Cloudflare, func(ctx context.Context) ([]*Range, error) {
+			return newRange("172.64.0.0/13", Cloudflare, "CDN", ""), nil
+		}},
+	}
+	r, err := Load(context.Background(), dir)
+	if err == nil {
+		t.Error("Load() did not report the failed provider")
+	}
+	if rng := r.Lookup("3.5.1.1"); rng == nil || rng.Provider != AWS {
+		t.Errorf("Load() dropped the ranges of the failed provider: %v", rng)
+	}
+	if rng := r.Lookup("172.64.1.1"); rng == nil || rng.Provider != Cloudflare {
+		t.Errorf("Load() did not provide the downloaded ranges: %v", rng)
+	}
+	if c := readCache(path); c == nil || c.Updated.Equal(stale) || len(c.Providers[AWS]) != 1 {
+		t.Errorf("Load() did not update the cache with the downloaded ranges")
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloud
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/owasp-amass/amass/v3/net/http"
+)
+
+// The cloud and CDN providers that can be identified
+const (
+	AWS          = "AWS"
+	Akamai       = "Akamai"
+	Azure        = "Azure"
+	Cloudflare   = "Cloudflare"
+	DigitalOcean = "DigitalOcean"
+	Fastly       = "Fastly"
+	GCP          = "GCP"
+	Oracle       = "Oracle"
+)
+
+const azureDownloadPage = "https://www.microsoft.com/en-us/download/confirmation.aspx?id=56519"
+
+var azureJSONRE = regexp.MustCompile(`https://download\.microsoft\.com/download/[^"']+/ServiceTags_Public_[0-9]+\.json`)
+
+type source struct {
+	provider string
+	fetch    func(ctx context.Context) ([]*Range, error)
+}
+
+var sources = []*source{
+	{AWS, fetchWith("https://ip-ranges.amazonaws.com/ip-ranges.json", parseAWS)},
+	{Azure, fetchAzure},
+	{Cloudflare, fetchWith("https://www.cloudflare.com/ips-v4", parsePlain(Cloudflare),
+		"https://www.cloudflare.com/ips-v6")},
+	{DigitalOcean, fetchWith("https://digitalocean.com/geo/google.csv", parseDigitalOcean)},
+	{Fastly, fetchWith("https://api.fastly.com/public-ip-list", parseFastly)},
+	{GCP, fetchWith("https://www.gstatic.com/ipranges/cloud.json", parseGCP)},
+	{Oracle, fetchWith("https://docs.oracle.com/en-us/iaas/tools/public_ip_ranges.json", parseOracle)},
+}
+
+// Providers that do not publish their ranges are identified by the autonomous systems they announce
+var asnProviders = map[int]string{
+	12222: Akamai,
+	16625: Akamai,
+	16702: Akamai,
+	20940: Akamai,
+	21342: Akamai,
+	32787: Akamai,
+	35994: Akamai,
+}
+
+// ProviderByASN returns the provider operating the autonomous system, or an empty
+// string when the ASN is not associated with a provider that lacks published ranges.
+func ProviderByASN(asn int) string {
+	return asnProviders[asn]
+}
+
+func fetchBody(ctx context.Context, u string) (string, error) {
+	resp, err := http.RequestWebPage(ctx, &http.Request{URL: u})
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("%s returned status %s", u, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func fetchWith(u string, parse func(string) ([]*Range, error), more ...string) func(context.Context) ([]*Range, error) {
+	return func(ctx context.Context) ([]*Range, error) {
+		var results []*Range
+
+		for _, url := range append([]string{u}, more...) {
+			body, err := fetchBody(ctx, url)
+			if err != nil {
+				return nil, err
+			}
+
+			ranges, err := parse(body)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, ranges...)
+		}
+		return results, nil
+	}
+}
+
+func fetchAzure(ctx context.Context) ([]*Range, error) {
+	page, err := fetchBody(ctx, azureDownloadPage)
+	if err != nil {
+		return nil, err
+	}
+
+	u := azureJSONRE.FindString(page)
+	if u == "" {
+		return nil, errors.New("failed to find the Azure service tags download link")
+	}
+
+	body, err := fetchBody(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return parseAzure(body)
+}
+
+func parseAWS(body string) ([]*Range, error) {
+	var m struct {
+		Prefixes []struct {
+			Prefix  string `json:"ip_prefix"`
+			Region  string `json:"region"`
+			Service string `json:"service"`
+		} `json:"prefixes"`
+		IPv6Prefixes []struct {
+			Prefix  string `json:"ipv6_prefix"`
+			Region  string `json:"region"`
+			Service string `json:"service"`
+		} `json:"ipv6_prefixes"`
+	}
+	if err := json.Unmarshal([]byte(body), &m); err != nil {
+		return nil, fmt.Errorf("failed to decode the AWS ranges: %v", err)
+	}
+
+	var results []*Range
+	for _, p := range m.Prefixes {
+		results = append(results, newRange(p.Prefix, AWS, p.Service, p.Region)...)
+	}
+	for _, p := range m.IPv6Prefixes {
+		results = append(results, newRange(p.Prefix, AWS, p.Service, p.Region)...)
+	}
+	return results, nil
+}
+
+func parseAzure(body string) ([]*Range, error) {
+	var m struct {
+		Values []struct {
+			Properties struct {
+				Region          string   `json:"region"`
+				SystemService   string   `json:"systemService"`
+				AddressPrefixes []string `json:"addressPrefixes"`
+			} `json:"properties"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal([]byte(body), &m); err != nil {
+		return nil, fmt.Errorf("failed to decode the Azure ranges: %v", err)
+	}
+
+	var results []*Range
+	for _, v := range m.Values {
+		for _, p := range v.Properties.AddressPrefixes {
+			results = append(results, newRange(p, Azure, v.Properties.SystemService, v.Properties.Region)...)
+		}
+	}
+	return results, nil
+}
+
+func parseGCP(body string) ([]*Range, error) {
+	var m struct {
+		Prefixes []struct {
+			IPv4    string `json:"ipv4Prefix"`
+			IPv6    string `json:"ipv6Prefix"`
+			Service string `json:"service"`
+			Scope   string `json:"scope"`
+		} `json:"prefixes"`
+	}
+	if err := json.Unmarshal([]byte(body), &m); err != nil {
+		return nil, fmt.Errorf("failed to decode the GCP ranges: %v", err)
+	}
+
+	var results []*Range
+	for _, p := range m.Prefixes {
+		results = append(results, newRange(p.IPv4, GCP, p.Service, p.Scope)...)
+		results = append(results, newRange(p.IPv6, GCP, p.Service, p.Scope)...)
+	}
+	return results, nil
+}
+
+func parseFastly(body string) ([]*Range, error) {
+	var m struct {
+		Addresses     []string `json:"addresses"`
+		IPv6Addresses []string `json:"ipv6_addresses"`
+	}
+	if err := json.Unmarshal([]byte(body), &m); err != nil {
+		return nil, fmt.Errorf("failed to decode the Fastly ranges: %v", err)
+	}
+
+	var results []*Range
+	for _, p := range append(m.Addresses, m.IPv6Addresses...) {
+		results = append(results, newRange(p, Fastly, "CDN", "")...)
+	}
+	return results, nil
+}
+
+func parseOracle(body string) ([]*Range, error) {
+	var m struct {
+		Regions []struct {
+			Region string `json:"region"`
+			CIDRs  []struct {
+				CIDR string   `json:"cidr"`
+				Tags []string `json:"tags"`
+			} `json:"cidrs"`
+		} `json:"regions"`
+	}
+	if err := json.Unmarshal([]byte(body), &m); err != nil {
+		return nil, fmt.Errorf("failed to decode the Oracle ranges: %v", err)
+	}
+
+	var results []*Range
+	for _, r := range m.Regions {
+		for _, c := range r.CIDRs {
+			results = append(results, newRange(c.CIDR, Oracle, strings.Join(c.Tags, ","), r.Region)...)
+		}
+	}
+	return results, nil
+}
+
+func parseDigitalOcean(body string) ([]*Range, error) {
+	var results []*Range
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		// Each line provides the prefix, country code, region code, city and postal code
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), ",")
+		if len(fields) < 3 {
+			continue
+		}
+		results = append(results, newRange(fields[0], DigitalOcean, "", fields[2])...)
+	}
+	return results, scanner.Err()
+}
+
+func parsePlain(provider string) func(string) ([]*Range, error) {
+	return func(body string) ([]*Range, error) {
+		var results []*Range
+
+		for _, line := range strings.Split(body, "\n") {
+			results = append(results, newRange(line, provider, "CDN", "")...)
+		}
+		return results, nil
+	}
+}
+
+func newRange(cidr, provider, service, region string) []*Range {
+	_, ipnet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+	if err != nil {
+		return nil
+	}
+
+	return []*Range{{
+		CIDR:     ipnet.String(),
+		Provider: provider,
+		Service:  service,
+		Region:   region,
+	}}
+}
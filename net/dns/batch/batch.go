@@ -0,0 +1,372 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package batch provides a high-rate DNS query path in the style of massdns. The queries are written
+// to the resolvers in batches from a few UDP sockets, using sendmmsg and recvmmsg where available,
+// and the responses are matched to the queries by a dedicated receive loop.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/resolve"
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	// The number of packets written or read with each system call
+	batchSize = 64
+	// The largest UDP response accepted from the resolvers
+	maxPacketSize = 4096
+	// The number of sockets sharing the queries sent to the resolvers
+	numSockets = 4
+	// DefaultTimeout is the time the Sender waits for a response before reporting no response.
+	DefaultTimeout = 2 * time.Second
+)
+
+type query struct {
+	key     string
+	msg     *dns.Msg
+	ch      chan *dns.Msg
+	server  *net.UDPAddr
+	expires time.Time
+}
+
+// Sender sends DNS queries to a set of resolvers at a high rate. Query has the same semantics as
+// the resolve package: each message is answered on the provided channel, and those that do not
+// receive a response are returned with the resolve.RcodeNoResponse code.
+type Sender struct {
+	sync.Mutex
+	conns   []*ipv4.PacketConn
+	servers []*net.UDPAddr
+	queue   chan *query
+	pending map[string]*query
+	timeout time.Duration
+	period  time.Duration
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewSender returns a Sender distributing the queries across the resolvers, which are IPv4
+// addresses with an optional port. A positive qps limits the rate of the queries sent.
+func NewSender(resolvers []string, qps int) (*Sender, error) {
+	var servers []*net.UDPAddr
+
+	for _, r := range resolvers {
+		addr, err := resolverAddr(r)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, addr)
+	}
+	if len(servers) == 0 {
+		return nil, errors.New("the batch DNS sender requires at least one resolver")
+	}
+
+	s := &Sender{
+		servers: servers,
+		queue:   make(chan *query, batchSize*numSockets*4),
+		pending: make(map[string]*query),
+		timeout: DefaultTimeout,
+		done:    make(chan struct{}),
+	}
+	if qps > 0 {
+		s.period = time.Second / time.Duration(qps)
+	}
+
+	for i := 0; i < numSockets; i++ {
+		conn, err := net.ListenUDP("udp4", nil)
+		if err != nil {
+			s.closeConns()
+			return nil, fmt.Errorf("failed to open the batch DNS socket: %v", err)
+		}
+		_ = conn.SetReadBuffer(8 << 20)
+		_ = conn.SetWriteBuffer(8 << 20)
+		s.conns = append(s.conns, ipv4.NewPacketConn(conn))
+	}
+
+	go s.sendQueries()
+	for _, c := range s.conns {
+		go s.receiveResponses(c)
+	}
+	go s.timeouts()
+	return s, nil
+}
+
+func resolverAddr(r string) (*net.UDPAddr, error) {
+	host, port := r, "53"
+	if h, p, err := net.SplitHostPort(r); err == nil {
+		host, port = h, p
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.To4() == nil {
+		return nil, fmt.Errorf("the batch DNS sender only supports IPv4 resolvers: %s", r)
+	}
+	return net.ResolveUDPAddr("udp4", net.JoinHostPort(ip.String(), port))
+}
+
+// SetTimeout changes the time waited for each response.
+func (s *Sender) SetTimeout(d time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.timeout = d
+}
+
+// Len returns the number of resolvers receiving the queries.
+func (s *Sender) Len() int {
+	return len(s.servers)
+}
+
+// Stop closes the sockets and reports no response for the queries still pending.
+func (s *Sender) Stop() {
+	s.once.Do(func() {
+		close(s.done)
+		s.closeConns()
+
+		s.Lock()
+		pending := s.pending
+		s.pending = make(map[string]*query)
+		s.Unlock()
+
+		// The receivers of the responses may also be shutting down
+		for _, q := range pending {
+			go q.noResponse()
+		}
+	})
+}
+
+func (s *Sender) closeConns() {
+	for _, c := range s.conns {
+		_ = c.Close()
+	}
+}
+
+// Query sends the DNS message and returns the response on the provided channel.
+func (s *Sender) Query(ctx context.Context, msg *dns.Msg, ch chan *dns.Msg) {
+	if msg == nil || len(msg.Question) == 0 {
+		ch <- msg
+		return
+	}
+
+	q := &query{
+		key: key(msg.Id, msg.Question[0].Name),
+		msg: msg,
+		ch:  ch,
+	}
+	select {
+	case <-ctx.Done():
+	case <-s.done:
+	case s.queue <- q:
+		return
+	}
+	q.noResponse()
+}
+
+func key(id uint16, name string) string {
+	return fmt.Sprintf("%d:%s", id, strings.ToLower(resolve.RemoveLastDot(name)))
+}
+
+func (q *query) noResponse() {
+	q.msg.Rcode = resolve.RcodeNoResponse
+	q.ch <- q.msg
+}
+
+func (s *Sender) sendQueries() {
+	msgs := make([]ipv4.Message, batchSize)
+	batch := make([]*query, 0, batchSize)
+	next := time.Now()
+
+	for i, srv := 0, 0; ; i = (i + 1) % len(s.conns) {
+		batch = batch[:0]
+		// Block for the first query and then take what is already waiting
+		select {
+		case <-s.done:
+			return
+		case q := <-s.queue:
+			batch = append(batch, q)
+		}
+	fill:
+		for len(batch) < batchSize {
+			select {
+			case q := <-s.queue:
+				batch = append(batch, q)
+			default:
+				break fill
+			}
+		}
+
+		if s.period > 0 {
+			if d := time.Until(next); d > 0 {
+				time.Sleep(d)
+			}
+			if now := time.Now(); next.Before(now) {
+				next = now
+			}
+			next = next.Add(s.period * time.Duration(len(batch)))
+		}
+
+		var n int
+		for _, q := range batch {
+			b, err := q.msg.Pack()
+			if err != nil || !s.register(q, s.servers[srv]) {
+				q.noResponse()
+				continue
+			}
+			srv = (srv + 1) % len(s.servers)
+
+			msgs[n] = ipv4.Message{Buffers: [][]byte{b}, Addr: q.server}
+			batch[n] = q
+			n++
+		}
+		s.write(s.conns[i], msgs[:n], batch[:n])
+	}
+}
+
+func (s *Sender) register(q *query, server *net.UDPAddr) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+	if _, found := s.pending[q.key]; found {
+		return false
+	}
+
+	q.server = server
+	q.expires = time.Now().Add(s.timeout)
+	s.pending[q.key] = q
+	return true
+}
+
+func (s *Sender) remove(key string) *query {
+	s.Lock()
+	defer s.Unlock()
+
+	q, found := s.pending[key]
+	if found {
+		delete(s.pending, key)
+	}
+	return q
+}
+
+func (s *Sender) write(conn *ipv4.PacketConn, msgs []ipv4.Message, batch []*query) {
+	for len(msgs) > 0 {
+		n, err := conn.WriteBatch(msgs, 0)
+		if err != nil {
+			if n < 0 {
+				n = 0
+			}
+			// The failed query is reported now instead of waiting for the timeout
+			if q := s.remove(batch[n].key); q != nil {
+				q.noResponse()
+			}
+			n++
+		}
+		msgs, batch = msgs[n:], batch[n:]
+	}
+}
+
+func (s *Sender) receiveResponses(conn *ipv4.PacketConn) {
+	msgs := make([]ipv4.Message, batchSize)
+	for i := range msgs {
+		msgs[i].Buffers = [][]byte{make([]byte, maxPacketSize)}
+	}
+
+	for {
+		n, err := conn.ReadBatch(msgs, 0)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+
+		for _, m := range msgs[:n] {
+			resp := new(dns.Msg)
+			if err := resp.Unpack(m.Buffers[0][:m.N]); err != nil || len(resp.Question) == 0 {
+				continue
+			}
+			s.processResponse(resp, m.Addr)
+		}
+	}
+}
+
+func (s *Sender) processResponse(resp *dns.Msg, from net.Addr) {
+	k := key(resp.Id, resp.Question[0].Name)
+
+	s.Lock()
+	q, found := s.pending[k]
+	// Only accept the response from the resolver that was sent the query
+	if found {
+		if addr, ok := from.(*net.UDPAddr); !ok || !addr.IP.Equal(q.server.IP) || addr.Port != q.server.Port {
+			found = false
+		} else {
+			delete(s.pending, k)
+		}
+	}
+	s.Unlock()
+	if !found {
+		return
+	}
+
+	if resp.Truncated {
+		go s.tcpExchange(q)
+		return
+	}
+	q.ch <- resp
+}
+
+func (s *Sender) tcpExchange(q *query) {
+	client := dns.Client{
+		Net:     "tcp",
+		Timeout: time.Minute,
+	}
+
+	if resp, _, err := client.Exchange(q.msg, q.server.String()); err == nil {
+		q.ch <- resp
+		return
+	}
+	q.noResponse()
+}
+
+func (s *Sender) timeouts() {
+	for {
+		s.Lock()
+		d := s.timeout / 2
+		s.Unlock()
+
+		select {
+		case <-s.done:
+			return
+		case <-time.After(d):
+		}
+
+		now := time.Now()
+		var expired []*query
+		s.Lock()
+		for k, q := range s.pending {
+			if now.After(q.expires) {
+				delete(s.pending, k)
+				expired = append(expired, q)
+			}
+		}
+		s.Unlock()
+
+		for _, q := range expired {
+			q.noResponse()
+		}
+	}
+}
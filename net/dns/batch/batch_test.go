@@ -0,0 +1,97 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package batch
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/resolve"
+)
+
+func TestNewSenderResolvers(t *testing.T) {
+	for _, resolvers := range [][]string{nil, {"2001:4860:4860::8888"}, {"dns.google"}} {
+		if s, err := NewSender(resolvers, 0); err == nil {
+			s.Stop()
+			t.Errorf("NewSender(%v) did not return an error", resolvers)
+		}
+	}
+}
+
+func TestSenderQuery(t *testing.T) {
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the queries: %v", err)
+	}
+
+	srv := &dns.Server{
+		PacketConn: pc,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			m := new(dns.Msg)
+			m.SetReply(req)
+			if rr, err := dns.NewRR(req.Question[0].Name + " 300 IN A 192.168.1.1"); err == nil {
+				m.Answer = append(m.Answer, rr)
+			}
+			_ = w.WriteMsg(m)
+		}),
+	}
+	go func() { _ = srv.ActivateAndServe() }()
+	defer func() { _ = srv.Shutdown() }()
+
+	// The rate limit keeps the test server from dropping the packets
+	s, err := NewSender([]string{pc.LocalAddr().String()}, 2000)
+	if err != nil {
+		t.Fatalf("NewSender() error = %v", err)
+	}
+	defer s.Stop()
+
+	num := 500
+	ch := make(chan *dns.Msg, num)
+	for i := 0; i < num; i++ {
+		s.Query(context.Background(), resolve.QueryMsg(fmt.Sprintf("host%d.owasp.org", i), dns.TypeA), ch)
+	}
+
+	for i := 0; i < num; i++ {
+		select {
+		case resp := <-ch:
+			if resp.Rcode != dns.RcodeSuccess || len(resp.Answer) != 1 {
+				t.Fatalf("the query for %s returned %v", resp.Question[0].Name, resp)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("received %d of the %d responses", i, num)
+		}
+	}
+}
+
+func TestSenderTimeout(t *testing.T) {
+	// The resolver never answers the queries
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the queries: %v", err)
+	}
+	defer pc.Close()
+
+	s, err := NewSender([]string{pc.LocalAddr().String()}, 100)
+	if err != nil {
+		t.Fatalf("NewSender() error = %v", err)
+	}
+	defer s.Stop()
+	s.SetTimeout(100 * time.Millisecond)
+
+	ch := make(chan *dns.Msg, 1)
+	s.Query(context.Background(), resolve.QueryMsg("www.owasp.org", dns.TypeA), ch)
+	select {
+	case resp := <-ch:
+		if resp.Rcode != resolve.RcodeNoResponse {
+			t.Errorf("the unanswered query returned the rcode %d", resp.Rcode)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the unanswered query was not reported")
+	}
+}
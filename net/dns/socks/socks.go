@@ -0,0 +1,170 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package socks provides a DNS query path for restricted networks. The queries are sent over TCP
+// through a SOCKS5 proxy, since the proxies rarely relay UDP, and each query uses its own connection.
+package socks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/resolve"
+	"golang.org/x/net/proxy"
+)
+
+// DefaultTimeout is the time the Sender waits for the connection and the response of each query.
+const DefaultTimeout = 5 * time.Second
+
+// Sender sends DNS queries to a set of resolvers through a SOCKS5 proxy. Query has the same semantics
+// as the resolve package: each message is answered on the provided channel, and those that do not
+// receive a response are returned with the resolve.RcodeNoResponse code.
+type Sender struct {
+	sync.Mutex
+	dialer  proxy.ContextDialer
+	servers []string
+	next    int
+	sem     chan struct{}
+	timeout time.Duration
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewSender returns a Sender distributing the queries across the resolvers, which are IP addresses
+// with an optional port, through the proxy URL that may carry the username and password. No more
+// than maxInFlight queries are sent through the proxy at the same time.
+func NewSender(proxyURL string, resolvers []string, maxInFlight int) (*Sender, error) {
+	d, err := Dialer(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []string
+	for _, r := range resolvers {
+		if _, _, err := net.SplitHostPort(r); err != nil {
+			r = net.JoinHostPort(r, "53")
+		}
+		servers = append(servers, r)
+	}
+	if len(servers) == 0 {
+		return nil, errors.New("no resolvers were provided to the SOCKS5 DNS sender")
+	}
+	if maxInFlight <= 0 {
+		maxInFlight = len(servers)
+	}
+
+	return &Sender{
+		dialer:  d,
+		servers: servers,
+		sem:     make(chan struct{}, maxInFlight),
+		timeout: DefaultTimeout,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Dialer returns the dialer connecting through the SOCKS5 proxy URL.
+func Dialer(proxyURL string) (proxy.ContextDialer, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the SOCKS5 proxy: %v", err)
+	}
+	if u.Scheme != "socks5" || u.Host == "" {
+		return nil, fmt.Errorf("the proxy %s is not a socks5 URL", proxyURL)
+	}
+
+	d, err := proxy.FromURL(u, proxy.Direct)
+	if err != nil {
+		return nil, err
+	}
+
+	cd, ok := d.(proxy.ContextDialer)
+	if !ok {
+		return nil, errors.New("the SOCKS5 dialer does not support contexts")
+	}
+	return cd, nil
+}
+
+// SetTimeout changes the time waited for the connection and the response of each query.
+func (s *Sender) SetTimeout(d time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.timeout = d
+}
+
+// Stop ends the queries waiting to be sent, which are returned without a response.
+func (s *Sender) Stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Query sends the DNS message and returns the response on the provided channel.
+func (s *Sender) Query(ctx context.Context, msg *dns.Msg, ch chan *dns.Msg) {
+	if msg == nil || len(msg.Question) == 0 {
+		ch <- msg
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-s.done:
+	case s.sem <- struct{}{}:
+		go func() {
+			defer func() { <-s.sem }()
+
+			if resp, err := s.exchange(ctx, msg); err == nil {
+				ch <- resp
+				return
+			}
+			noResponse(msg, ch)
+		}()
+		return
+	}
+	noResponse(msg, ch)
+}
+
+func (s *Sender) exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	s.Lock()
+	server := s.servers[s.next]
+	s.next = (s.next + 1) % len(s.servers)
+	timeout := s.timeout
+	s.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := s.dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+
+	co := &dns.Conn{Conn: conn}
+	defer co.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = co.SetDeadline(deadline)
+	}
+	if err := co.WriteMsg(msg); err != nil {
+		return nil, err
+	}
+
+	resp, err := co.ReadMsg()
+	if err != nil {
+		return nil, err
+	}
+	if resp.Id != msg.Id {
+		return nil, fmt.Errorf("the response from %s did not match the query", server)
+	}
+	return resp, nil
+}
+
+func noResponse(msg *dns.Msg, ch chan *dns.Msg) {
+	msg.Rcode = resolve.RcodeNoResponse
+	ch <- msg
+}
@@ -0,0 +1,168 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package socks
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/resolve"
+)
+
+// serveSOCKS5 accepts the CONNECT requests of the clients providing the username and password,
+// and relays the connections to the destinations.
+func serveSOCKS5(t *testing.T, user, pass string) net.Listener {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the proxy clients: %v", err)
+	}
+
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go relaySOCKS5(c, user, pass)
+		}
+	}()
+	return ln
+}
+
+func relaySOCKS5(c net.Conn, user, pass string) {
+	defer c.Close()
+
+	// The greeting must offer the username and password method
+	hdr := make([]byte, 2)
+	if _, err := io.ReadFull(c, hdr); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(c, make([]byte, hdr[1])); err != nil {
+		return
+	}
+	_, _ = c.Write([]byte{5, 2})
+
+	auth := make([]byte, 2)
+	if _, err := io.ReadFull(c, auth); err != nil {
+		return
+	}
+	u := make([]byte, auth[1])
+	_, _ = io.ReadFull(c, u)
+	plen := make([]byte, 1)
+	_, _ = io.ReadFull(c, plen)
+	p := make([]byte, plen[0])
+	_, _ = io.ReadFull(c, p)
+	if string(u) != user || string(p) != pass {
+		_, _ = c.Write([]byte{1, 1})
+		return
+	}
+	_, _ = c.Write([]byte{1, 0})
+
+	// Only the CONNECT requests for IPv4 addresses are relayed
+	req := make([]byte, 10)
+	if _, err := io.ReadFull(c, req); err != nil || req[1] != 1 || req[3] != 1 {
+		return
+	}
+	addr := net.JoinHostPort(net.IP(req[4:8]).String(), strconv.Itoa(int(binary.BigEndian.Uint16(req[8:]))))
+
+	dst, err := net.Dial("tcp", addr)
+	if err != nil {
+		_, _ = c.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer dst.Close()
+	_, _ = c.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+
+	go func() { _, _ = io.Copy(dst, c) }()
+	_, _ = io.Copy(c, dst)
+}
+
+func TestNewSender(t *testing.T) {
+	for _, p := range []string{"http://127.0.0.1:1080", "socks5://", "://bad"} {
+		if _, err := NewSender(p, []string{"8.8.8.8"}, 0); err == nil {
+			t.Errorf("NewSender(%s) did not return an error", p)
+		}
+	}
+	if _, err := NewSender("socks5://127.0.0.1:1080", nil, 0); err == nil {
+		t.Error("NewSender did not return an error without the resolvers")
+	}
+}
+
+func TestSenderQuery(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the queries: %v", err)
+	}
+
+	srv := &dns.Server{
+		Listener: ln,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			m := new(dns.Msg)
+			m.SetReply(req)
+			if rr, err := dns.NewRR(req.Question[0].Name + " 300 IN A 192.168.1.1"); err == nil {
+				m.Answer = append(m.Answer, rr)
+			}
+			_ = w.WriteMsg(m)
+		}),
+	}
+	go func() { _ = srv.ActivateAndServe() }()
+	defer func() { _ = srv.Shutdown() }()
+
+	proxy := serveSOCKS5(t, "caffix", "secret")
+	defer proxy.Close()
+
+	s, err := NewSender("socks5://caffix:secret@"+proxy.Addr().String(), []string{ln.Addr().String()}, 10)
+	if err != nil {
+		t.Fatalf("NewSender() error = %v", err)
+	}
+	defer s.Stop()
+
+	num := 50
+	ch := make(chan *dns.Msg, num)
+	for i := 0; i < num; i++ {
+		s.Query(context.Background(), resolve.QueryMsg(fmt.Sprintf("host%d.owasp.org", i), dns.TypeA), ch)
+	}
+
+	for i := 0; i < num; i++ {
+		select {
+		case resp := <-ch:
+			if resp.Rcode != dns.RcodeSuccess || len(resp.Answer) != 1 {
+				t.Fatalf("the query for %s returned %v", resp.Question[0].Name, resp)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("received %d of the %d responses", i, num)
+		}
+	}
+}
+
+func TestSenderBadCredentials(t *testing.T) {
+	proxy := serveSOCKS5(t, "caffix", "secret")
+	defer proxy.Close()
+
+	s, err := NewSender("socks5://caffix:wrong@"+proxy.Addr().String(), []string{"127.0.0.1"}, 1)
+	if err != nil {
+		t.Fatalf("NewSender() error = %v", err)
+	}
+	defer s.Stop()
+
+	ch := make(chan *dns.Msg, 1)
+	s.Query(context.Background(), resolve.QueryMsg("www.owasp.org", dns.TypeA), ch)
+
+	select {
+	case resp := <-ch:
+		if resp.Rcode != resolve.RcodeNoResponse {
+			t.Errorf("the query returned %v without valid proxy credentials", resp)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the query was not returned")
+	}
+}
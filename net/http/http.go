@@ -53,6 +53,20 @@ var (
 // DefaultClient is the same HTTP client used by the package methods.
 var DefaultClient *http.Client
 
+// DirectProxy sends the request without a proxy, ignoring the proxy settings of the environment.
+const DirectProxy = "direct"
+
+// The clients sending requests through a proxy or with their own TLS settings
+var (
+	proxyLock    sync.Mutex
+	proxyClients = make(map[clientKey]*http.Client)
+)
+
+type clientKey struct {
+	proxy string
+	tls   *tls.Config
+}
+
 // Header represents the HTTP headers for requests and responses.
 type Header map[string]string
 
@@ -63,6 +77,10 @@ type Request struct {
 	Header Header
 	Body   string
 	Auth   *BasicAuth
+	// The proxy URL replacing the proxy settings of the environment, or DirectProxy
+	Proxy string
+	// The TLS settings replacing those of the DefaultClient, which does not verify the certificates
+	TLS *tls.Config
 }
 
 // Response represents the HTTP response in the Amass preferred format.
@@ -76,6 +94,8 @@ type Response struct {
 	Body       string
 	Length     int64
 	TLS        *tls.ConnectionState
+	// The URL of the request that received the response, after following the redirects
+	URL string
 }
 
 // BasicAuth contains the data used for HTTP basic authentication.
@@ -98,7 +118,8 @@ func init() {
 			ExpectContinueTimeout: 5 * time.Second,
 			TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
 		},
-		Jar: jar,
+		CheckRedirect: auditRedirect,
+		Jar:           jar,
 	}
 
 	switch runtime.GOOS {
@@ -159,6 +180,11 @@ func RespToAmassResponse(resp *http.Response) *Response {
 		_ = resp.Body.Close()
 	}
 
+	var u string
+	if resp.Request != nil && resp.Request.URL != nil {
+		u = resp.Request.URL.String()
+	}
+
 	return &Response{
 		Status:     resp.Status,
 		StatusCode: resp.StatusCode,
@@ -169,6 +195,7 @@ func RespToAmassResponse(resp *http.Response) *Response {
 		Body:       body,
 		Length:     resp.ContentLength,
 		TLS:        resp.TLS,
+		URL:        u,
 	}
 }
 
@@ -210,6 +237,10 @@ func RequestWebPage(ctx context.Context, r *Request) (*Response, error) {
 	if err != nil {
 		return nil, err
 	}
+	// The requests sent through a proxy are not seen by the dialer
+	if err := amassnet.Audit.Allow(req.URL.Scheme, req.URL.Host); err != nil {
+		return nil, err
+	}
 	req.Close = true
 
 	if r.Auth != nil && r.Auth.Username != "" && r.Auth.Password != "" {
@@ -223,13 +254,76 @@ func RequestWebPage(ctx context.Context, r *Request) (*Response, error) {
 		req.Header.Set(k, v)
 	}
 
-	resp, err := DefaultClient.Do(req)
+	c, err := proxyClient(r.Proxy, r.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	return RespToAmassResponse(resp), nil
 }
 
+// auditRedirect checks each redirect against the endpoint audit, since the redirects followed through
+// a proxy are not seen by the dialer, and stops after 10 redirects like the default policy.
+func auditRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return amassnet.Audit.Allow(req.URL.Scheme, req.URL.Host)
+}
+
+// proxyClient returns the client sending requests through the proxy with the TLS settings. The
+// clients share the remaining settings and the cookies of the DefaultClient.
+func proxyClient(proxy string, tlsc *tls.Config) (*http.Client, error) {
+	if proxy == "" && tlsc == nil {
+		return DefaultClient, nil
+	}
+	// The transports replacing the default, such as those replaying recorded responses, receive
+	// the requests without the proxy and TLS settings
+	if _, ok := DefaultClient.Transport.(*http.Transport); !ok && DefaultClient.Transport != nil {
+		return DefaultClient, nil
+	}
+
+	proxyLock.Lock()
+	defer proxyLock.Unlock()
+
+	key := clientKey{proxy: proxy, tls: tlsc}
+	if c, found := proxyClients[key]; found {
+		return c, nil
+	}
+
+	t, ok := DefaultClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("the default client does not support proxies")
+	}
+
+	t = t.Clone()
+	if proxy == DirectProxy {
+		t.Proxy = nil
+	} else if proxy != "" {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the proxy %s: %v", proxy, err)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	if tlsc != nil {
+		t.TLSClientConfig = tlsc
+	}
+
+	c := &http.Client{
+		Timeout:       DefaultClient.Timeout,
+		Transport:     t,
+		CheckRedirect: auditRedirect,
+		Jar:           DefaultClient.Jar,
+	}
+	proxyClients[key] = c
+	return c, nil
+}
+
 // Crawl will spider the web page at the URL argument looking while staying within the scope provided.
 func Crawl(ctx context.Context, u string, scope []string, max int, callback func(*Request, *Response)) error {
 	select {
@@ -238,6 +332,12 @@ func Crawl(ctx context.Context, u string, scope []string, max int, callback func
 	default:
 	}
 
+	if base, err := url.Parse(u); err != nil {
+		return err
+	} else if err := amassnet.Audit.Allow(base.Scheme, base.Host); err != nil {
+		return err
+	}
+
 	var count int
 	var m sync.Mutex
 	filter := bf.NewDefaultStableBloomFilter(10000, 0.01)
@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	amassnet "github.com/owasp-amass/amass/v3/net"
 	amassdns "github.com/owasp-amass/amass/v3/net/dns"
 	"github.com/owasp-amass/resolve"
 	"github.com/caffix/stringset"
@@ -147,6 +148,93 @@ func TestRequestWebPage(t *testing.T) {
 	}
 }
 
+func TestRequestWebPageRedirect(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/target?page=2", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, "target")
+	}))
+	defer ts.Close()
+
+	resp, err := RequestWebPage(context.TODO(), &Request{URL: ts.URL + "/moved"})
+	if err != nil || resp.Body != "target" {
+		t.Fatalf("Failed to follow the redirect: %v", err)
+	}
+	if want := ts.URL + "/target?page=2"; resp.URL != want {
+		t.Errorf("The response provided the URL %s, expected %s", resp.URL, want)
+	}
+}
+
+func TestRequestWebPageRedirectAudit(t *testing.T) {
+	defer func(a *amassnet.EndpointAudit) { amassnet.Audit = a }(amassnet.Audit)
+	amassnet.Audit = amassnet.NewEndpointAudit(func(host string) bool {
+		return host == "owasp.org" || strings.HasSuffix(host, ".owasp.org")
+	})
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.String() {
+		case "http://cdn.example.com/start":
+			http.Redirect(w, r, "http://cdn.example.com/next", http.StatusFound)
+		case "http://cdn.example.com/next":
+			http.Redirect(w, r, "http://www.owasp.org/", http.StatusFound)
+		default:
+			fmt.Fprint(w, "target")
+		}
+	}))
+	defer proxy.Close()
+	// The redirect toward the target is refused, while the proxy hides it from the dialer
+	if _, err := RequestWebPage(context.TODO(), &Request{URL: "http://cdn.example.com/start", Proxy: proxy.URL}); err == nil {
+		t.Error("Failed to refuse the redirect toward the target")
+	}
+
+	var contacted int
+	for _, e := range amassnet.Audit.Contacted() {
+		if e.Protocol == "http" && e.Endpoint == "cdn.example.com" {
+			contacted = e.Count
+		}
+	}
+	if contacted != 2 {
+		t.Errorf("The audit recorded %d requests to the redirecting host, expected 2", contacted)
+	}
+	if refused := amassnet.Audit.Refused(); len(refused) != 1 || refused[0].Endpoint != "www.owasp.org" {
+		t.Errorf("The audit refused %v", refused)
+	}
+}
+
+func TestRequestWebPageProxy(t *testing.T) {
+	target := "http://service.owasp.org/api"
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute URL of the target
+		if r.URL.String() != target {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "proxied")
+	}))
+	defer proxy.Close()
+
+	resp, err := RequestWebPage(context.TODO(), &Request{URL: target, Proxy: proxy.URL})
+	if err != nil || resp.StatusCode != 200 || resp.Body != "proxied" {
+		t.Errorf("Failed to send the request through the proxy: %v", err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "direct")
+	}))
+	defer ts.Close()
+
+	resp, err = RequestWebPage(context.TODO(), &Request{URL: ts.URL, Proxy: DirectProxy})
+	if err != nil || resp.Body != "direct" {
+		t.Errorf("Failed to send the request without a proxy: %v", err)
+	}
+
+	if _, err := RequestWebPage(context.TODO(), &Request{URL: ts.URL, Proxy: "://bad"}); err == nil {
+		t.Error("Failed to report the invalid proxy")
+	}
+}
+
 func TestCrawl(t *testing.T) {
 	re, err := regexp.Compile(amassdns.AnySubdomainRegexString())
 	if err != nil {
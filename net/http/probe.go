@@ -0,0 +1,98 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxProbeRedirects int   = 10
+	maxProbeBodySize  int64 = 2 << 20
+)
+
+var titleRE = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// ProbeResult contains the final response received while probing a URL, along with the redirects followed.
+type ProbeResult struct {
+	URL       string
+	Redirects []string
+	Title     string
+	Response  *Response
+}
+
+// Probe requests the URL, following up to ten redirects, and returns the details of the final response.
+func Probe(ctx context.Context, u string) (*ProbeResult, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Close = true
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", Accept)
+	req.Header.Set("Accept-Language", AcceptLang)
+
+	var redirects []string
+	client := &http.Client{
+		Timeout:   httpTimeout,
+		Transport: DefaultClient.Transport,
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			if len(via) >= maxProbeRedirects {
+				return errors.New("stopped after too many redirects")
+			}
+			redirects = append(redirects, r.URL.String())
+			return nil
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe %s: %v", u, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response body from %s: %v", u, err)
+	}
+
+	r := &Response{
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Proto:      resp.Proto,
+		ProtoMajor: resp.ProtoMajor,
+		ProtoMinor: resp.ProtoMinor,
+		Header:     HdrToAmassHeader(resp.Header),
+		Body:       string(body),
+		Length:     resp.ContentLength,
+		TLS:        resp.TLS,
+	}
+	if r.Length < 0 {
+		r.Length = int64(len(body))
+	}
+
+	return &ProbeResult{
+		URL:       resp.Request.URL.String(),
+		Redirects: redirects,
+		Title:     PageTitle(r.Body),
+		Response:  r,
+	}, nil
+}
+
+// PageTitle returns the text of the title element in the HTML document.
+func PageTitle(body string) string {
+	m := titleRE.FindStringSubmatch(body)
+	if len(m) < 2 {
+		return ""
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(m[1])), " ")
+}
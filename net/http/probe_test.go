@@ -0,0 +1,40 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProbe(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login", http.StatusFound)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx")
+		fmt.Fprint(w, "<html><head><title>\n  Sign In &amp; Welcome </title></head></html>")
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	r, err := Probe(context.Background(), ts.URL+"/")
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if r.URL != ts.URL+"/login" || len(r.Redirects) != 1 {
+		t.Errorf("Probe() returned the URL %s with the redirects %v", r.URL, r.Redirects)
+	}
+	if r.Title != "Sign In & Welcome" {
+		t.Errorf("Probe() returned the title %q", r.Title)
+	}
+	if r.Response.StatusCode != 200 || r.Response.Header["Server"] != "nginx" || r.Response.Length == 0 {
+		t.Errorf("Probe() returned the response %v", r.Response)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	screenshotWidth   int = 1280
+	screenshotHeight  int = 800
+	screenshotTimeout     = 30 * time.Second
+)
+
+var screenshotNameRE = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// Screenshotter captures images of web pages using a headless Chrome browser.
+type Screenshotter struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	sem    chan struct{}
+}
+
+// NewScreenshotter launches the headless browser and returns a Screenshotter
+// that captures no more than max pages at the same time.
+func NewScreenshotter(ctx context.Context, max int) (*Screenshotter, error) {
+	if max <= 0 {
+		max = 1
+	}
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("ignore-certificate-errors", true),
+		chromedp.UserAgent(UserAgent),
+		chromedp.WindowSize(screenshotWidth, screenshotHeight),
+	)
+	actx, acancel := chromedp.NewExecAllocator(ctx, opts...)
+	bctx, bcancel := chromedp.NewContext(actx)
+	cancel := func() {
+		bcancel()
+		acancel()
+	}
+	// Start the browser before any pages are requested
+	if err := chromedp.Run(bctx); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start the headless browser: %v", err)
+	}
+
+	return &Screenshotter{
+		ctx:    bctx,
+		cancel: cancel,
+		sem:    make(chan struct{}, max),
+	}, nil
+}
+
+// Close shuts down the headless browser.
+func (s *Screenshotter) Close() {
+	s.cancel()
+}
+
+// Capture loads the URL in a new browser tab and returns a PNG image of the page.
+func (s *Screenshotter) Capture(ctx context.Context, u string) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	case s.sem <- struct{}{}:
+	}
+	defer func() { <-s.sem }()
+
+	tctx, cancel := chromedp.NewContext(s.ctx)
+	defer cancel()
+	tctx, tcancel := context.WithTimeout(tctx, screenshotTimeout)
+	defer tcancel()
+	// Stop the capture when the enumeration is cancelled
+	go func() {
+		select {
+		case <-ctx.Done():
+			tcancel()
+		case <-tctx.Done():
+		}
+	}()
+
+	var buf []byte
+	if err := chromedp.Run(tctx, chromedp.Navigate(u), chromedp.CaptureScreenshot(&buf)); err != nil {
+		return nil, fmt.Errorf("failed to capture a screenshot of %s: %v", u, err)
+	}
+	return buf, nil
+}
+
+// ScreenshotFilename returns the name of the PNG file used to store the screenshot of the URL.
+func ScreenshotFilename(u string) string {
+	u = strings.Replace(u, "://", "_", 1)
+	return strings.Trim(screenshotNameRE.ReplaceAllString(u, "_"), "_") + ".png"
+}
@@ -0,0 +1,21 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import "testing"
+
+func TestScreenshotFilename(t *testing.T) {
+	tests := map[string]string{
+		"https://www.owasp.org":           "https_www.owasp.org.png",
+		"http://www.owasp.org:8080/login": "http_www.owasp.org_8080_login.png",
+		"https://owasp.org/?q=a&b=c":      "https_owasp.org_q_a_b_c.png",
+	}
+
+	for u, want := range tests {
+		if got := ScreenshotFilename(u); got != want {
+			t.Errorf("ScreenshotFilename(%s) = %s, want %s", u, got, want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"fmt"
+	"net/textproto"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/owasp-amass/amass/v3/resources"
+)
+
+var (
+	cookieNameRE = regexp.MustCompile(`(?:^|,\s*)([^=;,\s]+)=([^;,]*)`)
+	metaTagRE    = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	metaAttrRE   = regexp.MustCompile(`(?is)\b(name|property|content)\s*=\s*["']([^"']*)["']`)
+)
+
+// Technology is a web technology identified in an HTTP response.
+type Technology struct {
+	Name       string
+	Version    string
+	Categories []string
+}
+
+// String returns the technology name, followed by the version when known.
+func (t *Technology) String() string {
+	if t.Version == "" {
+		return t.Name
+	}
+	return t.Name + "/" + t.Version
+}
+
+type techPattern struct {
+	name       string
+	categories []string
+	headers    map[string]*regexp.Regexp
+	cookies    map[string]*regexp.Regexp
+	html       []*regexp.Regexp
+	meta       map[string]*regexp.Regexp
+	implies    []string
+}
+
+// TechDetector identifies the web technologies used by a host from the headers,
+// cookies and body of its HTTP responses.
+type TechDetector struct {
+	patterns []*techPattern
+	byName   map[string]*techPattern
+}
+
+// NewTechDetector returns a TechDetector that matches responses against the provided fingerprints.
+func NewTechDetector(fps []*resources.TechFingerprint) (*TechDetector, error) {
+	d := &TechDetector{byName: make(map[string]*techPattern)}
+
+	for _, fp := range fps {
+		p := &techPattern{
+			name:       fp.Name,
+			categories: fp.Categories,
+			headers:    make(map[string]*regexp.Regexp),
+			cookies:    make(map[string]*regexp.Regexp),
+			meta:       make(map[string]*regexp.Regexp),
+			implies:    fp.Implies,
+		}
+
+		for k, v := range fp.Headers {
+			re, err := compileSignature(fp.Name, v)
+			if err != nil {
+				return nil, err
+			}
+			p.headers[textproto.CanonicalMIMEHeaderKey(k)] = re
+		}
+		for k, v := range fp.Cookies {
+			re, err := compileSignature(fp.Name, v)
+			if err != nil {
+				return nil, err
+			}
+			p.cookies[k] = re
+		}
+		for k, v := range fp.Meta {
+			re, err := compileSignature(fp.Name, v)
+			if err != nil {
+				return nil, err
+			}
+			p.meta[strings.ToLower(k)] = re
+		}
+		for _, v := range fp.HTML {
+			re, err := compileSignature(fp.Name, v)
+			if err != nil {
+				return nil, err
+			}
+			if re != nil {
+				p.html = append(p.html, re)
+			}
+		}
+
+		d.patterns = append(d.patterns, p)
+		d.byName[fp.Name] = p
+	}
+	return d, nil
+}
+
+func compileSignature(name, sig string) (*regexp.Regexp, error) {
+	if sig == "" {
+		return nil, nil
+	}
+
+	re, err := regexp.Compile("(?i)" + sig)
+	if err != nil {
+		return nil, fmt.Errorf("the technology fingerprint for %s has an invalid signature '%s': %v", name, sig, err)
+	}
+	return re, nil
+}
+
+// Detect returns the technologies identified in the response, including
+// the technologies implied by them, sorted by name.
+func (d *TechDetector) Detect(resp *Response) []*Technology {
+	if resp == nil {
+		return nil
+	}
+
+	header := make(map[string]string, len(resp.Header))
+	for k, v := range resp.Header {
+		header[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
+
+	cookies := make(map[string]string)
+	for _, m := range cookieNameRE.FindAllStringSubmatch(header["Set-Cookie"], -1) {
+		cookies[m[1]] = m[2]
+	}
+
+	meta := make(map[string]string)
+	for _, tag := range metaTagRE.FindAllString(resp.Body, -1) {
+		var name, content string
+
+		for _, m := range metaAttrRE.FindAllStringSubmatch(tag, -1) {
+			if strings.EqualFold(m[1], "content") {
+				content = m[2]
+			} else {
+				name = strings.ToLower(m[2])
+			}
+		}
+		if name != "" {
+			meta[name] = content
+		}
+	}
+
+	found := make(map[string]*Technology)
+	for _, p := range d.patterns {
+		if matched, version := p.match(header, cookies, meta, resp.Body); matched {
+			found[p.name] = &Technology{
+				Name:       p.name,
+				Version:    version,
+				Categories: p.categories,
+			}
+		}
+	}
+
+	for _, t := range mapValues(found) {
+		d.addImplied(found, t.Name)
+	}
+
+	results := mapValues(found)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+func (d *TechDetector) addImplied(found map[string]*Technology, name string) {
+	p, ok := d.byName[name]
+	if !ok {
+		return
+	}
+
+	for _, implied := range p.implies {
+		if _, dup := found[implied]; dup {
+			continue
+		}
+
+		t := &Technology{Name: implied}
+		if ip, ok := d.byName[implied]; ok {
+			t.Categories = ip.categories
+		}
+		found[implied] = t
+		d.addImplied(found, implied)
+	}
+}
+
+func (p *techPattern) match(header, cookies, meta map[string]string, body string) (bool, string) {
+	var matched bool
+	var version string
+
+	check := func(re *regexp.Regexp, value string) {
+		if re == nil {
+			matched = true
+			return
+		}
+		if m := re.FindStringSubmatch(value); m != nil {
+			matched = true
+			if version == "" && len(m) > 1 {
+				version = m[1]
+			}
+		}
+	}
+
+	for k, re := range p.headers {
+		if v, ok := header[k]; ok {
+			check(re, v)
+		}
+	}
+	for k, re := range p.cookies {
+		if v, ok := cookies[k]; ok {
+			check(re, v)
+		}
+	}
+	for k, re := range p.meta {
+		if v, ok := meta[k]; ok {
+			check(re, v)
+		}
+	}
+	for _, re := range p.html {
+		check(re, body)
+	}
+	return matched, version
+}
+
+func mapValues(m map[string]*Technology) []*Technology {
+	results := make([]*Technology, 0, len(m))
+
+	for _, t := range m {
+		results = append(results, t)
+	}
+	return results
+}
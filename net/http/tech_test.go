@@ -0,0 +1,61 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"testing"
+
+	"github.com/owasp-amass/amass/v3/resources"
+)
+
+func TestTechDetector(t *testing.T) {
+	fps, err := resources.GetTechFingerprints()
+	if err != nil {
+		t.Fatalf("GetTechFingerprints() error = %v", err)
+	}
+
+	d, err := NewTechDetector(fps)
+	if err != nil {
+		t.Fatalf("NewTechDetector() error = %v", err)
+	}
+
+	resp := &Response{
+		Header: Header{
+			"Server":     "nginx/1.18.0",
+			"Set-Cookie": "PHPSESSID=abc123; path=/, other=1; Expires=Wed, 21 Oct 2015 07:28:00 GMT",
+		},
+		Body: `<html><head><meta name="generator" content="WordPress 6.1.1" /></head>` +
+			`<body><script src="/wp-includes/js/jquery/jquery.min.js"></script></body></html>`,
+	}
+
+	got := make(map[string]string)
+	for _, tech := range d.Detect(resp) {
+		got[tech.Name] = tech.Version
+	}
+
+	want := map[string]string{
+		"nginx":     "1.18.0",
+		"PHP":       "",
+		"WordPress": "6.1.1",
+		"jQuery":    "",
+		"MySQL":     "",
+	}
+	for name, version := range want {
+		if v, ok := got[name]; !ok || v != version {
+			t.Errorf("Detect() did not return %s with the version %q: %v", name, version, got)
+		}
+	}
+	if _, ok := got["Apache"]; ok {
+		t.Errorf("Detect() returned a technology without a matching signature: %v", got)
+	}
+}
+
+func TestNewTechDetectorInvalidSignature(t *testing.T) {
+	fps := []*resources.TechFingerprint{{Name: "Example", HTML: []string{"("}}}
+
+	if _, err := NewTechDetector(fps); err == nil {
+		t.Errorf("NewTechDetector() accepted an invalid signature")
+	}
+}
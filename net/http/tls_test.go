@@ -0,0 +1,117 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCert creates a self-signed client certificate and returns the paths of the PEM files.
+func writeClientCert(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the client key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "amass"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create the client certificate: %v", err)
+	}
+	kder, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal the client key: %v", err)
+	}
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	_ = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	_ = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder}), 0600)
+	return certFile, keyFile
+}
+
+func TestTLSConfig(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "verified")
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0)
+	ts.StartTLS()
+	defer ts.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	_ = os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0600)
+	certFile, keyFile := writeClientCert(t, dir)
+
+	tests := []struct {
+		name     string
+		ca       string
+		cert     string
+		key      string
+		insecure bool
+		success  bool
+	}{
+		{"trusted CA and client certificate", caFile, certFile, keyFile, false, true},
+		{"unknown authority", "", certFile, keyFile, false, false},
+		{"missing client certificate", caFile, "", "", false, false},
+		{"verification disabled", "", certFile, keyFile, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := TLSConfig(tt.ca, tt.cert, tt.key, tt.insecure)
+			if err != nil {
+				t.Fatalf("TLSConfig() error = %v", err)
+			}
+
+			resp, err := RequestWebPage(context.TODO(), &Request{URL: ts.URL, TLS: c})
+			if success := err == nil && resp.Body == "verified"; success != tt.success {
+				t.Errorf("RequestWebPage() error = %v, expected success %v", err, tt.success)
+			}
+		})
+	}
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, _ := writeClientCert(t, dir)
+
+	if _, err := TLSConfig("", certFile, "", false); err == nil {
+		t.Error("TLSConfig did not report the missing client key")
+	}
+	if _, err := TLSConfig(filepath.Join(dir, "missing.pem"), "", "", false); err == nil {
+		t.Error("TLSConfig did not report the missing CA bundle")
+	}
+
+	empty := filepath.Join(dir, "empty.pem")
+	_ = os.WriteFile(empty, []byte("not a certificate"), 0600)
+	if _, err := TLSConfig(empty, "", "", false); err == nil {
+		t.Error("TLSConfig did not report the CA bundle without certificates")
+	}
+}
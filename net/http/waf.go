@@ -0,0 +1,155 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// GenericWAF is reported when requests containing attack payloads are blocked without revealing the firewall.
+const GenericWAF = "Generic"
+
+// The query sent to provoke the web application firewall into blocking the request
+const wafPayload = "id=1%27%20OR%20%271%27=%271&q=%3Cscript%3Ealert(1)%3C/script%3E&file=../../../../etc/passwd"
+
+type wafSignature struct {
+	name string
+	// The header values are substrings matched case-insensitively, where an empty value only requires the header
+	headers map[string]string
+	cookies []string
+	body    []string
+}
+
+var wafSignatures = []*wafSignature{
+	{
+		name:    "Cloudflare",
+		headers: map[string]string{"Server": "cloudflare", "CF-RAY": ""},
+		cookies: []string{"__cf_bm", "__cfduid", "cf_clearance"},
+		body:    []string{"Attention Required! | Cloudflare", "cf-error-details"},
+	},
+	{
+		name:    "Akamai",
+		headers: map[string]string{"Server": "AkamaiGHost", "X-Akamai-Transformed": ""},
+		cookies: []string{"ak_bmsc", "bm_sv", "_abck"},
+		body:    []string{"errors.edgesuite.net"},
+	},
+	{
+		name:    "AWS WAF",
+		headers: map[string]string{"X-Amzn-Waf-Action": ""},
+		cookies: []string{"aws-waf-token", "awsalb"},
+		body:    []string{"Request blocked.", "Generated by cloudfront (CloudFront)"},
+	},
+	{
+		name:    "Imperva",
+		headers: map[string]string{"X-Iinfo": "", "X-CDN": "Incapsula"},
+		cookies: []string{"incap_ses_", "visid_incap_", "nlbi_"},
+		body:    []string{"Incapsula incident ID", "_Incapsula_Resource"},
+	},
+	{
+		name:    "F5 BIG-IP ASM",
+		headers: map[string]string{"X-WA-Info": ""},
+		cookies: []string{"TS01", "BIGipServer"},
+		body:    []string{"The requested URL was rejected. Please consult with your administrator."},
+	},
+	{
+		name:    "Sucuri",
+		headers: map[string]string{"Server": "Sucuri", "X-Sucuri-ID": ""},
+		body:    []string{"Sucuri WebSite Firewall"},
+	},
+	{
+		name:    "Azure Application Gateway",
+		headers: map[string]string{"Server": "Microsoft-Azure-Application-Gateway"},
+		cookies: []string{"ApplicationGatewayAffinity"},
+		body:    []string{"Microsoft-Azure-Application-Gateway"},
+	},
+	{
+		name:    "Citrix NetScaler",
+		headers: map[string]string{"Via": "NS-CACHE"},
+		cookies: []string{"citrix_ns_id", "NSC_", "ns_af"},
+		body:    []string{"NS Transaction ID"},
+	},
+	{
+		name:    "ModSecurity",
+		headers: map[string]string{"Server": "Mod_Security"},
+		body:    []string{"This error was generated by Mod_Security", "ModSecurity Action"},
+	},
+}
+
+// The status codes commonly returned by firewalls when blocking requests
+var wafBlockStatus = map[int]struct{}{
+	403: {}, 406: {}, 419: {}, 429: {}, 501: {}, 999: {},
+}
+
+// DetectWAF returns the name of the web application firewall protecting the URL of the probe result,
+// or an empty string when none was detected. The firewall is identified from the headers and cookies
+// of the response and, when they provide no indication, the response to a request containing attack payloads.
+func DetectWAF(ctx context.Context, r *ProbeResult) string {
+	if r == nil || r.Response == nil {
+		return ""
+	}
+	if name := matchWAF(r.Response, false); name != "" {
+		return name
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+	u.RawQuery = wafPayload
+
+	blocked, err := Probe(ctx, u.String())
+	if err != nil || blocked.Response == nil {
+		return ""
+	}
+	if name := matchWAF(blocked.Response, true); name != "" {
+		return name
+	}
+	// The payloads were rejected, while the original request was not
+	if _, found := wafBlockStatus[blocked.Response.StatusCode]; found {
+		if _, found := wafBlockStatus[r.Response.StatusCode]; !found {
+			return GenericWAF
+		}
+	}
+	return ""
+}
+
+func matchWAF(resp *Response, body bool) string {
+	header := make(map[string]string, len(resp.Header))
+	for k, v := range resp.Header {
+		header[textproto.CanonicalMIMEHeaderKey(k)] = strings.ToLower(v)
+	}
+
+	var cookies []string
+	for _, m := range cookieNameRE.FindAllStringSubmatch(header["Set-Cookie"], -1) {
+		cookies = append(cookies, strings.ToLower(m[1]))
+	}
+
+	for _, sig := range wafSignatures {
+		for k, v := range sig.headers {
+			if hv, found := header[textproto.CanonicalMIMEHeaderKey(k)]; found && strings.Contains(hv, strings.ToLower(v)) {
+				return sig.name
+			}
+		}
+		for _, prefix := range sig.cookies {
+			for _, c := range cookies {
+				if strings.HasPrefix(c, strings.ToLower(prefix)) {
+					return sig.name
+				}
+			}
+		}
+		if !body {
+			continue
+		}
+		for _, s := range sig.body {
+			if strings.Contains(resp.Body, s) {
+				return sig.name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMatchWAF(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *Response
+		body bool
+		want string
+	}{
+		{
+			name: "Cloudflare header",
+			resp: &Response{Header: Header{"server": "cloudflare", "cf-ray": "7d1a2b3c4d5e6f70-IAD"}},
+			want: "Cloudflare",
+		},
+		{
+			name: "Imperva cookie",
+			resp: &Response{Header: Header{"Set-Cookie": "visid_incap_123456=abc; path=/, incap_ses_1_123456=def"}},
+			want: "Imperva",
+		},
+		{
+			name: "AWS WAF block page",
+			resp: &Response{StatusCode: 403, Body: "<H1>403 ERROR</H1><H2>The request could not be satisfied.</H2>Request blocked."},
+			body: true,
+			want: "AWS WAF",
+		},
+		{
+			name: "block page ignored",
+			resp: &Response{Body: "Request blocked."},
+			want: "",
+		},
+		{
+			name: "no firewall",
+			resp: &Response{Header: Header{"Server": "nginx/1.18.0", "Set-Cookie": "PHPSESSID=abc123"}},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchWAF(tt.resp, tt.body); got != tt.want {
+				t.Errorf("matchWAF() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectWAF(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("<html><head><title>Home</title></head></html>"))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	r, err := Probe(ctx, ts.URL)
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if got := DetectWAF(ctx, r); got != GenericWAF {
+		t.Errorf("DetectWAF() = %q, want %q", got, GenericWAF)
+	}
+
+	r.Response.StatusCode = http.StatusForbidden
+	if got := DetectWAF(ctx, r); got != "" {
+		t.Errorf("DetectWAF() reported a firewall for a page that rejects all requests: %q", got)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type masscanRecord struct {
+	IP    string `json:"ip"`
+	Ports []struct {
+		Port    int    `json:"port"`
+		Proto   string `json:"proto"`
+		Status  string `json:"status"`
+		Service *struct {
+			Name string `json:"name"`
+		} `json:"service"`
+	} `json:"ports"`
+}
+
+// ParseMasscan returns the hosts with open TCP ports in the JSON (-oJ) or list (-oL) output of masscan.
+// The ports are identified as offering TLS by the banners grabbed, or by the ports commonly serving TLS.
+func ParseMasscan(r io.Reader) ([]*ScanHost, error) {
+	var order []string
+	hosts := make(map[string]*ScanHost)
+	add := func(addr string, port int, service string) {
+		if ip := net.ParseIP(addr); ip == nil || port <= 0 {
+			return
+		}
+
+		host, found := hosts[addr]
+		if !found {
+			host = &ScanHost{Address: addr, Source: "Masscan"}
+			hosts[addr] = host
+			order = append(order, addr)
+		}
+		if service == "" {
+			host.Ports = appendUniqueInt(host.Ports, port)
+		}
+		if service == "ssl" || service == "x509" || (service == "" && likelyTLSPort(port)) {
+			host.TLSPorts = appendUniqueInt(host.TLSPorts, port)
+		}
+	}
+
+	var num int
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		num++
+
+		line := strings.Trim(strings.TrimSpace(scanner.Text()), ",")
+		if line == "" || line == "[" || line == "]" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "{finished") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "{") {
+			var rec masscanRecord
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				return nil, fmt.Errorf("failed to parse line %d of the masscan JSON output: %v", num, err)
+			}
+
+			for _, p := range rec.Ports {
+				if p.Proto != "tcp" {
+					continue
+				}
+				if p.Service != nil {
+					add(rec.IP, p.Port, strings.ToLower(p.Service.Name))
+				} else if p.Status == "" || p.Status == "open" {
+					add(rec.IP, p.Port, "")
+				}
+			}
+			continue
+		}
+
+		// The list lines are formatted as 'open tcp 443 192.0.2.1 1600000000', while the
+		// banners follow the timestamp with the service name
+		fields := strings.Fields(line)
+		if len(fields) < 4 || (fields[0] != "open" && fields[0] != "banner") {
+			return nil, fmt.Errorf("line %d is not in the masscan list format: %s", num, line)
+		}
+		if fields[1] != "tcp" {
+			continue
+		}
+
+		port, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d of the masscan output has an invalid port: %s", num, fields[2])
+		}
+		switch fields[0] {
+		case "open":
+			add(fields[3], port, "")
+		case "banner":
+			if len(fields) > 5 {
+				add(fields[3], port, strings.ToLower(fields[5]))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the masscan output: %v", err)
+	}
+
+	var results []*ScanHost
+	for _, addr := range order {
+		host := hosts[addr]
+		if len(host.Ports) == 0 {
+			continue
+		}
+		// The banners are only kept for the ports found open
+		var tls []int
+		for _, port := range host.TLSPorts {
+			for _, open := range host.Ports {
+				if port == open {
+					tls = append(tls, port)
+					break
+				}
+			}
+		}
+		host.TLSPorts = tls
+
+		sort.Ints(host.Ports)
+		sort.Ints(host.TLSPorts)
+		results = append(results, host)
+	}
+	return results, nil
+}
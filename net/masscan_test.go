@@ -0,0 +1,82 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMasscan(t *testing.T) {
+	expected := []*ScanHost{
+		{Address: "192.0.2.1", Source: "Masscan", Ports: []int{22, 443, 8000}, TLSPorts: []int{443, 8000}},
+		{Address: "192.0.2.7", Source: "Masscan", Ports: []int{8443}, TLSPorts: []int{8443}},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{
+			name: "JSON",
+			output: `[
+{   "ip": "192.0.2.1",   "timestamp": "1600000000", "ports": [ {"port": 443, "proto": "tcp", "status": "open", "reason": "syn-ack", "ttl": 54} ] },
+{   "ip": "192.0.2.1",   "timestamp": "1600000000", "ports": [ {"port": 22, "proto": "tcp", "status": "open", "reason": "syn-ack", "ttl": 54} ] },
+{   "ip": "192.0.2.1",   "timestamp": "1600000000", "ports": [ {"port": 8000, "proto": "tcp", "status": "open", "reason": "syn-ack", "ttl": 54} ] },
+{   "ip": "192.0.2.1",   "timestamp": "1600000001", "ports": [ {"port": 8000, "proto": "tcp", "service": {"name": "ssl", "banner": "TLS/1.2 cipher:0xc02f"} } ] },
+{   "ip": "192.0.2.7",   "timestamp": "1600000000", "ports": [ {"port": 8443, "proto": "tcp", "status": "open", "reason": "syn-ack", "ttl": 54} ] },
+{   "ip": "192.0.2.9",   "timestamp": "1600000000", "ports": [ {"port": 53, "proto": "udp", "status": "open"} ] },
+{finished: 1}
+]`,
+		},
+		{
+			name: "list",
+			output: `#masscan
+open tcp 443 192.0.2.1 1600000000
+open tcp 22 192.0.2.1 1600000000
+open tcp 8000 192.0.2.1 1600000000
+banner tcp 8000 192.0.2.1 1600000001 ssl TLS/1.2 cipher:0xc02f
+open tcp 8443 192.0.2.7 1600000000
+open udp 53 192.0.2.9 1600000000
+# end`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hosts, err := ParseMasscan(strings.NewReader(tt.output))
+			if err != nil {
+				t.Fatalf("ParseMasscan() error = %v", err)
+			}
+			if !reflect.DeepEqual(hosts, expected) {
+				for _, h := range hosts {
+					t.Logf("%+v", *h)
+				}
+				t.Error("ParseMasscan() did not return the expected hosts")
+			}
+		})
+	}
+
+	if _, err := ParseMasscan(strings.NewReader("Discovered open port 443/tcp on 192.0.2.1")); err == nil {
+		t.Error("ParseMasscan() did not report the output in an unknown format")
+	}
+}
+
+func TestMergeScanHosts(t *testing.T) {
+	merged := MergeScanHosts([]*ScanHost{
+		{Address: "192.0.2.1", Source: "Nmap", Names: []string{"www.example.com"}, Ports: []int{443}, TLSPorts: []int{443}},
+		{Address: "192.0.2.2", Source: "Masscan", Ports: []int{80}},
+		{Address: "192.0.2.1", Source: "Masscan", Ports: []int{22, 443, 8443}, TLSPorts: []int{443, 8443}},
+	})
+
+	expected := []*ScanHost{
+		{Address: "192.0.2.1", Source: "Nmap", Names: []string{"www.example.com"}, Ports: []int{22, 443, 8443}, TLSPorts: []int{443, 8443}},
+		{Address: "192.0.2.2", Source: "Masscan", Ports: []int{80}},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("MergeScanHosts() did not return the expected hosts")
+	}
+}
@@ -55,7 +55,7 @@ func init() {
 	}
 }
 
-// DialContext performs the dial using global variables (e.g. LocalAddr).
+// DialContext performs the dial using global variables (e.g. LocalAddr and Audit).
 func DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
 	d := &net.Dialer{DualStack: true}
 
@@ -68,6 +68,9 @@ func DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := Audit.Allow(network, addr); err != nil {
+		return nil, err
+	}
 
 	if LocalAddr != nil {
 		addr, _, err := net.ParseCIDR(LocalAddr.String())
@@ -0,0 +1,193 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+)
+
+// The ports commonly serving TLS, which are checked when the scanner did not identify the service
+var tlsPorts = map[int]struct{}{
+	443:  {},
+	465:  {},
+	636:  {},
+	853:  {},
+	990:  {},
+	993:  {},
+	995:  {},
+	4443: {},
+	6443: {},
+	8443: {},
+	9443: {},
+}
+
+// The service names reported by nmap for the protocols running over TLS
+var tlsServices = map[string]struct{}{
+	"https":     {},
+	"https-alt": {},
+	"ssl":       {},
+	"imaps":     {},
+	"pop3s":     {},
+	"smtps":     {},
+	"ldaps":     {},
+	"ftps":      {},
+	"domain-s":  {},
+}
+
+// ScanHost is a live host found by a port scanner, along with the names and open ports reported for it.
+type ScanHost struct {
+	Address string
+	Names   []string
+	// The name of the port scanner, used as the data source of the host
+	Source string
+	// The open TCP ports in ascending order
+	Ports []int
+	// The open TCP ports offering TLS, where the certificates can be pulled from
+	TLSPorts []int
+}
+
+// MergeScanHosts combines the hosts found at the same address by several scans, such as those of
+// nmap and masscan, keeping the source of the first scan.
+func MergeScanHosts(hosts []*ScanHost) []*ScanHost {
+	var merged []*ScanHost
+	byAddr := make(map[string]*ScanHost)
+
+	for _, h := range hosts {
+		host, found := byAddr[h.Address]
+		if !found {
+			host = &ScanHost{Address: h.Address, Source: h.Source}
+			byAddr[h.Address] = host
+			merged = append(merged, host)
+		}
+
+		for _, name := range h.Names {
+			host.Names = appendUniqueString(host.Names, name)
+		}
+		for _, port := range h.Ports {
+			host.Ports = appendUniqueInt(host.Ports, port)
+		}
+		for _, port := range h.TLSPorts {
+			host.TLSPorts = appendUniqueInt(host.TLSPorts, port)
+		}
+	}
+
+	for _, host := range merged {
+		sort.Ints(host.Ports)
+		sort.Ints(host.TLSPorts)
+	}
+	return merged
+}
+
+type nmapRun struct {
+	Hosts []struct {
+		Status struct {
+			State string `xml:"state,attr"`
+		} `xml:"status"`
+		Addresses []struct {
+			Addr string `xml:"addr,attr"`
+			Type string `xml:"addrtype,attr"`
+		} `xml:"address"`
+		Hostnames []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"hostnames>hostname"`
+		Ports []struct {
+			Protocol string `xml:"protocol,attr"`
+			ID       int    `xml:"portid,attr"`
+			State    struct {
+				State string `xml:"state,attr"`
+			} `xml:"state"`
+			Service *struct {
+				Name   string `xml:"name,attr"`
+				Tunnel string `xml:"tunnel,attr"`
+			} `xml:"service"`
+		} `xml:"ports>port"`
+	} `xml:"host"`
+}
+
+// ParseNmapXML returns the live hosts in the XML output of nmap (-oX). The hosts reported as down,
+// and the ports not found open, are left out.
+func ParseNmapXML(r io.Reader) ([]*ScanHost, error) {
+	var run nmapRun
+
+	if err := xml.NewDecoder(r).Decode(&run); err != nil {
+		return nil, fmt.Errorf("failed to parse the nmap XML output: %v", err)
+	}
+
+	var hosts []*ScanHost
+	for _, h := range run.Hosts {
+		if h.Status.State != "" && h.Status.State != "up" {
+			continue
+		}
+
+		var addr string
+		for _, a := range h.Addresses {
+			if (a.Type == "ipv4" || a.Type == "ipv6") && net.ParseIP(a.Addr) != nil {
+				addr = a.Addr
+				break
+			}
+		}
+		if addr == "" {
+			continue
+		}
+
+		host := &ScanHost{Address: addr, Source: "Nmap"}
+		for _, hn := range h.Hostnames {
+			if name := strings.Trim(strings.ToLower(strings.TrimSpace(hn.Name)), "."); name != "" {
+				host.Names = appendUniqueString(host.Names, name)
+			}
+		}
+		for _, p := range h.Ports {
+			if p.Protocol != "tcp" || p.State.State != "open" || p.ID <= 0 {
+				continue
+			}
+
+			host.Ports = appendUniqueInt(host.Ports, p.ID)
+			if (p.Service == nil && likelyTLSPort(p.ID)) || (p.Service != nil && tlsService(p.Service.Name, p.Service.Tunnel)) {
+				host.TLSPorts = appendUniqueInt(host.TLSPorts, p.ID)
+			}
+		}
+
+		sort.Ints(host.Ports)
+		sort.Ints(host.TLSPorts)
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+func tlsService(name, tunnel string) bool {
+	if tunnel == "ssl" {
+		return true
+	}
+	_, found := tlsServices[strings.ToLower(name)]
+	return found
+}
+
+func likelyTLSPort(port int) bool {
+	_, found := tlsPorts[port]
+	return found
+}
+
+func appendUniqueString(s []string, v string) []string {
+	for _, e := range s {
+		if e == v {
+			return s
+		}
+	}
+	return append(s, v)
+}
+
+func appendUniqueInt(s []int, v int) []int {
+	for _, e := range s {
+		if e == v {
+			return s
+		}
+	}
+	return append(s, v)
+}
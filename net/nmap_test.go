@@ -0,0 +1,69 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testNmapXML = `<?xml version="1.0" encoding="UTF-8"?>
+<nmaprun scanner="nmap" args="nmap -sV -oX scan.xml 192.0.2.0/30">
+<host>
+  <status state="up" reason="syn-ack"/>
+  <address addr="192.0.2.1" addrtype="ipv4"/>
+  <address addr="00:11:22:33:44:55" addrtype="mac"/>
+  <hostnames>
+    <hostname name="www.example.com" type="user"/>
+    <hostname name="WWW.example.com." type="PTR"/>
+    <hostname name="edge.example.net" type="PTR"/>
+  </hostnames>
+  <ports>
+    <port protocol="tcp" portid="8443"><state state="open"/><service name="http" tunnel="ssl"/></port>
+    <port protocol="tcp" portid="22"><state state="open"/><service name="ssh"/></port>
+    <port protocol="tcp" portid="443"><state state="open"/></port>
+    <port protocol="tcp" portid="993"><state state="open"/><service name="imaps"/></port>
+    <port protocol="tcp" portid="25"><state state="filtered"/><service name="smtp"/></port>
+    <port protocol="udp" portid="53"><state state="open"/><service name="domain"/></port>
+  </ports>
+</host>
+<host>
+  <status state="down" reason="no-response"/>
+  <address addr="192.0.2.2" addrtype="ipv4"/>
+</host>
+<host>
+  <status state="up" reason="echo-reply"/>
+  <address addr="2001:db8::1" addrtype="ipv6"/>
+</host>
+</nmaprun>`
+
+func TestParseNmapXML(t *testing.T) {
+	hosts, err := ParseNmapXML(strings.NewReader(testNmapXML))
+	if err != nil {
+		t.Fatalf("ParseNmapXML() error = %v", err)
+	}
+
+	expected := []*ScanHost{
+		{
+			Address:  "192.0.2.1",
+			Names:    []string{"www.example.com", "edge.example.net"},
+			Source:   "Nmap",
+			Ports:    []int{22, 443, 993, 8443},
+			TLSPorts: []int{443, 993, 8443},
+		},
+		{Address: "2001:db8::1", Source: "Nmap"},
+	}
+	if !reflect.DeepEqual(hosts, expected) {
+		for _, h := range hosts {
+			t.Logf("%+v", *h)
+		}
+		t.Errorf("ParseNmapXML() did not return the expected hosts")
+	}
+
+	if _, err := ParseNmapXML(strings.NewReader("Nmap scan report for 192.0.2.1")); err == nil {
+		t.Error("ParseNmapXML() did not report the output that is not XML")
+	}
+}
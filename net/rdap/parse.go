@@ -0,0 +1,143 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdap
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// rawObject contains the fields of interest across the RDAP object classes.
+type rawObject struct {
+	ClassName     string       `json:"objectClassName"`
+	Handle        string       `json:"handle"`
+	LDHName       string       `json:"ldhName"`
+	Name          string       `json:"name"`
+	Type          string       `json:"type"`
+	Country       string       `json:"country"`
+	Status        []string     `json:"status"`
+	IPVersion     string       `json:"ipVersion"`
+	StartAddress  string       `json:"startAddress"`
+	EndAddress    string       `json:"endAddress"`
+	ParentHandle  string       `json:"parentHandle"`
+	StartAutnum   int          `json:"startAutnum"`
+	EndAutnum     int          `json:"endAutnum"`
+	OriginAutnums []int        `json:"arin_originas0_originautnums"`
+	Entities      []*rawEntity `json:"entities"`
+	Events        []*rawEvent  `json:"events"`
+	Nameservers   []struct {
+		LDHName string `json:"ldhName"`
+	} `json:"nameservers"`
+	CIDRs []struct {
+		V4Prefix string `json:"v4prefix"`
+		V6Prefix string `json:"v6prefix"`
+		Length   int    `json:"length"`
+	} `json:"cidr0_cidrs"`
+}
+
+type rawEntity struct {
+	Handle   string          `json:"handle"`
+	Roles    []string        `json:"roles"`
+	VCard    json.RawMessage `json:"vcardArray"`
+	Entities []*rawEntity    `json:"entities"`
+}
+
+type rawEvent struct {
+	Action string `json:"eventAction"`
+	Date   string `json:"eventDate"`
+}
+
+func parseEntities(raw []*rawEntity) []*Entity {
+	var entities []*Entity
+
+	for _, r := range raw {
+		if r == nil {
+			continue
+		}
+
+		e := &Entity{
+			Handle:   r.Handle,
+			Roles:    r.Roles,
+			Entities: parseEntities(r.Entities),
+		}
+		e.Name, e.Org, e.Email = parseVCard(r.VCard)
+		entities = append(entities, e)
+	}
+	return entities
+}
+
+func parseEvents(raw []*rawEvent) []*Event {
+	var events []*Event
+
+	for _, r := range raw {
+		if r == nil || r.Action == "" {
+			continue
+		}
+
+		e := &Event{Action: r.Action}
+		if d, err := time.Parse(time.RFC3339, r.Date); err == nil {
+			e.Date = d
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// parseVCard extracts the name, organization and email address from a jCard (RFC 7095).
+func parseVCard(data json.RawMessage) (string, string, string) {
+	var card []interface{}
+	if len(data) == 0 || json.Unmarshal(data, &card) != nil || len(card) < 2 {
+		return "", "", ""
+	}
+
+	props, ok := card[1].([]interface{})
+	if !ok {
+		return "", "", ""
+	}
+
+	var name, org, email string
+	for _, p := range props {
+		prop, ok := p.([]interface{})
+		if !ok || len(prop) < 4 {
+			continue
+		}
+
+		key, _ := prop[0].(string)
+		value := vcardValue(prop[3])
+		switch strings.ToLower(key) {
+		case "fn":
+			if name == "" {
+				name = value
+			}
+		case "org":
+			if org == "" {
+				org = value
+			}
+		case "email":
+			if email == "" {
+				email = value
+			}
+		}
+	}
+	return name, org, email
+}
+
+func vcardValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case []interface{}:
+		var parts []string
+
+		for _, p := range val {
+			if s, ok := p.(string); ok && s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.TrimSpace(strings.Join(parts, " "))
+	}
+	return ""
+}
@@ -0,0 +1,460 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/net/http"
+)
+
+const (
+	// IANABootstrapURL is the base URL for the IANA RDAP bootstrap registry files.
+	IANABootstrapURL = "https://data.iana.org/rdap/"
+	// DefaultTTL is the amount of time responses are kept in the local cache.
+	DefaultTTL = 24 * time.Hour
+	// DefaultMaxEntries is the number of responses kept in the local cache.
+	DefaultMaxEntries = 10000
+	acceptRDAP        = "application/rdap+json, application/json"
+)
+
+// ErrNotFound is returned when the RDAP server has no record for the query.
+var ErrNotFound = errors.New("the RDAP server returned no record for the query")
+
+// Client performs RDAP lookups using the IANA bootstrap registries to select the correct server.
+type Client struct {
+	sync.Mutex
+	// BootstrapURL is the base URL for the bootstrap registry files.
+	BootstrapURL string
+	// TTL determines how long bootstrap data and responses are kept in the local cache.
+	TTL time.Duration
+	// MaxEntries is the number of responses kept in the local cache, which evicts the
+	// responses closest to expiration once the limit is reached.
+	MaxEntries int
+	boot       map[string]*bootstrap
+	cache      map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	body    string
+	expires time.Time
+}
+
+type bootstrap struct {
+	expires  time.Time
+	services []*bootService
+}
+
+type bootService struct {
+	entries []string
+	urls    []string
+}
+
+// NewClient returns a Client that bootstraps via the IANA registries.
+func NewClient() *Client {
+	return &Client{
+		BootstrapURL: IANABootstrapURL,
+		TTL:          DefaultTTL,
+		MaxEntries:   DefaultMaxEntries,
+		boot:         make(map[string]*bootstrap),
+		cache:        make(map[string]*cacheEntry),
+	}
+}
+
+// Entity represents an RDAP entity object, such as a registrant or abuse contact.
+type Entity struct {
+	Handle   string
+	Roles    []string
+	Name     string
+	Org      string
+	Email    string
+	Entities []*Entity
+}
+
+// Event represents an RDAP event, such as the registration or expiration of an object.
+type Event struct {
+	Action string
+	Date   time.Time
+}
+
+// Domain represents the registration data for a domain name.
+type Domain struct {
+	Handle      string
+	Name        string
+	Status      []string
+	Nameservers []string
+	Entities    []*Entity
+	Events      []*Event
+}
+
+// IPNetwork represents the registration data for an IP address block.
+type IPNetwork struct {
+	Handle       string
+	Name         string
+	Type         string
+	Country      string
+	IPVersion    string
+	StartAddress string
+	EndAddress   string
+	ParentHandle string
+	CIDRs        []string
+	OriginASNs   []int
+	Entities     []*Entity
+	Events       []*Event
+}
+
+// Autnum represents the registration data for an autonomous system number.
+type Autnum struct {
+	Handle      string
+	Name        string
+	Type        string
+	Country     string
+	StartAutnum int
+	EndAutnum   int
+	Entities    []*Entity
+	Events      []*Event
+}
+
+// Domain returns the registration data for the provided domain name.
+func (c *Client) Domain(ctx context.Context, name string) (*Domain, error) {
+	name = strings.ToLower(strings.Trim(strings.TrimSpace(name), "."))
+	if name == "" {
+		return nil, errors.New("failed to provide a valid domain name")
+	}
+
+	tld := name
+	if i := strings.LastIndex(name, "."); i != -1 {
+		tld = name[i+1:]
+	}
+
+	base, err := c.serverURL(ctx, "dns", func(entry string) bool {
+		return strings.EqualFold(entry, tld)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var m rawObject
+	if err := c.query(ctx, base+"domain/"+name, &m); err != nil {
+		return nil, err
+	}
+	if m.ClassName != "domain" {
+		return nil, fmt.Errorf("the RDAP server returned a %s object for the domain query", m.ClassName)
+	}
+
+	d := &Domain{
+		Handle:   m.Handle,
+		Name:     strings.ToLower(m.LDHName),
+		Status:   m.Status,
+		Entities: parseEntities(m.Entities),
+		Events:   parseEvents(m.Events),
+	}
+	for _, ns := range m.Nameservers {
+		if n := strings.ToLower(strings.Trim(ns.LDHName, ".")); n != "" {
+			d.Nameservers = append(d.Nameservers, n)
+		}
+	}
+	return d, nil
+}
+
+// IPNetwork returns the registration data for the network containing the provided IP address.
+func (c *Client) IPNetwork(ctx context.Context, addr string) (*IPNetwork, error) {
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return nil, fmt.Errorf("%s is not a valid IP address", addr)
+	}
+
+	registry := "ipv6"
+	if ip.To4() != nil {
+		registry = "ipv4"
+	}
+
+	base, err := c.serverURL(ctx, registry, func(entry string) bool {
+		_, ipnet, err := net.ParseCIDR(entry)
+		return err == nil && ipnet.Contains(ip)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var m rawObject
+	if err := c.query(ctx, base+"ip/"+ip.String(), &m); err != nil {
+		return nil, err
+	}
+	if m.ClassName != "ip network" {
+		return nil, fmt.Errorf("the RDAP server returned a %s object for the IP query", m.ClassName)
+	}
+
+	n := &IPNetwork{
+		Handle:       m.Handle,
+		Name:         m.Name,
+		Type:         m.Type,
+		Country:      m.Country,
+		IPVersion:    m.IPVersion,
+		StartAddress: m.StartAddress,
+		EndAddress:   m.EndAddress,
+		ParentHandle: m.ParentHandle,
+		OriginASNs:   m.OriginAutnums,
+		Entities:     parseEntities(m.Entities),
+		Events:       parseEvents(m.Events),
+	}
+	for _, cidr := range m.CIDRs {
+		prefix := cidr.V4Prefix
+		if prefix == "" {
+			prefix = cidr.V6Prefix
+		}
+		if prefix != "" {
+			n.CIDRs = append(n.CIDRs, prefix+"/"+strconv.Itoa(cidr.Length))
+		}
+	}
+	if len(n.CIDRs) == 0 {
+		n.CIDRs = rangeToCIDRs(n.StartAddress, n.EndAddress)
+	}
+	return n, nil
+}
+
+// Autnum returns the registration data for the provided autonomous system number.
+func (c *Client) Autnum(ctx context.Context, asn int) (*Autnum, error) {
+	if asn <= 0 {
+		return nil, fmt.Errorf("%d is not a valid autonomous system number", asn)
+	}
+
+	base, err := c.serverURL(ctx, "asn", func(entry string) bool {
+		start, end, ok := parseASNRange(entry)
+		return ok && asn >= start && asn <= end
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var m rawObject
+	if err := c.query(ctx, base+"autnum/"+strconv.Itoa(asn), &m); err != nil {
+		return nil, err
+	}
+	if m.ClassName != "autnum" {
+		return nil, fmt.Errorf("the RDAP server returned a %s object for the autnum query", m.ClassName)
+	}
+
+	return &Autnum{
+		Handle:      m.Handle,
+		Name:        m.Name,
+		Type:        m.Type,
+		Country:     m.Country,
+		StartAutnum: m.StartAutnum,
+		EndAutnum:   m.EndAutnum,
+		Entities:    parseEntities(m.Entities),
+		Events:      parseEvents(m.Events),
+	}, nil
+}
+
+// FindEntity returns the first entity, including nested entities, that has the provided role.
+func FindEntity(entities []*Entity, role string) *Entity {
+	for _, e := range entities {
+		for _, r := range e.Roles {
+			if strings.EqualFold(r, role) {
+				return e
+			}
+		}
+		if found := FindEntity(e.Entities, role); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// FindEvent returns the date of the first event that has the provided action.
+func FindEvent(events []*Event, action string) time.Time {
+	for _, e := range events {
+		if strings.EqualFold(e.Action, action) {
+			return e.Date
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Client) serverURL(ctx context.Context, registry string, match func(entry string) bool) (string, error) {
+	b, err := c.getBootstrap(ctx, registry)
+	if err != nil {
+		return "", err
+	}
+
+	for _, srv := range b.services {
+		for _, entry := range srv.entries {
+			if !match(entry) {
+				continue
+			}
+
+			url := srv.urls[0]
+			// Prefer the secure URL when the registry lists more than one
+			for _, u := range srv.urls {
+				if strings.HasPrefix(u, "https://") {
+					url = u
+					break
+				}
+			}
+			if !strings.HasSuffix(url, "/") {
+				url += "/"
+			}
+			return url, nil
+		}
+	}
+	return "", fmt.Errorf("the %s bootstrap registry has no RDAP server for the query", registry)
+}
+
+func (c *Client) getBootstrap(ctx context.Context, registry string) (*bootstrap, error) {
+	c.Lock()
+	if b, found := c.boot[registry]; found && time.Now().Before(b.expires) {
+		c.Unlock()
+		return b, nil
+	}
+	c.Unlock()
+
+	var m struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := c.query(ctx, c.BootstrapURL+registry+".json", &m); err != nil {
+		return nil, fmt.Errorf("failed to obtain the %s bootstrap registry: %v", registry, err)
+	}
+
+	b := &bootstrap{expires: time.Now().Add(c.TTL)}
+	for _, s := range m.Services {
+		if len(s) < 2 || len(s[1]) == 0 {
+			continue
+		}
+		b.services = append(b.services, &bootService{
+			entries: s[0],
+			urls:    s[1],
+		})
+	}
+	if len(b.services) == 0 {
+		return nil, fmt.Errorf("the %s bootstrap registry contained no services", registry)
+	}
+
+	c.Lock()
+	c.boot[registry] = b
+	c.Unlock()
+	return b, nil
+}
+
+func (c *Client) query(ctx context.Context, url string, v interface{}) error {
+	body, err := c.fetch(ctx, url)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(body), v)
+}
+
+func (c *Client) fetch(ctx context.Context, url string) (string, error) {
+	c.Lock()
+	if e, found := c.cache[url]; found {
+		if time.Now().Before(e.expires) {
+			c.Unlock()
+			return e.body, nil
+		}
+		delete(c.cache, url)
+	}
+	c.Unlock()
+
+	resp, err := http.RequestWebPage(ctx, &http.Request{
+		URL:    url,
+		Header: http.Header{"Accept": acceptRDAP},
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == 404 {
+		return "", ErrNotFound
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return "", fmt.Errorf("%s returned with status: %s", url, resp.Status)
+	}
+
+	c.store(url, resp.Body)
+	return resp.Body, nil
+}
+
+func (c *Client) store(url, body string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.MaxEntries > 0 && len(c.cache) >= c.MaxEntries {
+		now := time.Now()
+		// Remove the expired responses before evicting one still valid
+		for k, e := range c.cache {
+			if !now.Before(e.expires) {
+				delete(c.cache, k)
+			}
+		}
+
+		for len(c.cache) >= c.MaxEntries {
+			var oldest string
+			for k, e := range c.cache {
+				if oldest == "" || e.expires.Before(c.cache[oldest].expires) {
+					oldest = k
+				}
+			}
+			delete(c.cache, oldest)
+		}
+	}
+
+	c.cache[url] = &cacheEntry{
+		body:    body,
+		expires: time.Now().Add(c.TTL),
+	}
+}
+
+func parseASNRange(entry string) (int, int, bool) {
+	parts := strings.SplitN(entry, "-", 2)
+
+	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	end := start
+	if len(parts) == 2 {
+		if end, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, end, true
+}
+
+func rangeToCIDRs(start, end string) []string {
+	first := net.ParseIP(start)
+	last := net.ParseIP(end)
+	if first == nil || last == nil {
+		return nil
+	}
+
+	if f4, l4 := first.To4(), last.To4(); f4 != nil && l4 != nil {
+		first, last = f4, l4
+	} else {
+		first, last = first.To16(), last.To16()
+	}
+
+	// Find the longest common prefix that covers the entire range
+	bits := len(first) * 8
+	ones := 0
+	for ; ones < bits; ones++ {
+		mask := net.CIDRMask(ones+1, bits)
+		if !first.Mask(mask).Equal(last.Mask(mask)) {
+			break
+		}
+	}
+
+	ipnet := &net.IPNet{
+		IP:   first.Mask(net.CIDRMask(ones, bits)),
+		Mask: net.CIDRMask(ones, bits),
+	}
+	return []string{ipnet.String()}
+}
@@ -0,0 +1,201 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testDomainResp = `{
+  "objectClassName": "domain",
+  "handle": "2336799_DOMAIN_COM-VRSN",
+  "ldhName": "EXAMPLE.COM",
+  "status": ["client delete prohibited"],
+  "nameservers": [{"objectClassName": "nameserver", "ldhName": "A.IANA-SERVERS.NET"}],
+  "events": [{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"}],
+  "entities": [{
+    "objectClassName": "entity",
+    "handle": "376",
+    "roles": ["registrar"],
+    "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "RESERVED-IANA"]]],
+    "entities": [{
+      "objectClassName": "entity",
+      "roles": ["abuse"],
+      "vcardArray": ["vcard", [["fn", {}, "text", "Abuse"], ["email", {}, "text", "abuse@iana.org"]]]
+    }]
+  }]
+}`
+
+const testNetworkResp = `{
+  "objectClassName": "ip network",
+  "handle": "NET-93-184-216-0-1",
+  "name": "EDGECAST-NETBLK-03",
+  "ipVersion": "v4",
+  "startAddress": "93.184.216.0",
+  "endAddress": "93.184.216.255",
+  "arin_originas0_originautnums": [15133]
+}`
+
+const testAutnumResp = `{
+  "objectClassName": "autnum",
+  "handle": "AS15133",
+  "name": "EDGECAST",
+  "startAutnum": 15133,
+  "endAutnum": 15133,
+  "events": [{"eventAction": "registration", "eventDate": "2000-06-05T00:00:00Z"}]
+}`
+
+func testServer() *httptest.Server {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+
+	bootstrap := func(entry string) string {
+		return fmt.Sprintf(`{"services": [[["%s"], ["%s/rdap/"]]]}`, entry, srv.URL)
+	}
+	mux.HandleFunc("/boot/dns.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, bootstrap("com"))
+	})
+	mux.HandleFunc("/boot/ipv4.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, bootstrap("93.0.0.0/8"))
+	})
+	mux.HandleFunc("/boot/asn.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, bootstrap("15000-16000"))
+	})
+	mux.HandleFunc("/rdap/domain/example.com", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testDomainResp)
+	})
+	mux.HandleFunc("/rdap/ip/93.184.216.34", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testNetworkResp)
+	})
+	mux.HandleFunc("/rdap/autnum/15133", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testAutnumResp)
+	})
+	return srv
+}
+
+func testClient(srv *httptest.Server) *Client {
+	c := NewClient()
+	c.BootstrapURL = srv.URL + "/boot/"
+	return c
+}
+
+func TestDomain(t *testing.T) {
+	srv := testServer()
+	defer srv.Close()
+	c := testClient(srv)
+
+	d, err := c.Domain(context.Background(), "Example.com.")
+	if err != nil {
+		t.Fatalf("Domain returned an error: %v", err)
+	}
+	if d.Name != "example.com" {
+		t.Errorf("Domain returned the name %s", d.Name)
+	}
+	if len(d.Nameservers) != 1 || d.Nameservers[0] != "a.iana-servers.net" {
+		t.Errorf("Domain returned the nameservers %v", d.Nameservers)
+	}
+	if FindEvent(d.Events, "registration").Year() != 1995 {
+		t.Errorf("Domain failed to parse the registration event")
+	}
+	if e := FindEntity(d.Entities, "abuse"); e == nil || e.Email != "abuse@iana.org" {
+		t.Errorf("Domain failed to parse the nested abuse entity")
+	}
+	if e := FindEntity(d.Entities, "registrar"); e == nil || e.Name != "RESERVED-IANA" {
+		t.Errorf("Domain failed to parse the registrar entity")
+	}
+
+	if _, err := c.Domain(context.Background(), "missing.com"); err != ErrNotFound {
+		t.Errorf("Domain returned %v for a missing domain", err)
+	}
+	if _, err := c.Domain(context.Background(), "example.org"); err == nil {
+		t.Errorf("Domain did not fail for a TLD missing from the bootstrap registry")
+	}
+}
+
+func TestIPNetwork(t *testing.T) {
+	srv := testServer()
+	defer srv.Close()
+	c := testClient(srv)
+
+	n, err := c.IPNetwork(context.Background(), "93.184.216.34")
+	if err != nil {
+		t.Fatalf("IPNetwork returned an error: %v", err)
+	}
+	if len(n.CIDRs) != 1 || n.CIDRs[0] != "93.184.216.0/24" {
+		t.Errorf("IPNetwork returned the CIDRs %v", n.CIDRs)
+	}
+	if len(n.OriginASNs) != 1 || n.OriginASNs[0] != 15133 {
+		t.Errorf("IPNetwork returned the origin ASNs %v", n.OriginASNs)
+	}
+	if _, err := c.IPNetwork(context.Background(), "not.an.address"); err == nil {
+		t.Errorf("IPNetwork did not fail for an invalid address")
+	}
+}
+
+func TestAutnum(t *testing.T) {
+	srv := testServer()
+	defer srv.Close()
+	c := testClient(srv)
+
+	a, err := c.Autnum(context.Background(), 15133)
+	if err != nil {
+		t.Fatalf("Autnum returned an error: %v", err)
+	}
+	if a.Name != "EDGECAST" || a.StartAutnum != 15133 {
+		t.Errorf("Autnum returned unexpected data: %+v", a)
+	}
+	// The second lookup should be served from the local cache
+	srv.Close()
+	if _, err := c.Autnum(context.Background(), 15133); err != nil {
+		t.Errorf("Autnum failed to use the local cache: %v", err)
+	}
+}
+
+func TestRangeToCIDRs(t *testing.T) {
+	tests := []struct {
+		start, end, want string
+	}{
+		{"10.0.0.0", "10.0.255.255", "10.0.0.0/16"},
+		{"192.168.1.0", "192.168.1.127", "192.168.1.0/25"},
+		{"2001:db8::", "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", "2001:db8::/32"},
+	}
+
+	for _, test := range tests {
+		if got := rangeToCIDRs(test.start, test.end); len(got) != 1 || got[0] != test.want {
+			t.Errorf("rangeToCIDRs(%s, %s) returned %v, expected %s", test.start, test.end, got, test.want)
+		}
+	}
+}
+
+func TestCacheLimit(t *testing.T) {
+	c := NewClient()
+	c.MaxEntries = 3
+
+	for i := 0; i < 5; i++ {
+		c.store(fmt.Sprintf("https://rdap.example/domain/%d.com", i), "{}")
+		time.Sleep(time.Millisecond)
+	}
+	if len(c.cache) != 3 {
+		t.Errorf("the cache holds %d responses, expected 3", len(c.cache))
+	}
+	// The responses closest to expiration are evicted first
+	for i := 0; i < 2; i++ {
+		if _, found := c.cache[fmt.Sprintf("https://rdap.example/domain/%d.com", i)]; found {
+			t.Errorf("the cache kept the oldest response %d", i)
+		}
+	}
+
+	c.cache["https://rdap.example/expired"] = &cacheEntry{expires: time.Now().Add(-time.Minute)}
+	c.MaxEntries = 4
+	c.store("https://rdap.example/domain/new.com", "{}")
+	if _, found := c.cache["https://rdap.example/expired"]; found || len(c.cache) != 4 {
+		t.Errorf("the cache kept the expired response instead of a valid one: %d entries", len(c.cache))
+	}
+}
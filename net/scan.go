@@ -0,0 +1,92 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// The TCP ports most commonly found open, starting with the nmap top 100 ports in order
+// of frequency, followed by common service and web administration ports.
+var topPorts = []int{
+	80, 23, 443, 21, 22, 25, 3389, 110, 445, 139, 143, 53, 135, 3306, 8080, 1723, 111, 995, 993, 5900,
+	1025, 587, 8888, 199, 1720, 465, 548, 113, 81, 6001, 10000, 514, 5060, 179, 1026, 2000, 8443, 8000,
+	32768, 554, 26, 1433, 49152, 2001, 515, 8008, 49154, 1027, 5666, 646, 5000, 5631, 631, 49153, 8081,
+	2049, 88, 79, 5800, 106, 2121, 1110, 49155, 6000, 513, 990, 5357, 427, 49156, 543, 544, 5101, 144,
+	7, 389, 8009, 3128, 444, 9999, 5009, 7070, 5190, 3000, 5432, 1900, 3986, 13, 1029, 9, 5051, 6646,
+	49157, 1028, 873, 1755, 2717, 4899, 9100, 119, 37,
+	// Common service and web administration ports outside of the top 100
+	6379, 9200, 27017, 11211, 5601, 9090, 9443, 2375, 2376, 6443, 10250, 5985, 5986, 8880, 4443,
+	7001, 8161, 9001, 15672, 5672, 1883, 8883, 4848, 2082, 2083, 2086, 2087, 2095, 2096, 50000,
+}
+
+// TopPorts returns the n TCP ports most commonly found open.
+func TopPorts(n int) []int {
+	if n <= 0 || n > len(topPorts) {
+		n = len(topPorts)
+	}
+	return append([]int(nil), topPorts[:n]...)
+}
+
+// ScanPorts performs a TCP connect scan of the ports on the address, making no more than
+// max connection attempts at the same time, and returns the open ports in ascending order.
+func ScanPorts(ctx context.Context, addr string, ports []int, timeout time.Duration, max int) []int {
+	if max <= 0 {
+		max = 1
+	}
+
+	d := scanDialer(timeout)
+	var open []int
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max)
+loop:
+	for _, port := range ports {
+		select {
+		case <-ctx.Done():
+			break loop
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(port int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+			if err != nil {
+				return
+			}
+			conn.Close()
+
+			lock.Lock()
+			open = append(open, port)
+			lock.Unlock()
+		}(port)
+	}
+	wg.Wait()
+
+	sort.Ints(open)
+	return open
+}
+
+// scanDialer returns a dialer bound to the LocalAddr when it has been set.
+func scanDialer(timeout time.Duration) *net.Dialer {
+	d := &net.Dialer{Timeout: timeout}
+
+	if LocalAddr != nil {
+		if ip, _, err := net.ParseCIDR(LocalAddr.String()); err == nil {
+			d.LocalAddr = &net.TCPAddr{IP: ip}
+		}
+	}
+	return d
+}
@@ -0,0 +1,52 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTopPorts(t *testing.T) {
+	if ports := TopPorts(3); len(ports) != 3 || ports[0] != 80 || ports[2] != 443 {
+		t.Errorf("TopPorts(3) returned %v", ports)
+	}
+	if ports := TopPorts(100000); len(ports) != len(topPorts) {
+		t.Errorf("TopPorts() returned %d ports, expected %d", len(ports), len(topPorts))
+	}
+}
+
+func TestScanPorts(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to open the listener: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	open := l.Addr().(*net.TCPAddr).Port
+
+	// Obtain a port that is not listening
+	c, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to open the listener: %v", err)
+	}
+	closed := c.Addr().(*net.TCPAddr).Port
+	c.Close()
+
+	ports := ScanPorts(context.Background(), "127.0.0.1", []int{closed, open}, time.Second, 2)
+	if len(ports) != 1 || ports[0] != open {
+		t.Errorf("ScanPorts() returned %v, expected [%d]", ports, open)
+	}
+}
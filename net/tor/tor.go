@@ -0,0 +1,153 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tor routes requests through a local Tor client. Each data source is given its own SOCKS
+// credentials, which Tor isolates onto separate circuits, and the control port is signaled for new
+// circuits when a service starts rate limiting the exit relay.
+package tor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSOCKSAddr is the address of the SOCKS port opened by a local Tor client.
+	DefaultSOCKSAddr = "127.0.0.1:9050"
+	// DefaultControlAddr is the address of the control port opened by a local Tor client.
+	DefaultControlAddr = "127.0.0.1:9051"
+	// NewIdentityInterval is the time Tor requires between two NEWNYM signals.
+	NewIdentityInterval = 10 * time.Second
+)
+
+// ProxyURL returns the SOCKS5 proxy URL for the Tor SOCKS address, carrying credentials derived from
+// the isolation key. Tor places the connections using distinct credentials on distinct circuits.
+func ProxyURL(socksAddr, isolation string) string {
+	u := &url.URL{
+		Scheme: "socks5",
+		Host:   socksAddr,
+		User:   url.UserPassword(strings.ToLower(isolation), "amass"),
+	}
+	return u.String()
+}
+
+// Controller signals a Tor client through its control port.
+type Controller struct {
+	sync.Mutex
+	addr     string
+	password string
+	last     time.Time
+}
+
+var (
+	controllersLock sync.Mutex
+	controllers     = make(map[string]*Controller)
+)
+
+// SharedController returns the Controller of the control port address, which is shared by the
+// callers in the process, so the NEWNYM signals of all the data sources are throttled together.
+func SharedController(addr, password string) *Controller {
+	controllersLock.Lock()
+	defer controllersLock.Unlock()
+
+	c, found := controllers[addr]
+	if !found || c.password != password {
+		c = NewController(addr, password)
+		controllers[addr] = c
+	}
+	return c
+}
+
+// NewController returns a Controller for the control port address. An empty password selects the
+// authentication without credentials.
+func NewController(addr, password string) *Controller {
+	return &Controller{
+		addr:     addr,
+		password: password,
+	}
+}
+
+// NewIdentity signals the Tor client to use clean circuits for the new connections. The signal is
+// only sent once per NewIdentityInterval, and the method returns false when the call was throttled.
+func (c *Controller) NewIdentity(ctx context.Context) (bool, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.last.IsZero() && time.Since(c.last) < NewIdentityInterval {
+		return false, nil
+	}
+	if err := c.signal(ctx, "NEWNYM"); err != nil {
+		return false, err
+	}
+
+	c.last = time.Now()
+	return true, nil
+}
+
+func (c *Controller) signal(ctx context.Context, sig string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the Tor control port: %v", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	rd := bufio.NewReader(conn)
+	auth := "AUTHENTICATE"
+	if c.password != "" {
+		auth += " " + quote(c.password)
+	}
+	for _, cmd := range []string{auth, "SIGNAL " + sig} {
+		if err := command(conn, rd, cmd); err != nil {
+			return err
+		}
+	}
+
+	_, _ = conn.Write([]byte("QUIT\r\n"))
+	return nil
+}
+
+func command(conn net.Conn, rd *bufio.Reader, cmd string) error {
+	if _, err := conn.Write([]byte(cmd + "\r\n")); err != nil {
+		return fmt.Errorf("failed to send the Tor control command: %v", err)
+	}
+
+	for {
+		line, err := rd.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read the Tor control reply: %v", err)
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) < 4 {
+			return fmt.Errorf("the Tor control reply %q is malformed", line)
+		}
+		// The final line of a reply separates the status code with a space
+		if line[3] != ' ' {
+			continue
+		}
+		if !strings.HasPrefix(line, "250") {
+			return fmt.Errorf("the Tor control port rejected the %s command: %s", strings.Fields(cmd)[0], line[4:])
+		}
+		return nil
+	}
+}
+
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,127 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package tor
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// serveControl answers the control port commands, accepting only the password provided,
+// and returns the listener along with the signals received.
+func serveControl(t *testing.T, password string) (net.Listener, func() []string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the control port clients: %v", err)
+	}
+
+	var lock sync.Mutex
+	var signals []string
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(c net.Conn) {
+				defer c.Close()
+
+				authenticated := false
+				rd := bufio.NewReader(c)
+				for {
+					line, err := rd.ReadString('\n')
+					if err != nil {
+						return
+					}
+
+					line = strings.TrimRight(line, "\r\n")
+					switch {
+					case strings.HasPrefix(line, "AUTHENTICATE"):
+						if line != "AUTHENTICATE "+quote(password) {
+							_, _ = c.Write([]byte("515 Authentication failed: Password did not match\r\n"))
+							return
+						}
+						authenticated = true
+						_, _ = c.Write([]byte("250 OK\r\n"))
+					case strings.HasPrefix(line, "SIGNAL ") && authenticated:
+						lock.Lock()
+						signals = append(signals, strings.TrimPrefix(line, "SIGNAL "))
+						lock.Unlock()
+						_, _ = c.Write([]byte("250 OK\r\n"))
+					case line == "QUIT":
+						_, _ = c.Write([]byte("250 closing connection\r\n"))
+						return
+					default:
+						_, _ = c.Write([]byte("514 Authentication required.\r\n"))
+						return
+					}
+				}
+			}(c)
+		}
+	}()
+
+	return ln, func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return append([]string(nil), signals...)
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	u, err := url.Parse(ProxyURL(DefaultSOCKSAddr, "CertSpotter"))
+	if err != nil {
+		t.Fatalf("ProxyURL returned an invalid URL: %v", err)
+	}
+	if u.Scheme != "socks5" || u.Host != DefaultSOCKSAddr || u.User.Username() != "certspotter" {
+		t.Errorf("ProxyURL returned %s", u.String())
+	}
+	if ProxyURL(DefaultSOCKSAddr, "Crtsh") == u.String() {
+		t.Error("ProxyURL returned the same credentials for two data sources")
+	}
+}
+
+func TestNewIdentity(t *testing.T) {
+	ln, signals := serveControl(t, `pass"word`)
+	defer ln.Close()
+
+	c := NewController(ln.Addr().String(), `pass"word`)
+	if sent, err := c.NewIdentity(context.Background()); err != nil || !sent {
+		t.Fatalf("NewIdentity() = %v, %v", sent, err)
+	}
+	// The second signal is throttled by the interval required by Tor
+	if sent, err := c.NewIdentity(context.Background()); err != nil || sent {
+		t.Errorf("NewIdentity() = %v, %v within the interval", sent, err)
+	}
+	if s := signals(); len(s) != 1 || s[0] != "NEWNYM" {
+		t.Errorf("the control port received the signals %v", s)
+	}
+}
+
+func TestNewIdentityBadPassword(t *testing.T) {
+	ln, signals := serveControl(t, "secret")
+	defer ln.Close()
+
+	c := NewController(ln.Addr().String(), "wrong")
+	if _, err := c.NewIdentity(context.Background()); err == nil {
+		t.Error("NewIdentity did not return an error with the wrong password")
+	}
+	if s := signals(); len(s) != 0 {
+		t.Errorf("the control port received the signals %v", s)
+	}
+}
+
+func TestSharedController(t *testing.T) {
+	a := SharedController(DefaultControlAddr, "")
+	if b := SharedController(DefaultControlAddr, ""); a != b {
+		t.Error("SharedController returned different controllers for the same control port")
+	}
+}
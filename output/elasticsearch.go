@@ -0,0 +1,180 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/http"
+)
+
+const defaultIndex = "amass"
+
+// The index template applied when the configuration does not provide one, where %s is the index name
+const defaultIndexTemplate = `{
+  "index_patterns": ["%s*"],
+  "template": {
+    "mappings": {
+      "properties": {
+        "timestamp": {"type": "date"},
+        "uuid": {"type": "keyword"},
+        "name": {"type": "keyword"},
+        "domain": {"type": "keyword"},
+        "tag": {"type": "keyword"},
+        "sources": {"type": "keyword"},
+        "addresses": {
+          "properties": {
+            "ip": {"type": "ip"},
+            "cidr": {"type": "keyword"},
+            "asn": {"type": "long"},
+            "desc": {"type": "text"}
+          }
+        }
+      }
+    }
+  }
+}`
+
+// Elasticsearch is the Sink that bulk indexes the events into Elasticsearch or OpenSearch.
+type Elasticsearch struct {
+	settings *config.OutputSink
+	url      string
+	index    string
+	batch    *batch
+}
+
+// NewElasticsearch returns the Elasticsearch sink after the index template has been installed.
+func NewElasticsearch(ctx context.Context, settings *config.OutputSink) (*Elasticsearch, error) {
+	es := &Elasticsearch{
+		settings: settings,
+		url:      strings.TrimRight(settings.URL, "/"),
+		index:    settings.Index,
+		batch:    newBatch(settings.BatchSize),
+	}
+	if es.index == "" {
+		es.index = defaultIndex
+	}
+
+	template := fmt.Sprintf(defaultIndexTemplate, es.index)
+	if settings.Template != "" {
+		b, err := os.ReadFile(settings.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the Elasticsearch index template: %v", err)
+		}
+		template = string(b)
+	}
+
+	resp, err := es.request(ctx, "/_index_template/"+es.index, "application/json", template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install the Elasticsearch index template: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to install the Elasticsearch index template: %s", resp.Status)
+	}
+	return es, nil
+}
+
+// String implements the Stringer interface.
+func (es *Elasticsearch) String() string {
+	return es.settings.Name
+}
+
+// Write implements the Sink interface.
+func (es *Elasticsearch) Write(ctx context.Context, ev *Event) error {
+	if es.batch.add(ev) {
+		return es.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (es *Elasticsearch) Flush(ctx context.Context) error {
+	events := es.batch.take()
+	if len(events) == 0 {
+		return nil
+	}
+
+	action, _ := json.Marshal(map[string]interface{}{"index": map[string]string{"_index": es.index}})
+	var body strings.Builder
+	for _, ev := range events {
+		doc, err := json.Marshal(ev)
+		if err != nil {
+			continue
+		}
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(doc)
+		body.WriteByte('\n')
+	}
+
+	resp, err := es.request(ctx, "/_bulk", "application/x-ndjson", body.String())
+	if err != nil {
+		return fmt.Errorf("failed to index %d events in Elasticsearch: %v", len(events), err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to index %d events in Elasticsearch: %s", len(events), resp.Status)
+	}
+	return bulkError(resp.Body)
+}
+
+// Close implements the Sink interface.
+func (es *Elasticsearch) Close(ctx context.Context) error {
+	return es.Flush(ctx)
+}
+
+func (es *Elasticsearch) request(ctx context.Context, path, ctype, body string) (*http.Response, error) {
+	req := &http.Request{
+		URL:    es.url + path,
+		Method: "POST",
+		Header: http.Header{"Content-Type": ctype},
+		Body:   body,
+		TLS:    es.settings.TLSConfig(),
+	}
+
+	if es.settings.Key != "" {
+		req.Header["Authorization"] = "ApiKey " + es.settings.Key
+	} else if es.settings.Username != "" {
+		req.Auth = &http.BasicAuth{
+			Username: es.settings.Username,
+			Password: es.settings.Password,
+		}
+	}
+	return http.RequestWebPage(ctx, req)
+}
+
+// bulkError returns the first error reported in the response to a bulk request.
+func bulkError(body string) error {
+	var resp struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Error *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return fmt.Errorf("failed to parse the Elasticsearch bulk response: %v", err)
+	}
+	if !resp.Errors {
+		return nil
+	}
+
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Error != nil {
+				return fmt.Errorf("elasticsearch failed to index an event: %s: %s", result.Error.Type, result.Error.Reason)
+			}
+		}
+	}
+	return errors.New("elasticsearch failed to index the events")
+}
@@ -0,0 +1,100 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func TestElasticsearch(t *testing.T) {
+	var lock sync.Mutex
+	var template string
+	var docs []map[string]interface{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "elastic" || pass != "changeme" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		defer lock.Unlock()
+		switch r.URL.Path {
+		case "/_index_template/assets":
+			template = string(body)
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		case "/_bulk":
+			lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+			for i := 1; i < len(lines); i += 2 {
+				var doc map[string]interface{}
+				if err := json.Unmarshal([]byte(lines[i]), &doc); err == nil {
+					docs = append(docs, doc)
+				}
+			}
+			_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	es, err := NewElasticsearch(ctx, &config.OutputSink{
+		System:    "elasticsearch",
+		URL:       ts.URL,
+		Username:  "elastic",
+		Password:  "changeme",
+		Index:     "assets",
+		BatchSize: 2,
+	})
+	if err != nil {
+		t.Fatalf("NewElasticsearch() error = %v", err)
+	}
+	if !strings.Contains(template, `"assets*"`) {
+		t.Errorf("NewElasticsearch() installed the index template %s", template)
+	}
+
+	for _, name := range []string{"www.owasp.org", "api.owasp.org", "dev.owasp.org"} {
+		out := &requests.Output{Name: name, Domain: "owasp.org", Tag: requests.DNS, Sources: []string{"DNS"}}
+		if err := es.Write(ctx, NewEvent("uuid", out)); err != nil {
+			t.Errorf("Elasticsearch.Write() error = %v", err)
+		}
+	}
+	lock.Lock()
+	if len(docs) != 2 {
+		t.Errorf("Elasticsearch.Write() indexed %d events before the batch was full", len(docs))
+	}
+	lock.Unlock()
+
+	if err := es.Close(ctx); err != nil {
+		t.Errorf("Elasticsearch.Close() error = %v", err)
+	}
+	if len(docs) != 3 || docs[2]["name"] != "dev.owasp.org" || docs[2]["uuid"] != "uuid" || docs[2]["timestamp"] == nil {
+		t.Errorf("Elasticsearch.Close() indexed the documents %v", docs)
+	}
+}
+
+func TestBulkError(t *testing.T) {
+	body := `{"errors":true,"items":[{"index":{"status":201}},` +
+		`{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [addresses.ip]"}}}]}`
+
+	if err := bulkError(body); err == nil || !strings.Contains(err.Error(), "mapper_parsing_exception") {
+		t.Errorf("bulkError() = %v", err)
+	}
+	if err := bulkError(`{"errors":false,"items":[]}`); err != nil {
+		t.Errorf("bulkError() = %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+const (
+	defaultTopic      = "amass"
+	kafkaBatchTimeout = 10 * time.Millisecond
+)
+
+// Kafka is the Sink that publishes the events as JSON messages to a Kafka topic.
+type Kafka struct {
+	settings *config.OutputSink
+	writer   *kafka.Writer
+	batch    *batch
+}
+
+// NewKafka returns the Kafka sink for the brokers and topic in the settings.
+func NewKafka(settings *config.OutputSink) (*Kafka, error) {
+	var brokers []string
+	for _, b := range strings.Split(settings.Brokers, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			brokers = append(brokers, b)
+		}
+	}
+	if len(brokers) == 0 {
+		return nil, errors.New("the kafka output requires the brokers setting")
+	}
+
+	mechanism, err := kafkaMechanism(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &kafka.Transport{
+		ClientID: "amass",
+		SASL:     mechanism,
+	}
+	if settings.TLS {
+		transport.TLS = settings.TLSConfig()
+	}
+
+	topic := settings.Topic
+	if topic == "" {
+		topic = defaultTopic
+	}
+
+	b := newBatch(settings.BatchSize)
+	return &Kafka{
+		settings: settings,
+		batch:    b,
+		writer: &kafka.Writer{
+			Addr: kafka.TCP(brokers...),
+			// Messages for the same name are published to the same partition
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			BatchSize:    b.size,
+			BatchTimeout: kafkaBatchTimeout,
+			RequiredAcks: kafka.RequireOne,
+			Transport:    transport,
+		},
+	}, nil
+}
+
+// kafkaMechanism returns the SASL mechanism used to authenticate with the brokers.
+func kafkaMechanism(settings *config.OutputSink) (sasl.Mechanism, error) {
+	if settings.Username == "" {
+		return nil, nil
+	}
+
+	switch m := strings.ToUpper(settings.Mechanism); m {
+	case "", "PLAIN":
+		return plain.Mechanism{Username: settings.Username, Password: settings.Password}, nil
+	case "SCRAM-SHA-256":
+		return scram.Mechanism(scram.SHA256, settings.Username, settings.Password)
+	case "SCRAM-SHA-512":
+		return scram.Mechanism(scram.SHA512, settings.Username, settings.Password)
+	default:
+		return nil, fmt.Errorf("the kafka output SASL mechanism '%s' is not supported", m)
+	}
+}
+
+// String implements the Stringer interface.
+func (k *Kafka) String() string {
+	return k.settings.Name
+}
+
+// Write implements the Sink interface.
+func (k *Kafka) Write(ctx context.Context, ev *Event) error {
+	if k.batch.add(ev) {
+		return k.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (k *Kafka) Flush(ctx context.Context) error {
+	events := k.batch.take()
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := k.writer.WriteMessages(ctx, kafkaMessages(events)...); err != nil {
+		return fmt.Errorf("failed to publish %d events to the %s topic: %v", len(events), k.writer.Topic, err)
+	}
+	return nil
+}
+
+// Close implements the Sink interface.
+func (k *Kafka) Close(ctx context.Context) error {
+	err := k.Flush(ctx)
+
+	if cerr := k.writer.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func kafkaMessages(events []*Event) []kafka.Message {
+	var msgs []kafka.Message
+
+	for _, ev := range events {
+		b, err := json.Marshal(ev)
+		if err != nil {
+			continue
+		}
+
+		msgs = append(msgs, kafka.Message{
+			Key:   []byte(ev.Name),
+			Value: b,
+			Time:  ev.Timestamp,
+		})
+	}
+	return msgs
+}
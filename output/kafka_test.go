@@ -0,0 +1,69 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func TestNewKafka(t *testing.T) {
+	k, err := NewKafka(&config.OutputSink{System: "kafka", Brokers: "broker1:9092, broker2:9092", Topic: "assets"})
+	if err != nil {
+		t.Fatalf("NewKafka() error = %v", err)
+	}
+	if addr := k.writer.Addr.String(); addr != "broker1:9092,broker2:9092" || k.writer.Topic != "assets" {
+		t.Errorf("NewKafka() returned the writer for %s and the topic %s", addr, k.writer.Topic)
+	}
+
+	if _, err := NewKafka(&config.OutputSink{System: "kafka", Brokers: " , "}); err == nil {
+		t.Errorf("NewKafka() accepted settings without brokers")
+	}
+}
+
+func TestKafkaMechanism(t *testing.T) {
+	tests := []struct {
+		mechanism string
+		want      string
+		wantErr   bool
+	}{
+		{mechanism: "", want: "PLAIN"},
+		{mechanism: "scram-sha-256", want: "SCRAM-SHA-256"},
+		{mechanism: "SCRAM-SHA-512", want: "SCRAM-SHA-512"},
+		{mechanism: "GSSAPI", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		m, err := kafkaMechanism(&config.OutputSink{Username: "amass", Password: "secret", Mechanism: tt.mechanism})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("kafkaMechanism(%q) error = %v, wantErr %v", tt.mechanism, err, tt.wantErr)
+			continue
+		}
+		if err == nil && m.Name() != tt.want {
+			t.Errorf("kafkaMechanism(%q) = %s, want %s", tt.mechanism, m.Name(), tt.want)
+		}
+	}
+
+	if m, err := kafkaMechanism(&config.OutputSink{}); m != nil || err != nil {
+		t.Errorf("kafkaMechanism() returned a mechanism without credentials")
+	}
+}
+
+func TestKafkaMessages(t *testing.T) {
+	ev := NewEvent("uuid", &requests.Output{Name: "www.owasp.org", Domain: "owasp.org"})
+
+	msgs := kafkaMessages([]*Event{ev})
+	if len(msgs) != 1 || string(msgs[0].Key) != "www.owasp.org" || !msgs[0].Time.Equal(ev.Timestamp) {
+		t.Fatalf("kafkaMessages() returned %v", msgs)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(msgs[0].Value, &doc); err != nil || doc["name"] != "www.owasp.org" || doc["uuid"] != "uuid" {
+		t.Errorf("kafkaMessages() returned the value %s", msgs[0].Value)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/http"
+)
+
+const (
+	mispEventPath     = "/events/add"
+	mispAttributePath = "/attributes/add/"
+	// The attributes inherit the distribution level of the event
+	mispInheritDistribution int = 5
+	// The threat level and analysis of the events created by the sink are undefined and initial
+	mispThreatLevel int = 4
+	mispAnalysis    int = 0
+)
+
+// MISP is the Sink that adds the discovered hostnames, addresses and URLs as attributes of a MISP event.
+type MISP struct {
+	sync.Mutex
+	settings *config.OutputSink
+	url      string
+	tags     []mispTag
+	batch    *batch
+	events   map[string]string
+	seen     map[string]struct{}
+}
+
+type mispTag struct {
+	Name string `json:"name"`
+}
+
+type mispEvent struct {
+	Info          string    `json:"info"`
+	Date          string    `json:"date"`
+	Distribution  int       `json:"distribution"`
+	ThreatLevelID int       `json:"threat_level_id"`
+	Analysis      int       `json:"analysis"`
+	Tag           []mispTag `json:"Tag,omitempty"`
+}
+
+type mispAttribute struct {
+	Type         string    `json:"type"`
+	Category     string    `json:"category"`
+	Value        string    `json:"value"`
+	ToIDS        bool      `json:"to_ids"`
+	Distribution int       `json:"distribution"`
+	Comment      string    `json:"comment,omitempty"`
+	Tag          []mispTag `json:"Tag,omitempty"`
+}
+
+// NewMISP returns the MISP sink for the instance in the settings.
+func NewMISP(settings *config.OutputSink) (*MISP, error) {
+	if settings.Key == "" {
+		return nil, errors.New("the misp output requires the apikey setting")
+	}
+	if settings.Distribution < 0 || settings.Distribution > 3 {
+		return nil, errors.New("the misp output distribution setting must be between 0 and 3")
+	}
+
+	var tags []mispTag
+	for _, t := range strings.Split(settings.Tags, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, mispTag{Name: t})
+		}
+	}
+
+	return &MISP{
+		settings: settings,
+		url:      strings.TrimRight(settings.URL, "/"),
+		tags:     tags,
+		batch:    newBatch(settings.BatchSize),
+		events:   make(map[string]string),
+		seen:     make(map[string]struct{}),
+	}, nil
+}
+
+// String implements the Stringer interface.
+func (m *MISP) String() string {
+	return m.settings.Name
+}
+
+// Write implements the Sink interface.
+func (m *MISP) Write(ctx context.Context, ev *Event) error {
+	if m.batch.add(ev) {
+		return m.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (m *MISP) Flush(ctx context.Context) error {
+	events := m.batch.take()
+	if len(events) == 0 {
+		return nil
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	// Each enumeration has its own MISP event, unless the attributes are added to an existing event
+	var uuids []string
+	attrs := make(map[string][]*mispAttribute)
+	for _, ev := range events {
+		if _, found := attrs[ev.UUID]; !found {
+			uuids = append(uuids, ev.UUID)
+		}
+		attrs[ev.UUID] = append(attrs[ev.UUID], m.attributes(ev)...)
+	}
+
+	for _, uuid := range uuids {
+		id, err := m.eventID(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		var list []*mispAttribute
+		// MISP rejects the attributes already present in the event
+		for _, a := range attrs[uuid] {
+			key := id + "|" + a.Type + "|" + a.Value
+			if _, found := m.seen[key]; !found {
+				m.seen[key] = struct{}{}
+				list = append(list, a)
+			}
+		}
+		if len(list) == 0 {
+			continue
+		}
+
+		body, err := json.Marshal(list)
+		if err != nil {
+			return fmt.Errorf("failed to encode %d MISP attributes: %v", len(list), err)
+		}
+		if _, err := m.post(ctx, mispAttributePath+id, string(body)); err != nil {
+			return fmt.Errorf("failed to add %d attributes to the MISP event %s: %v", len(list), id, err)
+		}
+	}
+	return nil
+}
+
+// Close implements the Sink interface.
+func (m *MISP) Close(ctx context.Context) error {
+	return m.Flush(ctx)
+}
+
+// attributes returns the MISP attributes for the hostname, its root domain, addresses and URLs.
+func (m *MISP) attributes(ev *Event) []*mispAttribute {
+	var tags []mispTag
+	// The tags are applied to the attributes when they are added to an existing event
+	if m.settings.Event != "" {
+		tags = m.tags
+	}
+
+	newAttr := func(typ, value, comment string) *mispAttribute {
+		return &mispAttribute{
+			Type:         typ,
+			Category:     "Network activity",
+			Value:        value,
+			Distribution: mispInheritDistribution,
+			Comment:      comment,
+			Tag:          tags,
+		}
+	}
+
+	var results []*mispAttribute
+	if ev.Domain != "" {
+		results = append(results, newAttr("domain", ev.Domain, "Root domain name in scope of the enumeration"))
+	}
+	if ev.Name != "" && ev.Name != ev.Domain {
+		comment := "Discovered by Amass"
+		if len(ev.Sources) > 0 {
+			comment += " using " + strings.Join(ev.Sources, ", ")
+		}
+		results = append(results, newAttr("hostname", ev.Name, comment))
+	}
+	for _, addr := range ev.Addresses {
+		if addr.Address == nil {
+			continue
+		}
+
+		comment := "Resolved from " + ev.Name
+		if addr.ASN != 0 {
+			comment += fmt.Sprintf(" in AS%d %s", addr.ASN, addr.Description)
+		}
+		results = append(results, newAttr("ip-dst", addr.Address.String(), strings.TrimSpace(comment)))
+	}
+	for _, h := range ev.HTTP {
+		if h.URL == "" {
+			continue
+		}
+
+		comment := fmt.Sprintf("HTTP status %d", h.StatusCode)
+		if h.Title != "" {
+			comment += ": " + h.Title
+		}
+		results = append(results, newAttr("url", h.URL, comment))
+	}
+	return results
+}
+
+// eventID returns the identifier of the MISP event receiving the attributes for the enumeration.
+func (m *MISP) eventID(ctx context.Context, uuid string) (string, error) {
+	if m.settings.Event != "" {
+		return m.settings.Event, nil
+	}
+	if id, found := m.events[uuid]; found {
+		return id, nil
+	}
+
+	body, err := json.Marshal(map[string]*mispEvent{
+		"Event": {
+			Info:          "Amass enumeration " + uuid,
+			Date:          time.Now().UTC().Format("2006-01-02"),
+			Distribution:  m.settings.Distribution,
+			ThreatLevelID: mispThreatLevel,
+			Analysis:      mispAnalysis,
+			Tag:           m.tags,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the MISP event: %v", err)
+	}
+
+	resp, err := m.post(ctx, mispEventPath, string(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create the MISP event for enumeration %s: %v", uuid, err)
+	}
+
+	var created struct {
+		Event struct {
+			ID interface{} `json:"id"`
+		} `json:"Event"`
+	}
+	if err := json.Unmarshal([]byte(resp), &created); err != nil || created.Event.ID == nil {
+		return "", fmt.Errorf("failed to obtain the MISP event created for enumeration %s", uuid)
+	}
+
+	id := fmt.Sprint(created.Event.ID)
+	m.events[uuid] = id
+	return id, nil
+}
+
+// post sends the body to the MISP API endpoint and returns the response body.
+func (m *MISP) post(ctx context.Context, path, body string) (string, error) {
+	resp, err := http.RequestWebPage(ctx, &http.Request{
+		URL:    m.url + path,
+		Method: "POST",
+		Header: http.Header{
+			"Authorization": m.settings.Key,
+			"Accept":        "application/json",
+			"Content-Type":  "application/json",
+		},
+		Body: body,
+		TLS:  m.settings.TLSConfig(),
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("%s: %s", resp.Status, mispError(resp.Body))
+	}
+	return resp.Body, nil
+}
+
+// mispError returns the reason provided by MISP for rejecting the request.
+func mispError(body string) string {
+	var resp struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal([]byte(body), &resp); err != nil || (resp.Message == "" && resp.Name == "") {
+		return strings.TrimSpace(body)
+	}
+	if resp.Message == "" {
+		return resp.Name
+	}
+	return resp.Message
+}
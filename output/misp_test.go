@@ -0,0 +1,135 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func TestMISP(t *testing.T) {
+	var created []map[string]interface{}
+	attrs := make(map[string][]map[string]interface{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "0123456789abcdef" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"name":"Authentication failed.","message":"Authentication failed."}`))
+			return
+		}
+
+		switch {
+		case r.URL.Path == mispEventPath:
+			var req map[string]map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			created = append(created, req["Event"])
+			_, _ = w.Write([]byte(`{"Event":{"id":"42"}}`))
+		case strings.HasPrefix(r.URL.Path, mispAttributePath):
+			var req []map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			id := strings.TrimPrefix(r.URL.Path, mispAttributePath)
+			attrs[id] = append(attrs[id], req...)
+			_, _ = w.Write([]byte(`{"saved":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	m, err := NewMISP(&config.OutputSink{
+		Name:         "misp",
+		System:       "misp",
+		URL:          ts.URL + "/",
+		Key:          "0123456789abcdef",
+		Tags:         "tlp:amber, amass",
+		Distribution: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewMISP() error = %v", err)
+	}
+
+	out := &requests.Output{
+		Name:      "www.owasp.org",
+		Domain:    "owasp.org",
+		Sources:   []string{"DNS"},
+		Addresses: []requests.AddressInfo{{Address: net.ParseIP("192.168.1.1"), ASN: 64496, Description: "EXAMPLE"}},
+		HTTP:      []*requests.HTTPInfo{{URL: "https://www.owasp.org/", StatusCode: 200, Title: "OWASP"}},
+	}
+	if err := m.Write(ctx, NewEvent("uuid", out)); err != nil {
+		t.Errorf("MISP.Write() error = %v", err)
+	}
+	if err := m.Write(ctx, NewEvent("uuid", &requests.Output{Name: "api.owasp.org", Domain: "owasp.org"})); err != nil {
+		t.Errorf("MISP.Write() error = %v", err)
+	}
+	if err := m.Flush(ctx); err != nil {
+		t.Fatalf("MISP.Flush() error = %v", err)
+	}
+
+	if len(created) != 1 {
+		t.Fatalf("MISP.Flush() created %d events, expected 1", len(created))
+	}
+	if created[0]["distribution"] != float64(1) || !strings.Contains(created[0]["info"].(string), "uuid") {
+		t.Errorf("MISP.Flush() created the event %v", created[0])
+	}
+	if tags, ok := created[0]["Tag"].([]interface{}); !ok || len(tags) != 2 {
+		t.Errorf("MISP.Flush() created the event with the tags %v", created[0]["Tag"])
+	}
+
+	expected := map[string]string{
+		"domain":   "owasp.org",
+		"hostname": "www.owasp.org",
+		"ip-dst":   "192.168.1.1",
+		"url":      "https://www.owasp.org/",
+	}
+	// The root domain is only added once
+	if len(attrs["42"]) != 5 {
+		t.Fatalf("MISP.Flush() added %d attributes, expected 5", len(attrs["42"]))
+	}
+	for _, a := range attrs["42"][:4] {
+		if v := expected[a["type"].(string)]; v != a["value"] {
+			t.Errorf("MISP.Flush() added the %s attribute %v", a["type"], a["value"])
+		}
+	}
+
+	// The attributes added to an existing event carry the tags
+	m.settings.Event = "7"
+	_ = m.Write(ctx, NewEvent("uuid2", &requests.Output{Name: "dev.owasp.org", Domain: "owasp.org"}))
+	if err := m.Flush(ctx); err != nil {
+		t.Fatalf("MISP.Flush() error = %v", err)
+	}
+	if len(created) != 1 || len(attrs["7"]) != 2 {
+		t.Fatalf("MISP.Flush() created %d events and added %d attributes to the existing event", len(created), len(attrs["7"]))
+	}
+	if tags, ok := attrs["7"][1]["Tag"].([]interface{}); !ok || len(tags) != 2 {
+		t.Errorf("MISP.Flush() added the attribute with the tags %v", attrs["7"][1]["Tag"])
+	}
+
+	m.settings.Key = "invalid"
+	_ = m.Write(ctx, NewEvent("uuid2", &requests.Output{Name: "test.owasp.org"}))
+	if err := m.Close(ctx); err == nil || !strings.Contains(err.Error(), "Authentication failed") {
+		t.Errorf("MISP.Close() error = %v", err)
+	}
+}
+
+func TestNewMISPMissingKey(t *testing.T) {
+	if _, err := NewMISP(&config.OutputSink{System: "misp", URL: "https://misp.example.com"}); err == nil {
+		t.Errorf("NewMISP() accepted settings without an API key")
+	}
+}
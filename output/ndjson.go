@@ -0,0 +1,88 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// NDJSON is the Sink that appends each event to a file as a line of JSON. The events are
+// written and synced as they arrive, so consumers following the file see them immediately.
+type NDJSON struct {
+	sync.Mutex
+	path string
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewNDJSON returns the NDJSON sink appending to the file at path, or writing to STDOUT when the path is "-".
+func NewNDJSON(path string) (*NDJSON, error) {
+	f := os.Stdout
+	if path != "-" {
+		var err error
+
+		f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the NDJSON output file: %v", err)
+		}
+	}
+
+	return &NDJSON{
+		path: path,
+		file: f,
+		enc:  json.NewEncoder(f),
+	}, nil
+}
+
+// String implements the Stringer interface.
+func (n *NDJSON) String() string {
+	return "ndjson"
+}
+
+// Write implements the Sink interface.
+func (n *NDJSON) Write(ctx context.Context, ev *Event) error {
+	n.Lock()
+	defer n.Unlock()
+
+	if err := n.enc.Encode(ev); err != nil {
+		return fmt.Errorf("failed to write the event for %s: %v", ev.Name, err)
+	}
+	return n.sync()
+}
+
+// Flush implements the Sink interface.
+func (n *NDJSON) Flush(ctx context.Context) error {
+	n.Lock()
+	defer n.Unlock()
+
+	return n.sync()
+}
+
+// Close implements the Sink interface.
+func (n *NDJSON) Close(ctx context.Context) error {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.path == "-" {
+		return nil
+	}
+	if err := n.sync(); err != nil {
+		_ = n.file.Close()
+		return err
+	}
+	return n.file.Close()
+}
+
+// Pipes and terminals cannot be synced, and their writes are already visible to the reader
+func (n *NDJSON) sync() error {
+	if n.path == "-" {
+		return nil
+	}
+	return n.file.Sync()
+}
@@ -0,0 +1,71 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func readNDJSON(t *testing.T, path string) []*Event {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open the NDJSON file: %v", err)
+	}
+	defer f.Close()
+
+	var events []*Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("the line %q is not a JSON document: %v", scanner.Text(), err)
+		}
+		events = append(events, &ev)
+	}
+	return events
+}
+
+func TestNDJSON(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "amass.ndjson")
+
+	n, err := NewNDJSON(path)
+	if err != nil {
+		t.Fatalf("NewNDJSON() error = %v", err)
+	}
+	if err := n.Write(ctx, NewEvent("uuid", &requests.Output{Name: "www.owasp.org", Domain: "owasp.org"})); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	// The event must be available while the sink remains open
+	if events := readNDJSON(t, path); len(events) != 1 || events[0].Name != "www.owasp.org" || events[0].UUID != "uuid" {
+		t.Errorf("the file did not contain the event after the write: %v", events)
+	}
+	if err := n.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Another run appends to the events already in the file
+	n, err = NewNDJSON(path)
+	if err != nil {
+		t.Fatalf("NewNDJSON() error = %v", err)
+	}
+	_ = n.Write(ctx, NewEvent("uuid2", &requests.Output{Name: "api.owasp.org", Domain: "owasp.org"}))
+	_ = n.Close(ctx)
+
+	if events := readNDJSON(t, path); len(events) != 2 || events[1].Name != "api.owasp.org" {
+		t.Errorf("the file did not contain the events of both runs: %v", events)
+	}
+
+	if _, err := NewNDJSON(filepath.Join(path, "missing", "amass.ndjson")); err == nil {
+		t.Errorf("NewNDJSON() accepted a path that cannot be created")
+	}
+}
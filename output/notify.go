@@ -0,0 +1,264 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/http"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+// The maximum number of changes listed in a notification
+const maxNotifyLines int = 50
+
+// The maximum length of a Discord message
+const maxDiscordLen int = 2000
+
+// Severity ranks the changes reported by the notifiers.
+type Severity int
+
+// The severities assigned to the changes, from the least to the most severe.
+const (
+	SeverityInfo Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"info", "low", "medium", "high", "critical"}
+
+// String implements the Stringer interface.
+func (s Severity) String() string {
+	if s < SeverityInfo || s > SeverityCritical {
+		return "unknown"
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity returns the Severity with the name provided.
+func ParseSeverity(name string) (Severity, error) {
+	for i, n := range severityNames {
+		if strings.EqualFold(strings.TrimSpace(name), n) {
+			return Severity(i), nil
+		}
+	}
+	return SeverityInfo, fmt.Errorf("the severity '%s' is not valid", name)
+}
+
+// The severities of the findings reported by the enumeration
+var findingSeverities = map[string]Severity{
+	requests.FindingTakeover: SeverityHigh,
+	requests.FindingBucket:   SeverityHigh,
+	requests.FindingDangling: SeverityMedium,
+}
+
+// Change is an item of a Summary, described in a human-readable form.
+type Change struct {
+	Severity    Severity
+	Description string
+}
+
+// Summary describes the changes found between enumerations of the domains.
+type Summary struct {
+	Domains []string
+	Changes []*Change
+}
+
+// NewSummary returns the Summary of the new subdomains, findings and netblocks in the
+// newer output that are not present in the older output, ordered by decreasing severity.
+func NewSummary(domains []string, older, newer []*requests.Output) *Summary {
+	oldNames := make(map[string]*requests.Output)
+	oldFindings := make(map[string]struct{})
+	oldNetblocks := make(map[string]struct{})
+	for _, o := range older {
+		oldNames[o.Name] = o
+		for _, f := range o.Findings {
+			oldFindings[o.Name+f.String()] = struct{}{}
+		}
+		for _, a := range o.Addresses {
+			oldNetblocks[a.CIDRStr] = struct{}{}
+		}
+	}
+
+	s := &Summary{Domains: domains}
+	netblocks := make(map[string]struct{})
+	for _, o := range newer {
+		if _, found := oldNames[o.Name]; !found {
+			s.Changes = append(s.Changes, &Change{
+				Severity:    SeverityInfo,
+				Description: strings.TrimSpace("New subdomain: " + o.Name + " " + addressList(o.Addresses)),
+			})
+		}
+
+		for _, f := range o.Findings {
+			if _, found := oldFindings[o.Name+f.String()]; found {
+				continue
+			}
+
+			sev, found := findingSeverities[f.Type]
+			if !found {
+				sev = SeverityMedium
+			}
+			desc := fmt.Sprintf("%s: %s", o.Name, f.Description)
+			if f.Evidence != "" {
+				desc += " (" + f.Evidence + ")"
+			}
+			s.Changes = append(s.Changes, &Change{Severity: sev, Description: desc})
+		}
+
+		for _, a := range o.Addresses {
+			cidr := a.CIDRStr
+			if cidr == "" {
+				continue
+			}
+			if _, found := oldNetblocks[cidr]; found {
+				continue
+			}
+			if _, found := netblocks[cidr]; found {
+				continue
+			}
+			netblocks[cidr] = struct{}{}
+
+			desc := "New netblock: " + cidr
+			if a.ASN != 0 {
+				desc += fmt.Sprintf(" (AS%d %s)", a.ASN, a.Description)
+			}
+			s.Changes = append(s.Changes, &Change{Severity: SeverityLow, Description: strings.TrimSpace(desc)})
+		}
+	}
+
+	sort.SliceStable(s.Changes, func(i, j int) bool {
+		return s.Changes[i].Severity > s.Changes[j].Severity
+	})
+	return s
+}
+
+func addressList(addrs []requests.AddressInfo) string {
+	var list []string
+
+	for _, a := range addrs {
+		list = append(list, a.Address.String())
+	}
+	return strings.Join(list, ",")
+}
+
+// Notifier posts human-readable summaries to Slack, Discord or Microsoft Teams channels.
+type Notifier struct {
+	settings *config.OutputSink
+	min      Severity
+}
+
+// IsNotifier returns true when the output system is a chat service supported by the notifiers.
+func IsNotifier(system string) bool {
+	switch strings.ToLower(system) {
+	case "slack", "discord", "teams":
+		return true
+	}
+	return false
+}
+
+// NewNotifiers returns the notifiers for the chat service outputs in the configuration.
+func NewNotifiers(cfg *config.Config) ([]*Notifier, error) {
+	var notifiers []*Notifier
+
+	for _, o := range cfg.Outputs {
+		if !IsNotifier(o.System) {
+			continue
+		}
+
+		n, err := NewNotifier(o)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, n)
+	}
+	return notifiers, nil
+}
+
+// NewNotifier returns the Notifier for the chat service webhook in the settings.
+func NewNotifier(settings *config.OutputSink) (*Notifier, error) {
+	if settings.URL == "" {
+		return nil, fmt.Errorf("the %s output requires the url setting", settings.Name)
+	}
+
+	n := &Notifier{settings: settings}
+	if settings.Severity != "" {
+		sev, err := ParseSeverity(settings.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("the %s output: %v", settings.Name, err)
+		}
+		n.min = sev
+	}
+	return n, nil
+}
+
+// String implements the Stringer interface.
+func (n *Notifier) String() string {
+	return n.settings.Name
+}
+
+// Notify posts the changes in the summary meeting the minimum severity of the notifier.
+// Nothing is posted when none of the changes are severe enough.
+func (n *Notifier) Notify(ctx context.Context, s *Summary) error {
+	var lines []string
+	for _, c := range s.Changes {
+		if c.Severity >= n.min {
+			lines = append(lines, fmt.Sprintf("[%s] %s", strings.ToUpper(c.Severity.String()), c.Description))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	total := len(lines)
+	if total > maxNotifyLines {
+		lines = append(lines[:maxNotifyLines], fmt.Sprintf("... and %d more changes", total-maxNotifyLines))
+	}
+	title := fmt.Sprintf("Amass found %d changes for %s", total, strings.Join(s.Domains, ", "))
+
+	body, err := json.Marshal(n.payload(title, lines))
+	if err != nil {
+		return fmt.Errorf("failed to encode the %s notification: %v", n, err)
+	}
+
+	retries := n.settings.Retries
+	if retries == 0 {
+		retries = defaultRetries
+	}
+	header := http.Header{"Content-Type": "application/json"}
+	if err := postWithRetry(ctx, n.settings.URL, header, string(body), n.settings.TLSConfig(), retries); err != nil {
+		return fmt.Errorf("failed to post the %s notification: %v", n, err)
+	}
+	return nil
+}
+
+// payload returns the message in the format expected by the chat service webhook.
+func (n *Notifier) payload(title string, lines []string) interface{} {
+	switch strings.ToLower(n.settings.System) {
+	case "discord":
+		msg := "**" + title + "**\n" + strings.Join(lines, "\n")
+		if len(msg) > maxDiscordLen {
+			msg = msg[:maxDiscordLen-3] + "..."
+		}
+		return map[string]string{"content": msg}
+	case "teams":
+		return map[string]string{
+			"@type":    "MessageCard",
+			"@context": "http://schema.org/extensions",
+			"summary":  title,
+			"title":    title,
+			// Teams requires blank lines between the paragraphs of the card text
+			"text": strings.Join(lines, "\n\n"),
+		}
+	}
+	return map[string]string{"text": "*" + title + "*\n" + strings.Join(lines, "\n")}
+}
@@ -0,0 +1,119 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func testSummary() *Summary {
+	older := []*requests.Output{
+		{
+			Name:      "www.owasp.org",
+			Addresses: []requests.AddressInfo{{Address: net.ParseIP("192.0.2.1"), CIDRStr: "192.0.2.0/24", ASN: 64500}},
+		},
+	}
+	newer := []*requests.Output{
+		older[0],
+		{
+			Name:      "shop.owasp.org",
+			Addresses: []requests.AddressInfo{{Address: net.ParseIP("198.51.100.7"), CIDRStr: "198.51.100.0/24", ASN: 64501, Description: "EXAMPLE"}},
+			Findings: []*requests.Finding{{
+				Type:        requests.FindingTakeover,
+				Description: "Possible GitHub Pages subdomain takeover",
+				Evidence:    "CNAME owasp.github.io",
+			}},
+		},
+	}
+	return NewSummary([]string{"owasp.org"}, older, newer)
+}
+
+func TestNewSummary(t *testing.T) {
+	s := testSummary()
+
+	want := []struct {
+		sev  Severity
+		desc string
+	}{
+		{SeverityHigh, "shop.owasp.org: Possible GitHub Pages subdomain takeover (CNAME owasp.github.io)"},
+		{SeverityLow, "New netblock: 198.51.100.0/24 (AS64501 EXAMPLE)"},
+		{SeverityInfo, "New subdomain: shop.owasp.org 198.51.100.7"},
+	}
+	if len(s.Changes) != len(want) {
+		t.Fatalf("NewSummary() returned %d changes, expected %d", len(s.Changes), len(want))
+	}
+	for i, w := range want {
+		if c := s.Changes[i]; c.Severity != w.sev || c.Description != w.desc {
+			t.Errorf("NewSummary() returned the change [%s] %s, expected [%s] %s", c.Severity, c.Description, w.sev, w.desc)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	if sev, err := ParseSeverity(" High "); err != nil || sev != SeverityHigh {
+		t.Errorf("ParseSeverity() = %s, %v", sev, err)
+	}
+	if _, err := ParseSeverity("urgent"); err == nil {
+		t.Errorf("ParseSeverity() accepted an invalid severity")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var body map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		system   string
+		severity string
+		field    string
+		want     []string
+		exclude  string
+	}{
+		{system: "slack", field: "text", want: []string{"[HIGH] shop.owasp.org", "[INFO] New subdomain"}},
+		{system: "discord", severity: "low", field: "content", want: []string{"**Amass found 2 changes", "[LOW] New netblock"}, exclude: "[INFO]"},
+		{system: "teams", severity: "high", field: "text", want: []string{"[HIGH] shop.owasp.org"}, exclude: "[LOW]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.system, func(t *testing.T) {
+			n, err := NewNotifier(&config.OutputSink{Name: tt.system, System: tt.system, URL: ts.URL, Severity: tt.severity})
+			if err != nil {
+				t.Fatalf("NewNotifier() error = %v", err)
+			}
+			if err := n.Notify(context.Background(), testSummary()); err != nil {
+				t.Fatalf("Notifier.Notify() error = %v", err)
+			}
+
+			msg := body[tt.field]
+			for _, w := range tt.want {
+				if !strings.Contains(msg, w) {
+					t.Errorf("Notifier.Notify() posted %q without %q", msg, w)
+				}
+			}
+			if tt.exclude != "" && strings.Contains(msg, tt.exclude) {
+				t.Errorf("Notifier.Notify() posted %q with changes below the severity", msg)
+			}
+		})
+	}
+
+	body = nil
+	n, _ := NewNotifier(&config.OutputSink{Name: "slack", System: "slack", URL: ts.URL, Severity: "critical"})
+	if err := n.Notify(context.Background(), testSummary()); err != nil || body != nil {
+		t.Errorf("Notifier.Notify() posted a notification without changes meeting the severity")
+	}
+}
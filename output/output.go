@@ -0,0 +1,126 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+const defaultBatchSize int = 500
+
+// Event is the document delivered to the sinks for each name discovered during an enumeration.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	UUID      string    `json:"uuid"`
+	*requests.Output
+}
+
+// NewEvent returns the Event for the output discovered during the enumeration identified by the UUID.
+func NewEvent(uuid string, out *requests.Output) *Event {
+	return &Event{
+		Timestamp: time.Now().UTC(),
+		UUID:      uuid,
+		Output:    out,
+	}
+}
+
+// Sink delivers the enumeration findings to an external system.
+type Sink interface {
+	fmt.Stringer
+
+	// Write delivers the event, which can be buffered until the sink is flushed
+	Write(ctx context.Context, ev *Event) error
+
+	// Flush delivers the events buffered by the sink
+	Flush(ctx context.Context) error
+
+	// Close flushes the sink and releases the resources it acquired
+	Close(ctx context.Context) error
+}
+
+// batch buffers the events written to a sink until the batch is full or the sink is flushed.
+type batch struct {
+	sync.Mutex
+	size   int
+	events []*Event
+}
+
+func newBatch(size int) *batch {
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+	return &batch{size: size}
+}
+
+// add buffers the event and returns true when the batch is full.
+func (b *batch) add(ev *Event) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	b.events = append(b.events, ev)
+	return len(b.events) >= b.size
+}
+
+// take returns the buffered events and empties the batch.
+func (b *batch) take() []*Event {
+	b.Lock()
+	defer b.Unlock()
+
+	events := b.events
+	b.events = nil
+	return events
+}
+
+// NewSinks returns the sinks for the outputs in the configuration, excluding the notifiers, and
+// the sinks provided by the plugins.
+func NewSinks(ctx context.Context, cfg *config.Config) ([]Sink, error) {
+	var sinks []Sink
+
+	for _, o := range cfg.Outputs {
+		if IsNotifier(o.System) {
+			continue
+		}
+
+		var err error
+		var sink Sink
+
+		switch strings.ToLower(o.System) {
+		case "elasticsearch", "opensearch":
+			sink, err = NewElasticsearch(ctx, o)
+		case "splunk":
+			sink, err = NewSplunk(o)
+		case "kafka":
+			sink, err = NewKafka(o)
+		case "webhook":
+			sink, err = NewWebhook(o)
+		case "misp":
+			sink, err = NewMISP(o)
+		case "syslog":
+			// The syslog outputs only receive the log messages unless asked for the discoveries
+			if !o.Discoveries {
+				continue
+			}
+			sink, err = NewSyslog(o)
+		default:
+			err = fmt.Errorf("the %s output is not supported", o.System)
+		}
+
+		if err != nil {
+			for _, s := range sinks {
+				_ = s.Close(ctx)
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return append(sinks, pluginSinks(ctx, cfg)...), nil
+}
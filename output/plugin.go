@@ -0,0 +1,105 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/plugins"
+)
+
+// Plugin is the Sink that delivers the events to an output sink provided by an external plugin.
+type Plugin struct {
+	name   string
+	client *plugins.Client
+	sink   plugins.Sink
+}
+
+// NewPlugin starts the plugin executable at path and configures its output sink with the
+// settings of the plugins section in the configuration.
+func NewPlugin(ctx context.Context, cfg *config.Config, path string) (*Plugin, error) {
+	client, err := plugins.NewClient(path, cfg.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	sink, err := client.Sink()
+	if err != nil {
+		client.Kill()
+		return nil, err
+	}
+
+	name, err := sink.Name(ctx)
+	if err != nil {
+		client.Kill()
+		return nil, err
+	}
+	if name == "" {
+		client.Kill()
+		return nil, fmt.Errorf("the %s plugin did not provide the name of its output", path)
+	}
+
+	if err := sink.Configure(ctx, cfg.GetPluginSettings(name)); err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("failed to configure the %s output: %v", name, err)
+	}
+
+	return &Plugin{
+		name:   name,
+		client: client,
+		sink:   sink,
+	}, nil
+}
+
+// String implements the Stringer interface.
+func (p *Plugin) String() string {
+	return p.name
+}
+
+// Write implements the Sink interface.
+func (p *Plugin) Write(ctx context.Context, ev *Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to encode the event for %s: %v", ev.Name, err)
+	}
+	return p.sink.Write(ctx, data)
+}
+
+// Flush implements the Sink interface.
+func (p *Plugin) Flush(ctx context.Context) error {
+	return p.sink.Flush(ctx)
+}
+
+// Close implements the Sink interface.
+func (p *Plugin) Close(ctx context.Context) error {
+	defer p.client.Kill()
+
+	return p.sink.Close(ctx)
+}
+
+// pluginSinks returns the output sinks provided by the plugins in the plugins directories. The
+// plugins that fail to start are logged and left out, as are those providing only data sources.
+func pluginSinks(ctx context.Context, cfg *config.Config) []Sink {
+	paths, err := cfg.AcquirePlugins()
+	if err != nil {
+		return nil
+	}
+
+	var sinks []Sink
+	for _, path := range paths {
+		p, err := NewPlugin(ctx, cfg, path)
+		if err != nil {
+			if !plugins.Unimplemented(err) {
+				cfg.Log.Printf("Plugin: %v", err)
+			}
+			continue
+		}
+		sinks = append(sinks, p)
+	}
+	return sinks
+}
@@ -0,0 +1,133 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/http"
+)
+
+const (
+	hecEventPath      = "/services/collector/event"
+	defaultSourceType = "amass:asset"
+	splunkSource      = "amass"
+)
+
+// Splunk is the Sink that sends the events to a Splunk HTTP Event Collector.
+type Splunk struct {
+	settings *config.OutputSink
+	url      string
+	batch    *batch
+}
+
+type hecEvent struct {
+	Time       float64 `json:"time"`
+	Host       string  `json:"host,omitempty"`
+	Source     string  `json:"source"`
+	SourceType string  `json:"sourcetype"`
+	Index      string  `json:"index,omitempty"`
+	Event      *Event  `json:"event"`
+}
+
+// NewSplunk returns the Splunk sink for the HTTP Event Collector in the settings.
+func NewSplunk(settings *config.OutputSink) (*Splunk, error) {
+	if settings.Token == "" {
+		return nil, errors.New("the splunk output requires the token setting")
+	}
+
+	s := &Splunk{
+		settings: settings,
+		url:      strings.TrimRight(settings.URL, "/"),
+		batch:    newBatch(settings.BatchSize),
+	}
+	// The URL can be the address of the collector or the full path of the event endpoint
+	if !strings.Contains(s.url, "/services/collector") {
+		s.url += hecEventPath
+	}
+	return s, nil
+}
+
+// String implements the Stringer interface.
+func (s *Splunk) String() string {
+	return s.settings.Name
+}
+
+// Write implements the Sink interface.
+func (s *Splunk) Write(ctx context.Context, ev *Event) error {
+	if s.batch.add(ev) {
+		return s.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (s *Splunk) Flush(ctx context.Context) error {
+	events := s.batch.take()
+	if len(events) == 0 {
+		return nil
+	}
+
+	sourcetype := s.settings.SourceType
+	if sourcetype == "" {
+		sourcetype = defaultSourceType
+	}
+	// The collector accepts a batch of events as concatenated JSON objects
+	var body strings.Builder
+	for _, ev := range events {
+		b, err := json.Marshal(&hecEvent{
+			Time:       float64(ev.Timestamp.UnixMilli()) / 1000,
+			Host:       ev.Name,
+			Source:     splunkSource,
+			SourceType: sourcetype,
+			Index:      s.settings.Index,
+			Event:      ev,
+		})
+		if err == nil {
+			body.Write(b)
+		}
+	}
+
+	resp, err := http.RequestWebPage(ctx, &http.Request{
+		URL:    s.url,
+		Method: "POST",
+		Header: http.Header{
+			"Authorization": "Splunk " + s.settings.Token,
+			"Content-Type":  "application/json",
+		},
+		Body: body.String(),
+		TLS:  s.settings.TLSConfig(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send %d events to Splunk: %v", len(events), err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send %d events to Splunk: %s: %s", len(events), resp.Status, hecError(resp.Body))
+	}
+	return nil
+}
+
+// Close implements the Sink interface.
+func (s *Splunk) Close(ctx context.Context) error {
+	return s.Flush(ctx)
+}
+
+// hecError returns the reason provided by the collector for rejecting the events.
+func hecError(body string) string {
+	var resp struct {
+		Text string `json:"text"`
+		Code int    `json:"code"`
+	}
+
+	if err := json.Unmarshal([]byte(body), &resp); err != nil || resp.Text == "" {
+		return strings.TrimSpace(body)
+	}
+	return fmt.Sprintf("%s (code %d)", resp.Text, resp.Code)
+}
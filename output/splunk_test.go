@@ -0,0 +1,89 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func TestSplunk(t *testing.T) {
+	var events []map[string]interface{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != hecEventPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Splunk 12345678-abcd" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"text":"Invalid token","code":4}`))
+			return
+		}
+
+		dec := json.NewDecoder(r.Body)
+		for dec.More() {
+			var ev map[string]interface{}
+			if err := dec.Decode(&ev); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			events = append(events, ev)
+		}
+		_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	s, err := NewSplunk(&config.OutputSink{
+		System:     "splunk",
+		URL:        ts.URL,
+		Token:      "12345678-abcd",
+		Index:      "attack_surface",
+		SourceType: "amass",
+	})
+	if err != nil {
+		t.Fatalf("NewSplunk() error = %v", err)
+	}
+
+	for _, name := range []string{"www.owasp.org", "api.owasp.org"} {
+		out := &requests.Output{Name: name, Domain: "owasp.org", Tag: requests.DNS, Sources: []string{"DNS"}}
+		if err := s.Write(ctx, NewEvent("uuid", out)); err != nil {
+			t.Errorf("Splunk.Write() error = %v", err)
+		}
+	}
+	if err := s.Flush(ctx); err != nil {
+		t.Errorf("Splunk.Flush() error = %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Splunk.Flush() sent %d events, expected 2", len(events))
+	}
+	if events[0]["index"] != "attack_surface" || events[0]["sourcetype"] != "amass" || events[0]["host"] != "www.owasp.org" {
+		t.Errorf("Splunk.Flush() sent the event metadata %v", events[0])
+	}
+	if ev, ok := events[1]["event"].(map[string]interface{}); !ok || ev["name"] != "api.owasp.org" {
+		t.Errorf("Splunk.Flush() sent the event %v", events[1]["event"])
+	}
+
+	s.settings.Token = "invalid"
+	_ = s.Write(ctx, NewEvent("uuid", &requests.Output{Name: "dev.owasp.org"}))
+	if err := s.Close(ctx); err == nil || !strings.Contains(err.Error(), "Invalid token") {
+		t.Errorf("Splunk.Close() error = %v", err)
+	}
+}
+
+func TestNewSplunkMissingToken(t *testing.T) {
+	if _, err := NewSplunk(&config.OutputSink{System: "splunk", URL: "https://localhost:8088"}); err == nil {
+		t.Errorf("NewSplunk() accepted settings without a token")
+	}
+}
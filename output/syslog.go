@@ -0,0 +1,83 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/logging"
+)
+
+// Syslog is the Sink that sends each event as an RFC 5424 message to a syslog endpoint.
+type Syslog struct {
+	settings *config.OutputSink
+	client   *logging.Syslog
+}
+
+// NewSyslog returns the Syslog sink for the endpoint in the settings.
+func NewSyslog(settings *config.OutputSink) (*Syslog, error) {
+	client, err := logging.DialSyslog(settings.URL, settings.Facility)
+	if err != nil {
+		return nil, fmt.Errorf("the %s output: %v", settings.Name, err)
+	}
+	return &Syslog{settings: settings, client: client}, nil
+}
+
+// String implements the Stringer interface.
+func (s *Syslog) String() string {
+	return s.settings.Name
+}
+
+// Write implements the Sink interface. The events are sent as they are discovered.
+func (s *Syslog) Write(ctx context.Context, ev *Event) error {
+	msg, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to encode the event for %s: %v", ev.Name, err)
+	}
+
+	if err := s.client.Send(ev.Timestamp, logging.SeverityNotice, "discovery", map[string]string{
+		"uuid":   ev.UUID,
+		"name":   ev.Name,
+		"domain": ev.Domain,
+	}, string(msg)); err != nil {
+		return fmt.Errorf("failed to send the event for %s to syslog: %v", ev.Name, err)
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (s *Syslog) Flush(ctx context.Context) error {
+	return nil
+}
+
+// Close implements the Sink interface.
+func (s *Syslog) Close(ctx context.Context) error {
+	return s.client.Close()
+}
+
+// NewSyslogLoggers returns the syslog clients receiving the log messages for the syslog outputs in the configuration.
+func NewSyslogLoggers(cfg *config.Config) ([]*logging.Syslog, error) {
+	var clients []*logging.Syslog
+
+	for _, o := range cfg.Outputs {
+		if strings.ToLower(o.System) != "syslog" {
+			continue
+		}
+
+		client, err := logging.DialSyslog(o.URL, o.Facility)
+		if err != nil {
+			for _, c := range clients {
+				_ = c.Close()
+			}
+			return nil, fmt.Errorf("the %s output: %v", o.Name, err)
+		}
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
@@ -0,0 +1,71 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func TestSyslog(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the messages: %v", err)
+	}
+	defer pc.Close()
+
+	url := "udp://" + pc.LocalAddr().String()
+	cfg := config.NewConfig()
+	cfg.Outputs = []*config.OutputSink{
+		{Name: "syslog", System: "syslog", URL: url},
+		{Name: "syslog.siem", System: "syslog", URL: url, Discoveries: true},
+	}
+
+	ctx := context.Background()
+	sinks, err := NewSinks(ctx, cfg)
+	if err != nil || len(sinks) != 1 || sinks[0].String() != "syslog.siem" {
+		t.Fatalf("NewSinks() = %v, %v", sinks, err)
+	}
+	defer func() { _ = sinks[0].Close(ctx) }()
+	// The log messages are sent to all the syslog outputs
+	loggers, err := NewSyslogLoggers(cfg)
+	if err != nil || len(loggers) != 2 {
+		t.Fatalf("NewSyslogLoggers() = %v, %v", loggers, err)
+	}
+	for _, l := range loggers {
+		_ = l.Close()
+	}
+
+	out := &requests.Output{Name: "www.owasp.org", Domain: "owasp.org", Sources: []string{"Crtsh"}}
+	if err := sinks[0].Write(ctx, NewEvent("uuid", out)); err != nil {
+		t.Fatalf("Syslog.Write() error = %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("failed to read the message: %v", err)
+	}
+
+	msg := string(buf[:n])
+	sd := `discovery [amass@32473 domain="owasp.org" name="www.owasp.org" uuid="uuid"] `
+	i := strings.Index(msg, sd)
+	if !strings.HasPrefix(msg, "<13>1 ") || i < 0 {
+		t.Fatalf("the syslog message was %q", msg)
+	}
+
+	var ev Event
+	if err := json.Unmarshal([]byte(msg[i+len(sd):]), &ev); err != nil || ev.Name != "www.owasp.org" || ev.UUID != "uuid" {
+		t.Errorf("the syslog message provided the event %q", msg[i+len(sd):])
+	}
+}
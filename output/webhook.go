@@ -0,0 +1,135 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/net/http"
+)
+
+const (
+	defaultRetries  int = 3
+	signatureHeader     = "X-Amass-Signature"
+)
+
+// The delay before the first retry, which doubles after each failed attempt
+var retryBackoff = time.Second
+
+// Webhook is the Sink that POSTs batches of events as JSON to a URL.
+type Webhook struct {
+	settings *config.OutputSink
+	retries  int
+	batch    *batch
+}
+
+type webhookPayload struct {
+	Events []*Event `json:"events"`
+}
+
+// NewWebhook returns the Webhook sink for the URL in the settings.
+func NewWebhook(settings *config.OutputSink) (*Webhook, error) {
+	if settings.URL == "" {
+		return nil, fmt.Errorf("the %s output requires the url setting", settings.Name)
+	}
+
+	retries := settings.Retries
+	if retries == 0 {
+		retries = defaultRetries
+	}
+	return &Webhook{
+		settings: settings,
+		retries:  retries,
+		batch:    newBatch(settings.BatchSize),
+	}, nil
+}
+
+// String implements the Stringer interface.
+func (w *Webhook) String() string {
+	return w.settings.Name
+}
+
+// Write implements the Sink interface.
+func (w *Webhook) Write(ctx context.Context, ev *Event) error {
+	if w.batch.add(ev) {
+		return w.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (w *Webhook) Flush(ctx context.Context) error {
+	events := w.batch.take()
+	if len(events) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(&webhookPayload{Events: events})
+	if err != nil {
+		return fmt.Errorf("failed to encode %d events: %v", len(events), err)
+	}
+
+	header := http.Header{"Content-Type": "application/json"}
+	// The signature allows the receiver to verify the events were sent by this enumeration
+	if w.settings.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.settings.Secret))
+		mac.Write(body)
+		header[signatureHeader] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	if err := postWithRetry(ctx, w.settings.URL, header, string(body), w.settings.TLSConfig(), w.retries); err != nil {
+		return fmt.Errorf("failed to deliver %d events: %v", len(events), err)
+	}
+	return nil
+}
+
+// Close implements the Sink interface.
+func (w *Webhook) Close(ctx context.Context) error {
+	return w.Flush(ctx)
+}
+
+// postWithRetry sends the body to the URL, retrying with an increasing delay when
+// the request fails, is rate limited or results in a server error.
+func postWithRetry(ctx context.Context, u string, header http.Header, body string, tlsc *tls.Config, retries int) error {
+	var err error
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryBackoff << (attempt - 1)):
+			}
+		}
+
+		resp, rerr := http.RequestWebPage(ctx, &http.Request{
+			URL:    u,
+			Method: "POST",
+			Header: header,
+			Body:   body,
+			TLS:    tlsc,
+		})
+		switch {
+		case rerr != nil:
+			err = rerr
+		case resp.StatusCode == 429 || resp.StatusCode >= 500:
+			err = errors.New(resp.Status)
+		case resp.StatusCode >= 300:
+			return fmt.Errorf("the request was rejected: %s", resp.Status)
+		default:
+			return nil
+		}
+	}
+	return fmt.Errorf("the request failed after %d attempts: %v", retries+1, err)
+}
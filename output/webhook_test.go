@@ -0,0 +1,133 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package output
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/config"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func TestWebhook(t *testing.T) {
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = time.Second }()
+
+	var attempts int
+	var payload webhookPayload
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first attempt fails to exercise the retries
+		if attempts++; attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		if r.Header.Get(signatureHeader) != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.Unmarshal(body, &payload)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	w, err := NewWebhook(&config.OutputSink{Name: "webhook.chatops", System: "webhook", URL: ts.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+
+	for _, name := range []string{"www.owasp.org", "api.owasp.org"} {
+		out := &requests.Output{Name: name, Domain: "owasp.org", Tag: requests.CERT, Sources: []string{"Active Cert"}}
+		if err := w.Write(ctx, NewEvent("uuid", out)); err != nil {
+			t.Errorf("Webhook.Write() error = %v", err)
+		}
+	}
+	if err := w.Close(ctx); err != nil {
+		t.Errorf("Webhook.Close() error = %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Webhook.Close() made %d attempts, expected 2", attempts)
+	}
+	if len(payload.Events) != 2 || payload.Events[1].Name != "api.owasp.org" ||
+		payload.Events[1].Tag != requests.CERT || payload.Events[1].Timestamp.IsZero() {
+		t.Errorf("Webhook.Close() delivered the events %v", payload.Events)
+	}
+}
+
+func TestPostWithRetry(t *testing.T) {
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = time.Second }()
+
+	var attempts int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	if err := postWithRetry(context.Background(), ts.URL, nil, "{}", nil, 2); err == nil || attempts != 3 {
+		t.Errorf("postWithRetry() error = %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	if err := postWithRetry(context.Background(), ts.URL+"/missing", nil, "{}", nil, 2); err == nil || attempts != 1 {
+		t.Errorf("postWithRetry() retried a rejected request %d times: %v", attempts, err)
+	}
+}
+
+func TestWebhookVerifiesTLS(t *testing.T) {
+	retryBackoff = time.Millisecond
+	defer func() { retryBackoff = time.Second }()
+
+	var delivered int
+	// The certificate of the test server is not signed by a trusted CA
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered++
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		insecure bool
+		expected int
+	}{
+		{name: "default", insecure: false, expected: 0},
+		{name: "insecure_skip_verify", insecure: true, expected: 1},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		delivered = 0
+		w, err := NewWebhook(&config.OutputSink{System: "webhook", URL: ts.URL, Insecure: tt.insecure})
+		if err != nil {
+			t.Fatalf("NewWebhook() error = %v", err)
+		}
+
+		out := &requests.Output{Name: "www.owasp.org", Domain: "owasp.org", Sources: []string{"DNS"}}
+		if err := w.Write(ctx, NewEvent("uuid", out)); err != nil {
+			t.Errorf("Webhook.Write() error = %v", err)
+		}
+		if err := w.Close(ctx); (err != nil) != (tt.expected == 0) || delivered != tt.expected {
+			t.Errorf("Webhook.Close() with the %s settings made %d deliveries, expected %d: %v", tt.name, delivered, tt.expected, err)
+		}
+	}
+}
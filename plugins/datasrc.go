@@ -0,0 +1,250 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/owasp-amass/amass/v3/config"
+	pb "github.com/owasp-amass/amass/v3/plugins/proto"
+	"google.golang.org/grpc"
+)
+
+// SourceInfo identifies the data source provided by a plugin and the requests it handles.
+type SourceInfo struct {
+	Name string
+	// The category of the data source, such as api, cert or scrape
+	Type       string
+	Vertical   bool
+	Horizontal bool
+	Address    bool
+}
+
+// SourceConfig is provided to the data source when the enumeration starts.
+type SourceConfig struct {
+	// The API credentials of the data_sources section in the configuration
+	Credentials []*config.Credentials
+	// The settings of the plugins section in the configuration
+	Settings map[string]string
+	// The root domain names in scope of the enumeration
+	Domains []string
+	// The number of minutes the responses of the data source can be cached
+	TTL int
+}
+
+// DataSource is implemented by the plugins providing names to the enumerations. The callbacks
+// send the findings to Amass as they are discovered.
+type DataSource interface {
+	// Info returns the name of the data source and the requests it handles
+	Info(ctx context.Context) (*SourceInfo, error)
+
+	// Start prepares the data source for the enumeration
+	Start(ctx context.Context, cfg *SourceConfig) error
+
+	// Stop releases the resources acquired by the data source
+	Stop(ctx context.Context) error
+
+	// Vertical finds the subdomain names of the root domain name
+	Vertical(ctx context.Context, domain string, name func(name string)) error
+
+	// Horizontal finds the domain names associated with the root domain name
+	Horizontal(ctx context.Context, domain string, assoc func(domain string)) error
+
+	// Address finds the names related to the IP address
+	Address(ctx context.Context, addr string, name func(name string)) error
+}
+
+// DataSourcePlugin is the go-plugin implementation serving and consuming a DataSource over gRPC.
+type DataSourcePlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl DataSource
+}
+
+// GRPCServer implements the GRPCPlugin interface.
+func (p *DataSourcePlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	// The service is left unregistered when the plugin does not provide the data source
+	if p.Impl != nil {
+		pb.RegisterDataSourceServer(s, &dataSourceServer{impl: p.Impl})
+	}
+	return nil
+}
+
+// GRPCClient implements the GRPCPlugin interface.
+func (p *DataSourcePlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &dataSourceClient{client: pb.NewDataSourceClient(c)}, nil
+}
+
+// dataSourceClient is the DataSource used by Amass to reach the plugin process.
+type dataSourceClient struct {
+	client pb.DataSourceClient
+}
+
+func (c *dataSourceClient) Info(ctx context.Context) (*SourceInfo, error) {
+	resp, err := c.client.Info(ctx, &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SourceInfo{
+		Name:       resp.GetName(),
+		Type:       resp.GetType(),
+		Vertical:   resp.GetVertical(),
+		Horizontal: resp.GetHorizontal(),
+		Address:    resp.GetAddress(),
+	}, nil
+}
+
+func (c *dataSourceClient) Start(ctx context.Context, cfg *SourceConfig) error {
+	req := &pb.StartRequest{
+		Settings: cfg.Settings,
+		Domains:  cfg.Domains,
+		Ttl:      int32(cfg.TTL),
+	}
+
+	for _, cr := range cfg.Credentials {
+		req.Credentials = append(req.Credentials, &pb.Credentials{
+			Name:     cr.Name,
+			Username: cr.Username,
+			Password: cr.Password,
+			Key:      cr.Key,
+			Secret:   cr.Secret,
+		})
+	}
+
+	_, err := c.client.Start(ctx, req)
+	return err
+}
+
+func (c *dataSourceClient) Stop(ctx context.Context) error {
+	_, err := c.client.Stop(ctx, &pb.Empty{})
+	return err
+}
+
+func (c *dataSourceClient) Vertical(ctx context.Context, domain string, name func(name string)) error {
+	stream, err := c.client.Vertical(ctx, &pb.DomainRequest{Domain: domain})
+	if err != nil {
+		return err
+	}
+
+	return receive(func() error {
+		n, err := stream.Recv()
+		if err == nil {
+			name(n.GetName())
+		}
+		return err
+	})
+}
+
+func (c *dataSourceClient) Horizontal(ctx context.Context, domain string, assoc func(domain string)) error {
+	stream, err := c.client.Horizontal(ctx, &pb.DomainRequest{Domain: domain})
+	if err != nil {
+		return err
+	}
+
+	return receive(func() error {
+		a, err := stream.Recv()
+		if err == nil {
+			assoc(a.GetAssociated())
+		}
+		return err
+	})
+}
+
+func (c *dataSourceClient) Address(ctx context.Context, addr string, name func(name string)) error {
+	stream, err := c.client.Address(ctx, &pb.AddressRequest{Address: addr})
+	if err != nil {
+		return err
+	}
+
+	return receive(func() error {
+		n, err := stream.Recv()
+		if err == nil {
+			name(n.GetName())
+		}
+		return err
+	})
+}
+
+// receive calls the function for each message of the stream until the plugin closes the stream.
+func receive(recv func() error) error {
+	for {
+		if err := recv(); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// dataSourceServer serves the DataSource implementation of the plugin to Amass.
+type dataSourceServer struct {
+	pb.UnimplementedDataSourceServer
+	impl DataSource
+}
+
+func (s *dataSourceServer) Info(ctx context.Context, _ *pb.Empty) (*pb.SourceInfo, error) {
+	info, err := s.impl.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SourceInfo{
+		Name:       info.Name,
+		Type:       info.Type,
+		Vertical:   info.Vertical,
+		Horizontal: info.Horizontal,
+		Address:    info.Address,
+	}, nil
+}
+
+func (s *dataSourceServer) Start(ctx context.Context, req *pb.StartRequest) (*pb.Empty, error) {
+	cfg := &SourceConfig{
+		Settings: req.GetSettings(),
+		Domains:  req.GetDomains(),
+		TTL:      int(req.GetTtl()),
+	}
+
+	for _, cr := range req.GetCredentials() {
+		cfg.Credentials = append(cfg.Credentials, &config.Credentials{
+			Name:     cr.GetName(),
+			Username: cr.GetUsername(),
+			Password: cr.GetPassword(),
+			Key:      cr.GetKey(),
+			Secret:   cr.GetSecret(),
+		})
+	}
+
+	return &pb.Empty{}, s.impl.Start(ctx, cfg)
+}
+
+func (s *dataSourceServer) Stop(ctx context.Context, _ *pb.Empty) (*pb.Empty, error) {
+	return &pb.Empty{}, s.impl.Stop(ctx)
+}
+
+func (s *dataSourceServer) Vertical(req *pb.DomainRequest, stream pb.DataSource_VerticalServer) error {
+	return s.impl.Vertical(stream.Context(), req.GetDomain(), func(name string) {
+		_ = stream.Send(&pb.Name{Name: name})
+	})
+}
+
+func (s *dataSourceServer) Horizontal(req *pb.DomainRequest, stream pb.DataSource_HorizontalServer) error {
+	domain := req.GetDomain()
+
+	return s.impl.Horizontal(stream.Context(), domain, func(assoc string) {
+		_ = stream.Send(&pb.Association{
+			Domain:     domain,
+			Associated: assoc,
+		})
+	})
+}
+
+func (s *dataSourceServer) Address(req *pb.AddressRequest, stream pb.DataSource_AddressServer) error {
+	return s.impl.Address(stream.Context(), req.GetAddress(), func(name string) {
+		_ = stream.Send(&pb.Name{Name: name})
+	})
+}
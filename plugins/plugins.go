@@ -0,0 +1,141 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package plugins allows third parties to provide data sources and output sinks as executables
+// built outside of the Amass repository. The plugins are discovered in the plugins directory and
+// communicate with Amass over gRPC.
+package plugins
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/plugin.proto
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The names of the plugin types served by the plugin executables
+const (
+	DataSourceType = "datasrc"
+	SinkType       = "sink"
+)
+
+// Handshake is shared by Amass and the plugins, so the executables that are not Amass plugins,
+// or that were built for an incompatible protocol version, are refused.
+var Handshake = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "AMASS_PLUGIN",
+	MagicCookieValue: "d7c8b0d3a3e74a8fb3b5a6c9a4c2f3e1",
+}
+
+// ServeOpts are the implementations served by a plugin. Either can be left nil.
+type ServeOpts struct {
+	DataSource DataSource
+	Sink       Sink
+}
+
+// Serve is called from the main function of the plugin executable, and serves the data source
+// and output sink implementations to Amass until the plugin process is stopped.
+func Serve(opts *ServeOpts) {
+	set := plugin.PluginSet{}
+
+	if opts.DataSource != nil {
+		set[DataSourceType] = &DataSourcePlugin{Impl: opts.DataSource}
+	}
+	if opts.Sink != nil {
+		set[SinkType] = &SinkPlugin{Impl: opts.Sink}
+	}
+
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         set,
+		GRPCServer:      plugin.DefaultGRPCServer,
+	})
+}
+
+// Client is the connection to a plugin process started by Amass.
+type Client struct {
+	path   string
+	client *plugin.Client
+}
+
+// NewClient starts the plugin executable at the path provided. The messages logged by the plugin are
+// written to the logger, or discarded when the logger is nil.
+func NewClient(path string, logger *log.Logger) (*Client, error) {
+	l := hclog.New(&hclog.LoggerOptions{
+		Output: io.Discard,
+		Level:  hclog.Off,
+	})
+	if logger != nil {
+		l = hclog.FromStandardLogger(logger, &hclog.LoggerOptions{Name: path})
+	}
+
+	c := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		Plugins: plugin.PluginSet{
+			DataSourceType: &DataSourcePlugin{},
+			SinkType:       &SinkPlugin{},
+		},
+		Cmd:              exec.Command(path),
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		Logger:           l,
+	})
+
+	if _, err := c.Client(); err != nil {
+		c.Kill()
+		return nil, fmt.Errorf("failed to start the %s plugin: %v", path, err)
+	}
+	return &Client{
+		path:   path,
+		client: c,
+	}, nil
+}
+
+// DataSource returns the data source served by the plugin.
+func (c *Client) DataSource() (DataSource, error) {
+	raw, err := c.dispense(DataSourceType)
+	if err != nil {
+		return nil, err
+	}
+	return raw.(DataSource), nil
+}
+
+// Sink returns the output sink served by the plugin.
+func (c *Client) Sink() (Sink, error) {
+	raw, err := c.dispense(SinkType)
+	if err != nil {
+		return nil, err
+	}
+	return raw.(Sink), nil
+}
+
+func (c *Client) dispense(name string) (interface{}, error) {
+	rpc, err := c.client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the %s plugin: %v", c.path, err)
+	}
+
+	raw, err := rpc.Dispense(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain the %s of the %s plugin: %v", name, c.path, err)
+	}
+	return raw, nil
+}
+
+// Kill stops the plugin process.
+func (c *Client) Kill() {
+	c.client.Kill()
+}
+
+// Unimplemented returns true when the error was caused by a plugin not serving the requested type,
+// such as a plugin providing only an output sink being asked for the data source information.
+func Unimplemented(err error) bool {
+	return status.Code(err) == codes.Unimplemented
+}
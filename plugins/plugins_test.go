@@ -0,0 +1,207 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/owasp-amass/amass/v3/config"
+)
+
+type testSource struct {
+	cfg *SourceConfig
+}
+
+func (s *testSource) Info(ctx context.Context) (*SourceInfo, error) {
+	return &SourceInfo{
+		Name:       "Testing",
+		Type:       "api",
+		Vertical:   true,
+		Horizontal: true,
+	}, nil
+}
+
+func (s *testSource) Start(ctx context.Context, cfg *SourceConfig) error {
+	s.cfg = cfg
+	return nil
+}
+
+func (s *testSource) Stop(ctx context.Context) error {
+	return nil
+}
+
+func (s *testSource) Vertical(ctx context.Context, domain string, name func(name string)) error {
+	for _, label := range []string{"www", "api", "mail"} {
+		name(label + "." + domain)
+	}
+	return nil
+}
+
+func (s *testSource) Horizontal(ctx context.Context, domain string, assoc func(domain string)) error {
+	assoc("owasp.com")
+	return nil
+}
+
+func (s *testSource) Address(ctx context.Context, addr string, name func(name string)) error {
+	return nil
+}
+
+type testSink struct {
+	settings map[string]string
+	events   [][]byte
+	flushed  int
+}
+
+func (s *testSink) Name(ctx context.Context) (string, error) {
+	return "testing", nil
+}
+
+func (s *testSink) Configure(ctx context.Context, settings map[string]string) error {
+	s.settings = settings
+	return nil
+}
+
+func (s *testSink) Write(ctx context.Context, event []byte) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *testSink) Flush(ctx context.Context) error {
+	s.flushed++
+	return nil
+}
+
+func (s *testSink) Close(ctx context.Context) error {
+	return s.Flush(ctx)
+}
+
+func TestDataSourcePlugin(t *testing.T) {
+	impl := &testSource{}
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		DataSourceType: &DataSourcePlugin{Impl: impl},
+	})
+	defer client.Close()
+	defer server.Stop()
+
+	raw, err := client.Dispense(DataSourceType)
+	if err != nil {
+		t.Fatalf("Failed to dispense the data source: %v", err)
+	}
+	src := raw.(DataSource)
+	ctx := context.Background()
+
+	info, err := src.Info(ctx)
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if info.Name != "Testing" || info.Type != "api" || !info.Vertical || !info.Horizontal || info.Address {
+		t.Errorf("Info() = %+v", info)
+	}
+
+	cfg := &SourceConfig{
+		Credentials: []*config.Credentials{{Name: "account", Key: "secretkey"}},
+		Settings:    map[string]string{"endpoint": "https://api.example.com"},
+		Domains:     []string{"owasp.org"},
+		TTL:         1440,
+	}
+	if err := src.Start(ctx, cfg); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if !reflect.DeepEqual(impl.cfg, cfg) {
+		t.Errorf("Start() provided %+v, want %+v", impl.cfg, cfg)
+	}
+
+	var names []string
+	if err := src.Vertical(ctx, "owasp.org", func(name string) {
+		names = append(names, name)
+	}); err != nil {
+		t.Fatalf("Vertical() error = %v", err)
+	}
+	if want := []string{"www.owasp.org", "api.owasp.org", "mail.owasp.org"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Vertical() provided %v, want %v", names, want)
+	}
+
+	var assocs []string
+	if err := src.Horizontal(ctx, "owasp.org", func(domain string) {
+		assocs = append(assocs, domain)
+	}); err != nil {
+		t.Fatalf("Horizontal() error = %v", err)
+	}
+	if want := []string{"owasp.com"}; !reflect.DeepEqual(assocs, want) {
+		t.Errorf("Horizontal() provided %v, want %v", assocs, want)
+	}
+
+	if err := src.Stop(ctx); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestSinkPlugin(t *testing.T) {
+	impl := &testSink{}
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		SinkType: &SinkPlugin{Impl: impl},
+	})
+	defer client.Close()
+	defer server.Stop()
+
+	raw, err := client.Dispense(SinkType)
+	if err != nil {
+		t.Fatalf("Failed to dispense the sink: %v", err)
+	}
+	sink := raw.(Sink)
+	ctx := context.Background()
+
+	if name, err := sink.Name(ctx); err != nil || name != "testing" {
+		t.Errorf("Name() = %s, %v", name, err)
+	}
+
+	settings := map[string]string{"url": "https://siem.example.com"}
+	if err := sink.Configure(ctx, settings); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if !reflect.DeepEqual(impl.settings, settings) {
+		t.Errorf("Configure() provided %v, want %v", impl.settings, settings)
+	}
+
+	event := []byte(`{"name":"www.owasp.org","domain":"owasp.org"}`)
+	if err := sink.Write(ctx, event); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if len(impl.events) != 1 || string(impl.events[0]) != string(event) {
+		t.Errorf("Write() delivered %q, want %q", impl.events, event)
+	}
+
+	if err := sink.Flush(ctx); err != nil {
+		t.Errorf("Flush() error = %v", err)
+	}
+	if err := sink.Close(ctx); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if impl.flushed != 2 {
+		t.Errorf("The sink was flushed %d times, want 2", impl.flushed)
+	}
+}
+
+func TestUnimplemented(t *testing.T) {
+	// The plugin only serves an output sink
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		SinkType:       &SinkPlugin{Impl: &testSink{}},
+		DataSourceType: &DataSourcePlugin{},
+	})
+	defer client.Close()
+	defer server.Stop()
+
+	raw, err := client.Dispense(DataSourceType)
+	if err != nil {
+		t.Fatalf("Failed to dispense the data source: %v", err)
+	}
+
+	if _, err := raw.(DataSource).Info(context.Background()); !Unimplemented(err) {
+		t.Errorf("Info() error = %v, want the unimplemented status", err)
+	}
+}
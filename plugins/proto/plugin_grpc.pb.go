@@ -0,0 +1,624 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.22.3
+// source: proto/plugin.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DataSource_Info_FullMethodName       = "/amass.plugins.DataSource/Info"
+	DataSource_Start_FullMethodName      = "/amass.plugins.DataSource/Start"
+	DataSource_Stop_FullMethodName       = "/amass.plugins.DataSource/Stop"
+	DataSource_Vertical_FullMethodName   = "/amass.plugins.DataSource/Vertical"
+	DataSource_Horizontal_FullMethodName = "/amass.plugins.DataSource/Horizontal"
+	DataSource_Address_FullMethodName    = "/amass.plugins.DataSource/Address"
+)
+
+// DataSourceClient is the client API for DataSource service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DataSourceClient interface {
+	Info(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SourceInfo, error)
+	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*Empty, error)
+	Stop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	// Vertical streams the subdomain names found for the root domain name
+	Vertical(ctx context.Context, in *DomainRequest, opts ...grpc.CallOption) (DataSource_VerticalClient, error)
+	// Horizontal streams the domain names associated with the root domain name
+	Horizontal(ctx context.Context, in *DomainRequest, opts ...grpc.CallOption) (DataSource_HorizontalClient, error)
+	// Address streams the names found for the IP address
+	Address(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (DataSource_AddressClient, error)
+}
+
+type dataSourceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDataSourceClient(cc grpc.ClientConnInterface) DataSourceClient {
+	return &dataSourceClient{cc}
+}
+
+func (c *dataSourceClient) Info(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SourceInfo, error) {
+	out := new(SourceInfo)
+	err := c.cc.Invoke(ctx, DataSource_Info_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataSourceClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, DataSource_Start_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataSourceClient) Stop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, DataSource_Stop_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataSourceClient) Vertical(ctx context.Context, in *DomainRequest, opts ...grpc.CallOption) (DataSource_VerticalClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataSource_ServiceDesc.Streams[0], DataSource_Vertical_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataSourceVerticalClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DataSource_VerticalClient interface {
+	Recv() (*Name, error)
+	grpc.ClientStream
+}
+
+type dataSourceVerticalClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataSourceVerticalClient) Recv() (*Name, error) {
+	m := new(Name)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dataSourceClient) Horizontal(ctx context.Context, in *DomainRequest, opts ...grpc.CallOption) (DataSource_HorizontalClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataSource_ServiceDesc.Streams[1], DataSource_Horizontal_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataSourceHorizontalClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DataSource_HorizontalClient interface {
+	Recv() (*Association, error)
+	grpc.ClientStream
+}
+
+type dataSourceHorizontalClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataSourceHorizontalClient) Recv() (*Association, error) {
+	m := new(Association)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dataSourceClient) Address(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (DataSource_AddressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataSource_ServiceDesc.Streams[2], DataSource_Address_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataSourceAddressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DataSource_AddressClient interface {
+	Recv() (*Name, error)
+	grpc.ClientStream
+}
+
+type dataSourceAddressClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataSourceAddressClient) Recv() (*Name, error) {
+	m := new(Name)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DataSourceServer is the server API for DataSource service.
+// All implementations must embed UnimplementedDataSourceServer
+// for forward compatibility
+type DataSourceServer interface {
+	Info(context.Context, *Empty) (*SourceInfo, error)
+	Start(context.Context, *StartRequest) (*Empty, error)
+	Stop(context.Context, *Empty) (*Empty, error)
+	// Vertical streams the subdomain names found for the root domain name
+	Vertical(*DomainRequest, DataSource_VerticalServer) error
+	// Horizontal streams the domain names associated with the root domain name
+	Horizontal(*DomainRequest, DataSource_HorizontalServer) error
+	// Address streams the names found for the IP address
+	Address(*AddressRequest, DataSource_AddressServer) error
+	mustEmbedUnimplementedDataSourceServer()
+}
+
+// UnimplementedDataSourceServer must be embedded to have forward compatible implementations.
+type UnimplementedDataSourceServer struct {
+}
+
+func (UnimplementedDataSourceServer) Info(context.Context, *Empty) (*SourceInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Info not implemented")
+}
+func (UnimplementedDataSourceServer) Start(context.Context, *StartRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Start not implemented")
+}
+func (UnimplementedDataSourceServer) Stop(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+func (UnimplementedDataSourceServer) Vertical(*DomainRequest, DataSource_VerticalServer) error {
+	return status.Errorf(codes.Unimplemented, "method Vertical not implemented")
+}
+func (UnimplementedDataSourceServer) Horizontal(*DomainRequest, DataSource_HorizontalServer) error {
+	return status.Errorf(codes.Unimplemented, "method Horizontal not implemented")
+}
+func (UnimplementedDataSourceServer) Address(*AddressRequest, DataSource_AddressServer) error {
+	return status.Errorf(codes.Unimplemented, "method Address not implemented")
+}
+func (UnimplementedDataSourceServer) mustEmbedUnimplementedDataSourceServer() {}
+
+// UnsafeDataSourceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DataSourceServer will
+// result in compilation errors.
+type UnsafeDataSourceServer interface {
+	mustEmbedUnimplementedDataSourceServer()
+}
+
+func RegisterDataSourceServer(s grpc.ServiceRegistrar, srv DataSourceServer) {
+	s.RegisterService(&DataSource_ServiceDesc, srv)
+}
+
+func _DataSource_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataSourceServer).Info(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataSource_Info_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataSourceServer).Info(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataSource_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataSourceServer).Start(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataSource_Start_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataSourceServer).Start(ctx, req.(*StartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataSource_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataSourceServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataSource_Stop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataSourceServer).Stop(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataSource_Vertical_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DomainRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataSourceServer).Vertical(m, &dataSourceVerticalServer{stream})
+}
+
+type DataSource_VerticalServer interface {
+	Send(*Name) error
+	grpc.ServerStream
+}
+
+type dataSourceVerticalServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataSourceVerticalServer) Send(m *Name) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DataSource_Horizontal_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DomainRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataSourceServer).Horizontal(m, &dataSourceHorizontalServer{stream})
+}
+
+type DataSource_HorizontalServer interface {
+	Send(*Association) error
+	grpc.ServerStream
+}
+
+type dataSourceHorizontalServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataSourceHorizontalServer) Send(m *Association) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DataSource_Address_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AddressRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataSourceServer).Address(m, &dataSourceAddressServer{stream})
+}
+
+type DataSource_AddressServer interface {
+	Send(*Name) error
+	grpc.ServerStream
+}
+
+type dataSourceAddressServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataSourceAddressServer) Send(m *Name) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// DataSource_ServiceDesc is the grpc.ServiceDesc for DataSource service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DataSource_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "amass.plugins.DataSource",
+	HandlerType: (*DataSourceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Info",
+			Handler:    _DataSource_Info_Handler,
+		},
+		{
+			MethodName: "Start",
+			Handler:    _DataSource_Start_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _DataSource_Stop_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Vertical",
+			Handler:       _DataSource_Vertical_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Horizontal",
+			Handler:       _DataSource_Horizontal_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Address",
+			Handler:       _DataSource_Address_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/plugin.proto",
+}
+
+const (
+	Sink_Info_FullMethodName      = "/amass.plugins.Sink/Info"
+	Sink_Configure_FullMethodName = "/amass.plugins.Sink/Configure"
+	Sink_Write_FullMethodName     = "/amass.plugins.Sink/Write"
+	Sink_Flush_FullMethodName     = "/amass.plugins.Sink/Flush"
+	Sink_Close_FullMethodName     = "/amass.plugins.Sink/Close"
+)
+
+// SinkClient is the client API for Sink service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SinkClient interface {
+	Info(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SinkInfo, error)
+	Configure(ctx context.Context, in *ConfigureRequest, opts ...grpc.CallOption) (*Empty, error)
+	Write(ctx context.Context, in *Event, opts ...grpc.CallOption) (*Empty, error)
+	Flush(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	Close(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type sinkClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSinkClient(cc grpc.ClientConnInterface) SinkClient {
+	return &sinkClient{cc}
+}
+
+func (c *sinkClient) Info(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SinkInfo, error) {
+	out := new(SinkInfo)
+	err := c.cc.Invoke(ctx, Sink_Info_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sinkClient) Configure(ctx context.Context, in *ConfigureRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Sink_Configure_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sinkClient) Write(ctx context.Context, in *Event, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Sink_Write_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sinkClient) Flush(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Sink_Flush_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sinkClient) Close(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Sink_Close_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SinkServer is the server API for Sink service.
+// All implementations must embed UnimplementedSinkServer
+// for forward compatibility
+type SinkServer interface {
+	Info(context.Context, *Empty) (*SinkInfo, error)
+	Configure(context.Context, *ConfigureRequest) (*Empty, error)
+	Write(context.Context, *Event) (*Empty, error)
+	Flush(context.Context, *Empty) (*Empty, error)
+	Close(context.Context, *Empty) (*Empty, error)
+	mustEmbedUnimplementedSinkServer()
+}
+
+// UnimplementedSinkServer must be embedded to have forward compatible implementations.
+type UnimplementedSinkServer struct {
+}
+
+func (UnimplementedSinkServer) Info(context.Context, *Empty) (*SinkInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Info not implemented")
+}
+func (UnimplementedSinkServer) Configure(context.Context, *ConfigureRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Configure not implemented")
+}
+func (UnimplementedSinkServer) Write(context.Context, *Event) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Write not implemented")
+}
+func (UnimplementedSinkServer) Flush(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Flush not implemented")
+}
+func (UnimplementedSinkServer) Close(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Close not implemented")
+}
+func (UnimplementedSinkServer) mustEmbedUnimplementedSinkServer() {}
+
+// UnsafeSinkServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SinkServer will
+// result in compilation errors.
+type UnsafeSinkServer interface {
+	mustEmbedUnimplementedSinkServer()
+}
+
+func RegisterSinkServer(s grpc.ServiceRegistrar, srv SinkServer) {
+	s.RegisterService(&Sink_ServiceDesc, srv)
+}
+
+func _Sink_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SinkServer).Info(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sink_Info_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SinkServer).Info(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sink_Configure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfigureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SinkServer).Configure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sink_Configure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SinkServer).Configure(ctx, req.(*ConfigureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sink_Write_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Event)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SinkServer).Write(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sink_Write_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SinkServer).Write(ctx, req.(*Event))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sink_Flush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SinkServer).Flush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sink_Flush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SinkServer).Flush(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sink_Close_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SinkServer).Close(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sink_Close_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SinkServer).Close(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Sink_ServiceDesc is the grpc.ServiceDesc for Sink service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Sink_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "amass.plugins.Sink",
+	HandlerType: (*SinkServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Info",
+			Handler:    _Sink_Info_Handler,
+		},
+		{
+			MethodName: "Configure",
+			Handler:    _Sink_Configure_Handler,
+		},
+		{
+			MethodName: "Write",
+			Handler:    _Sink_Write_Handler,
+		},
+		{
+			MethodName: "Flush",
+			Handler:    _Sink_Flush_Handler,
+		},
+		{
+			MethodName: "Close",
+			Handler:    _Sink_Close_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/plugin.proto",
+}
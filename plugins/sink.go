@@ -0,0 +1,114 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugins
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-plugin"
+	pb "github.com/owasp-amass/amass/v3/plugins/proto"
+	"google.golang.org/grpc"
+)
+
+// Sink is implemented by the plugins delivering the enumeration findings to external systems.
+type Sink interface {
+	// Name returns the name of the output sink
+	Name(ctx context.Context) (string, error)
+
+	// Configure provides the settings of the plugins section in the configuration
+	Configure(ctx context.Context, settings map[string]string) error
+
+	// Write delivers the JSON document of the event, which can be buffered until the sink is flushed
+	Write(ctx context.Context, event []byte) error
+
+	// Flush delivers the events buffered by the sink
+	Flush(ctx context.Context) error
+
+	// Close flushes the sink and releases the resources it acquired
+	Close(ctx context.Context) error
+}
+
+// SinkPlugin is the go-plugin implementation serving and consuming a Sink over gRPC.
+type SinkPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl Sink
+}
+
+// GRPCServer implements the GRPCPlugin interface.
+func (p *SinkPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	// The service is left unregistered when the plugin does not provide the output sink
+	if p.Impl != nil {
+		pb.RegisterSinkServer(s, &sinkServer{impl: p.Impl})
+	}
+	return nil
+}
+
+// GRPCClient implements the GRPCPlugin interface.
+func (p *SinkPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &sinkClient{client: pb.NewSinkClient(c)}, nil
+}
+
+// sinkClient is the Sink used by Amass to reach the plugin process.
+type sinkClient struct {
+	client pb.SinkClient
+}
+
+func (c *sinkClient) Name(ctx context.Context) (string, error) {
+	resp, err := c.client.Info(ctx, &pb.Empty{})
+	if err != nil {
+		return "", err
+	}
+	return resp.GetName(), nil
+}
+
+func (c *sinkClient) Configure(ctx context.Context, settings map[string]string) error {
+	_, err := c.client.Configure(ctx, &pb.ConfigureRequest{Settings: settings})
+	return err
+}
+
+func (c *sinkClient) Write(ctx context.Context, event []byte) error {
+	_, err := c.client.Write(ctx, &pb.Event{Json: event})
+	return err
+}
+
+func (c *sinkClient) Flush(ctx context.Context) error {
+	_, err := c.client.Flush(ctx, &pb.Empty{})
+	return err
+}
+
+func (c *sinkClient) Close(ctx context.Context) error {
+	_, err := c.client.Close(ctx, &pb.Empty{})
+	return err
+}
+
+// sinkServer serves the Sink implementation of the plugin to Amass.
+type sinkServer struct {
+	pb.UnimplementedSinkServer
+	impl Sink
+}
+
+func (s *sinkServer) Info(ctx context.Context, _ *pb.Empty) (*pb.SinkInfo, error) {
+	name, err := s.impl.Name(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SinkInfo{Name: name}, nil
+}
+
+func (s *sinkServer) Configure(ctx context.Context, req *pb.ConfigureRequest) (*pb.Empty, error) {
+	return &pb.Empty{}, s.impl.Configure(ctx, req.GetSettings())
+}
+
+func (s *sinkServer) Write(ctx context.Context, ev *pb.Event) (*pb.Empty, error) {
+	return &pb.Empty{}, s.impl.Write(ctx, ev.GetJson())
+}
+
+func (s *sinkServer) Flush(ctx context.Context, _ *pb.Empty) (*pb.Empty, error) {
+	return &pb.Empty{}, s.impl.Flush(ctx)
+}
+
+func (s *sinkServer) Close(ctx context.Context, _ *pb.Empty) (*pb.Empty, error) {
+	return &pb.Empty{}, s.impl.Close(ctx)
+}
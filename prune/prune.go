@@ -0,0 +1,119 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package prune identifies the assets in the graph that have not been seen by recent enumerations and removes them.
+package prune
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/cayleygraph/quad"
+)
+
+// The predicates of the event edges that do not lead to the assets discovered
+var eventPredicates = map[string]struct{}{
+	"used":   {},
+	"domain": {},
+}
+
+// Asset is a node of the graph discovered by the enumerations.
+type Asset struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Stale returns the assets that were last seen by enumerations finishing before the time provided, ordered by the time last seen.
+func Stale(ctx context.Context, g *netmap.Graph, before time.Time) ([]*Asset, error) {
+	assets := make(map[string]*Asset)
+
+	for _, uuid := range g.EventList(ctx) {
+		_, finish := g.EventDateRange(ctx, uuid)
+
+		quads, err := g.ReadEventQuads(ctx, uuid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the quads of event %s: %v", uuid, err)
+		}
+
+		types := make(map[string]string)
+		for _, q := range quads {
+			if valToStr(q.Predicate) == "type" {
+				types[valToStr(q.Subject)] = valToStr(q.Object)
+			}
+		}
+
+		for _, q := range quads {
+			if valToStr(q.Subject) != uuid {
+				continue
+			}
+			if _, found := eventPredicates[valToStr(q.Predicate)]; found {
+				continue
+			}
+			if _, ok := q.Object.(quad.IRI); !ok {
+				continue
+			}
+
+			id := valToStr(q.Object)
+			a, found := assets[id]
+			if !found {
+				a = &Asset{ID: id, Type: types[id]}
+				assets[id] = a
+			}
+			if finish.After(a.LastSeen) {
+				a.LastSeen = finish
+			}
+		}
+	}
+
+	var stale []*Asset
+	for _, a := range assets {
+		if a.LastSeen.Before(before) {
+			stale = append(stale, a)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		if !stale[i].LastSeen.Equal(stale[j].LastSeen) {
+			return stale[i].LastSeen.Before(stale[j].LastSeen)
+		}
+		return stale[i].ID < stale[j].ID
+	})
+	return stale, nil
+}
+
+// Remove deletes the edges and properties of the assets, including the edges associating them with the enumerations.
+// The enumerations are kept, and only the type of each asset remains in the graph database.
+func Remove(ctx context.Context, g *netmap.Graph, assets []*Asset) error {
+	for _, a := range assets {
+		node := netmap.Node(a.ID)
+		// The graph only removes the quads leaving the node
+		edges, err := g.ReadInEdges(ctx, node)
+		if err != nil {
+			return fmt.Errorf("failed to read the edges of %s: %v", a.ID, err)
+		}
+		for _, e := range edges {
+			if err := g.DeleteEdge(ctx, e); err != nil {
+				return fmt.Errorf("failed to remove the %s edge of %s: %v", e.Predicate, a.ID, err)
+			}
+		}
+		if err := g.DeleteNode(ctx, node); err != nil {
+			return fmt.Errorf("failed to remove %s: %v", a.ID, err)
+		}
+	}
+	return nil
+}
+
+func valToStr(v quad.Value) string {
+	switch val := v.(type) {
+	case quad.IRI:
+		return strings.TrimRight(strings.TrimLeft(string(val), "<"), ">")
+	case quad.String:
+		return string(val)
+	}
+	return ""
+}
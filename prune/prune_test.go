@@ -0,0 +1,126 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package prune
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caffix/netmap"
+)
+
+const (
+	ancientUUID = "5d1c2b7e-7a43-4a0e-9b8e-1f4c3d2e1a90"
+	olderUUID   = "ef9f9475-34eb-465e-81ec-9a1f7f8d2f5b"
+	newerUUID   = "0a3c4c48-59b4-4f7d-9d6c-2a4bba2f5f8e"
+)
+
+// testGraph returns a graph with enumerations finishing before and after the returned time.
+func testGraph(t *testing.T) (*netmap.Graph, time.Time) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+
+	for _, uuid := range []string{ancientUUID, olderUUID} {
+		if _, err := g.UpsertEvent(ctx, uuid); err != nil {
+			t.Fatalf("failed to insert the event: %v", err)
+		}
+	}
+	// The names of the domain are only found by the ancient enumeration
+	_ = g.UpsertA(ctx, "www.example.com", "10.0.0.1", "DNS", ancientUUID)
+	_ = g.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", olderUUID)
+	_ = g.UpsertA(ctx, "old.owasp.org", "192.168.1.3", "DNS", olderUUID)
+
+	// The graph databases store the times with a precision of seconds
+	time.Sleep(600 * time.Millisecond)
+	cutoff := time.Now()
+	time.Sleep(600 * time.Millisecond)
+
+	if _, err := g.UpsertEvent(ctx, newerUUID); err != nil {
+		t.Fatalf("failed to insert the event: %v", err)
+	}
+	_ = g.UpsertA(ctx, "www.owasp.org", "192.168.1.2", "DNS", newerUUID)
+	return g, cutoff
+}
+
+func assetIDs(assets []*Asset) []string {
+	var ids []string
+	for _, a := range assets {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func TestStale(t *testing.T) {
+	g, cutoff := testGraph(t)
+	defer g.Close()
+
+	stale, err := Stale(context.Background(), g, cutoff)
+	if err != nil {
+		t.Fatalf("failed to obtain the stale assets: %v", err)
+	}
+
+	expected := map[string]string{
+		"www.example.com": netmap.TypeFQDN,
+		"example.com":     netmap.TypeFQDN,
+		"com":             netmap.TypeFQDN,
+		"10.0.0.1":        netmap.TypeAddr,
+		"old.owasp.org":   netmap.TypeFQDN,
+		"192.168.1.1":     netmap.TypeAddr,
+		"192.168.1.3":     netmap.TypeAddr,
+	}
+	if len(stale) != len(expected) {
+		t.Fatalf("expected %d stale assets, got %v", len(expected), assetIDs(stale))
+	}
+	for i, a := range stale {
+		if typ, found := expected[a.ID]; !found || typ != a.Type {
+			t.Errorf("unexpected stale asset: %+v", a)
+		}
+		if !a.LastSeen.Before(cutoff) {
+			t.Errorf("%s was last seen at %v, after the cutoff", a.ID, a.LastSeen)
+		}
+		if i > 0 && a.LastSeen.Before(stale[i-1].LastSeen) {
+			t.Errorf("the stale assets are not ordered by the time last seen")
+		}
+	}
+
+	if stale, _ := Stale(context.Background(), g, cutoff.Add(-time.Hour)); len(stale) != 0 {
+		t.Errorf("expected no stale assets before the enumerations, got %v", assetIDs(stale))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	ctx := context.Background()
+	g, cutoff := testGraph(t)
+	defer g.Close()
+
+	stale, err := Stale(ctx, g, cutoff)
+	if err != nil {
+		t.Fatalf("failed to obtain the stale assets: %v", err)
+	}
+
+	if err := Remove(ctx, g, stale); err != nil {
+		t.Fatalf("failed to remove the stale assets: %v", err)
+	}
+
+	if events := g.EventList(ctx); len(events) != 3 {
+		t.Errorf("expected the enumerations to be kept, got %v", events)
+	}
+	if domains := g.EventDomains(ctx, ancientUUID); len(domains) != 0 {
+		t.Errorf("expected the ancient enumeration to have no domains, got %v", domains)
+	}
+	if stale, _ := Stale(ctx, g, cutoff); len(stale) != 0 {
+		t.Errorf("expected no stale assets after the removal, got %v", assetIDs(stale))
+	}
+
+	// The fresh name must no longer reference the removed address
+	edges, err := g.ReadOutEdges(ctx, netmap.Node("www.owasp.org"), "a_record")
+	if err != nil || len(edges) != 1 || g.NodeToID(edges[0].To) != "192.168.1.2" {
+		t.Errorf("unexpected address records of the fresh name: %v", edges)
+	}
+	if names := g.EventFQDNs(ctx, olderUUID); len(names) != 2 {
+		t.Errorf("expected the older enumeration to keep two names, got %v", names)
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+const reportTimeFormat = "2006-01-02 15:04:05 MST"
+
+var nodeColors = map[string]string{
+	"subdomain": "#229954",
+	"domain":    "#f22c0d",
+	"address":   "#f39c12",
+	"ptr":       "#edf31a",
+	"ns":        "#1af3f0",
+	"mx":        "#8e44ad",
+	"netblock":  "#f31abc",
+	"as":        "#1a45f3",
+}
+
+type graphNode struct {
+	Label string `json:"label"`
+	Title string `json:"title"`
+	Color string `json:"color"`
+}
+
+type graphEdge struct {
+	Source int    `json:"source"`
+	Target int    `json:"target"`
+	Label  string `json:"label"`
+}
+
+type graphData struct {
+	Nodes []graphNode `json:"nodes"`
+	Edges []graphEdge `json:"edges"`
+}
+
+var reportFuncs = template.FuncMap{
+	"datetime": func(t time.Time) string {
+		if t.IsZero() {
+			return "unknown"
+		}
+		return t.Format(reportTimeFormat)
+	},
+	"join": strings.Join,
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(reportFuncs).Parse(htmlTemplate))
+
+// WriteHTML renders the report as an HTML document with no external dependencies.
+func (r *Report) WriteHTML(output io.Writer) error {
+	graph := graphData{
+		Nodes: []graphNode{},
+		Edges: []graphEdge{},
+	}
+
+	for _, n := range r.Nodes {
+		graph.Nodes = append(graph.Nodes, graphNode{
+			Label: n.Label,
+			Title: n.Title,
+			Color: nodeColors[n.Type],
+		})
+	}
+	for _, e := range r.Edges {
+		graph.Edges = append(graph.Edges, graphEdge{
+			Source: e.From,
+			Target: e.To,
+			Label:  e.Title,
+		})
+	}
+
+	return reportTemplate.Execute(output, struct {
+		*Report
+		Graph graphData
+	}{Report: r, Graph: graph})
+}
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0 auto; max-width: 1200px; padding: 1em 2em; color: #222; }
+h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+h2 { border-bottom: 1px solid #ddd; padding-bottom: 0.2em; margin-top: 1.6em; }
+.meta { color: #666; margin-top: 0; }
+.stats { display: flex; flex-wrap: wrap; gap: 0.8em; }
+.stat { border: 1px solid #ddd; border-radius: 4px; padding: 0.6em 1em; min-width: 7em; }
+.stat .value { font-size: 1.6em; font-weight: bold; }
+.stat .label { color: #666; font-size: 0.9em; }
+table { border-collapse: collapse; width: 100%; font-size: 0.92em; }
+th, td { text-align: left; padding: 0.35em 0.6em; border-bottom: 1px solid #eee; vertical-align: top; }
+th { background: #f6f6f6; }
+.none { color: #666; font-style: italic; }
+.takeover { color: #b00; font-weight: bold; }
+.dangling { color: #c60; font-weight: bold; }
+.added { color: #1a7f37; }
+.removed { color: #b00; }
+#graph { border: 1px solid #ddd; border-radius: 4px; width: 100%; height: 640px; cursor: grab; }
+#tooltip { position: absolute; display: none; background: #fff; border: 1px solid #999; border-radius: 2px; padding: 4px 8px; font-size: 0.85em; pointer-events: none; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<p class="meta">Enumeration {{ .Event.UUID }}: {{ datetime .Event.Start }} to {{ datetime .Event.Finish }}<br>
+Generated {{ datetime .Generated }}</p>
+
+<h2>Summary</h2>
+<div class="stats">
+<div class="stat"><div class="value">{{ .Summary.Names }}</div><div class="label">Names</div></div>
+<div class="stat"><div class="value">{{ .Summary.Domains }}</div><div class="label">Domains</div></div>
+<div class="stat"><div class="value">{{ .Summary.Addresses }}</div><div class="label">Addresses</div></div>
+<div class="stat"><div class="value">{{ .Summary.Netblocks }}</div><div class="label">Netblocks</div></div>
+<div class="stat"><div class="value">{{ .Summary.ASNs }}</div><div class="label">Autonomous Systems</div></div>
+<div class="stat"><div class="value">{{ .Summary.Findings }}</div><div class="label">Findings</div></div>
+{{- if .Changes }}
+<div class="stat"><div class="value">{{ .Summary.Added }}</div><div class="label">New Names</div></div>
+<div class="stat"><div class="value">{{ .Summary.Removed }}</div><div class="label">Removed Names</div></div>
+<div class="stat"><div class="value">{{ .Summary.Changed }}</div><div class="label">Changed Names</div></div>
+{{- end }}
+</div>
+
+<h2>New Since the Last Run</h2>
+{{- if not .Changes }}
+<p class="none">No previous enumeration of these domains was found in the graph database.</p>
+{{- else }}
+<p class="meta">Compared with enumeration {{ .Previous.UUID }}: {{ datetime .Previous.Start }} to {{ datetime .Previous.Finish }}</p>
+{{- if or .Changes.Added .Changes.Removed .Changes.Changed }}
+<table>
+<tr><th>Change</th><th>Name</th><th>Details</th></tr>
+{{- range .Changes.Added }}
+<tr><td class="added">Added</td><td>{{ .Name }}</td><td>{{ join .Addresses ", " }}</td></tr>
+{{- end }}
+{{- range .Changes.Removed }}
+<tr><td class="removed">Removed</td><td>{{ .Name }}</td><td>{{ join .Addresses ", " }}</td></tr>
+{{- end }}
+{{- range .Changes.Changed }}
+<tr><td>Changed</td><td>{{ .Name }}</td><td>
+{{- range .AddedAddresses }}<span class="added">+{{ . }}</span> {{ end }}
+{{- range .RemovedAddresses }}<span class="removed">-{{ . }}</span> {{ end }}
+{{- range .AddedRecords }}<span class="added">+{{ .Type }} {{ .Value }}</span> {{ end }}
+{{- range .RemovedRecords }}<span class="removed">-{{ .Type }} {{ .Value }}</span> {{ end -}}
+</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p class="none">No differences were discovered.</p>
+{{- end }}
+{{- end }}
+
+<h2>Findings</h2>
+{{- if .Findings }}
+<table>
+<tr><th>Type</th><th>Name</th><th>Description</th><th>Evidence</th></tr>
+{{- range .Findings }}
+<tr><td class="{{ .Type }}">{{ .Type }}</td><td>{{ .Name }}</td><td>{{ .Description }}</td><td>{{ .Evidence }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p class="none">No subdomain takeovers or other findings were detected.</p>
+{{- end }}
+
+<h2>Data Source Contributions</h2>
+{{- if .Sources }}
+<table>
+<tr><th>Source</th><th>Names</th><th>Unique Names</th><th>Share of Names</th></tr>
+{{- range .Sources }}
+<tr><td>{{ .Name }}</td><td>{{ .Names }}</td><td>{{ .Unique }}</td><td>{{ printf "%.1f" .Percent }}%</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p class="none">No names were discovered.</p>
+{{- end }}
+
+<h2>Graph</h2>
+{{- if .GraphOmitted }}
+<p class="none">The graph was too large to embed in the report. Use the 'amass viz' subcommand to visualize it.</p>
+{{- else }}
+<p class="meta">Drag to pan, scroll to zoom and hover over the nodes for details.</p>
+<canvas id="graph"></canvas>
+<div id="tooltip"></div>
+<script>
+(function() {
+    var graph = {{ .Graph }};
+    var canvas = document.getElementById("graph");
+    var tooltip = document.getElementById("tooltip");
+    var ctx = canvas.getContext("2d");
+    var width = canvas.width = canvas.clientWidth;
+    var height = canvas.height = canvas.clientHeight;
+    var view = {x: width / 2, y: height / 2, k: 1};
+    var nodes = graph.nodes, edges = graph.edges;
+
+    nodes.forEach(function(n, i) {
+        var angle = i * 2.399963, radius = 10 * Math.sqrt(i);
+        n.x = radius * Math.cos(angle);
+        n.y = radius * Math.sin(angle);
+        n.vx = 0;
+        n.vy = 0;
+    });
+
+    function tick(alpha) {
+        var i, j, a, b, dx, dy, d2, d, f;
+        for (i = 0; i < nodes.length; i++) {
+            a = nodes[i];
+            for (j = i + 1; j < nodes.length; j++) {
+                b = nodes[j];
+                dx = b.x - a.x;
+                dy = b.y - a.y;
+                d2 = dx * dx + dy * dy || 0.01;
+                if (d2 > 90000) {
+                    continue;
+                }
+                f = 300 * alpha / d2;
+                a.vx -= dx * f; a.vy -= dy * f;
+                b.vx += dx * f; b.vy += dy * f;
+            }
+        }
+        edges.forEach(function(e) {
+            a = nodes[e.source];
+            b = nodes[e.target];
+            dx = b.x - a.x;
+            dy = b.y - a.y;
+            d = Math.sqrt(dx * dx + dy * dy) || 0.01;
+            f = (d - 40) / d * 0.1 * alpha;
+            a.vx += dx * f; a.vy += dy * f;
+            b.vx -= dx * f; b.vy -= dy * f;
+        });
+        nodes.forEach(function(n) {
+            n.vx = (n.vx - n.x * 0.01 * alpha) * 0.6;
+            n.vy = (n.vy - n.y * 0.01 * alpha) * 0.6;
+            n.x += n.vx;
+            n.y += n.vy;
+        });
+    }
+
+    function draw() {
+        ctx.setTransform(1, 0, 0, 1, 0, 0);
+        ctx.clearRect(0, 0, width, height);
+        ctx.setTransform(view.k, 0, 0, view.k, view.x, view.y);
+        ctx.strokeStyle = "#aaa";
+        ctx.lineWidth = 1 / view.k;
+        ctx.beginPath();
+        edges.forEach(function(e) {
+            ctx.moveTo(nodes[e.source].x, nodes[e.source].y);
+            ctx.lineTo(nodes[e.target].x, nodes[e.target].y);
+        });
+        ctx.stroke();
+        nodes.forEach(function(n) {
+            ctx.beginPath();
+            ctx.arc(n.x, n.y, 5, 0, 2 * Math.PI);
+            ctx.fillStyle = n.color || "#999";
+            ctx.fill();
+        });
+    }
+
+    var alpha = 1;
+    (function animate() {
+        if (alpha > 0.01) {
+            tick(alpha);
+            alpha *= 0.98;
+            draw();
+            window.requestAnimationFrame(animate);
+        }
+    })();
+
+    function nodeAt(px, py) {
+        var x = (px - view.x) / view.k, y = (py - view.y) / view.k;
+        for (var i = nodes.length - 1; i >= 0; i--) {
+            var dx = nodes[i].x - x, dy = nodes[i].y - y;
+            if (dx * dx + dy * dy < 49) {
+                return nodes[i];
+            }
+        }
+        return null;
+    }
+
+    var drag = null;
+    canvas.addEventListener("mousedown", function(ev) {
+        drag = {x: ev.offsetX - view.x, y: ev.offsetY - view.y};
+    });
+    window.addEventListener("mouseup", function() {
+        drag = null;
+    });
+    canvas.addEventListener("mousemove", function(ev) {
+        if (drag) {
+            view.x = ev.offsetX - drag.x;
+            view.y = ev.offsetY - drag.y;
+            draw();
+            return;
+        }
+        var n = nodeAt(ev.offsetX, ev.offsetY);
+        if (!n) {
+            tooltip.style.display = "none";
+            return;
+        }
+        tooltip.textContent = n.title;
+        tooltip.style.left = (ev.pageX + 12) + "px";
+        tooltip.style.top = (ev.pageY + 12) + "px";
+        tooltip.style.display = "block";
+    });
+    canvas.addEventListener("wheel", function(ev) {
+        ev.preventDefault();
+        var k = ev.deltaY < 0 ? 1.2 : 1 / 1.2;
+        view.x = ev.offsetX - (ev.offsetX - view.x) * k;
+        view.y = ev.offsetY - (ev.offsetY - view.y) * k;
+        view.k *= k;
+        draw();
+    });
+})();
+</script>
+{{- end }}
+</body>
+</html>
+`
@@ -0,0 +1,208 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package report builds a self-contained HTML report of an enumeration from the graph database.
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/owasp-amass/amass/v3/diff"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/viz"
+)
+
+// MaxGraphNodes is the largest graph embedded in the report before it is left out.
+const MaxGraphNodes = 2000
+
+// The order that finding types are presented in the report
+var findingOrder = map[string]int{
+	requests.FindingTakeover: 0,
+	requests.FindingDangling: 1,
+	requests.FindingBucket:   2,
+}
+
+// Summary contains the statistics for the enumeration covered by the report.
+type Summary struct {
+	Names     int
+	Domains   int
+	Addresses int
+	Netblocks int
+	ASNs      int
+	Findings  int
+	Added     int
+	Removed   int
+	Changed   int
+}
+
+// Finding is an issue detected for a name discovered by the enumeration.
+type Finding struct {
+	Name string
+	*requests.Finding
+}
+
+// Source describes the contribution of a data source to the enumeration.
+type Source struct {
+	Name    string
+	Names   int
+	Unique  int
+	Percent float64
+}
+
+// Report contains the information rendered into the HTML report.
+type Report struct {
+	Title     string
+	Generated time.Time
+	Domains   []string
+	Event     *diff.Event
+	Previous  *diff.Event
+	Summary   Summary
+	Changes   *diff.Diff
+	Findings  []*Finding
+	Sources   []*Source
+	Nodes     []viz.Node
+	Edges     []viz.Edge
+	// GraphOmitted is true when the graph was too large to embed in the report
+	GraphOmitted bool
+}
+
+// New returns the Report for the enumeration identified by the uuid, compared with the enumeration identified
+// by prev, when prev is not empty. The assets are the names discovered by the enumeration within the domains.
+func New(ctx context.Context, g *netmap.Graph, uuid, prev string, domains []string, assets []*requests.Output) (*Report, error) {
+	if len(domains) == 0 {
+		domains = g.EventDomains(ctx, uuid)
+	}
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("failed to identify the domains of enumeration %s", uuid)
+	}
+
+	start, finish := g.EventDateRange(ctx, uuid)
+	r := &Report{
+		Title:     "OWASP Amass Report: " + strings.Join(domains, ", "),
+		Generated: time.Now(),
+		Domains:   domains,
+		Event:     &diff.Event{UUID: uuid, Start: start, Finish: finish},
+		Findings:  findings(assets),
+		Sources:   sources(assets),
+	}
+
+	if prev != "" {
+		d, err := diff.Events(ctx, g, prev, uuid, domains)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare with the previous enumeration: %v", err)
+		}
+		r.Changes = d
+		r.Previous = d.From
+		r.Summary.Added = len(d.Added)
+		r.Summary.Removed = len(d.Removed)
+		r.Summary.Changed = len(d.Changed)
+	}
+
+	r.Nodes, r.Edges = viz.VizData(ctx, g, []string{uuid})
+	for _, n := range r.Nodes {
+		switch n.Type {
+		case "netblock":
+			r.Summary.Netblocks++
+		case "as":
+			r.Summary.ASNs++
+		}
+	}
+	if len(r.Nodes) > MaxGraphNodes {
+		r.Nodes, r.Edges = nil, nil
+		r.GraphOmitted = true
+	}
+
+	doms := make(map[string]struct{})
+	addrs := make(map[string]struct{})
+	for _, a := range assets {
+		doms[a.Domain] = struct{}{}
+		for _, addr := range a.Addresses {
+			addrs[addr.Address.String()] = struct{}{}
+		}
+	}
+	r.Summary.Names = len(assets)
+	r.Summary.Domains = len(doms)
+	r.Summary.Addresses = len(addrs)
+	r.Summary.Findings = len(r.Findings)
+	return r, nil
+}
+
+func findings(assets []*requests.Output) []*Finding {
+	var results []*Finding
+
+	for _, a := range assets {
+		seen := make(map[string]struct{})
+
+		for _, f := range a.Findings {
+			key := f.Type + f.Description
+			if _, found := seen[key]; found {
+				continue
+			}
+			seen[key] = struct{}{}
+			results = append(results, &Finding{Name: a.Name, Finding: f})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		oi, found := findingOrder[results[i].Type]
+		if !found {
+			oi = len(findingOrder)
+		}
+		oj, found := findingOrder[results[j].Type]
+		if !found {
+			oj = len(findingOrder)
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		if results[i].Type != results[j].Type {
+			return results[i].Type < results[j].Type
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+func sources(assets []*requests.Output) []*Source {
+	srcs := make(map[string]*Source)
+
+	for _, a := range assets {
+		names := make(map[string]struct{})
+		for _, src := range a.Sources {
+			names[src] = struct{}{}
+		}
+
+		for name := range names {
+			s, found := srcs[name]
+			if !found {
+				s = &Source{Name: name}
+				srcs[name] = s
+			}
+
+			s.Names++
+			if len(names) == 1 {
+				s.Unique++
+			}
+		}
+	}
+
+	var results []*Source
+	for _, s := range srcs {
+		s.Percent = 100 * float64(s.Names) / float64(len(assets))
+		results = append(results, s)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Names != results[j].Names {
+			return results[i].Names > results[j].Names
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
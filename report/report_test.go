@@ -0,0 +1,106 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/caffix/netmap"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+func testAssets() []*requests.Output {
+	return []*requests.Output{
+		{
+			Name:      "www.owasp.org",
+			Domain:    "owasp.org",
+			Sources:   []string{"Crtsh", "DNS"},
+			Addresses: []requests.AddressInfo{{Address: net.ParseIP("192.168.1.10")}},
+		},
+		{
+			Name:      "api.owasp.org",
+			Domain:    "owasp.org",
+			Sources:   []string{"DNS"},
+			Addresses: []requests.AddressInfo{{Address: net.ParseIP("192.168.1.10")}},
+			Findings: []*requests.Finding{
+				{Type: requests.FindingDangling, Description: "CNAME target does not resolve"},
+				{Type: requests.FindingTakeover, Description: "<script>GitHub Pages</script>"},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	_ = g.UpsertA(ctx, "www.owasp.org", "192.168.1.10", "DNS", "first")
+	_ = g.UpsertA(ctx, "www.owasp.org", "192.168.1.10", "DNS", "second")
+	_ = g.UpsertA(ctx, "api.owasp.org", "192.168.1.10", "DNS", "second")
+	_ = g.UpsertInfrastructure(ctx, 64496, "EXAMPLE-AS", "192.168.1.10", "192.168.1.0/24", "RIR", "second")
+
+	r, err := New(ctx, g, "second", "first", []string{"owasp.org"}, testAssets())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	expected := Summary{Names: 2, Domains: 1, Addresses: 1, Netblocks: 1, ASNs: 1, Findings: 2, Added: 1}
+	if r.Summary != expected {
+		t.Errorf("New() returned the summary %+v, expected %+v", r.Summary, expected)
+	}
+	if len(r.Findings) != 2 || r.Findings[0].Type != requests.FindingTakeover {
+		t.Errorf("New() did not put the takeover finding first: %v", r.Findings)
+	}
+	if len(r.Sources) != 2 || r.Sources[0].Name != "DNS" || r.Sources[0].Unique != 1 || r.Sources[1].Percent != 50 {
+		t.Errorf("New() returned the wrong source contributions")
+	}
+	if len(r.Nodes) == 0 || len(r.Edges) == 0 {
+		t.Errorf("New() did not provide the graph")
+	}
+
+	if _, err := New(ctx, g, "missing", "", nil, nil); err == nil {
+		t.Errorf("New() accepted an enumeration without domains")
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	_ = g.UpsertA(ctx, "api.owasp.org", "192.168.1.10", "DNS", "only")
+
+	r, err := New(ctx, g, "only", "", nil, testAssets())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteHTML(&buf); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+
+	html := buf.String()
+	for _, s := range []string{
+		"<title>OWASP Amass Report: owasp.org</title>",
+		"No previous enumeration",
+		`<td class="takeover">takeover</td><td>api.owasp.org</td>`,
+		"&lt;script&gt;GitHub Pages&lt;/script&gt;",
+		"<td>Crtsh</td><td>1</td><td>0</td><td>50.0%</td>",
+		`"label":"api.owasp.org"`,
+	} {
+		if !strings.Contains(html, s) {
+			t.Errorf("WriteHTML() output is missing %q", s)
+		}
+	}
+	if strings.Contains(html, "<script src=") {
+		t.Errorf("WriteHTML() output depends on an external script")
+	}
+}
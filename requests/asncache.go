@@ -34,6 +34,9 @@ var reservedCIDRs = []string{
 	"192.0.0.0/29",
 }
 
+// NotRoutedDescription is the description of the address ranges that the ASN data shows as not routed.
+const NotRoutedDescription = "Not routed"
+
 // ASNCache builds a cache of ASN and netblock information.
 type ASNCache struct {
 	sync.RWMutex
@@ -0,0 +1,46 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// FindingPredicate is the graph property predicate used to store findings on asset nodes.
+const FindingPredicate = "finding"
+
+// Finding types reported by the enumeration.
+const (
+	FindingBucket   = "bucket"
+	FindingDangling = "dangling"
+	FindingTakeover = "takeover"
+)
+
+// Finding represents an issue detected for an asset discovered during the enumeration.
+type Finding struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Evidence    string `json:"evidence,omitempty"`
+}
+
+// String returns the Finding encoded for storage as a graph property value.
+func (f *Finding) String() string {
+	b, err := json.Marshal(f)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// ParseFinding decodes a Finding previously encoded by the String method.
+func ParseFinding(s string) (*Finding, bool) {
+	var f Finding
+
+	if err := json.Unmarshal([]byte(strings.TrimSpace(s)), &f); err != nil || f.Type == "" {
+		return nil, false
+	}
+	return &f, true
+}
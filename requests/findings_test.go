@@ -0,0 +1,27 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import "testing"
+
+func TestParseFinding(t *testing.T) {
+	f := &Finding{
+		Type:        FindingTakeover,
+		Description: "Possible GitHub Pages subdomain takeover",
+		Evidence:    "CNAME example.github.io",
+	}
+
+	got, ok := ParseFinding(f.String())
+	if !ok {
+		t.Fatalf("ParseFinding() failed to decode %s", f.String())
+	}
+	if *got != *f {
+		t.Errorf("ParseFinding() returned %v, expected %v", got, f)
+	}
+
+	if _, ok := ParseFinding("not a finding"); ok {
+		t.Errorf("ParseFinding() accepted an invalid value")
+	}
+}
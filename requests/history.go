@@ -0,0 +1,63 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// DNSHistoryPredicate is the graph property predicate used to store the historical DNS records on FQDN nodes.
+const DNSHistoryPredicate = "dns_history"
+
+// HistoricalRecord is a DNS record that a data source observed for a name in the past. The first
+// and last seen dates are provided as reported by the data source.
+type HistoricalRecord struct {
+	Type      int    `json:"type"`
+	Data      string `json:"data"`
+	FirstSeen string `json:"first_seen,omitempty"`
+	LastSeen  string `json:"last_seen,omitempty"`
+}
+
+// DNSHistoryRequest contains the historical DNS records of a name provided by a data source.
+type DNSHistoryRequest struct {
+	Name    string
+	Domain  string
+	Records []HistoricalRecord
+	Tag     string
+	Source  string
+}
+
+// String returns the HistoricalRecord encoded for storage as a graph property value.
+func (r *HistoricalRecord) String() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// ParseHistoricalRecord decodes a HistoricalRecord previously encoded by the String method.
+func ParseHistoricalRecord(s string) (*HistoricalRecord, bool) {
+	var r HistoricalRecord
+
+	if err := json.Unmarshal([]byte(strings.TrimSpace(s)), &r); err != nil || r.Type == 0 || r.Data == "" {
+		return nil, false
+	}
+	return &r, true
+}
+
+// HistoryEdgePredicate returns the graph edge predicate linking the FQDN node to the data of a
+// historical record of the DNS type, such as 'a_record_history'. Edges with these predicates are
+// kept apart from the edges of the current records.
+func HistoryEdgePredicate(rrtype int) string {
+	t, found := dns.TypeToString[uint16(rrtype)]
+	if !found {
+		return ""
+	}
+	return strings.ToLower(t) + "_record_history"
+}
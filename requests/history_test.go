@@ -0,0 +1,49 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestParseHistoricalRecord(t *testing.T) {
+	r := &HistoricalRecord{
+		Type:      int(dns.TypeA),
+		Data:      "192.0.2.1",
+		FirstSeen: "2019-04-01",
+		LastSeen:  "2021-11-30",
+	}
+
+	got, ok := ParseHistoricalRecord(r.String())
+	if !ok {
+		t.Fatalf("ParseHistoricalRecord() failed to decode %s", r.String())
+	}
+	if *got != *r {
+		t.Errorf("ParseHistoricalRecord() returned %v, expected %v", got, r)
+	}
+
+	if _, ok := ParseHistoricalRecord(`{"type":1}`); ok {
+		t.Errorf("ParseHistoricalRecord() accepted a record without data")
+	}
+}
+
+func TestHistoryEdgePredicate(t *testing.T) {
+	for _, tc := range []struct {
+		rrtype   uint16
+		expected string
+	}{
+		{rrtype: dns.TypeA, expected: "a_record_history"},
+		{rrtype: dns.TypeAAAA, expected: "aaaa_record_history"},
+		{rrtype: dns.TypeNS, expected: "ns_record_history"},
+		{rrtype: dns.TypeMX, expected: "mx_record_history"},
+		{rrtype: 0xfeee, expected: ""},
+	} {
+		if got := HistoryEdgePredicate(int(tc.rrtype)); got != tc.expected {
+			t.Errorf("HistoryEdgePredicate(%d) returned %s, expected %s", tc.rrtype, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// HTTPPredicate is the graph property predicate used to store the HTTP probe results on FQDN nodes.
+const HTTPPredicate = "http"
+
+// HTTPInfo stores the details of the response received when probing a discovered name over HTTP.
+// The Screenshot is the path of the captured image, relative to the output directory,
+// and the WAF is the name of the web application firewall found protecting the page.
+type HTTPInfo struct {
+	URL          string   `json:"url"`
+	StatusCode   int      `json:"status"`
+	Title        string   `json:"title,omitempty"`
+	Server       string   `json:"server,omitempty"`
+	Redirects    []string `json:"redirects,omitempty"`
+	Length       int64    `json:"length"`
+	Technologies []string `json:"technologies,omitempty"`
+	Screenshot   string   `json:"screenshot,omitempty"`
+	WAF          string   `json:"waf,omitempty"`
+}
+
+// Technologies returns the web technologies identified in the HTTP probe results of the output, without duplicates.
+func (o *Output) Technologies() []string {
+	var results []string
+	seen := make(map[string]struct{})
+
+	for _, h := range o.HTTP {
+		for _, t := range h.Technologies {
+			if _, found := seen[t]; !found {
+				seen[t] = struct{}{}
+				results = append(results, t)
+			}
+		}
+	}
+	return results
+}
+
+// WAFs returns the web application firewalls found protecting the pages in the HTTP probe results of the output.
+func (o *Output) WAFs() []string {
+	var results []string
+	seen := make(map[string]struct{})
+
+	for _, h := range o.HTTP {
+		if h.WAF == "" {
+			continue
+		}
+		if _, found := seen[h.WAF]; !found {
+			seen[h.WAF] = struct{}{}
+			results = append(results, h.WAF)
+		}
+	}
+	return results
+}
+
+// String returns the HTTPInfo encoded for storage as a graph property value.
+func (h *HTTPInfo) String() string {
+	b, err := json.Marshal(h)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// ParseHTTPInfo decodes an HTTPInfo previously encoded by the String method.
+func ParseHTTPInfo(s string) (*HTTPInfo, bool) {
+	var h HTTPInfo
+
+	if err := json.Unmarshal([]byte(strings.TrimSpace(s)), &h); err != nil || h.URL == "" {
+		return nil, false
+	}
+	return &h, true
+}
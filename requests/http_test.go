@@ -0,0 +1,57 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHTTPInfo(t *testing.T) {
+	h := &HTTPInfo{
+		URL:          "https://www.owasp.org/login",
+		StatusCode:   200,
+		Title:        "Sign In",
+		Server:       "nginx",
+		Redirects:    []string{"https://www.owasp.org/login"},
+		Length:       1024,
+		Technologies: []string{"nginx/1.18.0", "PHP"},
+	}
+
+	got, ok := ParseHTTPInfo(h.String())
+	if !ok || !reflect.DeepEqual(got, h) {
+		t.Errorf("ParseHTTPInfo() returned %v, expected %v", got, h)
+	}
+	if _, ok := ParseHTTPInfo(`{"status": 200}`); ok {
+		t.Errorf("ParseHTTPInfo() accepted a value without a URL")
+	}
+}
+
+func TestOutputTechnologies(t *testing.T) {
+	o := &Output{
+		HTTP: []*HTTPInfo{
+			{URL: "https://www.owasp.org", Technologies: []string{"nginx/1.18.0", "PHP"}},
+			{URL: "http://www.owasp.org", Technologies: []string{"nginx/1.18.0"}},
+		},
+	}
+
+	if got, want := o.Technologies(), []string{"nginx/1.18.0", "PHP"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Output.Technologies() returned %v, expected %v", got, want)
+	}
+}
+
+func TestOutputWAFs(t *testing.T) {
+	o := &Output{
+		HTTP: []*HTTPInfo{
+			{URL: "https://www.owasp.org", WAF: "Cloudflare"},
+			{URL: "http://www.owasp.org", WAF: "Cloudflare"},
+			{URL: "https://api.owasp.org"},
+		},
+	}
+
+	if got, want := o.WAFs(), []string{"Cloudflare"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Output.WAFs() returned %v, expected %v", got, want)
+	}
+}
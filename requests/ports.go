@@ -0,0 +1,34 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+// The graph node type and edge predicate used to store the open ports of an address.
+// Port nodes are identified by the address and port number, such as '192.0.2.1:443',
+// and store the service banner collected from the port using the BannerPredicate.
+const (
+	TypePort        = "port"
+	PortPredicate   = "port"
+	BannerPredicate = "banner"
+)
+
+// CPEPredicate is the graph property predicate used to store, on address nodes, the CPE names of
+// the software that a data source identified on the address.
+const CPEPredicate = "cpe"
+
+// PortInfo stores an open port discovered on an address.
+type PortInfo struct {
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+	Banner   string `json:"banner,omitempty"`
+}
+
+// ServicesRequest contains the open ports and the software of an address provided by a data source.
+type ServicesRequest struct {
+	Address string
+	Ports   []int
+	CPEs    []string
+	Tag     string
+	Source  string
+}
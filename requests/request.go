@@ -7,6 +7,7 @@ package requests
 import (
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	amassdns "github.com/owasp-amass/amass/v3/net/dns"
@@ -28,6 +29,8 @@ const (
 	DNS      = "dns"
 	RIR      = "rir"
 	EXTERNAL = "ext"
+	IMPORT   = "import"
+	LEAK     = "leak"
 	SCRAPE   = "scrape"
 )
 
@@ -61,15 +64,40 @@ type DNSRequest struct {
 	Source  string
 }
 
+// The requests reused across the pipeline, since the brute forcing of large wordlists
+// otherwise allocates a request for every name attempted.
+var dnsRequestPool = sync.Pool{
+	New: func() interface{} { return new(DNSRequest) },
+}
+
+// NewDNSRequest returns a DNSRequest from the pool with the provided fields set.
+func NewDNSRequest(name, domain, tag, source string) *DNSRequest {
+	d := dnsRequestPool.Get().(*DNSRequest)
+
+	d.Name = name
+	d.Domain = domain
+	d.Tag = tag
+	d.Source = source
+	return d
+}
+
+// ReleaseDNSRequest returns the DNSRequest to the pool. The request, including
+// the Records slice, must no longer be referenced after the call.
+func ReleaseDNSRequest(d *DNSRequest) {
+	if d == nil {
+		return
+	}
+	// The Records slice is not kept, since other requests may share the backing array
+	*d = DNSRequest{}
+	dnsRequestPool.Put(d)
+}
+
 // Clone implements pipeline Data.
 func (d *DNSRequest) Clone() pipeline.Data {
-	return &DNSRequest{
-		Name:    d.Name,
-		Domain:  d.Domain,
-		Records: append([]DNSAnswer(nil), d.Records...),
-		Tag:     d.Tag,
-		Source:  d.Source,
-	}
+	c := NewDNSRequest(d.Name, d.Domain, d.Tag, d.Source)
+
+	c.Records = append([]DNSAnswer(nil), d.Records...)
+	return c
 }
 
 // MarkAsProcessed implements pipeline Data.
@@ -293,6 +321,11 @@ type Output struct {
 	Addresses []AddressInfo `json:"addresses"`
 	Tag       string        `json:"tag"`
 	Sources   []string      `json:"sources"`
+	Findings  []*Finding    `json:"findings,omitempty"`
+	HTTP      []*HTTPInfo   `json:"http,omitempty"`
+	// The times the name was first and last seen across the enumerations, in RFC 3339 format
+	FirstSeen string `json:"first_seen,omitempty"`
+	LastSeen  string `json:"last_seen,omitempty"`
 }
 
 // Clone implements pipeline Data.
@@ -303,6 +336,10 @@ func (o *Output) Clone() pipeline.Data {
 		Addresses: append([]AddressInfo(nil), o.Addresses...),
 		Tag:       o.Tag,
 		Sources:   append([]string(nil), o.Sources...),
+		Findings:  append([]*Finding(nil), o.Findings...),
+		HTTP:      append([]*HTTPInfo(nil), o.HTTP...),
+		FirstSeen: o.FirstSeen,
+		LastSeen:  o.LastSeen,
 	}
 }
 
@@ -332,6 +369,33 @@ func (o *Output) Complete(passive bool) bool {
 	return true
 }
 
+// Confidence returns the confidence, from 0 to 100, that the discovered name is a real asset. The
+// names start with the trust in the technique that discovered them, where the guesses of brute forcing
+// and alterations are the least trusted, followed by the indexes of leaked and exposed services, and
+// gain confidence when resolved to addresses and when reported by several data sources.
+func (o *Output) Confidence() int {
+	score := 40
+	switch {
+	case TrustedTag(o.Tag):
+		score = 60
+	case o.Tag == BRUTE || o.Tag == ALT || o.Tag == GUESS:
+		score = 20
+	case o.Tag == LEAK:
+		score = 30
+	}
+
+	if len(o.Addresses) > 0 {
+		score += 30
+	}
+	if num := len(o.Sources); num > 1 {
+		score += 10 * (num - 1)
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
+
 // AddressInfo stores all network addressing info for the Output type.
 type AddressInfo struct {
 	Address     net.IP     `json:"ip"`
@@ -339,6 +403,15 @@ type AddressInfo struct {
 	CIDRStr     string     `json:"cidr"`
 	ASN         int        `json:"asn"`
 	Description string     `json:"desc"`
+	Provider    string     `json:"provider,omitempty"`
+	Service     string     `json:"service,omitempty"`
+	Region      string     `json:"region,omitempty"`
+	Ports       []PortInfo `json:"ports,omitempty"`
+	// The CPE names of the software that the data sources identified on the address
+	CPEs []string `json:"cpes,omitempty"`
+	// The times the name was first and last seen resolving to the address, in RFC 3339 format
+	FirstSeen string `json:"first_seen,omitempty"`
+	LastSeen  string `json:"last_seen,omitempty"`
 }
 
 // TrustedTag returns true when the tag parameter is of a type that should be trusted even
@@ -5,6 +5,7 @@
 package requests
 
 import (
+	"net"
 	"testing"
 	"time"
 
@@ -36,6 +37,31 @@ func TestTrustedTag(t *testing.T) {
 	}
 }
 
+func TestOutputConfidence(t *testing.T) {
+	addrs := []AddressInfo{{Address: net.ParseIP("192.0.2.1")}}
+
+	tests := []struct {
+		Output   *Output
+		Expected int
+	}{
+		{&Output{Tag: BRUTE, Sources: []string{"Brute Forcing"}}, 20},
+		{&Output{Tag: ALT, Sources: []string{"Alterations"}, Addresses: addrs}, 50},
+		{&Output{Tag: API, Sources: []string{"Shodan"}}, 40},
+		{&Output{Tag: LEAK, Sources: []string{"LeakIX"}}, 30},
+		{&Output{Tag: LEAK, Sources: []string{"LeakIX", "Shodan"}, Addresses: addrs}, 70},
+		{&Output{Tag: API, Sources: []string{"Shodan", "URLScan"}, Addresses: addrs}, 80},
+		{&Output{Tag: CERT, Sources: []string{"Crtsh"}}, 60},
+		{&Output{Tag: CERT, Sources: []string{"Crtsh", "DNS", "Shodan"}, Addresses: addrs}, 100},
+	}
+
+	for _, test := range tests {
+		if c := test.Output.Confidence(); c != test.Expected {
+			t.Errorf("the %s output from %v returned the confidence %d instead of %d",
+				test.Output.Tag, test.Output.Sources, c, test.Expected)
+		}
+	}
+}
+
 func TestDNSRequestClone(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -66,6 +92,56 @@ func TestDNSRequestClone(t *testing.T) {
 
 }
 
+func TestDNSRequestPool(t *testing.T) {
+	req := NewDNSRequest("www.owasp.org", "owasp.org", DNS, "DNS")
+	req.Records = []DNSAnswer{{Name: "www.owasp.org", Type: 1, Data: "192.168.1.1"}}
+
+	clone := req.Clone().(*DNSRequest)
+	ReleaseDNSRequest(req)
+	require.Equal(t, DNSRequest{}, *req)
+	require.Equal(t, "www.owasp.org", clone.Name)
+	require.Len(t, clone.Records, 1)
+
+	ReleaseDNSRequest(nil)
+	next := NewDNSRequest("ftp.owasp.org", "owasp.org", BRUTE, "Brute Forcing")
+	require.Equal(t, DNSRequest{Name: "ftp.owasp.org", Domain: "owasp.org", Tag: BRUTE, Source: "Brute Forcing"}, *next)
+}
+
+// Keeps the compiler from allocating the benchmarked requests on the stack
+var benchRequest *DNSRequest
+
+func BenchmarkDNSRequestAlloc(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchRequest = &DNSRequest{
+			Name:   "www.owasp.org",
+			Domain: "owasp.org",
+			Tag:    BRUTE,
+			Source: "Brute Forcing",
+		}
+	}
+}
+
+func BenchmarkDNSRequestPool(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchRequest = NewDNSRequest("www.owasp.org", "owasp.org", BRUTE, "Brute Forcing")
+		ReleaseDNSRequest(benchRequest)
+	}
+}
+
+func BenchmarkDNSRequestPoolParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			req := NewDNSRequest("www.owasp.org", "owasp.org", BRUTE, "Brute Forcing")
+			clone := req.Clone().(*DNSRequest)
+			ReleaseDNSRequest(req)
+			ReleaseDNSRequest(clone)
+		}
+	})
+}
+
 func TestDNSRequestValid(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -8,6 +8,8 @@ import (
 	"compress/gzip"
 	"embed"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -16,7 +18,7 @@ import (
 	"strconv"
 )
 
-//go:embed scripts ip2asn-combined.tsv.gz alterations.txt namelist.txt user_agents.txt
+//go:embed scripts ip2asn-combined.tsv.gz alterations.txt namelist.txt user_agents.txt takeovers.json technologies.json
 var resourceFS embed.FS
 
 // IP2ASN is a range record provided by the iptoasn.com service.
@@ -69,6 +71,86 @@ func GetIP2ASNData() ([]*IP2ASN, error) {
 	return ranges, nil
 }
 
+// TakeoverFingerprint describes how to identify a dangling CNAME record pointing at a vulnerable service.
+type TakeoverFingerprint struct {
+	Service     string   `json:"service"`
+	CNAME       []string `json:"cname"`
+	Fingerprint []string `json:"fingerprint,omitempty"`
+	NXDomain    bool     `json:"nxdomain,omitempty"`
+	HTTPStatus  int      `json:"http_status,omitempty"`
+}
+
+// GetTakeoverFingerprints returns the subdomain takeover fingerprints read from the 'takeovers.json' file.
+func GetTakeoverFingerprints() ([]*TakeoverFingerprint, error) {
+	file, err := resourceFS.Open("takeovers.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the 'takeovers.json' file: %v", err)
+	}
+	defer file.Close()
+
+	return ParseTakeoverFingerprints(file)
+}
+
+// ParseTakeoverFingerprints decodes the JSON array of subdomain takeover fingerprints provided by the reader.
+func ParseTakeoverFingerprints(r io.Reader) ([]*TakeoverFingerprint, error) {
+	var fps []*TakeoverFingerprint
+
+	if err := json.NewDecoder(r).Decode(&fps); err != nil {
+		return nil, fmt.Errorf("failed to decode the takeover fingerprints: %v", err)
+	}
+	for _, fp := range fps {
+		if fp.Service == "" || len(fp.CNAME) == 0 {
+			return nil, fmt.Errorf("the takeover fingerprint for %s did not provide a service and CNAME", fp.Service)
+		}
+		if !fp.NXDomain && len(fp.Fingerprint) == 0 {
+			return nil, fmt.Errorf("the takeover fingerprint for %s did not provide response fingerprints", fp.Service)
+		}
+	}
+	return fps, nil
+}
+
+// TechFingerprint describes the signatures that identify a web technology in HTTP responses. Each
+// signature is a regular expression, where an empty expression only requires the header, cookie or
+// meta element to be present, and the first capture group provides the version when available.
+type TechFingerprint struct {
+	Name       string            `json:"name"`
+	Categories []string          `json:"categories,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Cookies    map[string]string `json:"cookies,omitempty"`
+	HTML       []string          `json:"html,omitempty"`
+	Meta       map[string]string `json:"meta,omitempty"`
+	Implies    []string          `json:"implies,omitempty"`
+}
+
+// GetTechFingerprints returns the web technology fingerprints read from the 'technologies.json' file.
+func GetTechFingerprints() ([]*TechFingerprint, error) {
+	file, err := resourceFS.Open("technologies.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the 'technologies.json' file: %v", err)
+	}
+	defer file.Close()
+
+	return ParseTechFingerprints(file)
+}
+
+// ParseTechFingerprints decodes the JSON array of web technology fingerprints provided by the reader.
+func ParseTechFingerprints(r io.Reader) ([]*TechFingerprint, error) {
+	var fps []*TechFingerprint
+
+	if err := json.NewDecoder(r).Decode(&fps); err != nil {
+		return nil, fmt.Errorf("failed to decode the technology fingerprints: %v", err)
+	}
+	for _, fp := range fps {
+		if fp.Name == "" {
+			return nil, errors.New("a technology fingerprint did not provide a name")
+		}
+		if len(fp.Headers) == 0 && len(fp.Cookies) == 0 && len(fp.HTML) == 0 && len(fp.Meta) == 0 {
+			return nil, fmt.Errorf("the technology fingerprint for %s did not provide any signatures", fp.Name)
+		}
+	}
+	return fps, nil
+}
+
 func GetDefaultScripts() ([]string, error) {
 	var scripts []string
 
@@ -6,6 +6,8 @@ package resources
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -23,3 +25,64 @@ func TestGetDefaultScripts(t *testing.T) {
 
 	}
 }
+
+func TestGetTakeoverFingerprints(t *testing.T) {
+	fps, err := GetTakeoverFingerprints()
+	if err != nil {
+		t.Fatalf("GetTakeoverFingerprints() error = %v, wantErr <nil>", err)
+	}
+	if len(fps) == 0 {
+		t.Errorf("GetTakeoverFingerprints() returned no fingerprints")
+	}
+}
+
+func TestParseTakeoverFingerprints(t *testing.T) {
+	if _, err := ParseTakeoverFingerprints(strings.NewReader(`[{"service": "Example", "cname": ["example.com"]}]`)); err == nil {
+		t.Errorf("ParseTakeoverFingerprints() accepted a fingerprint without response checks")
+	}
+	if _, err := ParseTakeoverFingerprints(strings.NewReader(`[{"service": "Example", "cname": ["example.com"], "nxdomain": true}]`)); err != nil {
+		t.Errorf("ParseTakeoverFingerprints() error = %v, wantErr <nil>", err)
+	}
+}
+
+func TestGetTechFingerprints(t *testing.T) {
+	fps, err := GetTechFingerprints()
+	if err != nil {
+		t.Fatalf("GetTechFingerprints() error = %v, wantErr <nil>", err)
+	}
+	if len(fps) == 0 {
+		t.Errorf("GetTechFingerprints() returned no fingerprints")
+	}
+}
+
+func TestParseTechFingerprints(t *testing.T) {
+	if _, err := ParseTechFingerprints(strings.NewReader(`[{"name": "Example"}]`)); err == nil {
+		t.Errorf("ParseTechFingerprints() accepted a fingerprint without signatures")
+	}
+	if _, err := ParseTechFingerprints(strings.NewReader(`[{"name": "Example", "headers": {"X-Example": ""}}]`)); err != nil {
+		t.Errorf("ParseTechFingerprints() error = %v, wantErr <nil>", err)
+	}
+}
+
+func TestDefaultScriptNames(t *testing.T) {
+	scripts, err := GetDefaultScripts()
+	if err != nil {
+		t.Fatalf("GetDefaultScripts() error = %v, wantErr <nil>", err)
+	}
+
+	re := regexp.MustCompile(`(?m)^name\s*=\s*"([^"]+)"`)
+	names := make(map[string]int)
+	for _, script := range scripts {
+		m := re.FindStringSubmatch(script)
+		if m == nil {
+			t.Errorf("a default script does not provide a name: %.60q", script)
+			continue
+		}
+		names[strings.ToLower(m[1])]++
+	}
+	for name, count := range names {
+		if count > 1 {
+			t.Errorf("%d default scripts share the name %s", count, name)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package selftest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/caffix/service"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+const (
+	// DefaultCheckDomain is the known-good domain the data sources are checked with.
+	DefaultCheckDomain = "owasp.org"
+	// DefaultCheckTimeout is the time waited for each data source to provide a name.
+	DefaultCheckTimeout = time.Minute
+)
+
+// The time waited for more names after the last one provided by a data source
+const checkQuietPeriod = 2 * time.Second
+
+// The outcomes of the data source checks
+const (
+	CheckPassed      = "pass"
+	CheckNoCreds     = "missing or invalid credentials"
+	CheckFailedStart = "failed to start"
+	CheckUnreachable = "requests failed"
+	CheckNoNames     = "returned no names"
+	CheckSkipped     = "does not support domain queries"
+)
+
+// requestCounter is implemented by the data sources counting the requests sent to their service.
+type requestCounter interface {
+	RequestStats() (int, int)
+}
+
+// CheckResult is the outcome of exercising a single data source with the known-good domain.
+type CheckResult struct {
+	Name   string
+	Type   string
+	Status string
+	// The in-scope names provided by the data source
+	Names int
+	// The requests sent to the service of the data source, and those that failed
+	Requests int
+	Errors   int
+	// The time until the data source provided the first name
+	Latency time.Duration
+	Err     error
+}
+
+// Passed returns true when the data source provided at least one name for the domain.
+func (r *CheckResult) Passed() bool {
+	return r.Status == CheckPassed
+}
+
+// Failed returns true when the data source was checked and did not pass.
+func (r *CheckResult) Failed() bool {
+	return r.Status != CheckPassed && r.Status != CheckSkipped
+}
+
+// CheckSources starts each data source, asks it for the names of the domain and stops it once the
+// names stop arriving or the timeout expires. The domain must be in the scope of the configuration
+// shared by the data sources. The results are returned in the same order as the data sources.
+func CheckSources(ctx context.Context, srcs []service.Service, domain string, timeout time.Duration) []*CheckResult {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, sourcesAtOnce)
+	results := make([]*CheckResult, len(srcs))
+	for i, src := range srcs {
+		results[i] = &CheckResult{Name: src.String(), Type: src.Description()}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(src service.Service, r *CheckResult) {
+			defer func() { <-sem }()
+			defer wg.Done()
+
+			checkSource(ctx, src, r, domain, timeout)
+		}(src, results[i])
+	}
+
+	wg.Wait()
+	return results
+}
+
+func checkSource(ctx context.Context, src service.Service, r *CheckResult, domain string, timeout time.Duration) {
+	if err := src.Start(); err != nil {
+		r.Status, r.Err = CheckFailedStart, err
+		// The check of the API data sources fails without the credentials
+		if r.Type == requests.API {
+			r.Status = CheckNoCreds
+		}
+		return
+	}
+	defer func() { _ = src.Stop() }()
+
+	req := &requests.DNSRequest{Domain: domain}
+	if !src.HandlesReq(req) {
+		r.Status = CheckSkipped
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	select {
+	case <-ctx.Done():
+		r.Status, r.Err = CheckUnreachable, errors.New("the data source did not accept the request")
+		return
+	case src.Input() <- req:
+	}
+
+	// The timeout bounds the wait, while the quiet period ends it once the names stop arriving
+	wait := timeout
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-time.After(wait):
+			break loop
+		case out := <-src.Output():
+			if d, ok := out.(*requests.DNSRequest); ok && d.Name != "" && d.Domain == domain {
+				if r.Names == 0 {
+					r.Latency = time.Since(start)
+				}
+				r.Names++
+				wait = checkQuietPeriod
+			}
+		}
+	}
+
+	if rc, ok := src.(requestCounter); ok {
+		r.Requests, r.Errors = rc.RequestStats()
+	}
+	switch {
+	case r.Names > 0:
+		r.Status = CheckPassed
+	case r.Errors > 0:
+		r.Status = CheckUnreachable
+	default:
+		r.Status = CheckNoNames
+	}
+}
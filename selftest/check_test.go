@@ -0,0 +1,85 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package selftest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/caffix/service"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+type checkService struct {
+	service.BaseService
+	kind     string
+	names    []string
+	startErr error
+	failed   int
+}
+
+func newCheckService(name, kind string, names []string, startErr error, failed int) *checkService {
+	s := &checkService{kind: kind, names: names, startErr: startErr, failed: failed}
+
+	s.BaseService = *service.NewBaseService(s, name)
+	return s
+}
+
+func (s *checkService) Description() string { return s.kind }
+
+func (s *checkService) OnStart() error {
+	if s.startErr != nil {
+		return s.startErr
+	}
+
+	go func() {
+		select {
+		case <-s.Done():
+		case req := <-s.Input():
+			domain := req.(*requests.DNSRequest).Domain
+
+			for _, name := range s.names {
+				s.Output() <- &requests.DNSRequest{Name: name + "." + domain, Domain: domain}
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *checkService) HandlesReq(req interface{}) bool {
+	return s.kind != requests.RIR
+}
+
+func (s *checkService) RequestStats() (int, int) {
+	return 1, s.failed
+}
+
+func TestCheckSources(t *testing.T) {
+	srcs := []service.Service{
+		newCheckService("Working", requests.API, []string{"www", "mail"}, nil, 0),
+		newCheckService("NoKey", requests.API, nil, errors.New("no credentials"), 0),
+		newCheckService("Broken", requests.SCRAPE, nil, errors.New("bad script"), 0),
+		newCheckService("Refused", requests.SCRAPE, nil, nil, 1),
+		newCheckService("Parser", requests.SCRAPE, nil, nil, 0),
+		newCheckService("Registry", requests.RIR, nil, nil, 0),
+	}
+
+	results := CheckSources(context.Background(), srcs, DefaultCheckDomain, time.Second)
+
+	expected := []string{CheckPassed, CheckNoCreds, CheckFailedStart, CheckUnreachable, CheckNoNames, CheckSkipped}
+	for i, r := range results {
+		if r.Name != srcs[i].String() || r.Status != expected[i] {
+			t.Errorf("the %s data source was checked as %q, expected %q", srcs[i].String(), r.Status, expected[i])
+		}
+	}
+	if r := results[0]; !r.Passed() || r.Names != 2 || r.Latency <= 0 {
+		t.Errorf("the working data source was checked as %+v", r)
+	}
+	if results[5].Failed() {
+		t.Error("the skipped data source was reported as failed")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package selftest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/caffix/netmap"
+)
+
+const (
+	// DefaultWrites is the number of names written to the graph database by each measurement.
+	DefaultWrites = 1000
+	// The workers writing the names during the concurrent measurement
+	graphWorkers = 4
+)
+
+// GraphResult is the measurement of the local graph database.
+type GraphResult struct {
+	Writes  int
+	Workers int
+	// Sequential is the rate of names written per second by a single worker
+	Sequential float64
+	// Concurrent is the rate of names written per second by the workers
+	Concurrent float64
+}
+
+// Graph writes names to a temporary local graph database created within the directory, which
+// is removed before returning, and measures the rate of the writes.
+func Graph(ctx context.Context, dir string, writes int) (*GraphResult, error) {
+	if writes <= 0 {
+		writes = DefaultWrites
+	}
+
+	tmp, err := os.MkdirTemp(dir, "selftest-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the temporary graph directory: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	cayley := netmap.NewCayleyGraph("local", tmp, "")
+	if cayley == nil {
+		return nil, errors.New("failed to create the temporary graph database")
+	}
+	g := netmap.NewGraph(cayley)
+	if g == nil {
+		return nil, errors.New("failed to create the temporary graph database")
+	}
+	defer g.Close()
+
+	result := &GraphResult{Writes: writes, Workers: graphWorkers}
+	if result.Sequential, err = writeNames(ctx, g, "seq", writes, 1); err != nil {
+		return nil, err
+	}
+	if result.Concurrent, err = writeNames(ctx, g, "con", writes, graphWorkers); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func writeNames(ctx context.Context, g *netmap.Graph, label string, writes, workers int) (float64, error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var werr error
+	names := make(chan string, writes)
+	for i := 0; i < writes; i++ {
+		names <- fmt.Sprintf("host%d.%s.selftest.owasp.org", i, label)
+	}
+	close(names)
+
+	start := time.Now()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for name := range names {
+				if _, err := g.UpsertFQDN(ctx, name, "selftest", "selftest"); err != nil {
+					mu.Lock()
+					werr = fmt.Errorf("failed to write to the temporary graph database: %v", err)
+					mu.Unlock()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if werr != nil {
+		return 0, werr
+	}
+	return float64(writes) / time.Since(start).Seconds(), nil
+}
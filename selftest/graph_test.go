@@ -0,0 +1,26 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package selftest
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	dir := t.TempDir()
+
+	result, err := Graph(context.Background(), dir, 50)
+	if err != nil {
+		t.Fatalf("Graph() error = %v", err)
+	}
+	if result.Writes != 50 || result.Workers != graphWorkers || result.Sequential <= 0 || result.Concurrent <= 0 {
+		t.Errorf("Graph() = %+v", result)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Graph() did not remove the temporary graph database")
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package selftest measures the resolvers, data sources and graph database available to the
+// enumerations, and suggests the configuration values that suit the host and the network.
+package selftest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/resolve"
+)
+
+const (
+	// DefaultQueries is the number of queries sent to each resolver.
+	DefaultQueries = 50
+	// The queries outstanding for each resolver during the measurement
+	queriesPerResolver = 10
+	// The resolvers measured at the same time
+	resolversAtOnce = 10
+	queryTimeout    = 2 * time.Second
+)
+
+// The names queried during the measurement, which every reliable resolver can answer
+var testNames = []string{
+	"www.owasp.org",
+	"www.google.com",
+	"www.wikipedia.org",
+	"www.github.com",
+	"www.cloudflare.com",
+}
+
+// ResolverResult is the measurement of a single DNS resolver.
+type ResolverResult struct {
+	Address  string
+	Trusted  bool
+	Sent     int
+	Answered int
+	// Latency is the average round-trip time of the answered queries
+	Latency time.Duration
+	// QPS is the rate of answered queries achieved by the resolver
+	QPS float64
+	// Hijacked is true when the resolver answered a name that does not exist
+	Hijacked bool
+}
+
+// Reliability returns the fraction of the queries answered by the resolver.
+func (r *ResolverResult) Reliability() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Answered) / float64(r.Sent)
+}
+
+// Resolvers sends the number of queries to each of the resolvers and returns the results in
+// the same order as the addresses.
+func Resolvers(ctx context.Context, addrs []string, trusted bool, queries int) []*ResolverResult {
+	if queries <= 0 {
+		queries = DefaultQueries
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, resolversAtOnce)
+	results := make([]*ResolverResult, len(addrs))
+	for i, addr := range addrs {
+		results[i] = &ResolverResult{Address: addr, Trusted: trusted}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *ResolverResult) {
+			defer func() { <-sem }()
+			defer wg.Done()
+
+			measureResolver(ctx, r, queries)
+		}(results[i])
+	}
+
+	wg.Wait()
+	return results
+}
+
+func measureResolver(ctx context.Context, r *ResolverResult, queries int) {
+	server := r.Address
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var total time.Duration
+	names := make(chan string, queries)
+	for i := 0; i < queries; i++ {
+		names <- testNames[i%len(testNames)]
+	}
+	close(names)
+
+	start := time.Now()
+	for i := 0; i < queriesPerResolver && i < queries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			client := &dns.Client{Net: "udp", Timeout: queryTimeout}
+			for name := range names {
+				rtt, ok := exchange(ctx, client, server, name)
+
+				mu.Lock()
+				r.Sent++
+				if ok {
+					r.Answered++
+					total += rtt
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if r.Answered > 0 {
+		r.Latency = total / time.Duration(r.Answered)
+		r.QPS = float64(r.Answered) / time.Since(start).Seconds()
+	}
+	r.Hijacked = hijacked(ctx, server)
+}
+
+func exchange(ctx context.Context, client *dns.Client, server, name string) (time.Duration, bool) {
+	resp, rtt, err := client.ExchangeContext(ctx, resolve.QueryMsg(name, dns.TypeA), server)
+	if err != nil || resp == nil {
+		return 0, false
+	}
+	return rtt, resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0
+}
+
+// hijacked checks whether the resolver returns addresses for a name that cannot exist.
+func hijacked(ctx context.Context, server string) bool {
+	name := fmt.Sprintf("amass-selftest-%d.owasp.org", rand.Int63())
+	client := &dns.Client{Net: "udp", Timeout: queryTimeout}
+
+	resp, _, err := client.ExchangeContext(ctx, resolve.QueryMsg(name, dns.TypeA), server)
+	if err != nil || resp == nil {
+		return false
+	}
+	return resp.Rcode == dns.RcodeSuccess && len(resolve.ExtractAnswers(resp)) > 0
+}
@@ -0,0 +1,74 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package selftest
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/owasp-amass/resolve"
+)
+
+func testResolver(t *testing.T, hijack bool) string {
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the queries: %v", err)
+	}
+
+	known := make(map[string]bool)
+	for _, name := range testNames {
+		known[name] = true
+	}
+
+	srv := &dns.Server{
+		PacketConn: pc,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			m := new(dns.Msg)
+			m.SetReply(req)
+
+			name := req.Question[0].Name
+			if !hijack && !known[strings.ToLower(resolve.RemoveLastDot(name))] {
+				m.Rcode = dns.RcodeNameError
+			} else if rr, err := dns.NewRR(name + " 300 IN A 192.168.1.1"); err == nil {
+				m.Answer = append(m.Answer, rr)
+			}
+			_ = w.WriteMsg(m)
+		}),
+	}
+	go func() { _ = srv.ActivateAndServe() }()
+	t.Cleanup(func() { _ = srv.Shutdown() })
+
+	return pc.LocalAddr().String()
+}
+
+func TestResolvers(t *testing.T) {
+	good := testResolver(t, false)
+	hijacker := testResolver(t, true)
+	// Nothing is listening on the port after it is closed
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for the queries: %v", err)
+	}
+	silent := pc.LocalAddr().String()
+	pc.Close()
+
+	results := Resolvers(context.Background(), []string{good, hijacker, silent}, true, 20)
+	if len(results) != 3 {
+		t.Fatalf("Resolvers() returned %d results", len(results))
+	}
+
+	if r := results[0]; r.Address != good || !r.Trusted || r.Sent != 20 || r.Reliability() != 1 || r.Hijacked || r.QPS <= 0 {
+		t.Errorf("the reliable resolver was measured as %+v", r)
+	}
+	if r := results[1]; r.Reliability() != 1 || !r.Hijacked {
+		t.Errorf("the hijacking resolver was measured as %+v", r)
+	}
+	if r := results[2]; r.Sent != 20 || r.Answered != 0 || r.Reliability() != 0 {
+		t.Errorf("the silent resolver was measured as %+v", r)
+	}
+}
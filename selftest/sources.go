@@ -0,0 +1,110 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package selftest
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	amasshttp "github.com/owasp-amass/amass/v3/net/http"
+)
+
+// DefaultSourceTimeout is the time waited for each data source to respond.
+const DefaultSourceTimeout = 10 * time.Second
+
+// The data sources contacted at the same time
+const sourcesAtOnce = 10
+
+var (
+	scriptNameRE = regexp.MustCompile(`(?m)^name\s*=\s*"([^"]+)"`)
+	scriptURLRE  = regexp.MustCompile(`(https?)://([a-zA-Z0-9][a-zA-Z0-9.-]*\.[a-zA-Z]{2,})`)
+)
+
+// SourceTarget is the web server contacted on behalf of a data source.
+type SourceTarget struct {
+	Name string
+	URL  string
+}
+
+// SourceResult is the measurement of a single data source.
+type SourceResult struct {
+	Name       string
+	URL        string
+	StatusCode int
+	Latency    time.Duration
+	Err        error
+}
+
+// Reachable returns true when the web server of the data source sent a response.
+func (r *SourceResult) Reachable() bool {
+	return r.Err == nil
+}
+
+// ScriptTargets returns the first web server referenced by each of the data source scripts.
+// Scripts that do not reference a web server, such as the brute forcing script, are skipped.
+func ScriptTargets(scripts []string) []*SourceTarget {
+	var targets []*SourceTarget
+
+	for _, script := range scripts {
+		name := scriptNameRE.FindStringSubmatch(script)
+		u := scriptURLRE.FindStringSubmatch(script)
+		if name == nil || u == nil {
+			continue
+		}
+
+		targets = append(targets, &SourceTarget{
+			Name: name[1],
+			URL:  u[1] + "://" + u[2] + "/",
+		})
+	}
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+	return targets
+}
+
+// Sources requests the URL of each target and returns the results in the same order.
+func Sources(ctx context.Context, targets []*SourceTarget, timeout time.Duration) []*SourceResult {
+	if timeout <= 0 {
+		timeout = DefaultSourceTimeout
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, sourcesAtOnce)
+	results := make([]*SourceResult, len(targets))
+	for i, t := range targets {
+		results[i] = &SourceResult{Name: t.Name, URL: t.URL}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *SourceResult) {
+			defer func() { <-sem }()
+			defer wg.Done()
+
+			measureSource(ctx, r, timeout)
+		}(results[i])
+	}
+
+	wg.Wait()
+	return results
+}
+
+func measureSource(ctx context.Context, r *SourceResult, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	// Any response shows the data source can be reached, since the API may require credentials
+	resp, err := amasshttp.RequestWebPage(ctx, &amasshttp.Request{URL: r.URL})
+	if err != nil {
+		r.Err = err
+		return
+	}
+
+	r.Latency = time.Since(start)
+	r.StatusCode = resp.StatusCode
+}
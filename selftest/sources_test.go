@@ -0,0 +1,56 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package selftest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScriptTargets(t *testing.T) {
+	scripts := []string{
+		"name = \"ZoomEye\"\ntype = \"api\"\nlocal u = \"https://api.zoomeye.org/host/search?query=\" .. domain\n" +
+			"local v = \"https://api.zoomeye.org/user/login\"",
+		"name = \"Brute Forcing\"\ntype = \"brute\"",
+		"name = \"Crtsh\"\nlocal u = \"http://crt.sh/?q=\" .. domain",
+		"local u = \"https://example.com/\"",
+	}
+
+	targets := ScriptTargets(scripts)
+	if len(targets) != 2 {
+		t.Fatalf("ScriptTargets() returned %d targets", len(targets))
+	}
+	if targets[0].Name != "Crtsh" || targets[0].URL != "http://crt.sh/" {
+		t.Errorf("the first target was %+v", targets[0])
+	}
+	if targets[1].Name != "ZoomEye" || targets[1].URL != "https://api.zoomeye.org/" {
+		t.Errorf("the second target was %+v", targets[1])
+	}
+}
+
+func TestSources(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	down.Close()
+
+	results := Sources(context.Background(), []*SourceTarget{
+		{Name: "Available", URL: srv.URL},
+		{Name: "Unavailable", URL: down.URL},
+	}, 5*time.Second)
+
+	if r := results[0]; !r.Reachable() || r.StatusCode != http.StatusUnauthorized || r.Latency <= 0 {
+		t.Errorf("the available source was measured as %+v", r)
+	}
+	if r := results[1]; r.Reachable() || r.Name != "Unavailable" {
+		t.Errorf("the unavailable source was measured as %+v", r)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package selftest
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// Resolvers answering fewer of the queries are suggested for removal
+	minReliability = 0.85
+	// The fraction of the measured rate suggested for the queries per second, leaving headroom
+	// for the heavier load of the enumerations
+	qpsHeadroom = 0.8
+	// The concurrent graph writes must be this much faster to be suggested
+	minGraphSpeedup = 1.2
+)
+
+// Suggestions are the configuration values derived from the measurements. A zero value means
+// the measurements did not support a change from the default.
+type Suggestions struct {
+	ResolversQPS      int
+	TrustedQPS        int
+	ResolutionWorkers int
+	GraphWriteWorkers int
+	// DropResolvers are the resolvers that were unreliable or hijacked nonexistent names
+	DropResolvers []string
+	// DisableSources are the data sources that could not be reached
+	DisableSources []string
+}
+
+// Suggest returns the configuration values supported by the measurements. The graph result is
+// nil when the graph database was not measured.
+func Suggest(resolvers []*ResolverResult, sources []*SourceResult, graph *GraphResult) *Suggestions {
+	s := new(Suggestions)
+
+	var trusted, untrusted []*ResolverResult
+	for _, r := range resolvers {
+		if r.Hijacked || r.Reliability() < minReliability {
+			s.DropResolvers = append(s.DropResolvers, r.Address)
+			continue
+		}
+
+		if r.Trusted {
+			trusted = append(trusted, r)
+		} else {
+			untrusted = append(untrusted, r)
+		}
+	}
+	s.TrustedQPS = suggestedQPS(trusted)
+	s.ResolversQPS = suggestedQPS(untrusted)
+	// The queries in flight needed to sustain the rate of the larger pool, doubled for the retries
+	s.ResolutionWorkers = int(math.Max(inFlight(trusted, s.TrustedQPS), inFlight(untrusted, s.ResolversQPS)))
+
+	for _, src := range sources {
+		if !src.Reachable() {
+			s.DisableSources = append(s.DisableSources, src.Name)
+		}
+	}
+
+	if graph != nil {
+		s.GraphWriteWorkers = 1
+		if graph.Concurrent >= graph.Sequential*minGraphSpeedup {
+			s.GraphWriteWorkers = graph.Workers
+		}
+	}
+	return s
+}
+
+// suggestedQPS returns the median rate achieved by the resolvers, less the headroom.
+func suggestedQPS(results []*ResolverResult) int {
+	if len(results) == 0 {
+		return 0
+	}
+
+	rates := make([]float64, 0, len(results))
+	for _, r := range results {
+		rates = append(rates, r.QPS)
+	}
+	sort.Float64s(rates)
+
+	if qps := int(rates[len(rates)/2] * qpsHeadroom); qps > 0 {
+		return qps
+	}
+	return 1
+}
+
+func inFlight(results []*ResolverResult, qps int) float64 {
+	if len(results) == 0 {
+		return 0
+	}
+
+	var latency float64
+	for _, r := range results {
+		latency += r.Latency.Seconds()
+	}
+	latency /= float64(len(results))
+
+	return math.Ceil(float64(len(results)*qps) * latency * 2)
+}
@@ -0,0 +1,54 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package selftest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSuggest(t *testing.T) {
+	resolvers := []*ResolverResult{
+		{Address: "8.8.8.8", Trusted: true, Sent: 50, Answered: 50, Latency: 20 * time.Millisecond, QPS: 100},
+		{Address: "1.1.1.1", Trusted: true, Sent: 50, Answered: 48, Latency: 40 * time.Millisecond, QPS: 50},
+		{Address: "9.9.9.9", Trusted: true, Sent: 50, Answered: 50, Latency: 30 * time.Millisecond, QPS: 200},
+		{Address: "192.0.2.1", Sent: 50, Answered: 30, Latency: 100 * time.Millisecond, QPS: 30},
+		{Address: "192.0.2.2", Sent: 50, Answered: 50, Hijacked: true, QPS: 30},
+		{Address: "192.0.2.3", Sent: 50, Answered: 50, Latency: 500 * time.Millisecond, QPS: 1},
+	}
+	sources := []*SourceResult{
+		{Name: "crtsh", StatusCode: 200},
+		{Name: "Down", Err: errors.New("connection refused")},
+	}
+
+	s := Suggest(resolvers, sources, &GraphResult{Workers: 4, Sequential: 1000, Concurrent: 1100})
+	// The median rate of the trusted resolvers is 100 queries per second
+	if s.TrustedQPS != 80 {
+		t.Errorf("TrustedQPS = %d, expected 80", s.TrustedQPS)
+	}
+	if s.ResolversQPS != 1 {
+		t.Errorf("ResolversQPS = %d, expected 1", s.ResolversQPS)
+	}
+	// Three trusted resolvers at 80 queries per second with the average latency of 30ms
+	if s.ResolutionWorkers != 15 {
+		t.Errorf("ResolutionWorkers = %d, expected 15", s.ResolutionWorkers)
+	}
+	if s.GraphWriteWorkers != 1 {
+		t.Errorf("GraphWriteWorkers = %d, expected 1", s.GraphWriteWorkers)
+	}
+	if !reflect.DeepEqual(s.DropResolvers, []string{"192.0.2.1", "192.0.2.2"}) {
+		t.Errorf("DropResolvers = %v", s.DropResolvers)
+	}
+	if !reflect.DeepEqual(s.DisableSources, []string{"Down"}) {
+		t.Errorf("DisableSources = %v", s.DisableSources)
+	}
+
+	s = Suggest(nil, nil, &GraphResult{Workers: 4, Sequential: 1000, Concurrent: 3000})
+	if s.GraphWriteWorkers != 4 || s.TrustedQPS != 0 || s.ResolutionWorkers != 0 {
+		t.Errorf("Suggest() = %+v", s)
+	}
+}
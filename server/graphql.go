@@ -0,0 +1,796 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/caffix/netmap"
+	"github.com/graphql-go/graphql"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+// The maximum size of the GraphQL request bodies accepted by the handler
+const maxQuerySize int64 = 1 << 20
+
+// The nodes of the graph resolved by the GraphQL object types
+type (
+	eventNode    string
+	fqdnNode     string
+	addrNode     string
+	netblockNode string
+	asNode       string
+)
+
+type resolver struct {
+	graph *netmap.Graph
+}
+
+// NewGraphQLSchema returns the read-only GraphQL schema over the enumerations, names,
+// addresses, netblocks and autonomous systems in the graph database.
+func NewGraphQLSchema(g *netmap.Graph) (graphql.Schema, error) {
+	r := &resolver{graph: g}
+
+	findingType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Finding",
+		Description: "An issue detected for the asset during an enumeration",
+		Fields: graphql.Fields{
+			"type":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.Field{Type: graphql.String},
+			"evidence":    &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	httpType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "HTTPProbe",
+		Description: "The result of probing the web server of a name",
+		Fields: graphql.Fields{
+			"url":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"status":       &graphql.Field{Type: graphql.Int},
+			"title":        &graphql.Field{Type: graphql.String},
+			"server":       &graphql.Field{Type: graphql.String},
+			"technologies": &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"waf":          &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	var nameType, addrType, netblockType, asType *graphql.Object
+	nameList := func() graphql.Output { return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(nameType))) }
+	addrList := func() graphql.Output { return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(addrType))) }
+	stringList := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))
+	sourcesArgs := graphql.FieldConfigArgument{
+		"enumeration": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Only return the sources used during the enumeration",
+		},
+	}
+
+	nameType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Name",
+		Description: "A DNS name discovered during the enumerations",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: nodeID},
+				"domain": &graphql.Field{
+					Type:        graphql.String,
+					Description: "The root domain name of the name",
+					Resolve:     r.nameDomain,
+				},
+				"sources": &graphql.Field{
+					Type:    stringList,
+					Args:    sourcesArgs,
+					Resolve: r.sources,
+				},
+				"cname": &graphql.Field{
+					Type:        nameType,
+					Description: "The target of the CNAME record of the name",
+					Resolve:     r.cname,
+				},
+				"cnameChain": &graphql.Field{
+					Type:        nameList(),
+					Description: "The targets of the CNAME records followed from the name, in order",
+					Resolve:     r.cnameChain,
+				},
+				"aliases": &graphql.Field{
+					Type:        nameList(),
+					Description: "The names with CNAME records targeting the name",
+					Resolve:     r.inNames("cname_record"),
+				},
+				"addresses": &graphql.Field{
+					Type:        addrList(),
+					Description: "The addresses in the A and AAAA records of the name",
+					Resolve:     r.outAddrs("a_record", "aaaa_record"),
+				},
+				"nameservers": &graphql.Field{Type: nameList(), Resolve: r.outNames("ns_record")},
+				"mailservers": &graphql.Field{Type: nameList(), Resolve: r.outNames("mx_record")},
+				"services":    &graphql.Field{Type: nameList(), Resolve: r.outNames("srv_record")},
+				"findings": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(findingType))),
+					Resolve: r.findings,
+				},
+				"http": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(httpType))),
+					Resolve: r.httpProbes,
+				},
+			}
+		}),
+	})
+
+	addrType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Address",
+		Description: "An IPv4 or IPv6 address discovered during the enumerations",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"address": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: nodeID},
+				"sources": &graphql.Field{
+					Type:    stringList,
+					Args:    sourcesArgs,
+					Resolve: r.sources,
+				},
+				"names": &graphql.Field{
+					Type:        nameList(),
+					Description: "The names with A and AAAA records containing the address",
+					Resolve:     r.inNames("a_record", "aaaa_record"),
+				},
+				"netblock": &graphql.Field{
+					Type:        netblockType,
+					Description: "The netblock announced by an autonomous system containing the address",
+					Resolve:     r.addrNetblock,
+				},
+				"findings": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(findingType))),
+					Resolve: r.findings,
+				},
+			}
+		}),
+	})
+
+	netblockType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Netblock",
+		Description: "A netblock announced by an autonomous system",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"cidr": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: nodeID},
+				"addresses": &graphql.Field{
+					Type:        addrList(),
+					Description: "The discovered addresses contained by the netblock",
+					Resolve:     r.outAddrs("contains"),
+				},
+				"autonomousSystem": &graphql.Field{Type: asType, Resolve: r.netblockAS},
+			}
+		}),
+	})
+
+	asType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "AutonomousSystem",
+		Description: "An autonomous system announcing the netblocks of the discovered addresses",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"asn":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: r.asn},
+				"description": &graphql.Field{Type: graphql.String, Resolve: r.asDescription},
+				"netblocks": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(netblockType))),
+					Resolve: r.asNetblocks,
+				},
+			}
+		}),
+	})
+
+	eventType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Enumeration",
+		Description: "An enumeration stored in the graph database",
+		Fields: graphql.Fields{
+			"uuid":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: nodeID},
+			"start":     &graphql.Field{Type: graphql.DateTime, Resolve: r.eventTime(true)},
+			"finish":    &graphql.Field{Type: graphql.DateTime, Resolve: r.eventTime(false)},
+			"workspace": &graphql.Field{Type: graphql.String, Resolve: r.eventWorkspace},
+			"domains":   &graphql.Field{Type: stringList, Resolve: r.eventDomains},
+			"names": &graphql.Field{
+				Type:        nameList(),
+				Description: "The names discovered during the enumeration",
+				Resolve:     r.eventNames,
+			},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"enumerations": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(eventType))),
+				Args: graphql.FieldConfigArgument{
+					"domain":    &graphql.ArgumentConfig{Type: graphql.String},
+					"workspace": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: r.enumerations,
+			},
+			"enumeration": &graphql.Field{
+				Type: eventType,
+				Args: graphql.FieldConfigArgument{
+					"uuid": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.node("uuid", netmap.TypeEvent, func(id string) interface{} { return eventNode(id) }),
+			},
+			"name": &graphql.Field{
+				Type: nameType,
+				Args: graphql.FieldConfigArgument{
+					"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.node("name", netmap.TypeFQDN, func(id string) interface{} { return fqdnNode(id) }),
+			},
+			"names": &graphql.Field{
+				Type: nameList(),
+				Args: graphql.FieldConfigArgument{
+					"domain":      &graphql.ArgumentConfig{Type: graphql.String},
+					"source":      &graphql.ArgumentConfig{Type: graphql.String},
+					"enumeration": &graphql.ArgumentConfig{Type: graphql.String},
+					"workspace":   &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: r.names,
+			},
+			"address": &graphql.Field{
+				Type: addrType,
+				Args: graphql.FieldConfigArgument{
+					"address": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.node("address", netmap.TypeAddr, func(id string) interface{} { return addrNode(id) }),
+			},
+			"addresses": &graphql.Field{
+				Type: addrList(),
+				Args: graphql.FieldConfigArgument{
+					"netblock": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.addresses,
+			},
+			"netblock": &graphql.Field{
+				Type: netblockType,
+				Args: graphql.FieldConfigArgument{
+					"cidr": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.node("cidr", netmap.TypeNetblock, func(id string) interface{} { return netblockNode(id) }),
+			},
+			"autonomousSystem": &graphql.Field{
+				Type: asType,
+				Args: graphql.FieldConfigArgument{
+					"asn": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: r.autonomousSystem,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// GraphQLRequest is the body of the POST requests sent to the GraphQL endpoint.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// NewGraphQLHandler returns the HTTP handler executing the GraphQL queries, provided by the
+// query parameter of GET requests or the JSON body of POST requests, against the graph database.
+func NewGraphQLHandler(g *netmap.Graph) (http.Handler, error) {
+	schema, err := NewGraphQLSchema(g)
+	if err != nil {
+		return nil, err
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var gr GraphQLRequest
+
+		switch req.Method {
+		case http.MethodGet:
+			gr.Query = req.URL.Query().Get("query")
+			gr.OperationName = req.URL.Query().Get("operationName")
+			if v := req.URL.Query().Get("variables"); v != "" {
+				if err := json.Unmarshal([]byte(v), &gr.Variables); err != nil {
+					writeError(w, http.StatusBadRequest, "the variables parameter is not a valid JSON object")
+					return
+				}
+			}
+		case http.MethodPost:
+			body, err := io.ReadAll(io.LimitReader(req.Body, maxQuerySize))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "failed to read the request body")
+				return
+			}
+			if strings.HasPrefix(req.Header.Get("Content-Type"), "application/graphql") {
+				gr.Query = string(body)
+			} else if err := json.Unmarshal(body, &gr); err != nil {
+				writeError(w, http.StatusBadRequest, "the request body is not a valid GraphQL request")
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeError(w, http.StatusMethodNotAllowed, "the GraphQL endpoint only accepts GET and POST requests")
+			return
+		}
+
+		if strings.TrimSpace(gr.Query) == "" {
+			writeError(w, http.StatusBadRequest, "the request does not provide a query")
+			return
+		}
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  gr.Query,
+			VariableValues: gr.Variables,
+			OperationName:  gr.OperationName,
+			Context:        req.Context(),
+		})
+		writeJSON(w, http.StatusOK, result)
+	}), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]interface{}{
+		"errors": []map[string]string{{"message": msg}},
+	})
+}
+
+func nodeID(p graphql.ResolveParams) (interface{}, error) {
+	return sourceID(p.Source), nil
+}
+
+func sourceID(src interface{}) string {
+	switch v := src.(type) {
+	case eventNode:
+		return string(v)
+	case fqdnNode:
+		return string(v)
+	case addrNode:
+		return string(v)
+	case netblockNode:
+		return string(v)
+	case asNode:
+		return string(v)
+	}
+	return ""
+}
+
+// node returns the resolver of the node identified by the argument, or null when it is not in the graph.
+func (r *resolver) node(arg, ntype string, wrap func(string) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		id, _ := p.Args[arg].(string)
+		if ntype == netmap.TypeFQDN {
+			id = strings.ToLower(strings.Trim(id, "."))
+		}
+		if _, err := r.graph.ReadNode(p.Context, id, ntype); err != nil {
+			return nil, nil
+		}
+		return wrap(id), nil
+	}
+}
+
+func (r *resolver) autonomousSystem(p graphql.ResolveParams) (interface{}, error) {
+	asn, _ := p.Args["asn"].(int)
+
+	id := strconv.Itoa(asn)
+	if _, err := r.graph.ReadNode(p.Context, id, netmap.TypeAS); err != nil {
+		return nil, nil
+	}
+	return asNode(id), nil
+}
+
+func (r *resolver) enumerations(p graphql.ResolveParams) (interface{}, error) {
+	var events []string
+	if domain, _ := p.Args["domain"].(string); domain != "" {
+		events = r.graph.EventsInScope(p.Context, strings.ToLower(domain))
+	} else {
+		events = r.graph.EventList(p.Context)
+	}
+	if ws, ok := p.Args["workspace"].(string); ok {
+		events = systems.WorkspaceEvents(p.Context, r.graph, ws, events)
+	}
+
+	type entry struct {
+		uuid  string
+		start int64
+	}
+	var entries []entry
+	for _, uuid := range events {
+		start, _ := r.graph.EventDateRange(p.Context, uuid)
+		entries = append(entries, entry{uuid: uuid, start: start.UnixNano()})
+	}
+	// The most recent enumerations are listed first
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].start != entries[j].start {
+			return entries[i].start > entries[j].start
+		}
+		return entries[i].uuid < entries[j].uuid
+	})
+
+	results := []interface{}{}
+	for _, e := range entries {
+		results = append(results, eventNode(e.uuid))
+	}
+	return results, nil
+}
+
+func (r *resolver) eventTime(start bool) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		s, f := r.graph.EventDateRange(p.Context, sourceID(p.Source))
+		t := f
+		if start {
+			t = s
+		}
+		if t.IsZero() {
+			return nil, nil
+		}
+		return t, nil
+	}
+}
+
+func (r *resolver) eventWorkspace(p graphql.ResolveParams) (interface{}, error) {
+	if ws := systems.EventWorkspace(p.Context, r.graph, sourceID(p.Source)); ws != "" {
+		return ws, nil
+	}
+	return nil, nil
+}
+
+func (r *resolver) eventDomains(p graphql.ResolveParams) (interface{}, error) {
+	domains := r.graph.EventDomains(p.Context, sourceID(p.Source))
+
+	sort.Strings(domains)
+	return append([]string{}, domains...), nil
+}
+
+func (r *resolver) eventNames(p graphql.ResolveParams) (interface{}, error) {
+	names := r.graph.EventFQDNs(p.Context, sourceID(p.Source))
+
+	sort.Strings(names)
+	results := []interface{}{}
+	for _, name := range names {
+		results = append(results, fqdnNode(name))
+	}
+	return results, nil
+}
+
+func (r *resolver) names(p graphql.ResolveParams) (interface{}, error) {
+	var events []string
+	if uuid, _ := p.Args["enumeration"].(string); uuid != "" {
+		events = append(events, uuid)
+	}
+	if ws, ok := p.Args["workspace"].(string); ok {
+		if len(events) == 0 {
+			events = r.graph.EventList(p.Context)
+		}
+		events = systems.WorkspaceEvents(p.Context, r.graph, ws, events)
+		if len(events) == 0 {
+			return []interface{}{}, nil
+		}
+	}
+
+	domain, _ := p.Args["domain"].(string)
+	domain = strings.ToLower(strings.Trim(domain, "."))
+	source, _ := p.Args["source"].(string)
+	eventset := r.eventSet(p.Context, events)
+
+	// AllNodesOfType returns an error when no nodes are found
+	nodes, _ := r.graph.AllNodesOfType(p.Context, netmap.TypeFQDN, events...)
+	var names []string
+	for _, node := range nodes {
+		name := r.graph.NodeToID(node)
+		if domain != "" && name != domain && !strings.HasSuffix(name, "."+domain) {
+			continue
+		}
+		if r.graph.IsTLDNode(p.Context, name) {
+			continue
+		}
+		if source != "" && !containsFold(r.nodeSources(p.Context, name, eventset), source) {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	results := []interface{}{}
+	for _, name := range names {
+		results = append(results, fqdnNode(name))
+	}
+	return results, nil
+}
+
+func (r *resolver) addresses(p graphql.ResolveParams) (interface{}, error) {
+	cidr, _ := p.Args["netblock"].(string)
+
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, errors.New("the netblock argument is not a valid CIDR")
+	}
+
+	var addrs []net.IP
+	// AllNodesOfType returns an error when no nodes are found
+	nodes, _ := r.graph.AllNodesOfType(p.Context, netmap.TypeAddr)
+	for _, node := range nodes {
+		if ip := net.ParseIP(r.graph.NodeToID(node)); ip != nil && ipnet.Contains(ip) {
+			addrs = append(addrs, ip)
+		}
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return compareIPs(addrs[i], addrs[j]) < 0
+	})
+	results := []interface{}{}
+	for _, ip := range addrs {
+		results = append(results, addrNode(ip.String()))
+	}
+	return results, nil
+}
+
+func (r *resolver) sources(p graphql.ResolveParams) (interface{}, error) {
+	var events []string
+	if uuid, _ := p.Args["enumeration"].(string); uuid != "" {
+		events = append(events, uuid)
+	}
+
+	sources := r.nodeSources(p.Context, sourceID(p.Source), r.eventSet(p.Context, events))
+	sort.Strings(sources)
+	return sources, nil
+}
+
+// eventSet returns the set of enumerations, or all the enumerations when none are provided.
+func (r *resolver) eventSet(ctx context.Context, events []string) map[string]struct{} {
+	if len(events) == 0 {
+		events = r.graph.EventList(ctx)
+	}
+
+	set := make(map[string]struct{}, len(events))
+	for _, e := range events {
+		set[e] = struct{}{}
+	}
+	return set
+}
+
+// nodeSources returns the data sources that discovered the node during the enumerations in the set.
+func (r *resolver) nodeSources(ctx context.Context, id string, events map[string]struct{}) []string {
+	sources := []string{}
+
+	edges, err := r.graph.ReadInEdges(ctx, netmap.Node(id))
+	if err != nil {
+		return sources
+	}
+
+	seen := make(map[string]struct{})
+	for _, edge := range edges {
+		if _, found := events[r.graph.NodeToID(edge.From)]; !found || edge.Predicate == "domain" {
+			continue
+		}
+		if _, found := seen[edge.Predicate]; !found {
+			seen[edge.Predicate] = struct{}{}
+			sources = append(sources, edge.Predicate)
+		}
+	}
+	return sources
+}
+
+func (r *resolver) nameDomain(p graphql.ResolveParams) (interface{}, error) {
+	name := sourceID(p.Source)
+
+	if edges, err := r.graph.ReadOutEdges(p.Context, netmap.Node(name), "root"); err == nil && len(edges) > 0 {
+		return r.graph.NodeToID(edges[0].To), nil
+	}
+	if r.graph.IsRootDomainNode(p.Context, name) {
+		return name, nil
+	}
+	return nil, nil
+}
+
+func (r *resolver) cname(p graphql.ResolveParams) (interface{}, error) {
+	targets := r.outNodes(p.Context, sourceID(p.Source), "cname_record")
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	return fqdnNode(targets[0]), nil
+}
+
+func (r *resolver) cnameChain(p graphql.ResolveParams) (interface{}, error) {
+	name := sourceID(p.Source)
+	seen := map[string]struct{}{name: {}}
+
+	results := []interface{}{}
+	// Follow the CNAME records until a name without one is reached or a loop is detected
+	for {
+		targets := r.outNodes(p.Context, name, "cname_record")
+		if len(targets) == 0 {
+			break
+		}
+
+		name = targets[0]
+		if _, found := seen[name]; found {
+			break
+		}
+		seen[name] = struct{}{}
+		results = append(results, fqdnNode(name))
+	}
+	return results, nil
+}
+
+func (r *resolver) outNames(predicates ...string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		results := []interface{}{}
+		for _, id := range r.outNodes(p.Context, sourceID(p.Source), predicates...) {
+			results = append(results, fqdnNode(id))
+		}
+		return results, nil
+	}
+}
+
+func (r *resolver) outAddrs(predicates ...string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		results := []interface{}{}
+		for _, id := range r.outNodes(p.Context, sourceID(p.Source), predicates...) {
+			results = append(results, addrNode(id))
+		}
+		return results, nil
+	}
+}
+
+func (r *resolver) inNames(predicates ...string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		results := []interface{}{}
+		for _, id := range r.inNodes(p.Context, sourceID(p.Source), predicates...) {
+			results = append(results, fqdnNode(id))
+		}
+		return results, nil
+	}
+}
+
+func (r *resolver) addrNetblock(p graphql.ResolveParams) (interface{}, error) {
+	cidrs := r.inNodes(p.Context, sourceID(p.Source), "contains")
+	if len(cidrs) == 0 {
+		return nil, nil
+	}
+	// The most specific netblock containing the address is selected
+	sort.Slice(cidrs, func(i, j int) bool {
+		return prefixLen(cidrs[i]) > prefixLen(cidrs[j])
+	})
+	return netblockNode(cidrs[0]), nil
+}
+
+func (r *resolver) netblockAS(p graphql.ResolveParams) (interface{}, error) {
+	asns := r.inNodes(p.Context, sourceID(p.Source), "prefix")
+	if len(asns) == 0 {
+		return nil, nil
+	}
+	return asNode(asns[0]), nil
+}
+
+func (r *resolver) asn(p graphql.ResolveParams) (interface{}, error) {
+	return strconv.Atoi(sourceID(p.Source))
+}
+
+func (r *resolver) asDescription(p graphql.ResolveParams) (interface{}, error) {
+	asn, err := strconv.Atoi(sourceID(p.Source))
+	if err != nil {
+		return nil, nil
+	}
+	if desc := r.graph.ReadASDescription(p.Context, asn); desc != "" {
+		return desc, nil
+	}
+	return nil, nil
+}
+
+func (r *resolver) asNetblocks(p graphql.ResolveParams) (interface{}, error) {
+	results := []interface{}{}
+	for _, id := range r.outNodes(p.Context, sourceID(p.Source), "prefix") {
+		results = append(results, netblockNode(id))
+	}
+	return results, nil
+}
+
+func (r *resolver) findings(p graphql.ResolveParams) (interface{}, error) {
+	results := []*requests.Finding{}
+	for _, s := range r.properties(p.Context, sourceID(p.Source), requests.FindingPredicate) {
+		if f, ok := requests.ParseFinding(s); ok {
+			results = append(results, f)
+		}
+	}
+	return results, nil
+}
+
+func (r *resolver) httpProbes(p graphql.ResolveParams) (interface{}, error) {
+	results := []*requests.HTTPInfo{}
+	for _, s := range r.properties(p.Context, sourceID(p.Source), requests.HTTPPredicate) {
+		if h, ok := requests.ParseHTTPInfo(s); ok {
+			results = append(results, h)
+		}
+	}
+	return results, nil
+}
+
+func (r *resolver) properties(ctx context.Context, id, predicate string) []string {
+	var values []string
+
+	if props, err := r.graph.ReadProperties(ctx, netmap.Node(id), predicate); err == nil {
+		for _, p := range props {
+			if s, ok := p.Value.Native().(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func (r *resolver) outNodes(ctx context.Context, id string, predicates ...string) []string {
+	var ids []string
+
+	if edges, err := r.graph.ReadOutEdges(ctx, netmap.Node(id), predicates...); err == nil {
+		for _, edge := range edges {
+			ids = append(ids, r.graph.NodeToID(edge.To))
+		}
+	}
+	return uniqueSorted(ids)
+}
+
+func (r *resolver) inNodes(ctx context.Context, id string, predicates ...string) []string {
+	var ids []string
+
+	if edges, err := r.graph.ReadInEdges(ctx, netmap.Node(id), predicates...); err == nil {
+		for _, edge := range edges {
+			ids = append(ids, r.graph.NodeToID(edge.From))
+		}
+	}
+	return uniqueSorted(ids)
+}
+
+func uniqueSorted(ids []string) []string {
+	seen := make(map[string]struct{})
+
+	var results []string
+	for _, id := range ids {
+		if _, found := seen[id]; !found && id != "" {
+			seen[id] = struct{}{}
+			results = append(results, id)
+		}
+	}
+	sort.Strings(results)
+	return results
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func prefixLen(cidr string) int {
+	if _, ipnet, err := net.ParseCIDR(cidr); err == nil {
+		ones, _ := ipnet.Mask.Size()
+		return ones
+	}
+	return 0
+}
+
+func compareIPs(a, b net.IP) int {
+	if a4, b4 := a.To4(), b.To4(); (a4 == nil) != (b4 == nil) {
+		if a4 != nil {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(string(a.To16()), string(b.To16()))
+}
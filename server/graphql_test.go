@@ -0,0 +1,173 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/caffix/netmap"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+const testUUID = "ef9f9475-34eb-465e-81ec-9a1f7f8d2f5b"
+
+func testGraph(t *testing.T) *netmap.Graph {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+
+	if err := systems.SetEventWorkspace(ctx, g, testUUID, "acme"); err != nil {
+		t.Fatalf("failed to assign the workspace: %v", err)
+	}
+	if _, err := g.UpsertFQDN(ctx, "www.owasp.org", "Crtsh", testUUID); err != nil {
+		t.Fatalf("failed to insert the name: %v", err)
+	}
+	_ = g.UpsertCNAME(ctx, "www.owasp.org", "owasp.cdn.example.com", "DNS", testUUID)
+	_ = g.UpsertCNAME(ctx, "owasp.cdn.example.com", "edge.example.net", "DNS", testUUID)
+	_ = g.UpsertA(ctx, "edge.example.net", "192.168.1.10", "DNS", testUUID)
+	_ = g.UpsertA(ctx, "api.owasp.org", "192.168.2.5", "DNS", testUUID)
+	_ = g.UpsertInfrastructure(ctx, 64496, "EXAMPLE-AS", "192.168.1.10", "192.168.1.0/24", "RIR", testUUID)
+
+	h := &requests.HTTPInfo{URL: "https://www.owasp.org/", StatusCode: 200, Title: "OWASP"}
+	_ = g.UpsertProperty(ctx, netmap.Node("www.owasp.org"), requests.HTTPPredicate, h.String())
+	return g
+}
+
+func query(t *testing.T, handler http.Handler, q string) map[string]interface{} {
+	body, _ := json.Marshal(&GraphQLRequest{Query: q})
+	req := httptest.NewRequest(http.MethodPost, GraphQLPath, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("the query returned status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Data   map[string]interface{}   `json:"data"`
+		Errors []map[string]interface{} `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode the response: %v", err)
+	}
+	if len(resp.Errors) > 0 {
+		t.Fatalf("the query returned errors: %v", resp.Errors)
+	}
+	return resp.Data
+}
+
+func TestGraphQLQueries(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	handler, err := NewHandler(context.Background(), g, nil)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	data := query(t, handler, `{ enumerations(workspace: "acme") { uuid workspace domains } }`)
+	expected := map[string]interface{}{
+		"enumerations": []interface{}{map[string]interface{}{
+			"uuid":      testUUID,
+			"workspace": "acme",
+			"domains":   []interface{}{"example.com", "example.net", "owasp.org"},
+		}},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("enumerations returned %v, expected %v", data, expected)
+	}
+
+	data = query(t, handler, `{ name(name: "www.owasp.org") { domain sources cnameChain { name } http { url status } } }`)
+	expected = map[string]interface{}{
+		"name": map[string]interface{}{
+			"domain":     "owasp.org",
+			"sources":    []interface{}{"Crtsh", "DNS"},
+			"cnameChain": []interface{}{map[string]interface{}{"name": "owasp.cdn.example.com"}, map[string]interface{}{"name": "edge.example.net"}},
+			"http":       []interface{}{map[string]interface{}{"url": "https://www.owasp.org/", "status": float64(200)}},
+		},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("name returned %v, expected %v", data, expected)
+	}
+
+	data = query(t, handler, `{ names(domain: "owasp.org", source: "crtsh") { name } }`)
+	expected = map[string]interface{}{
+		"names": []interface{}{map[string]interface{}{"name": "owasp.org"}, map[string]interface{}{"name": "www.owasp.org"}},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("names returned %v, expected %v", data, expected)
+	}
+
+	data = query(t, handler, `{ addresses(netblock: "192.168.0.0/16") { address names { name } netblock { cidr autonomousSystem { asn description } } } }`)
+	expected = map[string]interface{}{
+		"addresses": []interface{}{
+			map[string]interface{}{
+				"address": "192.168.1.10",
+				"names":   []interface{}{map[string]interface{}{"name": "edge.example.net"}},
+				"netblock": map[string]interface{}{
+					"cidr":             "192.168.1.0/24",
+					"autonomousSystem": map[string]interface{}{"asn": float64(64496), "description": "EXAMPLE-AS"},
+				},
+			},
+			map[string]interface{}{
+				"address":  "192.168.2.5",
+				"names":    []interface{}{map[string]interface{}{"name": "api.owasp.org"}},
+				"netblock": nil,
+			},
+		},
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("addresses returned %v, expected %v", data, expected)
+	}
+
+	if data := query(t, handler, `{ name(name: "missing.owasp.org") { name } }`); data["name"] != nil {
+		t.Errorf("name returned %v for a name not in the graph", data["name"])
+	}
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	handler, err := NewGraphQLHandler(g)
+	if err != nil {
+		t.Fatalf("NewGraphQLHandler() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, GraphQLPath+"?query="+url.QueryEscape(`{ enumeration(uuid: "`+testUUID+`") { uuid } }`), nil))
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte(testUUID)) {
+		t.Errorf("the GET request returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// The schema does not provide any mutations
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, GraphQLPath, bytes.NewReader([]byte(`mutation { deleteName(name: "www.owasp.org") }`)))
+	req.Header.Set("Content-Type", "application/graphql")
+	handler.ServeHTTP(rec, req)
+	if !bytes.Contains(rec.Body.Bytes(), []byte(`"errors"`)) {
+		t.Errorf("the mutation was not rejected: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, GraphQLPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("the DELETE request returned %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, GraphQLPath, nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("the request without a query returned %d", rec.Code)
+	}
+}
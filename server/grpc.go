@@ -0,0 +1,259 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/owasp-amass/amass/v3/output"
+	"github.com/owasp-amass/amass/v3/requests"
+	"github.com/owasp-amass/amass/v3/server/jobspb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The status values of the job management API for the states of the jobs
+var jobStatuses = map[string]jobspb.JobStatus{
+	JobQueued:   jobspb.JobStatus_JOB_STATUS_QUEUED,
+	JobRunning:  jobspb.JobStatus_JOB_STATUS_RUNNING,
+	JobPaused:   jobspb.JobStatus_JOB_STATUS_PAUSED,
+	JobFinished: jobspb.JobStatus_JOB_STATUS_FINISHED,
+	JobFailed:   jobspb.JobStatus_JOB_STATUS_FAILED,
+	JobCanceled: jobspb.JobStatus_JOB_STATUS_CANCELED,
+}
+
+// NewGRPCServer returns the gRPC server providing the job management API defined in jobs.proto. The
+// jobs are shared with the HTTP handler created using the same settings, and the jobs still being
+// performed are canceled when the context is done.
+func NewGRPCServer(ctx context.Context, s *Settings) (*grpc.Server, error) {
+	if s == nil || s.Runner == nil {
+		return nil, errors.New("the job management API requires a Runner to perform the jobs")
+	}
+
+	var opts []grpc.ServerOption
+	if len(s.Tokens) > 0 {
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if err := checkToken(ctx, s.Tokens); err != nil {
+					return nil, err
+				}
+				return handler(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				if err := checkToken(ss.Context(), s.Tokens); err != nil {
+					return err
+				}
+				return handler(srv, ss)
+			}),
+		)
+	}
+
+	srv := grpc.NewServer(opts...)
+	jobspb.RegisterJobsServer(srv, &jobsServer{jobs: s.jobManager(ctx)})
+	return srv, nil
+}
+
+// checkToken only passes the calls providing one of the tokens in the authorization metadata.
+func checkToken(ctx context.Context, tokens []string) error {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, auth := range md.Get("authorization") {
+			if validToken(tokens, auth) {
+				return nil
+			}
+		}
+	}
+	return status.Error(codes.Unauthenticated, "the call does not provide a valid token")
+}
+
+// jobsServer implements the Jobs service over the job manager used by the REST API.
+type jobsServer struct {
+	jobspb.UnimplementedJobsServer
+	jobs *jobManager
+}
+
+// Submit implements the jobspb.JobsServer interface.
+func (s *jobsServer) Submit(ctx context.Context, req *jobspb.JobRequest) (*jobspb.Job, error) {
+	jr := &JobRequest{
+		Domains:     req.GetDomains(),
+		Workspace:   req.GetWorkspace(),
+		Passive:     req.GetPassive(),
+		Active:      req.GetActive(),
+		Brute:       req.GetBrute(),
+		Alterations: req.GetAlterations(),
+		Timeout:     int(req.GetTimeout()),
+	}
+	if err := jr.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	job, err := s.jobs.submit(jr)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return jobMessage(job), nil
+}
+
+// Get implements the jobspb.JobsServer interface.
+func (s *jobsServer) Get(ctx context.Context, req *jobspb.GetJobRequest) (*jobspb.Job, error) {
+	js, err := s.job(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return jobMessage(s.jobs.withProgress(js.snapshot())), nil
+}
+
+// List implements the jobspb.JobsServer interface.
+func (s *jobsServer) List(ctx context.Context, req *jobspb.ListJobsRequest) (*jobspb.ListJobsResponse, error) {
+	resp := new(jobspb.ListJobsResponse)
+
+	for _, job := range s.jobs.list() {
+		resp.Jobs = append(resp.Jobs, jobMessage(job))
+	}
+	return resp, nil
+}
+
+// Cancel implements the jobspb.JobsServer interface.
+func (s *jobsServer) Cancel(ctx context.Context, req *jobspb.CancelJobRequest) (*jobspb.Job, error) {
+	js, err := s.job(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	js.stop()
+	return jobMessage(js.snapshot()), nil
+}
+
+// Pause implements the jobspb.JobsServer interface.
+func (s *jobsServer) Pause(ctx context.Context, req *jobspb.PauseJobRequest) (*jobspb.Job, error) {
+	return s.setPaused(req.GetId(), true)
+}
+
+// Resume implements the jobspb.JobsServer interface.
+func (s *jobsServer) Resume(ctx context.Context, req *jobspb.ResumeJobRequest) (*jobspb.Job, error) {
+	return s.setPaused(req.GetId(), false)
+}
+
+func (s *jobsServer) setPaused(id string, pause bool) (*jobspb.Job, error) {
+	js, err := s.job(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.jobs.setPaused(js, pause); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return jobMessage(js.snapshot()), nil
+}
+
+// Results implements the jobspb.JobsServer interface. Unless follow is false, the stream provides
+// the new names until the job is done, as performed by the results endpoint of the REST API.
+func (s *jobsServer) Results(req *jobspb.ResultsRequest, stream jobspb.Jobs_ResultsServer) error {
+	js, err := s.job(req.GetId())
+	if err != nil {
+		return err
+	}
+
+	follow := req.Follow == nil || *req.Follow
+	var sent int
+	for {
+		js.Lock()
+		results := js.results[sent:]
+		done := js.done()
+		changed := js.changed
+		id := js.job.ID
+		js.Unlock()
+
+		for _, out := range results {
+			if err := stream.Send(outputMessage(output.NewEvent(id, out))); err != nil {
+				return err
+			}
+		}
+		sent += len(results)
+		if done || !follow {
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-changed:
+		}
+	}
+}
+
+func (s *jobsServer) job(id string) (*jobState, error) {
+	js := s.jobs.get(id)
+	if js == nil {
+		return nil, status.Error(codes.NotFound, "the job was not found")
+	}
+	return js, nil
+}
+
+func jobMessage(job *Job) *jobspb.Job {
+	msg := &jobspb.Job{
+		Id:      job.ID,
+		Status:  jobStatuses[job.Status],
+		Names:   int64(job.Names),
+		Created: timestamppb.New(job.Created),
+		Error:   job.Error,
+	}
+	if req := job.Request; req != nil {
+		msg.Request = &jobspb.JobRequest{
+			Domains:     req.Domains,
+			Workspace:   req.Workspace,
+			Passive:     req.Passive,
+			Active:      req.Active,
+			Brute:       req.Brute,
+			Alterations: req.Alterations,
+			Timeout:     int32(req.Timeout),
+		}
+	}
+	if job.Started != nil {
+		msg.Started = timestamppb.New(*job.Started)
+	}
+	if job.Finished != nil {
+		msg.Finished = timestamppb.New(*job.Finished)
+	}
+	if p := job.Progress; p != nil {
+		msg.Progress = &jobspb.JobProgress{
+			Percent:    p.Percent,
+			Planned:    int64(p.Planned),
+			Completed:  int64(p.Completed),
+			EtaSeconds: int64(p.ETA),
+		}
+	}
+	return msg
+}
+
+func outputMessage(ev *output.Event) *jobspb.Output {
+	msg := &jobspb.Output{
+		Timestamp: timestamppb.New(ev.Timestamp),
+		Uuid:      ev.UUID,
+		Name:      ev.Name,
+		Domain:    ev.Domain,
+		Tag:       ev.Tag,
+		Sources:   ev.Sources,
+	}
+	for _, addr := range ev.Addresses {
+		msg.Addresses = append(msg.Addresses, addressMessage(addr))
+	}
+	return msg
+}
+
+func addressMessage(addr requests.AddressInfo) *jobspb.AddressInfo {
+	msg := &jobspb.AddressInfo{
+		Cidr:        addr.CIDRStr,
+		Asn:         int32(addr.ASN),
+		Description: addr.Description,
+	}
+	if addr.Address != nil {
+		msg.Address = addr.Address.String()
+	}
+	return msg
+}
@@ -0,0 +1,251 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/server/jobspb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func testJobsClient(t *testing.T, ctx context.Context, s *Settings) jobspb.JobsClient {
+	srv, err := NewGRPCServer(ctx, s)
+	if err != nil {
+		t.Fatalf("NewGRPCServer() error = %v", err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to connect to the gRPC server: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return jobspb.NewJobsClient(conn)
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func waitForJobStatus(t *testing.T, ctx context.Context, c jobspb.JobsClient, id string, st jobspb.JobStatus) *jobspb.Job {
+	var job *jobspb.Job
+
+	for i := 0; i < 100; i++ {
+		var err error
+		if job, err = c.Get(ctx, &jobspb.GetJobRequest{Id: id}); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if job.GetStatus() == st {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("the job has the status %s, expected %s", job.GetStatus(), st)
+	return nil
+}
+
+func TestGRPCServerRequiresRunner(t *testing.T) {
+	if _, err := NewGRPCServer(context.Background(), &Settings{}); err == nil {
+		t.Errorf("NewGRPCServer() did not return an error without a Runner")
+	}
+}
+
+func TestGRPCTokenAuthentication(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := testJobsClient(t, ctx, &Settings{Tokens: []string{testToken}, Runner: &testRunner{release: make(chan struct{})}})
+
+	for _, cctx := range []context.Context{ctx, withToken(ctx, "wrong-token")} {
+		if _, err := c.List(cctx, &jobspb.ListJobsRequest{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("List() without a valid token returned %v", err)
+		}
+		stream, err := c.Results(cctx, &jobspb.ResultsRequest{Id: "missing"})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Results() without a valid token returned %v", err)
+		}
+	}
+
+	if _, err := c.List(withToken(ctx, testToken), &jobspb.ListJobsRequest{}); err != nil {
+		t.Errorf("List() with the token returned %v", err)
+	}
+}
+
+func TestGRPCJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := &testRunner{release: make(chan struct{})}
+	c := testJobsClient(t, ctx, &Settings{Runner: runner})
+
+	for _, req := range []*jobspb.JobRequest{
+		{},
+		{Domains: []string{"owasp.org"}, Passive: true, Brute: true},
+		{Domains: []string{"owasp.org"}, Timeout: -1},
+	} {
+		if _, err := c.Submit(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Submit(%v) returned %v", req, err)
+		}
+	}
+	if _, err := c.Get(ctx, &jobspb.GetJobRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() of a missing job returned %v", err)
+	}
+
+	job, err := c.Submit(ctx, &jobspb.JobRequest{Domains: []string{"OWASP.org."}, Passive: true})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if job.GetRequest().GetDomains()[0] != "owasp.org" || job.GetCreated() == nil {
+		t.Errorf("Submit() returned %v", job)
+	}
+
+	running := waitForJobStatus(t, ctx, c, job.GetId(), jobspb.JobStatus_JOB_STATUS_RUNNING)
+	if running.GetStarted() == nil || running.GetNames() != 1 || running.GetProgress().GetPlanned() != 10 {
+		t.Errorf("the running job provided the wrong progress: %v", running)
+	}
+
+	stream, err := c.Results(ctx, &jobspb.ResultsRequest{Id: job.GetId()})
+	if err != nil {
+		t.Fatalf("Results() error = %v", err)
+	}
+	first, err := stream.Recv()
+	if err != nil || first.GetName() != "www.owasp.org" || first.GetUuid() != job.GetId() {
+		t.Fatalf("the first streamed result was %v: %v", first, err)
+	}
+
+	close(runner.release)
+	// The results are streamed until the job is done
+	var names []string
+	for {
+		out, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("the stream failed: %v", err)
+		}
+		names = append(names, out.GetName())
+	}
+	if len(names) != 1 || names[0] != "api.owasp.org" {
+		t.Errorf("the stream provided the later results %v", names)
+	}
+
+	finished := waitForJobStatus(t, ctx, c, job.GetId(), jobspb.JobStatus_JOB_STATUS_FINISHED)
+	if finished.GetNames() != 2 || finished.GetFinished() == nil || finished.GetProgress() != nil {
+		t.Errorf("the finished job provided the wrong progress: %v", finished)
+	}
+
+	follow := false
+	stream, err = c.Results(ctx, &jobspb.ResultsRequest{Id: job.GetId(), Follow: &follow})
+	if err != nil {
+		t.Fatalf("Results() error = %v", err)
+	}
+	var count int
+	for ; ; count++ {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("the results of the finished job had %d names", count)
+	}
+
+	list, err := c.List(ctx, &jobspb.ListJobsRequest{})
+	if err != nil || len(list.GetJobs()) != 1 || list.GetJobs()[0].GetId() != job.GetId() {
+		t.Errorf("List() returned %v: %v", list, err)
+	}
+}
+
+func TestGRPCCancelAndPause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := &testRunner{release: make(chan struct{})}
+	c := testJobsClient(t, ctx, &Settings{Runner: runner})
+
+	job, err := c.Submit(ctx, &jobspb.JobRequest{Domains: []string{"owasp.org"}})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	waitForJobStatus(t, ctx, c, job.GetId(), jobspb.JobStatus_JOB_STATUS_RUNNING)
+
+	if _, err := c.Resume(ctx, &jobspb.ResumeJobRequest{Id: job.GetId()}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Resume() of the running job returned %v", err)
+	}
+	if paused, err := c.Pause(ctx, &jobspb.PauseJobRequest{Id: job.GetId()}); err != nil ||
+		paused.GetStatus() != jobspb.JobStatus_JOB_STATUS_PAUSED || !runner.isPaused() {
+		t.Errorf("Pause() returned %v: %v", paused, err)
+	}
+	if _, err := c.Resume(ctx, &jobspb.ResumeJobRequest{Id: job.GetId()}); err != nil || runner.isPaused() {
+		t.Errorf("Resume() of the paused job returned %v", err)
+	}
+
+	queued, err := c.Submit(ctx, &jobspb.JobRequest{Domains: []string{"example.com"}})
+	if err != nil || queued.GetStatus() != jobspb.JobStatus_JOB_STATUS_QUEUED {
+		t.Fatalf("the second job was submitted as %v: %v", queued, err)
+	}
+	// Canceling the queued job keeps it from being performed
+	if _, err := c.Cancel(ctx, &jobspb.CancelJobRequest{Id: queued.GetId()}); err != nil {
+		t.Errorf("Cancel() of the queued job returned %v", err)
+	}
+	if _, err := c.Cancel(ctx, &jobspb.CancelJobRequest{Id: job.GetId()}); err != nil {
+		t.Errorf("Cancel() of the running job returned %v", err)
+	}
+
+	if canceled := waitForJobStatus(t, ctx, c, job.GetId(), jobspb.JobStatus_JOB_STATUS_CANCELED); canceled.GetNames() != 1 {
+		t.Errorf("the canceled job provided the wrong progress: %v", canceled)
+	}
+	if cq := waitForJobStatus(t, ctx, c, queued.GetId(), jobspb.JobStatus_JOB_STATUS_CANCELED); cq.GetStarted() != nil {
+		t.Errorf("the canceled job was performed: %v", cq)
+	}
+	if _, err := c.Cancel(ctx, &jobspb.CancelJobRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Cancel() of a missing job returned %v", err)
+	}
+}
+
+func TestJobsSharedWithHandler(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := &Settings{Runner: &testRunner{release: make(chan struct{})}}
+	handler, err := NewHandler(ctx, g, s)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+	c := testJobsClient(t, ctx, s)
+
+	job, err := c.Submit(ctx, &jobspb.JobRequest{Domains: []string{"owasp.org"}})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	// The job submitted over gRPC is provided by the REST API
+	if rec := apiRequest(t, handler, http.MethodGet, JobsPath+"/"+job.GetId(), ""); rec.Code != http.StatusOK {
+		t.Errorf("the REST API returned status %d for the job", rec.Code)
+	}
+}
@@ -0,0 +1,514 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/owasp-amass/amass/v3/output"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+// JobsPath is the path of the endpoint accepting the enumeration jobs.
+const JobsPath = APIPath + "/jobs"
+
+// The states of the enumeration jobs
+const (
+	JobQueued   = "queued"
+	JobRunning  = "running"
+	JobPaused   = "paused"
+	JobFinished = "finished"
+	JobFailed   = "failed"
+	JobCanceled = "canceled"
+)
+
+// The maximum number of jobs waiting for the enumeration in progress
+const maxQueuedJobs = 100
+
+// The maximum number of finished jobs retained with their results
+const maxFinishedJobs = 100
+
+// JobRequest describes the enumeration submitted as a job.
+type JobRequest struct {
+	Domains     []string `json:"domains"`
+	Workspace   string   `json:"workspace,omitempty"`
+	Passive     bool     `json:"passive,omitempty"`
+	Active      bool     `json:"active,omitempty"`
+	Brute       bool     `json:"brute,omitempty"`
+	Alterations bool     `json:"alterations,omitempty"`
+	// The number of minutes the enumeration runs before quitting
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// Job provides the status and progress of an enumeration job. The job ID is also the
+// UUID of the enumeration in the graph database.
+type Job struct {
+	ID       string      `json:"id"`
+	Status   string      `json:"status"`
+	Request  *JobRequest `json:"request"`
+	Names    int         `json:"names"`
+	Created  time.Time   `json:"created"`
+	Started  *time.Time  `json:"started,omitempty"`
+	Finished *time.Time  `json:"finished,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	// Provided while the job is running or paused, when the runner reports the progress
+	Progress *JobProgress `json:"progress,omitempty"`
+}
+
+// JobProgress describes the work planned and completed by the enumeration of a job.
+type JobProgress struct {
+	Percent   float64 `json:"percent"`
+	Planned   int     `json:"planned"`
+	Completed int     `json:"completed"`
+	// The estimated number of seconds remaining, which is omitted while unknown
+	ETA int `json:"eta_seconds,omitempty"`
+}
+
+// Runner performs the enumerations of the jobs. Run provides each discovered name to the
+// output function, and stores the findings in the graph database under the job ID.
+type Runner interface {
+	Run(ctx context.Context, id string, req *JobRequest, output func(*requests.Output)) error
+}
+
+// Pauser is implemented by the Runners able to pause and resume the job in progress, holding
+// its active work without losing the state of the enumeration.
+type Pauser interface {
+	Pause(id string) error
+	Resume(id string) error
+}
+
+// ProgressReporter is implemented by the Runners able to report the progress of the job in progress.
+type ProgressReporter interface {
+	Progress(id string) (*JobProgress, error)
+}
+
+type jobState struct {
+	sync.Mutex
+	job     Job
+	results []*requests.Output
+	cancel  context.CancelFunc
+	// Closed and replaced each time the job changes
+	changed chan struct{}
+}
+
+type jobManager struct {
+	sync.Mutex
+	ctx         context.Context
+	runner      Runner
+	jobs        map[string]*jobState
+	queue       chan *jobState
+	maxFinished int
+}
+
+func newJobManager(ctx context.Context, runner Runner) *jobManager {
+	m := &jobManager{
+		ctx:         ctx,
+		runner:      runner,
+		jobs:        make(map[string]*jobState),
+		queue:       make(chan *jobState, maxQueuedJobs),
+		maxFinished: maxFinishedJobs,
+	}
+
+	go m.processJobs()
+	return m
+}
+
+// The jobs are performed one at a time in the order of submission.
+func (m *jobManager) processJobs() {
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case js := <-m.queue:
+			m.run(js)
+		}
+	}
+}
+
+func (m *jobManager) run(js *jobState) {
+	js.Lock()
+	if js.job.Status != JobQueued {
+		js.Unlock()
+		return
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if t := js.job.Request.Timeout; t > 0 {
+		ctx, cancel = context.WithTimeout(m.ctx, time.Duration(t)*time.Minute)
+	} else {
+		ctx, cancel = context.WithCancel(m.ctx)
+	}
+	defer cancel()
+
+	now := time.Now()
+	js.cancel = cancel
+	js.job.Status = JobRunning
+	js.job.Started = &now
+	js.notify()
+	js.Unlock()
+
+	err := m.runner.Run(ctx, js.job.ID, js.job.Request, func(out *requests.Output) {
+		js.Lock()
+		defer js.Unlock()
+
+		js.results = append(js.results, out)
+		js.job.Names = len(js.results)
+		js.notify()
+	})
+
+	js.Lock()
+	finished := time.Now()
+	js.job.Finished = &finished
+	switch {
+	case js.job.Status == JobCanceled:
+	case err != nil:
+		js.job.Status = JobFailed
+		js.job.Error = err.Error()
+	default:
+		js.job.Status = JobFinished
+	}
+	js.notify()
+	js.Unlock()
+
+	m.prune()
+}
+
+func (m *jobManager) submit(req *JobRequest) (*Job, error) {
+	js := &jobState{
+		job: Job{
+			ID:      uuid.New().String(),
+			Status:  JobQueued,
+			Request: req,
+			Created: time.Now(),
+		},
+		changed: make(chan struct{}),
+	}
+
+	// The job must be found by the time it is taken from the queue
+	m.Lock()
+	m.jobs[js.job.ID] = js
+	m.Unlock()
+
+	select {
+	case m.queue <- js:
+	default:
+		m.Lock()
+		delete(m.jobs, js.job.ID)
+		m.Unlock()
+		return nil, errors.New("too many jobs are waiting to be performed")
+	}
+
+	m.prune()
+	return js.snapshot(), nil
+}
+
+// prune removes the oldest finished jobs beyond the number retained, releasing their results.
+func (m *jobManager) prune() {
+	m.Lock()
+	defer m.Unlock()
+
+	var finished []*jobState
+	for _, js := range m.jobs {
+		js.Lock()
+		if js.done() {
+			finished = append(finished, js)
+		}
+		js.Unlock()
+	}
+	if len(finished) <= m.maxFinished {
+		return
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].finishedAt().Before(finished[j].finishedAt())
+	})
+	for _, js := range finished[:len(finished)-m.maxFinished] {
+		delete(m.jobs, js.job.ID)
+	}
+}
+
+func (m *jobManager) get(id string) *jobState {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.jobs[id]
+}
+
+func (m *jobManager) list() []*Job {
+	m.Lock()
+	states := make([]*jobState, 0, len(m.jobs))
+	for _, js := range m.jobs {
+		states = append(states, js)
+	}
+	m.Unlock()
+
+	jobs := make([]*Job, 0, len(states))
+	for _, js := range states {
+		jobs = append(jobs, m.withProgress(js.snapshot()))
+	}
+	// The most recent jobs are listed first
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].Created.Equal(jobs[j].Created) {
+			return jobs[i].Created.After(jobs[j].Created)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs
+}
+
+// notify must be called while holding the lock.
+func (js *jobState) notify() {
+	close(js.changed)
+	js.changed = make(chan struct{})
+}
+
+func (js *jobState) snapshot() *Job {
+	js.Lock()
+	defer js.Unlock()
+
+	job := js.job
+	return &job
+}
+
+// withProgress adds the progress reported by the runner to the snapshot of an active job.
+func (m *jobManager) withProgress(job *Job) *Job {
+	pr, ok := m.runner.(ProgressReporter)
+	if !ok || (job.Status != JobRunning && job.Status != JobPaused) {
+		return job
+	}
+
+	if p, err := pr.Progress(job.ID); err == nil {
+		job.Progress = p
+	}
+	return job
+}
+
+func (js *jobState) stop() {
+	js.Lock()
+	defer js.Unlock()
+
+	switch js.job.Status {
+	case JobQueued:
+		now := time.Now()
+		js.job.Finished = &now
+	case JobRunning, JobPaused:
+		js.cancel()
+	default:
+		return
+	}
+	js.job.Status = JobCanceled
+	js.notify()
+}
+
+// setPaused pauses or resumes the job in progress using the runner.
+func (m *jobManager) setPaused(js *jobState, pause bool) error {
+	p, ok := m.runner.(Pauser)
+	if !ok {
+		return errors.New("the server cannot pause the jobs")
+	}
+
+	js.Lock()
+	defer js.Unlock()
+
+	from, to := JobRunning, JobPaused
+	if !pause {
+		from, to = to, from
+	}
+	if js.job.Status != from {
+		return fmt.Errorf("the job is %s", js.job.Status)
+	}
+
+	var err error
+	if pause {
+		err = p.Pause(js.job.ID)
+	} else {
+		err = p.Resume(js.job.ID)
+	}
+	if err != nil {
+		return err
+	}
+	js.job.Status = to
+	js.notify()
+	return nil
+}
+
+func (js *jobState) finishedAt() time.Time {
+	js.Lock()
+	defer js.Unlock()
+
+	if js.job.Finished == nil {
+		return time.Time{}
+	}
+	return *js.job.Finished
+}
+
+func (js *jobState) done() bool {
+	switch js.job.Status {
+	case JobFinished, JobFailed, JobCanceled:
+		return true
+	}
+	return false
+}
+
+func (m *jobManager) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(req.URL.Path, JobsPath), "/")
+	if rest == "" {
+		switch req.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, m.list())
+		case http.MethodPost:
+			m.handleSubmit(w, req)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeError(w, http.StatusMethodNotAllowed, "the jobs endpoint only accepts GET and POST requests")
+		}
+		return
+	}
+
+	parts := strings.Split(rest, "/")
+	js := m.get(parts[0])
+	if js == nil || len(parts) > 2 || (len(parts) == 2 && parts[1] != "results" && parts[1] != "pause" && parts[1] != "resume") {
+		writeError(w, http.StatusNotFound, "the job was not found")
+		return
+	}
+
+	if len(parts) == 2 && parts[1] != "results" {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeError(w, http.StatusMethodNotAllowed, "the "+parts[1]+" endpoint only accepts POST requests")
+			return
+		}
+		if err := m.setPaused(js, parts[1] == "pause"); err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, js.snapshot())
+		return
+	}
+	if len(parts) == 2 {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			writeError(w, http.StatusMethodNotAllowed, "the results endpoint only accepts GET requests")
+			return
+		}
+		streamResults(w, req, js)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, m.withProgress(js.snapshot()))
+	case http.MethodDelete:
+		js.stop()
+		writeJSON(w, http.StatusOK, js.snapshot())
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, "the job endpoint only accepts GET and DELETE requests")
+	}
+}
+
+func (m *jobManager) handleSubmit(w http.ResponseWriter, req *http.Request) {
+	var jr JobRequest
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxQuerySize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read the request body")
+		return
+	}
+	if err := json.Unmarshal(body, &jr); err != nil {
+		writeError(w, http.StatusBadRequest, "the request body is not a valid job request")
+		return
+	}
+	if err := jr.validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := m.submit(&jr)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	w.Header().Set("Location", JobsPath+"/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (jr *JobRequest) validate() error {
+	var domains []string
+
+	for _, d := range jr.Domains {
+		d = strings.ToLower(strings.Trim(strings.TrimSpace(d), "."))
+		if d == "" {
+			continue
+		}
+		if strings.ContainsAny(d, " /:") {
+			return fmt.Errorf("%q is not a valid domain name", d)
+		}
+		domains = append(domains, d)
+	}
+	if len(domains) == 0 {
+		return errors.New("the job request does not provide any domain names")
+	}
+	jr.Domains = domains
+
+	if jr.Passive && (jr.Active || jr.Brute || jr.Alterations) {
+		return errors.New("the passive mode cannot be used with the active, brute or alterations options")
+	}
+	if jr.Timeout < 0 {
+		return errors.New("the timeout cannot be negative")
+	}
+	return nil
+}
+
+// streamResults writes the names discovered by the job as newline delimited JSON. Unless the follow
+// parameter is false, the response stays open and provides the new names until the job is done.
+func streamResults(w http.ResponseWriter, req *http.Request, js *jobState) {
+	follow := req.URL.Query().Get("follow") != "false"
+	flusher, _ := w.(http.Flusher)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	var sent int
+	for {
+		js.Lock()
+		results := js.results[sent:]
+		done := js.done()
+		changed := js.changed
+		id := js.job.ID
+		js.Unlock()
+
+		for _, out := range results {
+			if err := enc.Encode(output.NewEvent(id, out)); err != nil {
+				return
+			}
+		}
+		sent += len(results)
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if done || !follow {
+			return
+		}
+
+		select {
+		case <-req.Context().Done():
+			return
+		case <-changed:
+		}
+	}
+}
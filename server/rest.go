@@ -0,0 +1,148 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/owasp-amass/amass/v3/systems"
+)
+
+// APIPath is the path prefix of the REST API endpoints.
+const APIPath = "/api/v1"
+
+// EnumerationsPath is the path of the endpoint reading the enumerations in the graph database.
+const EnumerationsPath = APIPath + "/enumerations"
+
+// Enumeration describes an enumeration stored in the graph database.
+type Enumeration struct {
+	UUID      string     `json:"uuid"`
+	Workspace string     `json:"workspace,omitempty"`
+	Domains   []string   `json:"domains"`
+	Start     *time.Time `json:"start,omitempty"`
+	Finish    *time.Time `json:"finish,omitempty"`
+}
+
+// Name describes a DNS name discovered by an enumeration.
+type Name struct {
+	Name      string   `json:"name"`
+	Domain    string   `json:"domain,omitempty"`
+	Addresses []string `json:"addresses"`
+	Sources   []string `json:"sources"`
+}
+
+// ServeHTTP implements the http.Handler interface for the enumerations endpoints.
+func (r *resolver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, http.StatusMethodNotAllowed, "the enumerations endpoint only accepts GET requests")
+		return
+	}
+
+	rest := strings.Trim(strings.TrimPrefix(req.URL.Path, EnumerationsPath), "/")
+	if rest == "" {
+		r.listEnumerations(w, req)
+		return
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) > 2 || (len(parts) == 2 && parts[1] != "names") || !r.hasEvent(req, parts[0]) {
+		writeError(w, http.StatusNotFound, "the enumeration was not found")
+		return
+	}
+	if len(parts) == 2 {
+		r.enumerationNames(w, req, parts[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, r.enumeration(req, parts[0]))
+}
+
+func (r *resolver) listEnumerations(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	q := req.URL.Query()
+
+	var events []string
+	if domain := q.Get("domain"); domain != "" {
+		events = r.graph.EventsInScope(ctx, strings.ToLower(domain))
+	} else {
+		events = r.graph.EventList(ctx)
+	}
+	if q.Has("workspace") {
+		events = systems.WorkspaceEvents(ctx, r.graph, q.Get("workspace"), events)
+	}
+
+	enums := []*Enumeration{}
+	for _, uuid := range events {
+		enums = append(enums, r.enumeration(req, uuid))
+	}
+	// The most recent enumerations are listed first
+	sort.Slice(enums, func(i, j int) bool {
+		si, sj := enums[i].Start, enums[j].Start
+		if si != nil && sj != nil && !si.Equal(*sj) {
+			return si.After(*sj)
+		}
+		return enums[i].UUID < enums[j].UUID
+	})
+	writeJSON(w, http.StatusOK, enums)
+}
+
+func (r *resolver) hasEvent(req *http.Request, uuid string) bool {
+	for _, e := range r.graph.EventList(req.Context()) {
+		if e == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *resolver) enumeration(req *http.Request, uuid string) *Enumeration {
+	ctx := req.Context()
+	domains := r.graph.EventDomains(ctx, uuid)
+	sort.Strings(domains)
+
+	e := &Enumeration{
+		UUID:      uuid,
+		Workspace: systems.EventWorkspace(ctx, r.graph, uuid),
+		Domains:   append([]string{}, domains...),
+	}
+	if start, finish := r.graph.EventDateRange(ctx, uuid); !start.IsZero() {
+		e.Start = &start
+		e.Finish = &finish
+	}
+	return e
+}
+
+// enumerationNames writes the names discovered by the enumeration, optionally within the domain parameter.
+func (r *resolver) enumerationNames(w http.ResponseWriter, req *http.Request, uuid string) {
+	ctx := req.Context()
+	domain := strings.ToLower(strings.Trim(req.URL.Query().Get("domain"), "."))
+	eventset := r.eventSet(ctx, []string{uuid})
+
+	names := r.graph.EventFQDNs(ctx, uuid)
+	sort.Strings(names)
+
+	results := []*Name{}
+	for _, name := range names {
+		if domain != "" && name != domain && !strings.HasSuffix(name, "."+domain) {
+			continue
+		}
+
+		n := &Name{
+			Name:      name,
+			Addresses: append([]string{}, r.outNodes(ctx, name, "a_record", "aaaa_record")...),
+			Sources:   r.nodeSources(ctx, name, eventset),
+		}
+		if d, _ := r.nameDomain(graphql.ResolveParams{Context: ctx, Source: fqdnNode(name)}); d != nil {
+			n.Domain, _ = d.(string)
+		}
+		sort.Strings(n.Sources)
+		results = append(results, n)
+	}
+	writeJSON(w, http.StatusOK, results)
+}
@@ -0,0 +1,389 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/owasp-amass/amass/v3/output"
+	"github.com/owasp-amass/amass/v3/requests"
+)
+
+const testToken = "secret-token"
+
+type testRunner struct {
+	sync.Mutex
+	release chan struct{}
+	paused  bool
+}
+
+func (tr *testRunner) Run(ctx context.Context, id string, req *JobRequest, out func(*requests.Output)) error {
+	for _, d := range req.Domains {
+		out(&requests.Output{Name: "www." + d, Domain: d})
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-tr.release:
+		out(&requests.Output{Name: "api." + req.Domains[0], Domain: req.Domains[0]})
+	}
+	return nil
+}
+
+func (tr *testRunner) Pause(id string) error {
+	tr.Lock()
+	defer tr.Unlock()
+
+	tr.paused = true
+	return nil
+}
+
+func (tr *testRunner) Resume(id string) error {
+	tr.Lock()
+	defer tr.Unlock()
+
+	tr.paused = false
+	return nil
+}
+
+func (tr *testRunner) Progress(id string) (*JobProgress, error) {
+	return &JobProgress{Percent: 50, Planned: 10, Completed: 5, ETA: 60}, nil
+}
+
+func (tr *testRunner) isPaused() bool {
+	tr.Lock()
+	defer tr.Unlock()
+
+	return tr.paused
+}
+
+func apiRequest(t *testing.T, handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func waitForStatus(t *testing.T, handler http.Handler, id, status string) *Job {
+	var job Job
+
+	for i := 0; i < 100; i++ {
+		rec := apiRequest(t, handler, http.MethodGet, JobsPath+"/"+id, "")
+		if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+			t.Fatalf("failed to decode the job: %v", err)
+		}
+		if job.Status == status {
+			return &job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("the job has the status %s, expected %s", job.Status, status)
+	return nil
+}
+
+func TestTokenAuthentication(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	handler, err := NewHandler(context.Background(), g, &Settings{Tokens: []string{testToken}})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	for _, auth := range []string{"", "Bearer wrong-token", "Basic " + testToken} {
+		req := httptest.NewRequest(http.MethodGet, EnumerationsPath, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("the request with the authorization %q returned status %d", auth, rec.Code)
+		}
+	}
+
+	if rec := apiRequest(t, handler, http.MethodGet, EnumerationsPath, ""); rec.Code != http.StatusOK {
+		t.Errorf("the authenticated request returned status %d", rec.Code)
+	}
+	if rec := apiRequest(t, handler, http.MethodGet, JobsPath, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("the jobs endpoint was served without a runner")
+	}
+}
+
+func TestEnumerationEndpoints(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	handler, err := NewHandler(context.Background(), g, nil)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	var enums []*Enumeration
+	rec := apiRequest(t, handler, http.MethodGet, EnumerationsPath+"?workspace=acme", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &enums); err != nil {
+		t.Fatalf("failed to decode the enumerations: %v", err)
+	}
+	if len(enums) != 1 || enums[0].UUID != testUUID || enums[0].Workspace != "acme" || enums[0].Start == nil {
+		t.Errorf("the enumerations endpoint returned %s", rec.Body.String())
+	}
+
+	rec = apiRequest(t, handler, http.MethodGet, EnumerationsPath+"?workspace=other", "")
+	if got := strings.TrimSpace(rec.Body.String()); got != "[]" {
+		t.Errorf("the enumerations of another workspace were returned: %s", got)
+	}
+
+	var names []*Name
+	rec = apiRequest(t, handler, http.MethodGet, EnumerationsPath+"/"+testUUID+"/names?domain=owasp.org", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &names); err != nil {
+		t.Fatalf("failed to decode the names: %v", err)
+	}
+	expected := []*Name{
+		{Name: "api.owasp.org", Domain: "owasp.org", Addresses: []string{"192.168.2.5"}, Sources: []string{"DNS"}},
+		{Name: "owasp.org", Domain: "owasp.org", Addresses: []string{}, Sources: []string{"Crtsh", "DNS"}},
+		{Name: "www.owasp.org", Domain: "owasp.org", Addresses: []string{}, Sources: []string{"Crtsh", "DNS"}},
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("the names endpoint returned %s", rec.Body.String())
+	}
+
+	if rec := apiRequest(t, handler, http.MethodGet, EnumerationsPath+"/missing", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("the missing enumeration returned status %d", rec.Code)
+	}
+	if rec := apiRequest(t, handler, http.MethodPost, EnumerationsPath, "{}"); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("the POST request returned status %d", rec.Code)
+	}
+}
+
+func TestJobEndpoints(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := &testRunner{release: make(chan struct{})}
+	handler, err := NewHandler(ctx, g, &Settings{Tokens: []string{testToken}, Runner: runner})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	for _, body := range []string{`{"domains": []}`, `{"domains": ["owasp.org"], "passive": true, "brute": true}`, `not json`} {
+		if rec := apiRequest(t, handler, http.MethodPost, JobsPath, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("the job request %s returned status %d", body, rec.Code)
+		}
+	}
+
+	var job Job
+	rec := apiRequest(t, handler, http.MethodPost, JobsPath, `{"domains": ["OWASP.org."], "passive": true}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("the job submission returned status %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+		t.Fatalf("failed to decode the job: %v", err)
+	}
+	if rec.Header().Get("Location") != JobsPath+"/"+job.ID || job.Request.Domains[0] != "owasp.org" {
+		t.Errorf("the job submission returned %s", rec.Body.String())
+	}
+
+	running := waitForStatus(t, handler, job.ID, JobRunning)
+	if running.Started == nil || running.Names != 1 {
+		t.Errorf("the running job provided the wrong progress: %+v", running)
+	}
+	// The results are streamed until the job is done
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+JobsPath+"/"+job.ID+"/results", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to request the results: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	var first output.Event
+	line, err := reader.ReadBytes('\n')
+	if err != nil || json.Unmarshal(line, &first) != nil || first.Name != "www.owasp.org" || first.UUID != job.ID {
+		t.Fatalf("the first streamed result was %q", line)
+	}
+
+	close(runner.release)
+	rest, _ := io.ReadAll(reader)
+	if !strings.Contains(string(rest), `"name":"api.owasp.org"`) {
+		t.Errorf("the stream did not provide the later result: %q", rest)
+	}
+
+	finished := waitForStatus(t, handler, job.ID, JobFinished)
+	if finished.Names != 2 || finished.Finished == nil {
+		t.Errorf("the finished job provided the wrong progress: %+v", finished)
+	}
+
+	rec = apiRequest(t, handler, http.MethodGet, JobsPath+"/"+job.ID+"/results?follow=false", "")
+	if lines := strings.Count(rec.Body.String(), "\n"); lines != 2 {
+		t.Errorf("the results of the finished job had %d lines", lines)
+	}
+
+	var jobs []*Job
+	rec = apiRequest(t, handler, http.MethodGet, JobsPath, "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &jobs); err != nil || len(jobs) != 1 {
+		t.Errorf("the jobs endpoint returned %s", rec.Body.String())
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	runner := &testRunner{release: make(chan struct{})}
+	handler, err := NewHandler(context.Background(), g, &Settings{Runner: runner})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	var job Job
+	rec := apiRequest(t, handler, http.MethodPost, JobsPath, `{"domains": ["owasp.org"]}`)
+	_ = json.Unmarshal(rec.Body.Bytes(), &job)
+	waitForStatus(t, handler, job.ID, JobRunning)
+
+	var queued Job
+	rec = apiRequest(t, handler, http.MethodPost, JobsPath, `{"domains": ["example.com"]}`)
+	_ = json.Unmarshal(rec.Body.Bytes(), &queued)
+	if queued.Status != JobQueued {
+		t.Errorf("the second job has the status %s", queued.Status)
+	}
+	// Canceling the queued job keeps it from being performed
+	if rec := apiRequest(t, handler, http.MethodDelete, JobsPath+"/"+queued.ID, ""); rec.Code != http.StatusOK {
+		t.Errorf("the cancellation returned status %d", rec.Code)
+	}
+	apiRequest(t, handler, http.MethodDelete, JobsPath+"/"+job.ID, "")
+
+	if canceled := waitForStatus(t, handler, job.ID, JobCanceled); canceled.Names != 1 {
+		t.Errorf("the canceled job provided the wrong progress: %+v", canceled)
+	}
+	if c := waitForStatus(t, handler, queued.ID, JobCanceled); c.Started != nil || c.Names != 0 {
+		t.Errorf("the canceled job was performed: %+v", c)
+	}
+}
+
+func TestPauseJob(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	runner := &testRunner{release: make(chan struct{})}
+	handler, err := NewHandler(context.Background(), g, &Settings{Runner: runner})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	var job Job
+	rec := apiRequest(t, handler, http.MethodPost, JobsPath, `{"domains": ["owasp.org"]}`)
+	_ = json.Unmarshal(rec.Body.Bytes(), &job)
+	waitForStatus(t, handler, job.ID, JobRunning)
+
+	if rec := apiRequest(t, handler, http.MethodGet, JobsPath+"/"+job.ID+"/pause", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("the pause endpoint accepted a GET request with status %d", rec.Code)
+	}
+	if rec := apiRequest(t, handler, http.MethodPost, JobsPath+"/"+job.ID+"/resume", ""); rec.Code != http.StatusConflict {
+		t.Errorf("the running job was resumed with status %d", rec.Code)
+	}
+
+	rec = apiRequest(t, handler, http.MethodPost, JobsPath+"/"+job.ID+"/pause", "")
+	if rec.Code != http.StatusOK || !runner.isPaused() {
+		t.Fatalf("the pause returned status %d: %s", rec.Code, rec.Body.String())
+	}
+	waitForStatus(t, handler, job.ID, JobPaused)
+	if rec := apiRequest(t, handler, http.MethodPost, JobsPath+"/"+job.ID+"/pause", ""); rec.Code != http.StatusConflict {
+		t.Errorf("the paused job was paused again with status %d", rec.Code)
+	}
+
+	rec = apiRequest(t, handler, http.MethodPost, JobsPath+"/"+job.ID+"/resume", "")
+	if rec.Code != http.StatusOK || runner.isPaused() {
+		t.Fatalf("the resume returned status %d: %s", rec.Code, rec.Body.String())
+	}
+	waitForStatus(t, handler, job.ID, JobRunning)
+
+	close(runner.release)
+	waitForStatus(t, handler, job.ID, JobFinished)
+	if rec := apiRequest(t, handler, http.MethodPost, JobsPath+"/"+job.ID+"/pause", ""); rec.Code != http.StatusConflict {
+		t.Errorf("the finished job was paused with status %d", rec.Code)
+	}
+}
+
+func TestJobProgress(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	runner := &testRunner{release: make(chan struct{})}
+	handler, err := NewHandler(context.Background(), g, &Settings{Runner: runner})
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	var job Job
+	rec := apiRequest(t, handler, http.MethodPost, JobsPath, `{"domains": ["owasp.org"]}`)
+	_ = json.Unmarshal(rec.Body.Bytes(), &job)
+
+	running := waitForStatus(t, handler, job.ID, JobRunning)
+	if p := running.Progress; p == nil || p.Percent != 50 || p.Planned != 10 || p.Completed != 5 || p.ETA != 60 {
+		t.Errorf("the running job provided the progress %+v", p)
+	}
+
+	close(runner.release)
+	waitForStatus(t, handler, job.ID, JobFinished)
+
+	var finished Job
+	rec = apiRequest(t, handler, http.MethodGet, JobsPath+"/"+job.ID, "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &finished); err != nil || finished.Progress != nil {
+		t.Errorf("the finished job provided the progress %+v", finished.Progress)
+	}
+}
+
+func TestPruneFinishedJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	close(release)
+	m := newJobManager(ctx, &testRunner{release: release})
+	m.maxFinished = 2
+
+	var ids []string
+	for _, d := range []string{"owasp.org", "example.com", "example.org", "example.net"} {
+		job, err := m.submit(&JobRequest{Domains: []string{d}})
+		if err != nil {
+			t.Fatalf("submit() error = %v", err)
+		}
+		ids = append(ids, job.ID)
+	}
+
+	last := m.get(ids[len(ids)-1])
+	for i := 0; i < 100 && last.snapshot().Status != JobFinished; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := last.snapshot().Status; status != JobFinished {
+		t.Fatalf("the last job has the status %s", status)
+	}
+	// Only the most recent finished jobs are retained
+	for i, id := range ids {
+		if retained := m.get(id) != nil; retained != (i >= 2) {
+			t.Errorf("the job %d was retained: %t", i, retained)
+		}
+	}
+}
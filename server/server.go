@@ -0,0 +1,95 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package server exposes the Amass graph database over HTTP, so that user interfaces and
+// scripts can query the enumeration findings without knowledge of the internal store.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/caffix/netmap"
+)
+
+// GraphQLPath is the path of the GraphQL endpoint.
+const GraphQLPath = "/graphql"
+
+// Settings configures the endpoints served by the handler.
+type Settings struct {
+	// The bearer tokens accepted by the endpoints. No authentication is performed when empty
+	Tokens []string
+	// The Runner performing the enumeration jobs. The jobs endpoints are not served when nil
+	Runner Runner
+	// The jobs are shared by the HTTP handler and gRPC server created with the same settings
+	jobs *jobManager
+}
+
+// NewHandler returns the HTTP handler serving the endpoints over the graph database. The
+// enumeration jobs still being performed are canceled when the context is done.
+func NewHandler(ctx context.Context, g *netmap.Graph, s *Settings) (http.Handler, error) {
+	if s == nil {
+		s = &Settings{}
+	}
+
+	gql, err := NewGraphQLHandler(g)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := &resolver{graph: g}
+	mux := http.NewServeMux()
+	mux.Handle(GraphQLPath, gql)
+	mux.Handle(EnumerationsPath, rest)
+	mux.Handle(EnumerationsPath+"/", rest)
+	if s.Runner != nil {
+		jobs := s.jobManager(ctx)
+
+		mux.Handle(JobsPath, jobs)
+		mux.Handle(JobsPath+"/", jobs)
+	}
+
+	if len(s.Tokens) == 0 {
+		return mux, nil
+	}
+	return requireToken(s.Tokens, mux), nil
+}
+
+// jobManager returns the job manager of the settings, which starts performing the jobs when created.
+func (s *Settings) jobManager(ctx context.Context) *jobManager {
+	if s.jobs == nil {
+		s.jobs = newJobManager(ctx, s.Runner)
+	}
+	return s.jobs
+}
+
+// requireToken only passes the requests providing one of the tokens as the bearer credentials.
+func requireToken(tokens []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if validToken(tokens, req.Header.Get("Authorization")) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		w.Header().Set("WWW-Authenticate", `Bearer realm="amass"`)
+		writeError(w, http.StatusUnauthorized, "the request does not provide a valid token")
+	})
+}
+
+// validToken checks that the authorization value provides one of the tokens as the bearer credentials.
+func validToken(tokens []string, auth string) bool {
+	if len(auth) <= 7 || !strings.EqualFold(auth[:7], "bearer ") {
+		return false
+	}
+
+	provided := []byte(strings.TrimSpace(auth[7:]))
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare(provided, []byte(t)) == 1 {
+			return true
+		}
+	}
+	return false
+}
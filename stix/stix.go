@@ -0,0 +1,326 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package stix converts the Amass graph into a STIX 2.1 bundle, where the names, addresses, netblocks and
+// autonomous systems become cyber observable objects, the edges become relationships and each enumeration
+// becomes an observed data object referencing the objects it discovered.
+package stix
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/cayleygraph/quad"
+	"github.com/google/uuid"
+)
+
+// SpecVersion is the version of the STIX specification implemented by the objects.
+const SpecVersion = "2.1"
+
+// The namespace of the deterministic identifiers of the STIX cyber observable objects
+var scoNamespace = uuid.MustParse("00abedb4-aa42-466c-9c01-fed23315a9b7")
+
+// The STIX relationships representing the edges of the graph
+var relationships = map[string]struct {
+	typ     string
+	reverse bool
+}{
+	"a_record":     {typ: "resolves-to"},
+	"aaaa_record":  {typ: "resolves-to"},
+	"cname_record": {typ: "resolves-to"},
+	"ns_record":    {typ: "ns-record"},
+	"mx_record":    {typ: "mx-record"},
+	"ptr_record":   {typ: "ptr-record"},
+	"srv_record":   {typ: "srv-record"},
+	"contains":     {typ: "contains"},
+	"prefix":       {typ: "belongs-to", reverse: true},
+}
+
+// Bundle is a collection of STIX objects.
+type Bundle struct {
+	Type    string        `json:"type"`
+	ID      string        `json:"id"`
+	Objects []interface{} `json:"objects"`
+}
+
+// DomainName is the STIX cyber observable object of a DNS name.
+type DomainName struct {
+	Type        string `json:"type"`
+	SpecVersion string `json:"spec_version"`
+	ID          string `json:"id"`
+	Value       string `json:"value"`
+}
+
+// IPAddress is the STIX cyber observable object of an IPv4 or IPv6 address or netblock.
+type IPAddress struct {
+	Type        string `json:"type"`
+	SpecVersion string `json:"spec_version"`
+	ID          string `json:"id"`
+	Value       string `json:"value"`
+}
+
+// AutonomousSystem is the STIX cyber observable object of an autonomous system.
+type AutonomousSystem struct {
+	Type        string `json:"type"`
+	SpecVersion string `json:"spec_version"`
+	ID          string `json:"id"`
+	Number      int    `json:"number"`
+	Name        string `json:"name,omitempty"`
+}
+
+// Relationship is the STIX relationship object linking two of the objects.
+type Relationship struct {
+	Type             string `json:"type"`
+	SpecVersion      string `json:"spec_version"`
+	ID               string `json:"id"`
+	Created          string `json:"created"`
+	Modified         string `json:"modified"`
+	RelationshipType string `json:"relationship_type"`
+	SourceRef        string `json:"source_ref"`
+	TargetRef        string `json:"target_ref"`
+}
+
+// ObservedData is the STIX domain object of an enumeration and the objects it discovered.
+type ObservedData struct {
+	Type           string   `json:"type"`
+	SpecVersion    string   `json:"spec_version"`
+	ID             string   `json:"id"`
+	Created        string   `json:"created"`
+	Modified       string   `json:"modified"`
+	FirstObserved  string   `json:"first_observed"`
+	LastObserved   string   `json:"last_observed"`
+	NumberObserved int      `json:"number_observed"`
+	ObjectRefs     []string `json:"object_refs"`
+}
+
+type node struct {
+	ntype      string
+	quads      []quad.Quad
+	properties map[string]quad.Value
+}
+
+// NewBundle returns the STIX bundle of the quads, with the relationship and observed
+// data objects created at the time provided.
+func NewBundle(quads []quad.Quad, created time.Time) *Bundle {
+	nodes := make(map[string]*node)
+	for _, q := range quads {
+		subject := valToStr(q.Subject)
+		n, found := nodes[subject]
+		if !found {
+			n = &node{properties: make(map[string]quad.Value)}
+			nodes[subject] = n
+		}
+
+		pred := valToStr(q.Predicate)
+		if pred == "type" {
+			n.ntype = valToStr(q.Object)
+		} else if _, ok := q.Object.(quad.IRI); !ok {
+			n.properties[pred] = q.Object
+		}
+		n.quads = append(n.quads, q)
+	}
+
+	tlds := make(map[string]struct{})
+	for _, q := range quads {
+		if valToStr(q.Predicate) == "tld" {
+			tlds[valToStr(q.Object)] = struct{}{}
+		}
+	}
+
+	var ids []string
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	b := &Bundle{
+		Type:    "bundle",
+		ID:      "bundle--" + uuid.New().String(),
+		Objects: []interface{}{},
+	}
+	// Create the cyber observable objects for the nodes
+	refs := make(map[string]string)
+	for _, id := range ids {
+		if _, found := tlds[id]; found {
+			continue
+		}
+		if obj, ref := observable(id, nodes[id]); obj != nil {
+			refs[id] = ref
+			b.Objects = append(b.Objects, obj)
+		}
+	}
+
+	timestamp := formatTime(created)
+	// Create the relationships for the edges between the objects
+	for _, id := range ids {
+		source, found := refs[id]
+		if !found {
+			continue
+		}
+
+		for _, q := range nodes[id].quads {
+			rel, found := relationships[valToStr(q.Predicate)]
+			if !found {
+				continue
+			}
+			target, found := refs[valToStr(q.Object)]
+			if !found {
+				continue
+			}
+
+			from, to := source, target
+			if rel.reverse {
+				from, to = target, source
+			}
+			b.Objects = append(b.Objects, &Relationship{
+				Type:             "relationship",
+				SpecVersion:      SpecVersion,
+				ID:               "relationship--" + uuid.NewSHA1(scoNamespace, []byte(from+rel.typ+to)).String(),
+				Created:          timestamp,
+				Modified:         timestamp,
+				RelationshipType: rel.typ,
+				SourceRef:        from,
+				TargetRef:        to,
+			})
+		}
+	}
+	// Create the observed data objects for the enumerations
+	for _, id := range ids {
+		n := nodes[id]
+		if n.ntype != netmap.TypeEvent {
+			continue
+		}
+
+		var objs []string
+		for _, q := range n.quads {
+			if ref, found := refs[valToStr(q.Object)]; found && valToStr(q.Predicate) != "type" {
+				objs = append(objs, ref)
+			}
+		}
+		if len(objs) == 0 {
+			continue
+		}
+
+		b.Objects = append(b.Objects, &ObservedData{
+			Type:           "observed-data",
+			SpecVersion:    SpecVersion,
+			ID:             "observed-data--" + eventUUID(id),
+			Created:        timestamp,
+			Modified:       timestamp,
+			FirstObserved:  formatTime(nativeTime(n.properties["start"], created)),
+			LastObserved:   formatTime(nativeTime(n.properties["finish"], created)),
+			NumberObserved: 1,
+			ObjectRefs:     unique(objs),
+		})
+	}
+	return b
+}
+
+// WriteBundle writes the STIX bundle as JSON.
+func WriteBundle(w io.Writer, b *Bundle) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(b)
+}
+
+func observable(id string, n *node) (interface{}, string) {
+	switch n.ntype {
+	case netmap.TypeFQDN:
+		ref := scoID("domain-name", "value", id)
+		return &DomainName{Type: "domain-name", SpecVersion: SpecVersion, ID: ref, Value: id}, ref
+	case netmap.TypeAddr, netmap.TypeNetblock:
+		addr := id
+		if n.ntype == netmap.TypeNetblock {
+			addr = strings.Split(id, "/")[0]
+		}
+
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return nil, ""
+		}
+
+		t := "ipv6-addr"
+		if ip.To4() != nil {
+			t = "ipv4-addr"
+		}
+		ref := scoID(t, "value", id)
+		return &IPAddress{Type: t, SpecVersion: SpecVersion, ID: ref, Value: id}, ref
+	case netmap.TypeAS:
+		asn, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, ""
+		}
+
+		ref := scoID("autonomous-system", "number", asn)
+		as := &AutonomousSystem{Type: "autonomous-system", SpecVersion: SpecVersion, ID: ref, Number: asn}
+		if desc, ok := n.properties["description"].(quad.String); ok {
+			as.Name = string(desc)
+		}
+		return as, ref
+	}
+	return nil, ""
+}
+
+// scoID returns the deterministic identifier of the cyber observable object, which is derived from the
+// canonical JSON serialization of the property that contributes to the identifier.
+func scoID(typ, property string, value interface{}) string {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(map[string]interface{}{property: value})
+
+	return typ + "--" + uuid.NewSHA1(scoNamespace, bytes.TrimSpace(buf.Bytes())).String()
+}
+
+func eventUUID(id string) string {
+	if u, err := uuid.Parse(id); err == nil {
+		return u.String()
+	}
+	return uuid.NewSHA1(scoNamespace, []byte(id)).String()
+}
+
+func nativeTime(v quad.Value, def time.Time) time.Time {
+	if v != nil {
+		if t, ok := v.Native().(time.Time); ok {
+			return t
+		}
+	}
+	return def
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+func unique(refs []string) []string {
+	seen := make(map[string]struct{})
+
+	var results []string
+	for _, ref := range refs {
+		if _, found := seen[ref]; !found {
+			seen[ref] = struct{}{}
+			results = append(results, ref)
+		}
+	}
+	sort.Strings(results)
+	return results
+}
+
+func valToStr(v quad.Value) string {
+	switch val := v.(type) {
+	case quad.IRI:
+		return strings.TrimRight(strings.TrimLeft(string(val), "<"), ">")
+	case quad.String:
+		return string(val)
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package stix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caffix/netmap"
+	"github.com/cayleygraph/quad"
+)
+
+const testUUID = "ef9f9475-34eb-465e-81ec-9a1f7f8d2f5b"
+
+func testQuads(t *testing.T) []quad.Quad {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	if err := g.UpsertCNAME(ctx, "www.owasp.org", "owasp.org", "DNS", testUUID); err != nil {
+		t.Fatalf("failed to insert the CNAME record: %v", err)
+	}
+	if err := g.UpsertA(ctx, "owasp.org", "192.168.1.1", "DNS", testUUID); err != nil {
+		t.Fatalf("failed to insert the A record: %v", err)
+	}
+	if err := g.UpsertInfrastructure(ctx, 26808, "UTORONTO-AS, CA", "192.168.1.1", "192.168.1.0/24", "RIR", testUUID); err != nil {
+		t.Fatalf("failed to insert the infrastructure: %v", err)
+	}
+
+	quads, err := g.ReadEventQuads(ctx, testUUID)
+	if err != nil {
+		t.Fatalf("failed to read the event quads: %v", err)
+	}
+	return quads
+}
+
+func TestNewBundle(t *testing.T) {
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := NewBundle(testQuads(t), created)
+
+	if b.Type != "bundle" || !strings.HasPrefix(b.ID, "bundle--") {
+		t.Errorf("unexpected bundle: %s %s", b.Type, b.ID)
+	}
+
+	values := make(map[string]string)
+	rels := make(map[string]struct{})
+	var observed *ObservedData
+	for _, obj := range b.Objects {
+		switch o := obj.(type) {
+		case *DomainName:
+			values[o.ID] = o.Value
+		case *IPAddress:
+			values[o.ID] = o.Value
+		case *AutonomousSystem:
+			values[o.ID] = o.Name
+			if o.Number != 26808 {
+				t.Errorf("unexpected autonomous system number: %d", o.Number)
+			}
+		case *Relationship:
+			if o.Created != "2023-01-02T03:04:05.000Z" {
+				t.Errorf("unexpected creation time of the relationship: %s", o.Created)
+			}
+			rels[values[o.SourceRef]+" "+o.RelationshipType+" "+values[o.TargetRef]] = struct{}{}
+		case *ObservedData:
+			observed = o
+		}
+	}
+
+	for _, v := range []string{"www.owasp.org", "owasp.org", "192.168.1.1", "192.168.1.0/24", "UTORONTO-AS, CA"} {
+		var found bool
+		for _, val := range values {
+			if val == v {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("the %s object was not found in the bundle", v)
+		}
+	}
+	for _, v := range values {
+		if v == "org" {
+			t.Error("the top-level domain should not be included in the bundle")
+		}
+	}
+
+	for _, rel := range []string{
+		"www.owasp.org resolves-to owasp.org",
+		"owasp.org resolves-to 192.168.1.1",
+		"192.168.1.0/24 contains 192.168.1.1",
+		"192.168.1.0/24 belongs-to UTORONTO-AS, CA",
+	} {
+		if _, found := rels[rel]; !found {
+			t.Errorf("the relationship '%s' was not found", rel)
+		}
+	}
+
+	if observed == nil {
+		t.Fatal("the observed data of the enumeration was not found")
+	}
+	if observed.ID != "observed-data--"+testUUID || observed.NumberObserved != 1 || len(observed.ObjectRefs) != len(values) {
+		t.Errorf("unexpected observed data: %+v", observed)
+	}
+	if _, err := time.Parse(time.RFC3339, observed.FirstObserved); err != nil {
+		t.Errorf("unexpected first observed time: %s", observed.FirstObserved)
+	}
+}
+
+func TestSCOID(t *testing.T) {
+	// The identifier generated for the name by the STIX reference implementation
+	if id := scoID("domain-name", "value", "example.com"); id != "domain-name--bedb4899-d24b-5401-bc86-8f6b4cc18ec7" {
+		t.Errorf("unexpected identifier of example.com: %s", id)
+	}
+
+	id := scoID("domain-name", "value", "www.owasp.org")
+	if id != scoID("domain-name", "value", "www.owasp.org") {
+		t.Error("the identifiers of the observable objects are not deterministic")
+	}
+	if !strings.HasPrefix(id, "domain-name--") || id[len("domain-name--")+14] != '5' {
+		t.Errorf("expected a version 5 UUID, got %s", id)
+	}
+	if id == scoID("domain-name", "value", "owasp.org") {
+		t.Error("different values produced the same identifier")
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteBundle(buf, NewBundle(testQuads(t), time.Now())); err != nil {
+		t.Fatalf("failed to write the bundle: %v", err)
+	}
+
+	var b struct {
+		Type    string                   `json:"type"`
+		Objects []map[string]interface{} `json:"objects"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &b); err != nil {
+		t.Fatalf("failed to decode the bundle: %v", err)
+	}
+	for _, obj := range b.Objects {
+		if obj["spec_version"] != SpecVersion {
+			t.Errorf("unexpected spec_version of the %v object", obj["type"])
+		}
+	}
+}
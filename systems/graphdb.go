@@ -0,0 +1,147 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package systems
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/caffix/netmap"
+	"github.com/cayleygraph/cayley/graph"
+	csql "github.com/cayleygraph/cayley/graph/sql"
+	"github.com/owasp-amass/amass/v3/config"
+)
+
+// The options configuring the pool of database connections used by Cayley
+var sqlPoolOptions = []string{"maxopenconnections", "maxidleconnections", "connmaxlifetime"}
+
+// The connection pool options that Cayley requires as integers
+var sqlIntOptions = []string{"maxopenconnections", "maxidleconnections"}
+
+func init() {
+	// The netmap package selects the SQL flavor using the options of the 'sql' quad store
+	graph.RegisterQuadStore("sql", graph.QuadStoreRegistration{
+		NewFunc: func(addr string, opts graph.Options) (graph.QuadStore, error) {
+			return csql.New(sqlFlavor(opts), addr, sqlOptions(opts))
+		},
+		InitFunc: func(addr string, opts graph.Options) error {
+			return csql.Init(sqlFlavor(opts), addr, sqlOptions(opts))
+		},
+		IsPersistent: true,
+	})
+}
+
+// NewCayleyGraph returns the netmap graph database for the settings provided, with support
+// for the SQLite database files in addition to the systems supported by netmap.
+func NewCayleyGraph(db *config.Database) (*netmap.CayleyGraph, error) {
+	var cayley *netmap.CayleyGraph
+
+	switch db.System {
+	case "postgres":
+		u, options, err := postgresURL(db)
+		if err != nil {
+			return nil, err
+		}
+		cayley = netmap.NewCayleyGraph(db.System, u, options)
+	case SQLiteFlavor:
+		if db.URL == "" {
+			return nil, errors.New("the path of the SQLite database file was not provided")
+		}
+		cayley = sqliteGraph(db.URL, db.Options)
+	default:
+		cayley = netmap.NewCayleyGraph(db.System, db.URL, db.Options)
+	}
+
+	if cayley == nil {
+		return nil, fmt.Errorf("failed to connect to the %s database", db.System)
+	}
+	return cayley, nil
+}
+
+func sqliteGraph(path, options string) *netmap.CayleyGraph {
+
+	// Allow reading the graph while it is being written, and wait for locks held by other connections
+	if !strings.Contains(path, "?") {
+		path += "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)"
+	}
+
+	opts := "flavor=" + SQLiteFlavor
+	if options != "" {
+		opts += "," + options
+	}
+	// The flavor is selected through the SQL quad store used by netmap for MySQL
+	return netmap.NewCayleyGraph("mysql", path, opts)
+}
+
+// postgresURL returns the connection string built from the PostgreSQL database settings, where the
+// username, password and database name are used when missing from the URL. The options, other than
+// those configuring the pool of connections, are added to the parameters of the connection string.
+func postgresURL(db *config.Database) (string, string, error) {
+	u, err := url.Parse(db.URL)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Host == "" {
+		return "", "", fmt.Errorf("the PostgreSQL URL %q is not valid", db.URL)
+	}
+
+	if u.User == nil && db.Username != "" {
+		if db.Password != "" {
+			u.User = url.UserPassword(db.Username, db.Password)
+		} else {
+			u.User = url.User(db.Username)
+		}
+	}
+	if strings.Trim(u.Path, "/") == "" && db.DBName != "" {
+		u.Path = "/" + db.DBName
+	}
+
+	var pool []string
+	params := u.Query()
+	for _, opt := range strings.Split(db.Options, ",") {
+		kv := strings.SplitN(strings.TrimSpace(opt), "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			continue
+		}
+
+		if isPoolOption(kv[0]) {
+			pool = append(pool, kv[0]+"="+kv[1])
+		} else if !params.Has(kv[0]) {
+			params.Set(kv[0], kv[1])
+		}
+	}
+	u.RawQuery = params.Encode()
+	return u.String(), strings.Join(pool, ","), nil
+}
+
+func isPoolOption(name string) bool {
+	for _, opt := range sqlPoolOptions {
+		if strings.EqualFold(name, opt) {
+			return true
+		}
+	}
+	return false
+}
+
+func sqlFlavor(opts graph.Options) string {
+	flavor, _ := opts.StringKey("flavor", "")
+	return flavor
+}
+
+func sqlOptions(opts graph.Options) graph.Options {
+	o := make(graph.Options, len(opts))
+	for k, v := range opts {
+		o[k] = v
+	}
+
+	for _, key := range sqlIntOptions {
+		if s, ok := o[key].(string); ok {
+			if n, err := strconv.Atoi(s); err == nil {
+				o[key] = n
+			}
+		}
+	}
+	return o
+}
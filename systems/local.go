@@ -34,6 +34,15 @@ type LocalSystem struct {
 	doneAlreadyClosed bool
 	addSource         chan service.Service
 	allSources        chan chan []service.Service
+	replaceSource     chan *sourceReplacement
+}
+
+// sourceReplacement is the data source replacing the source of the name, and the channel
+// receiving the source that was replaced. The source is only removed when srv is nil.
+type sourceReplacement struct {
+	name string
+	srv  service.Service
+	old  chan service.Service
 }
 
 // NewLocalSystem returns an initialized LocalSystem object.
@@ -42,37 +51,20 @@ func NewLocalSystem(cfg *config.Config) (*LocalSystem, error) {
 		return nil, err
 	}
 
-	trusted, num := trustedResolvers(cfg)
-	if trusted == nil || num == 0 {
-		return nil, errors.New("the system was unable to build the pool of trusted resolvers")
-	}
-
-	pool, num := trusted, num
-	if !cfg.Passive {
-		pool, num = untrustedResolvers(cfg)
-	}
-
-	if pool == nil || num == 0 {
-		return nil, errors.New("the system was unable to build the pool of untrusted resolvers")
-	}
-	if cfg.MaxDNSQueries == 0 {
-		cfg.MaxDNSQueries += num * cfg.ResolversQPS
-	} else {
-		pool.SetMaxQPS(cfg.MaxDNSQueries)
+	pool, trusted, err := NewResolverPools(cfg, cfg.Passive)
+	if err != nil {
+		return nil, err
 	}
-	// set a single name server rate limiter for both resolver pools
-	rate := resolve.NewRateTracker()
-	trusted.SetRateTracker(rate)
-	pool.SetRateTracker(rate)
 
 	sys := &LocalSystem{
-		Cfg:        cfg,
-		pool:       pool,
-		trusted:    trusted,
-		cache:      requests.NewASNCache(),
-		done:       make(chan struct{}, 2),
-		addSource:  make(chan service.Service),
-		allSources: make(chan chan []service.Service, 10),
+		Cfg:           cfg,
+		pool:          pool,
+		trusted:       trusted,
+		cache:         requests.NewASNCache(),
+		done:          make(chan struct{}, 2),
+		addSource:     make(chan service.Service),
+		allSources:    make(chan chan []service.Service, 10),
+		replaceSource: make(chan *sourceReplacement),
 	}
 
 	// Load the ASN information into the cache
@@ -131,6 +123,41 @@ func (l *LocalSystem) AddAndStart(srv service.Service) error {
 	return err
 }
 
+// ReplaceSource implements the System interface.
+func (l *LocalSystem) ReplaceSource(srv service.Service) error {
+	if err := srv.Start(); err != nil {
+		return err
+	}
+
+	ch := make(chan service.Service, 1)
+	l.replaceSource <- &sourceReplacement{
+		name: srv.String(),
+		srv:  srv,
+		old:  ch,
+	}
+	if old := <-ch; old != nil {
+		_ = old.Stop()
+	}
+	return nil
+}
+
+// RemoveSource implements the System interface.
+func (l *LocalSystem) RemoveSource(name string) error {
+	ch := make(chan service.Service, 1)
+	l.replaceSource <- &sourceReplacement{
+		name: name,
+		old:  ch,
+	}
+
+	old := <-ch
+	if old == nil {
+		return fmt.Errorf("the %s data source was not found", name)
+	}
+
+	_ = old.Stop()
+	return nil
+}
+
 // DataSources implements the System interface.
 func (l *LocalSystem) DataSources() []service.Service {
 	ch := make(chan []service.Service, 2)
@@ -225,9 +252,9 @@ func (l *LocalSystem) setupGraphDBs() error {
 	dbs = append(dbs, cfg.GraphDBs...)
 
 	for _, db := range dbs {
-		cayley := netmap.NewCayleyGraph(db.System, db.URL, db.Options)
-		if cayley == nil {
-			return fmt.Errorf("System: Failed to create the %s graph", db.System)
+		cayley, err := NewCayleyGraph(db)
+		if err != nil {
+			return fmt.Errorf("System: Failed to create the %s graph: %v", db.System, err)
 		}
 
 		g := netmap.NewGraph(cayley)
@@ -262,6 +289,26 @@ func (l *LocalSystem) manageDataSources() {
 			})
 		case all := <-l.allSources:
 			all <- dataSources
+		case r := <-l.replaceSource:
+			var old service.Service
+			// A new slice is built, since the slices provided earlier can still be in use
+			sources := make([]service.Service, 0, len(dataSources)+1)
+			for _, src := range dataSources {
+				if src.String() == r.name {
+					old = src
+					continue
+				}
+				sources = append(sources, src)
+			}
+
+			dataSources = sources
+			if r.srv != nil {
+				dataSources = append(dataSources, r.srv)
+			}
+			sort.Slice(dataSources, func(i, j int) bool {
+				return dataSources[i].String() < dataSources[j].String()
+			})
+			r.old <- old
 		}
 	}
 }
@@ -292,6 +339,39 @@ func (l *LocalSystem) loadCacheData() error {
 	return nil
 }
 
+// NewResolverPools returns the pools of untrusted and trusted resolvers selected by the configuration,
+// which share a single name server rate limiter. The trusted resolvers serve both pools when trustedOnly
+// is true, such as in the passive mode.
+func NewResolverPools(cfg *config.Config, trustedOnly bool) (*resolve.Resolvers, *resolve.Resolvers, error) {
+	trusted, num := trustedResolvers(cfg)
+	if trusted == nil || num == 0 {
+		return nil, nil, errors.New("the system was unable to build the pool of trusted resolvers")
+	}
+
+	pool, num := trusted, num
+	if !trustedOnly {
+		pool, num = untrustedResolvers(cfg)
+	}
+
+	if pool == nil || num == 0 {
+		if pool != nil && pool != trusted {
+			pool.Stop()
+		}
+		trusted.Stop()
+		return nil, nil, errors.New("the system was unable to build the pool of untrusted resolvers")
+	}
+	if cfg.MaxDNSQueries == 0 {
+		cfg.MaxDNSQueries += num * cfg.ResolversQPS
+	} else {
+		pool.SetMaxQPS(cfg.MaxDNSQueries)
+	}
+	// set a single name server rate limiter for both resolver pools
+	rate := resolve.NewRateTracker()
+	trusted.SetRateTracker(rate)
+	pool.SetRateTracker(rate)
+	return pool, trusted, nil
+}
+
 func trustedResolvers(cfg *config.Config) (*resolve.Resolvers, int) {
 	pool := resolve.NewResolvers()
 	trusted := config.DefaultBaselineResolvers
@@ -301,6 +381,7 @@ func trustedResolvers(cfg *config.Config) (*resolve.Resolvers, int) {
 
 	_ = pool.AddResolvers(cfg.TrustedQPS, trusted...)
 	pool.SetDetectionResolver(cfg.TrustedQPS, "8.8.8.8")
+	auditResolvers(append([]string{"8.8.8.8"}, trusted...)...)
 
 	pool.SetLogger(cfg.Log)
 	pool.SetTimeout(2 * time.Second)
@@ -323,6 +404,7 @@ func untrustedResolvers(cfg *config.Config) (*resolve.Resolvers, int) {
 		pool.SetMaxQPS(cfg.MaxDNSQueries)
 	}
 	_ = pool.AddResolvers(cfg.ResolversQPS, cfg.Resolvers...)
+	auditResolvers(cfg.Resolvers...)
 	pool.SetTimeout(3 * time.Second)
 	pool.SetThresholdOptions(&resolve.ThresholdOptions{
 		ThresholdValue:      20,
@@ -336,6 +418,17 @@ func untrustedResolvers(cfg *config.Config) (*resolve.Resolvers, int) {
 	return pool, pool.Len()
 }
 
+// auditResolvers records the resolvers as contacted, since the queries are sent over the UDP
+// sockets of the resolver pools without being seen by the dialer.
+func auditResolvers(addrs ...string) {
+	for _, addr := range addrs {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, "53")
+		}
+		_ = amassnet.Audit.Allow("udp", addr)
+	}
+}
+
 func publicResolverAddrs(cfg *config.Config) []string {
 	addrs := config.PublicResolvers
 
@@ -7,6 +7,9 @@ package systems
 import (
 	"reflect"
 	"testing"
+
+	"github.com/caffix/service"
+	amassnet "github.com/owasp-amass/amass/v3/net"
 )
 
 func TestCheckAddresses(t *testing.T) {
@@ -56,3 +59,113 @@ func TestCheckAddresses(t *testing.T) {
 		})
 	}
 }
+
+type testSource struct {
+	service.BaseService
+}
+
+func newTestSource(name string) *testSource {
+	s := new(testSource)
+
+	s.BaseService = *service.NewBaseService(s, name)
+	return s
+}
+
+func TestReplaceSource(t *testing.T) {
+	l := &LocalSystem{
+		done:          make(chan struct{}, 2),
+		addSource:     make(chan service.Service),
+		allSources:    make(chan chan []service.Service, 10),
+		replaceSource: make(chan *sourceReplacement),
+	}
+	go l.manageDataSources()
+	defer close(l.done)
+
+	first := newTestSource("Testing")
+	other := newTestSource("Other")
+	for _, srv := range []service.Service{first, other} {
+		if err := l.AddAndStart(srv); err != nil {
+			t.Fatalf("AddAndStart() error = %v", err)
+		}
+	}
+	prev := l.DataSources()
+
+	second := newTestSource("Testing")
+	if err := l.ReplaceSource(second); err != nil {
+		t.Fatalf("ReplaceSource() error = %v", err)
+	}
+	select {
+	case <-first.Done():
+	default:
+		t.Errorf("ReplaceSource() did not stop the source that was replaced")
+	}
+
+	srcs := l.DataSources()
+	if len(srcs) != 2 || srcs[0] != other || srcs[1] != second {
+		t.Errorf("ReplaceSource() provided the sources %v", srcs)
+	}
+	if prev[1] != first {
+		t.Errorf("ReplaceSource() modified the slice provided earlier")
+	}
+
+	third := newTestSource("Third")
+	if err := l.ReplaceSource(third); err != nil {
+		t.Fatalf("ReplaceSource() error = %v", err)
+	}
+	if srcs := l.DataSources(); len(srcs) != 3 || srcs[2] != third {
+		t.Errorf("ReplaceSource() did not append the new source: %v", srcs)
+	}
+}
+
+func TestRemoveSource(t *testing.T) {
+	l := &LocalSystem{
+		done:          make(chan struct{}, 2),
+		addSource:     make(chan service.Service),
+		allSources:    make(chan chan []service.Service, 10),
+		replaceSource: make(chan *sourceReplacement),
+	}
+	go l.manageDataSources()
+	defer close(l.done)
+
+	first := newTestSource("Testing")
+	other := newTestSource("Other")
+	for _, srv := range []service.Service{first, other} {
+		if err := l.AddAndStart(srv); err != nil {
+			t.Fatalf("AddAndStart() error = %v", err)
+		}
+	}
+
+	if err := l.RemoveSource("Testing"); err != nil {
+		t.Fatalf("RemoveSource() error = %v", err)
+	}
+	select {
+	case <-first.Done():
+	default:
+		t.Errorf("RemoveSource() did not stop the source that was removed")
+	}
+	if srcs := l.DataSources(); len(srcs) != 1 || srcs[0] != other {
+		t.Errorf("RemoveSource() provided the sources %v", srcs)
+	}
+
+	if err := l.RemoveSource("Missing"); err == nil {
+		t.Errorf("RemoveSource() did not return an error for the missing source")
+	}
+}
+
+func TestAuditResolvers(t *testing.T) {
+	defer func(a *amassnet.EndpointAudit) { amassnet.Audit = a }(amassnet.Audit)
+	amassnet.Audit = amassnet.NewEndpointAudit(nil)
+
+	auditResolvers("192.0.2.53", "192.0.2.54:5353", "2001:db8::53")
+	expected := []string{"192.0.2.53:53", "192.0.2.54:5353", "[2001:db8::53]:53"}
+
+	entries := amassnet.Audit.Contacted()
+	if len(entries) != len(expected) {
+		t.Fatalf("auditResolvers() recorded %d endpoints, expected %d", len(entries), len(expected))
+	}
+	for i, e := range entries {
+		if e.Protocol != "udp" || e.Endpoint != expected[i] {
+			t.Errorf("auditResolvers() recorded %s %s, expected udp %s", e.Protocol, e.Endpoint, expected[i])
+		}
+	}
+}
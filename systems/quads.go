@@ -0,0 +1,59 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package systems
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/caffix/netmap"
+	"github.com/cayleygraph/cayley"
+	"github.com/cayleygraph/cayley/graph"
+	"github.com/cayleygraph/quad"
+)
+
+// WriteQuads writes the quads into the graph database. The quads are staged in a temporary graph, since
+// migration is the only means of writing quads with property values that are not strings, such as the
+// times of the enumerations.
+func WriteQuads(ctx context.Context, to *netmap.Graph, quads []quad.Quad) error {
+	if len(quads) == 0 {
+		return errors.New("no quads were provided for the import")
+	}
+
+	dir, err := os.MkdirTemp("", "amass-quads")
+	if err != nil {
+		return fmt.Errorf("failed to create the temporary graph: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := graph.InitQuadStore("bolt", dir, nil); err != nil {
+		return fmt.Errorf("failed to create the temporary graph: %v", err)
+	}
+	store, err := cayley.NewGraph("bolt", dir, nil)
+	if err != nil {
+		return fmt.Errorf("failed to open the temporary graph: %v", err)
+	}
+
+	tx := graph.NewTransactionN(len(quads))
+	for _, q := range quads {
+		tx.AddQuad(q)
+	}
+	err = store.ApplyDeltas(tx.Deltas, graph.IgnoreOpts{IgnoreMissing: true, IgnoreDup: true})
+	store.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write the quads into the temporary graph: %v", err)
+	}
+
+	cg := netmap.NewCayleyGraph("local", dir, "")
+	if cg == nil {
+		return errors.New("failed to open the temporary graph")
+	}
+	from := netmap.NewGraph(cg)
+	defer from.Close()
+
+	return from.Migrate(ctx, to)
+}
@@ -0,0 +1,49 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package systems
+
+import (
+	"context"
+	"testing"
+
+	"github.com/caffix/netmap"
+)
+
+func TestWriteQuads(t *testing.T) {
+	ctx := context.Background()
+	uuid := "ef9f9475-34eb-465e-81ec-9a1f7f8d2f5b"
+
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+	if err := g.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", uuid); err != nil {
+		t.Fatalf("failed to insert the A record: %v", err)
+	}
+	start, finish := g.EventDateRange(ctx, uuid)
+
+	quads, err := g.ReadEventQuads(ctx, uuid)
+	if err != nil {
+		t.Fatalf("failed to read the event quads: %v", err)
+	}
+
+	to := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer to.Close()
+	if err := WriteQuads(ctx, to, quads); err != nil {
+		t.Fatalf("failed to write the quads: %v", err)
+	}
+
+	if events := to.EventList(ctx); len(events) != 1 || events[0] != uuid {
+		t.Fatalf("expected the event %s, got %v", uuid, events)
+	}
+	if s, f := to.EventDateRange(ctx, uuid); !s.Equal(start) || !f.Equal(finish) {
+		t.Errorf("expected the event to range from %v to %v, got %v to %v", start, finish, s, f)
+	}
+	if got, err := to.ReadEventQuads(ctx, uuid); err != nil || len(got) != len(quads) {
+		t.Errorf("expected %d quads, got %d", len(quads), len(got))
+	}
+
+	if err := WriteQuads(ctx, to, nil); err == nil {
+		t.Error("expected an error when no quads are provided")
+	}
+}
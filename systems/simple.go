@@ -5,6 +5,7 @@
 package systems
 
 import (
+	"fmt"
 	"runtime"
 
 	"github.com/owasp-amass/amass/v3/config"
@@ -49,6 +50,30 @@ func (ss *SimpleSystem) AddAndStart(srv service.Service) error {
 	return err
 }
 
+// ReplaceSource implements the System interface.
+func (ss *SimpleSystem) ReplaceSource(srv service.Service) error {
+	if err := srv.Start(); err != nil {
+		return err
+	}
+
+	if old := ss.Service; old != nil {
+		_ = old.Stop()
+	}
+	ss.Service = srv
+	return nil
+}
+
+// RemoveSource implements the System interface.
+func (ss *SimpleSystem) RemoveSource(name string) error {
+	if ss.Service == nil || ss.Service.String() != name {
+		return fmt.Errorf("the %s data source was not found", name)
+	}
+
+	_ = ss.Service.Stop()
+	ss.Service = nil
+	return nil
+}
+
 // DataSources implements the System interface.
 func (ss *SimpleSystem) DataSources() []service.Service { return []service.Service{ss.Service} }
 
@@ -0,0 +1,155 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package systems
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cayleygraph/cayley/graph"
+	graphlog "github.com/cayleygraph/cayley/graph/log"
+	csql "github.com/cayleygraph/cayley/graph/sql"
+	"github.com/cayleygraph/quad"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// SQLiteFlavor is the Cayley SQL flavor storing the graph in an SQLite database file.
+const SQLiteFlavor = "sqlite"
+
+// The pure-Go database/sql driver registered by modernc.org/sqlite
+const sqliteDriver = "sqlite"
+
+func init() {
+	// SQLite evaluates 'X REGEXP Y' by calling regexp(Y, X)
+	sqlite.MustRegisterDeterministicScalarFunction("regexp", 2, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		re, ok := args[0].(string)
+		if !ok {
+			return nil, errors.New("the regexp pattern must be a string")
+		}
+
+		var s string
+		switch v := args[1].(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		case nil:
+			return false, nil
+		default:
+			s = fmt.Sprint(v)
+		}
+		return regexp.MatchString(re, s)
+	})
+
+	csql.Register(SQLiteFlavor, csql.Registration{
+		Driver:      sqliteDriver,
+		HashType:    fmt.Sprintf(`BINARY(%d)`, quad.HashSize),
+		BytesType:   `BLOB`,
+		HorizonType: `INTEGER`,
+		TimeType:    `DATETIME`,
+		QueryDialect: csql.QueryDialect{
+			RegexpOp: "REGEXP",
+			FieldQuote: func(name string) string {
+				return "`" + name + "`"
+			},
+			Placeholder: func(n int) string { return "?" },
+		},
+		NoOffsetWithoutLimit: true,
+		NoForeignKeys:        true,
+		Error: func(err error) error {
+			return err
+		},
+		RunTx: runTxSQLite,
+	})
+}
+
+// runTxSQLite writes the node and quad updates using the upsert syntax supported by SQLite.
+func runTxSQLite(tx *sql.Tx, nodes []graphlog.NodeUpdate, quads []graphlog.QuadUpdate, opts graph.IgnoreOpts) error {
+	// The statements are prepared once for each type of node value
+	insertValue := make(map[csql.ValueType]*sql.Stmt)
+	defer func() {
+		for _, stmt := range insertValue {
+			stmt.Close()
+		}
+	}()
+
+	for _, n := range nodes {
+		if n.RefInc < 0 {
+			return errors.New("the sqlite graph does not support removing nodes")
+		}
+
+		nodeKey, values, err := csql.NodeValues(csql.NodeHash{ValueHash: n.Hash}, n.Val)
+		if err != nil {
+			return err
+		}
+		values = append([]interface{}{n.RefInc}, values...)
+		// The reference increment is provided again for the update
+		values = append(values, n.RefInc)
+
+		stmt, found := insertValue[nodeKey]
+		if !found {
+			ph := make([]string, len(values)-1)
+			for i := range ph {
+				ph[i] = "?"
+			}
+
+			stmt, err = tx.Prepare(`INSERT INTO nodes(refs, hash, ` + strings.Join(nodeKey.Columns(), ", ") +
+				`) VALUES (` + strings.Join(ph, ", ") + `) ON CONFLICT(hash) DO UPDATE SET refs = refs + ?;`)
+			if err != nil {
+				return err
+			}
+			insertValue[nodeKey] = stmt
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			return sqliteInsertError(err)
+		}
+	}
+
+	ignore := ""
+	if opts.IgnoreDup {
+		ignore = " OR IGNORE"
+	}
+
+	var insertQuad *sql.Stmt
+	for _, q := range quads {
+		if q.Del {
+			return errors.New("the sqlite graph does not support removing quads")
+		}
+
+		if insertQuad == nil {
+			var err error
+
+			insertQuad, err = tx.Prepare(`INSERT` + ignore + ` INTO quads(subject_hash, predicate_hash, object_hash, label_hash, ts) VALUES (?, ?, ?, ?, datetime());`)
+			if err != nil {
+				return err
+			}
+			defer insertQuad.Close()
+		}
+
+		dirs := make([]interface{}, 0, len(quad.Directions))
+		for _, h := range q.Quad.Dirs() {
+			dirs = append(dirs, csql.NodeHash{ValueHash: h}.SQLValue())
+		}
+		if _, err := insertQuad.Exec(dirs...); err != nil {
+			return sqliteInsertError(err)
+		}
+	}
+	return nil
+}
+
+// sqliteInsertError reports constraint violations as the quads already existing in the graph.
+func sqliteInsertError(err error) error {
+	var e *sqlite.Error
+
+	if errors.As(err, &e) && e.Code()&0xff == sqlite3.SQLITE_CONSTRAINT {
+		return &graph.DeltaError{Err: graph.ErrQuadExists}
+	}
+	return err
+}
@@ -35,6 +35,13 @@ type System interface {
 	// AddAndStart starts the provided data source and then appends it to the slice of sources
 	AddAndStart(srv service.Service) error
 
+	// ReplaceSource starts the provided data source and replaces the source of the same name, which
+	// is stopped, or appends it to the slice of sources when no source has the name
+	ReplaceSource(srv service.Service) error
+
+	// RemoveSource stops the data source of the provided name and removes it from the slice of sources
+	RemoveSource(name string) error
+
 	// DataSources returns the slice of data sources managed by the System
 	DataSources() []service.Service
 
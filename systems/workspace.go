@@ -0,0 +1,56 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package systems
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/caffix/netmap"
+)
+
+// WorkspaceProperty is the property of the event nodes that identifies the workspace of the enumeration.
+const WorkspaceProperty = "workspace"
+
+// SetEventWorkspace assigns the enumeration identified by the uuid to the workspace.
+func SetEventWorkspace(ctx context.Context, g *netmap.Graph, uuid, workspace string) error {
+	if workspace == "" {
+		return nil
+	}
+
+	event, err := g.UpsertEvent(ctx, uuid)
+	if err != nil {
+		return fmt.Errorf("failed to create the event %s: %v", uuid, err)
+	}
+	if err := g.UpsertProperty(ctx, event, WorkspaceProperty, workspace); err != nil {
+		return fmt.Errorf("failed to assign the event %s to workspace %s: %v", uuid, workspace, err)
+	}
+	return nil
+}
+
+// EventWorkspace returns the workspace of the enumeration identified by the uuid, or an empty
+// string when the enumeration belongs to the default workspace.
+func EventWorkspace(ctx context.Context, g *netmap.Graph, uuid string) string {
+	properties, err := g.ReadProperties(ctx, netmap.Node(uuid), WorkspaceProperty)
+	if err != nil || len(properties) == 0 {
+		return ""
+	}
+
+	workspace, _ := properties[0].Value.Native().(string)
+	return workspace
+}
+
+// WorkspaceEvents returns the enumerations identified by the uuids that belong to the workspace.
+// The enumerations performed without a workspace are selected by an empty string.
+func WorkspaceEvents(ctx context.Context, g *netmap.Graph, workspace string, uuids []string) []string {
+	var events []string
+
+	for _, uuid := range uuids {
+		if EventWorkspace(ctx, g, uuid) == workspace {
+			events = append(events, uuid)
+		}
+	}
+	return events
+}
@@ -0,0 +1,45 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package systems
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/caffix/netmap"
+)
+
+func TestWorkspaceEvents(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	events := map[string]string{
+		"ef9f9475-34eb-465e-81ec-9a1f7f8d2f5b": "acme",
+		"0a3c4c48-59b4-4f7d-9d6c-2a4bba2f5f8e": "globex",
+		"5d1c2b7e-7a43-4a0e-9b8e-1f4c3d2e1a90": "",
+	}
+	var uuids []string
+	for uuid, workspace := range events {
+		if err := SetEventWorkspace(ctx, g, uuid, workspace); err != nil {
+			t.Fatalf("failed to assign the workspace: %v", err)
+		}
+		_ = g.UpsertA(ctx, "www.owasp.org", "192.168.1.1", "DNS", uuid)
+		uuids = append(uuids, uuid)
+	}
+
+	for uuid, workspace := range events {
+		if got := EventWorkspace(ctx, g, uuid); got != workspace {
+			t.Errorf("expected workspace %q for %s, got %q", workspace, uuid, got)
+		}
+		if got := WorkspaceEvents(ctx, g, workspace, uuids); !reflect.DeepEqual(got, []string{uuid}) {
+			t.Errorf("expected only %s in workspace %q, got %v", uuid, workspace, got)
+		}
+	}
+	if got := WorkspaceEvents(ctx, g, "initech", uuids); len(got) != 0 {
+		t.Errorf("expected no enumerations in an unused workspace, got %v", got)
+	}
+}
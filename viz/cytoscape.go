@@ -0,0 +1,135 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+type cytoscapeNodeData struct {
+	ID         string `json:"id"`
+	Label      string `json:"label"`
+	Title      string `json:"title"`
+	Type       string `json:"type"`
+	Category   string `json:"category"`
+	Source     string `json:"source"`
+	Screenshot string `json:"screenshot,omitempty"`
+}
+
+type cytoscapeEdgeData struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Label  string `json:"label"`
+}
+
+type cytoscapeNode struct {
+	Data    cytoscapeNodeData `json:"data"`
+	Classes string            `json:"classes"`
+}
+
+type cytoscapeEdge struct {
+	Data cytoscapeEdgeData `json:"data"`
+}
+
+type cytoscapeElements struct {
+	Nodes []cytoscapeNode `json:"nodes"`
+	Edges []cytoscapeEdge `json:"edges"`
+}
+
+type cytoscapeStyle struct {
+	Selector string            `json:"selector"`
+	Style    map[string]string `json:"style"`
+}
+
+type cytoscapeGraph struct {
+	Elements cytoscapeElements `json:"elements"`
+	Style    []cytoscapeStyle  `json:"style"`
+}
+
+// WriteCytoscapeData generates a JSON file to display the Amass graph using Cytoscape.js.
+func WriteCytoscapeData(output io.Writer, nodes []Node, edges []Edge) error {
+	categories := map[string]string{
+		"fqdn":     "fqdn",
+		"ipaddr":   "address",
+		"netblock": "netblock",
+		"as":       "asn",
+	}
+
+	graph := &cytoscapeGraph{
+		Elements: cytoscapeElements{
+			Nodes: []cytoscapeNode{},
+			Edges: []cytoscapeEdge{},
+		},
+		Style: cytoscapeStyles(),
+	}
+
+	for idx, node := range nodes {
+		category := categories[node.ActualType]
+		if category == "" {
+			category = node.ActualType
+		}
+
+		classes := category
+		if node.Type != category {
+			classes += " " + node.Type
+		}
+
+		graph.Elements.Nodes = append(graph.Elements.Nodes, cytoscapeNode{
+			Data: cytoscapeNodeData{
+				ID:         "n" + strconv.Itoa(idx),
+				Label:      node.Label,
+				Title:      node.Title,
+				Type:       node.Type,
+				Category:   category,
+				Source:     node.Source,
+				Screenshot: node.Screenshot,
+			},
+			Classes: classes,
+		})
+	}
+
+	for idx, edge := range edges {
+		graph.Elements.Edges = append(graph.Elements.Edges, cytoscapeEdge{
+			Data: cytoscapeEdgeData{
+				ID:     "e" + strconv.Itoa(idx),
+				Source: "n" + strconv.Itoa(edge.From),
+				Target: "n" + strconv.Itoa(edge.To),
+				Label:  edge.Title,
+			},
+		})
+	}
+
+	enc := json.NewEncoder(output)
+	enc.SetIndent("", "  ")
+	return enc.Encode(graph)
+}
+
+// The style hints shape the nodes by category and color them by type
+func cytoscapeStyles() []cytoscapeStyle {
+	styles := []cytoscapeStyle{
+		{Selector: "node", Style: map[string]string{"label": "data(label)", "font-size": "10px"}},
+		{Selector: "edge", Style: map[string]string{
+			"label":              "data(label)",
+			"font-size":          "8px",
+			"curve-style":        "bezier",
+			"target-arrow-shape": "triangle",
+		}},
+		{Selector: "node.fqdn", Style: map[string]string{"shape": "round-rectangle"}},
+		{Selector: "node.address", Style: map[string]string{"shape": "ellipse"}},
+		{Selector: "node.netblock", Style: map[string]string{"shape": "rectangle"}},
+		{Selector: "node.asn", Style: map[string]string{"shape": "hexagon"}},
+	}
+
+	for _, t := range nodeTypes {
+		styles = append(styles, cytoscapeStyle{
+			Selector: "node." + t,
+			Style:    map[string]string{"background-color": nodeColors[t]},
+		})
+	}
+	return styles
+}
@@ -0,0 +1,60 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCytoscapeData(t *testing.T) {
+	buf := bytes.NewBufferString("")
+	err := WriteCytoscapeData(buf, testNodes(), testEdges())
+	assert.Nil(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, `"nodes": [
+      {
+        "data": {
+          "id": "n0",
+          "label": "owasp.org",
+          "title": "domain: owasp.org",
+          "type": "domain",
+          "category": "fqdn",
+          "source": "DNS"
+        },
+        "classes": "fqdn domain"
+      },
+      {
+        "data": {
+          "id": "n1",
+          "label": "205.251.199.98",
+          "title": "address: 205.251.199.98",
+          "type": "address",
+          "category": "address",
+          "source": "DNS"
+        },
+        "classes": "address"
+      }
+    ],
+    "edges": [
+      {
+        "data": {
+          "id": "e0",
+          "source": "n0",
+          "target": "n1",
+          "label": "a_record"
+        }
+      }
+    ]`, "Cytoscape output should contain")
+
+	var graph cytoscapeGraph
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &graph))
+	assert.Contains(t, graph.Style, cytoscapeStyle{Selector: "node.asn", Style: map[string]string{"shape": "hexagon"}})
+	assert.Contains(t, graph.Style, cytoscapeStyle{Selector: "node.domain", Style: map[string]string{"background-color": "red"}})
+}
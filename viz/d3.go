@@ -42,7 +42,7 @@ const d3Template = `
 var graph = {
     nodes: [
     {{ range .Nodes }}
-        {id: {{.ID }}, num: {{ .Num }}, label: "{{ .Label }}", color: "{{ .Color }}" },
+        {id: {{.ID }}, num: {{ .Num }}, label: "{{ .Label }}", color: "{{ .Color }}"{{ if .Image }}, image: "{{ .Image }}"{{ end }} },
     {{ end }}
     ],
     edges: [
@@ -148,7 +148,7 @@ function update() {
             .style('opacity', 0.8)
             .style('top', transform.applyY(closeNode.y) + 5 + 'px')
             .style('left', transform.applyX(closeNode.x) + 5 + 'px')
-            .html(closeNode.label);
+            .html(closeNode.image ? closeNode.label + '<br><img src="' + closeNode.image + '" width="320">' : closeNode.label);
     }  else {
         d3.select('#tooltip')
             .style('opacity', 0);
@@ -268,6 +268,7 @@ type d3Node struct {
 	Num   int
 	Label string
 	Color string
+	Image string
 }
 
 type d3Graph struct {
@@ -302,6 +303,7 @@ func WriteD3Data(output io.Writer, nodes []Node, edges []Edge) error {
 			ID:    idx,
 			Label: label,
 			Color: colors[node.Type],
+			Image: node.Screenshot,
 		})
 	}
 
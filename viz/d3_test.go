@@ -21,6 +21,16 @@ func TestWriteD3DataHappyPath(t *testing.T) {
 	assert.Equalf(t, expectedD3Output, output, "Expected output to match")
 }
 
+func TestWriteD3DataScreenshot(t *testing.T) {
+	nodes := testNodes()
+	nodes[0].Screenshot = "screenshots/https_owasp.org.png"
+
+	buf := bytes.NewBufferString("")
+	err := WriteD3Data(buf, nodes, testEdges())
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `color: "red", image: "screenshots/https_owasp.org.png" },`)
+}
+
 const expectedD3Output = `
 <!DOCTYPE html>
 <html lang="en">
@@ -162,7 +172,7 @@ function update() {
             .style('opacity', 0.8)
             .style('top', transform.applyY(closeNode.y) + 5 + 'px')
             .style('left', transform.applyX(closeNode.x) + 5 + 'px')
-            .html(closeNode.label);
+            .html(closeNode.image ? closeNode.label + '<br><img src="' + closeNode.image + '" width="320">' : closeNode.label);
     }  else {
         d3.select('#tooltip')
             .style('opacity', 0);
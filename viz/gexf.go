@@ -18,7 +18,10 @@ const (
 
 	classNode string = "node"
 
-	modeStatic string = "static"
+	modeStatic  string = "static"
+	modeDynamic string = "dynamic"
+
+	timeFormatDateTime string = "datetime"
 
 	edgeTypeDirected string = "directed"
 )
@@ -53,6 +56,8 @@ type gexfColor struct {
 type gexfNode struct {
 	ID      string          `xml:"id,attr"`
 	Label   string          `xml:"label,attr,omitempty"`
+	Start   string          `xml:"start,attr,omitempty"`
+	End     string          `xml:"end,attr,omitempty"`
 	Attrs   []gexfAttrValue `xml:"attvalues>attvalue,omitempty"`
 	Parents []gexfParent    `xml:"parents>parent"`
 	Color   *gexfColor      `xml:"viz:color,omitempty"`
@@ -65,6 +70,8 @@ type gexfEdge struct {
 	Source string          `xml:"source,attr"`
 	Target string          `xml:"target,attr"`
 	Weight float64         `xml:"weight,attr,omitempty"`
+	Start  string          `xml:"start,attr,omitempty"`
+	End    string          `xml:"end,attr,omitempty"`
 	Attrs  []gexfAttrValue `xml:"attvalues>attvalue,omitempty"`
 }
 
@@ -76,11 +83,12 @@ type gexfMeta struct {
 }
 
 type gexfGraph struct {
-	Mode     string         `xml:"mode,attr,omitempty"`
-	EdgeType string         `xml:"defaultedgetype,attr,omitempty"`
-	Attrs    gexfAttributes `xml:"attributes,omitempty"`
-	Nodes    []gexfNode     `xml:"nodes>node,omitempty"`
-	Edges    []gexfEdge     `xml:"edges>edge,omitempty"`
+	Mode       string         `xml:"mode,attr,omitempty"`
+	EdgeType   string         `xml:"defaultedgetype,attr,omitempty"`
+	TimeFormat string         `xml:"timeformat,attr,omitempty"`
+	Attrs      gexfAttributes `xml:"attributes,omitempty"`
+	Nodes      []gexfNode     `xml:"nodes>node,omitempty"`
+	Edges      []gexfEdge     `xml:"edges>edge,omitempty"`
 }
 
 type gexf struct {
@@ -102,7 +110,8 @@ var (
 	gexfBlue   = &gexfColor{R: 26, G: 69, B: 243}
 )
 
-// WriteGEXFData generates a GEXF file to display the Amass graph using Gephi.
+// WriteGEXFData generates a GEXF file to display the Amass graph using Gephi. When the nodes
+// carry the times they were seen, the graph is dynamic and can be animated across enumerations.
 func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) error {
 	bufwr := bufio.NewWriter(output)
 
@@ -137,6 +146,18 @@ func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) error {
 		},
 	}
 
+	var dynamic bool
+	for _, n := range nodes {
+		if !n.FirstSeen.IsZero() {
+			dynamic = true
+			break
+		}
+	}
+	if dynamic {
+		doc.Graph.Mode = modeDynamic
+		doc.Graph.TimeFormat = timeFormatDateTime
+	}
+
 	for idx, n := range nodes {
 		var color *gexfColor
 
@@ -162,6 +183,8 @@ func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) error {
 		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
 			ID:    strconv.Itoa(idx),
 			Label: n.Label,
+			Start: gexfTime(n.FirstSeen),
+			End:   gexfTime(n.LastSeen),
 			Attrs: []gexfAttrValue{
 				{For: "0", Value: n.Title},
 				{For: "1", Value: n.Source},
@@ -172,12 +195,30 @@ func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) error {
 	}
 
 	for idx, e := range edges {
-		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+		edge := gexfEdge{
 			ID:     strconv.Itoa(idx),
 			Label:  e.Label,
 			Source: strconv.Itoa(e.From),
 			Target: strconv.Itoa(e.To),
-		})
+		}
+		// The edge exists while both of the nodes exist
+		if dynamic && e.From < len(nodes) && e.To < len(nodes) {
+			from, to := nodes[e.From], nodes[e.To]
+
+			start, end := from.FirstSeen, from.LastSeen
+			if to.FirstSeen.After(start) {
+				start = to.FirstSeen
+			}
+			if !to.LastSeen.IsZero() && (end.IsZero() || to.LastSeen.Before(end)) {
+				end = to.LastSeen
+			}
+			if end.Before(start) {
+				end = start
+			}
+			edge.Start = gexfTime(start)
+			edge.End = gexfTime(end)
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
 	}
 
 	enc := xml.NewEncoder(bufwr)
@@ -185,3 +226,10 @@ func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) error {
 	defer bufwr.Flush()
 	return enc.Encode(doc)
 }
+
+func gexfTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -7,6 +7,7 @@ package viz
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -56,3 +57,22 @@ const expectedGexfOutput = `<creator>OWASP Amass - https://github.com/owasp-amas
           </edges>
       </graph>
   </gexf>`
+
+func TestWriteGEXFDataDynamic(t *testing.T) {
+	first := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	last := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	nodes := testNodes()
+	nodes[0].FirstSeen, nodes[0].LastSeen = first, last
+	nodes[1].FirstSeen, nodes[1].LastSeen = first.Add(24*time.Hour), last
+
+	buf := bytes.NewBufferString("")
+	err := WriteGEXFData(buf, nodes, testEdges())
+	assert.Nil(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, `<graph mode="dynamic" defaultedgetype="directed" timeformat="datetime">`)
+	assert.Contains(t, output, `<node id="0" label="owasp.org" start="2023-06-01T12:00:00Z" end="2023-07-01T12:00:00Z">`)
+	assert.Contains(t, output, `<node id="1" label="205.251.199.98" start="2023-06-02T12:00:00Z" end="2023-07-01T12:00:00Z">`)
+	assert.Contains(t, output, `<edge id="0" source="0" target="1" start="2023-06-02T12:00:00Z" end="2023-07-01T12:00:00Z">`)
+}
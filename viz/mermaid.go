@@ -0,0 +1,61 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MermaidMaxEdges is the default limit on edges enforced by Mermaid renderers,
+// such as the ones embedded in Markdown viewers and wikis.
+const MermaidMaxEdges = 500
+
+var mermaidShapes = map[string][2]string{
+	"domain":   {"([", "])"},
+	"address":  {"(", ")"},
+	"netblock": {"[[", "]]"},
+	"as":       {"{{", "}}"},
+}
+
+// WriteMermaidData generates a Mermaid flowchart to display the Amass graph.
+func WriteMermaidData(output io.Writer, nodes []Node, edges []Edge) error {
+	w := bufio.NewWriter(output)
+
+	fmt.Fprintln(w, "graph LR")
+	for _, t := range nodeTypes {
+		fmt.Fprintf(w, "\tclassDef %s fill:%s\n", t, nodeColors[t])
+	}
+
+	for idx, node := range nodes {
+		shape, found := mermaidShapes[node.Type]
+		if !found {
+			shape = [2]string{"[", "]"}
+		}
+
+		fmt.Fprintf(w, "\tn%d%s\"%s\"%s", idx+1, shape[0], mermaidEscape(node.Label), shape[1])
+		if _, found := nodeColors[node.Type]; found {
+			fmt.Fprintf(w, ":::%s", node.Type)
+		}
+		fmt.Fprintln(w)
+	}
+
+	for _, edge := range edges {
+		if edge.Title == "" {
+			fmt.Fprintf(w, "\tn%d --> n%d\n", edge.From+1, edge.To+1)
+			continue
+		}
+		fmt.Fprintf(w, "\tn%d -->|\"%s\"| n%d\n", edge.From+1, mermaidEscape(edge.Title), edge.To+1)
+	}
+
+	return w.Flush()
+}
+
+// Mermaid labels are quoted strings that accept HTML entity codes.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
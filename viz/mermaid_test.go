@@ -0,0 +1,34 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package viz
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMermaidData(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteMermaidData(&buf, testNodes(), testEdges())
+	assert.NoError(t, err)
+
+	expected := `graph LR
+	classDef subdomain fill:green
+	classDef domain fill:red
+	classDef address fill:orange
+	classDef ptr fill:yellow
+	classDef ns fill:cyan
+	classDef mx fill:purple
+	classDef netblock fill:pink
+	classDef as fill:blue
+	n1(["owasp.org"]):::domain
+	n2("205.251.199.98"):::address
+	n1 -->|"a_record"| n2
+`
+	assert.Equal(t, expected, buf.String())
+}
@@ -7,10 +7,12 @@ package viz
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/caffix/netmap"
 	"github.com/caffix/stringset"
 	"github.com/cayleygraph/quad"
+	"github.com/owasp-amass/amass/v3/requests"
 )
 
 // Edge represents an Amass graph edge throughout the viz package.
@@ -28,8 +30,26 @@ type Node struct {
 	Title      string
 	Source     string
 	ActualType string
+	Screenshot string
+	FirstSeen  time.Time
+	LastSeen   time.Time
 }
 
+// The node types and colors shared by the output formats.
+var (
+	nodeTypes  = []string{"subdomain", "domain", "address", "ptr", "ns", "mx", "netblock", "as"}
+	nodeColors = map[string]string{
+		"subdomain": "green",
+		"domain":    "red",
+		"address":   "orange",
+		"ptr":       "yellow",
+		"ns":        "cyan",
+		"mx":        "purple",
+		"netblock":  "pink",
+		"as":        "blue",
+	}
+)
+
 // VizData returns the current state of the Graph as viz package Nodes and Edges.
 func VizData(ctx context.Context, g *netmap.Graph, uuids []string) ([]Node, []Edge) {
 	quads, err := g.ReadEventQuads(ctx, uuids...)
@@ -44,6 +64,8 @@ func VizData(ctx context.Context, g *netmap.Graph, uuids []string) ([]Node, []Ed
 		}
 	}
 
+	seen := nodeTimes(ctx, g, uuids, nodeQuads)
+
 	var idx int
 	var nodes []Node
 	nodeToIdx := make(map[string]int)
@@ -77,6 +99,9 @@ func VizData(ctx context.Context, g *netmap.Graph, uuids []string) ([]Node, []Ed
 			Title:      title,
 			Source:     src,
 			ActualType: ntype,
+			Screenshot: getScreenshot(qs),
+			FirstSeen:  seen[subject][0],
+			LastSeen:   seen[subject][1],
 		}
 
 		n.ID = idx
@@ -89,6 +114,111 @@ func VizData(ctx context.Context, g *netmap.Graph, uuids []string) ([]Node, []Ed
 	return nodes, vizEdges(nodes, nodeToIdx, nodeQuads)
 }
 
+// Subtree returns the nodes and edges for the names at or below the provided name, the nodes
+// reachable from those names, and the netblocks and autonomous systems announcing their addresses.
+func Subtree(nodes []Node, edges []Edge, name string) ([]Node, []Edge) {
+	name = strings.ToLower(strings.Trim(name, "."))
+
+	out := make(map[int][]Edge)
+	in := make(map[int][]Edge)
+	for _, e := range edges {
+		out[e.From] = append(out[e.From], e)
+		in[e.To] = append(in[e.To], e)
+	}
+
+	var queue []int
+	keep := make(map[int]bool)
+	for i, n := range nodes {
+		if n.ActualType != "fqdn" {
+			continue
+		}
+		if l := strings.ToLower(n.Label); l == name || strings.HasSuffix(l, "."+name) {
+			keep[i] = true
+			queue = append(queue, i)
+		}
+	}
+	var infra []int
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		infra = append(infra, cur)
+
+		for _, e := range out[cur] {
+			if !keep[e.To] {
+				keep[e.To] = true
+				queue = append(queue, e.To)
+			}
+		}
+	}
+	// Walk backwards to the netblocks and autonomous systems, without
+	// pulling in the other addresses announced by that infrastructure
+	for len(infra) > 0 {
+		cur := infra[0]
+		infra = infra[1:]
+
+		for _, e := range in[cur] {
+			if (e.Title == "contains" || e.Title == "prefix") && !keep[e.From] {
+				keep[e.From] = true
+				infra = append(infra, e.From)
+			}
+		}
+	}
+
+	var subNodes []Node
+	idxMap := make(map[int]int)
+	for i, n := range nodes {
+		if keep[i] {
+			idxMap[i] = len(subNodes)
+			n.ID = len(subNodes)
+			subNodes = append(subNodes, n)
+		}
+	}
+
+	var subEdges []Edge
+	for _, e := range edges {
+		from, ok1 := idxMap[e.From]
+		to, ok2 := idxMap[e.To]
+		if ok1 && ok2 {
+			e.From, e.To = from, to
+			subEdges = append(subEdges, e)
+		}
+	}
+	return subNodes, subEdges
+}
+
+// Identify when each node was first and last seen across the enumerations.
+func nodeTimes(ctx context.Context, g *netmap.Graph, uuids []string, quads map[string][]quad.Quad) map[string][2]time.Time {
+	seen := make(map[string][2]time.Time)
+
+	for _, uuid := range uuids {
+		start, finish := g.EventDateRange(ctx, uuid)
+		if start.IsZero() {
+			continue
+		}
+		if finish.IsZero() {
+			finish = start
+		}
+
+		for _, q := range quads[uuid] {
+			obj := valToStr(q.Get(quad.Object))
+			if obj == "" {
+				continue
+			}
+
+			t, found := seen[obj]
+			if !found || start.Before(t[0]) {
+				t[0] = start
+			}
+			if finish.After(t[1]) {
+				t[1] = finish
+			}
+			seen[obj] = t
+		}
+	}
+
+	return seen
+}
+
 func getType(quads []quad.Quad) string {
 	var t string
 
@@ -119,6 +249,18 @@ func getASDesc(quads []quad.Quad) string {
 	return desc
 }
 
+func getScreenshot(quads []quad.Quad) string {
+	for _, q := range quads {
+		if p := valToStr(q.Get(quad.Predicate)); p != requests.HTTPPredicate {
+			continue
+		}
+		if h, ok := requests.ParseHTTPInfo(valToStr(q.Get(quad.Object))); ok && h.Screenshot != "" {
+			return h.Screenshot
+		}
+	}
+	return ""
+}
+
 func isTLD(id string, quads map[string][]quad.Quad) bool {
 	var result bool
 loop:
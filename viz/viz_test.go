@@ -6,9 +6,12 @@ package viz
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/caffix/netmap"
+	"github.com/owasp-amass/amass/v3/requests"
 )
 
 func TestViz(t *testing.T) {
@@ -44,6 +47,32 @@ func TestViz(t *testing.T) {
 	}
 }
 
+func TestVizDataScreenshot(t *testing.T) {
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	ctx := context.Background()
+	if err := g.UpsertA(ctx, "www.example.domain", "127.0.0.1", "test", "barbazz"); err != nil {
+		t.Fatalf("Error inserting A record.\n%v", err)
+	}
+
+	info := &requests.HTTPInfo{URL: "https://www.example.domain", StatusCode: 200, Screenshot: "screenshots/https_www.example.domain.png"}
+	if err := g.UpsertProperty(ctx, netmap.Node("www.example.domain"), requests.HTTPPredicate, info.String()); err != nil {
+		t.Fatalf("Error inserting the HTTP property.\n%v", err)
+	}
+
+	nodes, _ := VizData(ctx, g, []string{"barbazz"})
+	for _, n := range nodes {
+		if n.Label == "www.example.domain" {
+			if n.Screenshot != info.Screenshot {
+				t.Errorf("VizData() returned the screenshot %q, expected %q", n.Screenshot, info.Screenshot)
+			}
+			return
+		}
+	}
+	t.Errorf("VizData() did not return the node for www.example.domain")
+}
+
 func testEdges() []Edge {
 	return []Edge{
 		{
@@ -75,3 +104,87 @@ func testNodes() []Node {
 		},
 	}
 }
+
+func TestSubtree(t *testing.T) {
+	nodes := []Node{
+		{ID: 0, Type: "domain", Label: "example.domain", ActualType: "fqdn"},
+		{ID: 1, Type: "subdomain", Label: "dev.example.domain", ActualType: "fqdn"},
+		{ID: 2, Type: "subdomain", Label: "www.dev.example.domain", ActualType: "fqdn"},
+		{ID: 3, Type: "subdomain", Label: "mail.example.domain", ActualType: "fqdn"},
+		{ID: 4, Type: "address", Label: "127.0.0.1", ActualType: "ipaddr"},
+		{ID: 5, Type: "address", Label: "127.0.0.2", ActualType: "ipaddr"},
+		{ID: 6, Type: "netblock", Label: "127.0.0.0/8", ActualType: "netblock"},
+		{ID: 7, Type: "as", Label: "64496", ActualType: "as"},
+	}
+	edges := []Edge{
+		{From: 1, To: 0, Title: "root"},
+		{From: 2, To: 0, Title: "root"},
+		{From: 3, To: 0, Title: "root"},
+		{From: 2, To: 4, Title: "a_record"},
+		{From: 3, To: 5, Title: "a_record"},
+		{From: 6, To: 4, Title: "contains"},
+		{From: 6, To: 5, Title: "contains"},
+		{From: 7, To: 6, Title: "prefix"},
+	}
+
+	subNodes, subEdges := Subtree(nodes, edges, "Dev.Example.Domain")
+
+	var labels []string
+	for i, n := range subNodes {
+		if n.ID != i {
+			t.Errorf("Subtree() returned the node %s with ID %d at index %d", n.Label, n.ID, i)
+		}
+		labels = append(labels, n.Label)
+	}
+	expected := []string{"example.domain", "dev.example.domain", "www.dev.example.domain", "127.0.0.1", "127.0.0.0/8", "64496"}
+	if strings.Join(labels, ",") != strings.Join(expected, ",") {
+		t.Errorf("Subtree() returned the nodes %v, expected %v", labels, expected)
+	}
+	if len(subEdges) != 5 {
+		t.Errorf("Subtree() returned %d edges, expected 5", len(subEdges))
+	}
+	for _, e := range subEdges {
+		if e.From >= len(subNodes) || e.To >= len(subNodes) {
+			t.Errorf("Subtree() returned an edge referencing a missing node: %v", e)
+		}
+	}
+
+	if n, e := Subtree(nodes, edges, "owasp.org"); len(n) != 0 || len(e) != 0 {
+		t.Errorf("Subtree() returned nodes for a name not in the graph")
+	}
+}
+
+func TestVizDataTimes(t *testing.T) {
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	ctx := context.Background()
+	if _, err := g.UpsertFQDN(ctx, "www.example.domain", "test", "first"); err != nil {
+		t.Fatalf("Error inserting the FQDN.\n%v", err)
+	}
+	// The event times are stored with a precision of seconds
+	time.Sleep(1100 * time.Millisecond)
+	if _, err := g.UpsertFQDN(ctx, "www.example.domain", "test", "second"); err != nil {
+		t.Fatalf("Error inserting the FQDN.\n%v", err)
+	}
+	if _, err := g.UpsertFQDN(ctx, "api.example.domain", "test", "second"); err != nil {
+		t.Fatalf("Error inserting the FQDN.\n%v", err)
+	}
+
+	seen := make(map[string]Node)
+	nodes, _ := VizData(ctx, g, []string{"first", "second"})
+	for _, n := range nodes {
+		seen[n.Label] = n
+	}
+
+	www, api := seen["www.example.domain"], seen["api.example.domain"]
+	if www.FirstSeen.IsZero() || api.FirstSeen.IsZero() {
+		t.Fatalf("VizData() did not provide the times the nodes were seen")
+	}
+	if !www.FirstSeen.Before(api.FirstSeen) {
+		t.Errorf("VizData() returned the first seen time %v for www, expected it before %v", www.FirstSeen, api.FirstSeen)
+	}
+	if www.LastSeen.Before(api.FirstSeen) {
+		t.Errorf("VizData() returned the last seen time %v for www, expected it after %v", www.LastSeen, api.FirstSeen)
+	}
+}